@@ -0,0 +1,113 @@
+package main
+
+import (
+  "context"
+  "flag"
+  "fmt"
+  "fontseca.dev/config"
+  "fontseca.dev/database"
+  "fontseca.dev/repository"
+  "fontseca.dev/service"
+  "fontseca.dev/transfer"
+  "os"
+  "text/tabwriter"
+  "time"
+)
+
+// manageArticles runs the articles subcommands through the same
+// services as the server, so the events they cause are delivered to
+// the webhooks by the running server.
+func manageArticles(cfg *config.Config, db *database.DB, args []string) error {
+  if 0 == len(args) {
+    return errUsage
+  }
+
+  var (
+    archive  = repository.NewArchiveRepository(db, cfg.Archive.ShareExpiry)
+    unit     = repository.NewUnitOfWork(db)
+    webhooks = service.NewWebhooksService(repository.NewWebhooksRepository(db))
+    drafts   = service.NewDraftsService(archive, unit, webhooks, cfg.Archive.ReadingSpeed)
+    articles = service.NewArticlesService(archive, unit, webhooks)
+    ctx      = context.Background()
+  )
+
+  defer archive.Close()
+
+  var subcommand = args[0]
+  args = args[1:]
+
+  switch subcommand {
+  case "list":
+    var set = flag.NewFlagSet("articles list", flag.ContinueOnError)
+    var hidden = set.Bool("hidden", false, "list the hidden articles")
+    var draft = set.Bool("drafts", false, "list the drafts")
+    if err := set.Parse(args); nil != err {
+      return errUsage
+    }
+
+    var get = articles.Get
+    switch {
+    case *hidden && *draft:
+      return errUsage
+    case *hidden:
+      get = articles.GetHidden
+    case *draft:
+      get = drafts.Get
+    }
+
+    return listArticles(ctx, get)
+  case "publish", "hide":
+    if 1 != len(args) {
+      return errUsage
+    }
+
+    var change, done = func(ctx context.Context, id string) error { return drafts.Publish(ctx, id) }, "published"
+    if "hide" == subcommand {
+      change, done = articles.Hide, "hidden"
+    }
+
+    if err := change(ctx, args[0]); nil != err {
+      return fmt.Errorf("%s %s: %w", subcommand, args[0], err)
+    }
+
+    fmt.Fprintf(os.Stdout, "%s %s\n", done, args[0])
+  default:
+    return errUsage
+  }
+
+  return nil
+}
+
+// listArticles writes a table with every article retrieved by get, page
+// by page.
+func listArticles(ctx context.Context, get func(context.Context, *transfer.ArticleFilter) ([]*transfer.Article, error)) error {
+  var table = tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+  fmt.Fprintln(table, "UUID\tPUBLISHED\tTITLE")
+
+  for page := 1; ; page++ {
+    articles, err := get(ctx, &transfer.ArticleFilter{Page: page, RPP: config.MaxPageSize})
+    if nil != err {
+      return fmt.Errorf("listing articles: %w", err)
+    }
+
+    for _, a := range articles {
+      var published = "-"
+      if nil != a.PublishedAt {
+        published = a.PublishedAt.Format(time.DateOnly)
+      }
+
+      var title = a.Title
+      if a.IsPinned {
+        title += " (pinned)"
+      }
+
+      fmt.Fprintf(table, "%s\t%s\t%s\n", a.UUID, published, title)
+    }
+
+    if len(articles) < config.MaxPageSize {
+      break
+    }
+  }
+
+  return table.Flush()
+}
@@ -0,0 +1,387 @@
+package main
+
+import (
+  "context"
+  "errors"
+  "fmt"
+  "fontseca.dev/config"
+  "fontseca.dev/database"
+  "fontseca.dev/handler"
+  "fontseca.dev/repository"
+  "fontseca.dev/service"
+  "fontseca.dev/transfer"
+  "github.com/gin-gonic/gin"
+  "html"
+  "io"
+  "io/fs"
+  "log"
+  "net/http"
+  "net/http/httptest"
+  "os"
+  "path"
+  "path/filepath"
+  "regexp"
+  "strconv"
+  "strings"
+)
+
+// buildHost is the host of the requests made to render the pages of
+// the static build. Absolute links to it are rewritten like any other
+// link of the site.
+const buildHost = "fontseca.dev"
+
+// buildPageSize is the number of articles retrieved at once to find
+// the pages of every article of the archive.
+const buildPageSize = 100
+
+var (
+  // linkAttribute matches the attributes that link to other resources.
+  linkAttribute = regexp.MustCompile(`\b(href|src)="([^"]*)"`)
+
+  // htmxAttribute matches the HTMX attributes, which need a server.
+  htmxAttribute = regexp.MustCompile(`\s+hx-[a-z-]+(="[^"]*")?`)
+)
+
+// buildSite renders every public page of the site into the directory
+// given in args (dist by default) and copies the public directory
+// next to them, so the result can be browsed without the server.
+func buildSite(cfg *config.Config, db *database.DB, args []string) error {
+  var dir = "dist"
+  if 0 < len(args) {
+    dir = args[0]
+  }
+
+  gin.SetMode(gin.ReleaseMode)
+
+  // The archive is not closed on purpose: the views counted while
+  // rendering the articles must not be written.
+  var (
+    archive         = repository.NewArchiveRepository(db, cfg.Archive.ShareExpiry)
+    projectsService = service.NewProjectsService(repository.NewProjectsRepository(db), nil, nil, cfg.Archive.ReadingSpeed)
+    articlesService = service.NewArticlesService(archive, nil, nil)
+    topicsService   = service.NewTopicsService(repository.NewTopicsRepository(db), nil)
+    tagsService     = service.NewTagsService(repository.NewTagsRepository(db), nil)
+  )
+
+  var web = handler.NewWebHandler(
+    service.NewMeService(repository.NewMeRepository(db), nil, nil),
+    service.NewExperienceService(repository.NewExperienceRepository(db), nil, nil),
+    projectsService,
+    service.NewDraftsService(archive, nil, nil, cfg.Archive.ReadingSpeed),
+    articlesService,
+    service.NewPatchesService(archive, repository.NewUnitOfWork(db), nil, cfg.Archive.ReadingSpeed),
+    topicsService,
+    tagsService,
+  )
+
+  var engine = gin.New()
+  routeWeb(engine, web)
+
+  var ctx = context.Background()
+  var seeds = []string{"/", "/experience", "/work", "/archive"}
+  var feeds = []string{"/archive/feed.xml"}
+
+  projects, err := projectsService.Get(ctx, false)
+  if nil != err {
+    return fmt.Errorf("building site: %w", err)
+  }
+
+  for _, project := range projects {
+    seeds = append(seeds, "/work/"+project.Slug)
+  }
+
+  topics, err := topicsService.Get(ctx)
+  if nil != err {
+    return fmt.Errorf("building site: %w", err)
+  }
+
+  for _, topic := range topics {
+    seeds = append(seeds, "/archive/"+topic.ID)
+    feeds = append(feeds, "/archive/"+topic.ID+"/feed.xml")
+  }
+
+  tags, err := tagsService.Get(ctx)
+  if nil != err {
+    return fmt.Errorf("building site: %w", err)
+  }
+
+  for _, tag := range tags {
+    seeds = append(seeds, "/archive/tag/"+tag.ID)
+  }
+
+  // The archive links to the older articles through pages that only
+  // exist for requests with a cursor, so every article is reached by
+  // paging through the archive instead.
+  var filter = &transfer.ArticleFilter{Page: 1, RPP: buildPageSize}
+
+  for {
+    articles, err := articlesService.Get(ctx, filter)
+    if nil != err {
+      return fmt.Errorf("building site: %w", err)
+    }
+
+    for _, article := range articles {
+      if route, ok := articleRoute(article); ok {
+        seeds = append(seeds, route)
+      }
+    }
+
+    if len(articles) < filter.RPP {
+      break
+    }
+
+    filter.After = articles[len(articles)-1].Cursor()
+  }
+
+  var pages, routes = crawl(engine, seeds)
+  var files = make(map[string][]byte)
+
+  for _, feed := range feeds {
+    if data, ok := render(engine, feed); ok {
+      files[feed] = data
+    }
+  }
+
+  for _, route := range routes {
+    var file = filepath.Join(dir, filepath.FromSlash(pageFile(route)))
+
+    if err = os.MkdirAll(filepath.Dir(file), 0o755); nil != err {
+      return err
+    }
+
+    if err = os.WriteFile(file, rewriteLinks(route, pages[route], pages, files), 0o644); nil != err {
+      return err
+    }
+  }
+
+  // The feeds are written as they are rendered: their links must stay
+  // absolute for the readers that fetch them.
+  for route, data := range files {
+    var file = filepath.Join(dir, filepath.FromSlash(strings.TrimPrefix(route, "/")))
+
+    if err = os.MkdirAll(filepath.Dir(file), 0o755); nil != err {
+      return err
+    }
+
+    if err = os.WriteFile(file, data, 0o644); nil != err {
+      return err
+    }
+  }
+
+  if err = copyDir("public", filepath.Join(dir, "public")); nil != err {
+    return fmt.Errorf("copying public directory: %w", err)
+  }
+
+  for route, file := range staticFiles {
+    err = copyFile(file, filepath.Join(dir, filepath.FromSlash(strings.TrimPrefix(route, "/"))))
+
+    switch {
+    case errors.Is(err, fs.ErrNotExist):
+      log.Printf("skipping %s: %v", route, err)
+    case nil != err:
+      return fmt.Errorf("copying %s: %w", file, err)
+    }
+  }
+
+  fmt.Fprintf(os.Stdout, "built %d pages and %d feeds into %s\n", len(routes), len(files), dir)
+  return nil
+}
+
+// articleRoute returns the route of the page of article, which has the
+// form '/archive/:topic/:year/:month/:slug'. Articles without a topic
+// have no page.
+func articleRoute(article *transfer.Article) (route string, ok bool) {
+  if nil == article.Topic || nil == article.PublishedAt {
+    return "", false
+  }
+
+  return path.Join(
+    "/archive",
+    article.Topic.ID,
+    strconv.Itoa(article.PublishedAt.Year()),
+    strconv.Itoa(int(article.PublishedAt.Month())),
+    article.Slug), true
+}
+
+// render renders the resource at route and reports whether it rendered
+// successfully.
+func render(engine http.Handler, route string) (data []byte, ok bool) {
+  var recorder = httptest.NewRecorder()
+  var request = httptest.NewRequest(http.MethodGet, "https://"+buildHost+route, nil)
+
+  engine.ServeHTTP(recorder, request)
+
+  if http.StatusOK != recorder.Code || 0 == recorder.Body.Len() {
+    log.Printf("skipping %s: status %d, %d bytes", route, recorder.Code, recorder.Body.Len())
+    return nil, false
+  }
+
+  return recorder.Body.Bytes(), true
+}
+
+// crawl renders the pages at seeds and every page of the site they
+// link to, transitively. Pages that do not render successfully, such
+// as the pages of shared drafts, are left out.
+func crawl(engine http.Handler, seeds []string) (pages map[string][]byte, routes []string) {
+  pages = make(map[string][]byte)
+  routes = make([]string, 0, len(seeds))
+
+  var visited = make(map[string]bool)
+  var queue = append([]string{}, seeds...)
+
+  for 0 < len(queue) {
+    var route = queue[0]
+    queue = queue[1:]
+
+    if visited[route] || !isPage(route) {
+      continue
+    }
+
+    visited[route] = true
+
+    page, ok := render(engine, route)
+    if !ok {
+      continue
+    }
+
+    pages[route] = page
+    routes = append(routes, route)
+
+    for _, match := range linkAttribute.FindAllSubmatch(pages[route], -1) {
+      if link, _, ok := localLink(string(match[2])); ok {
+        queue = append(queue, link)
+      }
+    }
+  }
+
+  return pages, routes
+}
+
+// isPage reports whether route may be a page of the site, rather than a
+// file, an API endpoint or a shared draft.
+func isPage(route string) bool {
+  if _, isFile := staticFiles[route]; isFile {
+    return false
+  }
+
+  return !strings.HasPrefix(route, "/public/") &&
+    !strings.HasPrefix(route, "/archive/sharing/") &&
+    !strings.Contains(route, ".")
+}
+
+// localLink reports whether the link ref points to the site itself and
+// returns its path and fragment.
+func localLink(ref string) (route, fragment string, ok bool) {
+  ref = html.UnescapeString(ref)
+
+  for _, scheme := range []string{"http://", "https://"} {
+    if strings.HasPrefix(ref, scheme+buildHost+"/") {
+      ref = strings.TrimPrefix(ref, scheme+buildHost)
+    }
+  }
+
+  if !strings.HasPrefix(ref, "/") || strings.HasPrefix(ref, "//") {
+    return "", "", false
+  }
+
+  if i := strings.IndexByte(ref, '#'); -1 != i {
+    ref, fragment = ref[:i], ref[i:]
+  }
+
+  if strings.Contains(ref, "?") {
+    return "", "", false
+  }
+
+  if 1 < len(ref) {
+    ref = strings.TrimSuffix(ref, "/")
+  }
+
+  return ref, fragment, true
+}
+
+// pageFile returns the file of the static build where the page at
+// route is written.
+func pageFile(route string) string {
+  return path.Join(strings.TrimPrefix(route, "/"), "index.html")
+}
+
+// rewriteLinks makes every link of the page at route that points to a
+// rendered page, to one of the rendered files or to a public file
+// relative to the page, and removes the HTMX attributes.
+func rewriteLinks(route string, page []byte, pages, files map[string][]byte) []byte {
+  var from = path.Dir(pageFile(route))
+
+  page = linkAttribute.ReplaceAllFunc(page, func(attribute []byte) []byte {
+    var match = linkAttribute.FindSubmatch(attribute)
+
+    link, fragment, ok := localLink(string(match[2]))
+    if !ok {
+      return attribute
+    }
+
+    var target string
+
+    _, isFile := staticFiles[link]
+
+    switch {
+    case isFile || nil != files[link] || strings.HasPrefix(link, "/public/"):
+      target = strings.TrimPrefix(link, "/")
+    case nil != pages[link]:
+      target = pageFile(link)
+    default:
+      return attribute
+    }
+
+    relative, err := filepath.Rel(filepath.FromSlash(from), filepath.FromSlash(target))
+    if nil != err {
+      return attribute
+    }
+
+    return []byte(fmt.Sprintf(`%s="%s"`, match[1], html.EscapeString(filepath.ToSlash(relative)+fragment)))
+  })
+
+  return htmxAttribute.ReplaceAll(page, nil)
+}
+
+// copyFile copies the file src to dst, creating the parent directories
+// of dst if needed.
+func copyFile(src, dst string) error {
+  in, err := os.Open(src)
+  if nil != err {
+    return err
+  }
+
+  defer in.Close()
+
+  if err = os.MkdirAll(filepath.Dir(dst), 0o755); nil != err {
+    return err
+  }
+
+  out, err := os.Create(dst)
+  if nil != err {
+    return err
+  }
+
+  if _, err = io.Copy(out, in); nil != err {
+    out.Close()
+    return err
+  }
+
+  return out.Close()
+}
+
+// copyDir copies the directory src into dst recursively.
+func copyDir(src, dst string) error {
+  return filepath.WalkDir(src, func(name string, entry fs.DirEntry, err error) error {
+    if nil != err || entry.IsDir() {
+      return err
+    }
+
+    relative, err := filepath.Rel(src, name)
+    if nil != err {
+      return err
+    }
+
+    return copyFile(name, filepath.Join(dst, relative))
+  })
+}
@@ -0,0 +1,64 @@
+package admin
+
+import (
+  "fontseca.dev/model"
+  "strconv"
+  "time"
+)
+
+// section is an area of the administration site.
+type section struct {
+  ID   string
+  Name string
+}
+
+// sections are the areas of the administration site in the order
+// they appear in its menu.
+var sections = []section{
+  {ID: "drafts", Name: "Drafts"},
+  {ID: "patches", Name: "Patches"},
+  {ID: "articles", Name: "Articles"},
+  {ID: "projects", Name: "Projects"},
+  {ID: "experience", Name: "Experience"},
+  {ID: "topics", Name: "Topics"},
+  {ID: "tags", Name: "Tags"},
+  {ID: "technologies", Name: "Technologies"},
+}
+
+// date formats a date of a record, where nil means that it has not
+// happened yet.
+func date(t *time.Time) string {
+  if nil == t || t.IsZero() {
+    return "—"
+  }
+
+  return t.Format("Jan 02, 2006")
+}
+
+// text returns the value of an optional field of a record, or fallback
+// if it is not set.
+func text(s *string, fallback string) string {
+  if nil == s {
+    return fallback
+  }
+
+  return *s
+}
+
+// number formats an optional number of a record, where nil is blank.
+func number(n *int) string {
+  if nil == n {
+    return ""
+  }
+
+  return strconv.Itoa(*n)
+}
+
+// topicID returns the ID of topic, or an empty string if there is none.
+func topicID(topic *model.Topic) string {
+  if nil == topic {
+    return ""
+  }
+
+  return topic.ID
+}
@@ -4,6 +4,7 @@ import (
   "github.com/gomarkdown/markdown"
   "github.com/gomarkdown/markdown/html"
   "github.com/gomarkdown/markdown/parser"
+  "strconv"
 )
 
 var extensions = parser.CommonExtensions
@@ -16,3 +17,13 @@ func md2html(md string) string {
   var data = markdown.ToHTML([]byte(md), p, renderer)
   return string(data)
 }
+
+// lineNumber formats a line number of a diff, where zero means that
+// the line is not present in that side.
+func lineNumber(n int) string {
+  if 0 == n {
+    return ""
+  }
+
+  return strconv.Itoa(n)
+}
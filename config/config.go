@@ -0,0 +1,406 @@
+// Package config defines the settings of the site and loads them from,
+// by order of precedence, command-line flags, the environment and a
+// YAML file.
+package config
+
+import (
+  "errors"
+  "flag"
+  "fmt"
+  "gopkg.in/yaml.v3"
+  "io"
+  "os"
+  "reflect"
+  "sort"
+  "strconv"
+  "strings"
+  "time"
+)
+
+// Config is the configuration of the site. Every setting is described
+// by the tags of its field: 'yaml' names it in the configuration file,
+// 'env' names the environment variable and 'flag' the command-line flag
+// that override it, and 'usage' describes it. Settings tagged with
+// 'secret' are redacted when printed.
+type Config struct {
+  Server   Server   `yaml:"server"`
+  Database Database `yaml:"database"`
+  Admin    Admin    `yaml:"admin"`
+  Log      Log      `yaml:"log"`
+  Tracing  Tracing  `yaml:"tracing"`
+  Archive  Archive  `yaml:"archive"`
+  Webhooks Webhooks `yaml:"webhooks"`
+  Backup   Backup   `yaml:"backup"`
+  Trash    Trash    `yaml:"trash"`
+}
+
+// Server configures the HTTP server.
+type Server struct {
+  Host           string        `yaml:"host" env:"HOST" flag:"host" usage:"address on which the server listens"`
+  Port           int           `yaml:"port" env:"PORT" flag:"port" usage:"port on which the server listens"`
+  Mode           string        `yaml:"mode" env:"SERVER_MODE" flag:"mode" usage:"mode of the server: debug, release or test"`
+  ReadTimeout    time.Duration `yaml:"read_timeout" env:"SERVER_READ_TIMEOUT" flag:"read-timeout" usage:"maximum duration for reading a request"`
+  WriteTimeout   time.Duration `yaml:"write_timeout" env:"SERVER_WRITE_TIMEOUT" flag:"write-timeout" usage:"maximum duration for writing a response"`
+  IdleTimeout    time.Duration `yaml:"idle_timeout" env:"SERVER_IDLE_TIMEOUT" flag:"idle-timeout" usage:"maximum duration a keep-alive connection waits for the next request"`
+  MaxHeaderBytes int           `yaml:"max_header_bytes" env:"SERVER_MAX_HEADER_BYTES" flag:"max-header-bytes" usage:"maximum size of the headers of a request, in bytes"`
+}
+
+// Database configures the database, which is either a SQLite or a
+// PostgreSQL one.
+type Database struct {
+  Driver      string        `yaml:"driver" env:"DATABASE_DRIVER" flag:"database-driver" usage:"database management system: sqlite or postgres"`
+  Path        string        `yaml:"path" env:"DATABASE_PATH" flag:"database" usage:"path of the SQLite database"`
+  URL         string        `yaml:"url" env:"DATABASE_URL" flag:"database-url" secret:"true" usage:"connection string of the PostgreSQL database"`
+  Readers     int           `yaml:"readers" env:"DATABASE_READERS" flag:"database-readers" usage:"maximum number of connections that read the database at once; with PostgreSQL, of connections altogether"`
+  BusyTimeout time.Duration `yaml:"busy_timeout" env:"DATABASE_BUSY_TIMEOUT" flag:"database-busy-timeout" usage:"time a connection waits for a lock held by another process"`
+}
+
+// Admin configures the access to the administrative endpoints.
+type Admin struct {
+  Token        string `yaml:"token" env:"ADMIN_TOKEN" flag:"admin-token" secret:"true" usage:"bearer token of the administrative endpoints; they are disabled without one"`
+  MetricsToken string `yaml:"metrics_token" env:"METRICS_TOKEN" flag:"metrics-token" secret:"true" usage:"bearer token of /metrics (default: the administration token)"`
+}
+
+// Log configures the log file.
+type Log struct {
+  File       string `yaml:"file" env:"LOG_FILE" flag:"log-file" usage:"path of the log file"`
+  MaxSize    int    `yaml:"max_size" env:"LOG_MAX_SIZE" flag:"log-max-size" usage:"size past which the log file is rotated, in MiB"`
+  MaxBackups int    `yaml:"max_backups" env:"LOG_MAX_BACKUPS" flag:"log-max-backups" usage:"number of rotated log files that are kept"`
+}
+
+// Tracing configures the export of traces.
+type Tracing struct {
+  Exporter string `yaml:"exporter" env:"OTEL_TRACES_EXPORTER" flag:"traces-exporter" usage:"exporter of traces: none, stdout or otlp"`
+}
+
+// Archive configures the articles of the archive.
+type Archive struct {
+  PageSize     int           `yaml:"page_size" env:"ARCHIVE_PAGE_SIZE" flag:"page-size" usage:"number of articles per page when none is requested"`
+  ReadingSpeed int           `yaml:"reading_speed" env:"ARCHIVE_READING_SPEED" flag:"reading-speed" usage:"words read per minute, used to estimate reading times"`
+  ShareExpiry  time.Duration `yaml:"share_expiry" env:"ARCHIVE_SHARE_EXPIRY" flag:"share-expiry" usage:"time after which a shareable link expires"`
+}
+
+// Webhooks configures the delivery of webhooks.
+type Webhooks struct {
+  Interval time.Duration `yaml:"interval" env:"WEBHOOKS_INTERVAL" flag:"webhooks-interval" usage:"time between deliveries of pending webhook events"`
+}
+
+// Backup configures the backups of the database.
+type Backup struct {
+  Dir       string        `yaml:"dir" env:"BACKUP_DIR" flag:"backup-dir" usage:"directory where the backups of the database are kept"`
+  Interval  time.Duration `yaml:"interval" env:"BACKUP_INTERVAL" flag:"backup-interval" usage:"time between scheduled backups, or 0 to disable them"`
+  Retention int           `yaml:"retention" env:"BACKUP_RETENTION" flag:"backup-retention" usage:"number of most recent backups that are kept"`
+}
+
+// Trash configures the purge of the removed content.
+type Trash struct {
+  Retention time.Duration `yaml:"retention" env:"TRASH_RETENTION" flag:"trash-retention" usage:"time removed content is kept in the trash before it is purged"`
+  Interval  time.Duration `yaml:"interval" env:"TRASH_INTERVAL" flag:"trash-interval" usage:"time between purges of the trash, or 0 to disable them"`
+}
+
+// MaxPageSize is the largest number of articles a page can have.
+const MaxPageSize = 100
+
+// Default returns the configuration used for the settings that are set
+// neither in the configuration file, the environment nor the flags.
+func Default() *Config {
+  return &Config{
+    Server: Server{
+      Host:           "0.0.0.0",
+      Port:           8080,
+      Mode:           "debug",
+      ReadTimeout:    5 * time.Second,
+      WriteTimeout:   5 * time.Second,
+      IdleTimeout:    time.Minute,
+      MaxHeaderBytes: 1024,
+    },
+    Database: Database{
+      Driver:      "sqlite",
+      Path:        "./db.sqlite",
+      Readers:     4,
+      BusyTimeout: 5 * time.Second,
+    },
+    Log: Log{
+      File:       "server.log",
+      MaxSize:    10,
+      MaxBackups: 5,
+    },
+    Tracing: Tracing{Exporter: "none"},
+    Archive: Archive{
+      PageSize:     20,
+      ReadingSpeed: 183,
+      ShareExpiry:  7 * 24 * time.Hour,
+    },
+    Webhooks: Webhooks{Interval: 15 * time.Second},
+    Backup: Backup{
+      Dir:       "backups",
+      Interval:  24 * time.Hour,
+      Retention: 7,
+    },
+    Trash: Trash{
+      Retention: 30 * 24 * time.Hour,
+      Interval:  24 * time.Hour,
+    },
+  }
+}
+
+// DefaultFile is the configuration file read when none is given with
+// the -config flag or the CONFIG_FILE environment variable. Unlike a
+// given one, it may not exist.
+const DefaultFile = "config.yaml"
+
+// setting is a field of a Config, along with its tags.
+type setting struct {
+  name   string // dotted path of the setting in the configuration file
+  env    string
+  flag   string
+  usage  string
+  secret bool
+  value  reflect.Value
+}
+
+// settings lists every setting of c.
+func (c *Config) settings() (settings []setting) {
+  var sections = reflect.ValueOf(c).Elem()
+  for i := 0; i < sections.NumField(); i++ {
+    var section = sections.Field(i)
+    var sectionName = sections.Type().Field(i).Tag.Get("yaml")
+    for j := 0; j < section.NumField(); j++ {
+      var field = section.Type().Field(j)
+      settings = append(settings, setting{
+        name:   sectionName + "." + field.Tag.Get("yaml"),
+        env:    field.Tag.Get("env"),
+        flag:   field.Tag.Get("flag"),
+        usage:  field.Tag.Get("usage"),
+        secret: "true" == field.Tag.Get("secret"),
+        value:  section.Field(j),
+      })
+    }
+  }
+
+  return settings
+}
+
+// set parses s into the setting.
+func (s setting) set(value string) error {
+  value = strings.TrimSpace(value)
+
+  switch s.value.Interface().(type) {
+  case time.Duration:
+    d, err := time.ParseDuration(value)
+    if nil != err {
+      return fmt.Errorf("%s: %q is not a duration", s.name, value)
+    }
+    s.value.SetInt(int64(d))
+  case int:
+    n, err := strconv.Atoi(value)
+    if nil != err {
+      return fmt.Errorf("%s: %q is not an integer", s.name, value)
+    }
+    s.value.SetInt(int64(n))
+  case string:
+    s.value.SetString(value)
+  default:
+    return fmt.Errorf("%s: unsupported type %s", s.name, s.value.Type())
+  }
+
+  return nil
+}
+
+// flags returns the set of command-line flags of the settings. The
+// flags that are passed are recorded in passed by name, and the
+// configuration file is stored in file.
+func flags(settings []setting, file *string, passed map[string]string) *flag.FlagSet {
+  var set = flag.NewFlagSet("fontseca.dev", flag.ContinueOnError)
+  set.SetOutput(io.Discard)
+  set.StringVar(file, "config", "", "path of the YAML configuration file (default: "+DefaultFile+", if it exists)")
+
+  for _, s := range settings {
+    var name = s.flag
+    set.Func(name, s.usage, func(value string) error {
+      passed[name] = value
+      return nil
+    })
+  }
+
+  return set
+}
+
+// PrintFlags writes the command-line flags, their usage and their
+// defaults to w.
+func PrintFlags(w io.Writer) {
+  fmt.Fprintf(w, "  -config file\n    \tpath of the YAML configuration file (default: %s, if it exists)\n    \tenvironment: CONFIG_FILE\n", DefaultFile)
+
+  var settings = Default().settings()
+  sort.Slice(settings, func(i, j int) bool { return settings[i].flag < settings[j].flag })
+
+  for _, s := range settings {
+    var kind = "string"
+    switch s.value.Interface().(type) {
+    case time.Duration:
+      kind = "duration"
+    case int:
+      kind = "int"
+    }
+
+    fmt.Fprintf(w, "  -%s %s\n    \t%s", s.flag, kind, s.usage)
+    if value := fmt.Sprint(s.value.Interface()); "" != value {
+      fmt.Fprintf(w, " (default %s)", value)
+    }
+    fmt.Fprintf(w, "\n    \tenvironment: %s; file: %s\n", s.env, s.name)
+  }
+}
+
+// Load returns the configuration made by the flags at the beginning of
+// args, the environment and the configuration file, and the arguments
+// that follow the flags. The configuration is validated.
+func Load(args []string) (config *Config, rest []string, err error) {
+  config = Default()
+
+  var (
+    settings = config.settings()
+    file     string
+    passed   = map[string]string{}
+    set      = flags(settings, &file, passed)
+  )
+
+  if err = set.Parse(args); nil != err {
+    return nil, nil, err
+  }
+
+  if "" == file {
+    file = strings.TrimSpace(os.Getenv("CONFIG_FILE"))
+  }
+
+  if err = config.read(file); nil != err {
+    return nil, nil, err
+  }
+
+  for _, s := range settings {
+    if value := os.Getenv(s.env); "" != strings.TrimSpace(value) {
+      if err = s.set(value); nil != err {
+        return nil, nil, fmt.Errorf("environment variable %s: %w", s.env, err)
+      }
+    }
+  }
+
+  for _, s := range settings {
+    if value, ok := passed[s.flag]; ok {
+      if err = s.set(value); nil != err {
+        return nil, nil, fmt.Errorf("flag -%s: %w", s.flag, err)
+      }
+    }
+  }
+
+  if err = config.Validate(); nil != err {
+    return nil, nil, err
+  }
+
+  return config, set.Args(), nil
+}
+
+// read decodes the configuration file into c. Without a file, it reads
+// DefaultFile if it exists.
+func (c *Config) read(file string) error {
+  var optional = "" == file
+  if optional {
+    file = DefaultFile
+  }
+
+  f, err := os.Open(file)
+  if nil != err {
+    if optional && errors.Is(err, os.ErrNotExist) {
+      return nil
+    }
+    return err
+  }
+
+  defer f.Close()
+
+  var decoder = yaml.NewDecoder(f)
+  decoder.KnownFields(true)
+
+  if err = decoder.Decode(c); nil != err && !errors.Is(err, io.EOF) {
+    return fmt.Errorf("configuration file %s: %w", file, err)
+  }
+
+  return nil
+}
+
+// Validate reports every invalid setting of c.
+func (c *Config) Validate() error {
+  var errs []error
+  var check = func(valid bool, format string, args ...any) {
+    if !valid {
+      errs = append(errs, fmt.Errorf(format, args...))
+    }
+  }
+
+  var server = c.Server
+  check(0 < server.Port && server.Port < 1<<16, "server.port: %d is not a valid port", server.Port)
+  check("debug" == server.Mode || "release" == server.Mode || "test" == server.Mode, "server.mode: %q must be debug, release or test", server.Mode)
+  check(0 < server.ReadTimeout, "server.read_timeout: must be positive")
+  check(0 < server.WriteTimeout, "server.write_timeout: must be positive")
+  check(0 < server.IdleTimeout, "server.idle_timeout: must be positive")
+  check(0 < server.MaxHeaderBytes, "server.max_header_bytes: must be positive")
+
+  var database = c.Database
+  switch database.Driver {
+  case "sqlite":
+    check("" != database.Path, "database.path: must not be empty")
+  case "postgres":
+    check("" != database.URL, "database.url: must not be empty with the postgres driver")
+  default:
+    check(false, "database.driver: %q must be sqlite or postgres", database.Driver)
+  }
+  check(0 < database.Readers, "database.readers: must be positive")
+  check(0 <= database.BusyTimeout, "database.busy_timeout: must not be negative")
+
+  check("" != c.Log.File, "log.file: must not be empty")
+  check(0 < c.Log.MaxSize, "log.max_size: must be positive")
+  check(0 <= c.Log.MaxBackups, "log.max_backups: must not be negative")
+
+  switch c.Tracing.Exporter {
+  case "", "none", "stdout", "console", "otlp":
+  default:
+    check(false, "tracing.exporter: %q must be none, stdout or otlp", c.Tracing.Exporter)
+  }
+
+  var archive = c.Archive
+  check(0 < archive.PageSize && archive.PageSize <= MaxPageSize, "archive.page_size: %d must be between 1 and %d", archive.PageSize, MaxPageSize)
+  check(0 < archive.ReadingSpeed, "archive.reading_speed: must be positive")
+  check(time.Second <= archive.ShareExpiry, "archive.share_expiry: must be at least a second")
+
+  check(0 < c.Webhooks.Interval, "webhooks.interval: must be positive")
+
+  var backup = c.Backup
+  check("" != backup.Dir, "backup.dir: must not be empty")
+  check(0 <= backup.Interval, "backup.interval: must not be negative")
+  check(0 < backup.Retention, "backup.retention: must be positive")
+
+  var trash = c.Trash
+  check(0 <= trash.Retention, "trash.retention: must not be negative")
+  check(0 <= trash.Interval, "trash.interval: must not be negative")
+
+  return errors.Join(errs...)
+}
+
+// Print writes c to w as a configuration file, with its secrets
+// redacted.
+func (c *Config) Print(w io.Writer) error {
+  var redacted = *c
+  for _, s := range redacted.settings() {
+    if s.secret && "" != s.value.String() {
+      s.value.SetString("********")
+    }
+  }
+
+  var encoder = yaml.NewEncoder(w)
+  encoder.SetIndent(2)
+
+  if err := encoder.Encode(&redacted); nil != err {
+    return err
+  }
+
+  return encoder.Close()
+}
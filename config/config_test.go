@@ -0,0 +1,127 @@
+package config
+
+import (
+  "bytes"
+  "github.com/stretchr/testify/assert"
+  "github.com/stretchr/testify/require"
+  "os"
+  "path/filepath"
+  "testing"
+  "time"
+)
+
+func writeFile(t *testing.T, content string) string {
+  var file = filepath.Join(t.TempDir(), "config.yaml")
+  require.NoError(t, os.WriteFile(file, []byte(content), 0644))
+  return file
+}
+
+func TestLoad(t *testing.T) {
+  wd, err := os.Getwd()
+  require.NoError(t, err)
+  require.NoError(t, os.Chdir(t.TempDir()))
+  defer os.Chdir(wd)
+
+  t.Run("defaults", func(t *testing.T) {
+    config, rest, err := Load([]string{"serve"})
+    require.NoError(t, err)
+    assert.Equal(t, Default(), config)
+    assert.Equal(t, []string{"serve"}, rest)
+  })
+
+  t.Run("flags over environment over file", func(t *testing.T) {
+    var file = writeFile(t, `
+server:
+  port: 9000
+  mode: release
+  read_timeout: 10s
+archive:
+  page_size: 30
+  reading_speed: 200
+`)
+
+    t.Setenv("PORT", "9001")
+    t.Setenv("SERVER_MODE", "test")
+
+    config, rest, err := Load([]string{"-config", file, "-port", "9002", "-share-expiry", "48h", "articles", "list"})
+    require.NoError(t, err)
+
+    assert.Equal(t, 9002, config.Server.Port)
+    assert.Equal(t, "test", config.Server.Mode)
+    assert.Equal(t, 10*time.Second, config.Server.ReadTimeout)
+    assert.Equal(t, 5*time.Second, config.Server.WriteTimeout)
+    assert.Equal(t, 30, config.Archive.PageSize)
+    assert.Equal(t, 200, config.Archive.ReadingSpeed)
+    assert.Equal(t, 48*time.Hour, config.Archive.ShareExpiry)
+    assert.Equal(t, []string{"articles", "list"}, rest)
+  })
+
+  t.Run("file from the environment", func(t *testing.T) {
+    t.Setenv("CONFIG_FILE", writeFile(t, "database:\n  path: site.sqlite\n"))
+
+    config, _, err := Load(nil)
+    require.NoError(t, err)
+    assert.Equal(t, "site.sqlite", config.Database.Path)
+  })
+
+  t.Run("default file", func(t *testing.T) {
+    require.NoError(t, os.WriteFile(DefaultFile, []byte("log:\n  max_backups: 2\n"), 0644))
+    defer os.Remove(DefaultFile)
+
+    config, _, err := Load(nil)
+    require.NoError(t, err)
+    assert.Equal(t, 2, config.Log.MaxBackups)
+  })
+
+  t.Run("missing file", func(t *testing.T) {
+    _, _, err := Load([]string{"-config", "missing.yaml"})
+    assert.ErrorIs(t, err, os.ErrNotExist)
+  })
+
+  t.Run("unknown setting in file", func(t *testing.T) {
+    _, _, err := Load([]string{"-config", writeFile(t, "server:\n  prot: 9000\n")})
+    assert.ErrorContains(t, err, "prot")
+  })
+
+  t.Run("malformed value", func(t *testing.T) {
+    t.Setenv("SERVER_READ_TIMEOUT", "soon")
+
+    _, _, err := Load(nil)
+    assert.ErrorContains(t, err, "SERVER_READ_TIMEOUT")
+  })
+
+  t.Run("invalid values", func(t *testing.T) {
+    _, _, err := Load([]string{"-port", "0", "-mode", "production", "-page-size", "500"})
+    assert.ErrorContains(t, err, "server.port")
+    assert.ErrorContains(t, err, "server.mode")
+    assert.ErrorContains(t, err, "archive.page_size")
+  })
+
+  t.Run("postgres without url", func(t *testing.T) {
+    _, _, err := Load([]string{"-database-driver", "postgres"})
+    assert.ErrorContains(t, err, "database.url")
+
+    config, _, err := Load([]string{"-database-driver", "postgres", "-database-url", "postgres://localhost/site"})
+    require.NoError(t, err)
+    assert.Equal(t, "postgres", config.Database.Driver)
+  })
+}
+
+func TestConfig_Print(t *testing.T) {
+  var config = Default()
+  config.Admin.Token = "s3cr3t"
+
+  var buf bytes.Buffer
+  require.NoError(t, config.Print(&buf))
+
+  assert.NotContains(t, buf.String(), "s3cr3t")
+  assert.Contains(t, buf.String(), "token: '********'")
+  assert.Contains(t, buf.String(), "read_timeout: 5s")
+  assert.Equal(t, "s3cr3t", config.Admin.Token)
+
+  var file = writeFile(t, buf.String())
+  printed, _, err := Load([]string{"-config", file})
+  require.NoError(t, err)
+  assert.Equal(t, config.Server, printed.Server)
+  assert.Equal(t, config.Archive, printed.Archive)
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+  "context"
+  "fontseca.dev/config"
+  "fontseca.dev/database"
+)
+
+// openDatabase opens the SQLite or PostgreSQL database of cfg, creating
+// every missing table and applying every pending migration.
+func openDatabase(cfg config.Database) (db *database.DB, err error) {
+  switch cfg.Driver {
+  case "postgres":
+    db, err = database.OpenPostgres(cfg.URL, cfg.Readers)
+  default:
+    db, err = database.Open(database.SQLiteDriver, cfg.Path, cfg.Readers, cfg.BusyTimeout)
+  }
+
+  if nil != err {
+    return nil, err
+  }
+
+  if err = db.PingContext(context.Background()); nil == err {
+    err = database.Migrate(context.Background(), db)
+  }
+
+  if nil != err {
+    db.Close()
+    return nil, err
+  }
+
+  return db, nil
+}
@@ -0,0 +1,288 @@
+package database
+
+import (
+  "context"
+  "database/sql/driver"
+  "fmt"
+  "strconv"
+  "strings"
+)
+
+// binder is a driver whose connections accept the named parameters
+// (@name) the repositories write their queries with, for a driver that
+// only understands the positional ones ($1) of PostgreSQL.
+type binder struct {
+  driver driver.Driver
+}
+
+func (b *binder) Open(name string) (driver.Conn, error) {
+  conn, err := b.driver.Open(name)
+  if nil != err {
+    return nil, err
+  }
+
+  return &boundConn{Conn: conn}, nil
+}
+
+// boundConn rewrites the named parameters of the queries and
+// statements run on a connection into positional ones.
+type boundConn struct {
+  driver.Conn
+}
+
+func (c *boundConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+  if b, ok := c.Conn.(driver.ConnBeginTx); ok {
+    return b.BeginTx(ctx, opts)
+  }
+
+  return c.Conn.Begin()
+}
+
+func (c *boundConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+  query, names := bind(query)
+
+  var (
+    stmt driver.Stmt
+    err  error
+  )
+
+  if p, ok := c.Conn.(driver.ConnPrepareContext); ok {
+    stmt, err = p.PrepareContext(ctx, query)
+  } else {
+    stmt, err = c.Conn.Prepare(query)
+  }
+
+  if nil != err {
+    return nil, err
+  }
+
+  return &boundStmt{Stmt: stmt, names: names}, nil
+}
+
+func (c *boundConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+  e, ok := c.Conn.(driver.ExecerContext)
+  if !ok {
+    return nil, driver.ErrSkip
+  }
+
+  query, names := bind(query)
+
+  args, err := order(names, args)
+  if nil != err {
+    return nil, err
+  }
+
+  return e.ExecContext(ctx, query, args)
+}
+
+func (c *boundConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+  q, ok := c.Conn.(driver.QueryerContext)
+  if !ok {
+    return nil, driver.ErrSkip
+  }
+
+  query, names := bind(query)
+
+  args, err := order(names, args)
+  if nil != err {
+    return nil, err
+  }
+
+  return q.QueryContext(ctx, query, args)
+}
+
+func (c *boundConn) Ping(ctx context.Context) error {
+  if p, ok := c.Conn.(driver.Pinger); ok {
+    return p.Ping(ctx)
+  }
+
+  return nil
+}
+
+func (c *boundConn) ResetSession(ctx context.Context) error {
+  if r, ok := c.Conn.(driver.SessionResetter); ok {
+    return r.ResetSession(ctx)
+  }
+
+  return nil
+}
+
+func (c *boundConn) IsValid() bool {
+  if v, ok := c.Conn.(driver.Validator); ok {
+    return v.IsValid()
+  }
+
+  return true
+}
+
+// boundStmt is a prepared statement whose named parameters were
+// rewritten into positional ones, in the order of names.
+type boundStmt struct {
+  driver.Stmt
+  names []string
+}
+
+func (s *boundStmt) NumInput() int {
+  if nil != s.names {
+    return -1
+  }
+
+  return s.Stmt.NumInput()
+}
+
+func (s *boundStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+  e, ok := s.Stmt.(driver.StmtExecContext)
+  if !ok {
+    return nil, driver.ErrSkip
+  }
+
+  args, err := order(s.names, args)
+  if nil != err {
+    return nil, err
+  }
+
+  return e.ExecContext(ctx, args)
+}
+
+func (s *boundStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+  q, ok := s.Stmt.(driver.StmtQueryContext)
+  if !ok {
+    return nil, driver.ErrSkip
+  }
+
+  args, err := order(s.names, args)
+  if nil != err {
+    return nil, err
+  }
+
+  return q.QueryContext(ctx, args)
+}
+
+// bind rewrites every named parameter (@name) of query into a
+// positional one ($n) and returns the names by position. A name used
+// many times takes as many positions, since PostgreSQL fails to deduce
+// a single type for a parameter used where different types are
+// expected, like in a comparison with a VARCHAR and in a TEXT function. Parameters inside
+// string literals, quoted identifiers, comments and dollar-quoted
+// strings are left alone. A query without named parameters is returned
+// as is, with no names.
+func bind(query string) (string, []string) {
+  if !strings.Contains(query, "@") {
+    return query, nil
+  }
+
+  var (
+    bound strings.Builder
+    names []string
+  )
+
+  for i := 0; i < len(query); {
+    var c = query[i]
+
+    switch {
+    case '\'' == c || '"' == c:
+      var end = strings.IndexByte(query[i+1:], c)
+      if -1 == end {
+        bound.WriteString(query[i:])
+        return bound.String(), names
+      }
+
+      bound.WriteString(query[i : i+end+2])
+      i += end + 2
+
+    case '-' == c && strings.HasPrefix(query[i:], "--"):
+      var end = strings.IndexByte(query[i:], '\n')
+      if -1 == end {
+        bound.WriteString(query[i:])
+        return bound.String(), names
+      }
+
+      bound.WriteString(query[i : i+end])
+      i += end
+
+    case '$' == c && 0 < dollarTag(query[i:]):
+      var tag = query[i : i+dollarTag(query[i:])]
+      var end = strings.Index(query[i+len(tag):], tag)
+      if -1 == end {
+        bound.WriteString(query[i:])
+        return bound.String(), names
+      }
+
+      bound.WriteString(query[i : i+len(tag)+end+len(tag)])
+      i += len(tag) + end + len(tag)
+
+    case '@' == c && i+1 < len(query) && isNameStart(query[i+1]):
+      var j = i + 1
+      for j < len(query) && isNamePart(query[j]) {
+        j++
+      }
+
+      names = append(names, query[i+1:j])
+      bound.WriteString("$" + strconv.Itoa(len(names)))
+      i = j
+
+    default:
+      bound.WriteByte(c)
+      i++
+    }
+  }
+
+  return bound.String(), names
+}
+
+// dollarTag returns the length of the tag ($tag$ or $$) that opens the
+// dollar-quoted string at the start of s, or 0 if there is none.
+func dollarTag(s string) int {
+  for i := 1; i < len(s); i++ {
+    if '$' == s[i] {
+      return i + 1
+    }
+
+    if !isNamePart(s[i]) || (1 == i && !isNameStart(s[i])) {
+      return 0
+    }
+  }
+
+  return 0
+}
+
+func isNameStart(c byte) bool {
+  return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '_' == c
+}
+
+func isNamePart(c byte) bool {
+  return isNameStart(c) || '0' <= c && c <= '9'
+}
+
+// order arranges the named arguments args by the positions of their
+// names in names. Arguments that no parameter names are left out, and
+// unnamed ones are only accepted by a query without named parameters.
+func order(names []string, args []driver.NamedValue) ([]driver.NamedValue, error) {
+  if nil == names {
+    for _, arg := range args {
+      if "" != arg.Name {
+        return nil, fmt.Errorf("database: argument @%s has no parameter", arg.Name)
+      }
+    }
+
+    return args, nil
+  }
+
+  var ordered = make([]driver.NamedValue, len(names))
+  for i, name := range names {
+    var found bool
+    for _, arg := range args {
+      if arg.Name == name {
+        ordered[i] = driver.NamedValue{Ordinal: i + 1, Value: arg.Value}
+        found = true
+        break
+      }
+    }
+
+    if !found {
+      return nil, fmt.Errorf("database: missing argument @%s", name)
+    }
+  }
+
+  return ordered, nil
+}
@@ -0,0 +1,88 @@
+package database
+
+import (
+  "database/sql/driver"
+  "github.com/stretchr/testify/assert"
+  "github.com/stretchr/testify/require"
+  "testing"
+)
+
+func TestBind(t *testing.T) {
+  var tests = []struct {
+    name  string
+    query string
+    bound string
+    names []string
+  }{
+    {
+      name:  "no parameters",
+      query: `SELECT * FROM "tag" WHERE "id" = $1;`,
+      bound: `SELECT * FROM "tag" WHERE "id" = $1;`,
+    },
+    {
+      name:  "named parameters",
+      query: `UPDATE "tag" SET "name" = @name WHERE "id" = @id;`,
+      bound: `UPDATE "tag" SET "name" = $1 WHERE "id" = $2;`,
+      names: []string{"name", "id"},
+    },
+    {
+      name:  "repeated parameter",
+      query: `SELECT @a_1 = "x" OR @a_1 IS NULL;`,
+      bound: `SELECT $1 = "x" OR $2 IS NULL;`,
+      names: []string{"a_1", "a_1"},
+    },
+    {
+      name:  "quoted",
+      query: `SELECT 'me@example.com', "@column", @value -- @comment` + "\n;",
+      bound: `SELECT 'me@example.com', "@column", $1 -- @comment` + "\n;",
+      names: []string{"value"},
+    },
+    {
+      name:  "dollar-quoted",
+      query: `SELECT $$ @body $$, $tag$ @body $tag$, @value;`,
+      bound: `SELECT $$ @body $$, $tag$ @body $tag$, $1;`,
+      names: []string{"value"},
+    },
+  }
+
+  for _, test := range tests {
+    t.Run(test.name, func(t *testing.T) {
+      bound, names := bind(test.query)
+      assert.Equal(t, test.bound, bound)
+      assert.Equal(t, test.names, names)
+    })
+  }
+}
+
+func TestOrder(t *testing.T) {
+  var args = []driver.NamedValue{
+    {Name: "id", Ordinal: 1, Value: "go"},
+    {Name: "name", Ordinal: 2, Value: "Go"},
+    {Name: "unused", Ordinal: 3, Value: 1},
+  }
+
+  t.Run("by name", func(t *testing.T) {
+    ordered, err := order([]string{"name", "id", "name"}, args)
+    require.NoError(t, err)
+    assert.Equal(t, []driver.NamedValue{
+      {Ordinal: 1, Value: "Go"},
+      {Ordinal: 2, Value: "go"},
+      {Ordinal: 3, Value: "Go"},
+    }, ordered)
+  })
+
+  t.Run("missing argument", func(t *testing.T) {
+    _, err := order([]string{"slug"}, args)
+    assert.ErrorContains(t, err, "@slug")
+  })
+
+  t.Run("positional query", func(t *testing.T) {
+    var positional = []driver.NamedValue{{Ordinal: 1, Value: "go"}}
+    ordered, err := order(nil, positional)
+    require.NoError(t, err)
+    assert.Equal(t, positional, ordered)
+
+    _, err = order(nil, args)
+    assert.ErrorContains(t, err, "@id")
+  })
+}
@@ -0,0 +1,188 @@
+// Package database opens the SQLite or PostgreSQL database of the site
+// with the settings it needs to serve many readers next to a writer,
+// and keeps its schema up to date.
+package database
+
+import (
+  "context"
+  "database/sql"
+  "errors"
+  "fmt"
+  "strings"
+  "time"
+)
+
+// DB is a SQLite database behind two pools of connections. The writer
+// pool has a single connection whose transactions take the write lock
+// as they begin, so writers queue up in it instead of failing with
+// 'database is locked' when one of them upgrades a read to a write.
+// The reader pool has many connections that cannot write; in WAL mode
+// they read alongside the writer without blocking it.
+//
+// Statements and transactions go to the writer pool, and queries to
+// the reader pool, except for read-only transactions, which go to the
+// reader pool too. A query that writes, like an INSERT with a
+// RETURNING clause, must therefore be run in a transaction. Within a
+// unit of work (see Transact), everything runs in its transaction.
+//
+// A PostgreSQL database has a single pool that is both.
+type DB struct {
+  writer  *sql.DB
+  reader  *sql.DB
+  dialect Dialect
+}
+
+// Open opens the SQLite database at path through the driver registered
+// as driver, with WAL journaling, enforced foreign keys and a busy
+// timeout, which is how long a connection waits for a lock held by
+// another process before it fails. The reader pool holds up to readers
+// connections. An in-memory database is private to its connection, so
+// it gets a single pool of one connection.
+func Open(driver, path string, readers int, busyTimeout time.Duration) (*DB, error) {
+  var settings = fmt.Sprintf("_foreign_keys=on&_busy_timeout=%d", busyTimeout.Milliseconds())
+
+  if inMemory(path) {
+    db, err := sql.Open(driver, dsn(path, settings))
+    if nil != err {
+      return nil, err
+    }
+
+    db.SetMaxOpenConns(1)
+    db.SetConnMaxLifetime(0)
+    db.SetConnMaxIdleTime(0)
+
+    return &DB{writer: db, reader: db, dialect: SQLite}, nil
+  }
+
+  writer, err := sql.Open(driver, dsn(path, settings+"&_journal_mode=WAL&_synchronous=NORMAL&_txlock=immediate"))
+  if nil != err {
+    return nil, err
+  }
+
+  writer.SetMaxOpenConns(1)
+
+  // The writer switches the database to WAL mode, which is persistent,
+  // so it connects before any reader does.
+  if err = writer.Ping(); nil != err {
+    writer.Close()
+    return nil, err
+  }
+
+  reader, err := sql.Open(driver, dsn(path, settings+"&_query_only=on"))
+  if nil != err {
+    writer.Close()
+    return nil, err
+  }
+
+  reader.SetMaxOpenConns(readers)
+  reader.SetMaxIdleConns(readers)
+
+  return &DB{writer: writer, reader: reader, dialect: SQLite}, nil
+}
+
+// inMemory reports whether path names an in-memory database.
+func inMemory(path string) bool {
+  return strings.Contains(path, ":memory:") || strings.Contains(path, "mode=memory")
+}
+
+// dsn appends the settings to the parameters of path.
+func dsn(path, settings string) string {
+  if strings.Contains(path, "?") {
+    return path + "&" + settings
+  }
+
+  return path + "?" + settings
+}
+
+func (db *DB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+  if tx := db.current(ctx); nil != tx {
+    return tx.ExecContext(ctx, query, args...)
+  }
+
+  return db.writer.ExecContext(ctx, query, args...)
+}
+
+func (db *DB) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+  if tx := db.current(ctx); nil != tx {
+    return tx.QueryContext(ctx, query, args...)
+  }
+
+  return db.reader.QueryContext(ctx, query, args...)
+}
+
+func (db *DB) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+  if tx := db.current(ctx); nil != tx {
+    return tx.QueryRowContext(ctx, query, args...)
+  }
+
+  return db.reader.QueryRowContext(ctx, query, args...)
+}
+
+// BeginTx begins a transaction, or joins the unit of work ctx carries.
+func (db *DB) BeginTx(ctx context.Context, opts *sql.TxOptions) (*Tx, error) {
+  if tx := db.current(ctx); nil != tx {
+    return &Tx{Tx: tx, joined: true}, nil
+  }
+
+  var (
+    tx  *sql.Tx
+    err error
+  )
+
+  if nil != opts && opts.ReadOnly {
+    tx, err = db.reader.BeginTx(ctx, opts)
+  } else {
+    tx, err = db.writer.BeginTx(ctx, opts)
+  }
+
+  if nil != err {
+    return nil, err
+  }
+
+  return &Tx{Tx: tx}, nil
+}
+
+// Conn returns the connection of the writer pool, for the statements
+// that must run on the same connection, like the pragmas that cannot
+// change within a transaction. It must be closed to be released.
+func (db *DB) Conn(ctx context.Context) (*sql.Conn, error) {
+  return db.writer.Conn(ctx)
+}
+
+func (db *DB) PingContext(ctx context.Context) error {
+  if err := db.writer.PingContext(ctx); nil != err {
+    return err
+  }
+
+  return db.reader.PingContext(ctx)
+}
+
+// Dialect returns the dialect of the SQL the database speaks.
+func (db *DB) Dialect() Dialect {
+  return db.dialect
+}
+
+// Optimize lets SQLite analyze the tables whose statistics would help
+// the queries run so far to plan better. PostgreSQL does so on its own.
+func (db *DB) Optimize(ctx context.Context) error {
+  if SQLite != db.dialect {
+    return nil
+  }
+
+  _, err := db.writer.ExecContext(ctx, `PRAGMA optimize;`)
+  return err
+}
+
+// Close optimizes the database and closes both pools.
+func (db *DB) Close() error {
+  ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+  defer cancel()
+
+  var err = db.Optimize(ctx)
+
+  if db.reader != db.writer {
+    err = errors.Join(err, db.reader.Close())
+  }
+
+  return errors.Join(err, db.writer.Close())
+}
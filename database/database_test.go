@@ -0,0 +1,204 @@
+package database
+
+import (
+  "context"
+  "database/sql"
+  _ "github.com/mattn/go-sqlite3"
+  "github.com/stretchr/testify/assert"
+  "github.com/stretchr/testify/require"
+  "os"
+  "path/filepath"
+  "sync"
+  "testing"
+  "time"
+)
+
+const testSchema = `
+CREATE TABLE "topic"
+(
+  "id"    VARCHAR(32) PRIMARY KEY,
+  "views" INT NOT NULL DEFAULT 0
+);
+CREATE TABLE "article"
+(
+  "uuid"  VARCHAR(36) NOT NULL PRIMARY KEY,
+  "topic" VARCHAR(32) REFERENCES "topic" ("id")
+);`
+
+// open opens a database with the test schema in a temporary directory.
+func open(t *testing.T, path string) *DB {
+  db, err := Open("sqlite3", path, 4, 5*time.Second)
+  require.NoError(t, err)
+  t.Cleanup(func() { db.Close() })
+  return db
+}
+
+func TestOpen(t *testing.T) {
+  var ctx = context.Background()
+  var db = open(t, filepath.Join(t.TempDir(), "db.sqlite"))
+  _, err := db.ExecContext(ctx, testSchema)
+  require.NoError(t, err)
+
+  t.Run("settings", func(t *testing.T) {
+    for _, pool := range []*sql.DB{db.writer, db.reader} {
+      var journalMode string
+      var foreignKeys, busyTimeout int
+      require.NoError(t, pool.QueryRowContext(ctx, `PRAGMA journal_mode;`).Scan(&journalMode))
+      require.NoError(t, pool.QueryRowContext(ctx, `PRAGMA foreign_keys;`).Scan(&foreignKeys))
+      require.NoError(t, pool.QueryRowContext(ctx, `PRAGMA busy_timeout;`).Scan(&busyTimeout))
+      assert.Equal(t, "wal", journalMode)
+      assert.Equal(t, 1, foreignKeys)
+      assert.Equal(t, 5000, busyTimeout)
+    }
+  })
+
+  t.Run("foreign keys are enforced", func(t *testing.T) {
+    _, err := db.ExecContext(ctx, `INSERT INTO "article" ("uuid", "topic") VALUES ('a', 'missing');`)
+    assert.ErrorContains(t, err, "FOREIGN KEY constraint failed")
+  })
+
+  t.Run("queries cannot write", func(t *testing.T) {
+    rows, err := db.QueryContext(ctx, `INSERT INTO "topic" ("id") VALUES ('go') RETURNING "id";`)
+    if nil == err {
+      rows.Next()
+      err = rows.Err()
+      rows.Close()
+    }
+    assert.Error(t, err)
+  })
+
+  t.Run("read-only transactions read", func(t *testing.T) {
+    tx, err := db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+    require.NoError(t, err)
+    defer tx.Rollback()
+    _, err = tx.ExecContext(ctx, `INSERT INTO "topic" ("id") VALUES ('go');`)
+    assert.Error(t, err)
+  })
+}
+
+func TestOpen_InMemory(t *testing.T) {
+  var ctx = context.Background()
+  var db = open(t, ":memory:")
+  _, err := db.ExecContext(ctx, testSchema)
+  require.NoError(t, err)
+
+  _, err = db.ExecContext(ctx, `INSERT INTO "topic" ("id") VALUES ('go');`)
+  require.NoError(t, err)
+
+  var n int
+  require.NoError(t, db.QueryRowContext(ctx, `SELECT count (*) FROM "topic";`).Scan(&n))
+  assert.Equal(t, 1, n)
+}
+
+// TestDB_Concurrency runs transactions that read before they write,
+// which used to fail with 'database is locked' when two of them tried
+// to upgrade their read locks at once, alongside many readers and the
+// writes of another process, and checks that none of them fails.
+func TestDB_Concurrency(t *testing.T) {
+  const (
+    writers      = 8
+    transactions = 50
+    readers      = 16
+  )
+
+  var ctx = context.Background()
+  var path = filepath.Join(t.TempDir(), "db.sqlite")
+  var db = open(t, path)
+  _, err := db.ExecContext(ctx, testSchema)
+  require.NoError(t, err)
+  _, err = db.ExecContext(ctx, `INSERT INTO "topic" ("id") VALUES ('go');`)
+  require.NoError(t, err)
+
+  // other stands for another process, like a command run from the
+  // shell while the server is running.
+  var other = open(t, path)
+
+  var (
+    wg     sync.WaitGroup
+    errs   = make(chan error, (writers+1)*transactions+readers)
+    done   = make(chan struct{})
+    reads  sync.WaitGroup
+    update = func(db *DB) error {
+      tx, err := db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+      if nil != err {
+        return err
+      }
+      defer tx.Rollback()
+
+      var views int
+      if err = tx.QueryRowContext(ctx, `SELECT "views" FROM "topic" WHERE "id" = 'go';`).Scan(&views); nil != err {
+        return err
+      }
+
+      if _, err = tx.ExecContext(ctx, `UPDATE "topic" SET "views" = @views WHERE "id" = 'go';`, sql.Named("views", views+1)); nil != err {
+        return err
+      }
+
+      return tx.Commit()
+    }
+  )
+
+  for range readers {
+    reads.Add(1)
+    go func() {
+      defer reads.Done()
+      for {
+        select {
+        case <-done:
+          return
+        default:
+        }
+
+        var views int
+        if err := db.QueryRowContext(ctx, `SELECT "views" FROM "topic" WHERE "id" = 'go';`).Scan(&views); nil != err {
+          errs <- err
+          return
+        }
+      }
+    }()
+  }
+
+  for i := range writers + 1 {
+    var db = db
+    if writers == i {
+      db = other
+    }
+
+    wg.Add(1)
+    go func() {
+      defer wg.Done()
+      for range transactions {
+        if err := update(db); nil != err {
+          errs <- err
+        }
+      }
+    }()
+  }
+
+  wg.Wait()
+  close(done)
+  reads.Wait()
+  close(errs)
+
+  for err := range errs {
+    assert.NoError(t, err)
+  }
+
+  var views int
+  require.NoError(t, db.QueryRowContext(ctx, `SELECT "views" FROM "topic" WHERE "id" = 'go';`).Scan(&views))
+  assert.Equal(t, (writers+1)*transactions, views)
+}
+
+func TestDB_Close(t *testing.T) {
+  var path = filepath.Join(t.TempDir(), "db.sqlite")
+  db, err := Open("sqlite3", path, 4, 5*time.Second)
+  require.NoError(t, err)
+
+  _, err = db.ExecContext(context.Background(), testSchema)
+  require.NoError(t, err)
+  assert.FileExists(t, path+"-wal")
+
+  require.NoError(t, db.Close())
+  _, err = os.Stat(path + "-wal")
+  assert.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -0,0 +1,91 @@
+package database
+
+import (
+  "fmt"
+)
+
+// Dialect is the flavor of SQL a database speaks, for the few
+// expressions the repositories cannot write the same way for every
+// database the site can store its data in.
+type Dialect interface {
+  // Name is the name of the database management system.
+  Name() string
+
+  // Year is the expression that extracts the year of the timestamp
+  // expression as an integer.
+  Year(timestamp string) string
+
+  // Month is the expression that extracts the month of the timestamp
+  // expression as an integer.
+  Month(timestamp string) string
+
+  // After is the expression of the moment the number of seconds of the
+  // expression seconds after the current timestamp.
+  After(seconds string) string
+
+  // Like is the operator that matches a pattern regardless of case.
+  Like() string
+
+  // schema returns the tables and migrations of the database.
+  schema() *schema
+}
+
+var (
+  // SQLite is the dialect of SQLite databases.
+  SQLite Dialect = sqliteDialect{}
+
+  // Postgres is the dialect of PostgreSQL databases.
+  Postgres Dialect = postgresDialect{}
+)
+
+type sqliteDialect struct{}
+
+func (sqliteDialect) Name() string {
+  return "sqlite"
+}
+
+func (sqliteDialect) Year(timestamp string) string {
+  return fmt.Sprintf("cast(strftime('%%Y', %s) AS INTEGER)", timestamp)
+}
+
+func (sqliteDialect) Month(timestamp string) string {
+  return fmt.Sprintf("cast(strftime('%%m', %s) AS INTEGER)", timestamp)
+}
+
+func (sqliteDialect) After(seconds string) string {
+  return fmt.Sprintf("datetime(current_timestamp, '+' || %s || ' seconds')", seconds)
+}
+
+func (sqliteDialect) Like() string {
+  return "LIKE"
+}
+
+func (sqliteDialect) schema() *schema {
+  return &sqliteSchema
+}
+
+type postgresDialect struct{}
+
+func (postgresDialect) Name() string {
+  return "postgresql"
+}
+
+func (postgresDialect) Year(timestamp string) string {
+  return fmt.Sprintf("cast(extract(YEAR FROM %s) AS INTEGER)", timestamp)
+}
+
+func (postgresDialect) Month(timestamp string) string {
+  return fmt.Sprintf("cast(extract(MONTH FROM %s) AS INTEGER)", timestamp)
+}
+
+func (postgresDialect) After(seconds string) string {
+  return fmt.Sprintf("cast(current_timestamp + make_interval(secs => %s) AS TIMESTAMP)", seconds)
+}
+
+func (postgresDialect) Like() string {
+  return "ILIKE"
+}
+
+func (postgresDialect) schema() *schema {
+  return &postgresSchema
+}
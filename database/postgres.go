@@ -0,0 +1,45 @@
+package database
+
+import (
+  "database/sql"
+  "fontseca.dev/tracing"
+  "github.com/lib/pq"
+  "strings"
+)
+
+// PostgresDriver is the name of the traced PostgreSQL driver whose
+// connections accept named parameters.
+const PostgresDriver = "postgres_named"
+
+func init() {
+  sql.Register(PostgresDriver, tracing.WrapDriver(&binder{driver: &pq.Driver{}}, Postgres.Name()))
+}
+
+// OpenPostgres opens the PostgreSQL database at url, a connection
+// string in either the URL or the keyword/value form, with up to conns
+// connections in a single pool, since PostgreSQL needs no separate
+// pools to let readers and writers run alongside each other. Sessions
+// use UTC as their time zone, the one the SQLite database keeps its
+// timestamps in, unless url sets a different one.
+func OpenPostgres(url string, conns int) (*DB, error) {
+  if !strings.Contains(url, "timezone=") {
+    switch {
+    case strings.Contains(url, "://") && strings.Contains(url, "?"):
+      url += "&timezone=UTC"
+    case strings.Contains(url, "://"):
+      url += "?timezone=UTC"
+    default:
+      url += " timezone=UTC"
+    }
+  }
+
+  db, err := sql.Open(PostgresDriver, url)
+  if nil != err {
+    return nil, err
+  }
+
+  db.SetMaxOpenConns(conns)
+  db.SetMaxIdleConns(conns)
+
+  return &DB{writer: db, reader: db, dialect: Postgres}, nil
+}
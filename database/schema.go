@@ -0,0 +1,221 @@
+package database
+
+import (
+  "context"
+  "database/sql"
+  "fmt"
+  "log/slog"
+  "time"
+)
+
+// schema is the definition of a database: the tables it was first
+// released with and the migrations made to them since.
+type schema struct {
+  tables     []table
+  migrations []migration
+
+  // exists is the query that counts the tables named $1.
+  exists string
+
+  // relax and enforce are the statements run before and after the
+  // migrations to stop and resume enforcing foreign keys, if they
+  // cannot be deferred within a transaction.
+  relax, enforce string
+
+  // violations is the query that finds the rows that reference missing
+  // rows, by the table they are in and the table they reference.
+  violations string
+}
+
+// table contains information about a relation in the database.
+type table struct {
+  name       string
+  definition string
+}
+
+// exists checks if the table t.name is already created in the transaction tx.
+func (t *table) exists(ctx context.Context, tx *sql.Tx, query string) (bool, error) {
+  ctx, cancel := context.WithTimeout(ctx, time.Second)
+  defer cancel()
+  var n int
+  if err := tx.QueryRowContext(ctx, query, t.name).Scan(&n); nil != err {
+    return false, fmt.Errorf("checking existence of table %q: %v", t.name, err)
+  }
+  return n >= 1, nil
+}
+
+// create attempts to create the table t in the transaction tx.
+func (t *table) create(ctx context.Context, tx *sql.Tx) error {
+  ctx, cancel := context.WithTimeout(ctx, time.Second)
+  defer cancel()
+  if _, err := tx.ExecContext(ctx, t.definition); nil != err {
+    return fmt.Errorf("creating table %q: %v", t.name, err)
+  }
+  return nil
+}
+
+// migration is a change to the schema of a table that already exists.
+// Every migration is applied only once and recorded by its version in
+// the "schema_migration" table.
+type migration struct {
+  version    int
+  name       string
+  statements []string
+}
+
+// applied checks if the migration m was already applied in the transaction tx.
+func (m *migration) applied(ctx context.Context, tx *sql.Tx) (bool, error) {
+  var query = `
+  SELECT count (1)
+    FROM "schema_migration"
+   WHERE "version" = $1;`
+  ctx, cancel := context.WithTimeout(ctx, time.Second)
+  defer cancel()
+  var n int
+  if err := tx.QueryRowContext(ctx, query, m.version).Scan(&n); nil != err {
+    return false, fmt.Errorf("checking migration %d (%s): %v", m.version, m.name, err)
+  }
+  return n >= 1, nil
+}
+
+// apply runs every statement of the migration m in the transaction tx
+// and records it as applied.
+func (m *migration) apply(ctx context.Context, tx *sql.Tx) error {
+  ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+  defer cancel()
+  var record = `
+  INSERT INTO "schema_migration" ("version", "name")
+                          VALUES ($1, $2);`
+  for _, statement := range append(m.statements, record) {
+    var args []any
+    if record == statement {
+      args = []any{m.version, m.name}
+    }
+    if _, err := tx.ExecContext(ctx, statement, args...); nil != err {
+      return fmt.Errorf("applying migration %d (%s): %v", m.version, m.name, err)
+    }
+  }
+  slog.InfoContext(ctx, "applied migration", slog.Int("version", m.version), slog.String("name", m.name))
+  return nil
+}
+
+// Version is the version of the last migration known to this binary,
+// which is the same for every dialect.
+func Version() int {
+  return sqliteSchema.migrations[len(sqliteSchema.migrations)-1].version
+}
+
+// Migrate creates every missing table of db and applies every pending
+// migration in a single transaction. Migrations rebuild tables that
+// others reference, so where foreign keys cannot be deferred they are
+// not enforced while they run, which they can only stop being outside
+// of a transaction; the rows that break them afterwards are reported
+// instead.
+func Migrate(ctx context.Context, db *DB) (err error) {
+  var s = db.dialect.schema()
+
+  conn, err := db.Conn(ctx)
+  if nil != err {
+    return err
+  }
+
+  defer conn.Close()
+
+  if "" != s.relax {
+    if _, err = conn.ExecContext(ctx, s.relax); nil != err {
+      return err
+    }
+
+    defer func() {
+      if _, enforceErr := conn.ExecContext(ctx, s.enforce); nil != enforceErr && nil == err {
+        err = enforceErr
+      }
+    }()
+  }
+
+  tx, err := conn.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+  if nil != err {
+    return err
+  }
+
+  defer tx.Rollback()
+
+  for _, t := range s.tables {
+    exists, err := t.exists(ctx, tx, s.exists)
+    if nil != err {
+      return err
+    }
+
+    if !exists {
+      if err = t.create(ctx, tx); nil != err {
+        return err
+      }
+    }
+  }
+
+  for _, m := range s.migrations {
+    applied, err := m.applied(ctx, tx)
+    if nil != err {
+      return err
+    }
+
+    if !applied {
+      if err = m.apply(ctx, tx); nil != err {
+        return err
+      }
+    }
+  }
+
+  if err = tx.Commit(); nil != err {
+    return err
+  }
+
+  if "" == s.violations {
+    return nil
+  }
+
+  rows, err := conn.QueryContext(ctx, s.violations)
+  if nil != err {
+    return err
+  }
+
+  defer rows.Close()
+
+  var violations = map[string]int{}
+  for rows.Next() {
+    var table, parent string
+    if err = rows.Scan(&table, &parent); nil != err {
+      return err
+    }
+
+    violations[table+" → "+parent]++
+  }
+
+  if err = rows.Err(); nil != err {
+    return err
+  }
+
+  for reference, count := range violations {
+    slog.WarnContext(ctx, "rows reference missing rows",
+      slog.String("reference", reference),
+      slog.Int("count", count))
+  }
+
+  return nil
+}
+
+// CheckSchema checks that the last of the migrations known to this
+// binary is the last one applied to the database db.
+func CheckSchema(ctx context.Context, db *DB) error {
+  var query = `
+  SELECT coalesce (max ("version"), 0)
+    FROM "schema_migration";`
+  var applied int
+  if err := db.QueryRowContext(ctx, query).Scan(&applied); nil != err {
+    return err
+  }
+  if latest := Version(); latest != applied {
+    return fmt.Errorf("schema version is %d, but %d is expected", applied, latest)
+  }
+  return nil
+}
@@ -0,0 +1,45 @@
+package database
+
+import (
+  "context"
+  "github.com/stretchr/testify/assert"
+  "github.com/stretchr/testify/require"
+  "testing"
+)
+
+func TestSchemas(t *testing.T) {
+  require.Equal(t, len(sqliteSchema.tables), len(postgresSchema.tables))
+  for i := range sqliteSchema.tables {
+    assert.Equal(t, sqliteSchema.tables[i].name, postgresSchema.tables[i].name)
+  }
+
+  require.Equal(t, len(sqliteSchema.migrations), len(postgresSchema.migrations))
+  for i := range sqliteSchema.migrations {
+    assert.Equal(t, sqliteSchema.migrations[i].version, postgresSchema.migrations[i].version)
+    assert.Equal(t, sqliteSchema.migrations[i].name, postgresSchema.migrations[i].name)
+  }
+}
+
+func TestMigrate(t *testing.T) {
+  var ctx = context.Background()
+
+  db, err := Open(SQLiteDriver, ":memory:", 1, 0)
+  require.NoError(t, err)
+  defer db.Close()
+
+  assert.Error(t, CheckSchema(ctx, db))
+
+  require.NoError(t, Migrate(ctx, db))
+  require.NoError(t, CheckSchema(ctx, db))
+
+  // Migrating again changes nothing.
+  require.NoError(t, Migrate(ctx, db))
+
+  var n int
+  require.NoError(t, db.QueryRowContext(ctx, `SELECT count (*) FROM "schema_migration";`).Scan(&n))
+  assert.Equal(t, Version(), n)
+
+  var foreignKeys bool
+  require.NoError(t, db.QueryRowContext(ctx, `PRAGMA foreign_keys;`).Scan(&foreignKeys))
+  assert.True(t, foreignKeys)
+}
@@ -0,0 +1,36 @@
+package database
+
+import (
+  "database/sql"
+  "fontseca.dev/tracing"
+  "github.com/google/uuid"
+  "github.com/mattn/go-sqlite3"
+)
+
+// SQLiteDriver is the name of the traced SQLite driver whose
+// connections have the functions the schema uses to generate UUIDs.
+const SQLiteDriver = "sqlite3_custom"
+
+func init() {
+  sql.Register(SQLiteDriver, tracing.WrapDriver(&sqlite3.SQLiteDriver{
+    ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+      if err := conn.RegisterFunc(
+        "uuid_generate_v4",
+        func() string { return uuid.New().String() },
+        true,
+      ); nil != err {
+        return err
+      }
+
+      if err := conn.RegisterFunc(
+        "uuid_nil",
+        func() string { return uuid.Nil.String() },
+        true,
+      ); nil != err {
+        return err
+      }
+
+      return nil
+    },
+  }, SQLite.Name()))
+}
@@ -0,0 +1,363 @@
+package database
+
+// sqliteSchema is the definition of the SQLite database. SQLite cannot
+// defer the foreign keys broken by the tables the migrations rebuild,
+// so it stops enforcing them while they run and looks for the rows
+// that break them afterwards.
+var sqliteSchema = schema{
+  tables:     sqliteTables,
+  migrations: sqliteMigrations,
+  exists: `
+  SELECT count (1)
+    FROM "sqlite_master"
+   WHERE "type" = 'table'
+     AND "name" = $1;`,
+  relax:   `PRAGMA foreign_keys = OFF;`,
+  enforce: `PRAGMA foreign_keys = ON;`,
+  violations: `
+  SELECT "table", "parent"
+    FROM pragma_foreign_key_check ();`,
+}
+
+// sqliteTables is the schema of the SQLite database as it was first
+// released. Every later change to it is a migration.
+var sqliteTables = []table{
+  {
+    name: "schema_migration",
+    definition: `
+    CREATE TABLE "schema_migration"
+    (
+      "version"    INT NOT NULL PRIMARY KEY,
+      "name"       VARCHAR(128) NOT NULL,
+      "applied_at" TIMESTAMP NOT NULL DEFAULT current_timestamp
+    );`,
+  },
+  {
+    name: "me",
+    definition: `
+    CREATE TABLE "me"
+    (
+      "username"      VARCHAR(64) UNIQUE NOT NULL DEFAULT 'fontseca.dev',
+      "first_name"    VARCHAR(6) NOT NULL DEFAULT 'Jeremy',
+      "last_name"     VARCHAR(7) NOT NULL DEFAULT 'Fonseca',
+      "summary"       VARCHAR(1024) NOT NULL,
+      "job_title"     VARCHAR(64) NOT NULL DEFAULT 'Back-End Software Developer',
+      "email"         VARCHAR(254) NOT NULL,
+      "photo_url"     VARCHAR(2048) NOT NULL DEFAULT 'about:blank',
+      "resume_url"    VARCHAR(2048) NOT NULL DEFAULT 'about:blank',
+      "coding_since"  INT NOT NULL DEFAULT 2017,
+      "company"       VARCHAR(64),
+      "location"      VARCHAR(64),
+      "hireable"      BOOLEAN NOT NULL DEFAULT TRUE,
+      "github_url"    VARCHAR(2048) NOT NULL DEFAULT 'https://github.com/fontseca',
+      "linkedin_url"  VARCHAR(2048) NOT NULL DEFAULT 'about:blank',
+      "youtube_url"   VARCHAR(2048) NOT NULL DEFAULT 'about:blank',
+      "twitter_url"   VARCHAR(2048) NOT NULL DEFAULT 'about:blank',
+      "instagram_url" VARCHAR(2048) NOT NULL DEFAULT 'about:blank',
+      "created_at"    TIMESTAMP NOT NULL DEFAULT current_timestamp,
+      "updated_at"    TIMESTAMP NOT NULL DEFAULT current_timestamp,
+      CHECK ("coding_since" = 2017)
+    );`,
+  },
+  {
+    name: "experience",
+    definition: `
+    CREATE TABLE "experience"
+    (
+      "uuid"       VARCHAR(36) NOT NULL PRIMARY KEY DEFAULT (uuid_generate_v4 ()),
+      "starts"     INT NOT NULL,
+      "ends"       INT NULL,
+      "job_title"  VARCHAR(64) NOT NULL DEFAULT 'Back-End Software Developer',
+      "company"    VARCHAR(64) NOT NULL,
+      "country"    VARCHAR(64),
+      "summary"    TEXT NOT NULL,
+      "active"     BOOLEAN DEFAULT FALSE,
+      "hidden"     BOOLEAN DEFAULT FALSE,
+      "created_at" TIMESTAMP NOT NULL DEFAULT current_timestamp,
+      "updated_at" TIMESTAMP NOT NULL DEFAULT current_timestamp,
+      CHECK ("starts" > 2017),
+      CHECK ("ends" > 2017 OR "ends" IS NULL)
+    );`,
+  },
+  {
+    name: "project",
+    definition: `
+    CREATE TABLE "project"
+    (
+      "uuid"             VARCHAR(36) NOT NULL PRIMARY KEY DEFAULT (uuid_generate_v4 ()),
+      "name"             VARCHAR(64) NOT NULL,
+      "slug"             VARCHAR(2024) NOT NULL,
+      "homepage"         VARCHAR(2048) NOT NULL ON CONFLICT REPLACE DEFAULT 'about:blank',
+      "language"         VARCHAR(64) NULL,
+      "summary"          VARCHAR(1024) NOT NULL ON CONFLICT REPLACE DEFAULT 'No summary.',
+      "read_time"        INT NOT NULL ON CONFLICT REPLACE DEFAULT 0,
+      "content"          TEXT NOT NULL ON CONFLICT REPLACE DEFAULT 'No content.',
+      "estimated_time"   INT DEFAULT NULL,
+      "first_image_url"  VARCHAR(2048) NOT NULL ON CONFLICT REPLACE DEFAULT 'about:blank',
+      "second_image_url" VARCHAR(2048) NOT NULL ON CONFLICT REPLACE DEFAULT 'about:blank',
+      "github_url"       VARCHAR(2048) NOT NULL ON CONFLICT REPLACE DEFAULT 'about:blank',
+      "collection_url"   VARCHAR(2048) NOT NULL ON CONFLICT REPLACE DEFAULT 'about:blank',
+      "playground_url"   VARCHAR(2048) NOT NULL ON CONFLICT REPLACE DEFAULT 'about:blank',
+      "playable"         BOOLEAN NOT NULL DEFAULT FALSE,
+      "archived"         BOOLEAN NOT NULL DEFAULT FALSE,
+      "finished"         BOOLEAN DEFAULT FALSE,
+      "created_at"       TIMESTAMP NOT NULL DEFAULT current_timestamp,
+      "updated_at"       TIMESTAMP NOT NULL DEFAULT current_timestamp
+    );`,
+  },
+  {
+    name: "technology_tag",
+    definition: `
+    CREATE TABLE "technology_tag"
+    (
+      "uuid"        VARCHAR(36) NOT NULL PRIMARY KEY DEFAULT (uuid_generate_v4 ()),
+      "name"        VARCHAR(64) NOT NULL,
+      "created_at"  TIMESTAMP NOT NULL DEFAULT current_timestamp,
+      "updated_at"  TIMESTAMP NOT NULL DEFAULT current_timestamp
+    );`,
+  },
+  {
+    name: "project_technology_tag",
+    definition: `
+    CREATE TABLE "project_technology_tag"
+    (
+      "project_uuid"        VARCHAR(36) NOT NULL REFERENCES "project" ("uuid"),
+      "technology_tag_uuid" VARCHAR(36) NOT NULL REFERENCES "technology_tag" ("uuid")
+    );`,
+  },
+  {
+    name: "topic",
+    definition: `
+    CREATE TABLE "topic"
+    (
+      "id"         VARCHAR(32) PRIMARY KEY,
+      "name"       VARCHAR(32) NOT NULL,
+      "created_at" TIMESTAMP NOT NULL DEFAULT current_timestamp,
+      "updated_at" TIMESTAMP NOT NULL DEFAULT current_timestamp
+    );`,
+  },
+  {
+    name: "article",
+    definition: `
+    CREATE TABLE "article"
+    (
+      "uuid"         VARCHAR(36) NOT NULL PRIMARY KEY DEFAULT (uuid_generate_v4 ()),
+      "title"        VARCHAR(256) NOT NULL,
+      "author"       VARCHAR(64) NOT NULL REFERENCES "me" ("username"),
+      "slug"         VARCHAR(512) NOT NULL,
+      "read_time"    INT NOT NULL ON CONFLICT REPLACE DEFAULT 0,
+      "views"        INTEGER NOT NULL ON CONFLICT REPLACE DEFAULT 0,
+      "content"      TEXT NOT NULL ON CONFLICT REPLACE DEFAULT 'No content.',
+      "draft"        BOOLEAN DEFAULT TRUE,
+      "pinned"       BOOLEAN DEFAULT FALSE,
+      "hidden"       BOOLEAN DEFAULT FALSE,
+      "topic"        VARCHAR(32) REFERENCES "topic" ("id"),
+      "drafted_at"   TIMESTAMP NOT NULL DEFAULT current_timestamp,
+      "published_at" TIMESTAMP DEFAULT NULL,
+      "updated_at"   TIMESTAMP NOT NULL DEFAULT current_timestamp,
+      "modified_at"  TIMESTAMP DEFAULT NULL
+    );`,
+  },
+  {
+    name: "article_patch",
+    definition: `
+    CREATE TABLE "article_patch"
+    (
+      "article_uuid" VARCHAR(36) UNIQUE PRIMARY KEY NOT NULL REFERENCES "article" ("uuid"),
+      "title"        VARCHAR(256),
+      "topic"        VARCHAR(32) REFERENCES "topic" ("id"),
+      "slug"         VARCHAR(512),
+      "read_time"    INT DEFAULT 0,
+      "content"      TEXT
+    );`,
+  },
+  {
+    name: "article_link",
+    definition: `
+    CREATE TABLE "article_link"
+    (
+      "article_uuid"  VARCHAR(36) UNIQUE PRIMARY KEY NOT NULL REFERENCES "article" ("uuid"),
+      "sharable_link" VARCHAR(248),
+      "expires_at"    TIMESTAMP NOT NULL DEFAULT (datetime(current_timestamp, '+7 day'))
+    );`,
+  },
+  {
+    name: "tag",
+    definition: `
+    CREATE TABLE "tag"
+    (
+      "id"         VARCHAR(32) NOT NULL PRIMARY KEY,
+      "name"       VARCHAR(32) NOT NULL,
+      "created_at" TIMESTAMP NOT NULL DEFAULT current_timestamp,
+      "updated_at" TIMESTAMP NOT NULL DEFAULT current_timestamp
+    );`,
+  },
+  {
+    name: "article_tag",
+    definition: `
+    CREATE TABLE "article_tag"
+    (
+      "article_uuid" VARCHAR(36) NOT NULL REFERENCES "article" ("uuid"),
+      "tag_id"     VARCHAR(32) NOT NULL REFERENCES "tag" ("id")
+    );`,
+  },
+}
+
+// sqliteMigrations are the changes made to the schema of the SQLite
+// database, in the order they must be applied.
+var sqliteMigrations = []migration{
+  {
+    version: 1,
+    name:    "concurrent article patches",
+    statements: []string{
+      `ALTER TABLE "article" ADD COLUMN "revision" INT NOT NULL DEFAULT 0;`,
+      `
+      CREATE TABLE "article_patch_new"
+      (
+        "uuid"          VARCHAR(36) NOT NULL PRIMARY KEY DEFAULT (uuid_generate_v4 ()),
+        "article_uuid"  VARCHAR(36) NOT NULL REFERENCES "article" ("uuid"),
+        "base_revision" INT NOT NULL DEFAULT 0,
+        "base_title"    VARCHAR(256) NOT NULL,
+        "base_slug"     VARCHAR(512) NOT NULL,
+        "base_topic"    VARCHAR(32),
+        "base_content"  TEXT NOT NULL,
+        "title"         VARCHAR(256),
+        "topic"         VARCHAR(32) REFERENCES "topic" ("id"),
+        "slug"          VARCHAR(512),
+        "read_time"     INT DEFAULT 0,
+        "content"       TEXT,
+        "created_at"    TIMESTAMP NOT NULL DEFAULT current_timestamp
+      );`,
+      `
+      INSERT INTO "article_patch_new" ("article_uuid",
+                                       "base_revision",
+                                       "base_title",
+                                       "base_slug",
+                                       "base_topic",
+                                       "base_content",
+                                       "title",
+                                       "topic",
+                                       "slug",
+                                       "read_time",
+                                       "content")
+           SELECT p."article_uuid",
+                  a."revision",
+                  a."title",
+                  a."slug",
+                  a."topic",
+                  a."content",
+                  p."title",
+                  p."topic",
+                  p."slug",
+                  p."read_time",
+                  p."content"
+             FROM "article_patch" p
+             JOIN "article" a
+               ON a."uuid" = p."article_uuid";`,
+      `DROP TABLE "article_patch";`,
+      `ALTER TABLE "article_patch_new" RENAME TO "article_patch";`,
+      `
+      CREATE TABLE "article_link_new"
+      (
+        "article_uuid"  VARCHAR(36) NOT NULL REFERENCES "article" ("uuid"),
+        "patch_uuid"    VARCHAR(36) UNIQUE REFERENCES "article_patch" ("uuid"),
+        "sharable_link" VARCHAR(248) NOT NULL UNIQUE,
+        "expires_at"    TIMESTAMP NOT NULL DEFAULT (datetime(current_timestamp, '+7 day'))
+      );`,
+      `
+      INSERT INTO "article_link_new" ("article_uuid",
+                                      "patch_uuid",
+                                      "sharable_link",
+                                      "expires_at")
+                SELECT l."article_uuid",
+                       p."uuid",
+                       l."sharable_link",
+                       l."expires_at"
+                  FROM "article_link" l
+             LEFT JOIN "article_patch" p
+                    ON p."article_uuid" = l."article_uuid"
+                 WHERE l."sharable_link" IS NOT NULL;`,
+      `DROP TABLE "article_link";`,
+      `ALTER TABLE "article_link_new" RENAME TO "article_link";`,
+    },
+  },
+  {
+    version: 2,
+    name:    "webhooks",
+    statements: []string{
+      `
+      CREATE TABLE "webhook"
+      (
+        "uuid"       VARCHAR(36) NOT NULL PRIMARY KEY DEFAULT (uuid_generate_v4 ()),
+        "url"        VARCHAR(2048) NOT NULL,
+        "secret"     VARCHAR(256) NOT NULL,
+        "events"     TEXT NOT NULL,
+        "created_at" TIMESTAMP NOT NULL DEFAULT current_timestamp,
+        "updated_at" TIMESTAMP NOT NULL DEFAULT current_timestamp
+      );`,
+      `
+      CREATE TABLE "webhook_delivery"
+      (
+        "uuid"             VARCHAR(36) NOT NULL PRIMARY KEY DEFAULT (uuid_generate_v4 ()),
+        "webhook_uuid"     VARCHAR(36) NOT NULL REFERENCES "webhook" ("uuid"),
+        "event"            VARCHAR(64) NOT NULL,
+        "payload"          TEXT NOT NULL,
+        "status"           VARCHAR(16) NOT NULL DEFAULT 'pending',
+        "attempts"         INT NOT NULL DEFAULT 0,
+        "last_status_code" INT DEFAULT NULL,
+        "last_error"       TEXT DEFAULT NULL,
+        "next_attempt_at"  TIMESTAMP DEFAULT current_timestamp,
+        "created_at"       TIMESTAMP NOT NULL DEFAULT current_timestamp,
+        "delivered_at"     TIMESTAMP DEFAULT NULL
+      );`,
+      `CREATE INDEX "webhook_delivery_due" ON "webhook_delivery" ("status", "next_attempt_at");`,
+    },
+  },
+  {
+    version: 3,
+    name:    "optimistic concurrency",
+    statements: []string{
+      `ALTER TABLE "article" ADD COLUMN "version" INT NOT NULL DEFAULT 1;`,
+      `ALTER TABLE "article_patch" ADD COLUMN "version" INT NOT NULL DEFAULT 1;`,
+      `ALTER TABLE "project" ADD COLUMN "version" INT NOT NULL DEFAULT 1;`,
+      `ALTER TABLE "experience" ADD COLUMN "version" INT NOT NULL DEFAULT 1;`,
+      `ALTER TABLE "me" ADD COLUMN "version" INT NOT NULL DEFAULT 1;`,
+    },
+  },
+  {
+    version: 4,
+    name:    "soft deletion",
+    statements: []string{
+      `ALTER TABLE "article" ADD COLUMN "deleted_at" TIMESTAMP DEFAULT NULL;`,
+      `ALTER TABLE "article_patch" ADD COLUMN "deleted_at" TIMESTAMP DEFAULT NULL;`,
+      `ALTER TABLE "project" ADD COLUMN "deleted_at" TIMESTAMP DEFAULT NULL;`,
+      `ALTER TABLE "experience" ADD COLUMN "deleted_at" TIMESTAMP DEFAULT NULL;`,
+      `ALTER TABLE "tag" ADD COLUMN "deleted_at" TIMESTAMP DEFAULT NULL;`,
+      `ALTER TABLE "topic" ADD COLUMN "deleted_at" TIMESTAMP DEFAULT NULL;`,
+    },
+  },
+  {
+    version: 5,
+    name:    "credential",
+    statements: []string{`
+    CREATE TABLE "credential"
+    (
+      "username"      VARCHAR(64) PRIMARY KEY,
+      "password_hash" VARCHAR(256) NOT NULL,
+      "updated_at"    TIMESTAMP NOT NULL DEFAULT current_timestamp
+    );`,
+    },
+  },
+  {
+    version: 6,
+    name:    "api token",
+    statements: []string{`
+    CREATE TABLE "api_token"
+    (
+      "name"       VARCHAR(64) PRIMARY KEY,
+      "hash"       VARCHAR(64) NOT NULL UNIQUE,
+      "created_at" TIMESTAMP NOT NULL DEFAULT current_timestamp
+    );`,
+    },
+  },
+}
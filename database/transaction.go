@@ -0,0 +1,126 @@
+package database
+
+import (
+  "context"
+  "database/sql"
+)
+
+// txKey is the key of the unit of work a context carries.
+type txKey struct{}
+
+// unit is a unit of work: the transaction in which every statement run
+// with the context that carries it takes part.
+type unit struct {
+  db        *DB
+  tx        *sql.Tx
+  committed []func(ctx context.Context)
+}
+
+// Tx is a transaction begun by BeginTx. A transaction begun within a
+// unit of work is the one of the unit, which commits or rolls it back
+// as a whole, so committing or rolling it back has no effect; failing
+// to do so must be reported by returning an error from the unit.
+type Tx struct {
+  *sql.Tx
+  joined bool
+}
+
+func (tx *Tx) Commit() error {
+  if tx.joined {
+    return nil
+  }
+
+  return tx.Tx.Commit()
+}
+
+func (tx *Tx) Rollback() error {
+  if tx.joined {
+    return nil
+  }
+
+  return tx.Tx.Rollback()
+}
+
+// Transact runs fn as a unit of work: every statement run on db with
+// the context fn receives, directly or in a transaction begun with it,
+// takes part in a single transaction that is committed if fn returns
+// nil and rolled back otherwise. Within fn, Transact joins the unit of
+// work it is called in.
+//
+// Only the context fn receives reaches the transaction. While it is
+// open, statements that write through any other context wait for it
+// to end, since SQLite has a single writer.
+func (db *DB) Transact(ctx context.Context, fn func(ctx context.Context) error) error {
+  if nil != db.current(ctx) {
+    return fn(ctx)
+  }
+
+  tx, err := db.writer.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+  if nil != err {
+    return err
+  }
+
+  defer tx.Rollback()
+
+  u := &unit{db: db, tx: tx}
+
+  if err = fn(context.WithValue(ctx, txKey{}, u)); nil != err {
+    return err
+  }
+
+  if err = tx.Commit(); nil != err {
+    return err
+  }
+
+  for _, f := range u.committed {
+    f(ctx)
+  }
+
+  return nil
+}
+
+// Read runs fn as a read-only unit of work on the reader pool: every
+// query run on db with the context fn receives sees the same snapshot
+// of the database, and none of them can write, so fn neither waits for
+// the writers nor holds them up. Within a unit of work, Read joins it.
+func (db *DB) Read(ctx context.Context, fn func(ctx context.Context) error) error {
+  if nil != db.current(ctx) {
+    return fn(ctx)
+  }
+
+  tx, err := db.reader.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+  if nil != err {
+    return err
+  }
+
+  defer tx.Rollback()
+
+  if err = fn(context.WithValue(ctx, txKey{}, &unit{db: db, tx: tx})); nil != err {
+    return err
+  }
+
+  return tx.Commit()
+}
+
+// OnCommit calls fn once the unit of work of db that ctx carries is
+// committed, with a context outside of it, or right away if ctx does
+// not carry any. It is meant for what must only see committed data,
+// like caches built from it.
+func (db *DB) OnCommit(ctx context.Context, fn func(ctx context.Context)) {
+  if u, ok := ctx.Value(txKey{}).(*unit); ok && db == u.db {
+    u.committed = append(u.committed, fn)
+    return
+  }
+
+  fn(ctx)
+}
+
+// current returns the transaction of the unit of work of db that ctx
+// carries, if any.
+func (db *DB) current(ctx context.Context) *sql.Tx {
+  if u, ok := ctx.Value(txKey{}).(*unit); ok && db == u.db {
+    return u.tx
+  }
+
+  return nil
+}
@@ -0,0 +1,169 @@
+package database
+
+import (
+  "context"
+  "database/sql"
+  "errors"
+  "github.com/stretchr/testify/assert"
+  "github.com/stretchr/testify/require"
+  "path/filepath"
+  "testing"
+)
+
+func TestDB_Transact(t *testing.T) {
+  var ctx = context.Background()
+  var db = open(t, filepath.Join(t.TempDir(), "db.sqlite"))
+  _, err := db.ExecContext(ctx, testSchema)
+  require.NoError(t, err)
+
+  var unexpected = errors.New("unexpected error")
+
+  count := func(t *testing.T, ctx context.Context) (n int) {
+    require.NoError(t, db.QueryRowContext(ctx, `SELECT count (*) FROM "topic";`).Scan(&n))
+    return n
+  }
+
+  t.Run("commits when fn succeeds", func(t *testing.T) {
+    t.Cleanup(func() { db.ExecContext(ctx, `DELETE FROM "topic";`) })
+
+    err := db.Transact(ctx, func(ctx context.Context) error {
+      _, err := db.ExecContext(ctx, `INSERT INTO "topic" ("id") VALUES ('go');`)
+      require.NoError(t, err)
+      assert.Equal(t, 1, count(t, ctx), "reads within the unit see its writes")
+      return nil
+    })
+
+    assert.NoError(t, err)
+    assert.Equal(t, 1, count(t, ctx))
+  })
+
+  t.Run("rolls back when fn fails", func(t *testing.T) {
+    err := db.Transact(ctx, func(ctx context.Context) error {
+      _, err := db.ExecContext(ctx, `INSERT INTO "topic" ("id") VALUES ('go');`)
+      require.NoError(t, err)
+      return unexpected
+    })
+
+    assert.ErrorIs(t, err, unexpected)
+    assert.Zero(t, count(t, ctx))
+  })
+
+  t.Run("nested units join the outer one", func(t *testing.T) {
+    err := db.Transact(ctx, func(ctx context.Context) error {
+      err := db.Transact(ctx, func(ctx context.Context) error {
+        _, err := db.ExecContext(ctx, `INSERT INTO "topic" ("id") VALUES ('go');`)
+        return err
+      })
+      require.NoError(t, err)
+      return unexpected
+    })
+
+    assert.ErrorIs(t, err, unexpected)
+    assert.Zero(t, count(t, ctx))
+  })
+
+  t.Run("transactions begun within a unit join it", func(t *testing.T) {
+    err := db.Transact(ctx, func(ctx context.Context) error {
+      tx, err := db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+      require.NoError(t, err)
+      defer tx.Rollback()
+
+      _, err = tx.ExecContext(ctx, `INSERT INTO "topic" ("id") VALUES ('go');`)
+      require.NoError(t, err)
+      require.NoError(t, tx.Commit())
+      return unexpected
+    })
+
+    assert.ErrorIs(t, err, unexpected)
+    assert.Zero(t, count(t, ctx))
+  })
+  t.Run("commit hooks wait for the unit", func(t *testing.T) {
+    t.Cleanup(func() { db.ExecContext(ctx, `DELETE FROM "topic";`) })
+
+    var seen = -1
+
+    hook := func(ctx context.Context) { seen = count(t, ctx) }
+
+    err := db.Transact(ctx, func(ctx context.Context) error {
+      _, err := db.ExecContext(ctx, `INSERT INTO "topic" ("id") VALUES ('go');`)
+      require.NoError(t, err)
+      db.OnCommit(ctx, hook)
+      assert.Equal(t, -1, seen)
+      return unexpected
+    })
+
+    assert.ErrorIs(t, err, unexpected)
+    assert.Equal(t, -1, seen, "hooks of a rolled back unit must not run")
+
+    err = db.Transact(ctx, func(ctx context.Context) error {
+      _, err := db.ExecContext(ctx, `INSERT INTO "topic" ("id") VALUES ('go');`)
+      require.NoError(t, err)
+      db.OnCommit(ctx, hook)
+      return nil
+    })
+
+    assert.NoError(t, err)
+    assert.Equal(t, 1, seen)
+
+    db.OnCommit(ctx, func(context.Context) { seen = 0 })
+    assert.Zero(t, seen, "hooks outside of a unit run right away")
+  })
+}
+
+func TestDB_Read(t *testing.T) {
+  var ctx = context.Background()
+  var db = open(t, filepath.Join(t.TempDir(), "db.sqlite"))
+  _, err := db.ExecContext(ctx, testSchema)
+  require.NoError(t, err)
+
+  count := func(t *testing.T, ctx context.Context) (n int) {
+    require.NoError(t, db.QueryRowContext(ctx, `SELECT count (*) FROM "topic";`).Scan(&n))
+    return n
+  }
+
+  t.Run("reads a snapshot without holding up writers", func(t *testing.T) {
+    t.Cleanup(func() { db.ExecContext(ctx, `DELETE FROM "topic";`) })
+
+    err := db.Read(ctx, func(ctx context.Context) error {
+      assert.Zero(t, count(t, ctx))
+
+      // The writer is free, so this does not wait for the unit.
+      _, err := db.ExecContext(context.Background(), `INSERT INTO "topic" ("id") VALUES ('go');`)
+      require.NoError(t, err)
+
+      assert.Zero(t, count(t, ctx), "reads within the unit see the same snapshot")
+      return nil
+    })
+
+    assert.NoError(t, err)
+    assert.Equal(t, 1, count(t, ctx))
+  })
+
+  t.Run("cannot write", func(t *testing.T) {
+    err := db.Read(ctx, func(ctx context.Context) error {
+      _, err := db.ExecContext(ctx, `INSERT INTO "topic" ("id") VALUES ('rust');`)
+      return err
+    })
+
+    assert.Error(t, err)
+  })
+
+  t.Run("joins the unit it is called in", func(t *testing.T) {
+    var unexpected = errors.New("unexpected error")
+
+    err := db.Transact(ctx, func(ctx context.Context) error {
+      _, err := db.ExecContext(ctx, `INSERT INTO "topic" ("id") VALUES ('zig');`)
+      require.NoError(t, err)
+
+      require.NoError(t, db.Read(ctx, func(ctx context.Context) error {
+        assert.Equal(t, 1, count(t, ctx), "reads within the unit see its writes")
+        return nil
+      }))
+
+      return unexpected
+    })
+
+    assert.ErrorIs(t, err, unexpected)
+    assert.Zero(t, count(t, ctx))
+  })
+}
@@ -0,0 +1,255 @@
+package diff
+
+import (
+  "strings"
+)
+
+// maxEditDistance is the maximum number of edits that Lines is willing
+// to look for before giving up and treating the remaining lines as
+// completely replaced. It keeps memory bounded for unrelated texts.
+const maxEditDistance = 2048
+
+// Operation is the kind of change that a line went through.
+type Operation int
+
+const (
+  // Equal means the line is present in both texts.
+  Equal Operation = iota
+
+  // Insert means the line is present only in the new text.
+  Insert
+
+  // Delete means the line is present only in the old text.
+  Delete
+)
+
+func (o Operation) String() string {
+  switch o {
+  case Insert:
+    return "insert"
+  case Delete:
+    return "delete"
+  default:
+    return "equal"
+  }
+}
+
+// MarshalText makes an operation be encoded with its name.
+func (o Operation) MarshalText() ([]byte, error) {
+  return []byte(o.String()), nil
+}
+
+// Line is a single line of a line-level diff.
+type Line struct {
+  Operation Operation `json:"operation"`
+  Text      string    `json:"text"`
+  Old       int       `json:"old_line,omitempty"` // 1-based line number in the old text; zero on insertions
+  New       int       `json:"new_line,omitempty"` // 1-based line number in the new text; zero on deletions
+}
+
+// split breaks text into lines. An empty text has no lines at all.
+func split(text string) []string {
+  if "" == text {
+    return []string{}
+  }
+
+  return strings.Split(text, "\n")
+}
+
+// Lines computes a line-level diff that turns the text a into the text b.
+func Lines(a, b string) []Line {
+  var (
+    x     = split(a)
+    y     = split(b)
+    lines = make([]Line, 0, max(len(x), len(y)))
+    i, j  = 0, 0
+  )
+
+  for _, m := range matches(x, y) {
+    for ; i < m[0]; i++ {
+      lines = append(lines, Line{Operation: Delete, Text: x[i], Old: 1 + i})
+    }
+
+    for ; j < m[1]; j++ {
+      lines = append(lines, Line{Operation: Insert, Text: y[j], New: 1 + j})
+    }
+
+    lines = append(lines, Line{Operation: Equal, Text: x[i], Old: 1 + i, New: 1 + j})
+    i++
+    j++
+  }
+
+  for ; i < len(x); i++ {
+    lines = append(lines, Line{Operation: Delete, Text: x[i], Old: 1 + i})
+  }
+
+  for ; j < len(y); j++ {
+    lines = append(lines, Line{Operation: Insert, Text: y[j], New: 1 + j})
+  }
+
+  return lines
+}
+
+// matches finds a longest common subsequence between x and y and
+// returns it as ordered pairs of indexes {i, j} where x[i] == y[j].
+func matches(x, y []string) [][2]int {
+  var prefix, suffix int
+
+  for prefix < len(x) && prefix < len(y) && x[prefix] == y[prefix] {
+    prefix++
+  }
+
+  for suffix < len(x)-prefix && suffix < len(y)-prefix && x[len(x)-1-suffix] == y[len(y)-1-suffix] {
+    suffix++
+  }
+
+  pairs := make([][2]int, 0, prefix+suffix)
+
+  for k := 0; k < prefix; k++ {
+    pairs = append(pairs, [2]int{k, k})
+  }
+
+  for _, m := range myers(x[prefix:len(x)-suffix], y[prefix:len(y)-suffix]) {
+    pairs = append(pairs, [2]int{prefix + m[0], prefix + m[1]})
+  }
+
+  for k := suffix; k > 0; k-- {
+    pairs = append(pairs, [2]int{len(x) - k, len(y) - k})
+  }
+
+  return pairs
+}
+
+// myers implements the greedy algorithm described by Eugene W. Myers in
+// "An O(ND) Difference Algorithm and Its Variations" and returns the
+// matching lines of the shortest edit script from x to y.
+func myers(x, y []string) [][2]int {
+  n, m := len(x), len(y)
+
+  if 0 == n || 0 == m {
+    return nil
+  }
+
+  var (
+    offset = n + m
+    v      = make([]int, 2*offset+2)
+    trace  = make([][]int, 0)
+    found  = false
+  )
+
+  for d := 0; d <= n+m && d <= maxEditDistance && !found; d++ {
+    // Keep only the diagonals reachable at this point so that the trace
+    // grows quadratically with the edit distance, not with the input.
+    snapshot := make([]int, 2*d+1)
+    copy(snapshot, v[offset-d:offset+d+1])
+    trace = append(trace, snapshot)
+
+    for k := -d; k <= d; k += 2 {
+      var i int
+
+      if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+        i = v[offset+k+1]
+      } else {
+        i = v[offset+k-1] + 1
+      }
+
+      j := i - k
+
+      for i < n && j < m && x[i] == y[j] {
+        i++
+        j++
+      }
+
+      v[offset+k] = i
+
+      if i >= n && j >= m {
+        found = true
+        break
+      }
+    }
+  }
+
+  if !found {
+    return nil // Too different: everything is replaced.
+  }
+
+  var (
+    pairs = make([][2]int, 0)
+    i, j  = n, m
+  )
+
+  for d := len(trace) - 1; d > 0; d-- {
+    var (
+      v     = trace[d]
+      k     = i - j
+      prevK int
+    )
+
+    if k == -d || (k != d && v[d+k-1] < v[d+k+1]) {
+      prevK = k + 1
+    } else {
+      prevK = k - 1
+    }
+
+    prevI := v[d+prevK]
+    prevJ := prevI - prevK
+
+    for i > prevI && j > prevJ {
+      i--
+      j--
+      pairs = append(pairs, [2]int{i, j})
+    }
+
+    i, j = prevI, prevJ
+  }
+
+  for i > 0 && j > 0 {
+    i--
+    j--
+    pairs = append(pairs, [2]int{i, j})
+  }
+
+  for l, r := 0, len(pairs)-1; l < r; l, r = l+1, r-1 {
+    pairs[l], pairs[r] = pairs[r], pairs[l]
+  }
+
+  return pairs
+}
+
+// Hunk is a group of nearby changed lines of a line-level diff,
+// surrounded by a few unchanged lines for context.
+type Hunk []Line
+
+// Hunks groups lines into hunks with up to context unchanged lines
+// before and after every change. Changes that are separated by at
+// most 2*context unchanged lines end up in the same hunk.
+func Hunks(lines []Line, context int) []Hunk {
+  var (
+    hunks = make([]Hunk, 0)
+    start = -1 // index in lines where the current hunk starts
+    end   = -1 // index in lines of the last change of the current hunk
+  )
+
+  for i, l := range lines {
+    if Equal == l.Operation {
+      continue
+    }
+
+    if -1 != start && i-end-1 > 2*context {
+      hunks = append(hunks, lines[start:min(len(lines), end+1+context)])
+      start = -1
+    }
+
+    if -1 == start {
+      start = max(0, i-context)
+    }
+
+    end = i
+  }
+
+  if -1 != start {
+    hunks = append(hunks, lines[start:min(len(lines), end+1+context)])
+  }
+
+  return hunks
+}
@@ -0,0 +1,189 @@
+package diff
+
+import (
+  "encoding/json"
+  "github.com/stretchr/testify/assert"
+  "github.com/stretchr/testify/require"
+  "strconv"
+  "strings"
+  "testing"
+)
+
+func TestLines(t *testing.T) {
+  t.Run("identical texts", func(t *testing.T) {
+    lines := Lines("a\nb\nc", "a\nb\nc")
+    require.Len(t, lines, 3)
+
+    for i, l := range lines {
+      assert.Equal(t, Equal, l.Operation)
+      assert.Equal(t, 1+i, l.Old)
+      assert.Equal(t, 1+i, l.New)
+    }
+  })
+
+  t.Run("empty texts", func(t *testing.T) {
+    assert.Empty(t, Lines("", ""))
+  })
+
+  t.Run("from empty text", func(t *testing.T) {
+    expected := []Line{
+      {Operation: Insert, Text: "a", New: 1},
+      {Operation: Insert, Text: "b", New: 2},
+    }
+
+    assert.Equal(t, expected, Lines("", "a\nb"))
+  })
+
+  t.Run("to empty text", func(t *testing.T) {
+    expected := []Line{
+      {Operation: Delete, Text: "a", Old: 1},
+      {Operation: Delete, Text: "b", Old: 2},
+    }
+
+    assert.Equal(t, expected, Lines("a\nb", ""))
+  })
+
+  t.Run("insertions, deletions and replacements", func(t *testing.T) {
+    expected := []Line{
+      {Operation: Equal, Text: "a", Old: 1, New: 1},
+      {Operation: Delete, Text: "b", Old: 2},
+      {Operation: Equal, Text: "c", Old: 3, New: 2},
+      {Operation: Delete, Text: "d", Old: 4},
+      {Operation: Insert, Text: "x", New: 3},
+      {Operation: Equal, Text: "e", Old: 5, New: 4},
+      {Operation: Insert, Text: "f", New: 5},
+    }
+
+    assert.Equal(t, expected, Lines("a\nb\nc\nd\ne", "a\nc\nx\ne\nf"))
+  })
+
+  t.Run("completely different texts", func(t *testing.T) {
+    lines := Lines("a\nb", "c\nd")
+    require.Len(t, lines, 4)
+
+    for _, l := range lines {
+      assert.NotEqual(t, Equal, l.Operation)
+    }
+  })
+
+  t.Run("operations are encoded with their names", func(t *testing.T) {
+    data, err := json.Marshal(Lines("a", "b"))
+    require.NoError(t, err)
+    assert.Contains(t, string(data), `"operation":"delete"`)
+    assert.Contains(t, string(data), `"operation":"insert"`)
+  })
+
+  t.Run("lines are applicable", func(t *testing.T) {
+    var a, b strings.Builder
+
+    for i := 0; i < 500; i++ {
+      a.WriteString(strconv.Itoa(i%7) + "\n")
+      b.WriteString(strconv.Itoa(i%5) + "\n")
+    }
+
+    var older, newer []string
+
+    for _, l := range Lines(a.String(), b.String()) {
+      if Insert != l.Operation {
+        older = append(older, l.Text)
+      }
+
+      if Delete != l.Operation {
+        newer = append(newer, l.Text)
+      }
+    }
+
+    assert.Equal(t, a.String(), strings.Join(older, "\n"))
+    assert.Equal(t, b.String(), strings.Join(newer, "\n"))
+  })
+}
+
+func TestMerge(t *testing.T) {
+  const base = "one\ntwo\nthree\nfour\nfive"
+
+  t.Run("no changes", func(t *testing.T) {
+    merged, conflicts := Merge(base, base, base)
+    assert.Equal(t, base, merged)
+    assert.Empty(t, conflicts)
+  })
+
+  t.Run("only ours changed", func(t *testing.T) {
+    ours := "one\n2\nthree\nfour\nfive\nsix"
+    merged, conflicts := Merge(base, ours, base)
+    assert.Equal(t, ours, merged)
+    assert.Empty(t, conflicts)
+  })
+
+  t.Run("only theirs changed", func(t *testing.T) {
+    theirs := "zero\none\ntwo\nfour\nfive"
+    merged, conflicts := Merge(base, base, theirs)
+    assert.Equal(t, theirs, merged)
+    assert.Empty(t, conflicts)
+  })
+
+  t.Run("both changed different regions", func(t *testing.T) {
+    ours := "one\n2\nthree\nfour\nfive"
+    theirs := "one\ntwo\nthree\nfour\n5"
+    merged, conflicts := Merge(base, ours, theirs)
+    assert.Equal(t, "one\n2\nthree\nfour\n5", merged)
+    assert.Empty(t, conflicts)
+  })
+
+  t.Run("both made the same change", func(t *testing.T) {
+    ours := "one\ntwo\n3\nfour\nfive"
+    merged, conflicts := Merge(base, ours, ours)
+    assert.Equal(t, ours, merged)
+    assert.Empty(t, conflicts)
+  })
+
+  t.Run("both changed the same region", func(t *testing.T) {
+    ours := "one\ntwo\nTHREE\nfour\nfive"
+    theirs := "one\ntwo\n3\nfour\nfive"
+    merged, conflicts := Merge(base, ours, theirs)
+    require.Len(t, conflicts, 1)
+    assert.Equal(t, &Conflict{
+      Line:   3,
+      Base:   []string{"three"},
+      Ours:   []string{"THREE"},
+      Theirs: []string{"3"},
+    }, conflicts[0])
+    assert.Contains(t, merged, "<<<<<<< ours\nTHREE\n=======\n3\n>>>>>>> theirs")
+  })
+
+  t.Run("both appended different lines", func(t *testing.T) {
+    merged, conflicts := Merge(base, base+"\nsix", base+"\nseis")
+    require.Len(t, conflicts, 1)
+    assert.Equal(t, 6, conflicts[0].Line)
+    assert.Empty(t, conflicts[0].Base)
+    assert.Contains(t, merged, "six")
+    assert.Contains(t, merged, "seis")
+  })
+
+  t.Run("from empty base", func(t *testing.T) {
+    merged, conflicts := Merge("", "", "new")
+    assert.Equal(t, "new", merged)
+    assert.Empty(t, conflicts)
+  })
+}
+
+func TestHunks(t *testing.T) {
+  t.Run("no changes", func(t *testing.T) {
+    assert.Empty(t, Hunks(Lines("a\nb", "a\nb"), 3))
+  })
+
+  t.Run("changes far apart", func(t *testing.T) {
+    hunks := Hunks(Lines("1\n2\n3\n4\n5\n6\n7\n8\n9", "x\n2\n3\n4\n5\n6\n7\n8\ny"), 1)
+    require.Len(t, hunks, 2)
+    assert.Equal(t, "1", hunks[0][0].Text)
+    assert.Equal(t, "2", hunks[0][len(hunks[0])-1].Text)
+    assert.Equal(t, "8", hunks[1][0].Text)
+    assert.Equal(t, "y", hunks[1][len(hunks[1])-1].Text)
+  })
+
+  t.Run("changes close together", func(t *testing.T) {
+    hunks := Hunks(Lines("1\n2\n3\n4\n5\n6", "1\nx\n3\n4\ny\n6"), 1)
+    require.Len(t, hunks, 1)
+    assert.Equal(t, "1", hunks[0][0].Text)
+    assert.Equal(t, "6", hunks[0][len(hunks[0])-1].Text)
+  })
+}
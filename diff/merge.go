@@ -0,0 +1,110 @@
+package diff
+
+import (
+  "slices"
+  "strings"
+)
+
+// Conflict is a region of a three-way merge where both sides changed
+// the same lines of their common ancestor in different ways.
+type Conflict struct {
+  Line   int      `json:"line"` // 1-based line number in base where the region starts
+  Base   []string `json:"base"`
+  Ours   []string `json:"ours"`
+  Theirs []string `json:"theirs"`
+}
+
+// Merge performs a line-level three-way merge of ours and theirs, two
+// texts that were derived from the common ancestor base.
+//
+// Every change made by only one side is kept. When both sides changed
+// the same region, that region is reported as a conflict and written
+// to merged surrounded by conflict markers, so merged should only be
+// used when no conflicts were found.
+func Merge(base, ours, theirs string) (merged string, conflicts []*Conflict) {
+  var (
+    o       = split(base)
+    a       = split(ours)
+    b       = split(theirs)
+    matchA  = positions(len(o), matches(o, a))
+    matchB  = positions(len(o), matches(o, b))
+    result  = make([]string, 0, max(len(a), len(b)))
+    i, j, k = 0, 0, 0
+  )
+
+  conflicts = make([]*Conflict, 0)
+
+  for {
+    // Copy the lines that remain untouched on both sides.
+    for i < len(o) && matchA[i] == j && matchB[i] == k {
+      result = append(result, o[i])
+      i++
+      j++
+      k++
+    }
+
+    if i == len(o) && j == len(a) && k == len(b) {
+      break
+    }
+
+    // Find the next line of base that both sides kept, which is where
+    // the current unstable region ends.
+    next := i
+
+    for next < len(o) && (-1 == matchA[next] || -1 == matchB[next]) {
+      next++
+    }
+
+    nextA, nextB := len(a), len(b)
+
+    if next < len(o) {
+      nextA, nextB = matchA[next], matchB[next]
+    }
+
+    var (
+      chunkO = o[i:next]
+      chunkA = a[j:nextA]
+      chunkB = b[k:nextB]
+    )
+
+    switch {
+    case slices.Equal(chunkA, chunkO):
+      result = append(result, chunkB...)
+    case slices.Equal(chunkB, chunkO), slices.Equal(chunkA, chunkB):
+      result = append(result, chunkA...)
+    default:
+      conflicts = append(conflicts, &Conflict{
+        Line:   1 + i,
+        Base:   slices.Clone(chunkO),
+        Ours:   slices.Clone(chunkA),
+        Theirs: slices.Clone(chunkB),
+      })
+
+      result = append(result, "<<<<<<< ours")
+      result = append(result, chunkA...)
+      result = append(result, "=======")
+      result = append(result, chunkB...)
+      result = append(result, ">>>>>>> theirs")
+    }
+
+    i, j, k = next, nextA, nextB
+  }
+
+  return strings.Join(result, "\n"), conflicts
+}
+
+// positions maps every line of a text of length n to the line it was
+// matched with in another text, or to -1 when it was not matched.
+func positions(n int, pairs [][2]int) []int {
+  p := make([]int, n)
+
+  for i := range p {
+    p[i] = -1
+  }
+
+  for _, m := range pairs {
+    p[m[0]] = m[1]
+  }
+
+  return p
+}
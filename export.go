@@ -0,0 +1,64 @@
+package main
+
+import (
+  "context"
+  "fmt"
+  "fontseca.dev/config"
+  "fontseca.dev/database"
+  "fontseca.dev/repository"
+  "fontseca.dev/service"
+  "os"
+  "time"
+)
+
+// exportSite writes the archive of the site to the file given in args.
+func exportSite(_ *config.Config, db *database.DB, args []string) error {
+  var name = fmt.Sprintf("fontseca.dev-%s.tar.gz", time.Now().Format("20060102"))
+  if 0 < len(args) {
+    name = args[0]
+  }
+
+  file, err := os.Create(name)
+  if nil != err {
+    return err
+  }
+
+  var site = service.NewSiteService(repository.NewSiteRepository(db))
+
+  if err = site.Export(context.Background(), file); nil != err {
+    file.Close()
+    os.Remove(name)
+    return fmt.Errorf("exporting site: %w", err)
+  }
+
+  if err = file.Close(); nil != err {
+    return err
+  }
+
+  fmt.Fprintf(os.Stdout, "exported site to %s\n", name)
+  return nil
+}
+
+// restoreSite replaces the content of the site with the archive given
+// in args.
+func restoreSite(_ *config.Config, db *database.DB, args []string) error {
+  if 1 != len(args) {
+    return errUsage
+  }
+
+  file, err := os.Open(args[0])
+  if nil != err {
+    return err
+  }
+
+  defer file.Close()
+
+  var site = service.NewSiteService(repository.NewSiteRepository(db))
+
+  if err = site.Restore(context.Background(), file); nil != err {
+    return fmt.Errorf("restoring %s: %w", args[0], err)
+  }
+
+  fmt.Fprintf(os.Stdout, "restored site from %s\n", args[0])
+  return nil
+}
@@ -0,0 +1,785 @@
+package handler
+
+import (
+  "context"
+  "crypto/subtle"
+  "errors"
+  "fmt"
+  "fontseca.dev/components/admin"
+  "fontseca.dev/model"
+  "fontseca.dev/problem"
+  "fontseca.dev/service"
+  "fontseca.dev/transfer"
+  "github.com/gin-gonic/gin"
+  "net/http"
+  "net/url"
+  "slices"
+  "strings"
+  "time"
+)
+
+// adminSessionAge is how long a session of the administration site
+// lasts before the token must be given again.
+const adminSessionAge = 12 * time.Hour
+
+// AdminHandler renders the administration site, where the content of
+// the site is managed through the same services as the API.
+type AdminHandler struct {
+  token        string
+  me           service.MeService
+  drafts       service.DraftsService
+  articles     service.ArticlesService
+  patches      service.PatchesService
+  projects     service.ProjectsService
+  experience   service.ExperienceService
+  topics       service.TopicsService
+  tags         service.TagsService
+  technologies service.TechnologyTagService
+}
+
+func NewAdminHandler(
+  token string,
+  me service.MeService,
+  drafts service.DraftsService,
+  articles service.ArticlesService,
+  patches service.PatchesService,
+  projects service.ProjectsService,
+  experience service.ExperienceService,
+  topics service.TopicsService,
+  tags service.TagsService,
+  technologies service.TechnologyTagService,
+) *AdminHandler {
+  return &AdminHandler{
+    token:        token,
+    me:           me,
+    drafts:       drafts,
+    articles:     articles,
+    patches:      patches,
+    projects:     projects,
+    experience:   experience,
+    topics:       topics,
+    tags:         tags,
+    technologies: technologies,
+  }
+}
+
+// isHTMX reports whether the request was made by HTMX.
+func isHTMX(c *gin.Context) bool {
+  return "true" == c.GetHeader("HX-Request")
+}
+
+// done answers a successful change by sending the browser to location.
+func (h *AdminHandler) done(c *gin.Context, location string) {
+  if isHTMX(c) {
+    c.Header("HX-Redirect", location)
+    c.Status(http.StatusNoContent)
+    return
+  }
+
+  c.Redirect(http.StatusSeeOther, location)
+}
+
+// fail shows err as a problem detail. HTMX requests get it inline, in
+// place of the problem placeholder of the page; any other request gets
+// a page of its own.
+func (h *AdminHandler) fail(c *gin.Context, err error) {
+  var p *problem.Problem
+  if !errors.As(err, &p) {
+    p = problem.NewInternal()
+  }
+
+  var status, title, detail = p.Describe()
+  var members = describeMembers(p.Extensions())
+
+  c.Status(status)
+
+  if isHTMX(c) {
+    c.Header("HX-Retarget", "#problem")
+    c.Header("HX-Reswap", "innerHTML")
+    admin.Problem(status, title, detail, members).Render(c, c.Writer)
+    return
+  }
+
+  admin.Failure(status, title, detail, members).Render(c, c.Writer)
+}
+
+// describeMembers turns the extension members of a problem into
+// sentences to show along with it.
+func describeMembers(members map[string]any) []string {
+  var described = make([]string, 0, len(members))
+
+  for key, value := range members {
+    if failures, ok := value.([]any); ok && "errors" == key {
+      for _, f := range failures {
+        if f, ok := f.(failure); ok {
+          var criterion = f.Criterion
+          if "" != f.Parameter {
+            criterion += "=" + f.Parameter
+          }
+          described = append(described, fmt.Sprintf("%s doesn't meet the '%s' criterion", f.Field, criterion))
+        }
+      }
+      continue
+    }
+
+    described = append(described, fmt.Sprintf("%s: %v", key, value))
+  }
+
+  slices.Sort(described)
+
+  return described
+}
+
+// bind reads the form of the request into form and validates it. Blank
+// fields are left out, so they keep their current value on updates.
+func (h *AdminHandler) bind(c *gin.Context, form any) bool {
+  if err := c.Request.ParseForm(); nil != err {
+    h.fail(c, err)
+    return false
+  }
+
+  for field, values := range c.Request.PostForm {
+    if "" == strings.TrimSpace(strings.Join(values, "")) {
+      delete(c.Request.PostForm, field)
+    }
+  }
+
+  if err := bindPostForm(c, form); nil != err {
+    h.fail(c, err)
+    return false
+  }
+
+  if err := validateStruct(form); nil != err {
+    h.fail(c, err)
+    return false
+  }
+
+  return true
+}
+
+// change applies a change to the content of the site and, if it
+// succeeds, sends the browser to location.
+func (h *AdminHandler) change(c *gin.Context, location string, apply func() error) {
+  if err := apply(); nil != err {
+    h.fail(c, err)
+    return
+  }
+
+  h.done(c, location)
+}
+
+// save applies a revision from an editor. Editors save as they are
+// typed in, so an HTMX request stays on its page and only learns the
+// version to send along with its next revision; any other request is
+// sent to location.
+func (h *AdminHandler) save(c *gin.Context, location string, revision *transfer.ArticleRevision, revise func() error) {
+  if err := revise(); nil != err {
+    h.fail(c, err)
+    return
+  }
+
+  if !isHTMX(c) {
+    c.Redirect(http.StatusSeeOther, location)
+    return
+  }
+
+  admin.Saved(revision.Version+1, time.Now()).Render(c, c.Writer)
+}
+
+// adminPageSize is the number of articles of a page of the lists of
+// the administration site.
+const adminPageSize = 50
+
+// adminPage retrieves with get the page of a list of articles of the
+// administration site that follows the article the 'after' cursor of
+// c points at, or the first page if the list is not the one named by
+// the 'hidden' parameter of c. It returns the URL that loads the next
+// page, if any.
+func adminPage(c *gin.Context, hidden bool, get func(context.Context, *transfer.ArticleFilter) ([]*transfer.Article, error)) (articles []*transfer.Article, next string, err error) {
+  var filter = &transfer.ArticleFilter{
+    Page: 1,
+    RPP:  1 + adminPageSize, // the extra article tells whether there is another page
+  }
+
+  if value := c.Query("after"); "" != value && hidden == ("true" == c.Query("hidden")) {
+    if filter.After, err = transfer.ParseCursor(value); nil != err {
+      return nil, "", problem.NewInvalidCursor(value)
+    }
+  }
+
+  if articles, err = get(c, filter); nil != err {
+    return nil, "", err
+  }
+
+  if adminPageSize < len(articles) {
+    articles = articles[:adminPageSize]
+
+    var query = url.Values{"after": {articles[len(articles)-1].Cursor().String()}}
+    if hidden {
+      query.Set("hidden", "true")
+    }
+
+    next = c.Request.URL.Path + "?" + query.Encode()
+  }
+
+  return articles, next, nil
+}
+
+func (h *AdminHandler) Login(c *gin.Context) {
+  admin.Login().Render(c, c.Writer)
+}
+
+func (h *AdminHandler) Authenticate(c *gin.Context) {
+  var given = strings.TrimSpace(c.PostForm("token"))
+
+  // The session is signed with the administration token, so without
+  // one nobody logs in, not even with my password.
+  if "" == h.token {
+    h.fail(c, problem.NewUnauthorized())
+    return
+  }
+
+  if 1 != subtle.ConstantTimeCompare([]byte(given), []byte(h.token)) {
+    ok, err := h.me.CheckPassword(c, given)
+    if nil != err {
+      h.fail(c, err)
+      return
+    }
+
+    if !ok {
+      h.fail(c, problem.NewUnauthorized())
+      return
+    }
+  }
+
+  var expires = time.Now().Add(adminSessionAge)
+
+  c.SetSameSite(http.SameSiteStrictMode)
+  c.SetCookie(adminSessionCookie, adminSession(h.token, expires), int(adminSessionAge.Seconds()), "/admin", "", !gin.IsDebugging(), true)
+
+  h.done(c, "/admin")
+}
+
+func (h *AdminHandler) Logout(c *gin.Context) {
+  c.SetSameSite(http.SameSiteStrictMode)
+  c.SetCookie(adminSessionCookie, "", -1, "/admin", "", !gin.IsDebugging(), true)
+
+  h.done(c, "/admin/login")
+}
+
+func (h *AdminHandler) Dashboard(c *gin.Context) {
+  var (
+    totals = make(map[string]int)
+    filter = new(transfer.ArticleFilter)
+    err    error
+  )
+
+  if totals["drafts"], err = h.drafts.Count(c, filter); nil != err {
+    h.fail(c, err)
+    return
+  }
+
+  published, err := h.articles.Count(c, filter, false)
+  if nil != err {
+    h.fail(c, err)
+    return
+  }
+
+  hidden, err := h.articles.Count(c, filter, true)
+  if nil != err {
+    h.fail(c, err)
+    return
+  }
+
+  totals["articles"] = published + hidden
+
+  patches, err := h.patches.Get(c)
+  if nil != err {
+    h.fail(c, err)
+    return
+  }
+
+  totals["patches"] = len(patches)
+
+  current, err := h.projects.Get(c)
+  if nil != err {
+    h.fail(c, err)
+    return
+  }
+
+  archived, err := h.projects.Get(c, true)
+  if nil != err {
+    h.fail(c, err)
+    return
+  }
+
+  totals["projects"] = len(current) + len(archived)
+
+  experience, err := h.getExperience(c)
+  if nil != err {
+    h.fail(c, err)
+    return
+  }
+
+  totals["experience"] = len(experience)
+
+  topics, err := h.topics.Get(c)
+  if nil != err {
+    h.fail(c, err)
+    return
+  }
+
+  totals["topics"] = len(topics)
+
+  tags, err := h.tags.Get(c)
+  if nil != err {
+    h.fail(c, err)
+    return
+  }
+
+  totals["tags"] = len(tags)
+
+  technologies, err := h.technologies.Get(c)
+  if nil != err {
+    h.fail(c, err)
+    return
+  }
+
+  totals["technologies"] = len(technologies)
+
+  admin.Dashboard(totals).Render(c, c.Writer)
+}
+
+func (h *AdminHandler) Drafts(c *gin.Context) {
+  var drafts, next, err = adminPage(c, false, h.drafts.Get)
+  if nil != err {
+    h.fail(c, err)
+    return
+  }
+
+  // The next pages are loaded into the table of the first one.
+  if isHTMX(c) && "" != c.Query("after") {
+    admin.DraftRows(drafts, next).Render(c, c.Writer)
+    return
+  }
+
+  admin.Drafts(drafts, next).Render(c, c.Writer)
+}
+
+func (h *AdminHandler) Draft(c *gin.Context) {
+  var draft, err = h.drafts.GetByID(c, c.Param("id"))
+  if nil != err {
+    h.fail(c, err)
+    return
+  }
+
+  topics, err := h.topics.Get(c)
+  if nil != err {
+    h.fail(c, err)
+    return
+  }
+
+  admin.Draft(draft, topics).Render(c, c.Writer)
+}
+
+func (h *AdminHandler) StartDraft(c *gin.Context) {
+  var creation transfer.ArticleCreation
+  if !h.bind(c, &creation) {
+    return
+  }
+
+  var id, err = h.drafts.Draft(c, &creation)
+  if nil != err {
+    h.fail(c, err)
+    return
+  }
+
+  h.done(c, "/admin/drafts/"+id.String())
+}
+
+func (h *AdminHandler) ReviseDraft(c *gin.Context) {
+  var id = c.Param("id")
+  var revision transfer.ArticleRevision
+  if !h.bind(c, &revision) {
+    return
+  }
+
+  h.save(c, "/admin/drafts/"+id, &revision, func() error { return h.drafts.Revise(c, id, &revision) })
+}
+
+func (h *AdminHandler) PublishDraft(c *gin.Context) {
+  h.change(c, "/admin/articles", func() error { return h.drafts.Publish(c, c.Param("id")) })
+}
+
+func (h *AdminHandler) DiscardDraft(c *gin.Context) {
+  h.change(c, "/admin/drafts", func() error { return h.drafts.Discard(c, c.Param("id")) })
+}
+
+func (h *AdminHandler) Preview(c *gin.Context) {
+  var title, content = c.PostForm("title"), c.PostForm("content")
+  var words, readTime = h.drafts.Measure(title, content)
+  admin.Preview(content, words, readTime).Render(c, c.Writer)
+}
+
+func (h *AdminHandler) Patches(c *gin.Context) {
+  var patches, err = h.patches.Get(c)
+  if nil != err {
+    h.fail(c, err)
+    return
+  }
+
+  admin.Patches(patches).Render(c, c.Writer)
+}
+
+func (h *AdminHandler) Patch(c *gin.Context) {
+  var id = c.Param("id")
+
+  patches, err := h.patches.Get(c)
+  if nil != err {
+    h.fail(c, err)
+    return
+  }
+
+  var i = slices.IndexFunc(patches, func(p *model.ArticlePatch) bool { return p.UUID.String() == id })
+  if -1 == i {
+    h.fail(c, problem.NewNotFound(id, "article patch"))
+    return
+  }
+
+  article, err := h.articles.GetByID(c, patches[i].ArticleUUID.String())
+  if nil != err {
+    h.fail(c, err)
+    return
+  }
+
+  topics, err := h.topics.Get(c)
+  if nil != err {
+    h.fail(c, err)
+    return
+  }
+
+  admin.Patch(patches[i], article, topics).Render(c, c.Writer)
+}
+
+func (h *AdminHandler) RevisePatch(c *gin.Context) {
+  var id = c.Param("id")
+  var revision transfer.ArticleRevision
+  if !h.bind(c, &revision) {
+    return
+  }
+
+  h.save(c, "/admin/patches/"+id, &revision, func() error { return h.patches.Revise(c, id, &revision) })
+}
+
+func (h *AdminHandler) ReleasePatch(c *gin.Context) {
+  h.change(c, "/admin/articles", func() error { return h.patches.Release(c, c.Param("id")) })
+}
+
+func (h *AdminHandler) DiscardPatch(c *gin.Context) {
+  h.change(c, "/admin/patches", func() error { return h.patches.Discard(c, c.Param("id")) })
+}
+
+func (h *AdminHandler) Articles(c *gin.Context) {
+  // The next pages of either list are loaded into its table.
+  if isHTMX(c) && "" != c.Query("after") {
+    var hidden, get = "true" == c.Query("hidden"), h.articles.Get
+    if hidden {
+      get = h.articles.GetHidden
+    }
+
+    articles, next, err := adminPage(c, hidden, get)
+    if nil != err {
+      h.fail(c, err)
+      return
+    }
+
+    admin.ArticleRows(articles, hidden, next).Render(c, c.Writer)
+    return
+  }
+
+  articles, next, err := adminPage(c, false, h.articles.Get)
+  if nil != err {
+    h.fail(c, err)
+    return
+  }
+
+  hidden, hiddenNext, err := adminPage(c, true, h.articles.GetHidden)
+  if nil != err {
+    h.fail(c, err)
+    return
+  }
+
+  admin.Articles(articles, next, hidden, hiddenNext).Render(c, c.Writer)
+}
+
+func (h *AdminHandler) AmendArticle(c *gin.Context) {
+  var patch, err = h.articles.Amend(c, c.Param("id"))
+  if nil != err {
+    h.fail(c, err)
+    return
+  }
+
+  h.done(c, "/admin/patches/"+patch.String())
+}
+
+func (h *AdminHandler) HideArticle(c *gin.Context) {
+  h.change(c, "/admin/articles", func() error { return h.articles.Hide(c, c.Param("id")) })
+}
+
+func (h *AdminHandler) ShowArticle(c *gin.Context) {
+  h.change(c, "/admin/articles", func() error { return h.articles.Show(c, c.Param("id")) })
+}
+
+func (h *AdminHandler) PinArticle(c *gin.Context) {
+  h.change(c, "/admin/articles", func() error { return h.articles.Pin(c, c.Param("id")) })
+}
+
+func (h *AdminHandler) UnpinArticle(c *gin.Context) {
+  h.change(c, "/admin/articles", func() error { return h.articles.Unpin(c, c.Param("id")) })
+}
+
+func (h *AdminHandler) RemoveArticle(c *gin.Context) {
+  h.change(c, "/admin/articles", func() error { return h.articles.Remove(c, c.Param("id")) })
+}
+
+func (h *AdminHandler) Projects(c *gin.Context) {
+  var projects, err = h.projects.Get(c)
+  if nil != err {
+    h.fail(c, err)
+    return
+  }
+
+  archived, err := h.projects.Get(c, true)
+  if nil != err {
+    h.fail(c, err)
+    return
+  }
+
+  admin.Projects(projects, archived).Render(c, c.Writer)
+}
+
+func (h *AdminHandler) Project(c *gin.Context) {
+  var project, err = h.projects.GetByID(c, c.Param("id"))
+  if nil != err {
+    h.fail(c, err)
+    return
+  }
+
+  admin.Project(project).Render(c, c.Writer)
+}
+
+func (h *AdminHandler) AddProject(c *gin.Context) {
+  var creation transfer.ProjectCreation
+  if !h.bind(c, &creation) {
+    return
+  }
+
+  var id, err = h.projects.Add(c, &creation)
+  if nil != err {
+    h.fail(c, err)
+    return
+  }
+
+  h.done(c, "/admin/projects/"+id)
+}
+
+func (h *AdminHandler) UpdateProject(c *gin.Context) {
+  var id = c.Param("id")
+  var update transfer.ProjectUpdate
+  if !h.bind(c, &update) {
+    return
+  }
+
+  h.change(c, "/admin/projects/"+id, func() error {
+    // The form doesn't edit these flags, so they keep their value.
+    var project, err = h.projects.GetByID(c, id)
+    if nil != err {
+      return err
+    }
+
+    update.Archived = project.Archived
+    update.Finished = project.Finished
+
+    _, err = h.projects.Update(c, id, &update)
+    return err
+  })
+}
+
+func (h *AdminHandler) RemoveProject(c *gin.Context) {
+  h.change(c, "/admin/projects", func() error { return h.projects.Remove(c, c.Param("id")) })
+}
+
+// getExperience retrieves every experience record, hidden or not.
+func (h *AdminHandler) getExperience(c *gin.Context) (experience []*model.Experience, err error) {
+  experience, err = h.experience.Get(c)
+  if nil != err {
+    return nil, err
+  }
+
+  hidden, err := h.experience.Get(c, true)
+  if nil != err {
+    return nil, err
+  }
+
+  return append(experience, hidden...), nil
+}
+
+func (h *AdminHandler) Experience(c *gin.Context) {
+  var experience, err = h.getExperience(c)
+  if nil != err {
+    h.fail(c, err)
+    return
+  }
+
+  admin.Experience(experience).Render(c, c.Writer)
+}
+
+func (h *AdminHandler) ExperienceEntry(c *gin.Context) {
+  var experience, err = h.experience.GetByID(c, c.Param("id"))
+  if nil != err {
+    h.fail(c, err)
+    return
+  }
+
+  admin.ExperienceEntry(experience).Render(c, c.Writer)
+}
+
+func (h *AdminHandler) AddExperience(c *gin.Context) {
+  var creation transfer.ExperienceCreation
+  if !h.bind(c, &creation) {
+    return
+  }
+
+  h.change(c, "/admin/experience", func() error {
+    var _, err = h.experience.Save(c, &creation)
+    return err
+  })
+}
+
+func (h *AdminHandler) UpdateExperience(c *gin.Context) {
+  var id = c.Param("id")
+  var update transfer.ExperienceUpdate
+  if !h.bind(c, &update) {
+    return
+  }
+
+  h.change(c, "/admin/experience/"+id, func() error {
+    // The form doesn't edit these flags, so they keep their value.
+    var experience, err = h.experience.GetByID(c, id)
+    if nil != err {
+      return err
+    }
+
+    update.Active = experience.Active
+    update.Hidden = experience.Hidden
+
+    _, err = h.experience.Update(c, id, &update)
+    return err
+  })
+}
+
+func (h *AdminHandler) RemoveExperience(c *gin.Context) {
+  h.change(c, "/admin/experience", func() error { return h.experience.Remove(c, c.Param("id")) })
+}
+
+func (h *AdminHandler) Topics(c *gin.Context) {
+  var topics, err = h.topics.Get(c)
+  if nil != err {
+    h.fail(c, err)
+    return
+  }
+
+  admin.Topics(topics).Render(c, c.Writer)
+}
+
+func (h *AdminHandler) AddTopic(c *gin.Context) {
+  var creation transfer.TopicCreation
+  if !h.bind(c, &creation) {
+    return
+  }
+
+  h.change(c, "/admin/topics", func() error { return h.topics.Add(c, &creation) })
+}
+
+func (h *AdminHandler) UpdateTopic(c *gin.Context) {
+  var update transfer.TopicUpdate
+  if !h.bind(c, &update) {
+    return
+  }
+
+  h.change(c, "/admin/topics", func() error { return h.topics.Update(c, c.Param("id"), &update) })
+}
+
+func (h *AdminHandler) RemoveTopic(c *gin.Context) {
+  h.change(c, "/admin/topics", func() error { return h.topics.Remove(c, c.Param("id")) })
+}
+
+func (h *AdminHandler) Tags(c *gin.Context) {
+  var tags, err = h.tags.Get(c)
+  if nil != err {
+    h.fail(c, err)
+    return
+  }
+
+  admin.Tags(tags).Render(c, c.Writer)
+}
+
+func (h *AdminHandler) AddTag(c *gin.Context) {
+  var creation transfer.TagCreation
+  if !h.bind(c, &creation) {
+    return
+  }
+
+  h.change(c, "/admin/tags", func() error { return h.tags.Add(c, &creation) })
+}
+
+func (h *AdminHandler) UpdateTag(c *gin.Context) {
+  var update transfer.TagUpdate
+  if !h.bind(c, &update) {
+    return
+  }
+
+  h.change(c, "/admin/tags", func() error { return h.tags.Update(c, c.Param("id"), &update) })
+}
+
+func (h *AdminHandler) RemoveTag(c *gin.Context) {
+  h.change(c, "/admin/tags", func() error { return h.tags.Remove(c, c.Param("id")) })
+}
+
+func (h *AdminHandler) Technologies(c *gin.Context) {
+  var technologies, err = h.technologies.Get(c)
+  if nil != err {
+    h.fail(c, err)
+    return
+  }
+
+  admin.Technologies(technologies).Render(c, c.Writer)
+}
+
+func (h *AdminHandler) AddTechnology(c *gin.Context) {
+  var creation transfer.TechnologyTagCreation
+  if !h.bind(c, &creation) {
+    return
+  }
+
+  h.change(c, "/admin/technologies", func() error {
+    var _, err = h.technologies.Add(c, &creation)
+    return err
+  })
+}
+
+func (h *AdminHandler) UpdateTechnology(c *gin.Context) {
+  var update transfer.TechnologyTagUpdate
+  if !h.bind(c, &update) {
+    return
+  }
+
+  h.change(c, "/admin/technologies", func() error {
+    var _, err = h.technologies.Update(c, c.Param("id"), &update)
+    return err
+  })
+}
+
+func (h *AdminHandler) RemoveTechnology(c *gin.Context) {
+  h.change(c, "/admin/technologies", func() error { return h.technologies.Remove(c, c.Param("id")) })
+}
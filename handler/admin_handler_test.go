@@ -0,0 +1,438 @@
+package handler
+
+import (
+  "context"
+  "errors"
+  "fontseca.dev/mocks"
+  "fontseca.dev/problem"
+  "fontseca.dev/transfer"
+  "github.com/gin-gonic/gin"
+  "github.com/google/uuid"
+  "github.com/stretchr/testify/assert"
+  "github.com/stretchr/testify/mock"
+  "github.com/stretchr/testify/require"
+  "net/http"
+  "net/http/httptest"
+  "strings"
+  "testing"
+  "time"
+)
+
+func newAdminHandler(token string, drafts *mocks.DraftsService, topics *mocks.TopicsService) *AdminHandler {
+  return NewAdminHandler(
+    token,
+    mocks.NewMeService(),
+    drafts,
+    mocks.NewArticlesService(),
+    mocks.NewPatchesService(),
+    mocks.NewProjectsService(),
+    mocks.NewExperienceService(),
+    topics,
+    mocks.NewTagsService(),
+    mocks.NewTechnologyTagService(),
+  )
+}
+
+func TestRequireAdminSession(t *testing.T) {
+  const token = "token"
+  const target = "/admin"
+
+  var engine = gin.Default()
+  engine.GET(target, RequireAdminSession(token, nil), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+  t.Run("valid session", func(t *testing.T) {
+    var request = httptest.NewRequest(http.MethodGet, target, nil)
+    request.AddCookie(&http.Cookie{Name: adminSessionCookie, Value: adminSession(token, time.Now().Add(time.Hour))})
+    var recorder = httptest.NewRecorder()
+    engine.ServeHTTP(recorder, request)
+    assert.Equal(t, http.StatusOK, recorder.Code)
+    assert.Equal(t, "no-store", recorder.Header().Get("Cache-Control"))
+  })
+
+  t.Run("bearer token", func(t *testing.T) {
+    var request = httptest.NewRequest(http.MethodGet, target, nil)
+    request.Header.Set("Authorization", "Bearer "+token)
+    var recorder = httptest.NewRecorder()
+    engine.ServeHTTP(recorder, request)
+    assert.Equal(t, http.StatusOK, recorder.Code)
+  })
+
+  t.Run("stored token", func(t *testing.T) {
+    var tokens = mocks.NewTokensService()
+    tokens.On("Check", mock.Anything, "stored").Return(true, nil)
+
+    var engine = gin.Default()
+    engine.GET(target, RequireAdminSession("", tokens), func(c *gin.Context) { c.Status(http.StatusOK) })
+    var request = httptest.NewRequest(http.MethodGet, target, nil)
+    request.Header.Set("Authorization", "Bearer stored")
+    var recorder = httptest.NewRecorder()
+    engine.ServeHTTP(recorder, request)
+    assert.Equal(t, http.StatusOK, recorder.Code)
+  })
+
+  var rejected = []struct {
+    name    string
+    session string
+  }{
+    {name: "no session", session: ""},
+    {name: "expired session", session: adminSession(token, time.Now().Add(-time.Minute))},
+    {name: "session signed with another token", session: adminSession("another", time.Now().Add(time.Hour))},
+    {name: "forged expiry", session: "99999999999." + adminSession(token, time.Now().Add(time.Hour))[11:]},
+  }
+
+  for _, r := range rejected {
+    t.Run(r.name, func(t *testing.T) {
+      var request = httptest.NewRequest(http.MethodGet, target, nil)
+      if "" != r.session {
+        request.AddCookie(&http.Cookie{Name: adminSessionCookie, Value: r.session})
+      }
+      var recorder = httptest.NewRecorder()
+      engine.ServeHTTP(recorder, request)
+      assert.Equal(t, http.StatusSeeOther, recorder.Code)
+      assert.Equal(t, "/admin/login", recorder.Header().Get("Location"))
+    })
+  }
+
+  t.Run("htmx request without session", func(t *testing.T) {
+    var request = httptest.NewRequest(http.MethodGet, target, nil)
+    request.Header.Set("HX-Request", "true")
+    var recorder = httptest.NewRecorder()
+    engine.ServeHTTP(recorder, request)
+    assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+    assert.Equal(t, "/admin/login", recorder.Header().Get("HX-Redirect"))
+  })
+
+  t.Run("empty token", func(t *testing.T) {
+    var engine = gin.Default()
+    engine.GET(target, RequireAdminSession("", nil), func(c *gin.Context) { c.Status(http.StatusOK) })
+    var request = httptest.NewRequest(http.MethodGet, target, nil)
+    request.AddCookie(&http.Cookie{Name: adminSessionCookie, Value: adminSession("", time.Now().Add(time.Hour))})
+    var recorder = httptest.NewRecorder()
+    engine.ServeHTTP(recorder, request)
+    assert.Equal(t, http.StatusSeeOther, recorder.Code)
+  })
+}
+
+func TestAdminHandler_Authenticate(t *testing.T) {
+  const token = "token"
+  const target = "/admin/login"
+
+  var me = mocks.NewMeService()
+  me.On("CheckPassword", mock.Anything, "correct horse battery").Return(true, nil)
+  me.On("CheckPassword", mock.Anything, mock.Anything).Return(false, nil)
+
+  var h = newAdminHandler(token, mocks.NewDraftsService(), mocks.NewTopicsService())
+  h.me = me
+
+  var engine = gin.Default()
+  engine.POST(target, h.Authenticate)
+
+  t.Run("success", func(t *testing.T) {
+    var request = httptest.NewRequest(http.MethodPost, target, nil)
+    _ = request.ParseForm()
+    request.PostForm.Add("token", token)
+    request.Header.Set("HX-Request", "true")
+    var recorder = httptest.NewRecorder()
+    engine.ServeHTTP(recorder, request)
+    assert.Equal(t, http.StatusNoContent, recorder.Code)
+    assert.Equal(t, "/admin", recorder.Header().Get("HX-Redirect"))
+    var cookies = recorder.Result().Cookies()
+    require.Len(t, cookies, 1)
+    assert.Equal(t, adminSessionCookie, cookies[0].Name)
+    assert.True(t, validAdminSession(token, cookies[0].Value))
+    assert.True(t, cookies[0].HttpOnly)
+    assert.Equal(t, http.SameSiteStrictMode, cookies[0].SameSite)
+  })
+
+  t.Run("wrong token", func(t *testing.T) {
+    var request = httptest.NewRequest(http.MethodPost, target, nil)
+    _ = request.ParseForm()
+    request.PostForm.Add("token", "wrong")
+    request.Header.Set("HX-Request", "true")
+    var recorder = httptest.NewRecorder()
+    engine.ServeHTTP(recorder, request)
+    assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+    assert.Equal(t, "#problem", recorder.Header().Get("HX-Retarget"))
+    assert.Empty(t, recorder.Result().Cookies())
+  })
+
+  t.Run("password", func(t *testing.T) {
+    var request = httptest.NewRequest(http.MethodPost, target, nil)
+    _ = request.ParseForm()
+    request.PostForm.Add("token", "correct horse battery")
+    request.Header.Set("HX-Request", "true")
+    var recorder = httptest.NewRecorder()
+    engine.ServeHTTP(recorder, request)
+    assert.Equal(t, http.StatusNoContent, recorder.Code)
+    var cookies = recorder.Result().Cookies()
+    require.Len(t, cookies, 1)
+    assert.True(t, validAdminSession(token, cookies[0].Value))
+  })
+
+  t.Run("password without token", func(t *testing.T) {
+    var engine = gin.Default()
+    var h = newAdminHandler("", mocks.NewDraftsService(), mocks.NewTopicsService())
+    h.me = me
+    engine.POST(target, h.Authenticate)
+    var request = httptest.NewRequest(http.MethodPost, target, nil)
+    _ = request.ParseForm()
+    request.PostForm.Add("token", "correct horse battery")
+    request.Header.Set("HX-Request", "true")
+    var recorder = httptest.NewRecorder()
+    engine.ServeHTTP(recorder, request)
+    assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+    assert.Empty(t, recorder.Result().Cookies())
+  })
+}
+
+func TestAdminHandler_AddTopic(t *testing.T) {
+  const routine = "Add"
+  const target = "/admin/topics"
+  var creation = &transfer.TopicCreation{Name: "Go"}
+
+  var newRequest = func(name string) *http.Request {
+    var request = httptest.NewRequest(http.MethodPost, target, nil)
+    _ = request.ParseForm()
+    request.PostForm.Add("name", name)
+    request.Header.Set("HX-Request", "true")
+    return request
+  }
+
+  t.Run("success", func(t *testing.T) {
+    var s = mocks.NewTopicsService()
+    s.On(routine, mock.AnythingOfType("*gin.Context"), creation).Return(nil)
+    var engine = gin.Default()
+    engine.POST(target, newAdminHandler("", mocks.NewDraftsService(), s).AddTopic)
+    var recorder = httptest.NewRecorder()
+    engine.ServeHTTP(recorder, newRequest(creation.Name))
+    assert.Equal(t, http.StatusNoContent, recorder.Code)
+    assert.Equal(t, target, recorder.Header().Get("HX-Redirect"))
+  })
+
+  t.Run("without htmx", func(t *testing.T) {
+    var s = mocks.NewTopicsService()
+    s.On(routine, mock.AnythingOfType("*gin.Context"), creation).Return(nil)
+    var engine = gin.Default()
+    engine.POST(target, newAdminHandler("", mocks.NewDraftsService(), s).AddTopic)
+    var request = newRequest(creation.Name)
+    request.Header.Del("HX-Request")
+    var recorder = httptest.NewRecorder()
+    engine.ServeHTTP(recorder, request)
+    assert.Equal(t, http.StatusSeeOther, recorder.Code)
+    assert.Equal(t, target, recorder.Header().Get("Location"))
+  })
+
+  t.Run("blank name", func(t *testing.T) {
+    var s = mocks.NewTopicsService()
+    var engine = gin.Default()
+    engine.POST(target, newAdminHandler("", mocks.NewDraftsService(), s).AddTopic)
+    var recorder = httptest.NewRecorder()
+    engine.ServeHTTP(recorder, newRequest("  "))
+    assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+    assert.Equal(t, "#problem", recorder.Header().Get("HX-Retarget"))
+    assert.Equal(t, "innerHTML", recorder.Header().Get("HX-Reswap"))
+    s.AssertNotCalled(t, routine)
+  })
+
+  t.Run("expected problem detail", func(t *testing.T) {
+    var expected = &problem.Problem{}
+    expected.Status(http.StatusConflict)
+    var s = mocks.NewTopicsService()
+    s.On(routine, mock.AnythingOfType("*gin.Context"), creation).Return(expected)
+    var engine = gin.Default()
+    engine.POST(target, newAdminHandler("", mocks.NewDraftsService(), s).AddTopic)
+    var recorder = httptest.NewRecorder()
+    engine.ServeHTTP(recorder, newRequest(creation.Name))
+    assert.Equal(t, http.StatusConflict, recorder.Code)
+    assert.Equal(t, "#problem", recorder.Header().Get("HX-Retarget"))
+  })
+
+  t.Run("unexpected error", func(t *testing.T) {
+    var s = mocks.NewTopicsService()
+    s.On(routine, mock.AnythingOfType("*gin.Context"), creation).Return(errors.New("unexpected error"))
+    var engine = gin.Default()
+    engine.POST(target, newAdminHandler("", mocks.NewDraftsService(), s).AddTopic)
+    var recorder = httptest.NewRecorder()
+    engine.ServeHTTP(recorder, newRequest(creation.Name))
+    assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+  })
+}
+
+func TestAdminHandler_Drafts(t *testing.T) {
+  const routine = "Get"
+  const target = "/admin/drafts"
+
+  var drafts = make([]*transfer.Article, 1+adminPageSize)
+  for i := range drafts {
+    drafts[i] = &transfer.Article{UUID: uuid.New(), Title: "Draft"}
+  }
+
+  var last = drafts[adminPageSize-1].Cursor()
+
+  t.Run("first page", func(t *testing.T) {
+    var s = mocks.NewDraftsService()
+    s.On(routine, mock.AnythingOfType("*gin.Context"), &transfer.ArticleFilter{Page: 1, RPP: 1 + adminPageSize}).Return(drafts, nil)
+    var engine = gin.Default()
+    engine.GET(target, newAdminHandler("", s, mocks.NewTopicsService()).Drafts)
+    var recorder = httptest.NewRecorder()
+    engine.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, target, nil))
+    assert.Equal(t, http.StatusOK, recorder.Code)
+    s.AssertExpectations(t)
+  })
+
+  t.Run("next page", func(t *testing.T) {
+    var s = mocks.NewDraftsService()
+    s.On(routine, mock.AnythingOfType("*gin.Context"), &transfer.ArticleFilter{Page: 1, RPP: 1 + adminPageSize, After: last}).Return(drafts[:1], nil)
+    var engine = gin.Default()
+    engine.GET(target, newAdminHandler("", s, mocks.NewTopicsService()).Drafts)
+    var request = httptest.NewRequest(http.MethodGet, target+"?after="+last.String(), nil)
+    request.Header.Set("HX-Request", "true")
+    var recorder = httptest.NewRecorder()
+    engine.ServeHTTP(recorder, request)
+    assert.Equal(t, http.StatusOK, recorder.Code)
+    s.AssertExpectations(t)
+  })
+
+  t.Run("invalid cursor", func(t *testing.T) {
+    var s = mocks.NewDraftsService()
+    var engine = gin.Default()
+    engine.GET(target, newAdminHandler("", s, mocks.NewTopicsService()).Drafts)
+    var request = httptest.NewRequest(http.MethodGet, target+"?after=~", nil)
+    request.Header.Set("HX-Request", "true")
+    var recorder = httptest.NewRecorder()
+    engine.ServeHTTP(recorder, request)
+    assert.Equal(t, http.StatusBadRequest, recorder.Code)
+    s.AssertNotCalled(t, routine)
+  })
+}
+
+func TestAdminPage(t *testing.T) {
+  var articles = make([]*transfer.Article, 1+adminPageSize)
+  for i := range articles {
+    var published = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).Add(-time.Duration(i) * time.Hour)
+    articles[i] = &transfer.Article{UUID: uuid.New(), PublishedAt: &published}
+  }
+
+  var newContext = func(target string) *gin.Context {
+    var c, _ = gin.CreateTestContext(httptest.NewRecorder())
+    c.Request = httptest.NewRequest(http.MethodGet, target, nil)
+    return c
+  }
+
+  var get = func(page []*transfer.Article, filters *[]*transfer.ArticleFilter) func(context.Context, *transfer.ArticleFilter) ([]*transfer.Article, error) {
+    return func(_ context.Context, filter *transfer.ArticleFilter) ([]*transfer.Article, error) {
+      *filters = append(*filters, filter)
+      return page, nil
+    }
+  }
+
+  t.Run("links the next page", func(t *testing.T) {
+    var filters []*transfer.ArticleFilter
+    page, next, err := adminPage(newContext("/admin/articles"), true, get(articles, &filters))
+    require.NoError(t, err)
+    assert.Len(t, page, adminPageSize)
+    assert.Equal(t, "/admin/articles?after="+articles[adminPageSize-1].Cursor().String()+"&hidden=true", next)
+    assert.Nil(t, filters[0].After)
+  })
+
+  t.Run("last page", func(t *testing.T) {
+    var filters []*transfer.ArticleFilter
+    page, next, err := adminPage(newContext("/admin/articles"), false, get(articles[:2], &filters))
+    require.NoError(t, err)
+    assert.Len(t, page, 2)
+    assert.Empty(t, next)
+  })
+
+  t.Run("cursor of the other list", func(t *testing.T) {
+    var cursor = articles[0].Cursor()
+    var filters []*transfer.ArticleFilter
+    _, _, err := adminPage(newContext("/admin/articles?hidden=true&after="+cursor.String()), false, get(nil, &filters))
+    require.NoError(t, err)
+    assert.Nil(t, filters[0].After)
+    _, _, err = adminPage(newContext("/admin/articles?hidden=true&after="+cursor.String()), true, get(nil, &filters))
+    require.NoError(t, err)
+    assert.Equal(t, cursor, filters[1].After)
+  })
+}
+
+func TestAdminHandler_ReviseDraft(t *testing.T) {
+  const routine = "Revise"
+  var id = uuid.NewString()
+  var target = "/admin/drafts/" + id
+
+  var request = httptest.NewRequest(http.MethodPost, target, nil)
+  _ = request.ParseForm()
+  request.PostForm.Add("title", "Title")
+  request.PostForm.Add("topic_id", "")
+  request.PostForm.Add("content", "Content")
+  request.PostForm.Add("version", "2")
+  request.Header.Set("HX-Request", "true")
+
+  var revision = &transfer.ArticleRevision{Title: "Title", Content: "Content", Version: 2}
+
+  t.Run("success", func(t *testing.T) {
+    var s = mocks.NewDraftsService()
+    s.On(routine, mock.AnythingOfType("*gin.Context"), id, revision).Return(nil)
+    var engine = gin.Default()
+    engine.POST("/admin/drafts/:id", newAdminHandler("", s, mocks.NewTopicsService()).ReviseDraft)
+    var recorder = httptest.NewRecorder()
+    engine.ServeHTTP(recorder, request)
+    assert.Equal(t, http.StatusOK, recorder.Code)
+    assert.Empty(t, recorder.Header().Get("HX-Redirect"))
+  })
+
+  t.Run("success without htmx", func(t *testing.T) {
+    var request = httptest.NewRequest(http.MethodPost, target, strings.NewReader("title=Title&content=Content&version=2"))
+    request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+    var s = mocks.NewDraftsService()
+    s.On(routine, mock.AnythingOfType("*gin.Context"), id, revision).Return(nil)
+    var engine = gin.Default()
+    engine.POST("/admin/drafts/:id", newAdminHandler("", s, mocks.NewTopicsService()).ReviseDraft)
+    var recorder = httptest.NewRecorder()
+    engine.ServeHTTP(recorder, request)
+    assert.Equal(t, http.StatusSeeOther, recorder.Code)
+    assert.Equal(t, target, recorder.Header().Get("Location"))
+  })
+
+  t.Run("version conflict", func(t *testing.T) {
+    var s = mocks.NewDraftsService()
+    s.On(routine, mock.AnythingOfType("*gin.Context"), id, revision).Return(problem.NewVersionConflict(id, "draft", 3))
+    var engine = gin.Default()
+    engine.POST("/admin/drafts/:id", newAdminHandler("", s, mocks.NewTopicsService()).ReviseDraft)
+    var recorder = httptest.NewRecorder()
+    engine.ServeHTTP(recorder, request)
+    assert.Equal(t, http.StatusConflict, recorder.Code)
+    assert.Equal(t, "#problem", recorder.Header().Get("HX-Retarget"))
+  })
+}
+
+func TestAdminHandler_Preview(t *testing.T) {
+  var request = httptest.NewRequest(http.MethodPost, "/admin/preview", strings.NewReader("title=Title&content=Some+**content**"))
+  request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+  var s = mocks.NewDraftsService()
+  s.On("Measure", "Title", "Some **content**").Return(3, 1)
+  var engine = gin.Default()
+  engine.POST("/admin/preview", newAdminHandler("", s, mocks.NewTopicsService()).Preview)
+  var recorder = httptest.NewRecorder()
+  engine.ServeHTTP(recorder, request)
+  assert.Equal(t, http.StatusOK, recorder.Code)
+  assert.Contains(t, recorder.Body.String(), "<strong>content</strong>")
+  s.AssertExpectations(t)
+}
+
+func TestDescribeMembers(t *testing.T) {
+  var described = describeMembers(map[string]any{
+    "current_version": 3,
+    "errors": []any{
+      failure{Field: "name", Criterion: "required"},
+      failure{Field: "title", Criterion: "max", Parameter: "256"},
+    },
+  })
+
+  assert.Equal(t, []string{
+    "current_version: 3",
+    "name doesn't meet the 'required' criterion",
+    "title doesn't meet the 'max=256' criterion",
+  }, described)
+}
@@ -9,32 +9,55 @@ import (
 
 type ArticlesHandler struct {
   articles service.ArticlesService
+  pageSize int
 }
 
-func NewArticlesHandler(articles service.ArticlesService) *ArticlesHandler {
-  return &ArticlesHandler{articles}
+// NewArticlesHandler returns an ArticlesHandler whose lists have pageSize
+// articles per page unless the request asks for another size.
+func NewArticlesHandler(articles service.ArticlesService, pageSize int) *ArticlesHandler {
+  return &ArticlesHandler{articles, pageSize}
 }
 
 func (h *ArticlesHandler) Get(c *gin.Context) {
-  filter := getArticleFilter(c)
+  filter, ok := getPagedArticleFilter(c, h.pageSize)
+  if !ok {
+    return
+  }
+
   articles, err := h.articles.Get(c, filter)
 
   if check(err, c.Writer) {
     return
   }
 
-  c.JSON(http.StatusOK, articles)
+  total, err := h.articles.Count(c, filter, false)
+
+  if check(err, c.Writer) {
+    return
+  }
+
+  emitArticlesPage(c, filter, articles, total)
 }
 
 func (h *ArticlesHandler) GetHidden(c *gin.Context) {
-  filter := getArticleFilter(c)
+  filter, ok := getPagedArticleFilter(c, h.pageSize)
+  if !ok {
+    return
+  }
+
   articles, err := h.articles.GetHidden(c, filter)
 
   if check(err, c.Writer) {
     return
   }
 
-  c.JSON(http.StatusOK, articles)
+  total, err := h.articles.Count(c, filter, true)
+
+  if check(err, c.Writer) {
+    return
+  }
+
+  emitArticlesPage(c, filter, articles, total)
 }
 
 func (h *ArticlesHandler) GetByID(c *gin.Context) {
@@ -45,6 +68,10 @@ func (h *ArticlesHandler) GetByID(c *gin.Context) {
     return
   }
 
+  if freshRecord(c, article) {
+    return
+  }
+
   c.JSON(http.StatusOK, article)
 }
 
@@ -86,11 +113,12 @@ func (h *ArticlesHandler) Amend(c *gin.Context) {
     return
   }
 
-  if err := h.articles.Amend(c, article); check(err, c.Writer) {
+  patch, err := h.articles.Amend(c, article)
+  if check(err, c.Writer) {
     return
   }
 
-  c.Status(http.StatusNoContent)
+  c.JSON(http.StatusCreated, gin.H{"patch_uuid": patch})
 }
 
 func (h *ArticlesHandler) SetSlug(c *gin.Context) {
@@ -203,3 +231,11 @@ func (h *ArticlesHandler) RemoveTag(c *gin.Context) {
 
   c.Status(http.StatusNoContent)
 }
+
+func (h *ArticlesHandler) FlushViews(c *gin.Context) {
+  if err := h.articles.FlushViews(c); check(err, c.Writer) {
+    return
+  }
+
+  c.Status(http.StatusNoContent)
+}
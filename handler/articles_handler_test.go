@@ -27,13 +27,14 @@ func TestArticlesHandler_Get(t *testing.T) {
 
   t.Run("success", func(t *testing.T) {
     expectedStatusCode := http.StatusOK
-    expectedBody := string(marshal(t, articles))
+    expectedBody := string(marshal(t, transfer.Page[*transfer.Article]{Items: articles, Total: 7}))
 
     s := mocks.NewArticlesService()
     s.On(routine, mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("*transfer.ArticleFilter")).Return(articles, nil)
+    s.On("Count", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("*transfer.ArticleFilter"), false).Return(7, nil)
 
     engine := gin.Default()
-    engine.GET(target, NewArticlesHandler(s).Get)
+    engine.GET(target, NewArticlesHandler(s, pageSize).Get)
 
     recorder := httptest.NewRecorder()
 
@@ -56,7 +57,7 @@ func TestArticlesHandler_Get(t *testing.T) {
     s.On(routine, mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("*transfer.ArticleFilter")).Return(nil, expected)
 
     engine := gin.Default()
-    engine.GET(target, NewArticlesHandler(s).Get)
+    engine.GET(target, NewArticlesHandler(s, pageSize).Get)
 
     recorder := httptest.NewRecorder()
 
@@ -77,7 +78,7 @@ func TestArticlesHandler_Get(t *testing.T) {
     s.On(routine, mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("*transfer.ArticleFilter")).Return(nil, unexpected)
 
     engine := gin.Default()
-    engine.GET(target, NewArticlesHandler(s).Get)
+    engine.GET(target, NewArticlesHandler(s, pageSize).Get)
 
     recorder := httptest.NewRecorder()
 
@@ -102,13 +103,14 @@ func TestArticlesHandler_GetHidden(t *testing.T) {
 
   t.Run("success", func(t *testing.T) {
     expectedStatusCode := http.StatusOK
-    expectedBody := string(marshal(t, articles))
+    expectedBody := string(marshal(t, transfer.Page[*transfer.Article]{Items: articles, Total: 7}))
 
     s := mocks.NewArticlesService()
     s.On(routine, mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("*transfer.ArticleFilter")).Return(articles, nil)
+    s.On("Count", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("*transfer.ArticleFilter"), true).Return(7, nil)
 
     engine := gin.Default()
-    engine.GET(target, NewArticlesHandler(s).GetHidden)
+    engine.GET(target, NewArticlesHandler(s, pageSize).GetHidden)
 
     recorder := httptest.NewRecorder()
 
@@ -131,7 +133,7 @@ func TestArticlesHandler_GetHidden(t *testing.T) {
     s.On(routine, mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("*transfer.ArticleFilter")).Return(nil, expected)
 
     engine := gin.Default()
-    engine.GET(target, NewArticlesHandler(s).GetHidden)
+    engine.GET(target, NewArticlesHandler(s, pageSize).GetHidden)
 
     recorder := httptest.NewRecorder()
 
@@ -152,7 +154,7 @@ func TestArticlesHandler_GetHidden(t *testing.T) {
     s.On(routine, mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("*transfer.ArticleFilter")).Return(nil, unexpected)
 
     engine := gin.Default()
-    engine.GET(target, NewArticlesHandler(s).GetHidden)
+    engine.GET(target, NewArticlesHandler(s, pageSize).GetHidden)
 
     recorder := httptest.NewRecorder()
 
@@ -189,7 +191,7 @@ func TestArticlesHandler_GetByID(t *testing.T) {
     s.On(routine, mock.AnythingOfType("*gin.Context"), id).Return(article, nil)
 
     engine := gin.Default()
-    engine.GET(target, NewArticlesHandler(s).GetByID)
+    engine.GET(target, NewArticlesHandler(s, pageSize).GetByID)
 
     recorder := httptest.NewRecorder()
 
@@ -212,7 +214,7 @@ func TestArticlesHandler_GetByID(t *testing.T) {
     s.On(routine, mock.AnythingOfType("*gin.Context"), id).Return(nil, expected)
 
     engine := gin.Default()
-    engine.GET(target, NewArticlesHandler(s).GetByID)
+    engine.GET(target, NewArticlesHandler(s, pageSize).GetByID)
 
     recorder := httptest.NewRecorder()
 
@@ -233,7 +235,7 @@ func TestArticlesHandler_GetByID(t *testing.T) {
     s.On(routine, mock.AnythingOfType("*gin.Context"), id).Return(nil, unexpected)
 
     engine := gin.Default()
-    engine.GET(target, NewArticlesHandler(s).GetByID)
+    engine.GET(target, NewArticlesHandler(s, pageSize).GetByID)
 
     recorder := httptest.NewRecorder()
 
@@ -267,7 +269,7 @@ func TestArticlesHandler_Hide(t *testing.T) {
     s.On(routine, mock.AnythingOfType("*gin.Context"), id).Return(nil)
 
     engine := gin.Default()
-    engine.POST(target, NewArticlesHandler(s).Hide)
+    engine.POST(target, NewArticlesHandler(s, pageSize).Hide)
 
     recorder := httptest.NewRecorder()
 
@@ -290,7 +292,7 @@ func TestArticlesHandler_Hide(t *testing.T) {
     s.On(routine, mock.AnythingOfType("*gin.Context"), id).Return(expected)
 
     engine := gin.Default()
-    engine.POST(target, NewArticlesHandler(s).Hide)
+    engine.POST(target, NewArticlesHandler(s, pageSize).Hide)
 
     recorder := httptest.NewRecorder()
 
@@ -311,7 +313,7 @@ func TestArticlesHandler_Hide(t *testing.T) {
     s.On(routine, mock.AnythingOfType("*gin.Context"), id).Return(unexpected)
 
     engine := gin.Default()
-    engine.POST(target, NewArticlesHandler(s).Hide)
+    engine.POST(target, NewArticlesHandler(s, pageSize).Hide)
 
     recorder := httptest.NewRecorder()
 
@@ -345,7 +347,7 @@ func TestArticlesHandler_Show(t *testing.T) {
     s.On(routine, mock.AnythingOfType("*gin.Context"), id).Return(nil)
 
     engine := gin.Default()
-    engine.POST(target, NewArticlesHandler(s).Show)
+    engine.POST(target, NewArticlesHandler(s, pageSize).Show)
 
     recorder := httptest.NewRecorder()
 
@@ -368,7 +370,7 @@ func TestArticlesHandler_Show(t *testing.T) {
     s.On(routine, mock.AnythingOfType("*gin.Context"), id).Return(expected)
 
     engine := gin.Default()
-    engine.POST(target, NewArticlesHandler(s).Show)
+    engine.POST(target, NewArticlesHandler(s, pageSize).Show)
 
     recorder := httptest.NewRecorder()
 
@@ -389,7 +391,7 @@ func TestArticlesHandler_Show(t *testing.T) {
     s.On(routine, mock.AnythingOfType("*gin.Context"), id).Return(unexpected)
 
     engine := gin.Default()
-    engine.POST(target, NewArticlesHandler(s).Show)
+    engine.POST(target, NewArticlesHandler(s, pageSize).Show)
 
     recorder := httptest.NewRecorder()
 
@@ -417,20 +419,22 @@ func TestArticlesHandler_Amend(t *testing.T) {
   request.PostForm.Add("article_uuid", id)
 
   t.Run("success", func(t *testing.T) {
-    expectedStatusCode := http.StatusNoContent
+    patch := uuid.New()
+    expectedStatusCode := http.StatusCreated
+    expectedResponse := string(marshal(t, gin.H{"patch_uuid": patch.String()}))
 
     s := mocks.NewArticlesService()
-    s.On(routine, mock.AnythingOfType("*gin.Context"), id).Return(nil)
+    s.On(routine, mock.AnythingOfType("*gin.Context"), id).Return(patch, nil)
 
     engine := gin.Default()
-    engine.POST(target, NewArticlesHandler(s).Amend)
+    engine.POST(target, NewArticlesHandler(s, pageSize).Amend)
 
     recorder := httptest.NewRecorder()
 
     engine.ServeHTTP(recorder, request)
 
     assert.Equal(t, expectedStatusCode, recorder.Code)
-    assert.Empty(t, recorder.Body)
+    assert.Equal(t, expectedResponse, recorder.Body.String())
     assert.Empty(t, recorder.Result().Cookies())
   })
 
@@ -443,10 +447,10 @@ func TestArticlesHandler_Amend(t *testing.T) {
     expected.Detail(expectBodyContains)
 
     s := mocks.NewArticlesService()
-    s.On(routine, mock.AnythingOfType("*gin.Context"), id).Return(expected)
+    s.On(routine, mock.AnythingOfType("*gin.Context"), id).Return(uuid.Nil, expected)
 
     engine := gin.Default()
-    engine.POST(target, NewArticlesHandler(s).Amend)
+    engine.POST(target, NewArticlesHandler(s, pageSize).Amend)
 
     recorder := httptest.NewRecorder()
 
@@ -464,10 +468,10 @@ func TestArticlesHandler_Amend(t *testing.T) {
     expectBodyContains := "An unexpected error occurred while processing your request"
 
     s := mocks.NewArticlesService()
-    s.On(routine, mock.AnythingOfType("*gin.Context"), id).Return(unexpected)
+    s.On(routine, mock.AnythingOfType("*gin.Context"), id).Return(uuid.Nil, unexpected)
 
     engine := gin.Default()
-    engine.POST(target, NewArticlesHandler(s).Amend)
+    engine.POST(target, NewArticlesHandler(s, pageSize).Amend)
 
     recorder := httptest.NewRecorder()
 
@@ -501,7 +505,7 @@ func TestArticlesHandler_Remove(t *testing.T) {
     s.On(routine, mock.AnythingOfType("*gin.Context"), id).Return(nil)
 
     engine := gin.Default()
-    engine.POST(target, NewArticlesHandler(s).Remove)
+    engine.POST(target, NewArticlesHandler(s, pageSize).Remove)
 
     recorder := httptest.NewRecorder()
 
@@ -524,7 +528,7 @@ func TestArticlesHandler_Remove(t *testing.T) {
     s.On(routine, mock.AnythingOfType("*gin.Context"), id).Return(expected)
 
     engine := gin.Default()
-    engine.POST(target, NewArticlesHandler(s).Remove)
+    engine.POST(target, NewArticlesHandler(s, pageSize).Remove)
 
     recorder := httptest.NewRecorder()
 
@@ -545,7 +549,7 @@ func TestArticlesHandler_Remove(t *testing.T) {
     s.On(routine, mock.AnythingOfType("*gin.Context"), id).Return(unexpected)
 
     engine := gin.Default()
-    engine.POST(target, NewArticlesHandler(s).Remove)
+    engine.POST(target, NewArticlesHandler(s, pageSize).Remove)
 
     recorder := httptest.NewRecorder()
 
@@ -579,7 +583,7 @@ func TestArticlesHandler_Pin(t *testing.T) {
     s.On(routine, mock.AnythingOfType("*gin.Context"), id).Return(nil)
 
     engine := gin.Default()
-    engine.POST(target, NewArticlesHandler(s).Pin)
+    engine.POST(target, NewArticlesHandler(s, pageSize).Pin)
 
     recorder := httptest.NewRecorder()
 
@@ -602,7 +606,7 @@ func TestArticlesHandler_Pin(t *testing.T) {
     s.On(routine, mock.AnythingOfType("*gin.Context"), id).Return(expected)
 
     engine := gin.Default()
-    engine.POST(target, NewArticlesHandler(s).Pin)
+    engine.POST(target, NewArticlesHandler(s, pageSize).Pin)
 
     recorder := httptest.NewRecorder()
 
@@ -623,7 +627,7 @@ func TestArticlesHandler_Pin(t *testing.T) {
     s.On(routine, mock.AnythingOfType("*gin.Context"), id).Return(unexpected)
 
     engine := gin.Default()
-    engine.POST(target, NewArticlesHandler(s).Pin)
+    engine.POST(target, NewArticlesHandler(s, pageSize).Pin)
 
     recorder := httptest.NewRecorder()
 
@@ -656,7 +660,7 @@ func TestArticlesHandler_Unpin(t *testing.T) {
     s.On(routine, mock.AnythingOfType("*gin.Context"), id).Return(nil)
 
     engine := gin.Default()
-    engine.POST(target, NewArticlesHandler(s).Unpin)
+    engine.POST(target, NewArticlesHandler(s, pageSize).Unpin)
 
     recorder := httptest.NewRecorder()
 
@@ -679,7 +683,7 @@ func TestArticlesHandler_Unpin(t *testing.T) {
     s.On(routine, mock.AnythingOfType("*gin.Context"), id).Return(expected)
 
     engine := gin.Default()
-    engine.POST(target, NewArticlesHandler(s).Unpin)
+    engine.POST(target, NewArticlesHandler(s, pageSize).Unpin)
 
     recorder := httptest.NewRecorder()
 
@@ -700,7 +704,7 @@ func TestArticlesHandler_Unpin(t *testing.T) {
     s.On(routine, mock.AnythingOfType("*gin.Context"), id).Return(unexpected)
 
     engine := gin.Default()
-    engine.POST(target, NewArticlesHandler(s).Unpin)
+    engine.POST(target, NewArticlesHandler(s, pageSize).Unpin)
 
     recorder := httptest.NewRecorder()
 
@@ -736,7 +740,7 @@ func TestArticlesHandler_AddTag(t *testing.T) {
     s.On(routine, mock.AnythingOfType("*gin.Context"), articleUUID, tagID).Return(nil)
 
     engine := gin.Default()
-    engine.POST(target, NewArticlesHandler(s).AddTag)
+    engine.POST(target, NewArticlesHandler(s, pageSize).AddTag)
 
     recorder := httptest.NewRecorder()
 
@@ -759,7 +763,7 @@ func TestArticlesHandler_AddTag(t *testing.T) {
     s.On(routine, mock.AnythingOfType("*gin.Context"), articleUUID, tagID).Return(expected)
 
     engine := gin.Default()
-    engine.POST(target, NewArticlesHandler(s).AddTag)
+    engine.POST(target, NewArticlesHandler(s, pageSize).AddTag)
 
     recorder := httptest.NewRecorder()
 
@@ -780,7 +784,7 @@ func TestArticlesHandler_AddTag(t *testing.T) {
     s.On(routine, mock.AnythingOfType("*gin.Context"), articleUUID, tagID).Return(unexpected)
 
     engine := gin.Default()
-    engine.POST(target, NewArticlesHandler(s).AddTag)
+    engine.POST(target, NewArticlesHandler(s, pageSize).AddTag)
 
     recorder := httptest.NewRecorder()
 
@@ -816,7 +820,7 @@ func TestArticlesHandler_RemoveTag(t *testing.T) {
     s.On(routine, mock.AnythingOfType("*gin.Context"), articlesUUID, tagID).Return(nil)
 
     engine := gin.Default()
-    engine.POST(target, NewArticlesHandler(s).RemoveTag)
+    engine.POST(target, NewArticlesHandler(s, pageSize).RemoveTag)
 
     recorder := httptest.NewRecorder()
 
@@ -839,7 +843,7 @@ func TestArticlesHandler_RemoveTag(t *testing.T) {
     s.On(routine, mock.AnythingOfType("*gin.Context"), articlesUUID, tagID).Return(expected)
 
     engine := gin.Default()
-    engine.POST(target, NewArticlesHandler(s).RemoveTag)
+    engine.POST(target, NewArticlesHandler(s, pageSize).RemoveTag)
 
     recorder := httptest.NewRecorder()
 
@@ -860,7 +864,7 @@ func TestArticlesHandler_RemoveTag(t *testing.T) {
     s.On(routine, mock.AnythingOfType("*gin.Context"), articlesUUID, tagID).Return(unexpected)
 
     engine := gin.Default()
-    engine.POST(target, NewArticlesHandler(s).RemoveTag)
+    engine.POST(target, NewArticlesHandler(s, pageSize).RemoveTag)
 
     recorder := httptest.NewRecorder()
 
@@ -872,3 +876,41 @@ func TestArticlesHandler_RemoveTag(t *testing.T) {
     assert.Contains(t, recorder.Result().Header.Get("Content-Type"), "application/problem+json")
   })
 }
+
+func TestArticlesHandler_FlushViews(t *testing.T) {
+  const (
+    routine = "FlushViews"
+    method  = http.MethodPost
+    target  = "/archive.articles.views.flush"
+  )
+
+  t.Run("success", func(t *testing.T) {
+    s := mocks.NewArticlesService()
+    s.On(routine, mock.AnythingOfType("*gin.Context")).Return(nil)
+
+    engine := gin.Default()
+    engine.POST(target, NewArticlesHandler(s, pageSize).FlushViews)
+
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, httptest.NewRequest(method, target, nil))
+
+    assert.Equal(t, http.StatusNoContent, recorder.Code)
+    assert.Empty(t, recorder.Body)
+  })
+
+  t.Run("unexpected error", func(t *testing.T) {
+    s := mocks.NewArticlesService()
+    s.On(routine, mock.AnythingOfType("*gin.Context")).Return(errors.New("unexpected error"))
+
+    engine := gin.Default()
+    engine.POST(target, NewArticlesHandler(s, pageSize).FlushViews)
+
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, httptest.NewRequest(method, target, nil))
+
+    assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+    assert.Contains(t, recorder.Result().Header.Get("Content-Type"), "application/problem+json")
+  })
+}
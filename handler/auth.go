@@ -0,0 +1,110 @@
+package handler
+
+import (
+  "crypto/hmac"
+  "crypto/sha256"
+  "crypto/subtle"
+  "encoding/hex"
+  "fontseca.dev/problem"
+  "fontseca.dev/service"
+  "github.com/gin-gonic/gin"
+  "net/http"
+  "strconv"
+  "strings"
+  "time"
+)
+
+// RequireToken is a middleware that aborts every request that does not
+// carry token, or a token stored in tokens, in its 'Authorization:
+// Bearer <token>' header. An empty token matches nothing, so if token
+// is empty and tokens is nil, every request is aborted.
+func RequireToken(token string, tokens service.TokensService) gin.HandlerFunc {
+  return func(c *gin.Context) {
+    if !hasToken(c, token, tokens) {
+      c.Header("WWW-Authenticate", `Bearer realm="fontseca.dev"`)
+      problem.NewUnauthorized().Emit(c.Writer)
+      c.Abort()
+      return
+    }
+
+    c.Next()
+  }
+}
+
+// hasToken reports whether the request carries token, or a token
+// stored in tokens, in its 'Authorization: Bearer <token>' header. A
+// token that cannot be checked does not authenticate the request.
+func hasToken(c *gin.Context, token string, tokens service.TokensService) bool {
+  given, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+  if !ok {
+    return false
+  }
+
+  given = strings.TrimSpace(given)
+
+  if "" != token && 1 == subtle.ConstantTimeCompare([]byte(given), []byte(token)) {
+    return true
+  }
+
+  if nil == tokens {
+    return false
+  }
+
+  stored, _ := tokens.Check(c, given)
+  return stored
+}
+
+// adminSessionCookie is the name of the cookie that authenticates the
+// browser sessions of the administration site.
+const adminSessionCookie = "admin_session"
+
+// adminSession returns the value of a session cookie that expires at
+// expires. It is signed with token, so the token itself never travels
+// in a cookie and changing it ends every session.
+func adminSession(token string, expires time.Time) string {
+  var expiry = strconv.FormatInt(expires.Unix(), 10)
+  var mac = hmac.New(sha256.New, []byte(token))
+  mac.Write([]byte(adminSessionCookie + "." + expiry))
+  return expiry + "." + hex.EncodeToString(mac.Sum(nil))
+}
+
+// validAdminSession reports whether session is an unexpired session
+// cookie signed with token.
+func validAdminSession(token, session string) bool {
+  expiry, _, ok := strings.Cut(session, ".")
+  if !ok || "" == token {
+    return false
+  }
+
+  seconds, err := strconv.ParseInt(expiry, 10, 64)
+  if nil != err || time.Now().After(time.Unix(seconds, 0)) {
+    return false
+  }
+
+  return 1 == subtle.ConstantTimeCompare([]byte(session), []byte(adminSession(token, time.Unix(seconds, 0))))
+}
+
+// RequireAdminSession is a middleware that lets through the requests
+// with a valid session cookie, or with token or a token of tokens as
+// in RequireToken. Any other request is sent to the login page of the
+// administration site.
+func RequireAdminSession(token string, tokens service.TokensService) gin.HandlerFunc {
+  return func(c *gin.Context) {
+    var session, _ = c.Cookie(adminSessionCookie)
+
+    if validAdminSession(token, session) || hasToken(c, token, tokens) {
+      c.Header("Cache-Control", "no-store")
+      c.Next()
+      return
+    }
+
+    if isHTMX(c) {
+      c.Header("HX-Redirect", "/admin/login")
+      c.AbortWithStatus(http.StatusUnauthorized)
+      return
+    }
+
+    c.Redirect(http.StatusSeeOther, "/admin/login")
+    c.Abort()
+  }
+}
@@ -0,0 +1,24 @@
+package handler
+
+import (
+  "fontseca.dev/service"
+  "github.com/gin-gonic/gin"
+  "net/http"
+)
+
+type BackupsHandler struct {
+  backups service.BackupsService
+}
+
+func NewBackupsHandler(backups service.BackupsService) *BackupsHandler {
+  return &BackupsHandler{backups}
+}
+
+func (h *BackupsHandler) Create(c *gin.Context) {
+  backup, err := h.backups.Create(c)
+  if check(err, c.Writer) {
+    return
+  }
+
+  c.JSON(http.StatusCreated, backup)
+}
@@ -0,0 +1,42 @@
+package handler
+
+import (
+  "errors"
+  "fontseca.dev/mocks"
+  "fontseca.dev/transfer"
+  "github.com/gin-gonic/gin"
+  "github.com/stretchr/testify/assert"
+  "github.com/stretchr/testify/mock"
+  "net/http"
+  "net/http/httptest"
+  "testing"
+  "time"
+)
+
+func TestBackupsHandler_Create(t *testing.T) {
+  const routine = "Create"
+  const method = http.MethodPost
+  const target = "/site.backup"
+
+  t.Run("success", func(t *testing.T) {
+    var backup = &transfer.Backup{Name: "db-20240301T120000.000.sqlite", Size: 4096, CreatedAt: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)}
+    var s = mocks.NewBackupsService()
+    s.On(routine, mock.AnythingOfType("*gin.Context")).Return(backup, nil)
+    var engine = gin.Default()
+    engine.POST(target, NewBackupsHandler(s).Create)
+    var recorder = httptest.NewRecorder()
+    engine.ServeHTTP(recorder, httptest.NewRequest(method, target, nil))
+    assert.Equal(t, http.StatusCreated, recorder.Code)
+    assert.Equal(t, string(marshal(t, backup)), recorder.Body.String())
+  })
+
+  t.Run("unexpected error", func(t *testing.T) {
+    var s = mocks.NewBackupsService()
+    s.On(routine, mock.AnythingOfType("*gin.Context")).Return(nil, errors.New("unexpected error"))
+    var engine = gin.Default()
+    engine.POST(target, NewBackupsHandler(s).Create)
+    var recorder = httptest.NewRecorder()
+    engine.ServeHTTP(recorder, httptest.NewRequest(method, target, nil))
+    assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+  })
+}
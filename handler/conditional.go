@@ -0,0 +1,333 @@
+package handler
+
+import (
+  "bytes"
+  "crypto/sha256"
+  "encoding/hex"
+  "encoding/json"
+  "fmt"
+  "fontseca.dev/model"
+  "fontseca.dev/problem"
+  "fontseca.dev/transfer"
+  "github.com/gin-gonic/gin"
+  "log/slog"
+  "net/http"
+  "reflect"
+  "strings"
+  "time"
+)
+
+// Conditional is a middleware that makes GET requests conditional.
+// Handlers that send records set their validators with fresh, which
+// answers 304 (Not Modified) before anything is rendered; their
+// responses are written right away. Any other successful JSON or HTML
+// response gets a weak ETag computed from a hash of its body; so do the
+// ones whose content type is left to be sniffed, like rendered pages.
+// If the request has a matching If-None-Match header or, lacking it,
+// an If-Modified-Since header not older than the Last-Modified header
+// of the response, the body is dropped in favor of a 304 response.
+func Conditional() gin.HandlerFunc {
+  return func(c *gin.Context) {
+    if http.MethodGet != c.Request.Method && http.MethodHead != c.Request.Method {
+      c.Next()
+      return
+    }
+
+    var original = c.Writer
+    var w = &conditionalWriter{ResponseWriter: original}
+
+    c.Writer = w
+    c.Next()
+    c.Writer = original
+
+    if !w.buffered {
+      return
+    }
+
+    var etag = bodyETag(w.body.Bytes())
+    original.Header().Set("ETag", etag)
+
+    if notModified(c.Request, etag, original.Header().Get("Last-Modified")) {
+      original.Header().Del("Content-Type")
+      original.Header().Del("Content-Length")
+      original.WriteHeader(http.StatusNotModified)
+      original.WriteHeaderNow()
+      return
+    }
+
+    _, _ = original.Write(w.body.Bytes())
+  }
+}
+
+// conditionalWriter holds back the body of a successful JSON or HTML
+// response so that its ETag can be computed before it is written.
+// Any other response is written right away.
+type conditionalWriter struct {
+  gin.ResponseWriter
+  body     bytes.Buffer
+  decided  bool
+  buffered bool
+}
+
+func (w *conditionalWriter) decide() {
+  if w.decided {
+    return
+  }
+
+  w.decided = true
+
+  var contentType = w.Header().Get("Content-Type")
+  w.buffered = http.StatusOK == w.Status() &&
+    "" == w.Header().Get("ETag") &&
+    ("" == contentType || strings.HasPrefix(contentType, "application/json") || strings.HasPrefix(contentType, "text/html"))
+}
+
+func (w *conditionalWriter) Write(data []byte) (int, error) {
+  if w.decide(); w.buffered {
+    return w.body.Write(data)
+  }
+  return w.ResponseWriter.Write(data)
+}
+
+func (w *conditionalWriter) WriteString(s string) (int, error) {
+  if w.decide(); w.buffered {
+    return w.body.WriteString(s)
+  }
+  return w.ResponseWriter.WriteString(s)
+}
+
+func (w *conditionalWriter) WriteHeaderNow() {
+  if w.decide(); !w.buffered {
+    w.ResponseWriter.WriteHeaderNow()
+  }
+}
+
+func (w *conditionalWriter) Flush() {
+  if w.decide(); !w.buffered {
+    w.ResponseWriter.Flush()
+  }
+}
+
+// bodyETag returns a weak entity tag of body, which is a hash of it.
+func bodyETag(body []byte) string {
+  var sum = sha256.Sum256(body)
+  return `W/"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// weakETag returns a weak entity tag of parts, the values that identify
+// a representation, like the identity of a record and the times it was
+// changed.
+func weakETag(parts ...any) string {
+  var hash = sha256.New()
+
+  for _, part := range parts {
+    if t, ok := part.(time.Time); ok {
+      part = t.UTC().Format(time.RFC3339Nano)
+    }
+
+    fmt.Fprintf(hash, "%v\x00", part)
+  }
+
+  return `W/"` + hex.EncodeToString(hash.Sum(nil)[:16]) + `"`
+}
+
+// validators returns the ETag of the representation of record and the
+// time it was last changed. The tag is derived from the identity of the
+// record, its version and the times it was changed, which change
+// whenever the record does; the name is part of it for the records
+// without a version, since their timestamps may not tell apart the
+// renames made within a second. It reports false for the values that
+// are not records.
+func validators(record any) (etag string, modified time.Time, ok bool) {
+  if value := reflect.ValueOf(record); !value.IsValid() || (reflect.Pointer == value.Kind() && value.IsNil()) {
+    return "", time.Time{}, false
+  }
+
+  switch r := record.(type) {
+  case *model.Me:
+    return weakETag("me", r.Version, r.UpdatedAt), r.UpdatedAt, true
+  case *model.Experience:
+    return weakETag(r.UUID, r.Version, r.UpdatedAt), r.UpdatedAt, true
+  case *model.Project:
+    return weakETag(r.UUID, r.Version, r.UpdatedAt), r.UpdatedAt, true
+  case *model.TechnologyTag:
+    return weakETag(r.UUID, r.Name, r.UpdatedAt), r.UpdatedAt, true
+  case *model.Tag:
+    return weakETag(r.ID, r.Name, r.UpdatedAt), r.UpdatedAt, true
+  case *model.Topic:
+    return weakETag(r.ID, r.Name, r.UpdatedAt), r.UpdatedAt, true
+  case *model.Webhook:
+    return weakETag(r.UUID, r.UpdatedAt), r.UpdatedAt, true
+  case *transfer.Article:
+    return weakETag(r.UUID, r.IsPinned, r.UpdatedAt), r.UpdatedAt, true
+  case *model.Article:
+    var parts = []any{r.UUID, r.Version, r.UpdatedAt}
+    modified = r.UpdatedAt
+
+    if nil != r.ModifiedAt {
+      parts = append(parts, *r.ModifiedAt)
+
+      if r.ModifiedAt.After(modified) {
+        modified = *r.ModifiedAt
+      }
+    }
+
+    // Its topic and tags change on their own.
+    if nil != r.Topic {
+      parts = append(parts, r.Topic.ID, r.Topic.Name, r.Topic.UpdatedAt)
+    }
+
+    for _, tag := range r.Tags {
+      parts = append(parts, tag.ID, tag.Name, tag.UpdatedAt)
+    }
+
+    return weakETag(parts...), modified, true
+  }
+
+  return "", time.Time{}, false
+}
+
+// etagOf returns the ETag of the representation of value: the one of
+// validators for records and, for other values, the one of a response
+// that has their JSON representation as its body.
+func etagOf(value any) (string, error) {
+  if etag, _, ok := validators(value); ok {
+    return etag, nil
+  }
+
+  data, err := json.Marshal(value)
+  if nil != err {
+    slog.Error(err.Error())
+    return "", err
+  }
+  return bodyETag(data), nil
+}
+
+// fresh sets the ETag of the response to etag and its Last-Modified
+// header to the latest of times, and reports whether the client of the
+// request already has that representation. If so, it answers 304 (Not
+// Modified) and the handler must not write anything else.
+func fresh(c *gin.Context, etag string, times ...time.Time) bool {
+  c.Header("ETag", etag)
+  setLastModified(c, times...)
+
+  if notModified(c.Request, etag, c.Writer.Header().Get("Last-Modified")) {
+    c.Status(http.StatusNotModified)
+    c.Writer.WriteHeaderNow()
+    return true
+  }
+
+  return false
+}
+
+// freshRecord is fresh with the validators of record. It reports false
+// for the values that are not records, which are left to Conditional.
+func freshRecord(c *gin.Context, record any) bool {
+  etag, modified, ok := validators(record)
+  if !ok {
+    return false
+  }
+
+  return fresh(c, etag, modified)
+}
+
+// freshPage is fresh for a page of a list. Its ETag is derived from the
+// ones of its items and its Last-Modified header is the newest of their
+// changes. Pages of values that are not records are left to Conditional.
+func freshPage[T any](c *gin.Context, page *transfer.Page[T]) bool {
+  var (
+    parts = []any{page.Total, page.NextCursor}
+    times = make([]time.Time, 0, len(page.Items))
+  )
+
+  for _, item := range page.Items {
+    etag, modified, ok := validators(item)
+    if !ok {
+      return false
+    }
+
+    parts = append(parts, etag)
+    times = append(times, modified)
+  }
+
+  return fresh(c, weakETag(parts...), times...)
+}
+
+// matchesETag reports whether the list of entity tags of an If-Match or
+// If-None-Match header has etag. The tags are compared regardless of
+// whether they are weak.
+func matchesETag(header, etag string) bool {
+  for _, candidate := range strings.Split(header, ",") {
+    candidate = strings.TrimSpace(candidate)
+    if "*" == candidate || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+      return true
+    }
+  }
+  return false
+}
+
+// notModified reports whether the representation of the response with
+// etag and lastModified is the one the client of request already has.
+func notModified(request *http.Request, etag, lastModified string) bool {
+  if header := request.Header.Get("If-None-Match"); "" != header {
+    return matchesETag(header, etag)
+  }
+
+  since, err := http.ParseTime(request.Header.Get("If-Modified-Since"))
+  if nil != err {
+    return false
+  }
+
+  modified, err := http.ParseTime(lastModified)
+  if nil != err {
+    return false
+  }
+
+  return !modified.After(since)
+}
+
+// setLastModified sets the Last-Modified header of the response to the
+// latest of times.
+func setLastModified(c *gin.Context, times ...time.Time) {
+  var latest time.Time
+  for _, t := range times {
+    if t.After(latest) {
+      latest = t
+    }
+  }
+
+  if !latest.IsZero() {
+    c.Header("Last-Modified", latest.UTC().Format(http.TimeFormat))
+  }
+}
+
+// ifMatch reports whether the request can modify a resource. That is,
+// either it has no If-Match header, or the header matches the ETag of
+// the current representation of the resource, which is retrieved with
+// current. Otherwise, it emits a problem and returns false. The tags
+// are weak, but they change whenever the resource does, so they are
+// compared regardless of it.
+func ifMatch(c *gin.Context, current func() (any, error)) bool {
+  var header = c.GetHeader("If-Match")
+  if "" == header {
+    return true
+  }
+
+  resource, err := current()
+  if check(err, c.Writer) {
+    return false
+  }
+
+  etag, err := etagOf(resource)
+  if check(err, c.Writer) {
+    return false
+  }
+
+  if !matchesETag(header, etag) {
+    c.Header("ETag", etag)
+    problem.NewPreconditionFailed(etag).Emit(c.Writer)
+    return false
+  }
+
+  return true
+}
@@ -0,0 +1,172 @@
+package handler
+
+import (
+  "errors"
+  "fontseca.dev/model"
+  "github.com/gin-gonic/gin"
+  "github.com/stretchr/testify/assert"
+  "net/http"
+  "net/http/httptest"
+  "strings"
+  "testing"
+  "time"
+)
+
+func TestConditional(t *testing.T) {
+  const target = "/me.info"
+  var updatedAt = time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+  var me = &model.Me{Username: "fontseca", UpdatedAt: updatedAt}
+
+  var engine = gin.New()
+  engine.Use(Conditional())
+  engine.GET(target, func(c *gin.Context) {
+    if !freshRecord(c, me) {
+      c.JSON(http.StatusOK, me)
+    }
+  })
+  engine.GET("/me.health", func(c *gin.Context) {
+    c.JSON(http.StatusOK, gin.H{"status": "alive"})
+  })
+  engine.GET("/archive.tar.gz", func(c *gin.Context) {
+    c.Data(http.StatusOK, "application/gzip", []byte("archive"))
+  })
+  engine.GET("/missing", func(c *gin.Context) {
+    c.JSON(http.StatusNotFound, gin.H{"title": "Record not found."})
+  })
+
+  var serve = func(target string, header http.Header) *httptest.ResponseRecorder {
+    var request = httptest.NewRequest(http.MethodGet, target, nil)
+    for name, values := range header {
+      request.Header[name] = values
+    }
+    var recorder = httptest.NewRecorder()
+    engine.ServeHTTP(recorder, request)
+    return recorder
+  }
+
+  var etag, _ = etagOf(me)
+
+  t.Run("sets the validators", func(t *testing.T) {
+    var recorder = serve(target, nil)
+    assert.Equal(t, http.StatusOK, recorder.Code)
+    assert.Equal(t, etag, recorder.Header().Get("ETag"))
+    assert.Equal(t, "Fri, 01 Mar 2024 12:00:00 GMT", recorder.Header().Get("Last-Modified"))
+    assert.Equal(t, string(marshal(t, me)), recorder.Body.String())
+  })
+
+  t.Run("matching If-None-Match", func(t *testing.T) {
+    var recorder = serve(target, http.Header{"If-None-Match": {`"other", ` + etag}})
+    assert.Equal(t, http.StatusNotModified, recorder.Code)
+    assert.Empty(t, recorder.Body.String())
+    assert.Equal(t, etag, recorder.Header().Get("ETag"))
+  })
+
+  t.Run("weak If-None-Match", func(t *testing.T) {
+    assert.True(t, strings.HasPrefix(etag, `W/"`), etag)
+
+    var recorder = serve(target, http.Header{"If-None-Match": {strings.TrimPrefix(etag, "W/")}})
+    assert.Equal(t, http.StatusNotModified, recorder.Code)
+  })
+
+  t.Run("stale If-None-Match", func(t *testing.T) {
+    var recorder = serve(target, http.Header{
+      "If-None-Match":     {`W/"stale"`},
+      "If-Modified-Since": {"Fri, 01 Mar 2024 12:00:00 GMT"},
+    })
+    assert.Equal(t, http.StatusOK, recorder.Code)
+    assert.NotEmpty(t, recorder.Body.String())
+  })
+
+  t.Run("If-Modified-Since", func(t *testing.T) {
+    var recorder = serve(target, http.Header{"If-Modified-Since": {"Fri, 01 Mar 2024 12:00:00 GMT"}})
+    assert.Equal(t, http.StatusNotModified, recorder.Code)
+
+    recorder = serve(target, http.Header{"If-Modified-Since": {"Fri, 01 Mar 2024 11:59:59 GMT"}})
+    assert.Equal(t, http.StatusOK, recorder.Code)
+  })
+
+  t.Run("tags other responses with a hash of their body", func(t *testing.T) {
+    var recorder = serve("/me.health", nil)
+    assert.Equal(t, http.StatusOK, recorder.Code)
+
+    var etag = recorder.Header().Get("ETag")
+    assert.Equal(t, bodyETag(recorder.Body.Bytes()), etag)
+    assert.True(t, strings.HasPrefix(etag, `W/"`), etag)
+
+    recorder = serve("/me.health", http.Header{"If-None-Match": {etag}})
+    assert.Equal(t, http.StatusNotModified, recorder.Code)
+    assert.Empty(t, recorder.Body.String())
+  })
+
+  t.Run("changes with the record", func(t *testing.T) {
+    var changed = *me
+    changed.Version++
+
+    tag, _ := etagOf(&changed)
+    assert.NotEqual(t, etag, tag)
+  })
+
+  t.Run("leaves other responses alone", func(t *testing.T) {
+    var recorder = serve("/archive.tar.gz", http.Header{"If-None-Match": {"*"}})
+    assert.Equal(t, http.StatusOK, recorder.Code)
+    assert.Empty(t, recorder.Header().Get("ETag"))
+    assert.Equal(t, "archive", recorder.Body.String())
+
+    recorder = serve("/missing", http.Header{"If-None-Match": {"*"}})
+    assert.Equal(t, http.StatusNotFound, recorder.Code)
+    assert.Empty(t, recorder.Header().Get("ETag"))
+  })
+}
+
+func TestIfMatch(t *testing.T) {
+  const target = "/me.set"
+  var me = &model.Me{Username: "fontseca"}
+  var etag, _ = etagOf(me)
+
+  var serve = func(header string, current func() (any, error)) *httptest.ResponseRecorder {
+    var engine = gin.New()
+    engine.POST(target, func(c *gin.Context) {
+      if ifMatch(c, current) {
+        c.Status(http.StatusNoContent)
+      }
+    })
+    var request = httptest.NewRequest(http.MethodPost, target, nil)
+    if "" != header {
+      request.Header.Set("If-Match", header)
+    }
+    var recorder = httptest.NewRecorder()
+    engine.ServeHTTP(recorder, request)
+    return recorder
+  }
+
+  var current = func() (any, error) { return me, nil }
+
+  t.Run("without If-Match", func(t *testing.T) {
+    var recorder = serve("", func() (any, error) {
+      t.Error("the resource must not be retrieved")
+      return nil, nil
+    })
+    assert.Equal(t, http.StatusNoContent, recorder.Code)
+  })
+
+  t.Run("matching If-Match", func(t *testing.T) {
+    assert.Equal(t, http.StatusNoContent, serve(etag, current).Code)
+    assert.Equal(t, http.StatusNoContent, serve("*", current).Code)
+  })
+
+  t.Run("stale If-Match", func(t *testing.T) {
+    var recorder = serve(`W/"stale"`, current)
+    assert.Equal(t, http.StatusPreconditionFailed, recorder.Code)
+    assert.Equal(t, etag, recorder.Header().Get("ETag"))
+    assert.Contains(t, recorder.Body.String(), etag[3:len(etag)-1])
+  })
+
+  t.Run("tag without the weak prefix", func(t *testing.T) {
+    assert.Equal(t, http.StatusNoContent, serve(strings.TrimPrefix(etag, "W/"), current).Code)
+  })
+
+  t.Run("unexpected error", func(t *testing.T) {
+    var recorder = serve(etag, func() (any, error) { return nil, errors.New("unexpected error") })
+    assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+  })
+}
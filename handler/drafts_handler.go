@@ -9,11 +9,14 @@ import (
 )
 
 type DraftsHandler struct {
-  drafts service.DraftsService
+  drafts   service.DraftsService
+  pageSize int
 }
 
-func NewDraftsHandler(drafts service.DraftsService) *DraftsHandler {
-  return &DraftsHandler{drafts}
+// NewDraftsHandler returns a DraftsHandler whose lists have pageSize
+// drafts per page unless the request asks for another size.
+func NewDraftsHandler(drafts service.DraftsService, pageSize int) *DraftsHandler {
+  return &DraftsHandler{drafts, pageSize}
 }
 
 func (h *DraftsHandler) Start(c *gin.Context) {
@@ -44,7 +47,7 @@ func (h *DraftsHandler) Publish(c *gin.Context) {
     return
   }
 
-  if err := h.drafts.Publish(c, draft); check(err, c.Writer) {
+  if err := h.drafts.Publish(c, draft, c.PostFormArray("tag_id")...); check(err, c.Writer) {
     return
   }
 
@@ -52,14 +55,24 @@ func (h *DraftsHandler) Publish(c *gin.Context) {
 }
 
 func (h *DraftsHandler) Get(c *gin.Context) {
-  filter := getArticleFilter(c)
+  filter, ok := getPagedArticleFilter(c, h.pageSize)
+  if !ok {
+    return
+  }
+
   drafts, err := h.drafts.Get(c, filter)
 
   if check(err, c.Writer) {
     return
   }
 
-  c.JSON(http.StatusOK, drafts)
+  total, err := h.drafts.Count(c, filter)
+
+  if check(err, c.Writer) {
+    return
+  }
+
+  emitArticlesPage(c, filter, drafts, total)
 }
 
 func (h *DraftsHandler) GetByID(c *gin.Context) {
@@ -70,6 +83,10 @@ func (h *DraftsHandler) GetByID(c *gin.Context) {
     return
   }
 
+  if freshRecord(c, draft) {
+    return
+  }
+
   c.JSON(http.StatusOK, draft)
 }
 
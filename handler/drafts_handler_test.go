@@ -43,7 +43,7 @@ func TestDraftsHandler_Start(t *testing.T) {
     s.On(routine, mock.AnythingOfType("*gin.Context"), creation).Return(id, nil)
 
     engine := gin.Default()
-    engine.POST(target, NewDraftsHandler(s).Start)
+    engine.POST(target, NewDraftsHandler(s, pageSize).Start)
 
     recorder := httptest.NewRecorder()
 
@@ -66,7 +66,7 @@ func TestDraftsHandler_Start(t *testing.T) {
     s.On(routine, mock.AnythingOfType("*gin.Context"), creation).Return(uuid.Nil, expected)
 
     engine := gin.Default()
-    engine.POST(target, NewDraftsHandler(s).Start)
+    engine.POST(target, NewDraftsHandler(s, pageSize).Start)
 
     recorder := httptest.NewRecorder()
 
@@ -87,7 +87,7 @@ func TestDraftsHandler_Start(t *testing.T) {
     s.On(routine, mock.AnythingOfType("*gin.Context"), creation).Return(uuid.Nil, unexpected)
 
     engine := gin.Default()
-    engine.POST(target, NewDraftsHandler(s).Start)
+    engine.POST(target, NewDraftsHandler(s, pageSize).Start)
 
     recorder := httptest.NewRecorder()
 
@@ -121,7 +121,7 @@ func TestDraftsHandler_Publish(t *testing.T) {
     s.On(routine, mock.AnythingOfType("*gin.Context"), id).Return(nil)
 
     engine := gin.Default()
-    engine.POST(target, NewDraftsHandler(s).Publish)
+    engine.POST(target, NewDraftsHandler(s, pageSize).Publish)
 
     recorder := httptest.NewRecorder()
 
@@ -132,6 +132,28 @@ func TestDraftsHandler_Publish(t *testing.T) {
     assert.Empty(t, recorder.Result().Cookies())
   })
 
+  t.Run("with tags", func(t *testing.T) {
+    s := mocks.NewDraftsService()
+    s.On(routine, mock.AnythingOfType("*gin.Context"), id, "go", "sql").Return(nil)
+
+    engine := gin.Default()
+    engine.POST(target, NewDraftsHandler(s, pageSize).Publish)
+
+    request := httptest.NewRequest(method, target, nil)
+    _ = request.ParseForm()
+
+    request.PostForm.Add("draft_uuid", id)
+    request.PostForm.Add("tag_id", "go")
+    request.PostForm.Add("tag_id", "sql")
+
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, request)
+
+    assert.Equal(t, http.StatusNoContent, recorder.Code)
+    s.AssertExpectations(t)
+  })
+
   t.Run("expected problem detail", func(t *testing.T) {
     expectedStatusCode := http.StatusBadRequest
     expectBodyContains := "Expected problem detail."
@@ -144,7 +166,7 @@ func TestDraftsHandler_Publish(t *testing.T) {
     s.On(routine, mock.AnythingOfType("*gin.Context"), id).Return(expected)
 
     engine := gin.Default()
-    engine.POST(target, NewDraftsHandler(s).Publish)
+    engine.POST(target, NewDraftsHandler(s, pageSize).Publish)
 
     recorder := httptest.NewRecorder()
 
@@ -165,7 +187,7 @@ func TestDraftsHandler_Publish(t *testing.T) {
     s.On(routine, mock.AnythingOfType("*gin.Context"), id).Return(unexpected)
 
     engine := gin.Default()
-    engine.POST(target, NewDraftsHandler(s).Publish)
+    engine.POST(target, NewDraftsHandler(s, pageSize).Publish)
 
     recorder := httptest.NewRecorder()
 
@@ -190,13 +212,14 @@ func TestDraftsHandler_Get(t *testing.T) {
 
   t.Run("success without search", func(t *testing.T) {
     expectedStatusCode := http.StatusOK
-    expectedBody := string(marshal(t, drafts))
+    expectedBody := string(marshal(t, transfer.Page[*transfer.Article]{Items: drafts, Total: len(drafts)}))
 
     s := mocks.NewDraftsService()
     s.On(routine, mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("*transfer.ArticleFilter")).Return(drafts, nil)
+    s.On("Count", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("*transfer.ArticleFilter")).Return(len(drafts), nil)
 
     engine := gin.Default()
-    engine.GET(target, NewDraftsHandler(s).Get)
+    engine.GET(target, NewDraftsHandler(s, pageSize).Get)
 
     recorder := httptest.NewRecorder()
 
@@ -219,7 +242,7 @@ func TestDraftsHandler_Get(t *testing.T) {
     s.On(routine, mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("*transfer.ArticleFilter")).Return(nil, expected)
 
     engine := gin.Default()
-    engine.GET(target, NewDraftsHandler(s).Get)
+    engine.GET(target, NewDraftsHandler(s, pageSize).Get)
 
     recorder := httptest.NewRecorder()
 
@@ -240,7 +263,7 @@ func TestDraftsHandler_Get(t *testing.T) {
     s.On(routine, mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("*transfer.ArticleFilter")).Return(nil, unexpected)
 
     engine := gin.Default()
-    engine.GET(target, NewDraftsHandler(s).Get)
+    engine.GET(target, NewDraftsHandler(s, pageSize).Get)
 
     recorder := httptest.NewRecorder()
 
@@ -277,7 +300,7 @@ func TestDraftsHandler_GetByID(t *testing.T) {
     s.On(routine, mock.AnythingOfType("*gin.Context"), id).Return(draft, nil)
 
     engine := gin.Default()
-    engine.GET(target, NewDraftsHandler(s).GetByID)
+    engine.GET(target, NewDraftsHandler(s, pageSize).GetByID)
 
     recorder := httptest.NewRecorder()
 
@@ -300,7 +323,7 @@ func TestDraftsHandler_GetByID(t *testing.T) {
     s.On(routine, mock.AnythingOfType("*gin.Context"), id).Return(nil, expected)
 
     engine := gin.Default()
-    engine.GET(target, NewDraftsHandler(s).GetByID)
+    engine.GET(target, NewDraftsHandler(s, pageSize).GetByID)
 
     recorder := httptest.NewRecorder()
 
@@ -321,7 +344,7 @@ func TestDraftsHandler_GetByID(t *testing.T) {
     s.On(routine, mock.AnythingOfType("*gin.Context"), id).Return(nil, unexpected)
 
     engine := gin.Default()
-    engine.GET(target, NewDraftsHandler(s).GetByID)
+    engine.GET(target, NewDraftsHandler(s, pageSize).GetByID)
 
     recorder := httptest.NewRecorder()
 
@@ -357,7 +380,7 @@ func TestDraftsHandler_AddTag(t *testing.T) {
     s.On(routine, mock.AnythingOfType("*gin.Context"), draftUUID, tagID).Return(nil)
 
     engine := gin.Default()
-    engine.POST(target, NewDraftsHandler(s).AddTag)
+    engine.POST(target, NewDraftsHandler(s, pageSize).AddTag)
 
     recorder := httptest.NewRecorder()
 
@@ -380,7 +403,7 @@ func TestDraftsHandler_AddTag(t *testing.T) {
     s.On(routine, mock.AnythingOfType("*gin.Context"), draftUUID, tagID).Return(expected)
 
     engine := gin.Default()
-    engine.POST(target, NewDraftsHandler(s).AddTag)
+    engine.POST(target, NewDraftsHandler(s, pageSize).AddTag)
 
     recorder := httptest.NewRecorder()
 
@@ -401,7 +424,7 @@ func TestDraftsHandler_AddTag(t *testing.T) {
     s.On(routine, mock.AnythingOfType("*gin.Context"), draftUUID, tagID).Return(unexpected)
 
     engine := gin.Default()
-    engine.POST(target, NewDraftsHandler(s).AddTag)
+    engine.POST(target, NewDraftsHandler(s, pageSize).AddTag)
 
     recorder := httptest.NewRecorder()
 
@@ -437,7 +460,7 @@ func TestDraftsHandler_RemoveTag(t *testing.T) {
     s.On(routine, mock.AnythingOfType("*gin.Context"), draftUUID, tagID).Return(nil)
 
     engine := gin.Default()
-    engine.POST(target, NewDraftsHandler(s).RemoveTag)
+    engine.POST(target, NewDraftsHandler(s, pageSize).RemoveTag)
 
     recorder := httptest.NewRecorder()
 
@@ -460,7 +483,7 @@ func TestDraftsHandler_RemoveTag(t *testing.T) {
     s.On(routine, mock.AnythingOfType("*gin.Context"), draftUUID, tagID).Return(expected)
 
     engine := gin.Default()
-    engine.POST(target, NewDraftsHandler(s).RemoveTag)
+    engine.POST(target, NewDraftsHandler(s, pageSize).RemoveTag)
 
     recorder := httptest.NewRecorder()
 
@@ -481,7 +504,7 @@ func TestDraftsHandler_RemoveTag(t *testing.T) {
     s.On(routine, mock.AnythingOfType("*gin.Context"), draftUUID, tagID).Return(unexpected)
 
     engine := gin.Default()
-    engine.POST(target, NewDraftsHandler(s).RemoveTag)
+    engine.POST(target, NewDraftsHandler(s, pageSize).RemoveTag)
 
     recorder := httptest.NewRecorder()
 
@@ -517,7 +540,7 @@ func TestDraftsHandler_Share(t *testing.T) {
     s.On(routine, mock.AnythingOfType("*gin.Context"), id).Return(link, nil)
 
     engine := gin.Default()
-    engine.POST(target, NewDraftsHandler(s).Share)
+    engine.POST(target, NewDraftsHandler(s, pageSize).Share)
 
     recorder := httptest.NewRecorder()
 
@@ -540,7 +563,7 @@ func TestDraftsHandler_Share(t *testing.T) {
     s.On(routine, mock.AnythingOfType("*gin.Context"), id).Return("about:blank", expected)
 
     engine := gin.Default()
-    engine.POST(target, NewDraftsHandler(s).Share)
+    engine.POST(target, NewDraftsHandler(s, pageSize).Share)
 
     recorder := httptest.NewRecorder()
 
@@ -561,7 +584,7 @@ func TestDraftsHandler_Share(t *testing.T) {
     s.On(routine, mock.AnythingOfType("*gin.Context"), id).Return("about:blank", unexpected)
 
     engine := gin.Default()
-    engine.POST(target, NewDraftsHandler(s).Share)
+    engine.POST(target, NewDraftsHandler(s, pageSize).Share)
 
     recorder := httptest.NewRecorder()
 
@@ -595,7 +618,7 @@ func TestDraftsHandler_Discard(t *testing.T) {
     s.On(routine, mock.AnythingOfType("*gin.Context"), id).Return(nil)
 
     engine := gin.Default()
-    engine.POST(target, NewDraftsHandler(s).Discard)
+    engine.POST(target, NewDraftsHandler(s, pageSize).Discard)
 
     recorder := httptest.NewRecorder()
 
@@ -618,7 +641,7 @@ func TestDraftsHandler_Discard(t *testing.T) {
     s.On(routine, mock.AnythingOfType("*gin.Context"), id).Return(expected)
 
     engine := gin.Default()
-    engine.POST(target, NewDraftsHandler(s).Discard)
+    engine.POST(target, NewDraftsHandler(s, pageSize).Discard)
 
     recorder := httptest.NewRecorder()
 
@@ -639,7 +662,7 @@ func TestDraftsHandler_Discard(t *testing.T) {
     s.On(routine, mock.AnythingOfType("*gin.Context"), id).Return(unexpected)
 
     engine := gin.Default()
-    engine.POST(target, NewDraftsHandler(s).Discard)
+    engine.POST(target, NewDraftsHandler(s, pageSize).Discard)
 
     recorder := httptest.NewRecorder()
 
@@ -662,6 +685,7 @@ func TestDraftsHandler_Revise(t *testing.T) {
   revision := &transfer.ArticleRevision{
     Title:   "Title",
     Content: "Content",
+    Version: 3,
   }
 
   id := uuid.NewString()
@@ -672,6 +696,7 @@ func TestDraftsHandler_Revise(t *testing.T) {
   request.PostForm.Add("draft_uuid", id)
   request.PostForm.Add("title", revision.Title)
   request.PostForm.Add("content", revision.Content)
+  request.PostForm.Add("version", "3")
 
   t.Run("success", func(t *testing.T) {
     expectedStatusCode := http.StatusNoContent
@@ -680,7 +705,7 @@ func TestDraftsHandler_Revise(t *testing.T) {
     s.On(routine, mock.AnythingOfType("*gin.Context"), id, revision).Return(nil)
 
     engine := gin.Default()
-    engine.POST(target, NewDraftsHandler(s).Revise)
+    engine.POST(target, NewDraftsHandler(s, pageSize).Revise)
 
     recorder := httptest.NewRecorder()
 
@@ -703,7 +728,7 @@ func TestDraftsHandler_Revise(t *testing.T) {
     s.On(routine, mock.AnythingOfType("*gin.Context"), id, revision).Return(expected)
 
     engine := gin.Default()
-    engine.POST(target, NewDraftsHandler(s).Revise)
+    engine.POST(target, NewDraftsHandler(s, pageSize).Revise)
 
     recorder := httptest.NewRecorder()
 
@@ -724,7 +749,7 @@ func TestDraftsHandler_Revise(t *testing.T) {
     s.On(routine, mock.AnythingOfType("*gin.Context"), id, revision).Return(unexpected)
 
     engine := gin.Default()
-    engine.POST(target, NewDraftsHandler(s).Revise)
+    engine.POST(target, NewDraftsHandler(s, pageSize).Revise)
 
     recorder := httptest.NewRecorder()
 
@@ -19,7 +19,11 @@ func NewExperienceHandler(s service.ExperienceService) *ExperienceHandler {
 }
 
 func (h *ExperienceHandler) Get(c *gin.Context) {
-  var e, err = h.s.Get(c)
+  var page, ok = getPagination(c)
+  if !ok {
+    return
+  }
+  var e, err = h.s.List(c, false, page)
   if nil != err {
     var p *problem.Problem
     if errors.As(err, &p) {
@@ -29,11 +33,15 @@ func (h *ExperienceHandler) Get(c *gin.Context) {
     }
     return
   }
-  c.JSON(http.StatusOK, e)
+  emitPage(c, e, page.Limit)
 }
 
 func (h *ExperienceHandler) GetHidden(c *gin.Context) {
-  var e, err = h.s.Get(c, true)
+  var page, ok = getPagination(c)
+  if !ok {
+    return
+  }
+  var e, err = h.s.List(c, true, page)
   if nil != err {
     var p *problem.Problem
     if errors.As(err, &p) {
@@ -43,7 +51,7 @@ func (h *ExperienceHandler) GetHidden(c *gin.Context) {
     }
     return
   }
-  c.JSON(http.StatusOK, e)
+  emitPage(c, e, page.Limit)
 }
 
 func (h *ExperienceHandler) GetByID(c *gin.Context) {
@@ -58,6 +66,10 @@ func (h *ExperienceHandler) GetByID(c *gin.Context) {
     }
     return
   }
+  if freshRecord(c, e) {
+    return
+  }
+
   c.JSON(http.StatusOK, e)
 }
 
@@ -101,6 +113,10 @@ func (h *ExperienceHandler) Set(c *gin.Context) {
     return
   }
 
+  if !ifMatch(c, func() (any, error) { return h.s.GetByID(c, id) }) {
+    return
+  }
+
   updated, err := h.s.Update(c, id, &update)
   if check(err, c.Writer) {
     return
@@ -120,7 +136,12 @@ func (h *ExperienceHandler) Hide(c *gin.Context) {
     return
   }
 
-  updated, err := h.s.Update(c, id, &transfer.ExperienceUpdate{Hidden: true})
+  version, ok := postFormVersion(c)
+  if !ok {
+    return
+  }
+
+  updated, err := h.s.Update(c, id, &transfer.ExperienceUpdate{Hidden: true, Version: version})
   if check(err, c.Writer) {
     return
   }
@@ -139,7 +160,12 @@ func (h *ExperienceHandler) Show(c *gin.Context) {
     return
   }
 
-  updated, err := h.s.Update(c, id, &transfer.ExperienceUpdate{Hidden: false})
+  version, ok := postFormVersion(c)
+  if !ok {
+    return
+  }
+
+  updated, err := h.s.Update(c, id, &transfer.ExperienceUpdate{Hidden: false, Version: version})
   if check(err, c.Writer) {
     return
   }
@@ -158,9 +184,15 @@ func (h *ExperienceHandler) Quit(c *gin.Context) {
     return
   }
 
+  version, ok := postFormVersion(c)
+  if !ok {
+    return
+  }
+
   var updated, err = h.s.Update(c, id, &transfer.ExperienceUpdate{
-    Active: false,
-    Ends:   time.Now().Year(),
+    Active:  false,
+    Ends:    time.Now().Year(),
+    Version: version,
   })
 
   if check(err, c.Writer) {
@@ -18,14 +18,14 @@ import (
 )
 
 func TestExperienceHandler_Get(t *testing.T) {
-  const routine = "Get"
+  const routine = "List"
   const method = http.MethodGet
   const target = "/experience.list"
 
   t.Run("success", func(t *testing.T) {
     var e = make([]*model.Experience, 1)
     var s = mocks.NewExperienceService()
-    s.On(routine, mock.AnythingOfType("*gin.Context"), []bool(nil)).Return(e, nil)
+    s.On(routine, mock.AnythingOfType("*gin.Context"), false, &transfer.PageRequest{Limit: defaultLimit}).Return(&transfer.Page[*model.Experience]{Items: e, Total: len(e)}, nil)
     gin.SetMode(gin.ReleaseMode)
     var engine = gin.Default()
     engine.GET(target, NewExperienceHandler(s).Get)
@@ -33,19 +33,19 @@ func TestExperienceHandler_Get(t *testing.T) {
     var recorder = httptest.NewRecorder()
     engine.ServeHTTP(recorder, request)
     assert.Equal(t, http.StatusOK, recorder.Code)
-    assert.Equal(t, string(marshal(t, e)), recorder.Body.String())
+    assert.Equal(t, string(marshal(t, transfer.Page[*model.Experience]{Items: e, Total: len(e)})), recorder.Body.String())
   })
 }
 
 func TestExperienceHandler_GetHidden(t *testing.T) {
-  const routine = "Get"
+  const routine = "List"
   const method = http.MethodGet
   const target = "/experience.hidden.list"
 
   t.Run("success", func(t *testing.T) {
     var e = make([]*model.Experience, 1)
     var s = mocks.NewExperienceService()
-    s.On(routine, mock.AnythingOfType("*gin.Context"), []bool{true}).Return(e, nil)
+    s.On(routine, mock.AnythingOfType("*gin.Context"), true, &transfer.PageRequest{Limit: defaultLimit}).Return(&transfer.Page[*model.Experience]{Items: e, Total: len(e)}, nil)
     gin.SetMode(gin.ReleaseMode)
     var engine = gin.Default()
     engine.GET(target, NewExperienceHandler(s).GetHidden)
@@ -53,7 +53,7 @@ func TestExperienceHandler_GetHidden(t *testing.T) {
     var recorder = httptest.NewRecorder()
     engine.ServeHTTP(recorder, request)
     assert.Equal(t, http.StatusOK, recorder.Code)
-    assert.Equal(t, string(marshal(t, e)), recorder.Body.String())
+    assert.Equal(t, string(marshal(t, transfer.Page[*model.Experience]{Items: e, Total: len(e)})), recorder.Body.String())
   })
 }
 
@@ -167,6 +167,7 @@ func TestExperienceHandler_Set(t *testing.T) {
     Company:  "Company",
     Country:  "Country",
     Summary:  "Summary",
+    Version:  2,
   }
 
   var request = httptest.NewRequest(method, target, nil)
@@ -180,6 +181,7 @@ func TestExperienceHandler_Set(t *testing.T) {
   request.PostForm.Add("company", "Company")
   request.PostForm.Add("country", "Country")
   request.PostForm.Add("summary", "Summary")
+  request.PostForm.Add("version", "2")
 
   t.Run("missing 'id' parameter", func(t *testing.T) {
     var s = mocks.NewExperienceService()
@@ -230,6 +232,17 @@ func TestExperienceHandler_Set(t *testing.T) {
     assert.Contains(t, recorder.Body.String(), "Expected problem detail.")
   })
 
+  t.Run("version conflict", func(t *testing.T) {
+    var s = mocks.NewExperienceService()
+    s.On(routine, mock.AnythingOfType("*gin.Context"), id, update).Return(false, problem.NewVersionConflict(id, "experience", 3))
+    var engine = gin.Default()
+    engine.POST(target, NewExperienceHandler(s).Set)
+    var recorder = httptest.NewRecorder()
+    engine.ServeHTTP(recorder, request)
+    assert.Equal(t, http.StatusConflict, recorder.Code)
+    assert.Contains(t, recorder.Body.String(), `"current_version":3`)
+  })
+
   t.Run("unexpected error", func(t *testing.T) {
     var unexpected = errors.New("unexpected error")
     var s = mocks.NewExperienceService()
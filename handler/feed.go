@@ -0,0 +1,114 @@
+package handler
+
+import (
+  "encoding/xml"
+  "fontseca.dev/transfer"
+  "github.com/gin-gonic/gin"
+  "net/http"
+  "time"
+)
+
+// feedSize is the number of articles in every feed of the archive.
+const feedSize = 20
+
+// atomFeed is an Atom feed (RFC 4287) of articles.
+type atomFeed struct {
+  XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+  ID      string      `xml:"id"`
+  Title   string      `xml:"title"`
+  Updated string      `xml:"updated"`
+  Links   []atomLink  `xml:"link"`
+  Author  string      `xml:"author>name"`
+  Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+  Rel  string `xml:"rel,attr,omitempty"`
+  Href string `xml:"href,attr"`
+}
+
+type atomEntry struct {
+  ID        string   `xml:"id"`
+  Title     string   `xml:"title"`
+  Link      atomLink `xml:"link"`
+  Published string   `xml:"published"`
+  Updated   string   `xml:"updated"`
+}
+
+// RenderFeed writes the Atom feed of the latest articles of the
+// archive or, under '/archive/:topic/feed.xml', of a topic.
+func (h *WebHandler) RenderFeed(c *gin.Context) {
+  var (
+    topic  = c.Param("topic")
+    filter = &transfer.ArticleFilter{Topic: topic, Page: 1, RPP: feedSize}
+  )
+
+  articles, err := h.articles.Get(c, filter)
+  if nil != err {
+    h.internal(c)
+    return
+  }
+
+  var scheme = "http"
+
+  if nil != c.Request.TLS {
+    scheme = "https"
+  }
+
+  var (
+    base    = scheme + "://" + c.Request.Host
+    title   = "fontseca.dev — Archive"
+    archive = base + "/archive"
+    updated time.Time
+  )
+
+  if "" != topic {
+    title += " — " + topic
+    archive += "/" + topic
+  }
+
+  var feed = atomFeed{
+    ID:    archive,
+    Title: title,
+    Links: []atomLink{
+      {Rel: "self", Href: base + c.Request.URL.Path},
+      {Rel: "alternate", Href: archive},
+    },
+    Author:  "fontseca.dev",
+    Entries: make([]atomEntry, 0, len(articles)),
+  }
+
+  for _, article := range articles {
+    if nil == article.PublishedAt {
+      continue
+    }
+
+    if article.PublishedAt.After(updated) {
+      updated = *article.PublishedAt
+    }
+
+    var published = article.PublishedAt.UTC().Format(time.RFC3339)
+
+    feed.Entries = append(feed.Entries, atomEntry{
+      ID:        "urn:uuid:" + article.UUID.String(),
+      Title:     article.Title,
+      Link:      atomLink{Rel: "alternate", Href: article.URL},
+      Published: published,
+      Updated:   published,
+    })
+  }
+
+  if updated.IsZero() {
+    updated = time.Now()
+  }
+
+  feed.Updated = updated.UTC().Format(time.RFC3339)
+
+  data, err := xml.MarshalIndent(feed, "", "  ")
+  if nil != err {
+    h.internal(c)
+    return
+  }
+
+  c.Data(http.StatusOK, "application/atom+xml; charset=utf-8", append([]byte(xml.Header), data...))
+}
@@ -0,0 +1,132 @@
+package handler
+
+import (
+  "context"
+  "fontseca.dev/problem"
+  "fontseca.dev/transfer"
+  "github.com/gin-gonic/gin"
+  "net/http"
+  "runtime/debug"
+  "sync"
+  "sync/atomic"
+  "time"
+)
+
+// Probe checks a part of the server that must work for it to serve
+// requests. It returns an error that tells why the part doesn't work.
+type Probe func(ctx context.Context) error
+
+// probeTimeout bounds the time given to every readiness probe.
+const probeTimeout = 2 * time.Second
+
+// HealthHandler answers the probes of the container orchestrator and
+// tells which build of the site is running.
+type HealthHandler struct {
+  probes   map[string]Probe
+  build    *transfer.Build
+  draining atomic.Bool
+}
+
+// NewHealthHandler creates a HealthHandler that is ready as long as
+// every one of probes succeeds. buildTime is the time at which the
+// binary was built, if known.
+func NewHealthHandler(probes map[string]Probe, buildTime string) *HealthHandler {
+  return &HealthHandler{
+    probes: probes,
+    build:  readBuild(buildTime),
+  }
+}
+
+// readBuild describes the running binary after the information that
+// the Go toolchain embeds into it.
+func readBuild(buildTime string) *transfer.Build {
+  var build = &transfer.Build{Version: "(devel)", BuildTime: buildTime}
+
+  info, ok := debug.ReadBuildInfo()
+  if !ok {
+    return build
+  }
+
+  build.Module = info.Main.Path
+  build.GoVersion = info.GoVersion
+
+  if "" != info.Main.Version {
+    build.Version = info.Main.Version
+  }
+
+  for _, setting := range info.Settings {
+    switch setting.Key {
+    case "vcs.revision":
+      build.Revision = setting.Value
+    case "vcs.modified":
+      build.Modified = "true" == setting.Value
+    case "vcs.time":
+      if "" == build.BuildTime {
+        build.BuildTime = setting.Value
+      }
+    }
+  }
+
+  return build
+}
+
+// Drain makes the server report that it is not ready anymore, so that
+// the orchestrator stops sending requests to it before it shuts down.
+func (h *HealthHandler) Drain() {
+  h.draining.Store(true)
+}
+
+func (h *HealthHandler) Live(c *gin.Context) {
+  c.JSON(http.StatusOK, &transfer.Health{Status: "alive"})
+}
+
+func (h *HealthHandler) Ready(c *gin.Context) {
+  c.Header("Cache-Control", "no-store")
+
+  if h.draining.Load() {
+    problem.NewNotReady(map[string]string{"server": "shutting down"}).Emit(c.Writer)
+    return
+  }
+
+  var (
+    checks = make(map[string]string, len(h.probes))
+    ready  = true
+    mu     sync.Mutex
+    wg     sync.WaitGroup
+  )
+
+  ctx, cancel := context.WithTimeout(c, probeTimeout)
+  defer cancel()
+
+  for name, probe := range h.probes {
+    wg.Add(1)
+
+    go func() {
+      defer wg.Done()
+
+      var result = "ok"
+      if err := probe(ctx); nil != err {
+        result = err.Error()
+      }
+
+      mu.Lock()
+      defer mu.Unlock()
+
+      checks[name] = result
+      ready = ready && "ok" == result
+    }()
+  }
+
+  wg.Wait()
+
+  if !ready {
+    problem.NewNotReady(checks).Emit(c.Writer)
+    return
+  }
+
+  c.JSON(http.StatusOK, &transfer.Health{Status: "ready", Checks: checks})
+}
+
+func (h *HealthHandler) Version(c *gin.Context) {
+  c.JSON(http.StatusOK, h.build)
+}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+  "context"
+  "encoding/json"
+  "errors"
+  "fontseca.dev/transfer"
+  "github.com/gin-gonic/gin"
+  "github.com/stretchr/testify/assert"
+  "github.com/stretchr/testify/require"
+  "net/http"
+  "net/http/httptest"
+  "testing"
+)
+
+func TestHealthHandler(t *testing.T) {
+  var failing error
+  var h = NewHealthHandler(map[string]Probe{
+    "database": func(context.Context) error { return nil },
+    "schema":   func(context.Context) error { return failing },
+  }, "2024-03-01T12:00:00Z")
+
+  var engine = gin.New()
+  engine.GET("/healthz", h.Live)
+  engine.GET("/readyz", h.Ready)
+  engine.GET("/version", h.Version)
+
+  var get = func(target string) *httptest.ResponseRecorder {
+    var recorder = httptest.NewRecorder()
+    engine.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, target, nil))
+    return recorder
+  }
+
+  t.Run("live", func(t *testing.T) {
+    var recorder = get("/healthz")
+    assert.Equal(t, http.StatusOK, recorder.Code)
+    assert.JSONEq(t, `{"status":"alive"}`, recorder.Body.String())
+  })
+
+  t.Run("ready", func(t *testing.T) {
+    var recorder = get("/readyz")
+    assert.Equal(t, http.StatusOK, recorder.Code)
+    assert.JSONEq(t, `{"status":"ready","checks":{"database":"ok","schema":"ok"}}`, recorder.Body.String())
+  })
+
+  t.Run("a probe fails", func(t *testing.T) {
+    failing = errors.New("schema version is 2, but 3 is expected")
+    defer func() { failing = nil }()
+
+    var recorder = get("/readyz")
+    assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+
+    var body struct {
+      Checks map[string]string `json:"checks"`
+    }
+    require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+    assert.Equal(t, map[string]string{"database": "ok", "schema": failing.Error()}, body.Checks)
+  })
+
+  t.Run("version", func(t *testing.T) {
+    var recorder = get("/version")
+    assert.Equal(t, http.StatusOK, recorder.Code)
+
+    var build transfer.Build
+    require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &build))
+    assert.Equal(t, "2024-03-01T12:00:00Z", build.BuildTime)
+    assert.NotEmpty(t, build.GoVersion)
+  })
+
+  t.Run("draining", func(t *testing.T) {
+    h.Drain()
+
+    assert.Equal(t, http.StatusOK, get("/healthz").Code)
+    assert.Equal(t, http.StatusServiceUnavailable, get("/readyz").Code)
+  })
+}
@@ -61,7 +61,7 @@ func bindJSONRequestBody(c *gin.Context, obj any) (ok bool) {
 
     switch {
     default:
-      slog.Error(err.Error())
+      slog.ErrorContext(c.Request.Context(), err.Error())
       problem.NewInternal().Emit(c.Writer)
       return
     case errors.Is(err, io.EOF):
@@ -160,6 +160,23 @@ func handleStrconvError(err error, targetType, fieldName string) (error, bool) {
   return nil, true
 }
 
+// postFormVersion parses the 'version' form field, which is the version
+// of the record the request modifies. It is zero if the field is not
+// given, which the repositories reject with a precondition required
+// problem. If it does not parse, it emits a problem and returns false.
+func postFormVersion(c *gin.Context) (version int, ok bool) {
+  var value, given = c.GetPostForm("version")
+  if !given {
+    return 0, true
+  }
+  version, err := strconv.Atoi(strings.TrimSpace(value))
+  if err, ok = handleStrconvError(err, "int", "version"); !ok {
+    check(err, c.Writer)
+    return 0, false
+  }
+  return version, true
+}
+
 func bindPostForm(c *gin.Context, val any) error {
   if nil == c || nil == val {
     var err = errors.New("got an unacceptable nil parameter")
@@ -241,8 +258,9 @@ var (
 )
 
 // getArticleFilter creates a transfer.ArticleFilter object with the values extracted from
-// c.Request.URL. If no values are provided, then it injects default values.
-func getArticleFilter(c *gin.Context) *transfer.ArticleFilter {
+// c.Request.URL. If no values are provided, then it injects default values; pages have
+// pageSize articles unless 'rpp' says otherwise.
+func getArticleFilter(c *gin.Context, pageSize int) *transfer.ArticleFilter {
   var (
     filter transfer.ArticleFilter
     err    error
@@ -293,7 +311,7 @@ func getArticleFilter(c *gin.Context) *transfer.ArticleFilter {
   }
 
   if 0 >= filter.RPP {
-    filter.RPP = 20
+    filter.RPP = pageSize
   }
 
   var from = c.Query("from")
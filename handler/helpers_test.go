@@ -10,6 +10,10 @@ import (
   "testing"
 )
 
+// pageSize is the default size of the pages of articles of the
+// handlers under test.
+const pageSize = 20
+
 type dummy struct {
   StringField  string  `json:"string_field,omitempty"`
   IntField     int     `json:"int_field"`
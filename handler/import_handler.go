@@ -0,0 +1,47 @@
+package handler
+
+import (
+  "fontseca.dev/problem"
+  "fontseca.dev/service"
+  "fontseca.dev/transfer"
+  "github.com/gin-gonic/gin"
+  "net/http"
+)
+
+type ImportHandler struct {
+  imports service.ImportService
+}
+
+func NewImportHandler(imports service.ImportService) *ImportHandler {
+  return &ImportHandler{imports}
+}
+
+func (h *ImportHandler) Import(c *gin.Context) {
+  form, err := c.MultipartForm()
+
+  if nil != err || 0 == len(form.File["files"]) {
+    problem.NewMissingParameter("files").Emit(c.Writer)
+    return
+  }
+
+  results := make([]*transfer.ArticleImportResult, 0, len(form.File["files"]))
+
+  for _, header := range form.File["files"] {
+    file, err := header.Open()
+
+    if check(err, c.Writer) {
+      return
+    }
+
+    result, err := h.imports.Import(c, header.Filename, file)
+    file.Close()
+
+    if check(err, c.Writer) {
+      return
+    }
+
+    results = append(results, result)
+  }
+
+  c.JSON(http.StatusOK, results)
+}
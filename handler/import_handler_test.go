@@ -0,0 +1,133 @@
+package handler
+
+import (
+  "bytes"
+  "errors"
+  "fontseca.dev/mocks"
+  "fontseca.dev/problem"
+  "fontseca.dev/transfer"
+  "github.com/gin-gonic/gin"
+  "github.com/google/uuid"
+  "github.com/stretchr/testify/assert"
+  "github.com/stretchr/testify/mock"
+  "github.com/stretchr/testify/require"
+  "mime/multipart"
+  "net/http"
+  "net/http/httptest"
+  "testing"
+)
+
+func TestImportHandler_Import(t *testing.T) {
+  const (
+    routine = "Import"
+    method  = http.MethodPost
+    target  = "/archive.import"
+  )
+
+  newRequest := func(t *testing.T, files ...string) *http.Request {
+    var body bytes.Buffer
+
+    w := multipart.NewWriter(&body)
+
+    for _, name := range files {
+      part, err := w.CreateFormFile("files", name)
+      require.NoError(t, err)
+      _, _ = part.Write([]byte("---\ntitle: Title\n---\n"))
+    }
+
+    require.NoError(t, w.Close())
+
+    request := httptest.NewRequest(method, target, &body)
+    request.Header.Set("Content-Type", w.FormDataContentType())
+
+    return request
+  }
+
+  t.Run("success", func(t *testing.T) {
+    results := []*transfer.ArticleImportResult{
+      {File: "a.md", UUID: uuid.New(), Slug: "a", Status: transfer.ImportCreated},
+      {File: "b.md", UUID: uuid.New(), Slug: "b", Status: transfer.ImportUnchanged},
+    }
+
+    expectedStatusCode := http.StatusOK
+    expectedBody := string(marshal(t, results))
+
+    s := mocks.NewImportService()
+    s.On(routine, mock.AnythingOfType("*gin.Context"), "a.md", mock.Anything).Return(results[0], nil)
+    s.On(routine, mock.AnythingOfType("*gin.Context"), "b.md", mock.Anything).Return(results[1], nil)
+
+    engine := gin.Default()
+    engine.POST(target, NewImportHandler(s).Import)
+
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, newRequest(t, "a.md", "b.md"))
+
+    assert.Equal(t, expectedStatusCode, recorder.Code)
+    assert.Equal(t, expectedBody, recorder.Body.String())
+    assert.Empty(t, recorder.Result().Cookies())
+  })
+
+  t.Run("missing files", func(t *testing.T) {
+    expectedStatusCode := http.StatusBadRequest
+
+    s := mocks.NewImportService()
+    s.AssertNotCalled(t, routine)
+
+    engine := gin.Default()
+    engine.POST(target, NewImportHandler(s).Import)
+
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, newRequest(t))
+
+    assert.Equal(t, expectedStatusCode, recorder.Code)
+    assert.Contains(t, recorder.Body.String(), "files")
+    assert.Contains(t, recorder.Result().Header.Get("Content-Type"), "application/problem+json")
+  })
+
+  t.Run("expected problem detail", func(t *testing.T) {
+    expectedStatusCode := http.StatusUnprocessableEntity
+    expectBodyContains := "Expected problem detail."
+
+    expected := &problem.Problem{}
+    expected.Status(expectedStatusCode)
+    expected.Detail(expectBodyContains)
+
+    s := mocks.NewImportService()
+    s.On(routine, mock.AnythingOfType("*gin.Context"), "a.md", mock.Anything).Return(nil, expected)
+
+    engine := gin.Default()
+    engine.POST(target, NewImportHandler(s).Import)
+
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, newRequest(t, "a.md"))
+
+    assert.Equal(t, expectedStatusCode, recorder.Code)
+    assert.Contains(t, recorder.Body.String(), expectBodyContains)
+    assert.Empty(t, recorder.Result().Cookies())
+    assert.Contains(t, recorder.Result().Header.Get("Content-Type"), "application/problem+json")
+  })
+
+  t.Run("unexpected error", func(t *testing.T) {
+    unexpected := errors.New("unexpected error")
+    expectedStatusCode := http.StatusInternalServerError
+    expectBodyContains := "An unexpected error occurred while processing your request"
+
+    s := mocks.NewImportService()
+    s.On(routine, mock.AnythingOfType("*gin.Context"), "a.md", mock.Anything).Return(nil, unexpected)
+
+    engine := gin.Default()
+    engine.POST(target, NewImportHandler(s).Import)
+
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, newRequest(t, "a.md"))
+
+    assert.Equal(t, expectedStatusCode, recorder.Code)
+    assert.Contains(t, recorder.Body.String(), expectBodyContains)
+    assert.Empty(t, recorder.Result().Cookies())
+    assert.Contains(t, recorder.Result().Header.Get("Content-Type"), "application/problem+json")
+  })
+}
@@ -0,0 +1,70 @@
+package handler
+
+import (
+  "fontseca.dev/logging"
+  "github.com/gin-gonic/gin"
+  "github.com/google/uuid"
+  "log/slog"
+  "net/http"
+  "regexp"
+  "time"
+)
+
+// RequestIDHeader is the header through which the ID of a request is
+// received from proxies and sent back to clients.
+const RequestIDHeader = "X-Request-ID"
+
+// validRequestID matches the request IDs received from clients that are
+// worth keeping; any other is replaced with a generated one.
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// RequestID is a middleware that identifies every request, either with
+// the ID in its RequestIDHeader header or with a new one. The ID is sent
+// back in the same header and carried by the context of the request, so
+// that every record logged with it gets its 'request_id'.
+func RequestID() gin.HandlerFunc {
+  return func(c *gin.Context) {
+    var id = c.GetHeader(RequestIDHeader)
+    if !validRequestID.MatchString(id) {
+      id = uuid.NewString()
+    }
+
+    c.Header(RequestIDHeader, id)
+    c.Request = c.Request.WithContext(logging.WithRequestID(c.Request.Context(), id))
+    c.Next()
+  }
+}
+
+// Log is a middleware that logs every request once it's been served.
+// Requests that end up in a server error are logged at the error level.
+func Log() gin.HandlerFunc {
+  return func(c *gin.Context) {
+    var start = time.Now()
+    var path = c.Request.URL.Path
+
+    c.Next()
+
+    var status = c.Writer.Status()
+    var level = slog.LevelInfo
+    if http.StatusInternalServerError <= status {
+      level = slog.LevelError
+    }
+
+    var attrs = []slog.Attr{
+      slog.String("method", c.Request.Method),
+      slog.String("path", path),
+      slog.String("route", c.FullPath()),
+      slog.Int("status", status),
+      slog.Int("bytes", max(0, c.Writer.Size())),
+      slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+      slog.String("client_ip", c.ClientIP()),
+      slog.String("user_agent", c.Request.UserAgent()),
+    }
+
+    if errs := c.Errors.ByType(gin.ErrorTypePrivate); 0 < len(errs) {
+      attrs = append(attrs, slog.String("error", errs.String()))
+    }
+
+    slog.LogAttrs(c.Request.Context(), level, "request served", attrs...)
+  }
+}
@@ -0,0 +1,101 @@
+package handler
+
+import (
+  "bytes"
+  "encoding/json"
+  "fontseca.dev/logging"
+  "github.com/gin-gonic/gin"
+  "github.com/stretchr/testify/assert"
+  "github.com/stretchr/testify/require"
+  "log/slog"
+  "net/http"
+  "net/http/httptest"
+  "testing"
+)
+
+func TestRequestID(t *testing.T) {
+  var engine = gin.New()
+  engine.Use(RequestID())
+
+  var seen string
+  engine.GET("/", func(c *gin.Context) {
+    seen = logging.RequestID(c.Request.Context())
+  })
+
+  t.Run("propagates a valid ID", func(t *testing.T) {
+    var request = httptest.NewRequest(http.MethodGet, "/", nil)
+    request.Header.Set(RequestIDHeader, "edge-1234.abc")
+    var response = httptest.NewRecorder()
+    engine.ServeHTTP(response, request)
+
+    assert.Equal(t, "edge-1234.abc", seen)
+    assert.Equal(t, "edge-1234.abc", response.Header().Get(RequestIDHeader))
+  })
+
+  for name, id := range map[string]string{"missing": "", "invalid": "<script>", "too long": string(bytes.Repeat([]byte("a"), 129))} {
+    t.Run("generates an ID when "+name, func(t *testing.T) {
+      var request = httptest.NewRequest(http.MethodGet, "/", nil)
+      if "" != id {
+        request.Header.Set(RequestIDHeader, id)
+      }
+      var response = httptest.NewRecorder()
+      engine.ServeHTTP(response, request)
+
+      assert.Len(t, seen, 36)
+      assert.NotEqual(t, id, seen)
+      assert.Equal(t, seen, response.Header().Get(RequestIDHeader))
+    })
+  }
+}
+
+func TestLog(t *testing.T) {
+  var buf bytes.Buffer
+  var previous = slog.Default()
+  slog.SetDefault(slog.New(logging.NewHandler(slog.NewJSONHandler(&buf, nil))))
+  defer slog.SetDefault(previous)
+
+  var engine = gin.New()
+  engine.Use(RequestID(), Log())
+  engine.GET("/archive.articles.info", func(c *gin.Context) {
+    slog.InfoContext(c.Request.Context(), "inside")
+    c.String(http.StatusOK, "hello")
+  })
+  engine.GET("/fail", func(c *gin.Context) {
+    c.Status(http.StatusInternalServerError)
+  })
+
+  var serve = func(target string) []map[string]any {
+    buf.Reset()
+    var request = httptest.NewRequest(http.MethodGet, target, nil)
+    request.Header.Set(RequestIDHeader, "req-1")
+    engine.ServeHTTP(httptest.NewRecorder(), request)
+
+    var records []map[string]any
+    for _, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+      var record map[string]any
+      require.NoError(t, json.Unmarshal(line, &record))
+      records = append(records, record)
+    }
+
+    return records
+  }
+
+  var records = serve("/archive.articles.info?article_uuid=x")
+  require.Len(t, records, 2)
+  assert.Equal(t, "inside", records[0]["msg"])
+  assert.Equal(t, "req-1", records[0]["request_id"])
+
+  var served = records[1]
+  assert.Equal(t, "INFO", served["level"])
+  assert.Equal(t, "req-1", served["request_id"])
+  assert.Equal(t, "GET", served["method"])
+  assert.Equal(t, "/archive.articles.info", served["path"])
+  assert.Equal(t, "/archive.articles.info", served["route"])
+  assert.EqualValues(t, http.StatusOK, served["status"])
+  assert.EqualValues(t, 5, served["bytes"])
+
+  records = serve("/fail")
+  require.Len(t, records, 1)
+  assert.Equal(t, "ERROR", records[0]["level"])
+  assert.EqualValues(t, http.StatusInternalServerError, records[0]["status"])
+}
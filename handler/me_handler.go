@@ -30,12 +30,20 @@ func (h *MeHandler) Get(c *gin.Context) {
     }
     return
   }
+  if freshRecord(c, me) {
+    return
+  }
+
   c.JSON(http.StatusOK, me)
 }
 
 func (h *MeHandler) SetPhoto(c *gin.Context) {
   var photoURL = c.PostForm("photo_url")
-  ok, err := h.s.Update(c, &transfer.MeUpdate{PhotoURL: photoURL})
+  version, ok := postFormVersion(c)
+  if !ok {
+    return
+  }
+  ok, err := h.s.Update(c, &transfer.MeUpdate{PhotoURL: photoURL, Version: version})
   if nil != err {
     var p *problem.Problem
     if errors.As(err, &p) {
@@ -55,7 +63,11 @@ func (h *MeHandler) SetPhoto(c *gin.Context) {
 
 func (h *MeHandler) SetResume(c *gin.Context) {
   var resumeURL = c.PostForm("resume_url")
-  ok, err := h.s.Update(c, &transfer.MeUpdate{ResumeURL: resumeURL})
+  version, ok := postFormVersion(c)
+  if !ok {
+    return
+  }
+  ok, err := h.s.Update(c, &transfer.MeUpdate{ResumeURL: resumeURL, Version: version})
   if nil != err {
     var p *problem.Problem
     if errors.As(err, &p) {
@@ -90,7 +102,11 @@ func (h *MeHandler) SetHireable(c *gin.Context) {
     return
   }
 
-  ok, err := h.s.Update(c, &transfer.MeUpdate{Hireable: hireable})
+  version, ok := postFormVersion(c)
+  if !ok {
+    return
+  }
+  ok, err = h.s.Update(c, &transfer.MeUpdate{Hireable: hireable, Version: version})
   if nil != err {
     var p *problem.Problem
     if errors.As(err, &p) {
@@ -115,6 +131,10 @@ func (h *MeHandler) Update(c *gin.Context) {
     return
   }
 
+  if !ifMatch(c, func() (any, error) { return h.s.Get(c) }) {
+    return
+  }
+
   ok, err := h.s.Update(c, &update)
   if nil != err {
     var p *problem.Problem
@@ -186,6 +186,7 @@ func TestMeHandler_Update(t *testing.T) {
       YouTubeURL:   "YouTubeURL",
       TwitterURL:   "TwitterURL",
       InstagramURL: "InstagramURL",
+      Version:      1,
     }
     var s = mocks.NewMeService()
     s.On(routine, mock.AnythingOfType("*gin.Context"), &update).Return(true, nil)
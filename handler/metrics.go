@@ -0,0 +1,32 @@
+package handler
+
+import (
+  "fontseca.dev/metrics"
+  "github.com/gin-gonic/gin"
+  "strconv"
+  "time"
+)
+
+// unmatchedRoute labels the metrics of the requests that match no
+// route, so that probing random paths can't grow the number of series.
+const unmatchedRoute = "unmatched"
+
+// Measure is a middleware that counts every request and measures how
+// long it takes to serve it, by route, method and status.
+func Measure() gin.HandlerFunc {
+  return func(c *gin.Context) {
+    var start = time.Now()
+
+    c.Next()
+
+    var route = c.FullPath()
+    if "" == route {
+      route = unmatchedRoute
+    }
+
+    var status = strconv.Itoa(c.Writer.Status())
+
+    metrics.Requests.WithLabelValues(route, c.Request.Method, status).Inc()
+    metrics.RequestDuration.WithLabelValues(route, c.Request.Method, status).Observe(time.Since(start).Seconds())
+  }
+}
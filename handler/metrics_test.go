@@ -0,0 +1,34 @@
+package handler
+
+import (
+  "fontseca.dev/metrics"
+  "github.com/gin-gonic/gin"
+  "github.com/prometheus/client_golang/prometheus/testutil"
+  "github.com/stretchr/testify/assert"
+  "net/http"
+  "net/http/httptest"
+  "testing"
+)
+
+func TestMeasure(t *testing.T) {
+  var engine = gin.New()
+  engine.Use(Measure())
+  engine.GET("/archive.articles.info", func(c *gin.Context) {
+    c.Status(http.StatusNotFound)
+  })
+
+  var serve = func(target string) {
+    engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+  }
+
+  var requests = metrics.Requests.WithLabelValues("/archive.articles.info", http.MethodGet, "404")
+  var unmatched = metrics.Requests.WithLabelValues(unmatchedRoute, http.MethodGet, "404")
+  var before, beforeUnmatched = testutil.ToFloat64(requests), testutil.ToFloat64(unmatched)
+
+  serve("/archive.articles.info?article_uuid=x")
+  serve("/archive.articles.info")
+  serve("/wp-login.php")
+
+  assert.Equal(t, before+2, testutil.ToFloat64(requests))
+  assert.Equal(t, beforeUnmatched+1, testutil.ToFloat64(unmatched))
+}
@@ -0,0 +1,143 @@
+package handler
+
+import (
+  "fmt"
+  "fontseca.dev/config"
+  "fontseca.dev/problem"
+  "fontseca.dev/transfer"
+  "github.com/gin-gonic/gin"
+  "net/http"
+  "strconv"
+  "strings"
+)
+
+const (
+  // defaultLimit is the number of items of a list page when the
+  // 'limit' parameter is not given.
+  defaultLimit = 20
+
+  // maxLimit is the maximum number of items of a list page.
+  maxLimit = config.MaxPageSize
+)
+
+// getPagination extracts the 'cursor' and 'limit' query parameters of
+// c and returns the page of a list they ask for. If any of them is
+// invalid, it emits a problem and returns false.
+func getPagination(c *gin.Context) (page *transfer.PageRequest, ok bool) {
+  page = &transfer.PageRequest{Limit: defaultLimit}
+
+  if value, given := c.GetQuery("limit"); given {
+    n, err := strconv.Atoi(value)
+
+    switch {
+    case nil != err:
+      problem.NewUnparsableValue("integer", "limit", value).Emit(c.Writer)
+      return nil, false
+    case 1 > n || maxLimit < n:
+      problem.NewValueOutOfRange(fmt.Sprintf("integer between 1 and %d", maxLimit), "limit", value).Emit(c.Writer)
+      return nil, false
+    }
+
+    page.Limit = n
+  }
+
+  if value := c.Query("cursor"); "" != value {
+    cursor, err := transfer.ParseCursor(value)
+    if nil != err {
+      problem.NewInvalidCursor(value).Emit(c.Writer)
+      return nil, false
+    }
+
+    page.After = cursor
+  }
+
+  return page, true
+}
+
+// pageURL returns the URL of the current request with its 'cursor' and
+// 'limit' query parameters replaced.
+func pageURL(c *gin.Context, cursor string, limit int) string {
+  var u = *c.Request.URL
+  var query = u.Query()
+
+  query.Del("cursor")
+  query.Del("page")
+  query.Set("limit", strconv.Itoa(limit))
+
+  if "" != cursor {
+    query.Set("cursor", cursor)
+  }
+
+  u.RawQuery = query.Encode()
+
+  return u.RequestURI()
+}
+
+// emitPage sends page as the response to c, along with a 'Link' header
+// that points to the first page of the list and to the next one, if any.
+func emitPage[T any](c *gin.Context, page *transfer.Page[T], limit int) {
+  if nil == page.Items {
+    page.Items = make([]T, 0)
+  }
+
+  var links = []string{fmt.Sprintf("<%s>; rel=\"first\"", pageURL(c, "", limit))}
+
+  if "" != page.NextCursor {
+    links = append(links, fmt.Sprintf("<%s>; rel=\"next\"", pageURL(c, page.NextCursor, limit)))
+  }
+
+  c.Header("Link", strings.Join(links, ", "))
+
+  if freshPage(c, page) {
+    return
+  }
+
+  c.JSON(http.StatusOK, page)
+}
+
+// getPagedArticleFilter is like getArticleFilter, but it also reads the
+// 'cursor' and 'limit' parameters. The 'rpp' parameter is still honored
+// when 'limit' is not given. If the filter asks for the first page or
+// for a cursor, it retrieves one extra article to know whether there
+// is a next page.
+func getPagedArticleFilter(c *gin.Context, pageSize int) (filter *transfer.ArticleFilter, ok bool) {
+  page, ok := getPagination(c)
+  if !ok {
+    return nil, false
+  }
+
+  filter = getArticleFilter(c, pageSize)
+
+  if _, given := c.GetQuery("limit"); given {
+    filter.RPP = page.Limit
+  }
+
+  filter.RPP = min(filter.RPP, maxLimit)
+  filter.After = page.After
+
+  if 1 == filter.Page || nil != filter.After {
+    filter.RPP++
+  }
+
+  return filter, true
+}
+
+// emitArticlesPage sends the page of articles retrieved for a filter
+// made by getPagedArticleFilter.
+func emitArticlesPage(c *gin.Context, filter *transfer.ArticleFilter, articles []*transfer.Article, total int) {
+  var page = &transfer.Page[*transfer.Article]{Items: articles, Total: total}
+  var limit = filter.RPP
+
+  if 1 == filter.Page || nil != filter.After {
+    limit-- // the extra article only tells whether there is a next page
+
+    if limit < len(articles) {
+      page.Items = articles[:limit]
+      page.NextCursor = articles[limit-1].Cursor().String()
+    }
+  } else if filter.Page*limit < total && 0 < len(articles) {
+    page.NextCursor = articles[len(articles)-1].Cursor().String()
+  }
+
+  emitPage(c, page, limit)
+}
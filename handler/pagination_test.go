@@ -0,0 +1,192 @@
+package handler
+
+import (
+  "encoding/base64"
+  "encoding/json"
+  "fontseca.dev/model"
+  "fontseca.dev/transfer"
+  "github.com/gin-gonic/gin"
+  "github.com/google/uuid"
+  "github.com/stretchr/testify/assert"
+  "github.com/stretchr/testify/require"
+  "net/http"
+  "net/http/httptest"
+  "net/url"
+  "strings"
+  "testing"
+  "time"
+)
+
+func TestGetPagination(t *testing.T) {
+  const target = "/archive.topics.list"
+
+  serve := func(query string) (*httptest.ResponseRecorder, *transfer.PageRequest) {
+    var page *transfer.PageRequest
+
+    engine := gin.Default()
+    engine.GET(target, func(c *gin.Context) {
+      if p, ok := getPagination(c); ok {
+        page = p
+        emitPage(c, &transfer.Page[*model.Topic]{NextCursor: "next", Total: 5}, p.Limit)
+      }
+    })
+
+    recorder := httptest.NewRecorder()
+    engine.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, target+query, nil))
+
+    return recorder, page
+  }
+
+  t.Run("default limit", func(t *testing.T) {
+    recorder, page := serve("")
+    require.Equal(t, http.StatusOK, recorder.Code)
+    assert.Equal(t, defaultLimit, page.Limit)
+    assert.Nil(t, page.After)
+    assert.JSONEq(t, `{"items":[],"next_cursor":"next","total":5}`, recorder.Body.String())
+  })
+
+  t.Run("cursor and limit", func(t *testing.T) {
+    cursor := &transfer.Cursor{Key: []string{"Go"}, ID: "go"}
+
+    recorder, page := serve("?limit=2&cursor=" + url.QueryEscape(cursor.String()))
+    require.Equal(t, http.StatusOK, recorder.Code)
+    assert.Equal(t, 2, page.Limit)
+    assert.Equal(t, cursor, page.After)
+    assert.Equal(t, `</archive.topics.list?limit=2>; rel="first", </archive.topics.list?cursor=next&limit=2>; rel="next"`, recorder.Header().Get("Link"))
+  })
+
+  t.Run("invalid parameters", func(t *testing.T) {
+    for query, expectedStatusCode := range map[string]int{
+      "?limit=x":    http.StatusUnprocessableEntity,
+      "?limit=0":    http.StatusUnprocessableEntity,
+      "?limit=101":  http.StatusUnprocessableEntity,
+      "?cursor=***": http.StatusBadRequest,
+      "?cursor=" + base64.RawURLEncoding.EncodeToString([]byte(`{"k":["Go"]}`)): http.StatusBadRequest,
+    } {
+      recorder, _ := serve(query)
+      assert.Equal(t, expectedStatusCode, recorder.Code, query)
+      assert.Contains(t, recorder.Result().Header.Get("Content-Type"), "application/problem+json")
+    }
+  })
+}
+
+func TestEmitPage(t *testing.T) {
+  const target = "/archive.topics.list"
+
+  var (
+    older = time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+    newer = older.Add(time.Hour)
+    page  = &transfer.Page[*model.Topic]{
+      Items: []*model.Topic{{ID: "go", Name: "Go", UpdatedAt: newer}, {ID: "rust", Name: "Rust", UpdatedAt: older}},
+      Total: 2,
+    }
+  )
+
+  serve := func(header http.Header) *httptest.ResponseRecorder {
+    engine := gin.New()
+    engine.Use(Conditional())
+    engine.GET(target, func(c *gin.Context) {
+      emitPage(c, page, defaultLimit)
+    })
+
+    request := httptest.NewRequest(http.MethodGet, target, nil)
+    for name, values := range header {
+      request.Header[name] = values
+    }
+
+    recorder := httptest.NewRecorder()
+    engine.ServeHTTP(recorder, request)
+
+    return recorder
+  }
+
+  recorder := serve(nil)
+  require.Equal(t, http.StatusOK, recorder.Code)
+  assert.Equal(t, "Fri, 01 Mar 2024 13:00:00 GMT", recorder.Header().Get("Last-Modified"))
+
+  etag := recorder.Header().Get("ETag")
+  assert.True(t, strings.HasPrefix(etag, `W/"`), etag)
+
+  recorder = serve(http.Header{"If-None-Match": {etag}})
+  assert.Equal(t, http.StatusNotModified, recorder.Code)
+  assert.Empty(t, recorder.Body.String())
+
+  recorder = serve(http.Header{"If-Modified-Since": {"Fri, 01 Mar 2024 13:00:00 GMT"}})
+  assert.Equal(t, http.StatusNotModified, recorder.Code)
+
+  page.Items[1].Name = "Rust lang"
+  recorder = serve(http.Header{"If-None-Match": {etag}})
+  assert.Equal(t, http.StatusOK, recorder.Code)
+  assert.NotEqual(t, etag, recorder.Header().Get("ETag"))
+}
+
+func TestGetPagedArticleFilter(t *testing.T) {
+  const target = "/archive.articles.list"
+
+  articles := make([]*transfer.Article, 4)
+  for i := range articles {
+    articles[i] = &transfer.Article{UUID: uuid.New()}
+  }
+
+  serve := func(query string, handle func(c *gin.Context, filter *transfer.ArticleFilter)) *httptest.ResponseRecorder {
+    engine := gin.Default()
+    engine.GET(target, func(c *gin.Context) {
+      if filter, ok := getPagedArticleFilter(c, pageSize); ok {
+        handle(c, filter)
+      }
+    })
+
+    recorder := httptest.NewRecorder()
+    engine.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, target+query, nil))
+
+    return recorder
+  }
+
+  t.Run("first page", func(t *testing.T) {
+    recorder := serve("?limit=3", func(c *gin.Context, filter *transfer.ArticleFilter) {
+      assert.Equal(t, 4, filter.RPP)
+      assert.Nil(t, filter.After)
+      emitArticlesPage(c, filter, articles, 10)
+    })
+
+    var page transfer.Page[*transfer.Article]
+    require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &page))
+    assert.Len(t, page.Items, 3)
+    assert.Equal(t, 10, page.Total)
+    assert.Equal(t, articles[2].Cursor().String(), page.NextCursor)
+  })
+
+  t.Run("page after cursor", func(t *testing.T) {
+    cursor := articles[2].Cursor()
+
+    recorder := serve("?limit=3&cursor="+cursor.String(), func(c *gin.Context, filter *transfer.ArticleFilter) {
+      assert.Equal(t, cursor, filter.After)
+      emitArticlesPage(c, filter, articles[3:], 10)
+    })
+
+    var page transfer.Page[*transfer.Article]
+    require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &page))
+    assert.Len(t, page.Items, 1)
+    assert.Empty(t, page.NextCursor)
+  })
+
+  t.Run("legacy page and rpp", func(t *testing.T) {
+    recorder := serve("?page=2&rpp=2", func(c *gin.Context, filter *transfer.ArticleFilter) {
+      assert.Equal(t, 2, filter.RPP)
+      emitArticlesPage(c, filter, articles[2:], 10)
+    })
+
+    var page transfer.Page[*transfer.Article]
+    require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &page))
+    assert.Len(t, page.Items, 2)
+    assert.Equal(t, articles[3].Cursor().String(), page.NextCursor)
+  })
+
+  t.Run("invalid cursor", func(t *testing.T) {
+    recorder := serve("?cursor="+url.QueryEscape(articles[0].UUID.String()), func(c *gin.Context, filter *transfer.ArticleFilter) {
+      t.Fatal("unexpected call")
+    })
+
+    assert.Equal(t, http.StatusBadRequest, recorder.Code)
+  })
+}
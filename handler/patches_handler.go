@@ -17,13 +17,28 @@ func NewPatchesHandler(patches service.PatchesService) *PatchesHandler {
 }
 
 func (h *PatchesHandler) Get(c *gin.Context) {
-  patches, err := h.patches.Get(c)
+  page, ok := getPagination(c)
+  if !ok {
+    return
+  }
+
+  patches, err := h.patches.List(c, page)
+
+  if check(err, c.Writer) {
+    return
+  }
+
+  emitPage(c, patches, page.Limit)
+}
+
+func (h *PatchesHandler) Diff(c *gin.Context) {
+  d, err := h.patches.Diff(c, c.Query("patch_uuid"))
 
   if check(err, c.Writer) {
     return
   }
 
-  c.JSON(http.StatusOK, patches)
+  c.JSON(http.StatusOK, d)
 }
 
 func (h *PatchesHandler) Revise(c *gin.Context) {
@@ -40,6 +55,10 @@ func (h *PatchesHandler) Revise(c *gin.Context) {
     return
   }
 
+  if err := validateStruct(&revision); check(err, c.Writer) {
+    return
+  }
+
   if err := h.patches.Revise(c, patch, &revision); check(err, c.Writer) {
     return
   }
@@ -17,7 +17,7 @@ import (
 
 func TestPatchesHandler_Get(t *testing.T) {
   const (
-    routine = "Get"
+    routine = "List"
     method  = http.MethodGet
     target  = "/archive.articles.patches.list"
   )
@@ -27,10 +27,10 @@ func TestPatchesHandler_Get(t *testing.T) {
 
   t.Run("success", func(t *testing.T) {
     expectedStatusCode := http.StatusOK
-    expectedBody := string(marshal(t, patches))
+    expectedBody := string(marshal(t, transfer.Page[*model.ArticlePatch]{Items: patches, Total: len(patches)}))
 
     s := mocks.NewPatchesService()
-    s.On(routine, mock.AnythingOfType("*gin.Context")).Return(patches, nil)
+    s.On(routine, mock.AnythingOfType("*gin.Context"), &transfer.PageRequest{Limit: defaultLimit}).Return(&transfer.Page[*model.ArticlePatch]{Items: patches, Total: len(patches)}, nil)
 
     engine := gin.Default()
     engine.GET(target, NewPatchesHandler(s).Get)
@@ -53,7 +53,7 @@ func TestPatchesHandler_Get(t *testing.T) {
     expected.Detail(expectBodyContains)
 
     s := mocks.NewPatchesService()
-    s.On(routine, mock.AnythingOfType("*gin.Context")).Return(nil, expected)
+    s.On(routine, mock.AnythingOfType("*gin.Context"), &transfer.PageRequest{Limit: defaultLimit}).Return(nil, expected)
 
     engine := gin.Default()
     engine.GET(target, NewPatchesHandler(s).Get)
@@ -74,7 +74,7 @@ func TestPatchesHandler_Get(t *testing.T) {
     expectBodyContains := "An unexpected error occurred while processing your request"
 
     s := mocks.NewPatchesService()
-    s.On(routine, mock.AnythingOfType("*gin.Context")).Return(nil, unexpected)
+    s.On(routine, mock.AnythingOfType("*gin.Context"), &transfer.PageRequest{Limit: defaultLimit}).Return(nil, unexpected)
 
     engine := gin.Default()
     engine.GET(target, NewPatchesHandler(s).Get)
@@ -90,6 +90,82 @@ func TestPatchesHandler_Get(t *testing.T) {
   })
 }
 
+func TestPatchesHandler_Diff(t *testing.T) {
+  const (
+    routine = "Diff"
+    method  = http.MethodGet
+    target  = "/archive.articles.patches.diff"
+  )
+
+  id := uuid.NewString()
+  request := httptest.NewRequest(method, target+"?patch_uuid="+id, nil)
+  d := &transfer.ArticlePatchDiff{PatchUUID: uuid.MustParse(id)}
+
+  t.Run("success", func(t *testing.T) {
+    expectedStatusCode := http.StatusOK
+    expectedBody := string(marshal(t, d))
+
+    s := mocks.NewPatchesService()
+    s.On(routine, mock.AnythingOfType("*gin.Context"), id).Return(d, nil)
+
+    engine := gin.Default()
+    engine.GET(target, NewPatchesHandler(s).Diff)
+
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, request)
+
+    assert.Equal(t, expectedStatusCode, recorder.Code)
+    assert.Equal(t, expectedBody, recorder.Body.String())
+    assert.Empty(t, recorder.Result().Cookies())
+  })
+
+  t.Run("expected problem detail", func(t *testing.T) {
+    expectedStatusCode := http.StatusBadRequest
+    expectBodyContains := "Expected problem detail."
+
+    expected := &problem.Problem{}
+    expected.Status(expectedStatusCode)
+    expected.Detail(expectBodyContains)
+
+    s := mocks.NewPatchesService()
+    s.On(routine, mock.AnythingOfType("*gin.Context"), id).Return(nil, expected)
+
+    engine := gin.Default()
+    engine.GET(target, NewPatchesHandler(s).Diff)
+
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, request)
+
+    assert.Equal(t, expectedStatusCode, recorder.Code)
+    assert.Contains(t, recorder.Body.String(), expectBodyContains)
+    assert.Empty(t, recorder.Result().Cookies())
+    assert.Contains(t, recorder.Result().Header.Get("Content-Type"), "application/problem+json")
+  })
+
+  t.Run("unexpected error", func(t *testing.T) {
+    unexpected := errors.New("unexpected error")
+    expectedStatusCode := http.StatusInternalServerError
+    expectBodyContains := "An unexpected error occurred while processing your request"
+
+    s := mocks.NewPatchesService()
+    s.On(routine, mock.AnythingOfType("*gin.Context"), id).Return(nil, unexpected)
+
+    engine := gin.Default()
+    engine.GET(target, NewPatchesHandler(s).Diff)
+
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, request)
+
+    assert.Equal(t, expectedStatusCode, recorder.Code)
+    assert.Contains(t, recorder.Body.String(), expectBodyContains)
+    assert.Empty(t, recorder.Result().Cookies())
+    assert.Contains(t, recorder.Result().Header.Get("Content-Type"), "application/problem+json")
+  })
+}
+
 func TestPatchesHandler_Revise(t *testing.T) {
   const (
     routine = "Revise"
@@ -100,6 +176,7 @@ func TestPatchesHandler_Revise(t *testing.T) {
   revision := &transfer.ArticleRevision{
     Title:   "Title",
     Content: "Content",
+    Version: 3,
   }
 
   id := uuid.NewString()
@@ -110,6 +187,7 @@ func TestPatchesHandler_Revise(t *testing.T) {
   request.PostForm.Add("patch_uuid", id)
   request.PostForm.Add("title", revision.Title)
   request.PostForm.Add("content", revision.Content)
+  request.PostForm.Add("version", "3")
 
   t.Run("success", func(t *testing.T) {
     expectedStatusCode := http.StatusNoContent
@@ -19,19 +19,27 @@ func NewProjectsHandler(service service.ProjectsService) *ProjectsHandler {
 }
 
 func (h *ProjectsHandler) Get(c *gin.Context) {
-  var projects, err = h.s.Get(c)
+  var page, ok = getPagination(c)
+  if !ok {
+    return
+  }
+  var projects, err = h.s.List(c, false, page)
   if check(err, c.Writer) {
     return
   }
-  c.JSON(http.StatusOK, projects)
+  emitPage(c, projects, page.Limit)
 }
 
 func (h *ProjectsHandler) GetArchived(c *gin.Context) {
-  var projects, err = h.s.Get(c, true)
+  var page, ok = getPagination(c)
+  if !ok {
+    return
+  }
+  var projects, err = h.s.List(c, true, page)
   if check(err, c.Writer) {
     return
   }
-  c.JSON(http.StatusOK, projects)
+  emitPage(c, projects, page.Limit)
 }
 
 func (h *ProjectsHandler) GetByID(c *gin.Context) {
@@ -40,6 +48,10 @@ func (h *ProjectsHandler) GetByID(c *gin.Context) {
   if check(err, c.Writer) {
     return
   }
+  if freshRecord(c, project) {
+    return
+  }
+
   c.JSON(http.StatusOK, project)
 }
 
@@ -71,6 +83,9 @@ func (h *ProjectsHandler) Set(c *gin.Context) {
   if err := validateStruct(&update); check(err, c.Writer) {
     return
   }
+  if !ifMatch(c, func() (any, error) { return h.s.GetByID(c, id) }) {
+    return
+  }
   var updated, err = h.s.Update(c, id, &update)
   if check(err, c.Writer) {
     return
@@ -88,7 +103,11 @@ func (h *ProjectsHandler) Archive(c *gin.Context) {
     problem.NewMissingParameter("id").Emit(c.Writer)
     return
   }
-  _, err := h.s.Update(c, id, &transfer.ProjectUpdate{Archived: true})
+  version, ok := postFormVersion(c)
+  if !ok {
+    return
+  }
+  _, err := h.s.Update(c, id, &transfer.ProjectUpdate{Archived: true, Version: version})
   if check(err, c.Writer) {
     return
   }
@@ -101,7 +120,11 @@ func (h *ProjectsHandler) Unarchive(c *gin.Context) {
     problem.NewMissingParameter("id").Emit(c.Writer)
     return
   }
-  var unarchived, err = h.s.Unarchive(c, id)
+  version, ok := postFormVersion(c)
+  if !ok {
+    return
+  }
+  var unarchived, err = h.s.Unarchive(c, id, version)
   if check(err, c.Writer) {
     return
   }
@@ -118,7 +141,11 @@ func (h *ProjectsHandler) Finish(c *gin.Context) {
     problem.NewMissingParameter("id").Emit(c.Writer)
     return
   }
-  var updated, err = h.s.Update(c, id, &transfer.ProjectUpdate{Finished: true})
+  version, ok := postFormVersion(c)
+  if !ok {
+    return
+  }
+  var updated, err = h.s.Update(c, id, &transfer.ProjectUpdate{Finished: true, Version: version})
   if check(err, c.Writer) {
     return
   }
@@ -135,7 +162,11 @@ func (h *ProjectsHandler) Unfinish(c *gin.Context) {
     problem.NewMissingParameter("id").Emit(c.Writer)
     return
   }
-  var updated, err = h.s.Update(c, id, &transfer.ProjectUpdate{Finished: false})
+  version, ok := postFormVersion(c)
+  if !ok {
+    return
+  }
+  var updated, err = h.s.Update(c, id, &transfer.ProjectUpdate{Finished: false, Version: version})
   if check(err, c.Writer) {
     return
   }
@@ -146,18 +177,21 @@ func (h *ProjectsHandler) Unfinish(c *gin.Context) {
   }
 }
 
-func (h *ProjectsHandler) getIDAndURLParameters(c *gin.Context) (id string, url string, ok bool) {
+func (h *ProjectsHandler) getIDAndURLParameters(c *gin.Context) (id string, url string, version int, ok bool) {
   id, success := c.GetPostForm("id")
   if !success {
     problem.NewMissingParameter("id").Emit(c.Writer)
-    return "", "", false
+    return "", "", 0, false
   }
   url, success = c.GetPostForm("url")
   if !success {
     problem.NewMissingParameter("url").Emit(c.Writer)
-    return "", "", false
+    return "", "", 0, false
+  }
+  if version, ok = postFormVersion(c); !ok {
+    return "", "", 0, false
   }
-  return id, url, true
+  return id, url, version, true
 }
 
 func (h *ProjectsHandler) setURL(c *gin.Context, id string, update *transfer.ProjectUpdate) {
@@ -173,43 +207,43 @@ func (h *ProjectsHandler) setURL(c *gin.Context, id string, update *transfer.Pro
 }
 
 func (h *ProjectsHandler) SetPlaygroundURL(c *gin.Context) {
-  id, url, ok := h.getIDAndURLParameters(c)
+  id, url, version, ok := h.getIDAndURLParameters(c)
   if !ok {
     return
   }
-  h.setURL(c, id, &transfer.ProjectUpdate{PlaygroundURL: url})
+  h.setURL(c, id, &transfer.ProjectUpdate{PlaygroundURL: url, Version: version})
 }
 
 func (h *ProjectsHandler) SetFirstImageURL(c *gin.Context) {
-  id, url, ok := h.getIDAndURLParameters(c)
+  id, url, version, ok := h.getIDAndURLParameters(c)
   if !ok {
     return
   }
-  h.setURL(c, id, &transfer.ProjectUpdate{FirstImageURL: url})
+  h.setURL(c, id, &transfer.ProjectUpdate{FirstImageURL: url, Version: version})
 }
 
 func (h *ProjectsHandler) SetSecondImageURL(c *gin.Context) {
-  id, url, ok := h.getIDAndURLParameters(c)
+  id, url, version, ok := h.getIDAndURLParameters(c)
   if !ok {
     return
   }
-  h.setURL(c, id, &transfer.ProjectUpdate{SecondImageURL: url})
+  h.setURL(c, id, &transfer.ProjectUpdate{SecondImageURL: url, Version: version})
 }
 
 func (h *ProjectsHandler) SetGitHubURL(c *gin.Context) {
-  id, url, ok := h.getIDAndURLParameters(c)
+  id, url, version, ok := h.getIDAndURLParameters(c)
   if !ok {
     return
   }
-  h.setURL(c, id, &transfer.ProjectUpdate{GitHubURL: url})
+  h.setURL(c, id, &transfer.ProjectUpdate{GitHubURL: url, Version: version})
 }
 
 func (h *ProjectsHandler) SetCollectionURL(c *gin.Context) {
-  id, url, ok := h.getIDAndURLParameters(c)
+  id, url, version, ok := h.getIDAndURLParameters(c)
   if !ok {
     return
   }
-  h.setURL(c, id, &transfer.ProjectUpdate{CollectionURL: url})
+  h.setURL(c, id, &transfer.ProjectUpdate{CollectionURL: url, Version: version})
 }
 
 func (h *ProjectsHandler) Remove(c *gin.Context) {
@@ -18,40 +18,40 @@ import (
 )
 
 func TestProjectsHandler_Get(t *testing.T) {
-  const routine = "Get"
+  const routine = "List"
   const method = http.MethodGet
   const target = "/me.projects.list"
 
   t.Run("success", func(t *testing.T) {
     var projects = make([]*model.Project, 0)
     var s = mocks.NewProjectsService()
-    s.On(routine, mock.AnythingOfType("*gin.Context"), []bool(nil)).Return(projects, nil)
+    s.On(routine, mock.AnythingOfType("*gin.Context"), false, &transfer.PageRequest{Limit: defaultLimit}).Return(&transfer.Page[*model.Project]{Items: projects}, nil)
     var engine = gin.Default()
     engine.GET(target, NewProjectsHandler(s).Get)
     var request = httptest.NewRequest(method, target, nil)
     var recorder = httptest.NewRecorder()
     engine.ServeHTTP(recorder, request)
     assert.Equal(t, http.StatusOK, recorder.Code)
-    assert.Equal(t, string(marshal(t, projects)), recorder.Body.String())
+    assert.Equal(t, string(marshal(t, transfer.Page[*model.Project]{Items: projects, Total: len(projects)})), recorder.Body.String())
   })
 }
 
 func TestProjectsHandler_GetArchived(t *testing.T) {
-  const routine = "Get"
+  const routine = "List"
   const method = http.MethodGet
   const target = "/me.projects.hidden.list"
 
   t.Run("success", func(t *testing.T) {
     var projects = make([]*model.Project, 0)
     var s = mocks.NewProjectsService()
-    s.On(routine, mock.AnythingOfType("*gin.Context"), []bool{true}).Return(projects, nil)
+    s.On(routine, mock.AnythingOfType("*gin.Context"), true, &transfer.PageRequest{Limit: defaultLimit}).Return(&transfer.Page[*model.Project]{Items: projects}, nil)
     var engine = gin.Default()
     engine.GET(target, NewProjectsHandler(s).GetArchived)
     var request = httptest.NewRequest(method, target, nil)
     var recorder = httptest.NewRecorder()
     engine.ServeHTTP(recorder, request)
     assert.Equal(t, http.StatusOK, recorder.Code)
-    assert.Equal(t, string(marshal(t, projects)), recorder.Body.String())
+    assert.Equal(t, string(marshal(t, transfer.Page[*model.Project]{Items: projects, Total: len(projects)})), recorder.Body.String())
   })
 }
 
@@ -178,6 +178,7 @@ func TestProjectsHandler_Set(t *testing.T) {
     Summary:       "Summary.",
     Content:       "Content.",
     EstimatedTime: 1,
+    Version:       2,
   }
   var request = httptest.NewRequest(method, target, nil)
   _ = request.ParseForm()
@@ -187,6 +188,7 @@ func TestProjectsHandler_Set(t *testing.T) {
   request.PostForm.Add("summary", update.Summary)
   request.PostForm.Add("content", update.Content)
   request.PostForm.Add("estimated_time", "1")
+  request.PostForm.Add("version", "2")
 
   t.Run("missing 'id' parameter", func(t *testing.T) {
     var s = mocks.NewProjectsService()
@@ -331,10 +333,24 @@ func TestProjectsHandler_Unarchive(t *testing.T) {
 
   var id = uuid.New().String()
   request.PostForm.Add("id", id)
+  request.PostForm.Add("version", "x")
+
+  t.Run("unparsable 'version' parameter", func(t *testing.T) {
+    var s = mocks.NewProjectsService()
+    s.AssertNotCalled(t, routine)
+    var engine = gin.Default()
+    engine.POST(target, NewProjectsHandler(s).Unarchive)
+    var recorder = httptest.NewRecorder()
+    engine.ServeHTTP(recorder, request)
+    assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+    assert.Contains(t, recorder.Body.String(), "Failure when parsing int value.")
+  })
+
+  request.PostForm.Set("version", "3")
 
   t.Run("success", func(t *testing.T) {
     var s = mocks.NewProjectsService()
-    s.On(routine, mock.AnythingOfType("*gin.Context"), id).Return(true, nil)
+    s.On(routine, mock.AnythingOfType("*gin.Context"), id, 3).Return(true, nil)
     var engine = gin.Default()
     engine.POST(target, NewProjectsHandler(s).Unarchive)
     var recorder = httptest.NewRecorder()
@@ -345,7 +361,7 @@ func TestProjectsHandler_Unarchive(t *testing.T) {
 
   t.Run("failed update without error", func(t *testing.T) {
     var s = mocks.NewProjectsService()
-    s.On(routine, mock.AnythingOfType("*gin.Context"), id).Return(false, nil)
+    s.On(routine, mock.AnythingOfType("*gin.Context"), id, 3).Return(false, nil)
     var engine = gin.Default()
     engine.POST(target, NewProjectsHandler(s).Unarchive)
     var recorder = httptest.NewRecorder()
@@ -0,0 +1,31 @@
+package handler
+
+import (
+  "bytes"
+  "fmt"
+  "fontseca.dev/service"
+  "github.com/gin-gonic/gin"
+  "net/http"
+  "time"
+)
+
+type SiteHandler struct {
+  site service.SiteService
+}
+
+func NewSiteHandler(site service.SiteService) *SiteHandler {
+  return &SiteHandler{site}
+}
+
+func (h *SiteHandler) Export(c *gin.Context) {
+  var buf bytes.Buffer
+
+  if check(h.site.Export(c, &buf), c.Writer) {
+    return
+  }
+
+  var name = fmt.Sprintf("fontseca.dev-%s.tar.gz", time.Now().Format("20060102"))
+
+  c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+  c.Data(http.StatusOK, "application/gzip", buf.Bytes())
+}
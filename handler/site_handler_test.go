@@ -0,0 +1,151 @@
+package handler
+
+import (
+  "errors"
+  "fontseca.dev/mocks"
+  "fontseca.dev/problem"
+  "github.com/gin-gonic/gin"
+  "github.com/stretchr/testify/assert"
+  "github.com/stretchr/testify/mock"
+  "io"
+  "net/http"
+  "net/http/httptest"
+  "testing"
+)
+
+func TestSiteHandler_Export(t *testing.T) {
+  const (
+    routine = "Export"
+    method  = http.MethodGet
+    target  = "/site.export"
+    token   = "s3cr3t"
+  )
+
+  newRequest := func(authorization string) *http.Request {
+    request := httptest.NewRequest(method, target, nil)
+    if "" != authorization {
+      request.Header.Set("Authorization", authorization)
+    }
+    return request
+  }
+
+  t.Run("success", func(t *testing.T) {
+    expectedStatusCode := http.StatusOK
+    expectedBody := "archive"
+
+    s := mocks.NewSiteService()
+    s.On(routine, mock.AnythingOfType("*gin.Context"), mock.Anything).
+      Run(func(args mock.Arguments) {
+        _, _ = io.WriteString(args.Get(1).(io.Writer), expectedBody)
+      }).
+      Return(nil)
+
+    engine := gin.Default()
+    engine.GET(target, RequireToken(token, nil), NewSiteHandler(s).Export)
+
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, newRequest("Bearer "+token))
+
+    assert.Equal(t, expectedStatusCode, recorder.Code)
+    assert.Equal(t, expectedBody, recorder.Body.String())
+    assert.Equal(t, "application/gzip", recorder.Header().Get("Content-Type"))
+    assert.Contains(t, recorder.Header().Get("Content-Disposition"), ".tar.gz")
+    assert.Empty(t, recorder.Result().Cookies())
+  })
+
+  t.Run("unauthorized", func(t *testing.T) {
+    for name, setup := range map[string]struct{ token, authorization string }{
+      "missing token":   {token, ""},
+      "wrong token":     {token, "Bearer wrong"},
+      "wrong scheme":    {token, "Basic " + token},
+      "no server token": {"", "Bearer "},
+    } {
+      t.Run(name, func(t *testing.T) {
+        expectedStatusCode := http.StatusUnauthorized
+
+        s := mocks.NewSiteService()
+        s.AssertNotCalled(t, routine)
+
+        engine := gin.Default()
+        engine.GET(target, RequireToken(setup.token, nil), NewSiteHandler(s).Export)
+
+        recorder := httptest.NewRecorder()
+
+        engine.ServeHTTP(recorder, newRequest(setup.authorization))
+
+        assert.Equal(t, expectedStatusCode, recorder.Code)
+        assert.Contains(t, recorder.Header().Get("WWW-Authenticate"), "Bearer")
+        assert.Contains(t, recorder.Result().Header.Get("Content-Type"), "application/problem+json")
+      })
+    }
+  })
+
+  t.Run("stored token", func(t *testing.T) {
+    s := mocks.NewSiteService()
+    s.On(routine, mock.AnythingOfType("*gin.Context"), mock.Anything).Return(nil)
+
+    tokens := mocks.NewTokensService()
+    tokens.On("Check", mock.Anything, "stored").Return(true, nil)
+    tokens.On("Check", mock.Anything, mock.Anything).Return(false, nil)
+
+    engine := gin.Default()
+    engine.GET(target, RequireToken("", tokens), NewSiteHandler(s).Export)
+
+    recorder := httptest.NewRecorder()
+    engine.ServeHTTP(recorder, newRequest("Bearer stored"))
+    assert.Equal(t, http.StatusOK, recorder.Code)
+
+    recorder = httptest.NewRecorder()
+    engine.ServeHTTP(recorder, newRequest("Bearer revoked"))
+    assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+
+    tokens = mocks.NewTokensService()
+    tokens.On("Check", mock.Anything, mock.Anything).Return(false, errors.New("unexpected error"))
+
+    engine = gin.Default()
+    engine.GET(target, RequireToken("", tokens), NewSiteHandler(s).Export)
+
+    recorder = httptest.NewRecorder()
+    engine.ServeHTTP(recorder, newRequest("Bearer stored"))
+    assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+  })
+
+  t.Run("expected problem detail", func(t *testing.T) {
+    expectedStatusCode := http.StatusUnprocessableEntity
+
+    p := problem.Problem{}
+    p.Status(expectedStatusCode)
+    p.Title("Could not restore record.")
+
+    s := mocks.NewSiteService()
+    s.On(routine, mock.AnythingOfType("*gin.Context"), mock.Anything).Return(&p)
+
+    engine := gin.Default()
+    engine.GET(target, RequireToken(token, nil), NewSiteHandler(s).Export)
+
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, newRequest("Bearer "+token))
+
+    assert.Equal(t, expectedStatusCode, recorder.Code)
+    assert.Contains(t, recorder.Result().Header.Get("Content-Type"), "application/problem+json")
+  })
+
+  t.Run("unexpected error", func(t *testing.T) {
+    expectedStatusCode := http.StatusInternalServerError
+
+    s := mocks.NewSiteService()
+    s.On(routine, mock.AnythingOfType("*gin.Context"), mock.Anything).Return(errors.New("unexpected error"))
+
+    engine := gin.Default()
+    engine.GET(target, RequireToken(token, nil), NewSiteHandler(s).Export)
+
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, newRequest("Bearer "+token))
+
+    assert.Equal(t, expectedStatusCode, recorder.Code)
+    assert.Contains(t, recorder.Result().Header.Get("Content-Type"), "application/problem+json")
+  })
+}
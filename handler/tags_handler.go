@@ -35,13 +35,32 @@ func (h *TagsHandler) Add(c *gin.Context) {
 }
 
 func (h *TagsHandler) Get(c *gin.Context) {
-  tags, err := h.tags.Get(c)
+  page, ok := getPagination(c)
+  if !ok {
+    return
+  }
+
+  tags, err := h.tags.List(c, page)
 
   if check(err, c.Writer) {
     return
   }
 
-  c.JSON(http.StatusOK, tags)
+  emitPage(c, tags, page.Limit)
+}
+
+func (h *TagsHandler) GetByID(c *gin.Context) {
+  tag, err := h.tags.GetByID(c, c.Query("tag_id"))
+
+  if check(err, c.Writer) {
+    return
+  }
+
+  if freshRecord(c, tag) {
+    return
+  }
+
+  c.JSON(http.StatusOK, tag)
 }
 
 func (h *TagsHandler) Update(c *gin.Context) {
@@ -62,6 +81,10 @@ func (h *TagsHandler) Update(c *gin.Context) {
     return
   }
 
+  if !ifMatch(c, func() (any, error) { return h.tags.GetByID(c, tag) }) {
+    return
+  }
+
   if err := h.tags.Update(c, tag, &update); check(err, c.Writer) {
     return
   }
@@ -17,11 +17,27 @@ func NewTechnologyTagHandler(service service.TechnologyTagService) *TechnologyTa
 }
 
 func (h *TechnologyTagHandler) Get(c *gin.Context) {
-  var tags, err = h.s.Get(c)
+  var page, ok = getPagination(c)
+  if !ok {
+    return
+  }
+  var tags, err = h.s.List(c, page)
+  if check(err, c.Writer) {
+    return
+  }
+  emitPage(c, tags, page.Limit)
+}
+
+func (h *TechnologyTagHandler) GetByID(c *gin.Context) {
+  var technology, err = h.s.GetByID(c, c.Query("id"))
   if check(err, c.Writer) {
     return
   }
-  c.JSON(http.StatusOK, tags)
+  if freshRecord(c, technology) {
+    return
+  }
+
+  c.JSON(http.StatusOK, technology)
 }
 
 func (h *TechnologyTagHandler) Add(c *gin.Context) {
@@ -52,6 +68,9 @@ func (h *TechnologyTagHandler) Set(c *gin.Context) {
   if err := validateStruct(&update); check(err, c.Writer) {
     return
   }
+  if !ifMatch(c, func() (any, error) { return h.s.GetByID(c, id) }) {
+    return
+  }
   updated, err := h.s.Update(c, id, &update)
   if check(err, c.Writer) {
     return
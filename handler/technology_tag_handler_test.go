@@ -16,7 +16,7 @@ import (
 )
 
 func TestTechnologyTagHandler_Get(t *testing.T) {
-  const routine = "Get"
+  const routine = "List"
   const method = http.MethodGet
   const target = "/technologies.list"
 
@@ -27,14 +27,45 @@ func TestTechnologyTagHandler_Get(t *testing.T) {
       new(model.TechnologyTag),
     }
     var s = mocks.NewTechnologyTagService()
-    s.On(routine, mock.AnythingOfType("*gin.Context")).Return(technologies, nil)
+    s.On(routine, mock.AnythingOfType("*gin.Context"), &transfer.PageRequest{Limit: defaultLimit}).Return(&transfer.Page[*model.TechnologyTag]{Items: technologies, Total: len(technologies)}, nil)
     var engine = gin.Default()
     engine.GET(target, NewTechnologyTagHandler(s).Get)
     var request = httptest.NewRequest(method, target, nil)
     var recorder = httptest.NewRecorder()
     engine.ServeHTTP(recorder, request)
     assert.Equal(t, http.StatusOK, recorder.Code)
-    assert.Equal(t, string(marshal(t, technologies)), recorder.Body.String())
+    assert.Equal(t, string(marshal(t, transfer.Page[*model.TechnologyTag]{Items: technologies, Total: len(technologies)})), recorder.Body.String())
+  })
+}
+
+func TestTechnologyTagHandler_GetByID(t *testing.T) {
+  const routine = "GetByID"
+  const method = http.MethodGet
+  const target = "/technologies.info"
+  var id = uuid.New().String()
+
+  t.Run("success", func(t *testing.T) {
+    var technology = &model.TechnologyTag{UUID: uuid.MustParse(id), Name: "Go"}
+    var s = mocks.NewTechnologyTagService()
+    s.On(routine, mock.AnythingOfType("*gin.Context"), id).Return(technology, nil)
+    var engine = gin.Default()
+    engine.GET(target, NewTechnologyTagHandler(s).GetByID)
+    var request = httptest.NewRequest(method, target+"?id="+id, nil)
+    var recorder = httptest.NewRecorder()
+    engine.ServeHTTP(recorder, request)
+    assert.Equal(t, http.StatusOK, recorder.Code)
+    assert.Equal(t, string(marshal(t, technology)), recorder.Body.String())
+  })
+
+  t.Run("expected problem detail", func(t *testing.T) {
+    var s = mocks.NewTechnologyTagService()
+    s.On(routine, mock.AnythingOfType("*gin.Context"), id).Return(nil, problem.NewNotFound(id, "technology_tag"))
+    var engine = gin.Default()
+    engine.GET(target, NewTechnologyTagHandler(s).GetByID)
+    var request = httptest.NewRequest(method, target+"?id="+id, nil)
+    var recorder = httptest.NewRecorder()
+    engine.ServeHTTP(recorder, request)
+    assert.Equal(t, http.StatusNotFound, recorder.Code)
   })
 }
 
@@ -120,6 +151,30 @@ func TestTechnologyTagHandler_Set(t *testing.T) {
     assert.Empty(t, recorder.Body.String())
   })
 
+  t.Run("If-Match", func(t *testing.T) {
+    var technology = &model.TechnologyTag{UUID: uuid.MustParse(id), Name: "Old name"}
+    var etag, _ = etagOf(technology)
+    var s = mocks.NewTechnologyTagService()
+    s.On("GetByID", mock.AnythingOfType("*gin.Context"), id).Return(technology, nil)
+    s.On(routine, mock.AnythingOfType("*gin.Context"), id, update).Return(true, nil)
+    var engine = gin.Default()
+    engine.POST(target, NewTechnologyTagHandler(s).Set)
+
+    var conditional = request.Clone(request.Context())
+    conditional.Header.Set("If-Match", `W/"stale"`)
+    var recorder = httptest.NewRecorder()
+    engine.ServeHTTP(recorder, conditional)
+    assert.Equal(t, http.StatusPreconditionFailed, recorder.Code)
+    assert.Equal(t, etag, recorder.Header().Get("ETag"))
+    s.AssertNotCalled(t, routine, mock.Anything, mock.Anything, mock.Anything)
+
+    conditional.Header.Set("If-Match", etag)
+    recorder = httptest.NewRecorder()
+    engine.ServeHTTP(recorder, conditional)
+    assert.Equal(t, http.StatusNoContent, recorder.Code)
+    s.AssertCalled(t, routine, mock.AnythingOfType("*gin.Context"), id, update)
+  })
+
   t.Run("failed update without error", func(t *testing.T) {
     var s = mocks.NewTechnologyTagService()
     s.On(routine, mock.AnythingOfType("*gin.Context"), id, update).Return(false, nil)
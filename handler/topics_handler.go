@@ -35,13 +35,32 @@ func (h *TopicsHandler) Add(c *gin.Context) {
 }
 
 func (h *TopicsHandler) Get(c *gin.Context) {
-  topics, err := h.topics.Get(c)
+  page, ok := getPagination(c)
+  if !ok {
+    return
+  }
+
+  topics, err := h.topics.List(c, page)
 
   if check(err, c.Writer) {
     return
   }
 
-  c.JSON(http.StatusOK, topics)
+  emitPage(c, topics, page.Limit)
+}
+
+func (h *TopicsHandler) GetByID(c *gin.Context) {
+  topic, err := h.topics.GetByID(c, c.Query("topic_id"))
+
+  if check(err, c.Writer) {
+    return
+  }
+
+  if freshRecord(c, topic) {
+    return
+  }
+
+  c.JSON(http.StatusOK, topic)
 }
 
 func (h *TopicsHandler) Update(c *gin.Context) {
@@ -62,6 +81,10 @@ func (h *TopicsHandler) Update(c *gin.Context) {
     return
   }
 
+  if !ifMatch(c, func() (any, error) { return h.topics.GetByID(c, topic) }) {
+    return
+  }
+
   if err := h.topics.Update(c, topic, &update); check(err, c.Writer) {
     return
   }
@@ -0,0 +1,62 @@
+package handler
+
+import (
+  "fontseca.dev/problem"
+  "fontseca.dev/tracing"
+  "github.com/gin-gonic/gin"
+  "go.opentelemetry.io/otel"
+  "go.opentelemetry.io/otel/codes"
+  "go.opentelemetry.io/otel/propagation"
+  semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+  "go.opentelemetry.io/otel/trace"
+  "net/http"
+)
+
+// Trace is a middleware that serves every request within a span of its
+// own, continuing the trace of the W3C trace context of the request,
+// if any. The span is handed to the rest of the handlers through the
+// context of the request, and the ID of its trace is sent back in the
+// problem.TraceIDHeader header.
+//
+// For services and repositories to see the span through a gin.Context,
+// the engine must have ContextWithFallback enabled.
+func Trace() gin.HandlerFunc {
+  return func(c *gin.Context) {
+    var ctx = otel.GetTextMapPropagator().Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+
+    var route = c.FullPath()
+    var spanName = c.Request.Method + " " + route
+    if "" == route {
+      spanName = c.Request.Method
+    }
+
+    ctx, span := tracing.Tracer().Start(ctx, spanName,
+      trace.WithSpanKind(trace.SpanKindServer),
+      trace.WithAttributes(
+        semconv.HTTPRequestMethodKey.String(c.Request.Method),
+        semconv.HTTPRoute(route),
+        semconv.URLPath(c.Request.URL.Path),
+        semconv.ClientAddress(c.ClientIP()),
+        semconv.UserAgentOriginal(c.Request.UserAgent())))
+
+    defer span.End()
+
+    if span.SpanContext().HasTraceID() {
+      c.Header(problem.TraceIDHeader, span.SpanContext().TraceID().String())
+    }
+
+    c.Request = c.Request.WithContext(ctx)
+    c.Next()
+
+    var status = c.Writer.Status()
+    span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+
+    if http.StatusInternalServerError <= status {
+      span.SetStatus(codes.Error, http.StatusText(status))
+    }
+
+    for _, err := range c.Errors {
+      span.RecordError(err.Err)
+    }
+  }
+}
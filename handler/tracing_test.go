@@ -0,0 +1,49 @@
+package handler
+
+import (
+  "fontseca.dev/problem"
+  "github.com/gin-gonic/gin"
+  "github.com/stretchr/testify/assert"
+  "github.com/stretchr/testify/require"
+  "go.opentelemetry.io/otel"
+  "go.opentelemetry.io/otel/propagation"
+  sdktrace "go.opentelemetry.io/otel/sdk/trace"
+  "go.opentelemetry.io/otel/sdk/trace/tracetest"
+  "go.opentelemetry.io/otel/trace"
+  "net/http"
+  "net/http/httptest"
+  "testing"
+)
+
+func TestTrace(t *testing.T) {
+  var recorder = tracetest.NewSpanRecorder()
+  var previous = otel.GetTracerProvider()
+  otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+  otel.SetTextMapPropagator(propagation.TraceContext{})
+  defer otel.SetTracerProvider(previous)
+
+  var engine = gin.New()
+  engine.ContextWithFallback = true
+  engine.Use(Trace())
+
+  var seen trace.SpanContext
+  engine.GET("/archive.articles.info", func(c *gin.Context) {
+    seen = trace.SpanContextFromContext(c)
+    problem.NewNotFound("x", "article").Emit(c.Writer)
+  })
+
+  var request = httptest.NewRequest(http.MethodGet, "/archive.articles.info?article_uuid=x", nil)
+  request.Header.Set("Traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+  var response = httptest.NewRecorder()
+  engine.ServeHTTP(response, request)
+
+  require.Len(t, recorder.Ended(), 1)
+  var span = recorder.Ended()[0]
+
+  assert.Equal(t, "GET /archive.articles.info", span.Name())
+  assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext().TraceID().String())
+  assert.Equal(t, "00f067aa0ba902b7", span.Parent().SpanID().String())
+  assert.Equal(t, span.SpanContext(), seen)
+  assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", response.Header().Get(problem.TraceIDHeader))
+  assert.Contains(t, response.Body.String(), `"trace_id":"4bf92f3577b34da6a3ce929d0e0e4736"`)
+}
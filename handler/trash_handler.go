@@ -0,0 +1,50 @@
+package handler
+
+import (
+  "fontseca.dev/problem"
+  "fontseca.dev/service"
+  "github.com/gin-gonic/gin"
+  "net/http"
+)
+
+type TrashHandler struct {
+  trash service.TrashService
+}
+
+func NewTrashHandler(trash service.TrashService) *TrashHandler {
+  return &TrashHandler{trash}
+}
+
+func (h *TrashHandler) Get(c *gin.Context) {
+  page, ok := getPagination(c)
+  if !ok {
+    return
+  }
+
+  items, err := h.trash.Get(c, page)
+  if check(err, c.Writer) {
+    return
+  }
+
+  emitPage(c, items, page.Limit)
+}
+
+func (h *TrashHandler) Restore(c *gin.Context) {
+  var kind, success = c.GetPostForm("type")
+  if !success {
+    problem.NewMissingParameter("type").Emit(c.Writer)
+    return
+  }
+
+  id, success := c.GetPostForm("id")
+  if !success {
+    problem.NewMissingParameter("id").Emit(c.Writer)
+    return
+  }
+
+  if check(h.trash.Restore(c, kind, id), c.Writer) {
+    return
+  }
+
+  c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,93 @@
+package handler
+
+import (
+  "errors"
+  "fontseca.dev/mocks"
+  "fontseca.dev/transfer"
+  "github.com/gin-gonic/gin"
+  "github.com/google/uuid"
+  "github.com/stretchr/testify/assert"
+  "github.com/stretchr/testify/mock"
+  "net/http"
+  "net/http/httptest"
+  "testing"
+  "time"
+)
+
+func TestTrashHandler_Get(t *testing.T) {
+  const routine = "Get"
+  const method = http.MethodGet
+  const target = "/trash.list"
+
+  t.Run("success", func(t *testing.T) {
+    var items = []*transfer.TrashedItem{
+      {Type: "tag", ID: "go", Title: "Go", DeletedAt: time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)},
+      {Type: "article", ID: uuid.NewString(), Title: "Title", DeletedAt: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+    }
+    var s = mocks.NewTrashService()
+    s.On(routine, mock.AnythingOfType("*gin.Context"), &transfer.PageRequest{Limit: defaultLimit}).Return(&transfer.Page[*transfer.TrashedItem]{Items: items, Total: len(items)}, nil)
+    var engine = gin.Default()
+    engine.GET(target, NewTrashHandler(s).Get)
+    var recorder = httptest.NewRecorder()
+    engine.ServeHTTP(recorder, httptest.NewRequest(method, target, nil))
+    assert.Equal(t, http.StatusOK, recorder.Code)
+    assert.Equal(t, string(marshal(t, transfer.Page[*transfer.TrashedItem]{Items: items, Total: len(items)})), recorder.Body.String())
+  })
+
+  t.Run("unexpected error", func(t *testing.T) {
+    var s = mocks.NewTrashService()
+    s.On(routine, mock.AnythingOfType("*gin.Context"), &transfer.PageRequest{Limit: defaultLimit}).Return(nil, errors.New("unexpected error"))
+    var engine = gin.Default()
+    engine.GET(target, NewTrashHandler(s).Get)
+    var recorder = httptest.NewRecorder()
+    engine.ServeHTTP(recorder, httptest.NewRequest(method, target, nil))
+    assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+  })
+}
+
+func TestTrashHandler_Restore(t *testing.T) {
+  const routine = "Restore"
+  const method = http.MethodPost
+  const target = "/trash.restore"
+  var id = uuid.NewString()
+
+  t.Run("success", func(t *testing.T) {
+    var request = httptest.NewRequest(method, target, nil)
+    _ = request.ParseForm()
+    request.PostForm.Add("type", "project")
+    request.PostForm.Add("id", id)
+    var s = mocks.NewTrashService()
+    s.On(routine, mock.AnythingOfType("*gin.Context"), "project", id).Return(nil)
+    var engine = gin.Default()
+    engine.POST(target, NewTrashHandler(s).Restore)
+    var recorder = httptest.NewRecorder()
+    engine.ServeHTTP(recorder, request)
+    assert.Equal(t, http.StatusNoContent, recorder.Code)
+  })
+
+  t.Run("missing type", func(t *testing.T) {
+    var request = httptest.NewRequest(method, target, nil)
+    _ = request.ParseForm()
+    request.PostForm.Add("id", id)
+    var s = mocks.NewTrashService()
+    s.AssertNotCalled(t, routine)
+    var engine = gin.Default()
+    engine.POST(target, NewTrashHandler(s).Restore)
+    var recorder = httptest.NewRecorder()
+    engine.ServeHTTP(recorder, request)
+    assert.Equal(t, http.StatusBadRequest, recorder.Code)
+  })
+
+  t.Run("missing id", func(t *testing.T) {
+    var request = httptest.NewRequest(method, target, nil)
+    _ = request.ParseForm()
+    request.PostForm.Add("type", "project")
+    var s = mocks.NewTrashService()
+    s.AssertNotCalled(t, routine)
+    var engine = gin.Default()
+    engine.POST(target, NewTrashHandler(s).Restore)
+    var recorder = httptest.NewRecorder()
+    engine.ServeHTTP(recorder, request)
+    assert.Equal(t, http.StatusBadRequest, recorder.Code)
+  })
+}
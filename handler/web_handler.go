@@ -4,6 +4,7 @@ import (
   "context"
   "database/sql"
   "errors"
+  "fmt"
   "fontseca.dev/components/pages"
   "fontseca.dev/components/ui"
   "fontseca.dev/model"
@@ -24,6 +25,7 @@ type WebHandler struct {
   projectsService   service.ProjectsService
   drafts            service.DraftsService
   articles          service.ArticlesService
+  patches           service.PatchesService
   topics            service.TopicsService
   tags              service.TagsService
 }
@@ -34,6 +36,7 @@ func NewWebHandler(
   projectsService service.ProjectsService,
   drafts service.DraftsService,
   articles service.ArticlesService,
+  patches service.PatchesService,
   topics service.TopicsService,
   tags service.TagsService,
 ) *WebHandler {
@@ -43,6 +46,7 @@ func NewWebHandler(
     projectsService:   projectsService,
     drafts:            drafts,
     articles:          articles,
+    patches:           patches,
     topics:            topics,
     tags:              tags,
   }
@@ -93,6 +97,57 @@ func (h *WebHandler) RenderProjectDetails(c *gin.Context) {
   pages.ProjectDetails(project).Render(c, c.Writer)
 }
 
+// archivePageSize is the number of articles in every page of the web archive.
+const archivePageSize = 20
+
+// archivePageURL returns the URL of the current archive page with its
+// pagination query parameters replaced by direction, either 'after'
+// or 'before', set to cursor.
+func archivePageURL(c *gin.Context, direction string, cursor *transfer.Cursor) string {
+  var u = *c.Request.URL
+  var query = u.Query()
+
+  query.Del("page")
+  query.Del("after")
+  query.Del("before")
+  query.Set(direction, cursor.String())
+
+  u.RawQuery = query.Encode()
+
+  return u.RequestURI()
+}
+
+// redirectArchivePage redirects a request for a page number of the
+// archive, which is now paged by cursors, to the page that follows the
+// last article of the page before it, or to the first page if none.
+func (h *WebHandler) redirectArchivePage(c *gin.Context, filter *transfer.ArticleFilter, number string) {
+  var u = *c.Request.URL
+  var query = u.Query()
+
+  query.Del("page")
+  u.RawQuery = query.Encode()
+
+  var location = u.RequestURI()
+
+  if n, err := strconv.Atoi(number); nil == err && 1 < n && !query.Has("after") && !query.Has("before") {
+    var previous = *filter
+    previous.Page, previous.RPP = n-1, archivePageSize
+
+    articles, err := h.articles.Get(c, &previous)
+
+    if nil != err {
+      h.internal(c)
+      return
+    }
+
+    if 0 < len(articles) {
+      location = archivePageURL(c, "after", articles[len(articles)-1].Cursor())
+    }
+  }
+
+  c.Redirect(http.StatusMovedPermanently, location)
+}
+
 func (h *WebHandler) RenderArchive(c *gin.Context) {
   var (
     anyTopicSentinel      = &model.Topic{ID: "any", Name: "Any topic"}
@@ -100,12 +155,15 @@ func (h *WebHandler) RenderArchive(c *gin.Context) {
     year, _               = strconv.Atoi(c.Param("year"))
     month, _              = strconv.Atoi(c.Param("month"))
     topic, includeTopic   = c.Params.Get("topic")
+    number, includePage   = c.GetQuery("page")
+    after, includeAfter   = c.GetQuery("after")
+    before                = c.Query("before")
     filter                = &transfer.ArticleFilter{
       Search:      strings.TrimSpace(search),
       Topic:       topic,
       Publication: &transfer.Publication{Month: time.Month(month), Year: year},
       Page:        1,
-      RPP:         10000,
+      RPP:         1 + archivePageSize, // the extra article tells whether there is another page
     }
   )
 
@@ -113,6 +171,17 @@ func (h *WebHandler) RenderArchive(c *gin.Context) {
     filter.Topic = ""
   }
 
+  if includePage {
+    h.redirectArchivePage(c, filter, number)
+    return
+  }
+
+  if cursor, err := transfer.ParseCursor(after); nil == err {
+    filter.After = cursor
+  } else if cursor, err := transfer.ParseCursor(before); nil == err {
+    filter.Before = cursor
+  }
+
   articles, err := h.articles.Get(c, filter)
 
   if nil != err {
@@ -120,6 +189,37 @@ func (h *WebHandler) RenderArchive(c *gin.Context) {
     return
   }
 
+  var (
+    page  = &transfer.ArchivePage{}
+    links = make([]string, 0, 2)
+    newer = nil != filter.After
+    older = nil != filter.Before
+  )
+
+  if archivePageSize < len(articles) {
+    if nil != filter.Before {
+      articles = articles[len(articles)-archivePageSize:]
+      newer = true
+    } else {
+      articles = articles[:archivePageSize]
+      older = true
+    }
+  }
+
+  if newer && 0 < len(articles) {
+    page.Previous = archivePageURL(c, "before", articles[0].Cursor())
+    links = append(links, fmt.Sprintf("<%s>; rel=\"prev\"", page.Previous))
+  }
+
+  if older && 0 < len(articles) {
+    page.Next = archivePageURL(c, "after", articles[len(articles)-1].Cursor())
+    links = append(links, fmt.Sprintf("<%s>; rel=\"next\"", page.Next))
+  }
+
+  if 0 < len(links) {
+    c.Header("Link", strings.Join(links, ", "))
+  }
+
   publications, err := h.articles.Publications(c)
 
   if nil != err {
@@ -136,8 +236,12 @@ func (h *WebHandler) RenderArchive(c *gin.Context) {
 
   hxRequest, _ := strconv.ParseBool(c.GetHeader("HX-Request"))
 
-  if hxRequest && (includeSearch || includeTopic) {
-    ui.SearchResults(articles).Render(c, c.Writer)
+  if hxRequest && (includeSearch || includeTopic || includeAfter) {
+    if includeAfter {
+      page.Previous = "" // the results are appended to the ones already loaded
+    }
+
+    ui.SearchResults(articles, page).Render(c, c.Writer)
     return
   }
 
@@ -163,6 +267,7 @@ func (h *WebHandler) RenderArchive(c *gin.Context) {
 
   pages.Archive(
     articles,
+    page,
     publications,
     topics,
     tags,
@@ -197,6 +302,18 @@ func (h *WebHandler) RenderArticle(c *gin.Context) {
       }
     }
 
+    if nil != draft.PatchUUID {
+      changes, err := h.patches.Diff(c.Request.Context(), draft.PatchUUID.String())
+
+      if nil != err {
+        h.internal(c)
+        return
+      }
+
+      pages.ArticlePatch(draft, changes).Render(c, c.Writer)
+      return
+    }
+
     pages.Article(draft).Render(c, c.Writer)
     return
   }
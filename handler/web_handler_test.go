@@ -0,0 +1,206 @@
+package handler
+
+import (
+  "fmt"
+  "fontseca.dev/mocks"
+  "fontseca.dev/model"
+  "fontseca.dev/transfer"
+  "github.com/gin-gonic/gin"
+  "github.com/google/uuid"
+  "github.com/stretchr/testify/assert"
+  "github.com/stretchr/testify/mock"
+  "net/http"
+  "net/http/httptest"
+  "testing"
+  "time"
+)
+
+func TestWebHandler_RenderArchive(t *testing.T) {
+  const (
+    routine = "Get"
+    method  = http.MethodGet
+    target  = "/archive"
+  )
+
+  newArticles := func(n int) []*transfer.Article {
+    articles := make([]*transfer.Article, n)
+    now := time.Now()
+
+    for i := range articles {
+      articles[i] = &transfer.Article{
+        UUID:        uuid.New(),
+        Title:       fmt.Sprintf("Article %d", i),
+        URL:         fmt.Sprintf("/archive/go/2024/1/article-%d", i),
+        PublishedAt: &now,
+      }
+    }
+
+    return articles
+  }
+
+  newEngine := func(articles *mocks.ArticlesService) *gin.Engine {
+    articles.On("Publications", mock.Anything).Return([]*transfer.Publication{}, nil)
+
+    topics := mocks.NewTopicsService()
+    topics.On(routine, mock.Anything).Return([]*model.Topic{}, nil)
+
+    tags := mocks.NewTagsService()
+    tags.On(routine, mock.Anything).Return([]*model.Tag{}, nil)
+
+    engine := gin.Default()
+    engine.GET(target, NewWebHandler(nil, nil, nil, nil, articles, nil, topics, tags).RenderArchive)
+
+    return engine
+  }
+
+  t.Run("first page", func(t *testing.T) {
+    articles := newArticles(1 + archivePageSize)
+
+    s := mocks.NewArticlesService()
+    s.On(routine, mock.Anything, mock.MatchedBy(func(f *transfer.ArticleFilter) bool {
+      return 1 == f.Page && 1+archivePageSize == f.RPP && nil == f.After && nil == f.Before
+    })).Return(articles, nil)
+
+    recorder := httptest.NewRecorder()
+
+    newEngine(s).ServeHTTP(recorder, httptest.NewRequest(method, target, nil))
+
+    assert.Equal(t, http.StatusOK, recorder.Code)
+    assert.Equal(t, fmt.Sprintf(`</archive?after=%s>; rel="next"`, articles[archivePageSize-1].Cursor()), recorder.Header().Get("Link"))
+  })
+
+  t.Run("page after cursor", func(t *testing.T) {
+    after := &transfer.Cursor{Key: []string{"false", "2024-01-01 00:00:00"}, ID: uuid.New().String()}
+    articles := newArticles(2)
+
+    s := mocks.NewArticlesService()
+    s.On(routine, mock.Anything, mock.MatchedBy(func(f *transfer.ArticleFilter) bool {
+      return assert.ObjectsAreEqual(after, f.After) && nil == f.Before
+    })).Return(articles, nil)
+
+    recorder := httptest.NewRecorder()
+
+    newEngine(s).ServeHTTP(recorder, httptest.NewRequest(method, target+"?search=go&after="+after.String(), nil))
+
+    assert.Equal(t, http.StatusOK, recorder.Code)
+    assert.Equal(t, fmt.Sprintf(`</archive?before=%s&search=go>; rel="prev"`, articles[0].Cursor()), recorder.Header().Get("Link"))
+  })
+
+  t.Run("page before cursor", func(t *testing.T) {
+    before := &transfer.Cursor{Key: []string{"false", "2024-01-01 00:00:00"}, ID: uuid.New().String()}
+    articles := newArticles(1 + archivePageSize)
+
+    s := mocks.NewArticlesService()
+    s.On(routine, mock.Anything, mock.MatchedBy(func(f *transfer.ArticleFilter) bool {
+      return nil == f.After && assert.ObjectsAreEqual(before, f.Before)
+    })).Return(articles, nil)
+
+    recorder := httptest.NewRecorder()
+
+    newEngine(s).ServeHTTP(recorder, httptest.NewRequest(method, target+"?before="+before.String(), nil))
+
+    assert.Equal(t, http.StatusOK, recorder.Code)
+    assert.Equal(t, fmt.Sprintf(`</archive?before=%s>; rel="prev", </archive?after=%s>; rel="next"`, articles[1].Cursor(), articles[archivePageSize].Cursor()), recorder.Header().Get("Link"))
+  })
+
+  t.Run("legacy page number", func(t *testing.T) {
+    articles := newArticles(archivePageSize)
+
+    s := mocks.NewArticlesService()
+    s.On(routine, mock.Anything, mock.MatchedBy(func(f *transfer.ArticleFilter) bool {
+      return 2 == f.Page && archivePageSize == f.RPP && nil == f.After && nil == f.Before && "go" == f.Search
+    })).Return(articles, nil)
+
+    recorder := httptest.NewRecorder()
+
+    newEngine(s).ServeHTTP(recorder, httptest.NewRequest(method, target+"?search=go&page=3", nil))
+
+    assert.Equal(t, http.StatusMovedPermanently, recorder.Code)
+    assert.Equal(t, fmt.Sprintf("/archive?after=%s&search=go", articles[archivePageSize-1].Cursor()), recorder.Header().Get("Location"))
+  })
+
+  t.Run("legacy page number out of range", func(t *testing.T) {
+    s := mocks.NewArticlesService()
+    s.On(routine, mock.Anything, mock.Anything).Return([]*transfer.Article{}, nil)
+
+    recorder := httptest.NewRecorder()
+
+    newEngine(s).ServeHTTP(recorder, httptest.NewRequest(method, target+"?page=99", nil))
+
+    assert.Equal(t, http.StatusMovedPermanently, recorder.Code)
+    assert.Equal(t, "/archive", recorder.Header().Get("Location"))
+  })
+
+  t.Run("legacy page number with cursor", func(t *testing.T) {
+    after := &transfer.Cursor{Key: []string{"false", "2024-01-01 00:00:00"}, ID: uuid.New().String()}
+
+    s := mocks.NewArticlesService()
+
+    recorder := httptest.NewRecorder()
+
+    newEngine(s).ServeHTTP(recorder, httptest.NewRequest(method, target+"?page=3&after="+after.String(), nil))
+
+    assert.Equal(t, http.StatusMovedPermanently, recorder.Code)
+    assert.Equal(t, "/archive?after="+after.String(), recorder.Header().Get("Location"))
+    s.AssertNotCalled(t, routine)
+  })
+
+  t.Run("invalid cursor", func(t *testing.T) {
+    s := mocks.NewArticlesService()
+    s.On(routine, mock.Anything, mock.MatchedBy(func(f *transfer.ArticleFilter) bool {
+      return 1 == f.Page && nil == f.After && nil == f.Before
+    })).Return(newArticles(2), nil)
+
+    recorder := httptest.NewRecorder()
+
+    newEngine(s).ServeHTTP(recorder, httptest.NewRequest(method, target+"?after=1;DROP&before=***", nil))
+
+    assert.Equal(t, http.StatusOK, recorder.Code)
+    assert.Empty(t, recorder.Header().Get("Link"))
+  })
+}
+
+func TestWebHandler_RenderFeed(t *testing.T) {
+  const routine = "Get"
+
+  published := time.Date(2024, time.January, 2, 3, 4, 5, 0, time.UTC)
+  articles := []*transfer.Article{
+    {UUID: uuid.New(), Title: "Latest", URL: "http://example.com/archive/go/2024/1/latest", PublishedAt: &published},
+  }
+
+  newEngine := func(topic string) *gin.Engine {
+    s := mocks.NewArticlesService()
+    s.On(routine, mock.Anything, mock.MatchedBy(func(f *transfer.ArticleFilter) bool {
+      return topic == f.Topic && feedSize == f.RPP
+    })).Return(articles, nil)
+
+    web := NewWebHandler(nil, nil, nil, nil, s, nil, nil, nil)
+
+    engine := gin.Default()
+    engine.GET("/archive/feed.xml", web.RenderFeed)
+    engine.GET("/archive/:topic/feed.xml", web.RenderFeed)
+
+    return engine
+  }
+
+  t.Run("archive", func(t *testing.T) {
+    recorder := httptest.NewRecorder()
+    newEngine("").ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://example.com/archive/feed.xml", nil))
+
+    assert.Equal(t, http.StatusOK, recorder.Code)
+    assert.Equal(t, "application/atom+xml; charset=utf-8", recorder.Header().Get("Content-Type"))
+    assert.Contains(t, recorder.Body.String(), `<id>http://example.com/archive</id>`)
+    assert.Contains(t, recorder.Body.String(), `<updated>2024-01-02T03:04:05Z</updated>`)
+    assert.Contains(t, recorder.Body.String(), `<link rel="alternate" href="http://example.com/archive/go/2024/1/latest"></link>`)
+    assert.Contains(t, recorder.Body.String(), "<id>urn:uuid:"+articles[0].UUID.String()+"</id>")
+  })
+
+  t.Run("topic", func(t *testing.T) {
+    recorder := httptest.NewRecorder()
+    newEngine("go").ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "https://example.com/archive/go/feed.xml", nil))
+
+    assert.Equal(t, http.StatusOK, recorder.Code)
+    assert.Contains(t, recorder.Body.String(), `<id>https://example.com/archive/go</id>`)
+    assert.Contains(t, recorder.Body.String(), `<link rel="self" href="https://example.com/archive/go/feed.xml"></link>`)
+  })
+}
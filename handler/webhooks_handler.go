@@ -0,0 +1,88 @@
+package handler
+
+import (
+  "fontseca.dev/problem"
+  "fontseca.dev/service"
+  "fontseca.dev/transfer"
+  "github.com/gin-gonic/gin"
+  "net/http"
+)
+
+type WebhooksHandler struct {
+  s service.WebhooksService
+}
+
+func NewWebhooksHandler(s service.WebhooksService) *WebhooksHandler {
+  return &WebhooksHandler{s}
+}
+
+func (h *WebhooksHandler) Add(c *gin.Context) {
+  var creation transfer.WebhookCreation
+
+  if err := bindPostForm(c, &creation); check(err, c.Writer) {
+    return
+  }
+
+  if err := validateStruct(&creation); check(err, c.Writer) {
+    return
+  }
+
+  insertedID, secret, err := h.s.Add(c, &creation)
+  if check(err, c.Writer) {
+    return
+  }
+
+  c.JSON(http.StatusOK, gin.H{"inserted_id": insertedID, "secret": secret})
+}
+
+func (h *WebhooksHandler) Get(c *gin.Context) {
+  page, ok := getPagination(c)
+  if !ok {
+    return
+  }
+
+  webhooks, err := h.s.Get(c, page)
+  if check(err, c.Writer) {
+    return
+  }
+
+  emitPage(c, webhooks, page.Limit)
+}
+
+func (h *WebhooksHandler) Remove(c *gin.Context) {
+  var id, success = c.GetPostForm("id")
+  if !success {
+    problem.NewMissingParameter("id").Emit(c.Writer)
+    return
+  }
+
+  if check(h.s.Remove(c, id), c.Writer) {
+    return
+  }
+
+  c.Status(http.StatusNoContent)
+}
+
+func (h *WebhooksHandler) Deliveries(c *gin.Context) {
+  var id, success = c.GetQuery("webhook_id")
+  if !success {
+    problem.NewMissingParameter("webhook_id").Emit(c.Writer)
+    return
+  }
+
+  page, ok := getPagination(c)
+  if !ok {
+    return
+  }
+
+  deliveries, err := h.s.Deliveries(c, id, page)
+  if check(err, c.Writer) {
+    return
+  }
+
+  emitPage(c, deliveries, page.Limit)
+}
+
+func (h *WebhooksHandler) Events(c *gin.Context) {
+  c.JSON(http.StatusOK, service.Events)
+}
@@ -0,0 +1,153 @@
+package handler
+
+import (
+  "errors"
+  "fontseca.dev/mocks"
+  "fontseca.dev/model"
+  "fontseca.dev/service"
+  "fontseca.dev/transfer"
+  "github.com/gin-gonic/gin"
+  "github.com/google/uuid"
+  "github.com/stretchr/testify/assert"
+  "github.com/stretchr/testify/mock"
+  "net/http"
+  "net/http/httptest"
+  "testing"
+)
+
+func TestWebhooksHandler_Add(t *testing.T) {
+  const routine = "Add"
+  const method = http.MethodPost
+  const target = "/webhooks.add"
+  var id = uuid.NewString()
+  var creation = &transfer.WebhookCreation{URL: "https://example.com/hooks", Events: "article.published"}
+
+  t.Run("success", func(t *testing.T) {
+    var request = httptest.NewRequest(method, target, nil)
+    _ = request.ParseForm()
+    request.PostForm.Add("url", creation.URL)
+    request.PostForm.Add("events", creation.Events)
+    var s = mocks.NewWebhooksService()
+    s.On(routine, mock.AnythingOfType("*gin.Context"), creation).Return(id, "secret", nil)
+    var engine = gin.Default()
+    engine.POST(target, NewWebhooksHandler(s).Add)
+    var recorder = httptest.NewRecorder()
+    engine.ServeHTTP(recorder, request)
+    assert.Equal(t, http.StatusOK, recorder.Code)
+    assert.Equal(t, string(marshal(t, gin.H{"inserted_id": id, "secret": "secret"})), recorder.Body.String())
+  })
+
+  t.Run("invalid url", func(t *testing.T) {
+    var request = httptest.NewRequest(method, target, nil)
+    _ = request.ParseForm()
+    request.PostForm.Add("url", "example")
+    request.PostForm.Add("events", creation.Events)
+    var s = mocks.NewWebhooksService()
+    s.AssertNotCalled(t, routine)
+    var engine = gin.Default()
+    engine.POST(target, NewWebhooksHandler(s).Add)
+    var recorder = httptest.NewRecorder()
+    engine.ServeHTTP(recorder, request)
+    assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+  })
+
+  t.Run("unexpected error", func(t *testing.T) {
+    var request = httptest.NewRequest(method, target, nil)
+    _ = request.ParseForm()
+    request.PostForm.Add("url", creation.URL)
+    request.PostForm.Add("events", creation.Events)
+    var s = mocks.NewWebhooksService()
+    s.On(routine, mock.AnythingOfType("*gin.Context"), creation).Return("", "", errors.New("unexpected error"))
+    var engine = gin.Default()
+    engine.POST(target, NewWebhooksHandler(s).Add)
+    var recorder = httptest.NewRecorder()
+    engine.ServeHTTP(recorder, request)
+    assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+  })
+}
+
+func TestWebhooksHandler_Get(t *testing.T) {
+  const routine = "Get"
+  const method = http.MethodGet
+  const target = "/webhooks.list"
+
+  var webhooks = []*model.Webhook{{UUID: uuid.New()}, {UUID: uuid.New()}}
+  var s = mocks.NewWebhooksService()
+  s.On(routine, mock.AnythingOfType("*gin.Context"), &transfer.PageRequest{Limit: defaultLimit}).Return(&transfer.Page[*model.Webhook]{Items: webhooks, Total: len(webhooks)}, nil)
+  var engine = gin.Default()
+  engine.GET(target, NewWebhooksHandler(s).Get)
+  var request = httptest.NewRequest(method, target, nil)
+  var recorder = httptest.NewRecorder()
+  engine.ServeHTTP(recorder, request)
+  assert.Equal(t, http.StatusOK, recorder.Code)
+  assert.Equal(t, string(marshal(t, transfer.Page[*model.Webhook]{Items: webhooks, Total: len(webhooks)})), recorder.Body.String())
+}
+
+func TestWebhooksHandler_Remove(t *testing.T) {
+  const routine = "Remove"
+  const method = http.MethodPost
+  const target = "/webhooks.remove"
+  var id = uuid.NewString()
+
+  t.Run("success", func(t *testing.T) {
+    var request = httptest.NewRequest(method, target, nil)
+    _ = request.ParseForm()
+    request.PostForm.Add("id", id)
+    var s = mocks.NewWebhooksService()
+    s.On(routine, mock.AnythingOfType("*gin.Context"), id).Return(nil)
+    var engine = gin.Default()
+    engine.POST(target, NewWebhooksHandler(s).Remove)
+    var recorder = httptest.NewRecorder()
+    engine.ServeHTTP(recorder, request)
+    assert.Equal(t, http.StatusNoContent, recorder.Code)
+  })
+
+  t.Run("missing id", func(t *testing.T) {
+    var s = mocks.NewWebhooksService()
+    s.AssertNotCalled(t, routine)
+    var engine = gin.Default()
+    engine.POST(target, NewWebhooksHandler(s).Remove)
+    var recorder = httptest.NewRecorder()
+    engine.ServeHTTP(recorder, httptest.NewRequest(method, target, nil))
+    assert.Equal(t, http.StatusBadRequest, recorder.Code)
+  })
+}
+
+func TestWebhooksHandler_Deliveries(t *testing.T) {
+  const routine = "Deliveries"
+  const method = http.MethodGet
+  const target = "/webhooks.deliveries.list"
+  var id = uuid.NewString()
+
+  t.Run("success", func(t *testing.T) {
+    var deliveries = []*model.WebhookDelivery{{UUID: uuid.New(), Payload: []byte(`{}`)}}
+    var s = mocks.NewWebhooksService()
+    s.On(routine, mock.AnythingOfType("*gin.Context"), id, &transfer.PageRequest{Limit: defaultLimit}).Return(&transfer.Page[*model.WebhookDelivery]{Items: deliveries, Total: len(deliveries)}, nil)
+    var engine = gin.Default()
+    engine.GET(target, NewWebhooksHandler(s).Deliveries)
+    var recorder = httptest.NewRecorder()
+    engine.ServeHTTP(recorder, httptest.NewRequest(method, target+"?webhook_id="+id, nil))
+    assert.Equal(t, http.StatusOK, recorder.Code)
+    assert.Equal(t, string(marshal(t, transfer.Page[*model.WebhookDelivery]{Items: deliveries, Total: len(deliveries)})), recorder.Body.String())
+  })
+
+  t.Run("missing webhook_id", func(t *testing.T) {
+    var s = mocks.NewWebhooksService()
+    s.AssertNotCalled(t, routine)
+    var engine = gin.Default()
+    engine.GET(target, NewWebhooksHandler(s).Deliveries)
+    var recorder = httptest.NewRecorder()
+    engine.ServeHTTP(recorder, httptest.NewRequest(method, target, nil))
+    assert.Equal(t, http.StatusBadRequest, recorder.Code)
+  })
+}
+
+func TestWebhooksHandler_Events(t *testing.T) {
+  const target = "/webhooks.events.list"
+  var engine = gin.Default()
+  engine.GET(target, NewWebhooksHandler(mocks.NewWebhooksService()).Events)
+  var recorder = httptest.NewRecorder()
+  engine.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, target, nil))
+  assert.Equal(t, http.StatusOK, recorder.Code)
+  assert.Equal(t, string(marshal(t, service.Events)), recorder.Body.String())
+}
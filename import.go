@@ -0,0 +1,38 @@
+package main
+
+import (
+  "context"
+  "fmt"
+  "fontseca.dev/config"
+  "fontseca.dev/database"
+  "fontseca.dev/repository"
+  "fontseca.dev/service"
+  "os"
+)
+
+// importArticles imports the Markdown files of every directory given
+// in args into the archive.
+func importArticles(cfg *config.Config, db *database.DB, args []string) error {
+  if 0 == len(args) {
+    return errUsage
+  }
+
+  var archive = repository.NewArchiveRepository(db, cfg.Archive.ShareExpiry)
+  defer archive.Close()
+
+  var imports = service.NewImportService(archive, cfg.Archive.ReadingSpeed)
+
+  for _, dir := range args {
+    results, err := imports.ImportAll(context.Background(), os.DirFS(dir))
+
+    for _, result := range results {
+      fmt.Fprintf(os.Stdout, "%-9s %s (%s)\n", result.Status, result.File, result.Slug)
+    }
+
+    if nil != err {
+      return fmt.Errorf("importing %s: %w", dir, err)
+    }
+  }
+
+  return nil
+}
@@ -0,0 +1,53 @@
+// Package logging sets up the structured logs of the site: JSON records
+// that carry the ID of the request they were logged for, written to
+// files that are rotated by size.
+package logging
+
+import (
+  "context"
+  "log/slog"
+)
+
+// requestIDKey is the key of the ID of a request in its context.
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx that carries the request ID id.
+func WithRequestID(ctx context.Context, id string) context.Context {
+  return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID carried by ctx, if any.
+func RequestID(ctx context.Context) string {
+  id, _ := ctx.Value(requestIDKey{}).(string)
+  return id
+}
+
+// handler adds the ID of the request in the context of a record to its
+// attributes.
+type handler struct {
+  slog.Handler
+}
+
+// NewHandler wraps h so that the records logged with the context of a
+// request get its 'request_id'.
+func NewHandler(h slog.Handler) slog.Handler {
+  return &handler{Handler: h}
+}
+
+func (h *handler) Handle(ctx context.Context, record slog.Record) error {
+  if nil != ctx {
+    if id := RequestID(ctx); "" != id {
+      record.AddAttrs(slog.String("request_id", id))
+    }
+  }
+
+  return h.Handler.Handle(ctx, record)
+}
+
+func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+  return &handler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *handler) WithGroup(name string) slog.Handler {
+  return &handler{Handler: h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,72 @@
+package logging
+
+import (
+  "bytes"
+  "context"
+  "encoding/json"
+  "github.com/stretchr/testify/assert"
+  "github.com/stretchr/testify/require"
+  "log/slog"
+  "os"
+  "path/filepath"
+  "testing"
+)
+
+func TestNewHandler(t *testing.T) {
+  var buf bytes.Buffer
+  var logger = slog.New(NewHandler(slog.NewJSONHandler(&buf, nil))).With("component", "test")
+
+  logger.InfoContext(WithRequestID(context.Background(), "abc-123"), "first")
+  logger.InfoContext(context.Background(), "second")
+
+  var lines = bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+  require.Len(t, lines, 2)
+
+  var first, second map[string]any
+  require.NoError(t, json.Unmarshal(lines[0], &first))
+  require.NoError(t, json.Unmarshal(lines[1], &second))
+
+  assert.Equal(t, "abc-123", first["request_id"])
+  assert.Equal(t, "test", first["component"])
+  assert.NotContains(t, second, "request_id")
+}
+
+func TestRotatingFile(t *testing.T) {
+  var path = filepath.Join(t.TempDir(), "server.log")
+
+  f, err := OpenRotatingFile(path, 10, 2)
+  require.NoError(t, err)
+
+  for _, line := range []string{"aaaaaaaa\n", "bbbbbbbb\n", "cccccccc\n", "dddddddd\n"} {
+    _, err = f.Write([]byte(line))
+    require.NoError(t, err)
+  }
+
+  require.NoError(t, f.Close())
+
+  var read = func(name string) string {
+    content, err := os.ReadFile(name)
+    require.NoError(t, err)
+    return string(content)
+  }
+
+  assert.Equal(t, "dddddddd\n", read(path))
+  assert.Equal(t, "cccccccc\n", read(path+".1"))
+  assert.Equal(t, "bbbbbbbb\n", read(path+".2"))
+  assert.NoFileExists(t, path+".3")
+
+  t.Run("appends to an existing file", func(t *testing.T) {
+    f, err := OpenRotatingFile(path, 20, 2)
+    require.NoError(t, err)
+    _, err = f.Write([]byte("eeeeeeee\n"))
+    require.NoError(t, err)
+    require.NoError(t, f.Close())
+
+    assert.Equal(t, "dddddddd\neeeeeeee\n", read(path))
+  })
+
+  t.Run("rejects a non-positive size", func(t *testing.T) {
+    _, err := OpenRotatingFile(path, 0, 2)
+    assert.Error(t, err)
+  })
+}
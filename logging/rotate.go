@@ -0,0 +1,113 @@
+package logging
+
+import (
+  "fmt"
+  "os"
+  "sync"
+)
+
+// RotatingFile is a log file that is rotated once it grows past a size.
+// The rotated files are named after it with the suffixes .1, .2 and so
+// on, .1 being the most recent one; only the most recent ones are kept.
+type RotatingFile struct {
+  path     string
+  maxSize  int64
+  retained int
+
+  mu   sync.Mutex
+  file *os.File
+  size int64
+}
+
+// OpenRotatingFile opens the log file at path for appending, creating
+// it if needed. The file is rotated before a write would make it grow
+// past maxSize bytes, and retained rotated files are kept.
+func OpenRotatingFile(path string, maxSize int64, retained int) (*RotatingFile, error) {
+  if 0 >= maxSize {
+    return nil, fmt.Errorf("the maximum size of a log file must be positive, but it is %d", maxSize)
+  }
+
+  var f = &RotatingFile{path: path, maxSize: maxSize, retained: max(0, retained)}
+  if err := f.open(); nil != err {
+    return nil, err
+  }
+
+  return f, nil
+}
+
+// open opens the file at f.path for appending.
+func (f *RotatingFile) open() error {
+  file, err := os.OpenFile(f.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+  if nil != err {
+    return err
+  }
+
+  info, err := file.Stat()
+  if nil != err {
+    file.Close()
+    return err
+  }
+
+  f.file, f.size = file, info.Size()
+  return nil
+}
+
+// backup names the n-th most recent rotated file.
+func (f *RotatingFile) backup(n int) string {
+  return fmt.Sprintf("%s.%d", f.path, n)
+}
+
+// rotate closes the current file, shifts the rotated ones dropping the
+// oldest, and opens a new file.
+func (f *RotatingFile) rotate() error {
+  if err := f.file.Close(); nil != err {
+    return err
+  }
+
+  if 0 == f.retained {
+    if err := os.Remove(f.path); nil != err && !os.IsNotExist(err) {
+      return err
+    }
+
+    return f.open()
+  }
+
+  if err := os.Remove(f.backup(f.retained)); nil != err && !os.IsNotExist(err) {
+    return err
+  }
+
+  for n := f.retained - 1; 0 < n; n-- {
+    if err := os.Rename(f.backup(n), f.backup(n+1)); nil != err && !os.IsNotExist(err) {
+      return err
+    }
+  }
+
+  if err := os.Rename(f.path, f.backup(1)); nil != err {
+    return err
+  }
+
+  return f.open()
+}
+
+func (f *RotatingFile) Write(p []byte) (n int, err error) {
+  f.mu.Lock()
+  defer f.mu.Unlock()
+
+  if 0 < f.size && f.maxSize < f.size+int64(len(p)) {
+    if err = f.rotate(); nil != err {
+      return 0, err
+    }
+  }
+
+  n, err = f.file.Write(p)
+  f.size += int64(n)
+
+  return n, err
+}
+
+func (f *RotatingFile) Close() error {
+  f.mu.Lock()
+  defer f.mu.Unlock()
+
+  return f.file.Close()
+}
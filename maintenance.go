@@ -0,0 +1,342 @@
+package main
+
+import (
+  "bufio"
+  "context"
+  "errors"
+  "fmt"
+  "fontseca.dev/config"
+  "fontseca.dev/database"
+  "fontseca.dev/repository"
+  "fontseca.dev/service"
+  "io"
+  "net"
+  "net/http"
+  "os"
+  "path/filepath"
+  "strconv"
+  "strings"
+  "text/tabwriter"
+  "time"
+)
+
+// migrateDatabase applies the pending migrations, which opening the
+// database already does, and prints the migrations applied so far.
+func migrateDatabase(_ *config.Config, db *database.DB, args []string) error {
+  if 0 != len(args) {
+    return errUsage
+  }
+
+  var query = `
+    SELECT "version",
+           "name",
+           "applied_at"
+      FROM "schema_migration"
+  ORDER BY "version";`
+
+  ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+  defer cancel()
+
+  rows, err := db.QueryContext(ctx, query)
+  if nil != err {
+    return err
+  }
+
+  defer rows.Close()
+
+  for rows.Next() {
+    var (
+      version   int
+      name      string
+      appliedAt time.Time
+    )
+
+    if err = rows.Scan(&version, &name, &appliedAt); nil != err {
+      return err
+    }
+
+    fmt.Fprintf(os.Stdout, "%4d  %s  %s\n", version, appliedAt.Format(time.DateTime), name)
+  }
+
+  if err = rows.Err(); nil != err {
+    return err
+  }
+
+  if err = database.CheckSchema(ctx, db); nil != err {
+    return err
+  }
+
+  fmt.Fprintf(os.Stdout, "schema is up to date at version %d\n", database.Version())
+  return nil
+}
+
+// manageLinks runs the links subcommands.
+func manageLinks(cfg *config.Config, db *database.DB, args []string) error {
+  if 1 != len(args) || "prune" != args[0] {
+    return errUsage
+  }
+
+  var archive = repository.NewArchiveRepository(db, cfg.Archive.ShareExpiry)
+  defer archive.Close()
+
+  removed, err := archive.PruneLinks(context.Background())
+  if nil != err {
+    return fmt.Errorf("pruning links: %w", err)
+  }
+
+  fmt.Fprintf(os.Stdout, "removed %d expired link(s)\n", removed)
+  return nil
+}
+
+// manageDatabase runs the db subcommands. The database is opened only
+// to back it up: restoring it replaces its file, which must be closed.
+func manageDatabase(cfg *config.Config, args []string) error {
+  switch {
+  case 1 <= len(args) && len(args) <= 2 && "backup" == args[0]:
+    db, err := openDatabase(cfg.Database)
+    if nil != err {
+      return err
+    }
+
+    defer db.Close()
+
+    return backupDatabase(cfg, db, args[1:])
+  case 2 == len(args) && "restore" == args[0]:
+    return restoreDatabase(cfg, args[1])
+  default:
+    return errUsage
+  }
+}
+
+// backupDatabase backs up the database db into the directory of the
+// backups, pruning the oldest ones, or into the file of args if given.
+func backupDatabase(cfg *config.Config, db *database.DB, args []string) error {
+  var (
+    ctx = context.Background()
+    r   = repository.NewBackupsRepository(db)
+  )
+
+  if 1 == len(args) {
+    if err := r.Backup(ctx, args[0]); nil != err {
+      return fmt.Errorf("backing up database: %w", err)
+    }
+
+    fmt.Fprintf(os.Stdout, "backed up database to %s\n", args[0])
+    return nil
+  }
+
+  backup, err := service.NewBackupsService(r, cfg.Backup.Dir, cfg.Backup.Retention).Create(ctx)
+  if nil != err {
+    return fmt.Errorf("backing up database: %w", err)
+  }
+
+  fmt.Fprintf(os.Stdout, "backed up database to %s\n", filepath.Join(cfg.Backup.Dir, backup.Name))
+  return nil
+}
+
+// restoreDatabase replaces the database with the backup in file once
+// it passes an integrity check. The server must be stopped: the new
+// file is copied next to the database and renamed over it, and the
+// database it replaces is kept aside rather than removed.
+func restoreDatabase(cfg *config.Config, file string) error {
+  var path = cfg.Database.Path
+
+  if "sqlite" != cfg.Database.Driver {
+    return fmt.Errorf("restoring database: only SQLite databases are restored; restore a %s one with its own tools", cfg.Database.Driver)
+  }
+
+  if err := repository.CheckIntegrity(context.Background(), file); nil != err {
+    return fmt.Errorf("restoring database: %w", err)
+  }
+
+  var host = cfg.Server.Host
+  if ip := net.ParseIP(host); "" == host || (nil != ip && ip.IsUnspecified()) {
+    host = "127.0.0.1"
+  }
+
+  if conn, err := net.DialTimeout("tcp", net.JoinHostPort(host, strconv.Itoa(cfg.Server.Port)), time.Second); nil == err {
+    conn.Close()
+    return fmt.Errorf("restoring database: the server is listening on port %d; stop it first", cfg.Server.Port)
+  }
+
+  for _, suffix := range []string{"-wal", "-journal"} {
+    if _, err := os.Stat(path + suffix); nil == err {
+      return fmt.Errorf("restoring database: %s exists; the database is in use or was not closed cleanly", path+suffix)
+    }
+  }
+
+  var restoring = path + ".restoring"
+  if err := copyDurably(file, restoring); nil != err {
+    os.Remove(restoring)
+    return fmt.Errorf("restoring database: %w", err)
+  }
+
+  var previous = fmt.Sprintf("%s.before-restore-%s", path, time.Now().Format("20060102T150405"))
+  if err := os.Rename(path, previous); nil != err {
+    if !errors.Is(err, os.ErrNotExist) {
+      os.Remove(restoring)
+      return fmt.Errorf("restoring database: %w", err)
+    }
+
+    previous = ""
+  }
+
+  if err := os.Rename(restoring, path); nil != err {
+    return fmt.Errorf("restoring database: %w", err)
+  }
+
+  fmt.Fprintf(os.Stdout, "restored database %s from %s\n", path, file)
+  if "" != previous {
+    fmt.Fprintf(os.Stdout, "the database it replaced was moved to %s\n", previous)
+  }
+
+  return nil
+}
+
+// copyDurably copies the file src to dst, which must not exist, and
+// flushes it to disk.
+func copyDurably(src, dst string) error {
+  in, err := os.Open(src)
+  if nil != err {
+    return err
+  }
+
+  defer in.Close()
+
+  out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+  if nil != err {
+    return err
+  }
+
+  if _, err = io.Copy(out, in); nil != err {
+    out.Close()
+    return err
+  }
+
+  if err = out.Sync(); nil != err {
+    out.Close()
+    return err
+  }
+
+  return out.Close()
+}
+
+// manageViews runs the views subcommands. The views are counted in the
+// memory of the running server, so they are flushed by asking it to.
+func manageViews(cfg *config.Config, args []string) error {
+  if 1 != len(args) || "flush" != args[0] {
+    return errUsage
+  }
+
+  if "" == cfg.Admin.Token {
+    return errors.New("flushing views: the administration token is not set")
+  }
+
+  var host = cfg.Server.Host
+  if ip := net.ParseIP(host); "" == host || (nil != ip && ip.IsUnspecified()) {
+    host = "127.0.0.1"
+  }
+
+  var url = "http://" + net.JoinHostPort(host, strconv.Itoa(cfg.Server.Port)) + "/archive.articles.views.flush"
+
+  request, err := http.NewRequest(http.MethodPost, url, nil)
+  if nil != err {
+    return err
+  }
+
+  request.Header.Set("Authorization", "Bearer "+cfg.Admin.Token)
+
+  response, err := (&http.Client{Timeout: time.Minute}).Do(request)
+  if nil != err {
+    return fmt.Errorf("flushing views: %w", err)
+  }
+
+  defer response.Body.Close()
+
+  if http.StatusNoContent != response.StatusCode {
+    body, _ := io.ReadAll(io.LimitReader(response.Body, 4<<10))
+    return fmt.Errorf("flushing views: %s: %s", response.Status, body)
+  }
+
+  fmt.Fprintln(os.Stdout, "flushed article views")
+  return nil
+}
+
+// manageTokens runs the tokens subcommands. A token is printed only
+// when it is created; the database keeps just its hash, which the
+// running server checks the bearer tokens of the requests against.
+func manageTokens(_ *config.Config, db *database.DB, args []string) error {
+  var (
+    ctx    = context.Background()
+    tokens = service.NewTokensService(repository.NewTokensRepository(db))
+  )
+
+  switch {
+  case 2 == len(args) && "create" == args[0]:
+    token, err := tokens.Create(ctx, args[1])
+    if nil != err {
+      return fmt.Errorf("creating token: %w", err)
+    }
+
+    fmt.Fprintln(os.Stdout, token)
+    fmt.Fprintln(os.Stderr, "keep the token: it cannot be printed again")
+  case 1 == len(args) && "list" == args[0]:
+    list, err := tokens.Get(ctx)
+    if nil != err {
+      return fmt.Errorf("listing tokens: %w", err)
+    }
+
+    var table = tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+    fmt.Fprintln(table, "NAME\tCREATED")
+
+    for _, token := range list {
+      fmt.Fprintf(table, "%s\t%s\n", token.Name, token.CreatedAt.Format(time.DateTime))
+    }
+
+    return table.Flush()
+  case 2 == len(args) && "revoke" == args[0]:
+    if err := tokens.Revoke(ctx, args[1]); nil != err {
+      return fmt.Errorf("revoking token %s: %w", args[1], err)
+    }
+
+    fmt.Fprintf(os.Stdout, "revoked token %s\n", args[1])
+  default:
+    return errUsage
+  }
+
+  return nil
+}
+
+// manageUser runs the user subcommands. The password is read from the
+// first line of the standard input, so that it is left out of the
+// shell history and the list of processes.
+func manageUser(_ *config.Config, db *database.DB, args []string) error {
+  if 1 != len(args) || "set-password" != args[0] {
+    return errUsage
+  }
+
+  fmt.Fprint(os.Stderr, "password (at least 12 characters): ")
+
+  password, err := bufio.NewReader(os.Stdin).ReadString('\n')
+  if nil != err && !errors.Is(err, io.EOF) {
+    return err
+  }
+
+  password = strings.TrimRight(password, "\r\n")
+
+  var (
+    ctx = context.Background()
+    r   = repository.NewMeRepository(db)
+  )
+
+  // The server registers me when it starts, which it may not have done
+  // yet on a new database.
+  r.Register(ctx)
+
+  if err = service.NewMeService(r, nil, nil).SetPassword(ctx, password); nil != err {
+    return fmt.Errorf("setting password: %w", err)
+  }
+
+  fmt.Fprintln(os.Stdout, "password set; log in to /admin with it")
+  return nil
+}
@@ -0,0 +1,154 @@
+// Package metrics keeps the measurements of the site and exposes them
+// in the Prometheus text format.
+package metrics
+
+import (
+  "github.com/prometheus/client_golang/prometheus"
+  "github.com/prometheus/client_golang/prometheus/collectors"
+  "github.com/prometheus/client_golang/prometheus/promhttp"
+  "net/http"
+  "runtime"
+  "strings"
+  "sync"
+  "time"
+)
+
+// namespace prefixes the name of every metric of the site.
+const namespace = "fontseca"
+
+// registry holds the metrics of the site, apart from the default
+// registry of the Prometheus client, so that no dependency can sneak
+// metrics of its own into the endpoint.
+var registry = prometheus.NewRegistry()
+
+var (
+  // Requests counts the HTTP requests served by route, method and status.
+  Requests = prometheus.NewCounterVec(prometheus.CounterOpts{
+    Namespace: namespace,
+    Subsystem: "http",
+    Name:      "requests_total",
+    Help:      "HTTP requests served, by route, method and status.",
+  }, []string{"route", "method", "status"})
+
+  // RequestDuration measures how long it takes to serve the HTTP
+  // requests by route, method and status.
+  RequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+    Namespace: namespace,
+    Subsystem: "http",
+    Name:      "request_duration_seconds",
+    Help:      "Time taken to serve HTTP requests, by route, method and status.",
+    Buckets:   prometheus.DefBuckets,
+  }, []string{"route", "method", "status"})
+
+  // QueryDuration measures how long the methods of the repositories
+  // take to query the database.
+  QueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+    Namespace: namespace,
+    Subsystem: "db",
+    Name:      "query_duration_seconds",
+    Help:      "Time taken by repository methods to query the database.",
+    Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 5},
+  }, []string{"repository", "method"})
+
+  // ViewsCacheSize is the number of articles whose views are cached
+  // and waiting to be written to the database.
+  ViewsCacheSize = prometheus.NewGauge(prometheus.GaugeOpts{
+    Namespace: namespace,
+    Subsystem: "archive",
+    Name:      "views_cache_articles",
+    Help:      "Articles whose cached views are waiting to be written to the database.",
+  })
+
+  // ViewsFlushDuration measures how long it takes to write the cached
+  // views of the articles to the database.
+  ViewsFlushDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+    Namespace: namespace,
+    Subsystem: "archive",
+    Name:      "views_flush_duration_seconds",
+    Help:      "Time taken to write the cached article views to the database.",
+    Buckets:   []float64{.01, .05, .1, .5, 1, 5, 10, 30, 60},
+  })
+
+  // BrokenLinksCleaned counts the expired share links removed from
+  // the database.
+  BrokenLinksCleaned = prometheus.NewCounter(prometheus.CounterOpts{
+    Namespace: namespace,
+    Subsystem: "archive",
+    Name:      "broken_links_cleaned_total",
+    Help:      "Expired share links removed from the database.",
+  })
+)
+
+func init() {
+  registry.MustRegister(
+    collectors.NewGoCollector(),
+    collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+    Requests,
+    RequestDuration,
+    QueryDuration,
+    ViewsCacheSize,
+    ViewsFlushDuration,
+    BrokenLinksCleaned,
+  )
+}
+
+// Handler serves the metrics of the site in the Prometheus text format.
+func Handler() http.Handler {
+  return promhttp.HandlerFor(registry, promhttp.HandlerOpts{Registry: registry})
+}
+
+// methods caches the repository and method names of the callers of
+// ObserveQuery, by program counter.
+var methods sync.Map
+
+type method struct {
+  repository string
+  name       string
+}
+
+// ObserveQuery records the time elapsed since start as a query of the
+// repository method that calls it. It is meant to be deferred at the
+// beginning of the method:
+//
+//	defer metrics.ObserveQuery(time.Now())
+//
+// The repository is named after the receiver type of the method, so
+// (*archiveRepository).GetByID is recorded as method GetByID of the
+// repository archive.
+func ObserveQuery(start time.Time) {
+  var pc, _, _, ok = runtime.Caller(1)
+  if !ok {
+    return
+  }
+
+  var m, cached = methods.Load(pc)
+  if !cached {
+    m, _ = methods.LoadOrStore(pc, callerMethod(pc))
+  }
+
+  var caller = m.(method)
+  QueryDuration.WithLabelValues(caller.repository, caller.name).Observe(time.Since(start).Seconds())
+}
+
+// callerMethod names the method at pc after a function name such as
+// 'fontseca.dev/repository.(*archiveRepository).GetByID'.
+func callerMethod(pc uintptr) method {
+  var fn = runtime.FuncForPC(pc)
+  if nil == fn {
+    return method{repository: "unknown", name: "unknown"}
+  }
+
+  var name = fn.Name()
+  name = name[1+strings.LastIndex(name, "/"):]
+
+  var parts = strings.Split(name, ".")
+  if 3 > len(parts) {
+    return method{repository: "unknown", name: parts[len(parts)-1]}
+  }
+
+  var receiver = strings.Trim(parts[1], "(*)")
+  receiver = strings.TrimSuffix(receiver, "Impl")
+  receiver = strings.TrimSuffix(receiver, "Repository")
+
+  return method{repository: receiver, name: parts[2]}
+}
@@ -0,0 +1,56 @@
+package metrics
+
+import (
+  "github.com/prometheus/client_golang/prometheus/testutil"
+  "github.com/stretchr/testify/assert"
+  "net/http"
+  "net/http/httptest"
+  "strings"
+  "testing"
+  "time"
+)
+
+type archiveRepository struct{}
+
+func (r *archiveRepository) GetByID() {
+  defer ObserveQuery(time.Now())
+}
+
+type meRepositoryImpl struct{}
+
+func (r *meRepositoryImpl) Get() {
+  defer ObserveQuery(time.Now())
+}
+
+func TestObserveQuery(t *testing.T) {
+  var before = testutil.CollectAndCount(QueryDuration)
+
+  new(archiveRepository).GetByID()
+  new(archiveRepository).GetByID()
+  new(meRepositoryImpl).Get()
+
+  assert.Equal(t, before+2, testutil.CollectAndCount(QueryDuration))
+
+  var expected = `
+# HELP fontseca_db_query_duration_seconds Time taken by repository methods to query the database.
+# TYPE fontseca_db_query_duration_seconds histogram
+`
+  var body = scrape(t)
+  assert.Contains(t, body, strings.TrimSpace(expected))
+  assert.Contains(t, body, `fontseca_db_query_duration_seconds_count{method="GetByID",repository="archive"} 2`)
+  assert.Contains(t, body, `fontseca_db_query_duration_seconds_count{method="Get",repository="me"} 1`)
+}
+
+func TestHandler(t *testing.T) {
+  var body = scrape(t)
+  assert.Contains(t, body, "go_goroutines")
+  assert.Contains(t, body, "fontseca_archive_views_cache_articles 0")
+  assert.Contains(t, body, "fontseca_archive_broken_links_cleaned_total 0")
+}
+
+func scrape(t *testing.T) string {
+  var recorder = httptest.NewRecorder()
+  Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+  assert.Equal(t, http.StatusOK, recorder.Code)
+  return recorder.Body.String()
+}
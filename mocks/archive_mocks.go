@@ -6,6 +6,8 @@ import (
   "fontseca.dev/transfer"
   "github.com/google/uuid"
   "github.com/stretchr/testify/mock"
+  "io"
+  "io/fs"
 )
 
 type ArchiveRepository struct {
@@ -40,6 +42,11 @@ func (o *ArchiveRepository) Publications(ctx context.Context) (publications []*t
   return publications, args.Error(1)
 }
 
+func (o *ArchiveRepository) Count(ctx context.Context, filter *transfer.ArticleFilter, hidden, draftsOnly bool) (total int, err error) {
+  args := o.Called(ctx, filter, hidden, draftsOnly)
+  return args.Int(0), args.Error(1)
+}
+
 func (o *ArchiveRepository) Get(ctx context.Context, filter *transfer.ArticleFilter, hidden, draftsOnly bool) (articles []*transfer.Article, err error) {
   args := o.Called(ctx, filter, hidden, draftsOnly)
   arg0 := args.Get(0)
@@ -84,14 +91,19 @@ func (o *ArchiveRepository) GetByID(ctx context.Context, id string, isDraft bool
   return article, args.Error(1)
 }
 
-func (o *ArchiveRepository) Amend(ctx context.Context, id string) error {
-  return o.Called(ctx, id).Error(0)
+func (o *ArchiveRepository) Amend(ctx context.Context, id string) (patchID string, err error) {
+  args := o.Called(ctx, id)
+  return args.String(0), args.Error(1)
 }
 
 func (o *ArchiveRepository) Remove(ctx context.Context, id string) error {
   return o.Called(ctx, id).Error(0)
 }
 
+func (o *ArchiveRepository) Restore(ctx context.Context, id string) error {
+  return o.Called(ctx, id).Error(0)
+}
+
 func (o *ArchiveRepository) AddTag(ctx context.Context, articleID, tagID string, isDraft ...bool) error {
   return o.Called(ctx, articleID, tagID, isDraft).Error(0)
 }
@@ -136,10 +148,48 @@ func (o *ArchiveRepository) GetPatches(ctx context.Context) (patches []*model.Ar
   return patches, args.Error(1)
 }
 
+func (o *ArchiveRepository) ListPatches(ctx context.Context, page *transfer.PageRequest) (patches *transfer.Page[*model.ArticlePatch], err error) {
+  var args = o.Called(ctx, page)
+  var arg0 = args.Get(0)
+  if nil != arg0 {
+    patches = arg0.(*transfer.Page[*model.ArticlePatch])
+  }
+  return patches, args.Error(1)
+}
+
+func (o *ArchiveRepository) Import(ctx context.Context, article *transfer.ArticleImport) (id, status string, err error) {
+  args := o.Called(ctx, article)
+  return args.String(0), args.String(1), args.Error(2)
+}
+
+func (o *ArchiveRepository) GetPatch(ctx context.Context, id string) (patch *model.ArticlePatch, err error) {
+  args := o.Called(ctx, id)
+  arg0 := args.Get(0)
+
+  if nil != arg0 {
+    patch = arg0.(*model.ArticlePatch)
+  }
+
+  return patch, args.Error(1)
+}
+
+func (o *ArchiveRepository) FlushViews(ctx context.Context) error {
+  return o.Called(ctx).Error(0)
+}
+
+func (o *ArchiveRepository) PruneLinks(ctx context.Context) (removed int64, err error) {
+  args := o.Called(ctx)
+  return args.Get(0).(int64), args.Error(1)
+}
+
 func (o *ArchiveRepository) Close() {
   o.Called()
 }
 
+func (o *ArchiveRepository) Running() bool {
+  return o.Called().Bool(0)
+}
+
 type DraftsService struct {
   mock.Mock
 }
@@ -153,8 +203,13 @@ func (o *DraftsService) Draft(ctx context.Context, creation *transfer.ArticleCre
   return args.Get(0).(uuid.UUID), args.Error(1)
 }
 
-func (o *DraftsService) Publish(ctx context.Context, draftUUID string) error {
-  return o.Called(ctx, draftUUID).Error(0)
+func (o *DraftsService) Publish(ctx context.Context, draftUUID string, tags ...string) error {
+  args := []any{ctx, draftUUID}
+  for _, tag := range tags {
+    args = append(args, tag)
+  }
+
+  return o.Called(args...).Error(0)
 }
 
 func (o *DraftsService) Get(ctx context.Context, filter *transfer.ArticleFilter) (drafts []*transfer.Article, err error) {
@@ -179,6 +234,11 @@ func (o *DraftsService) GetOne(ctx context.Context, request *transfer.ArticleReq
   return article, args.Error(1)
 }
 
+func (o *DraftsService) Count(ctx context.Context, filter *transfer.ArticleFilter) (total int, err error) {
+  args := o.Called(ctx, filter)
+  return args.Int(0), args.Error(1)
+}
+
 func (o *DraftsService) GetByLink(ctx context.Context, link string) (article *model.Article, err error) {
   args := o.Called(ctx, link)
   arg0 := args.Get(0)
@@ -222,6 +282,11 @@ func (o *DraftsService) Revise(ctx context.Context, draftUUID string, revision *
   return o.Called(ctx, draftUUID, revision).Error(0)
 }
 
+func (o *DraftsService) Measure(title, content string) (words, readTime int) {
+  var args = o.Called(title, content)
+  return args.Int(0), args.Int(1)
+}
+
 type ArticlesService struct {
   mock.Mock
 }
@@ -241,6 +306,11 @@ func (o *ArticlesService) Get(ctx context.Context, filter *transfer.ArticleFilte
   return articles, args.Error(1)
 }
 
+func (o *ArticlesService) Count(ctx context.Context, filter *transfer.ArticleFilter, hidden bool) (total int, err error) {
+  args := o.Called(ctx, filter, hidden)
+  return args.Int(0), args.Error(1)
+}
+
 func (o *ArticlesService) Publications(ctx context.Context) (publications []*transfer.Publication, err error) {
   args := o.Called(ctx)
   arg0 := args.Get(0)
@@ -285,6 +355,10 @@ func (o *ArticlesService) GetByID(ctx context.Context, id string) (article *mode
   return article, args.Error(1)
 }
 
+func (o *ArticlesService) FlushViews(ctx context.Context) error {
+  return o.Called(ctx).Error(0)
+}
+
 func (o *ArticlesService) Hide(ctx context.Context, id string) error {
   return o.Called(ctx, id).Error(0)
 }
@@ -293,8 +367,9 @@ func (o *ArticlesService) Show(ctx context.Context, id string) error {
   return o.Called(ctx, id).Error(0)
 }
 
-func (o *ArticlesService) Amend(ctx context.Context, id string) error {
-  return o.Called(ctx, id).Error(0)
+func (o *ArticlesService) Amend(ctx context.Context, id string) (patchUUID uuid.UUID, err error) {
+  args := o.Called(ctx, id)
+  return args.Get(0).(uuid.UUID), args.Error(1)
 }
 
 func (o *ArticlesService) SetSlug(ctx context.Context, id, slug string) error {
@@ -340,6 +415,26 @@ func (o *PatchesService) Get(ctx context.Context) (patches []*model.ArticlePatch
   return patches, args.Error(1)
 }
 
+func (o *PatchesService) List(ctx context.Context, page *transfer.PageRequest) (patches *transfer.Page[*model.ArticlePatch], err error) {
+  var args = o.Called(ctx, page)
+  var arg0 = args.Get(0)
+  if nil != arg0 {
+    patches = arg0.(*transfer.Page[*model.ArticlePatch])
+  }
+  return patches, args.Error(1)
+}
+
+func (o *PatchesService) Diff(ctx context.Context, id string) (diff *transfer.ArticlePatchDiff, err error) {
+  args := o.Called(ctx, id)
+  arg0 := args.Get(0)
+
+  if nil != arg0 {
+    diff = arg0.(*transfer.ArticlePatchDiff)
+  }
+
+  return diff, args.Error(1)
+}
+
 func (o *PatchesService) Revise(ctx context.Context, id string, revision *transfer.ArticleRevision) error {
   return o.Called(ctx, id, revision).Error(0)
 }
@@ -356,3 +451,33 @@ func (o *PatchesService) Discard(ctx context.Context, id string) error {
 func (o *PatchesService) Release(ctx context.Context, id string) error {
   return o.Called(ctx, id).Error(0)
 }
+
+type ImportService struct {
+  mock.Mock
+}
+
+func NewImportService() *ImportService {
+  return new(ImportService)
+}
+
+func (o *ImportService) Import(ctx context.Context, name string, r io.Reader) (result *transfer.ArticleImportResult, err error) {
+  args := o.Called(ctx, name, r)
+  arg0 := args.Get(0)
+
+  if nil != arg0 {
+    result = arg0.(*transfer.ArticleImportResult)
+  }
+
+  return result, args.Error(1)
+}
+
+func (o *ImportService) ImportAll(ctx context.Context, fsys fs.FS) (results []*transfer.ArticleImportResult, err error) {
+  args := o.Called(ctx, fsys)
+  arg0 := args.Get(0)
+
+  if nil != arg0 {
+    results = arg0.([]*transfer.ArticleImportResult)
+  }
+
+  return results, args.Error(1)
+}
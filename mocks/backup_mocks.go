@@ -0,0 +1,54 @@
+package mocks
+
+import (
+  "context"
+  "fontseca.dev/transfer"
+  "github.com/stretchr/testify/mock"
+  "time"
+)
+
+type BackupsRepository struct {
+  mock.Mock
+}
+
+func NewBackupsRepository() *BackupsRepository {
+  return new(BackupsRepository)
+}
+
+func (o *BackupsRepository) Backup(ctx context.Context, file string) error {
+  return o.Called(ctx, file).Error(0)
+}
+
+type BackupsService struct {
+  mock.Mock
+}
+
+func NewBackupsService() *BackupsService {
+  return new(BackupsService)
+}
+
+func (o *BackupsService) Create(ctx context.Context) (backup *transfer.Backup, err error) {
+  args := o.Called(ctx)
+  arg0 := args.Get(0)
+
+  if arg0 != nil {
+    backup = arg0.(*transfer.Backup)
+  }
+
+  return backup, args.Error(1)
+}
+
+func (o *BackupsService) Get(ctx context.Context) (backups []*transfer.Backup, err error) {
+  args := o.Called(ctx)
+  arg0 := args.Get(0)
+
+  if arg0 != nil {
+    backups = arg0.([]*transfer.Backup)
+  }
+
+  return backups, args.Error(1)
+}
+
+func (o *BackupsService) Run(ctx context.Context, interval time.Duration) {
+  o.Called(ctx, interval)
+}
@@ -24,6 +24,15 @@ func (o *ExperienceRepository) Get(ctx context.Context, hidden bool) (experience
   return experience, args.Error(1)
 }
 
+func (o *ExperienceRepository) List(ctx context.Context, hidden bool, page *transfer.PageRequest) (experience *transfer.Page[*model.Experience], err error) {
+  var args = o.Called(ctx, hidden, page)
+  var arg0 = args.Get(0)
+  if nil != arg0 {
+    experience = arg0.(*transfer.Page[*model.Experience])
+  }
+  return experience, args.Error(1)
+}
+
 func (o *ExperienceRepository) GetByID(ctx context.Context, id string) (experience *model.Experience, err error) {
   var args = o.Called(ctx, id)
   var arg0 = args.Get(0)
@@ -48,6 +57,11 @@ func (o *ExperienceRepository) Remove(ctx context.Context, id string) error {
   return args.Error(0)
 }
 
+func (o *ExperienceRepository) Restore(ctx context.Context, id string) error {
+  var args = o.Called(ctx, id)
+  return args.Error(0)
+}
+
 type ExperienceService struct {
   mock.Mock
 }
@@ -65,6 +79,15 @@ func (o *ExperienceService) Get(ctx context.Context, hidden ...bool) (experience
   return experience, args.Error(1)
 }
 
+func (o *ExperienceService) List(ctx context.Context, hidden bool, page *transfer.PageRequest) (experience *transfer.Page[*model.Experience], err error) {
+  var args = o.Called(ctx, hidden, page)
+  var arg0 = args.Get(0)
+  if nil != arg0 {
+    experience = arg0.(*transfer.Page[*model.Experience])
+  }
+  return experience, args.Error(1)
+}
+
 func (o *ExperienceService) GetByID(ctx context.Context, id string) (experience *model.Experience, err error) {
   var args = o.Called(ctx, id)
   var arg0 = args.Get(0)
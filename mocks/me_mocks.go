@@ -33,6 +33,15 @@ func (o *MeRepository) Update(ctx context.Context, update *transfer.MeUpdate) (o
   return args.Bool(0), args.Error(1)
 }
 
+func (o *MeRepository) SetPasswordHash(ctx context.Context, hash string) error {
+  return o.Called(ctx, hash).Error(0)
+}
+
+func (o *MeRepository) PasswordHash(ctx context.Context) (hash string, err error) {
+  var args = o.Called(ctx)
+  return args.String(0), args.Error(1)
+}
+
 type MeService struct {
   mock.Mock
 }
@@ -54,3 +63,12 @@ func (o *MeService) Update(ctx context.Context, update *transfer.MeUpdate) (ok b
   var args = o.Called(ctx, update)
   return args.Bool(0), args.Error(1)
 }
+
+func (o *MeService) SetPassword(ctx context.Context, password string) error {
+  return o.Called(ctx, password).Error(0)
+}
+
+func (o *MeService) CheckPassword(ctx context.Context, password string) (ok bool, err error) {
+  var args = o.Called(ctx, password)
+  return args.Bool(0), args.Error(1)
+}
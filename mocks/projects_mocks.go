@@ -24,6 +24,15 @@ func (o *ProjectsRepository) Get(ctx context.Context, archived bool) (projects [
   return projects, args.Error(1)
 }
 
+func (o *ProjectsRepository) List(ctx context.Context, archived bool, page *transfer.PageRequest) (projects *transfer.Page[*model.Project], err error) {
+  var args = o.Called(ctx, archived, page)
+  var arg0 = args.Get(0)
+  if nil != arg0 {
+    projects = arg0.(*transfer.Page[*model.Project])
+  }
+  return projects, args.Error(1)
+}
+
 func (o *ProjectsRepository) GetByID(ctx context.Context, id string) (project *model.Project, err error) {
   var args = o.Called(ctx, id)
   var arg0 = args.Get(0)
@@ -57,8 +66,8 @@ func (o *ProjectsRepository) Update(ctx context.Context, id string, update *tran
   return args.Bool(0), args.Error(1)
 }
 
-func (o *ProjectsRepository) Unarchive(ctx context.Context, id string) (unarchived bool, err error) {
-  var args = o.Called(ctx, id)
+func (o *ProjectsRepository) Unarchive(ctx context.Context, id string, version int) (unarchived bool, err error) {
+  var args = o.Called(ctx, id, version)
   return args.Bool(0), args.Error(1)
 }
 
@@ -67,6 +76,11 @@ func (o *ProjectsRepository) Remove(ctx context.Context, id string) (err error)
   return args.Error(0)
 }
 
+func (o *ProjectsRepository) Restore(ctx context.Context, id string) (err error) {
+  var args = o.Called(ctx, id)
+  return args.Error(0)
+}
+
 func (o *ProjectsRepository) ContainsTechnologyTag(ctx context.Context, projectID, technologyTagID string) (success bool, err error) {
   var args = o.Called(ctx, projectID, technologyTagID)
   return args.Bool(0), args.Error(1)
@@ -99,6 +113,15 @@ func (o *ProjectsService) Get(ctx context.Context, archived ...bool) (projects [
   return projects, args.Error(1)
 }
 
+func (o *ProjectsService) List(ctx context.Context, archived bool, page *transfer.PageRequest) (projects *transfer.Page[*model.Project], err error) {
+  var args = o.Called(ctx, archived, page)
+  var arg0 = args.Get(0)
+  if nil != arg0 {
+    projects = arg0.(*transfer.Page[*model.Project])
+  }
+  return projects, args.Error(1)
+}
+
 func (o *ProjectsService) GetByID(ctx context.Context, id string) (project *model.Project, err error) {
   var args = o.Called(ctx, id)
   var arg0 = args.Get(0)
@@ -132,8 +155,8 @@ func (o *ProjectsService) Update(ctx context.Context, id string, update *transfe
   return args.Bool(0), args.Error(1)
 }
 
-func (o *ProjectsService) Unarchive(ctx context.Context, id string) (unarchived bool, err error) {
-  var args = o.Called(ctx, id)
+func (o *ProjectsService) Unarchive(ctx context.Context, id string, version int) (unarchived bool, err error) {
+  var args = o.Called(ctx, id, version)
   return args.Bool(0), args.Error(1)
 }
 
@@ -0,0 +1,45 @@
+package mocks
+
+import (
+  "context"
+  "fontseca.dev/transfer"
+  "github.com/stretchr/testify/mock"
+  "io"
+)
+
+type SiteRepository struct {
+  mock.Mock
+}
+
+func NewSiteRepository() *SiteRepository {
+  return new(SiteRepository)
+}
+
+func (o *SiteRepository) Dump(ctx context.Context) (dump *transfer.SiteDump, err error) {
+  var args = o.Called(ctx)
+  var arg0 = args.Get(0)
+  if nil != arg0 {
+    dump = arg0.(*transfer.SiteDump)
+  }
+  return dump, args.Error(1)
+}
+
+func (o *SiteRepository) Restore(ctx context.Context, dump *transfer.SiteDump) error {
+  return o.Called(ctx, dump).Error(0)
+}
+
+type SiteService struct {
+  mock.Mock
+}
+
+func NewSiteService() *SiteService {
+  return new(SiteService)
+}
+
+func (o *SiteService) Export(ctx context.Context, w io.Writer) error {
+  return o.Called(ctx, w).Error(0)
+}
+
+func (o *SiteService) Restore(ctx context.Context, r io.Reader) error {
+  return o.Called(ctx, r).Error(0)
+}
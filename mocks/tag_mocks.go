@@ -30,6 +30,15 @@ func (o *TagsRepository) Get(ctx context.Context) (tags []*model.Tag, err error)
   return tags, args.Error(1)
 }
 
+func (o *TagsRepository) List(ctx context.Context, page *transfer.PageRequest) (tags *transfer.Page[*model.Tag], err error) {
+  var args = o.Called(ctx, page)
+  var arg0 = args.Get(0)
+  if nil != arg0 {
+    tags = arg0.(*transfer.Page[*model.Tag])
+  }
+  return tags, args.Error(1)
+}
+
 func (o *TagsRepository) Update(ctx context.Context, id string, update *transfer.TagUpdate) error {
   return o.Called(ctx, id, update).Error(0)
 }
@@ -37,3 +46,62 @@ func (o *TagsRepository) Update(ctx context.Context, id string, update *transfer
 func (o *TagsRepository) Remove(ctx context.Context, id string) error {
   return o.Called(ctx, id).Error(0)
 }
+
+func (o *TagsRepository) Restore(ctx context.Context, id string) error {
+  return o.Called(ctx, id).Error(0)
+}
+
+type TagsService struct {
+  mock.Mock
+}
+
+func NewTagsService() *TagsService {
+  return new(TagsService)
+}
+
+func (o *TagsService) Add(ctx context.Context, creation *transfer.TagCreation) error {
+  return o.Called(ctx, creation).Error(0)
+}
+
+func (o *TagsService) Get(ctx context.Context) (tags []*model.Tag, err error) {
+  args := o.Called(ctx)
+  arg0 := args.Get(0)
+
+  if arg0 != nil {
+    tags = arg0.([]*model.Tag)
+  }
+
+  return tags, args.Error(1)
+}
+
+func (o *TagsService) List(ctx context.Context, page *transfer.PageRequest) (tags *transfer.Page[*model.Tag], err error) {
+  var args = o.Called(ctx, page)
+  var arg0 = args.Get(0)
+  if nil != arg0 {
+    tags = arg0.(*transfer.Page[*model.Tag])
+  }
+  return tags, args.Error(1)
+}
+
+func (o *TagsService) GetByID(ctx context.Context, id string) (tag *model.Tag, err error) {
+  args := o.Called(ctx, id)
+  arg0 := args.Get(0)
+
+  if arg0 != nil {
+    tag = arg0.(*model.Tag)
+  }
+
+  return tag, args.Error(1)
+}
+
+func (o *TagsService) Update(ctx context.Context, id string, update *transfer.TagUpdate) error {
+  return o.Called(ctx, id, update).Error(0)
+}
+
+func (o *TagsService) Remove(ctx context.Context, id string) error {
+  return o.Called(ctx, id).Error(0)
+}
+
+func (o *TagsService) Restore(ctx context.Context, id string) error {
+  return o.Called(ctx, id).Error(0)
+}
@@ -24,6 +24,15 @@ func (o *TechnologyTagRepository) Get(ctx context.Context) (technologies []*mode
   return technologies, args.Error(1)
 }
 
+func (o *TechnologyTagRepository) List(ctx context.Context, page *transfer.PageRequest) (technologies *transfer.Page[*model.TechnologyTag], err error) {
+  var args = o.Called(ctx, page)
+  var arg0 = args.Get(0)
+  if nil != arg0 {
+    technologies = arg0.(*transfer.Page[*model.TechnologyTag])
+  }
+  return technologies, args.Error(1)
+}
+
 func (o *TechnologyTagRepository) Add(ctx context.Context, creation *transfer.TechnologyTagCreation) (id string, err error) {
   var args = o.Called(ctx, creation)
   return args.String(0), args.Error(1)
@@ -61,6 +70,24 @@ func (o *TechnologyTagService) Get(ctx context.Context) (technologies []*model.T
   return technologies, args.Error(1)
 }
 
+func (o *TechnologyTagService) List(ctx context.Context, page *transfer.PageRequest) (technologies *transfer.Page[*model.TechnologyTag], err error) {
+  var args = o.Called(ctx, page)
+  var arg0 = args.Get(0)
+  if nil != arg0 {
+    technologies = arg0.(*transfer.Page[*model.TechnologyTag])
+  }
+  return technologies, args.Error(1)
+}
+
+func (o *TechnologyTagService) GetByID(ctx context.Context, id string) (technology *model.TechnologyTag, err error) {
+  var args = o.Called(ctx, id)
+  var arg0 = args.Get(0)
+  if nil != arg0 {
+    technology = arg0.(*model.TechnologyTag)
+  }
+  return technology, args.Error(1)
+}
+
 func (o *TechnologyTagService) Add(ctx context.Context, creation *transfer.TechnologyTagCreation) (id string, err error) {
   var args = o.Called(ctx, creation)
   return args.String(0), args.Error(1)
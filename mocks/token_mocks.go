@@ -0,0 +1,72 @@
+package mocks
+
+import (
+  "context"
+  "fontseca.dev/model"
+  "github.com/stretchr/testify/mock"
+)
+
+type TokensRepository struct {
+  mock.Mock
+}
+
+func NewTokensRepository() *TokensRepository {
+  return new(TokensRepository)
+}
+
+func (o *TokensRepository) Add(ctx context.Context, name, hash string) error {
+  return o.Called(ctx, name, hash).Error(0)
+}
+
+func (o *TokensRepository) Exists(ctx context.Context, hash string) (bool, error) {
+  args := o.Called(ctx, hash)
+  return args.Bool(0), args.Error(1)
+}
+
+func (o *TokensRepository) Get(ctx context.Context) (tokens []*model.Token, err error) {
+  args := o.Called(ctx)
+  arg0 := args.Get(0)
+
+  if arg0 != nil {
+    tokens = arg0.([]*model.Token)
+  }
+
+  return tokens, args.Error(1)
+}
+
+func (o *TokensRepository) Remove(ctx context.Context, name string) error {
+  return o.Called(ctx, name).Error(0)
+}
+
+type TokensService struct {
+  mock.Mock
+}
+
+func NewTokensService() *TokensService {
+  return new(TokensService)
+}
+
+func (o *TokensService) Create(ctx context.Context, name string) (string, error) {
+  args := o.Called(ctx, name)
+  return args.String(0), args.Error(1)
+}
+
+func (o *TokensService) Check(ctx context.Context, token string) (bool, error) {
+  args := o.Called(ctx, token)
+  return args.Bool(0), args.Error(1)
+}
+
+func (o *TokensService) Get(ctx context.Context) (tokens []*model.Token, err error) {
+  args := o.Called(ctx)
+  arg0 := args.Get(0)
+
+  if arg0 != nil {
+    tokens = arg0.([]*model.Token)
+  }
+
+  return tokens, args.Error(1)
+}
+
+func (o *TokensService) Revoke(ctx context.Context, name string) error {
+  return o.Called(ctx, name).Error(0)
+}
@@ -30,6 +30,15 @@ func (o *TopicsRepository) Get(ctx context.Context) (topics []*model.Topic, err
   return topics, args.Error(1)
 }
 
+func (o *TopicsRepository) List(ctx context.Context, page *transfer.PageRequest) (topics *transfer.Page[*model.Topic], err error) {
+  var args = o.Called(ctx, page)
+  var arg0 = args.Get(0)
+  if nil != arg0 {
+    topics = arg0.(*transfer.Page[*model.Topic])
+  }
+  return topics, args.Error(1)
+}
+
 func (o *TopicsRepository) Update(ctx context.Context, id string, update *transfer.TopicUpdate) error {
   return o.Called(ctx, id, update).Error(0)
 }
@@ -37,3 +46,62 @@ func (o *TopicsRepository) Update(ctx context.Context, id string, update *transf
 func (o *TopicsRepository) Remove(ctx context.Context, id string) error {
   return o.Called(ctx, id).Error(0)
 }
+
+func (o *TopicsRepository) Restore(ctx context.Context, id string) error {
+  return o.Called(ctx, id).Error(0)
+}
+
+type TopicsService struct {
+  mock.Mock
+}
+
+func NewTopicsService() *TopicsService {
+  return new(TopicsService)
+}
+
+func (o *TopicsService) Add(ctx context.Context, creation *transfer.TopicCreation) error {
+  return o.Called(ctx, creation).Error(0)
+}
+
+func (o *TopicsService) Get(ctx context.Context) (topics []*model.Topic, err error) {
+  args := o.Called(ctx)
+  arg0 := args.Get(0)
+
+  if arg0 != nil {
+    topics = arg0.([]*model.Topic)
+  }
+
+  return topics, args.Error(1)
+}
+
+func (o *TopicsService) List(ctx context.Context, page *transfer.PageRequest) (topics *transfer.Page[*model.Topic], err error) {
+  var args = o.Called(ctx, page)
+  var arg0 = args.Get(0)
+  if nil != arg0 {
+    topics = arg0.(*transfer.Page[*model.Topic])
+  }
+  return topics, args.Error(1)
+}
+
+func (o *TopicsService) GetByID(ctx context.Context, id string) (topic *model.Topic, err error) {
+  args := o.Called(ctx, id)
+  arg0 := args.Get(0)
+
+  if arg0 != nil {
+    topic = arg0.(*model.Topic)
+  }
+
+  return topic, args.Error(1)
+}
+
+func (o *TopicsService) Update(ctx context.Context, id string, update *transfer.TopicUpdate) error {
+  return o.Called(ctx, id, update).Error(0)
+}
+
+func (o *TopicsService) Remove(ctx context.Context, id string) error {
+  return o.Called(ctx, id).Error(0)
+}
+
+func (o *TopicsService) Restore(ctx context.Context, id string) error {
+  return o.Called(ctx, id).Error(0)
+}
@@ -0,0 +1,60 @@
+package mocks
+
+import (
+  "context"
+  "fontseca.dev/transfer"
+  "github.com/stretchr/testify/mock"
+  "time"
+)
+
+type TrashRepository struct {
+  mock.Mock
+}
+
+func NewTrashRepository() *TrashRepository {
+  return new(TrashRepository)
+}
+
+func (o *TrashRepository) Get(ctx context.Context, page *transfer.PageRequest) (items *transfer.Page[*transfer.TrashedItem], err error) {
+  var args = o.Called(ctx, page)
+  var arg0 = args.Get(0)
+  if nil != arg0 {
+    items = arg0.(*transfer.Page[*transfer.TrashedItem])
+  }
+  return items, args.Error(1)
+}
+
+func (o *TrashRepository) Purge(ctx context.Context, before time.Time) (purged int64, err error) {
+  args := o.Called(ctx, before)
+  return args.Get(0).(int64), args.Error(1)
+}
+
+type TrashService struct {
+  mock.Mock
+}
+
+func NewTrashService() *TrashService {
+  return new(TrashService)
+}
+
+func (o *TrashService) Get(ctx context.Context, page *transfer.PageRequest) (items *transfer.Page[*transfer.TrashedItem], err error) {
+  var args = o.Called(ctx, page)
+  var arg0 = args.Get(0)
+  if nil != arg0 {
+    items = arg0.(*transfer.Page[*transfer.TrashedItem])
+  }
+  return items, args.Error(1)
+}
+
+func (o *TrashService) Restore(ctx context.Context, kind, id string) error {
+  return o.Called(ctx, kind, id).Error(0)
+}
+
+func (o *TrashService) Purge(ctx context.Context) (purged int64, err error) {
+  args := o.Called(ctx)
+  return args.Get(0).(int64), args.Error(1)
+}
+
+func (o *TrashService) Run(ctx context.Context, interval time.Duration) {
+  o.Called(ctx, interval)
+}
@@ -0,0 +1,32 @@
+package mocks
+
+import (
+  "context"
+  "github.com/stretchr/testify/mock"
+)
+
+type UnitOfWork struct {
+  mock.Mock
+}
+
+func NewUnitOfWork() *UnitOfWork {
+  return new(UnitOfWork)
+}
+
+// Do runs fn unless an error has been set up for it to return.
+func (o *UnitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+  if err := o.Called(ctx).Error(0); nil != err {
+    return err
+  }
+
+  return fn(ctx)
+}
+
+// Read runs fn unless an error has been set up for it to return.
+func (o *UnitOfWork) Read(ctx context.Context, fn func(ctx context.Context) error) error {
+  if err := o.Called(ctx).Error(0); nil != err {
+    return err
+  }
+
+  return fn(ctx)
+}
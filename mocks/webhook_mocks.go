@@ -0,0 +1,124 @@
+package mocks
+
+import (
+  "context"
+  "fontseca.dev/model"
+  "fontseca.dev/transfer"
+  "github.com/stretchr/testify/mock"
+  "time"
+)
+
+type WebhooksRepository struct {
+  mock.Mock
+}
+
+func NewWebhooksRepository() *WebhooksRepository {
+  return new(WebhooksRepository)
+}
+
+func (o *WebhooksRepository) Add(ctx context.Context, creation *transfer.WebhookCreation) (id string, err error) {
+  args := o.Called(ctx, creation)
+  return args.String(0), args.Error(1)
+}
+
+func (o *WebhooksRepository) Get(ctx context.Context, page *transfer.PageRequest) (webhooks *transfer.Page[*model.Webhook], err error) {
+  var args = o.Called(ctx, page)
+  var arg0 = args.Get(0)
+  if nil != arg0 {
+    webhooks = arg0.(*transfer.Page[*model.Webhook])
+  }
+  return webhooks, args.Error(1)
+}
+
+func (o *WebhooksRepository) Remove(ctx context.Context, id string) error {
+  return o.Called(ctx, id).Error(0)
+}
+
+func (o *WebhooksRepository) Enqueue(ctx context.Context, event string, payload []byte) error {
+  return o.Called(ctx, event, payload).Error(0)
+}
+
+func (o *WebhooksRepository) Due(ctx context.Context, limit int) (deliveries []*model.WebhookDelivery, err error) {
+  args := o.Called(ctx, limit)
+  arg0 := args.Get(0)
+
+  if arg0 != nil {
+    deliveries = arg0.([]*model.WebhookDelivery)
+  }
+
+  return deliveries, args.Error(1)
+}
+
+func (o *WebhooksRepository) Record(ctx context.Context, attempt *transfer.WebhookAttempt) error {
+  return o.Called(ctx, attempt).Error(0)
+}
+
+func (o *WebhooksRepository) Deliveries(ctx context.Context, webhookID string, page *transfer.PageRequest) (deliveries *transfer.Page[*model.WebhookDelivery], err error) {
+  var args = o.Called(ctx, webhookID, page)
+  var arg0 = args.Get(0)
+  if nil != arg0 {
+    deliveries = arg0.(*transfer.Page[*model.WebhookDelivery])
+  }
+  return deliveries, args.Error(1)
+}
+
+type WebhooksService struct {
+  mock.Mock
+}
+
+func NewWebhooksService() *WebhooksService {
+  return new(WebhooksService)
+}
+
+func (o *WebhooksService) Publish(ctx context.Context, event string, data any) error {
+  return o.Called(ctx, event, data).Error(0)
+}
+
+func (o *WebhooksService) Add(ctx context.Context, creation *transfer.WebhookCreation) (id, secret string, err error) {
+  args := o.Called(ctx, creation)
+  return args.String(0), args.String(1), args.Error(2)
+}
+
+func (o *WebhooksService) Get(ctx context.Context, page *transfer.PageRequest) (webhooks *transfer.Page[*model.Webhook], err error) {
+  var args = o.Called(ctx, page)
+  var arg0 = args.Get(0)
+  if nil != arg0 {
+    webhooks = arg0.(*transfer.Page[*model.Webhook])
+  }
+  return webhooks, args.Error(1)
+}
+
+func (o *WebhooksService) Remove(ctx context.Context, id string) error {
+  return o.Called(ctx, id).Error(0)
+}
+
+func (o *WebhooksService) Deliveries(ctx context.Context, webhookID string, page *transfer.PageRequest) (deliveries *transfer.Page[*model.WebhookDelivery], err error) {
+  var args = o.Called(ctx, webhookID, page)
+  var arg0 = args.Get(0)
+  if nil != arg0 {
+    deliveries = arg0.(*transfer.Page[*model.WebhookDelivery])
+  }
+  return deliveries, args.Error(1)
+}
+
+func (o *WebhooksService) Deliver(ctx context.Context) (delivered int, err error) {
+  args := o.Called(ctx)
+  return args.Int(0), args.Error(1)
+}
+
+func (o *WebhooksService) Run(ctx context.Context, interval time.Duration) {
+  o.Called(ctx, interval)
+}
+
+// Publisher records the events published by a service.
+type Publisher struct {
+  mock.Mock
+}
+
+func NewPublisher() *Publisher {
+  return new(Publisher)
+}
+
+func (o *Publisher) Publish(ctx context.Context, event string, data any) error {
+  return o.Called(ctx, event, data).Error(0)
+}
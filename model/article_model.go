@@ -14,6 +14,8 @@ type Article struct {
   Author      string     `json:"author"`
   Views       int64      `json:"views"`
   ReadTime    int        `json:"read_time"`
+  Revision    int        `json:"revision"`
+  Version     int        `json:"version"`
   IsDraft     bool       `json:"is_draft"`
   IsPinned    bool       `json:"is_pinned"`
   PublishedAt *time.Time `json:"published_at"`
@@ -23,14 +25,33 @@ type Article struct {
   Topic       *Topic     `json:"topic"`
   Tags        []*Tag     `json:"tags"`
   Content     string     `json:"content"`
+  PatchUUID   *uuid.UUID `json:"patch_uuid,omitempty"` // set when the article is a preview of a patch
 }
 
 // ArticlePatch is a patch for a published article.
+//
+// A patch is branched from a specific revision of the article, and
+// it keeps a copy of the article as it was at that revision so that
+// it can be merged even if other patches were released before it.
 type ArticlePatch struct {
-  ArticleUUID uuid.UUID `json:"article_uuid"`
-  Title       *string   `json:"title"`
-  Slug        *string   `json:"slug"`
-  ReadTime    *int      `json:"-"`
-  TopicID     *string   `json:"topic_id"`
-  Content     *string   `json:"content"`
+  UUID         uuid.UUID        `json:"uuid"`
+  ArticleUUID  uuid.UUID        `json:"article_uuid"`
+  BaseRevision int              `json:"base_revision"`
+  Version      int              `json:"version"`
+  Base         *ArticleSnapshot `json:"-"`
+  Title        *string          `json:"title"`
+  Slug         *string          `json:"slug"`
+  ReadTime     *int             `json:"-"`
+  TopicID      *string          `json:"topic_id"`
+  Content      *string          `json:"content"`
+  CreatedAt    time.Time        `json:"created_at"`
+}
+
+// ArticleSnapshot is the state of the mergeable fields of an article
+// at a given revision.
+type ArticleSnapshot struct {
+  Title   string
+  Slug    string
+  TopicID string
+  Content string
 }
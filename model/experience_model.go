@@ -16,6 +16,7 @@ type Experience struct {
   Summary   string    `json:"summary"`
   Active    bool      `json:"active"`
   Hidden    bool      `json:"hidden"`
+  Version   int       `json:"version"`
   CreatedAt time.Time `json:"created_at"`
   UpdatedAt time.Time `json:"updated_at"`
 }
@@ -24,6 +24,7 @@ type Me struct {
   YouTubeURL   string    `json:"youtube_url"`
   TwitterURL   string    `json:"twitter_url"`
   InstagramURL string    `json:"instagram_url"`
+  Version      int       `json:"version"`
   CreatedAt    time.Time `json:"created_at"`
   UpdatedAt    time.Time `json:"updated_at"`
 }
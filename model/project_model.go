@@ -24,6 +24,7 @@ type Project struct {
   Playable       bool      `json:"playable"`
   Archived       bool      `json:"archived"`
   Finished       bool      `json:"finished"`
+  Version        int       `json:"version"`
   TechnologyTags []string  `json:"technology_tags"`
   CreatedAt      time.Time `json:"created_at"`
   UpdatedAt      time.Time `json:"updated_at"`
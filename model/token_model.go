@@ -0,0 +1,13 @@
+package model
+
+import (
+  "time"
+)
+
+// Token is an API token that authenticates the administrative
+// endpoints. Only a hash of the token is stored, so the token itself
+// is shown once, when it is created.
+type Token struct {
+  Name      string    `json:"name"`
+  CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,43 @@
+package model
+
+import (
+  "encoding/json"
+  "github.com/google/uuid"
+  "time"
+)
+
+// Webhook is an endpoint outside the site that is notified of the
+// events of the lifecycle of its content.
+type Webhook struct {
+  UUID      uuid.UUID `json:"uuid"`
+  URL       string    `json:"url"`
+  Events    []string  `json:"events"` // names of the events it is subscribed to, or '*' for all of them
+  Secret    string    `json:"-"`      // key of the HMAC signature of the deliveries
+  CreatedAt time.Time `json:"created_at"`
+  UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Possible statuses of a webhook delivery.
+const (
+  DeliveryPending   = "pending"
+  DeliveryDelivered = "delivered"
+  DeliveryFailed    = "failed"
+)
+
+// WebhookDelivery is an event queued to be delivered to a webhook,
+// along with the outcome of its last attempt.
+type WebhookDelivery struct {
+  UUID           uuid.UUID       `json:"uuid"`
+  WebhookUUID    uuid.UUID       `json:"webhook_uuid"`
+  Event          string          `json:"event"`
+  Payload        json.RawMessage `json:"payload"`
+  Status         string          `json:"status"` // one of DeliveryPending, DeliveryDelivered or DeliveryFailed
+  Attempts       int             `json:"attempts"`
+  LastStatusCode *int            `json:"last_status_code"`
+  LastError      *string         `json:"last_error"`
+  NextAttemptAt  *time.Time      `json:"next_attempt_at"` // nil once it is no longer pending
+  CreatedAt      time.Time       `json:"created_at"`
+  DeliveredAt    *time.Time      `json:"delivered_at"`
+  URL            string          `json:"-"` // URL of the webhook at the time of the attempt
+  Secret         string          `json:"-"` // secret of the webhook at the time of the attempt
+}
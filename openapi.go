@@ -0,0 +1,228 @@
+package main
+
+import (
+  "fontseca.dev/model"
+  "fontseca.dev/openapi"
+  "fontseca.dev/transfer"
+  "github.com/gin-gonic/gin"
+  "github.com/google/uuid"
+  "net/http"
+  "strconv"
+  "strings"
+)
+
+// apiInfo is the metadata of the API document.
+var apiInfo = openapi.Info{
+  Title:       "fontseca.dev",
+  Description: "The API that manages the content of the site. Every error response is a problem details object (RFC 7807).",
+  Version:     "1.0.0",
+}
+
+// Query parameters and form fields shared by many endpoints.
+var (
+  pageFields = []openapi.Field{
+    {Name: "limit", Description: "Number of items of the page.", Schema: openapi.Integer(1, 100)},
+    {Name: "cursor", Description: "The 'next_cursor' of the previous page."},
+  }
+
+  articleFilterFields = append([]openapi.Field{
+    {Name: "search", Description: "Words to look for in the title of the articles."},
+    {Name: "topic", Description: "ID of the topic of the articles."},
+    {Name: "from", Description: "Publication date of the articles, in the form: 'YYYY/MM'."},
+    {Name: "page", Description: "Page number; superseded by 'cursor'.", Schema: openapi.Integer(1, 1<<31-1)},
+    {Name: "rpp", Description: "Records per page; superseded by 'limit'.", Schema: openapi.Integer(1, 1<<31-1)},
+  }, pageFields...)
+
+  idField           = openapi.Field{Name: "id", Required: true, Schema: openapi.String("uuid")}
+  urlField          = openapi.Field{Name: "url", Required: true, Schema: openapi.String("uri")}
+  versionField      = openapi.Field{Name: "version", Description: "The current version of the record.", Required: true, Schema: openapi.Integer(1, 1<<31-1)}
+  articleUUIDField  = openapi.Field{Name: "article_uuid", Required: true, Schema: openapi.String("uuid")}
+  draftUUIDField    = openapi.Field{Name: "draft_uuid", Required: true, Schema: openapi.String("uuid")}
+  patchUUIDField    = openapi.Field{Name: "patch_uuid", Required: true, Schema: openapi.String("uuid")}
+  tagIDField        = openapi.Field{Name: "tag_id", Required: true}
+  topicIDField      = openapi.Field{Name: "topic_id", Required: true}
+  technologyIDField = openapi.Field{Name: "technology_id", Required: true, Schema: openapi.String("uuid")}
+  seeOther          = []int{http.StatusSeeOther}
+  conflict          = []int{http.StatusConflict}
+)
+
+// Bodies of the responses that are not transfer types.
+type (
+  insertedID struct {
+    InsertedID string `json:"inserted_id"`
+  }
+
+  draftCreated struct {
+    DraftUUID uuid.UUID `json:"draft_uuid"`
+  }
+
+  patchCreated struct {
+    PatchUUID uuid.UUID `json:"patch_uuid"`
+  }
+
+  shareableLink struct {
+    ShareableLink string `json:"shareable_link"`
+  }
+
+  webhookCreated struct {
+    InsertedID string `json:"inserted_id"`
+    Secret     string `json:"secret"`
+  }
+)
+
+// endpoints describes every route of the API, keyed by method and path
+// as in 'GET /me.info'. A route registered without an entry here is left
+// out of the API document, which the tests do not allow.
+var endpoints = map[string]*openapi.Endpoint{
+  "GET /openapi.json": {Summary: "Get the OpenAPI document of the API.", Tag: "meta", MediaType: "application/json"},
+
+  "GET /me.info":            {Summary: "Get the information about me.", Tag: "me", Response: model.Me{}},
+  "POST /me.setPhoto":       {Summary: "Set the URL of my photo.", Tag: "me", Fields: []openapi.Field{{Name: "photo_url", Schema: openapi.String("uri")}, versionField}, Status: http.StatusNoContent, Others: seeOther},
+  "POST /me.setResume":      {Summary: "Set the URL of my resume.", Tag: "me", Fields: []openapi.Field{{Name: "resume_url", Schema: openapi.String("uri")}, versionField}, Status: http.StatusNoContent, Others: seeOther},
+  "POST /me.setHireable":    {Summary: "Set whether I am open to work.", Tag: "me", Fields: []openapi.Field{{Name: "hireable", Schema: openapi.Boolean()}, versionField}, Status: http.StatusNoContent, Others: seeOther},
+  "POST /me.set":            {Summary: "Update the information about me.", Tag: "me", JSON: transfer.MeUpdate{}, Status: http.StatusNoContent, Others: seeOther},
+  "POST /me.authenticate":   {Summary: "Authenticate as me. Not implemented yet.", Tag: "me", Status: http.StatusNoContent},
+  "POST /me.deauthenticate": {Summary: "End the session. Not implemented yet.", Tag: "me", Status: http.StatusNoContent},
+
+  "GET /me.experience.list":        {Summary: "List the visible experience.", Tag: "experience", Fields: pageFields, Response: transfer.Page[*model.Experience]{}},
+  "GET /me.experience.hidden.list": {Summary: "List the hidden experience.", Tag: "experience", Fields: pageFields, Response: transfer.Page[*model.Experience]{}},
+  "GET /me.experience.info":        {Summary: "Get an experience entry.", Tag: "experience", Fields: []openapi.Field{idField}, Response: model.Experience{}},
+  "POST /me.experience.add":        {Summary: "Add an experience entry.", Tag: "experience", Form: transfer.ExperienceCreation{}, Status: http.StatusCreated},
+  "POST /me.experience.set":        {Summary: "Update an experience entry.", Tag: "experience", Fields: []openapi.Field{idField}, Form: transfer.ExperienceUpdate{}, Status: http.StatusNoContent, Others: seeOther},
+  "POST /me.experience.hide":       {Summary: "Hide an experience entry.", Tag: "experience", Fields: []openapi.Field{idField, versionField}, Status: http.StatusNoContent, Others: seeOther},
+  "POST /me.experience.show":       {Summary: "Show a hidden experience entry.", Tag: "experience", Fields: []openapi.Field{idField, versionField}, Status: http.StatusNoContent, Others: seeOther},
+  "POST /me.experience.quit":       {Summary: "End an experience entry this year.", Tag: "experience", Fields: []openapi.Field{idField, versionField}, Status: http.StatusNoContent, Others: seeOther},
+  "POST /me.experience.remove":     {Summary: "Remove an experience entry.", Tag: "experience", Fields: []openapi.Field{idField}, Status: http.StatusNoContent},
+
+  "GET /technologies.list":    {Summary: "List the technology tags.", Tag: "technologies", Fields: pageFields, Response: transfer.Page[*model.TechnologyTag]{}},
+  "GET /technologies.info":    {Summary: "Get a technology tag.", Tag: "technologies", Fields: []openapi.Field{idField}, Response: model.TechnologyTag{}},
+  "POST /technologies.add":    {Summary: "Add a technology tag.", Tag: "technologies", Form: transfer.TechnologyTagCreation{}, Response: insertedID{}},
+  "POST /technologies.set":    {Summary: "Rename a technology tag.", Tag: "technologies", Fields: []openapi.Field{idField}, Form: transfer.TechnologyTagUpdate{}, Status: http.StatusNoContent, Others: conflict},
+  "POST /technologies.remove": {Summary: "Remove a technology tag.", Tag: "technologies", Fields: []openapi.Field{idField}, Status: http.StatusNoContent},
+
+  "GET /me.projects.list":                 {Summary: "List the active projects.", Tag: "projects", Fields: pageFields, Response: transfer.Page[*model.Project]{}},
+  "GET /me.projects.info":                 {Summary: "Get a project.", Tag: "projects", Fields: []openapi.Field{idField}, Response: model.Project{}},
+  "GET /me.projects.archived.list":        {Summary: "List the archived projects.", Tag: "projects", Fields: pageFields, Response: transfer.Page[*model.Project]{}},
+  "POST /me.projects.add":                 {Summary: "Add a project.", Tag: "projects", Form: transfer.ProjectCreation{}, Response: insertedID{}},
+  "POST /me.projects.set":                 {Summary: "Update a project.", Tag: "projects", Fields: []openapi.Field{idField}, Form: transfer.ProjectUpdate{}, Status: http.StatusNoContent, Others: seeOther},
+  "POST /me.projects.archive":             {Summary: "Archive a project.", Tag: "projects", Fields: []openapi.Field{idField, versionField}, Status: http.StatusNoContent},
+  "POST /me.projects.unarchive":           {Summary: "Unarchive a project.", Tag: "projects", Fields: []openapi.Field{idField, versionField}, Status: http.StatusNoContent, Others: seeOther},
+  "POST /me.projects.finish":              {Summary: "Mark a project as finished.", Tag: "projects", Fields: []openapi.Field{idField, versionField}, Status: http.StatusNoContent, Others: seeOther},
+  "POST /me.projects.unfinish":            {Summary: "Mark a project as unfinished.", Tag: "projects", Fields: []openapi.Field{idField, versionField}, Status: http.StatusNoContent, Others: seeOther},
+  "POST /me.projects.remove":              {Summary: "Remove a project.", Tag: "projects", Fields: []openapi.Field{idField}, Status: http.StatusNoContent},
+  "POST /me.projects.setPlaygroundURL":    {Summary: "Set the URL of the playground of a project.", Tag: "projects", Fields: []openapi.Field{idField, urlField, versionField}, Status: http.StatusNoContent, Others: conflict},
+  "POST /me.projects.setFirstImageURL":    {Summary: "Set the URL of the first image of a project.", Tag: "projects", Fields: []openapi.Field{idField, urlField, versionField}, Status: http.StatusNoContent, Others: conflict},
+  "POST /me.projects.setSecondImageURL":   {Summary: "Set the URL of the second image of a project.", Tag: "projects", Fields: []openapi.Field{idField, urlField, versionField}, Status: http.StatusNoContent, Others: conflict},
+  "POST /me.projects.setGitHubURL":        {Summary: "Set the URL of the repository of a project.", Tag: "projects", Fields: []openapi.Field{idField, urlField, versionField}, Status: http.StatusNoContent, Others: conflict},
+  "POST /me.projects.setCollectionURL":    {Summary: "Set the URL of the collection of a project.", Tag: "projects", Fields: []openapi.Field{idField, urlField, versionField}, Status: http.StatusNoContent, Others: conflict},
+  "POST /me.projects.technologies.add":    {Summary: "Add a technology tag to a project.", Tag: "projects", Fields: []openapi.Field{idField, technologyIDField}, Status: http.StatusNoContent, Others: conflict},
+  "POST /me.projects.technologies.remove": {Summary: "Remove a technology tag from a project.", Tag: "projects", Fields: []openapi.Field{idField, technologyIDField}, Status: http.StatusNoContent, Others: conflict},
+
+  "POST /archive.tags.add":    {Summary: "Add a tag.", Tag: "tags", Form: transfer.TagCreation{}, Status: http.StatusCreated},
+  "GET /archive.tags.list":    {Summary: "List the tags.", Tag: "tags", Fields: pageFields, Response: transfer.Page[*model.Tag]{}},
+  "GET /archive.tags.info":    {Summary: "Get a tag.", Tag: "tags", Fields: []openapi.Field{tagIDField}, Response: model.Tag{}},
+  "POST /archive.tags.set":    {Summary: "Rename a tag.", Tag: "tags", Fields: []openapi.Field{tagIDField}, Form: transfer.TagUpdate{}, Status: http.StatusNoContent},
+  "POST /archive.tags.remove": {Summary: "Remove a tag.", Tag: "tags", Fields: []openapi.Field{tagIDField}, Status: http.StatusNoContent},
+
+  "POST /archive.topics.add":    {Summary: "Add a topic.", Tag: "topics", Form: transfer.TopicCreation{}, Status: http.StatusCreated},
+  "GET /archive.topics.list":    {Summary: "List the topics.", Tag: "topics", Fields: pageFields, Response: transfer.Page[*model.Topic]{}},
+  "GET /archive.topics.info":    {Summary: "Get a topic.", Tag: "topics", Fields: []openapi.Field{topicIDField}, Response: model.Topic{}},
+  "POST /archive.topics.set":    {Summary: "Rename a topic.", Tag: "topics", Fields: []openapi.Field{topicIDField}, Form: transfer.TopicUpdate{}, Status: http.StatusNoContent},
+  "POST /archive.topics.remove": {Summary: "Remove a topic.", Tag: "topics", Fields: []openapi.Field{topicIDField}, Status: http.StatusNoContent},
+
+  "POST /archive.drafts.start":       {Summary: "Start a draft.", Tag: "drafts", Form: transfer.ArticleCreation{}, Status: http.StatusCreated, Response: draftCreated{}},
+  "POST /archive.drafts.publish":     {Summary: "Publish a draft, adding the tags given to it first.", Tag: "drafts", Fields: []openapi.Field{draftUUIDField, {Name: "tag_id", Description: "ID of a tag to add; may be repeated"}}, Status: http.StatusNoContent},
+  "GET /archive.drafts.list":         {Summary: "List the drafts.", Tag: "drafts", Fields: articleFilterFields, Response: transfer.Page[*transfer.Article]{}},
+  "GET /archive.drafts.info":         {Summary: "Get a draft.", Tag: "drafts", Fields: []openapi.Field{draftUUIDField}, Response: model.Article{}},
+  "POST /archive.drafts.share":       {Summary: "Create a temporary link to a draft.", Tag: "drafts", Fields: []openapi.Field{draftUUIDField}, Response: shareableLink{}},
+  "POST /archive.drafts.revise":      {Summary: "Revise a draft.", Tag: "drafts", Fields: []openapi.Field{draftUUIDField}, Form: transfer.ArticleRevision{}, Status: http.StatusNoContent},
+  "POST /archive.drafts.discard":     {Summary: "Discard a draft.", Tag: "drafts", Fields: []openapi.Field{draftUUIDField}, Status: http.StatusNoContent},
+  "POST /archive.drafts.tags.add":    {Summary: "Add a tag to a draft.", Tag: "drafts", Fields: []openapi.Field{draftUUIDField, tagIDField}, Status: http.StatusNoContent},
+  "POST /archive.drafts.tags.remove": {Summary: "Remove a tag from a draft.", Tag: "drafts", Fields: []openapi.Field{draftUUIDField, tagIDField}, Status: http.StatusNoContent},
+
+  "GET /archive.articles.list":         {Summary: "List the published articles.", Tag: "articles", Fields: articleFilterFields, Response: transfer.Page[*transfer.Article]{}},
+  "GET /archive.articles.hidden.list":  {Summary: "List the hidden articles.", Tag: "articles", Fields: articleFilterFields, Response: transfer.Page[*transfer.Article]{}},
+  "GET /archive.articles.info":         {Summary: "Get an article.", Tag: "articles", Fields: []openapi.Field{articleUUIDField}, Response: model.Article{}},
+  "POST /archive.articles.amend":       {Summary: "Start a patch of an article.", Tag: "articles", Fields: []openapi.Field{articleUUIDField}, Status: http.StatusCreated, Response: patchCreated{}},
+  "POST /archive.articles.setSlug":     {Summary: "Change the slug of an article.", Tag: "articles", Fields: []openapi.Field{articleUUIDField, {Name: "slug", Required: true}}, Status: http.StatusNoContent},
+  "POST /archive.articles.hide":        {Summary: "Hide an article.", Tag: "articles", Fields: []openapi.Field{articleUUIDField}, Status: http.StatusNoContent},
+  "POST /archive.articles.show":        {Summary: "Show a hidden article.", Tag: "articles", Fields: []openapi.Field{articleUUIDField}, Status: http.StatusNoContent},
+  "POST /archive.articles.remove":      {Summary: "Remove an article.", Tag: "articles", Fields: []openapi.Field{articleUUIDField}, Status: http.StatusNoContent},
+  "POST /archive.articles.pin":         {Summary: "Pin an article.", Tag: "articles", Fields: []openapi.Field{articleUUIDField}, Status: http.StatusNoContent},
+  "POST /archive.articles.unpin":       {Summary: "Unpin an article.", Tag: "articles", Fields: []openapi.Field{articleUUIDField}, Status: http.StatusNoContent},
+  "POST /archive.articles.tags.add":    {Summary: "Add a tag to an article.", Tag: "articles", Fields: []openapi.Field{articleUUIDField, tagIDField}, Status: http.StatusNoContent},
+  "POST /archive.articles.tags.remove": {Summary: "Remove a tag from an article.", Tag: "articles", Fields: []openapi.Field{articleUUIDField, tagIDField}, Status: http.StatusNoContent},
+  "POST /archive.articles.views.flush": {Summary: "Write the article views counted since midnight to the database.", Tag: "articles", Status: http.StatusNoContent, Secured: true},
+
+  "GET /archive.articles.patches.list":     {Summary: "List the patches of the articles.", Tag: "patches", Fields: pageFields, Response: transfer.Page[*model.ArticlePatch]{}},
+  "GET /archive.articles.patches.diff":     {Summary: "Get the changes a patch would make to its article.", Tag: "patches", Fields: []openapi.Field{patchUUIDField}, Response: transfer.ArticlePatchDiff{}},
+  "POST /archive.articles.patches.revise":  {Summary: "Revise a patch.", Tag: "patches", Fields: []openapi.Field{patchUUIDField}, Form: transfer.ArticleRevision{}, Status: http.StatusNoContent},
+  "POST /archive.articles.patches.share":   {Summary: "Create a temporary link to a patch.", Tag: "patches", Fields: []openapi.Field{patchUUIDField}, Response: shareableLink{}},
+  "POST /archive.articles.patches.discard": {Summary: "Discard a patch.", Tag: "patches", Fields: []openapi.Field{patchUUIDField}, Status: http.StatusNoContent},
+  "POST /archive.articles.patches.release": {Summary: "Merge a patch into its article.", Tag: "patches", Fields: []openapi.Field{patchUUIDField}, Status: http.StatusNoContent},
+
+  "POST /archive.import": {Summary: "Import articles from Markdown files with a YAML front matter.", Tag: "articles", Fields: []openapi.Field{{Name: "files", Required: true, Schema: openapi.Files()}}, Multipart: true, Response: []*transfer.ArticleImportResult{}, Secured: true},
+
+  "GET /site.export":  {Summary: "Export the content of the site as a gzipped tar archive.", Tag: "site", MediaType: "application/gzip", Secured: true},
+  "POST /site.backup": {Summary: "Back up the database into the directory of the backups, checking its integrity.", Tag: "site", Status: http.StatusCreated, Response: transfer.Backup{}, Secured: true},
+
+  "POST /webhooks.add":            {Summary: "Register a webhook; its secret is returned only once.", Tag: "webhooks", Form: transfer.WebhookCreation{}, Response: webhookCreated{}, Secured: true},
+  "GET /webhooks.list":            {Summary: "List the registered webhooks.", Tag: "webhooks", Fields: pageFields, Response: transfer.Page[*model.Webhook]{}, Secured: true},
+  "POST /webhooks.remove":         {Summary: "Remove a webhook and its deliveries.", Tag: "webhooks", Fields: []openapi.Field{idField}, Status: http.StatusNoContent, Secured: true},
+  "GET /webhooks.deliveries.list": {Summary: "List the deliveries of a webhook, the most recent first.", Tag: "webhooks", Fields: append([]openapi.Field{{Name: "webhook_id", Required: true, Schema: openapi.String("uuid")}}, pageFields...), Response: transfer.Page[*model.WebhookDelivery]{}, Secured: true},
+  "GET /webhooks.events.list":     {Summary: "List the events webhooks can subscribe to.", Tag: "webhooks", Response: []string{}, Secured: true},
+
+  "GET /trash.list":     {Summary: "List the removed content that has not been purged yet, the most recently removed first.", Tag: "trash", Fields: pageFields, Response: transfer.Page[*transfer.TrashedItem]{}, Secured: true},
+  "POST /trash.restore": {Summary: "Restore removed content.", Tag: "trash", Fields: []openapi.Field{{Name: "type", Required: true, Schema: &openapi.Schema{Type: "string", Enum: []string{"article", "draft", "patch", "project", "experience", "tag", "topic"}}}, {Name: "id", Required: true}}, Status: http.StatusNoContent, Secured: true},
+}
+
+// isAPIRoute reports whether path is a route of the API rather than a
+// page of the site, a feed or a static file.
+func isAPIRoute(path string) bool {
+  if _, isFile := staticFiles[path]; isFile {
+    return false
+  }
+
+  return strings.Contains(path, ".") &&
+    !strings.HasPrefix(path, "/public/") &&
+    !strings.HasSuffix(path, "/feed.xml")
+}
+
+// newAPIDocument generates the OpenAPI document of the API routes
+// among routes.
+func newAPIDocument(routes gin.RoutesInfo) *openapi.Document {
+  var api = make([]openapi.Route, 0, len(routes))
+
+  for _, route := range routes {
+    if isAPIRoute(route.Path) {
+      api = append(api, openapi.Route{Method: route.Method, Path: route.Path})
+    }
+  }
+
+  var document = openapi.Generate(apiInfo, api, endpoints)
+
+  // JSON responses of GET requests are conditional; see handler.Conditional.
+  for _, item := range document.Paths {
+    var get = item["get"]
+    if nil == get || nil == get.Responses["200"] || nil == get.Responses["200"].Content["application/json"] {
+      continue
+    }
+
+    get.Responses[strconv.Itoa(http.StatusNotModified)] = &openapi.Response{Description: http.StatusText(http.StatusNotModified)}
+  }
+
+  return document
+}
+
+// routeAPIDocument serves the OpenAPI document of the API at
+// /openapi.json. It must be called after every other route of the API
+// is registered in engine.
+func routeAPIDocument(engine *gin.Engine) {
+  var document *openapi.Document
+
+  engine.GET("/openapi.json", func(c *gin.Context) {
+    c.JSON(http.StatusOK, document)
+  })
+
+  document = newAPIDocument(engine.Routes())
+}
@@ -0,0 +1,304 @@
+package openapi
+
+import (
+  "net/http"
+  "slices"
+  "strconv"
+  "strings"
+)
+
+// Version is the version of the OpenAPI specification the generated
+// documents conform to.
+const Version = "3.1.0"
+
+// Document is an OpenAPI document.
+type Document struct {
+  OpenAPI    string              `json:"openapi"`
+  Info       Info                `json:"info"`
+  Paths      map[string]PathItem `json:"paths"`
+  Components Components          `json:"components"`
+}
+
+// Info is the metadata of the API described by a document.
+type Info struct {
+  Title       string `json:"title"`
+  Description string `json:"description,omitempty"`
+  Version     string `json:"version"`
+}
+
+// PathItem maps the lowercase HTTP methods of a path to its operations.
+type PathItem map[string]*Operation
+
+// Operation is a single API operation on a path.
+type Operation struct {
+  OperationID string                `json:"operationId"`
+  Summary     string                `json:"summary,omitempty"`
+  Tags        []string              `json:"tags,omitempty"`
+  Parameters  []*Parameter          `json:"parameters,omitempty"`
+  RequestBody *RequestBody          `json:"requestBody,omitempty"`
+  Responses   map[string]*Response  `json:"responses"`
+  Security    []map[string][]string `json:"security,omitempty"`
+}
+
+// Parameter is a query parameter of an operation.
+type Parameter struct {
+  Name        string  `json:"name"`
+  In          string  `json:"in"`
+  Description string  `json:"description,omitempty"`
+  Required    bool    `json:"required,omitempty"`
+  Schema      *Schema `json:"schema"`
+}
+
+// RequestBody is the body of the request of an operation.
+type RequestBody struct {
+  Required bool                  `json:"required,omitempty"`
+  Content  map[string]*MediaType `json:"content"`
+}
+
+// Response is a possible response of an operation.
+type Response struct {
+  Description string                `json:"description"`
+  Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+// MediaType is the schema of a request or response body.
+type MediaType struct {
+  Schema *Schema `json:"schema"`
+}
+
+// Components holds the schemas referenced from the operations.
+type Components struct {
+  Schemas         map[string]*Schema         `json:"schemas"`
+  SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// SecurityScheme is a way of authenticating requests.
+type SecurityScheme struct {
+  Type   string `json:"type"`
+  Scheme string `json:"scheme"`
+}
+
+// Route is a registered route of the API.
+type Route struct {
+  Method string
+  Path   string
+}
+
+// Key returns the key of the route in the map of endpoints given to
+// Generate, in the form: 'GET /me.info'.
+func (r Route) Key() string {
+  return r.Method + " " + r.Path
+}
+
+// Field is a query parameter or a form field of an endpoint that is
+// not part of a struct.
+type Field struct {
+  Name        string
+  Description string
+  Required    bool
+  Schema      *Schema // a string by default
+}
+
+// Endpoint describes what a route expects and returns, which cannot
+// be told from the route alone.
+type Endpoint struct {
+  Summary   string
+  Tag       string
+  Fields    []Field // query parameters of GET routes and form fields of any other route
+  Form      any     // value of the struct bound from the form, if any
+  JSON      any     // value of the struct of the JSON request body, if any
+  Multipart bool    // whether the form is sent as multipart/form-data
+  Status    int     // status of a successful response; 200 by default
+  Response  any     // value of the type of the successful response body, if any
+  MediaType string  // media type of the successful response; application/json by default
+  Others    []int   // other statuses of a response that is not a problem
+  Secured   bool    // whether the endpoint requires a bearer token
+}
+
+// bearer is the name of the security scheme of secured endpoints.
+const bearer = "bearer"
+
+// Generate creates the document of the routes described in endpoints,
+// which are keyed by Route.Key. Routes without an endpoint are left
+// out, and so are endpoints without a registered route. Every error
+// response is described as an RFC 7807 problem.
+func Generate(info Info, routes []Route, endpoints map[string]*Endpoint) *Document {
+  var g = newGenerator()
+
+  var document = &Document{
+    OpenAPI: Version,
+    Info:    info,
+    Paths:   make(map[string]PathItem),
+  }
+
+  var secured bool
+
+  for _, route := range routes {
+    endpoint, documented := endpoints[route.Key()]
+    if !documented {
+      continue
+    }
+
+    if nil == document.Paths[route.Path] {
+      document.Paths[route.Path] = make(PathItem)
+    }
+
+    document.Paths[route.Path][strings.ToLower(route.Method)] = g.operation(route, endpoint)
+    secured = secured || endpoint.Secured
+  }
+
+  document.Components.Schemas = g.components
+
+  if secured {
+    document.Components.SecuritySchemes = map[string]*SecurityScheme{
+      bearer: {Type: "http", Scheme: "bearer"},
+    }
+  }
+
+  return document
+}
+
+// operation creates the operation of route as described by endpoint.
+func (g *generator) operation(route Route, endpoint *Endpoint) *Operation {
+  var operation = &Operation{
+    OperationID: strings.TrimPrefix(route.Path, "/"),
+    Summary:     endpoint.Summary,
+    Responses:   make(map[string]*Response),
+  }
+
+  if "" != endpoint.Tag {
+    operation.Tags = []string{endpoint.Tag}
+  }
+
+  if http.MethodGet == route.Method {
+    for _, field := range endpoint.Fields {
+      operation.Parameters = append(operation.Parameters, &Parameter{
+        Name:        field.Name,
+        In:          "query",
+        Description: field.Description,
+        Required:    field.Required,
+        Schema:      field.schema(),
+      })
+    }
+  } else {
+    operation.RequestBody = g.requestBody(endpoint)
+  }
+
+  var status = endpoint.Status
+  if 0 == status {
+    status = http.StatusOK
+  }
+
+  var success = &Response{Description: http.StatusText(status)}
+
+  if nil != endpoint.Response || "" != endpoint.MediaType {
+    var mediaType = endpoint.MediaType
+    if "" == mediaType {
+      mediaType = "application/json"
+    }
+
+    var schema = &Schema{}
+    if nil != endpoint.Response {
+      schema = g.schema(typeOf(endpoint.Response))
+    }
+
+    success.Content = map[string]*MediaType{mediaType: {Schema: schema}}
+  }
+
+  operation.Responses[strconv.Itoa(status)] = success
+
+  for _, other := range endpoint.Others {
+    operation.Responses[strconv.Itoa(other)] = &Response{Description: http.StatusText(other)}
+  }
+
+  operation.Responses["default"] = &Response{
+    Description: "A problem that occurred while processing the request.",
+    Content:     map[string]*MediaType{"application/problem+json": {Schema: g.problem()}},
+  }
+
+  if endpoint.Secured {
+    operation.Security = []map[string][]string{{bearer: {}}}
+  }
+
+  return operation
+}
+
+// requestBody creates the body of the request of endpoint, or returns
+// nil if it does not expect one.
+func (g *generator) requestBody(endpoint *Endpoint) *RequestBody {
+  if 0 == len(endpoint.Fields) && nil == endpoint.Form && nil == endpoint.JSON {
+    return nil
+  }
+
+  var body = &Schema{Type: "object", Properties: make(map[string]*Schema)}
+
+  for _, field := range endpoint.Fields {
+    body.Properties[field.Name] = field.schema()
+    if field.Required {
+      body.Required = append(body.Required, field.Name)
+    }
+  }
+
+  if nil != endpoint.Form {
+    g.properties(body, typeOf(endpoint.Form), true)
+  }
+
+  if nil != endpoint.JSON {
+    g.properties(body, typeOf(endpoint.JSON), true)
+  }
+
+  slices.Sort(body.Required)
+
+  var mediaType string
+
+  switch {
+  case nil != endpoint.JSON:
+    mediaType = "application/json"
+  case endpoint.Multipart:
+    mediaType = "multipart/form-data"
+  default:
+    mediaType = "application/x-www-form-urlencoded"
+  }
+
+  return &RequestBody{
+    Required: 0 < len(body.Required) || nil != endpoint.JSON,
+    Content:  map[string]*MediaType{mediaType: {Schema: body}},
+  }
+}
+
+// schema returns the schema of the field, which is a string by default.
+func (f Field) schema() *Schema {
+  var schema = &Schema{Type: "string"}
+  if nil != f.Schema {
+    copied := *f.Schema
+    schema = &copied
+  }
+
+  if "" != f.Description && "" == schema.Ref {
+    schema.Description = f.Description
+  }
+
+  return schema
+}
+
+// problem returns a reference to the schema of an RFC 7807 problem.
+func (g *generator) problem() *Schema {
+  const name = "Problem"
+
+  if _, defined := g.components[name]; !defined {
+    g.components[name] = &Schema{
+      Type:        "object",
+      Description: "A problem details object as defined by RFC 7807. Problems may have extension members.",
+      Properties: map[string]*Schema{
+        "type":     {Type: "string", Format: "uri-reference"},
+        "title":    {Type: "string"},
+        "status":   {Type: "integer"},
+        "detail":   {Type: "string"},
+        "instance": {Type: "string", Format: "uri-reference"},
+      },
+      AdditionalProperties: true,
+    }
+  }
+
+  return &Schema{Ref: "#/components/schemas/" + name}
+}
@@ -0,0 +1,123 @@
+package openapi
+
+import (
+  "encoding/json"
+  "github.com/google/uuid"
+  "github.com/stretchr/testify/assert"
+  "github.com/stretchr/testify/require"
+  "net/http"
+  "testing"
+  "time"
+)
+
+type creation struct {
+  Name     string `json:"name" binding:"required,max=64"`
+  Year     int    `json:"year" binding:"required,number,gt=2017"`
+  Homepage string `json:"homepage" binding:"url"`
+  Kind     string `json:"kind" binding:"oneof=a b"`
+  Slug     string
+  Hidden   bool `json:"-"`
+}
+
+type entry struct {
+  UUID      uuid.UUID  `json:"uuid"`
+  Name      string     `json:"name"`
+  Ends      *int       `json:"ends"`
+  Parent    *entry     `json:"parent,omitempty"`
+  Tags      []string   `json:"tags"`
+  CreatedAt time.Time  `json:"created_at"`
+  DeletedAt *time.Time `json:"deleted_at"`
+}
+
+type page[T any] struct {
+  Items []T `json:"items"`
+}
+
+func TestGenerate(t *testing.T) {
+  var routes = []Route{
+    {http.MethodGet, "/entries.list"},
+    {http.MethodPost, "/entries.add"},
+    {http.MethodPost, "/entries.undocumented"},
+  }
+
+  var endpoints = map[string]*Endpoint{
+    "GET /entries.list": {
+      Tag:      "entries",
+      Fields:   []Field{{Name: "limit", Schema: Integer(1, 100)}},
+      Response: page[*entry]{},
+    },
+    "POST /entries.add": {
+      Fields:  []Field{{Name: "id", Required: true, Schema: String("uuid")}},
+      Form:    creation{},
+      Status:  http.StatusCreated,
+      Others:  []int{http.StatusConflict},
+      Secured: true,
+    },
+    "POST /entries.unregistered": {},
+  }
+
+  var document = Generate(Info{Title: "test", Version: "1"}, routes, endpoints)
+
+  _, err := json.Marshal(document)
+  require.NoError(t, err)
+
+  assert.Equal(t, Version, document.OpenAPI)
+  assert.Len(t, document.Paths, 2)
+  assert.NotContains(t, document.Paths, "/entries.undocumented")
+  assert.NotContains(t, document.Paths, "/entries.unregistered")
+
+  t.Run("query parameters", func(t *testing.T) {
+    list := document.Paths["/entries.list"]["get"]
+    require.NotNil(t, list)
+    require.Len(t, list.Parameters, 1)
+    assert.Equal(t, "query", list.Parameters[0].In)
+    assert.Equal(t, 100.0, *list.Parameters[0].Schema.Maximum)
+    assert.Nil(t, list.RequestBody)
+    assert.Equal(t, []string{"entries"}, list.Tags)
+  })
+
+  t.Run("response schemas", func(t *testing.T) {
+    list := document.Paths["/entries.list"]["get"]
+    assert.Equal(t, "#/components/schemas/page_entry", list.Responses["200"].Content["application/json"].Schema.Ref)
+
+    schemas := document.Components.Schemas
+    require.Contains(t, schemas, "entry")
+
+    e := schemas["entry"]
+    assert.Equal(t, []string{"created_at", "deleted_at", "ends", "name", "tags", "uuid"}, e.Required)
+    assert.Equal(t, "uuid", e.Properties["uuid"].Format)
+    assert.Equal(t, []string{"integer", "null"}, e.Properties["ends"].Type)
+    assert.Equal(t, "date-time", e.Properties["created_at"].Format)
+    assert.Equal(t, []string{"string", "null"}, e.Properties["deleted_at"].Type)
+    assert.Equal(t, "#/components/schemas/entry", e.Properties["parent"].OneOf[0].Ref)
+    assert.Equal(t, "array", e.Properties["tags"].Type)
+  })
+
+  t.Run("form bodies follow binding tags", func(t *testing.T) {
+    add := document.Paths["/entries.add"]["post"]
+    require.NotNil(t, add.RequestBody)
+
+    form := add.RequestBody.Content["application/x-www-form-urlencoded"].Schema
+    require.NotNil(t, form)
+
+    assert.True(t, add.RequestBody.Required)
+    assert.Equal(t, []string{"id", "name", "year"}, form.Required)
+    assert.NotContains(t, form.Properties, "Slug")
+    assert.NotContains(t, form.Properties, "Hidden")
+    assert.Equal(t, 64, *form.Properties["name"].MaxLength)
+    assert.Equal(t, 2017.0, *form.Properties["year"].ExclusiveMinimum)
+    assert.Equal(t, "uri", form.Properties["homepage"].Format)
+    assert.Equal(t, []string{"a", "b"}, form.Properties["kind"].Enum)
+  })
+
+  t.Run("statuses, problems and security", func(t *testing.T) {
+    add := document.Paths["/entries.add"]["post"]
+
+    assert.Contains(t, add.Responses, "201")
+    assert.Contains(t, add.Responses, "409")
+    assert.Equal(t, "#/components/schemas/Problem", add.Responses["default"].Content["application/problem+json"].Schema.Ref)
+    assert.Contains(t, document.Components.Schemas, "Problem")
+    assert.Equal(t, []map[string][]string{{"bearer": {}}}, add.Security)
+    assert.Contains(t, document.Components.SecuritySchemes, "bearer")
+  })
+}
@@ -0,0 +1,318 @@
+package openapi
+
+import (
+  "encoding"
+  "encoding/json"
+  "github.com/google/uuid"
+  "path"
+  "reflect"
+  "regexp"
+  "slices"
+  "strconv"
+  "strings"
+  "time"
+)
+
+// Schema is a JSON Schema as used by OpenAPI 3.1.
+type Schema struct {
+  Ref                  string             `json:"$ref,omitempty"`
+  Type                 any                `json:"type,omitempty"` // a string, or a list of them for nullable values
+  Format               string             `json:"format,omitempty"`
+  Description          string             `json:"description,omitempty"`
+  Enum                 []string           `json:"enum,omitempty"`
+  Items                *Schema            `json:"items,omitempty"`
+  Properties           map[string]*Schema `json:"properties,omitempty"`
+  Required             []string           `json:"required,omitempty"`
+  AdditionalProperties any                `json:"additionalProperties,omitempty"` // a boolean or a *Schema
+  OneOf                []*Schema          `json:"oneOf,omitempty"`
+  MinLength            *int               `json:"minLength,omitempty"`
+  MaxLength            *int               `json:"maxLength,omitempty"`
+  Minimum              *float64           `json:"minimum,omitempty"`
+  Maximum              *float64           `json:"maximum,omitempty"`
+  ExclusiveMinimum     *float64           `json:"exclusiveMinimum,omitempty"`
+  ExclusiveMaximum     *float64           `json:"exclusiveMaximum,omitempty"`
+}
+
+var (
+  timeType          = reflect.TypeOf(time.Time{})
+  uuidType          = reflect.TypeOf(uuid.UUID{})
+  textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+  marshalerType     = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+
+  // invalidComponentName matches the characters that component names
+  // cannot have.
+  invalidComponentName = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+)
+
+// typeOf returns the type of value, or the type it points to.
+func typeOf(value any) reflect.Type {
+  var t = reflect.TypeOf(value)
+  for reflect.Pointer == t.Kind() {
+    t = t.Elem()
+  }
+  return t
+}
+
+// generator creates the schemas of Go types the way encoding/json
+// encodes them, keeping every named struct as a component.
+type generator struct {
+  components map[string]*Schema
+  names      map[reflect.Type]string
+}
+
+func newGenerator() *generator {
+  return &generator{
+    components: make(map[string]*Schema),
+    names:      make(map[reflect.Type]string),
+  }
+}
+
+// componentName returns the name of the component of the struct t.
+// Type arguments are appended to the name, as in 'Page_Experience',
+// and the name of the package is prepended when two packages have a
+// struct with the same name.
+func (g *generator) componentName(t reflect.Type) string {
+  var name = t.Name()
+
+  if i := strings.IndexByte(name, '['); -1 != i {
+    var arguments = strings.Split(strings.TrimSuffix(name[i+1:], "]"), ",")
+
+    name = name[:i]
+    for _, argument := range arguments {
+      argument = strings.TrimLeft(argument, "*[]")
+      name += "_" + argument[strings.LastIndexByte(argument, '.')+1:]
+    }
+  }
+
+  name = invalidComponentName.ReplaceAllString(name, "_")
+
+  for other := range g.names {
+    if g.names[other] == name && other != t {
+      return path.Base(t.PkgPath()) + "." + name
+    }
+  }
+
+  return name
+}
+
+// schema returns the schema of the values of t.
+func (g *generator) schema(t reflect.Type) *Schema {
+  if reflect.Pointer == t.Kind() {
+    return nullable(g.schema(t.Elem()))
+  }
+
+  switch {
+  case timeType == t:
+    return &Schema{Type: "string", Format: "date-time"}
+  case uuidType == t:
+    return &Schema{Type: "string", Format: "uuid"}
+  case t.Implements(marshalerType) || reflect.PointerTo(t).Implements(marshalerType):
+    return &Schema{} // any value, as in json.RawMessage
+  case t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType):
+    return &Schema{Type: "string"}
+  }
+
+  switch t.Kind() {
+  case reflect.Bool:
+    return &Schema{Type: "boolean"}
+  case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+    return &Schema{Type: "integer"}
+  case reflect.Int64, reflect.Uint64:
+    return &Schema{Type: "integer", Format: "int64"}
+  case reflect.Float32, reflect.Float64:
+    return &Schema{Type: "number"}
+  case reflect.String:
+    return &Schema{Type: "string"}
+  case reflect.Slice, reflect.Array:
+    if reflect.Uint8 == t.Elem().Kind() {
+      return &Schema{Type: "string", Format: "byte"}
+    }
+    return &Schema{Type: "array", Items: g.schema(element(t))}
+  case reflect.Map:
+    return &Schema{Type: "object", AdditionalProperties: g.schema(element(t))}
+  case reflect.Struct:
+    if "" == t.Name() {
+      var schema = &Schema{Type: "object", Properties: make(map[string]*Schema)}
+      g.properties(schema, t, false)
+      return schema
+    }
+
+    name, defined := g.names[t]
+    if !defined {
+      name = g.componentName(t)
+      g.names[t] = name
+
+      var schema = &Schema{Type: "object", Properties: make(map[string]*Schema)}
+      g.components[name] = schema
+      g.properties(schema, t, false)
+    }
+
+    return &Schema{Ref: "#/components/schemas/" + name}
+  default:
+    return &Schema{}
+  }
+}
+
+// element returns the type of the elements of the slice, array or map
+// t. Pointers are dereferenced since the lists of the API never have
+// null elements.
+func element(t reflect.Type) reflect.Type {
+  var e = t.Elem()
+  for reflect.Pointer == e.Kind() {
+    e = e.Elem()
+  }
+  return e
+}
+
+// nullable makes schema accept null values too.
+func nullable(schema *Schema) *Schema {
+  switch kind := schema.Type.(type) {
+  case string:
+    schema.Type = []string{kind, "null"}
+    return schema
+  case nil:
+    if "" == schema.Ref {
+      return schema
+    }
+  }
+
+  return &Schema{OneOf: []*Schema{schema, {Type: "null"}}}
+}
+
+// properties adds the fields of the struct t to the properties of
+// schema. When request is true, the fields are those bound from a
+// request, that is, only the ones with a name in their 'json' tag;
+// otherwise, they are the ones encoding/json writes, and every field
+// without the 'omitempty' option is required. Either way, a field
+// with the 'required' criterion in its 'binding' tag is required.
+func (g *generator) properties(schema *Schema, t reflect.Type, request bool) {
+  for i := 0; i < t.NumField(); i++ {
+    var field = t.Field(i)
+
+    name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+
+    if field.Anonymous && "" == name && reflect.Struct == field.Type.Kind() {
+      g.properties(schema, field.Type, request)
+      continue
+    }
+
+    if !field.IsExported() || "-" == name || (request && "" == name) {
+      continue
+    }
+
+    if "" == name {
+      name = field.Name
+    }
+
+    var property = g.schema(field.Type)
+    var required = !request && !slices.Contains(strings.Split(options, ","), "omitempty")
+
+    if criteria := field.Tag.Get("binding"); "" != criteria {
+      required = constrain(property, field.Type, criteria) || required
+    }
+
+    schema.Properties[name] = property
+
+    if required {
+      schema.Required = append(schema.Required, name)
+    }
+  }
+
+  slices.Sort(schema.Required)
+}
+
+// constrain adds to schema the validation criteria of a 'binding' tag
+// that JSON Schema can express, and reports whether the value is
+// required. Criteria on values that are not strings or numbers are
+// ignored.
+func constrain(schema *Schema, t reflect.Type, criteria string) (required bool) {
+  for reflect.Pointer == t.Kind() {
+    t = t.Elem()
+  }
+
+  var isString = reflect.String == t.Kind()
+  var isNumber = reflect.Int <= t.Kind() && reflect.Float64 >= t.Kind()
+
+  for _, criterion := range strings.Split(criteria, ",") {
+    name, parameter, _ := strings.Cut(criterion, "=")
+
+    if "required" == name {
+      required = true
+      continue
+    }
+
+    if !isString && !isNumber {
+      continue
+    }
+
+    if "oneof" == name {
+      schema.Enum = strings.Fields(parameter)
+      continue
+    }
+
+    if isString {
+      switch name {
+      case "url", "http_url", "uri":
+        schema.Format = "uri"
+      case "email":
+        schema.Format = "email"
+      case "uuid", "uuid4":
+        schema.Format = "uuid"
+      case "max", "min", "len":
+        n, err := strconv.Atoi(parameter)
+        if nil != err {
+          continue
+        }
+
+        if "max" == name || "len" == name {
+          schema.MaxLength = &n
+        }
+
+        if "min" == name || "len" == name {
+          schema.MinLength = &n
+        }
+      }
+
+      continue
+    }
+
+    n, err := strconv.ParseFloat(parameter, 64)
+    if nil != err {
+      continue
+    }
+
+    switch name {
+    case "min", "gte":
+      schema.Minimum = &n
+    case "max", "lte":
+      schema.Maximum = &n
+    case "gt":
+      schema.ExclusiveMinimum = &n
+    case "lt":
+      schema.ExclusiveMaximum = &n
+    }
+  }
+
+  return required
+}
+
+// String returns the schema of a string of the given format, if any.
+func String(format string) *Schema {
+  return &Schema{Type: "string", Format: format}
+}
+
+// Integer returns the schema of an integer between min and max.
+func Integer(min, max int) *Schema {
+  var lower, upper = float64(min), float64(max)
+  return &Schema{Type: "integer", Minimum: &lower, Maximum: &upper}
+}
+
+// Boolean returns the schema of a boolean.
+func Boolean() *Schema {
+  return &Schema{Type: "boolean"}
+}
+
+// Files returns the schema of a list of uploaded files.
+func Files() *Schema {
+  return &Schema{Type: "array", Items: &Schema{Type: "string", Format: "binary"}}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+  "fontseca.dev/config"
+  "fontseca.dev/database"
+  "github.com/gin-gonic/gin"
+  "github.com/stretchr/testify/assert"
+  "github.com/stretchr/testify/require"
+  "net/http"
+  "net/http/httptest"
+  "strings"
+  "testing"
+)
+
+func TestAPIDocument(t *testing.T) {
+  gin.SetMode(gin.TestMode)
+
+  db, err := database.Open("sqlite3", ":memory:", 1, 0)
+  require.NoError(t, err)
+  defer db.Close()
+
+  var cfg = config.Default()
+  cfg.Admin.Token = "token"
+
+  var engine = gin.New()
+  route(engine, db, cfg)
+
+  var document = newAPIDocument(engine.Routes())
+  var registered = make(map[string]bool)
+
+  t.Run("every route is documented", func(t *testing.T) {
+    for _, r := range engine.Routes() {
+      if !isAPIRoute(r.Path) {
+        continue
+      }
+
+      registered[r.Method+" "+r.Path] = true
+
+      item, found := document.Paths[r.Path]
+      if assert.Truef(t, found, "route %s %s is missing from the API document; describe it in 'endpoints'", r.Method, r.Path) {
+        assert.NotNilf(t, item[strings.ToLower(r.Method)], "route %s %s is missing from the API document; describe it in 'endpoints'", r.Method, r.Path)
+      }
+    }
+  })
+
+  t.Run("every endpoint is registered", func(t *testing.T) {
+    for key := range endpoints {
+      assert.Truef(t, registered[key], "endpoint %s is documented but not registered", key)
+    }
+  })
+
+  t.Run("errors are problems", func(t *testing.T) {
+    for path, item := range document.Paths {
+      for method, operation := range item {
+        response := operation.Responses["default"]
+        if assert.NotNilf(t, response, "%s %s", method, path) {
+          assert.Equal(t, "#/components/schemas/Problem", response.Content["application/problem+json"].Schema.Ref)
+        }
+      }
+    }
+
+    assert.Contains(t, document.Components.Schemas, "Problem")
+  })
+
+  t.Run("secured endpoints require the token", func(t *testing.T) {
+    for key, endpoint := range endpoints {
+      if !endpoint.Secured {
+        continue
+      }
+
+      method, path, _ := strings.Cut(key, " ")
+      var recorder = httptest.NewRecorder()
+      engine.ServeHTTP(recorder, httptest.NewRequest(method, path, nil))
+      assert.Equalf(t, http.StatusUnauthorized, recorder.Code, "%s answers without the token", key)
+    }
+  })
+}
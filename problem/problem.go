@@ -149,6 +149,29 @@ func (p *Problem) Error() string {
   return p.detail
 }
 
+// Describe returns the HTTP status code, the title and the detail of
+// the problem as they are emitted.
+func (p *Problem) Describe() (status int, title, detail string) {
+  p.sanitize()
+  return p.status, p.title, p.detail
+}
+
+// Extensions returns the additional members of the problem by their
+// names. A member added more than once holds a slice of its values.
+func (p *Problem) Extensions() map[string]any {
+  var members = make(map[string]any)
+  for _, mp := range p.extensions {
+    for key, values := range mp {
+      if p.hasOneValueOnly(values) {
+        members[key] = values[0]
+      } else {
+        members[key] = values
+      }
+    }
+  }
+  return members
+}
+
 // hasOneValueOnly checks if the slice of extensions for a key has only one element.
 func (p *Problem) hasOneValueOnly(values []any) bool {
   return 1 == len(values)
@@ -255,11 +278,20 @@ func (p *Problem) doEmit(data []byte, w http.ResponseWriter) {
   }
 }
 
+// TraceIDHeader is the response header that carries the ID of the
+// trace of a request.
+const TraceIDHeader = "X-Trace-Id"
+
 // Emit sends the problem details as an HTTP response through the
 // provided http.ResponseWriter w. The response body is a JSON object
-// and its Content-Type is "application/problem+json".
+// and its Content-Type is "application/problem+json". If w already
+// carries a TraceIDHeader header, the problem gets it as the extension
+// member "trace_id".
 func (p *Problem) Emit(w http.ResponseWriter) {
   if nil != w {
+    if id := w.Header().Get(TraceIDHeader); "" != id && !p.hasExtension("trace_id") {
+      p.With("trace_id", id)
+    }
     p.sanitize()
     s := p.generateStruct()
     if serialized := p.serialize(s); nil != serialized {
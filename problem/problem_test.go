@@ -209,6 +209,21 @@ func TestEmit(t *testing.T) {
     assert.NotContains(t, object, "detail", "Superfluous \"detail\" field in JSON body response.")
     assert.NotContains(t, object, "instance", "Superfluous \"instance\" field in JSON body response.")
     assert.NotContains(t, object, "balance", "Superfluous \"balance\" field in JSON body response.")
+    assert.NotContains(t, object, "trace_id", "Superfluous \"trace_id\" field in JSON body response.")
+  })
+
+  t.Run("Problem of a traced request", func(t *testing.T) {
+    var expectedTraceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+
+    var recorder = httptest.NewRecorder()
+    recorder.Header().Set(TraceIDHeader, expectedTraceID)
+    NewInternal().Emit(recorder)
+
+    var object map[string]any
+    require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &object))
+
+    assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+    assert.Equal(t, expectedTraceID, object["trace_id"])
   })
 }
 
@@ -329,4 +344,29 @@ func TestProblem(t *testing.T) {
     assert.Contains(t, p.extensions, map[string][]any{"balance": {30}}, "Extensions slice does not include 'balance' entry or entry's value is not correct.")
     assert.Contains(t, p.extensions, map[string][]any{"accounts": {"/account/12345", "/account/67890"}}, "Extensions slice does not include 'accounts' entry or entry's value is not correct.")
   })
+
+  t.Run("Describe", func(t *testing.T) {
+    var p Problem
+    p.Status(http.StatusConflict)
+    p.Detail(" The article was changed. ")
+
+    status, title, detail := p.Describe()
+    assert.Equal(t, http.StatusConflict, status)
+    assert.Equal(t, "Conflict", title)
+    assert.Equal(t, "The article was changed.", detail)
+  })
+
+  t.Run("Extensions", func(t *testing.T) {
+    var p Problem
+    assert.Empty(t, p.Extensions())
+
+    p.With("balance", 30)
+    p.With("accounts", "/account/12345")
+    p.With("accounts", "/account/67890")
+
+    assert.Equal(t, map[string]any{
+      "balance":  30,
+      "accounts": []any{"/account/12345", "/account/67890"},
+    }, p.Extensions())
+  })
 }
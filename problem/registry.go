@@ -94,3 +94,75 @@ func NewMissingParameter(parameter string) *Problem {
   p.With("missing_parameter", parameter)
   return &p
 }
+
+func NewUnauthorized() *Problem {
+  var p Problem
+  p.Type("about:blank")
+  p.Status(http.StatusUnauthorized)
+  p.Title("Unauthorized.")
+  p.Detail("A valid bearer token is required to access this resource. Please provide it in the 'Authorization' header.")
+  return &p
+}
+
+func NewPreconditionFailed(etag string) *Problem {
+  var p Problem
+  p.Type("about:blank")
+  p.Status(http.StatusPreconditionFailed)
+  p.Title("Precondition failed.")
+  p.Detail("The resource has changed since it was last retrieved. Please retrieve it again and retry the request with its current 'ETag' in the 'If-Match' header.")
+  p.With("etag", etag)
+  return &p
+}
+
+func NewPreconditionRequired(recordType string) *Problem {
+  var p Problem
+  p.Type("about:blank")
+  p.Status(http.StatusPreconditionRequired)
+  p.Title("Precondition required.")
+  p.Detail(fmt.Sprintf("The version of the %s record being modified is required so that no change made by someone else is overwritten. Please retrieve the record and retry the request with its current version.", recordType))
+  p.With("record_type", recordType)
+  return &p
+}
+
+func NewVersionConflict(id, recordType string, currentVersion int) *Problem {
+  var p Problem
+  p.Type("about:blank")
+  p.Status(http.StatusConflict)
+  p.Title("Version conflict.")
+  p.Detail(fmt.Sprintf("The %s record with UUID '%s' was changed by someone else. Please retrieve its current version, reapply your changes and try again.", recordType, id))
+  p.With("record_uuid", id)
+  p.With("record_type", recordType)
+  p.With("current_version", currentVersion)
+  return &p
+}
+
+func NewInvalidCursor(cursor string) *Problem {
+  var p Problem
+  p.Type("about:blank")
+  p.Status(http.StatusBadRequest)
+  p.Title("Invalid cursor.")
+  p.Detail("The 'cursor' parameter is not one of the cursors returned by this endpoint. Please use the 'next_cursor' of a previous page or omit it to start from the first page.")
+  p.With("cursor", cursor)
+  return &p
+}
+
+func NewNotReady(checks map[string]string) *Problem {
+  var p Problem
+  p.Type("about:blank")
+  p.Status(http.StatusServiceUnavailable)
+  p.Title("Service not ready.")
+  p.Detail("The server is not ready to handle requests, either because it is shutting down or because something it depends on failed. Please try again later.")
+  p.With("checks", checks)
+  return &p
+}
+
+func NewInTrash(id, recordType string) *Problem {
+  var p Problem
+  p.Type("about:blank")
+  p.Status(http.StatusConflict)
+  p.Title("Record in the trash.")
+  p.Detail(fmt.Sprintf("The %s record with ID '%s' is in the trash. Please restore it with '/trash.restore' or wait until it is purged to add it again.", recordType, id))
+  p.With("record_id", id)
+  p.With("record_type", recordType)
+  return &p
+}
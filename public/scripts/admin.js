@@ -0,0 +1,16 @@
+// Problems are answered with an error status and are swapped into the
+// element the server retargets them to, so they show next to the form.
+document.addEventListener("htmx:beforeSwap", function (e) {
+  if (400 <= e.detail.xhr.status) {
+    e.detail.shouldSwap = true;
+    e.detail.isError = false;
+  }
+});
+
+document.addEventListener("htmx:beforeRequest", function () {
+  const problem = document.getElementById("problem");
+
+  if (null !== problem) {
+    problem.replaceChildren();
+  }
+});
@@ -6,17 +6,23 @@ import (
   "database/sql"
   "errors"
   "fmt"
+  "fontseca.dev/database"
+  "fontseca.dev/diff"
+  "fontseca.dev/metrics"
   "fontseca.dev/model"
   "fontseca.dev/problem"
+  "fontseca.dev/tracing"
   "fontseca.dev/transfer"
   "github.com/gin-gonic/gin"
   "github.com/google/uuid"
   "log/slog"
   "net/http"
   "net/url"
+  "slices"
   "strconv"
   "strings"
   "sync"
+  "sync/atomic"
   "time"
 )
 
@@ -44,9 +50,13 @@ import (
 // it is released and physically merged to the original article.
 //
 // The draft and the article are both referenced by the same UUID. The
-// patch is a completely different object that points to an article.
-// Since an article can only have one patch at a time, by using the
-// article's UUID, you can access any patch it currently has.
+// patch is a completely different object, with its own UUID, that
+// points to an article. An article can have many patches at a time,
+// so a patch remembers the revision of the article it was branched
+// from. Every release increments the revision of the article, and a
+// patch branched from an older revision is merged with the changes
+// released since then; if both changed the same parts of the article,
+// the release is rejected.
 type ArchiveRepository interface {
   // Draft starts the creation process of an article. It returns the
   // UUID of the draft that was created.
@@ -74,8 +84,18 @@ type ArchiveRepository interface {
   // function over non-hidden articles, so it attempts to find and
   // amass every article whose title contains any of the keywords
   // (if more than one) in needle.
+  //
+  // If filter.After is set, then Get retrieves the page of articles
+  // that follows that cursor instead of skipping filter.Page pages,
+  // so that deep pages are as cheap as the first one, even if the
+  // article the cursor was made from was removed since. Likewise, if
+  // filter.Before is set, it retrieves the page that precedes it.
   Get(ctx context.Context, filter *transfer.ArticleFilter, hidden, draftsOnly bool) (articles []*transfer.Article, err error)
 
+  // Count counts the articles that Get would retrieve for filter if it
+  // had a single page.
+  Count(ctx context.Context, filter *transfer.ArticleFilter, hidden, draftsOnly bool) (total int, err error)
+
   // GetOne retrieves one published article by the URL '/archive/:topic/:year/:month/:slug'.
   GetOne(ctx context.Context, request *transfer.ArticleRequest) (article *model.Article, err error)
 
@@ -87,15 +107,18 @@ type ArchiveRepository interface {
 
   // Amend starts the process to update an article. To amend the article,
   // a public copy of it is kept available to everyone while a patch
-  // is created to store any revision made to the article.
+  // is created to store any revision made to the article. It returns
+  // the UUID of the patch that was created.
   //
-  // If the article is still a draft, or it's already being amended,
-  // any call to this method has no effect.
-  Amend(ctx context.Context, id string) error
-
-  // Remove completely removes an article and any patch it currently
-  // has from the database. If the article is a draft, calling Remove
-  // has no effect on it whatsoever.
+  // An article can be amended several times concurrently; each call
+  // creates a new patch branched from the current revision of the
+  // article. If the article is still a draft, calling Amend has no
+  // effect on it.
+  Amend(ctx context.Context, id string) (patchID string, err error)
+
+  // Remove moves an article to the trash, along with any patch it
+  // currently has. If the article is a draft, calling Remove has no
+  // effect on it whatsoever.
   //
   // If you want to remove a draft, use Discard instead.
   Remove(ctx context.Context, id string) error
@@ -123,27 +146,70 @@ type ArchiveRepository interface {
   // eventually expire after a certain amount of time.
   Share(ctx context.Context, id string) (link string, err error)
 
-  // Discard completely drops a draft; otherwise if called on a patch
-  // it discards it but keeps the original article.
+  // Discard moves a draft to the trash; otherwise if called on a patch
+  // it moves the patch to the trash but keeps the original article.
   //
   // This method has no effect on an article.
   Discard(ctx context.Context, id string) error
 
+  // Restore brings back an article, a draft or a patch from the trash.
+  Restore(ctx context.Context, id string) error
+
   // Revise adds a correction or inclusion to a draft or patch in order
-  // to correct or improve it.
+  // to correct or improve it. revision.Version must be the current
+  // version of the draft or patch; otherwise a conflict problem with the
+  // current version is returned, or a precondition required problem if
+  // it is zero.
   Revise(ctx context.Context, id string, revision *transfer.ArticleRevision) error
 
   // Release merges patch into the original article and published the
   // update immediately after merging.
   //
+  // If the article was updated after the patch was created, Release
+  // performs a three-way merge between the revision the patch was
+  // branched from, the patch and the current article. When both
+  // changed the same parts of the article, it returns a conflict
+  // with the overlapping hunks and leaves the patch untouched.
+  //
   // This method works only for patches.
   Release(ctx context.Context, id string) error
 
   // GetPatches retrieves all the ongoing patches of every article.
   GetPatches(ctx context.Context) (patches []*model.ArticlePatch, err error)
 
+  // ListPatches retrieves the page of ongoing patches that follows the
+  // cursor of page, oldest first.
+  ListPatches(ctx context.Context, page *transfer.PageRequest) (patches *transfer.Page[*model.ArticlePatch], err error)
+
+  // GetPatch retrieves one article patch by its UUID, along with the
+  // snapshot of the article it was branched from.
+  GetPatch(ctx context.Context, id string) (patch *model.ArticlePatch, err error)
+
+  // Import inserts an article written outside the archive, or updates
+  // the one that already has its slug, so that importing the same
+  // article twice leaves a single copy of it. The topic and the tags
+  // of the article are created if they do not exist yet, or restored
+  // if they are in the trash. An article in the trash is not updated;
+  // a new one is created instead.
+  //
+  // The status reports whether the article was created, updated or
+  // left unchanged, as any of the transfer.Import* constants.
+  Import(ctx context.Context, article *transfer.ArticleImport) (id, status string, err error)
+
+  // FlushViews writes the cached article views to the database right
+  // away instead of waiting for midnight.
+  FlushViews(ctx context.Context) error
+
+  // PruneLinks removes every expired shareable link and returns how
+  // many were removed.
+  PruneLinks(ctx context.Context) (removed int64, err error)
+
   // Close forces all caches be written.
   Close()
+
+  // Running reports whether the goroutine that writes the cached
+  // article views to the database is still alive.
+  Running() bool
 }
 
 // visitor is the IP address of an article reader.
@@ -159,22 +225,29 @@ type entry struct {
 type articleViewsCache map[string]*entry
 
 type archiveRepository struct {
-  db                *sql.DB
+  db                *database.DB
   publicationsCache []*transfer.Publication
   articleViewsCache articleViewsCache
   done              chan struct{}
   mu                sync.RWMutex
-  cleanOnce         sync.Once // for cleaning broken links once per share
+  cleanOnce         *sync.Once  // for cleaning broken links once per share; replaced under mu
+  writing           atomic.Bool // whether cacheWriter is running
+  shareExpiry       time.Duration
 }
 
-func NewArchiveRepository(db *sql.DB) ArchiveRepository {
+// NewArchiveRepository returns an ArchiveRepository whose shareable
+// links expire after shareExpiry.
+func NewArchiveRepository(db *database.DB, shareExpiry time.Duration) ArchiveRepository {
   r := &archiveRepository{
     db:                db,
+    shareExpiry:       shareExpiry,
     publicationsCache: []*transfer.Publication{},
     articleViewsCache: articleViewsCache{},
     done:              make(chan struct{}),
+    cleanOnce:         new(sync.Once),
   }
 
+  r.writing.Store(true)
   go r.cacheWriter()
 
   return r
@@ -182,6 +255,8 @@ func NewArchiveRepository(db *sql.DB) ArchiveRepository {
 
 // cacheWriter is a goroutine that writes articles view cache every midnight.
 func (r *archiveRepository) cacheWriter() {
+  defer r.writing.Store(false)
+
   var (
     now           = time.Now()
     midnight      = time.Date(now.Year(), now.Month(), 1+now.Day(), 0, 0, 0, 0, now.Location())
@@ -217,6 +292,17 @@ func (r *archiveRepository) Close() {
   r.writeViewsCache(context.TODO())
 }
 
+func (r *archiveRepository) FlushViews(ctx context.Context) error {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
+  return r.writeViewsCache(ctx)
+}
+
+func (r *archiveRepository) Running() bool {
+  return r.writing.Load()
+}
+
 // closed checks if the repository has been closed, that is the
 // Close method was invoked.
 func (r *archiveRepository) closed() bool {
@@ -228,25 +314,37 @@ func (r *archiveRepository) closed() bool {
   }
 }
 
-// writeViewsCache is a goroutine that writes the cached article views to the database;
-// it should be run every midnight.
-func (r *archiveRepository) writeViewsCache(ctx context.Context) {
+// writeViewsCache writes the cached article views to the database in
+// a single transaction and resets the cache; it should be run every
+// midnight. The updates run one after the other: statements of the
+// same transaction share its connection, so running them in parallel
+// gains nothing.
+func (r *archiveRepository) writeViewsCache(ctx context.Context) error {
   r.mu.RLock()
 
   if 0 == len(r.articleViewsCache) {
     r.mu.RUnlock()
-    return
+    return nil
+  }
+
+  var views = make(map[string]int64, len(r.articleViewsCache))
+  for article, metadata := range r.articleViewsCache {
+    views[article] = metadata.views
   }
 
   r.mu.RUnlock()
 
-  slog.Info("writing article views cache to database")
+  slog.InfoContext(ctx, "writing article views cache to database")
+
+  defer func(start time.Time) {
+    metrics.ViewsFlushDuration.Observe(time.Since(start).Seconds())
+  }(time.Now())
 
   tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
 
   if nil != err {
-    slog.Error(err.Error())
-    return
+    slog.ErrorContext(ctx, err.Error())
+    return err
   }
 
   defer tx.Rollback()
@@ -256,43 +354,40 @@ func (r *archiveRepository) writeViewsCache(ctx context.Context) {
      SET "views" = "views" + @views
    WHERE "uuid" = @uuid;`
 
-  writers := sync.WaitGroup{}
   ctx, cancel := context.WithTimeout(ctx, time.Minute)
   defer cancel()
 
-  for article, metadata := range r.articleViewsCache {
-    writers.Add(1)
-
-    go func() {
-      r.mu.RLock()
-      defer writers.Done()
-      defer r.mu.RUnlock()
+  for article, count := range views {
+    slog.InfoContext(ctx, "writing cached view(s)",
+      slog.Int64("count", count),
+      slog.String("article_uuid", article))
 
-      slog.Info("writing cached view(s)",
-        slog.Int64("count", metadata.views),
-        slog.String("article_uuid", article))
+    _, err = tx.ExecContext(ctx, writeViewsQuery,
+      sql.Named("uuid", article),
+      sql.Named("views", count))
 
-      _, err := tx.ExecContext(ctx, writeViewsQuery,
-        sql.Named("uuid", article),
-        sql.Named("views", metadata.views))
-
-      if nil != err {
-        slog.Error(err.Error(), slog.String("article_uuid", article))
-      }
-    }()
+    if nil != err {
+      slog.ErrorContext(ctx, err.Error(), slog.String("article_uuid", article))
+      return err
+    }
   }
 
-  writers.Wait()
+  if err = tx.Commit(); nil != err {
+    slog.ErrorContext(ctx, err.Error())
+    return err
+  }
 
   if !r.closed() {
-    slog.Info("resetting article views cache")
+    slog.InfoContext(ctx, "resetting article views cache")
 
+    r.mu.Lock()
     r.articleViewsCache = articleViewsCache{}
-  }
+    r.mu.Unlock()
 
-  if err := tx.Commit(); nil != err {
-    slog.Error(err.Error())
+    metrics.ViewsCacheSize.Set(0)
   }
+
+  return nil
 }
 
 // views returns the cached views of the given article.
@@ -318,17 +413,14 @@ func (r *archiveRepository) incrementViews(ctx context.Context, article string)
   r.mu.Lock()
   defer r.mu.Unlock()
 
-  var (
-    value         = ctx.Value(VisitorKey)
-    ip    visitor = ""
-  )
+  var ip visitor
 
-  if "" != value {
-    ip = visitor(value.(string))
+  if value, ok := ctx.Value(VisitorKey).(string); ok {
+    ip = visitor(value)
   }
 
   if _, isCached := r.articleViewsCache[article]; !isCached {
-    slog.Info("caching article views count", slog.String("article_uuid", article))
+    slog.InfoContext(ctx, "caching article views count", slog.String("article_uuid", article))
 
     watchers := make(map[visitor]struct{})
     watchers[ip] = struct{}{}
@@ -338,6 +430,8 @@ func (r *archiveRepository) incrementViews(ctx context.Context, article string)
       views:    1,
     }
 
+    metrics.ViewsCacheSize.Set(float64(len(r.articleViewsCache)))
+
     return
   }
 
@@ -355,83 +449,97 @@ func (r *archiveRepository) incrementViews(ctx context.Context, article string)
 // cleanBrokenLinks is a goroutine that cleans up shareable links that
 // are no longer valid because have already expired.
 func (r *archiveRepository) cleanBrokenLinks() {
-  r.cleanOnce.Do(func() {
-    ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-    defer cancel()
+  r.mu.RLock()
+  var once = r.cleanOnce
+  r.mu.RUnlock()
 
-    checkThereAreBrokenLinksQuery := `
-    SELECT count (*)
-      FROM "article_link"
-     WHERE "expires_at" <= current_timestamp;`
+  once.Do(func() {
+    r.PruneLinks(context.Background())
+  })
+}
 
-    nbroken := 0
+func (r *archiveRepository) PruneLinks(ctx context.Context) (removed int64, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
 
-    err := r.db.QueryRowContext(ctx, checkThereAreBrokenLinksQuery).Scan(&nbroken)
+  checkThereAreBrokenLinksQuery := `
+  SELECT count (*)
+    FROM "article_link"
+   WHERE "expires_at" <= current_timestamp;`
 
-    if nil != err {
-      slog.Error(err.Error())
-    }
+  nbroken := 0
 
-    if 0 == nbroken {
-      return
-    }
+  ctx1, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
 
-    attempts := 1
-    anew := func() { attempts++ }
-    done := func() bool { return attempts > 3 }
+  err = r.db.QueryRowContext(ctx1, checkThereAreBrokenLinksQuery).Scan(&nbroken)
 
-  again:
-    slog.Info("trying to clear all broken shareable links",
-      slog.Int("broken_links", nbroken),
-      slog.Int("attempt", attempts))
+  if nil != err {
+    slog.ErrorContext(ctx, err.Error())
+    return 0, err
+  }
 
-    tx, err := r.db.BeginTx(context.Background(), &sql.TxOptions{Isolation: sql.LevelSerializable})
+  if 0 == nbroken {
+    return 0, nil
+  }
 
-    if nil != err {
-      slog.Error(err.Error())
+  removeBrokenLinksQuery := `
+  DELETE FROM "article_link"
+        WHERE "expires_at" <= current_timestamp;`
 
-      anew()
-      if done() {
-        return
-      }
+  for attempt := 1; attempt <= 3; attempt++ {
+    slog.InfoContext(ctx, "trying to clear all broken shareable links",
+      slog.Int("broken_links", nbroken),
+      slog.Int("attempt", attempt))
 
-      goto again
+    removed, err = r.removeBrokenLinks(ctx, removeBrokenLinksQuery)
+    if nil == err {
+      metrics.BrokenLinksCleaned.Add(float64(removed))
+      return removed, nil
     }
 
-    defer tx.Rollback()
+    slog.ErrorContext(ctx, err.Error())
+  }
 
-    removeBrokenLinksQuery := `
-    DELETE FROM "article_link"
-          WHERE "expires_at" <= current_timestamp;`
+  return 0, err
+}
 
-    ctx, cancel = context.WithTimeout(context.Background(), 30*time.Second)
-    defer cancel()
+// removeBrokenLinks runs query, which removes the expired shareable
+// links, within a transaction of its own.
+func (r *archiveRepository) removeBrokenLinks(ctx context.Context, query string) (removed int64, err error) {
+  tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+  if nil != err {
+    return 0, err
+  }
 
-    _, err = tx.ExecContext(ctx, removeBrokenLinksQuery)
+  defer tx.Rollback()
 
-    if nil != err {
-      slog.Error(err.Error())
+  ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+  defer cancel()
 
-      anew()
-      if done() {
-        return
-      }
+  result, err := tx.ExecContext(ctx, query)
+  if nil != err {
+    return 0, err
+  }
 
-      goto again
-    }
+  if err = tx.Commit(); nil != err {
+    return 0, err
+  }
 
-    if err = tx.Commit(); nil != err {
-      slog.Error(err.Error())
-    }
-  })
+  return result.RowsAffected()
 }
 
 func (r *archiveRepository) Draft(ctx context.Context, creation *transfer.ArticleCreation) (id string, err error) {
-  slog.Info("drafting new article", slog.String("title", creation.Title))
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
+  slog.InfoContext(ctx, "drafting new article", slog.String("title", creation.Title))
 
   tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return uuid.Nil.String(), err
   }
 
@@ -453,12 +561,12 @@ func (r *archiveRepository) Draft(ctx context.Context, creation *transfer.Articl
   )
 
   if err = result.Scan(&id); nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return uuid.Nil.String(), err
   }
 
   if err = tx.Commit(); nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return uuid.Nil.String(), err
   }
 
@@ -466,11 +574,31 @@ func (r *archiveRepository) Draft(ctx context.Context, creation *transfer.Articl
 }
 
 func (r *archiveRepository) Publish(ctx context.Context, id string) error {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
+  err := r.db.Transact(ctx, func(ctx context.Context) error {
+    return r.publish(ctx, id)
+  })
+
+  if nil != err {
+    return err
+  }
+
+  r.db.OnCommit(ctx, r.setPublicationsCache)
+
+  return nil
+}
+
+// publish checks that the draft id can be published and publishes it.
+func (r *archiveRepository) publish(ctx context.Context, id string) error {
   isArticleDraftQuery := `
   SELECT "draft" IS TRUE
      AND "published_at" IS NULL
     FROM "article"
-   WHERE "uuid" = @uuid;`
+   WHERE "uuid" = @uuid
+     AND "deleted_at" IS NULL;`
 
   ctx1, cancel := context.WithTimeout(ctx, time.Second)
   defer cancel()
@@ -484,7 +612,7 @@ func (r *archiveRepository) Publish(ctx context.Context, id string) error {
     if errors.Is(err, sql.ErrNoRows) {
       err = problem.NewNotFound(id, "draft")
     } else {
-      slog.Error(err.Error())
+      slog.ErrorContext(ctx, err.Error())
     }
 
     return err
@@ -514,7 +642,7 @@ func (r *archiveRepository) Publish(ctx context.Context, id string) error {
   err = r.db.QueryRowContext(ctx, hasTopicQuery, id).Scan(&hasTopic)
 
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return err
   }
 
@@ -529,7 +657,7 @@ func (r *archiveRepository) Publish(ctx context.Context, id string) error {
 
   tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return err
   }
 
@@ -541,14 +669,14 @@ func (r *archiveRepository) Publish(ctx context.Context, id string) error {
          "published_at" = current_timestamp
    WHERE "uuid" = @uuid;`
 
-  slog.Info("publishing draft", slog.String("uuid", id))
+  slog.InfoContext(ctx, "publishing draft", slog.String("uuid", id))
 
   ctx, cancel = context.WithTimeout(ctx, 2*time.Second)
   defer cancel()
 
   result, err := tx.ExecContext(ctx, publishArticleDraftQuery, sql.Named("uuid", id))
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return err
   }
 
@@ -557,22 +685,24 @@ func (r *archiveRepository) Publish(ctx context.Context, id string) error {
   }
 
   if err = tx.Commit(); nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return err
   }
 
-  r.setPublicationsCache(ctx)
-
   return nil
 }
 
 func (r *archiveRepository) SetSlug(ctx context.Context, id, slug string) error {
-  slog.Info("changing article slug", slog.String("article_uuid", id), slog.String("new_slug", slug))
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
+  slog.InfoContext(ctx, "changing article slug", slog.String("article_uuid", id), slog.String("new_slug", slug))
 
   tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
 
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return err
   }
 
@@ -583,7 +713,8 @@ func (r *archiveRepository) SetSlug(ctx context.Context, id, slug string) error
      SET "slug" = @slug
    WHERE "uuid" = @uuid
      AND "draft" IS FALSE
-     AND "published_at" IS NOT NULL;`
+     AND "published_at" IS NOT NULL
+     AND "deleted_at" IS NULL;`
 
   ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
   defer cancel()
@@ -591,7 +722,7 @@ func (r *archiveRepository) SetSlug(ctx context.Context, id, slug string) error
   result, err := tx.ExecContext(ctx, setSlugQuery, sql.Named("uuid", id), sql.Named("slug", slug))
 
   if nil != err && !errors.Is(sql.ErrNoRows, err) {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return err
   }
 
@@ -600,7 +731,7 @@ func (r *archiveRepository) SetSlug(ctx context.Context, id, slug string) error
   }
 
   if err := tx.Commit(); nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return err
   }
 
@@ -613,17 +744,24 @@ func (r *archiveRepository) setPublicationsCache(ctx context.Context) {
 }
 
 func (r *archiveRepository) Publications(ctx context.Context) (publications []*transfer.Publication, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
   if 0 < len(r.publicationsCache) {
     return r.publicationsCache, nil
   }
 
+  var dialect = r.db.Dialect()
+
   getPublicationsQuery := `
-  SELECT cast(strftime('%m', "published_at") AS INTEGER) AS "month",
-         cast(strftime('%Y', "published_at") AS INTEGER) AS "year"
+  SELECT ` + dialect.Month(`"published_at"`) + ` AS "month",
+         ` + dialect.Year(`"published_at"`) + ` AS "year"
     FROM "article"
    WHERE "draft" IS FALSE
      AND "published_at" IS NOT NULL
      AND "hidden" IS FALSE
+     AND "deleted_at" IS NULL
      GROUP BY "month", "year"
      ORDER BY "year" DESC, "month" DESC;`
 
@@ -633,7 +771,7 @@ func (r *archiveRepository) Publications(ctx context.Context) (publications []*t
   result, err := r.db.QueryContext(ctx, getPublicationsQuery)
 
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return nil, err
   }
 
@@ -650,7 +788,7 @@ func (r *archiveRepository) Publications(ctx context.Context) (publications []*t
     )
 
     if nil != err {
-      slog.Error(err.Error())
+      slog.ErrorContext(ctx, err.Error())
       return nil, err
     }
 
@@ -662,27 +800,24 @@ func (r *archiveRepository) Publications(ctx context.Context) (publications []*t
   return publications, nil
 }
 
-func (r *archiveRepository) Get(ctx context.Context, filter *transfer.ArticleFilter, hidden, draftsOnly bool) (articles []*transfer.Article, err error) {
-  query := strings.Builder{}
+// writeArticleFilter writes to query the FROM and WHERE clauses, in the
+// SQL of dialect, that select the articles matching filter, leaving out
+// its pagination.
+func writeArticleFilter(query *strings.Builder, filter *transfer.ArticleFilter, dialect database.Dialect) {
   query.WriteString(`
-  SELECT "uuid",
-         "title",
-         "slug",
-         "topic",
-         "pinned",
-         "published_at"
     FROM "article"
-   WHERE "draft" IS @drafts_only
+   WHERE "draft" IS NOT DISTINCT FROM @drafts_only
+     AND "deleted_at" IS NULL
      AND CASE WHEN @drafts_only
               THEN "published_at" IS NULL
               ELSE "published_at" IS NOT NULL
-               AND "hidden" IS @hidden
+               AND "hidden" IS NOT DISTINCT FROM @hidden
                AND CASE WHEN @publication_year <> 0 AND @publication_month <> 0 
                    THEN
-                        CAST(strftime('%Y', "published_at") AS INTEGER) = @publication_year AND
-                        CAST(strftime('%m', "published_at") AS INTEGER) = @publication_month
+                        ` + dialect.Year(`"published_at"`) + ` = @publication_year AND
+                        ` + dialect.Month(`"published_at"`) + ` = @publication_month
                     ELSE TRUE END
-               AND CASE WHEN @topic <> ""
+               AND CASE WHEN @topic <> ''
                    THEN
                         "topic" = @topic
                    ELSE TRUE END
@@ -694,15 +829,14 @@ func (r *archiveRepository) Get(ctx context.Context, filter *transfer.ArticleFil
         chunk = strings.ReplaceAll(chunk, "'", "''")
       }
 
-      query.WriteString("\nAND \"title\" LIKE '%" + chunk + "%'")
+      query.WriteString("\nAND \"title\" " + dialect.Like() + " '%" + chunk + "%'")
     }
   }
+}
 
-  query.WriteString(`
-  ORDER BY "pinned" DESC, "published_at" DESC
-  LIMIT @rpp
-  OFFSET @rpp * (@page - 1);`)
-
+// articleFilterArgs returns the arguments of the query written by
+// writeArticleFilter, along with the ones for its pagination.
+func articleFilterArgs(filter *transfer.ArticleFilter, hidden, draftsOnly bool) []any {
   var (
     year  = 0
     month = 0
@@ -713,10 +847,7 @@ func (r *archiveRepository) Get(ctx context.Context, filter *transfer.ArticleFil
     month = int(filter.Publication.Month)
   }
 
-  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
-  defer cancel()
-
-  result, err := r.db.QueryContext(ctx, query.String(),
+  return []any{
     sql.Named("drafts_only", draftsOnly),
     sql.Named("hidden", hidden),
     sql.Named("page", filter.Page),
@@ -724,10 +855,104 @@ func (r *archiveRepository) Get(ctx context.Context, filter *transfer.ArticleFil
     sql.Named("topic", filter.Topic),
     sql.Named("publication_year", year),
     sql.Named("publication_month", month),
+  }
+}
+
+// articleSortKeys are the keys lists of articles are sorted by, which
+// make the cursors of transfer.Article.Cursor: whether an article is
+// pinned and its publication date, which is '-infinity' for drafts.
+var articleSortKeys = []sortKey{
+  boolKey(`"pinned"`),
+  {
+    column: `coalesce ("published_at", '-infinity')`,
+    value: func(s string) (any, error) {
+      if "-infinity" == s {
+        return s, nil
+      }
+      return timeKey("").value(s)
+    },
+  },
+  textKey(`"uuid"`),
+}
+
+func (r *archiveRepository) Count(ctx context.Context, filter *transfer.ArticleFilter, hidden, draftsOnly bool) (total int, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
+  query := strings.Builder{}
+  query.WriteString(`
+  SELECT count (*)`)
+
+  writeArticleFilter(&query, filter, r.db.Dialect())
+
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+
+  err = r.db.QueryRowContext(ctx, query.String(), articleFilterArgs(filter, hidden, draftsOnly)...).Scan(&total)
+
+  if nil != err {
+    slog.ErrorContext(ctx, err.Error())
+    return 0, err
+  }
+
+  return total, nil
+}
+
+func (r *archiveRepository) Get(ctx context.Context, filter *transfer.ArticleFilter, hidden, draftsOnly bool) (articles []*transfer.Article, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
+  query := strings.Builder{}
+  query.WriteString(`
+  SELECT "uuid",
+         "title",
+         "slug",
+         "topic",
+         "pinned",
+         "published_at",
+         "updated_at"`)
+
+  writeArticleFilter(&query, filter, r.db.Dialect())
+
+  var (
+    condition = "TRUE"
+    order     = `"pinned" DESC, "published_at" DESC NULLS LAST, "uuid" DESC`
+    offset    = "@rpp * (@page - 1)"
+    args      []any
   )
 
+  switch {
+  case nil != filter.After:
+    condition, args, err = after(filter.After, true, articleSortKeys...)
+    offset = "0"
+  case nil != filter.Before:
+    // The page that precedes the cursor is the one that follows it in
+    // the reverse order, which is reversed back once retrieved.
+    condition, args, err = after(filter.Before, false, articleSortKeys...)
+    order = `"pinned", "published_at" NULLS FIRST, "uuid"`
+    offset = "0"
+  }
+
+  if nil != err {
+    return nil, err
+  }
+
+  query.WriteString(`
+     AND ` + condition + `
+  ORDER BY ` + order + `
+  LIMIT @rpp
+  OFFSET ` + offset + `;`)
+
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+
+  args = append(args, articleFilterArgs(filter, hidden, draftsOnly)...)
+  result, err := r.db.QueryContext(ctx, query.String(), args...)
+
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return nil, err
   }
 
@@ -755,17 +980,17 @@ func (r *archiveRepository) Get(ctx context.Context, filter *transfer.ArticleFil
   for result.Next() {
     var (
       article       transfer.Article
-      slug          string
       nullableTopic sql.NullString
     )
 
     err = result.Scan(
       &article.UUID,
       &article.Title,
-      &slug,
+      &article.Slug,
       &nullableTopic,
       &article.IsPinned,
       &article.PublishedAt,
+      &article.UpdatedAt,
     )
 
     topic := nullableTopic.String
@@ -785,7 +1010,7 @@ func (r *archiveRepository) Get(ctx context.Context, filter *transfer.ArticleFil
           URL: topicURL,
         }
       } else {
-        slog.Error(err.Error())
+        slog.ErrorContext(ctx, err.Error())
       }
     }
 
@@ -806,38 +1031,47 @@ func (r *archiveRepository) Get(ctx context.Context, filter *transfer.ArticleFil
         article.Topic.ID,
         strconv.Itoa(year),
         strconv.Itoa(month),
-        slug)
+        article.Slug)
 
       if nil == err {
         article.URL = u
       } else {
-        slog.Error(err.Error())
+        slog.ErrorContext(ctx, err.Error())
       }
     } else {
       article.URL = "about:blank"
     }
 
     if nil != err {
-      slog.Error(err.Error())
+      slog.ErrorContext(ctx, err.Error())
       return nil, err
     }
 
     articles = append(articles, &article)
   }
 
+  if nil == filter.After && nil != filter.Before {
+    slices.Reverse(articles)
+  }
+
   return articles, nil
 }
 
 func (r *archiveRepository) GetOne(ctx context.Context, request *transfer.ArticleRequest) (article *model.Article, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
   requestArticleUUIDQuery := `
   SELECT "uuid"
     FROM "article"
    WHERE "draft" IS FALSE
      AND "published_at" IS NOT NULL
      AND "hidden" IS FALSE
+     AND "deleted_at" IS NULL
      AND "topic" = @topic
-     AND cast(strftime('%Y', "published_at") AS INTEGER) = @year
-     AND cast(strftime('%m', "published_at") AS INTEGER) = @month
+     AND ` + r.db.Dialect().Year(`"published_at"`) + ` = @year
+     AND ` + r.db.Dialect().Month(`"published_at"`) + ` = @month
      AND "slug" = @slug;`
 
   var (
@@ -864,7 +1098,7 @@ func (r *archiveRepository) GetOne(ctx context.Context, request *transfer.Articl
 
   if nil != err {
     if !errors.Is(err, sql.ErrNoRows) {
-      slog.Error(err.Error())
+      slog.ErrorContext(ctx, err.Error())
     }
 
     return nil, err
@@ -880,21 +1114,33 @@ func (r *archiveRepository) GetOne(ctx context.Context, request *transfer.Articl
 }
 
 func (r *archiveRepository) GetByLink(ctx context.Context, link string) (article *model.Article, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
   getByLinkQuery := `
-  SELECT "article_uuid",
-         "expires_at"
-    FROM "article_link"
-   WHERE "sharable_link" = $1;`
+     SELECT l."article_uuid",
+            l."patch_uuid",
+            l."expires_at"
+       FROM "article_link" l
+       JOIN "article" a
+         ON a."uuid" = l."article_uuid"
+  LEFT JOIN "article_patch" p
+         ON p."uuid" = l."patch_uuid"
+      WHERE l."sharable_link" = $1
+        AND a."deleted_at" IS NULL
+        AND p."deleted_at" IS NULL;`
 
   var (
     id           string
+    patchID      sql.NullString
     expiresAtStr string
   )
 
   ctx1, cancel1 := context.WithTimeout(ctx, 5*time.Second)
   defer cancel1()
 
-  err = r.db.QueryRowContext(ctx1, getByLinkQuery, link).Scan(&id, &expiresAtStr)
+  err = r.db.QueryRowContext(ctx1, getByLinkQuery, link).Scan(&id, &patchID, &expiresAtStr)
 
   if nil != err {
     if errors.Is(err, sql.ErrNoRows) {
@@ -907,7 +1153,7 @@ func (r *archiveRepository) GetByLink(ctx context.Context, link string) (article
       p.With("shareable_link", link)
       err = p
     } else {
-      slog.Error(err.Error())
+      slog.ErrorContext(ctx, err.Error())
     }
 
     return nil, err
@@ -916,7 +1162,7 @@ func (r *archiveRepository) GetByLink(ctx context.Context, link string) (article
   expiresAt, err := time.Parse(time.RFC3339, expiresAtStr)
 
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     expiresAt = time.Time{}
   }
 
@@ -933,12 +1179,110 @@ func (r *archiveRepository) GetByLink(ctx context.Context, link string) (article
     return nil, p
   }
 
+  if patchID.Valid {
+    return r.getPatched(ctx, patchID.String)
+  }
+
   go r.incrementViews(ctx, id)
 
   return r.GetByID(ctx, id, true)
 }
 
+// getPatched retrieves the article that a patch points to as it would
+// look like if the patch were released right now.
+func (r *archiveRepository) getPatched(ctx context.Context, patchID string) (article *model.Article, err error) {
+  getPatchQuery := `
+     SELECT p."article_uuid",
+            p."title",
+            p."slug",
+            p."read_time",
+            p."content",
+            t."id",
+            t."name",
+            t."created_at",
+            t."updated_at"
+       FROM "article_patch" p
+  LEFT JOIN "topic" t
+         ON t."id" = p."topic"
+      WHERE p."uuid" = $1
+        AND p."deleted_at" IS NULL;`
+
+  var (
+    articleID string
+    patch     model.ArticlePatch
+    topic     struct {
+      ID        sql.NullString
+      Name      sql.NullString
+      CreatedAt sql.Null[time.Time]
+      UpdatedAt sql.Null[time.Time]
+    }
+  )
+
+  ctx1, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+
+  err = r.db.QueryRowContext(ctx1, getPatchQuery, patchID).
+    Scan(&articleID,
+      &patch.Title,
+      &patch.Slug,
+      &patch.ReadTime,
+      &patch.Content,
+      &topic.ID,
+      &topic.Name,
+      &topic.CreatedAt,
+      &topic.UpdatedAt,
+    )
+
+  if nil != err {
+    if errors.Is(err, sql.ErrNoRows) {
+      return nil, problem.NewNotFound(patchID, "article patch")
+    }
+
+    slog.ErrorContext(ctx, err.Error())
+    return nil, err
+  }
+
+  article, err = r.GetByID(ctx, articleID, false)
+  if nil != err {
+    return nil, err
+  }
+
+  article.PatchUUID = new(uuid.UUID)
+  *article.PatchUUID, _ = uuid.Parse(patchID)
+
+  if nil != patch.Title {
+    article.Title = *patch.Title
+  }
+
+  if nil != patch.Slug {
+    article.Slug = *patch.Slug
+  }
+
+  if nil != patch.ReadTime && 0 != *patch.ReadTime {
+    article.ReadTime = *patch.ReadTime
+  }
+
+  if nil != patch.Content {
+    article.Content = *patch.Content
+  }
+
+  if topic.ID.Valid {
+    article.Topic = &model.Topic{
+      ID:        topic.ID.String,
+      Name:      topic.Name.String,
+      CreatedAt: topic.CreatedAt.V,
+      UpdatedAt: topic.UpdatedAt.V,
+    }
+  }
+
+  return article, nil
+}
+
 func (r *archiveRepository) GetByID(ctx context.Context, id string, isDraft bool) (article *model.Article, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
   getTagsQuery := `
      SELECT t."id",
             t."name",
@@ -947,14 +1291,15 @@ func (r *archiveRepository) GetByID(ctx context.Context, id string, isDraft bool
        FROM "article_tag" at
   LEFT JOIN "tag" t
          ON at."tag_id" = t."id"
-      WHERE "article_uuid" = @article_uuid;`
+      WHERE "article_uuid" = @article_uuid
+        AND t."deleted_at" IS NULL;`
 
   ctx1, cancel1 := context.WithTimeout(ctx, 10*time.Second)
   defer cancel1()
 
   result, err := r.db.QueryContext(ctx1, getTagsQuery, sql.Named("article_uuid", id))
   if err != nil {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return nil, err
   }
 
@@ -973,7 +1318,7 @@ func (r *archiveRepository) GetByID(ctx context.Context, id string, isDraft bool
     )
 
     if nil != err {
-      slog.Error(err.Error())
+      slog.ErrorContext(ctx, err.Error())
       return nil, err
     }
 
@@ -986,6 +1331,8 @@ func (r *archiveRepository) GetByID(ctx context.Context, id string, isDraft bool
             a."author",
             a."slug",
             a."read_time",
+            a."revision",
+            a."version",
             a."views",
             a."content",
             a."draft",
@@ -1002,7 +1349,8 @@ func (r *archiveRepository) GetByID(ctx context.Context, id string, isDraft bool
   LEFT JOIN "topic" t
          ON t."id" = a."topic" 
       WHERE "uuid" = @uuid
-        AND "draft" IS @is_draft
+        AND a."deleted_at" IS NULL
+        AND "draft" IS NOT DISTINCT FROM @is_draft
         AND CASE WHEN @is_draft
                  THEN "published_at" IS NULL
                  ELSE "published_at" IS NOT NULL
@@ -1033,6 +1381,8 @@ func (r *archiveRepository) GetByID(ctx context.Context, id string, isDraft bool
     &article.Author,
     &article.Slug,
     &article.ReadTime,
+    &article.Revision,
+    &article.Version,
     &article.Views,
     &article.Content,
     &article.IsDraft,
@@ -1057,7 +1407,7 @@ func (r *archiveRepository) GetByID(ctx context.Context, id string, isDraft bool
     value, err := nullableTopicCreatedAt.Value()
 
     if nil != err {
-      slog.Error(err.Error())
+      slog.ErrorContext(ctx, err.Error())
     } else {
       article.Topic.CreatedAt = value.(time.Time)
     }
@@ -1065,7 +1415,7 @@ func (r *archiveRepository) GetByID(ctx context.Context, id string, isDraft bool
     value, err = nullableTopicUpdatedAt.Value()
 
     if nil != err {
-      slog.Error(err.Error())
+      slog.ErrorContext(ctx, err.Error())
     } else {
       article.Topic.UpdatedAt = value.(time.Time)
     }
@@ -1081,7 +1431,7 @@ func (r *archiveRepository) GetByID(ctx context.Context, id string, isDraft bool
 
       err = problem.NewNotFound(id, recordType)
     } else {
-      slog.Error(err.Error())
+      slog.ErrorContext(ctx, err.Error())
     }
 
     return nil, err
@@ -1090,108 +1440,111 @@ func (r *archiveRepository) GetByID(ctx context.Context, id string, isDraft bool
   return article, nil
 }
 
-func (r *archiveRepository) Amend(ctx context.Context, id string) error {
+func (r *archiveRepository) Amend(ctx context.Context, id string) (patchID string, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
+  err = r.db.Transact(ctx, func(ctx context.Context) (err error) {
+    patchID, err = r.amend(ctx, id)
+    return err
+  })
+
+  if nil != err {
+    return "", err
+  }
+
+  return patchID, nil
+}
+
+// amend checks that the article id is published and creates a patch
+// of it.
+func (r *archiveRepository) amend(ctx context.Context, id string) (patchID string, err error) {
   articleExistsQuery := `
   SELECT "uuid"
     FROM "article"
    WHERE "uuid" = @uuid
      AND "draft" IS FALSE
-     AND "published_at" IS NOT NULL;`
+     AND "published_at" IS NOT NULL
+     AND "deleted_at" IS NULL;`
 
   ctx1, cancel := context.WithTimeout(ctx, 3*time.Second)
   defer cancel()
 
-  err := r.db.QueryRowContext(ctx1, articleExistsQuery, sql.Named("uuid", id)).Scan(&id)
+  err = r.db.QueryRowContext(ctx1, articleExistsQuery, sql.Named("uuid", id)).Scan(&id)
   if nil != err {
     if errors.Is(err, sql.ErrNoRows) {
-      return problem.NewNotFound(id, "article")
+      return "", problem.NewNotFound(id, "article")
     }
 
-    slog.Error(err.Error())
-    return err
-  }
-
-  isBeenAmendedQuery := `
-    SELECT count (1)
-      FROM "article_patch"
-     WHERE "article_uuid" = @article_uuid;`
-
-  var isBeenAmended bool
-
-  ctx2, cancel := context.WithTimeout(ctx, 2*time.Second)
-  defer cancel()
-
-  err = r.db.QueryRowContext(ctx2, isBeenAmendedQuery, sql.Named("article_uuid", id)).Scan(&isBeenAmended)
-  if nil != err {
-    slog.Error(err.Error())
-  }
-
-  if isBeenAmended {
-    p := problem.Problem{}
-    p.Title("Article is currently been amended.")
-    p.Detail("Could not amend article because there is an ongoing update.")
-    p.Status(http.StatusConflict)
-    p.With("article_uuid", id)
-
-    return &p
+    slog.ErrorContext(ctx, err.Error())
+    return "", err
   }
 
   tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
   if nil != err {
-    slog.Error(err.Error())
-    return err
+    slog.ErrorContext(ctx, err.Error())
+    return "", err
   }
 
   defer tx.Rollback()
 
   amendArticleQuery := `
   INSERT INTO "article_patch" ("article_uuid",
-                               "title",
-                               "slug",
-                               "content")
-                       VALUES (@uuid,
-                               NULL,
-                               NULL,
-                               NULL);`
+                               "base_revision",
+                               "base_title",
+                               "base_slug",
+                               "base_topic",
+                               "base_content")
+       SELECT "uuid",
+              "revision",
+              "title",
+              "slug",
+              "topic",
+              "content"
+         FROM "article"
+        WHERE "uuid" = @uuid
+    RETURNING "uuid";`
 
   ctx, cancel = context.WithTimeout(ctx, 4*time.Second)
   defer cancel()
 
-  _, err = tx.ExecContext(ctx, amendArticleQuery, sql.Named("uuid", id))
+  err = tx.QueryRowContext(ctx, amendArticleQuery, sql.Named("uuid", id)).Scan(&patchID)
   if nil != err {
-    slog.Error(err.Error())
-    return err
+    slog.ErrorContext(ctx, err.Error())
+    return "", err
   }
 
   if err = tx.Commit(); nil != err {
-    slog.Error(err.Error())
-    return err
+    slog.ErrorContext(ctx, err.Error())
+    return "", err
   }
 
-  return nil
+  return patchID, nil
 }
 
 func (r *archiveRepository) Remove(ctx context.Context, id string) error {
-  tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
-  if nil != err {
-    slog.Error(err.Error())
-    return err
-  }
-
-  defer tx.Rollback()
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
 
+  // The patches, tags and shareable links of the article are kept for
+  // it to have them back if it is restored, and removed when it is
+  // purged.
   removeArticleQuery := `
-  DELETE FROM "article"
-        WHERE "uuid" = @uuid
-          AND "draft" IS FALSE
-          AND "published_at" IS NOT NULL;`
+  UPDATE "article"
+     SET "deleted_at" = current_timestamp
+   WHERE "uuid" = @uuid
+     AND "draft" IS FALSE
+     AND "published_at" IS NOT NULL
+     AND "deleted_at" IS NULL;`
 
   ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
   defer cancel()
 
-  result, err := tx.ExecContext(ctx, removeArticleQuery, sql.Named("uuid", id))
+  result, err := r.db.ExecContext(ctx, removeArticleQuery, sql.Named("uuid", id))
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return err
   }
 
@@ -1199,17 +1552,24 @@ func (r *archiveRepository) Remove(ctx context.Context, id string) error {
     return problem.NewNotFound(id, "article")
   }
 
-  if err = tx.Commit(); nil != err {
-    slog.Error(err.Error())
-    return err
-  }
-
-  r.setPublicationsCache(ctx)
+  r.db.OnCommit(ctx, r.setPublicationsCache)
 
   return nil
 }
 
 func (r *archiveRepository) AddTag(ctx context.Context, articleID, tagID string, isDraft ...bool) error {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
+  return r.db.Transact(ctx, func(ctx context.Context) error {
+    return r.addTag(ctx, articleID, tagID, isDraft...)
+  })
+}
+
+// addTag checks that both the article and the tag exist before it
+// attaches the tag to the article.
+func (r *archiveRepository) addTag(ctx context.Context, articleID, tagID string, isDraft ...bool) error {
   var isArticleDraft bool
 
   if 0 < len(isDraft) {
@@ -1220,7 +1580,8 @@ func (r *archiveRepository) AddTag(ctx context.Context, articleID, tagID string,
   SELECT count (*)
     FROM "article"
    WHERE "uuid" = @uuid
-     AND "draft" IS @is_draft
+     AND "deleted_at" IS NULL
+     AND "draft" IS NOT DISTINCT FROM @is_draft
      AND CASE WHEN @is_draft
            THEN "published_at" IS NULL
            ELSE "published_at" IS NOT NULL
@@ -1238,7 +1599,7 @@ func (r *archiveRepository) AddTag(ctx context.Context, articleID, tagID string,
 
   if nil != err {
     if !errors.Is(err, sql.ErrNoRows) {
-      slog.Error(err.Error())
+      slog.ErrorContext(ctx, err.Error())
       return err
     }
   }
@@ -1254,7 +1615,8 @@ func (r *archiveRepository) AddTag(ctx context.Context, articleID, tagID string,
   tagExistsQuery := `
   SELECT count (*)
     FROM "tag"
-   WHERE "id" = $1;`
+   WHERE "id" = $1
+     AND "deleted_at" IS NULL;`
 
   ctx1, cancel = context.WithTimeout(ctx, 3*time.Second)
   defer cancel()
@@ -1264,7 +1626,7 @@ func (r *archiveRepository) AddTag(ctx context.Context, articleID, tagID string,
   err = r.db.QueryRowContext(ctx, tagExistsQuery, tagID).Scan(&tagExists)
   if nil != err {
     if !errors.Is(err, sql.ErrNoRows) {
-      slog.Error(err.Error())
+      slog.ErrorContext(ctx, err.Error())
       return err
     }
   }
@@ -1291,7 +1653,7 @@ func (r *archiveRepository) AddTag(ctx context.Context, articleID, tagID string,
 
   if nil != err {
     if !errors.Is(err, sql.ErrNoRows) {
-      slog.Error(err.Error())
+      slog.ErrorContext(ctx, err.Error())
       return err
     }
   }
@@ -1316,7 +1678,7 @@ func (r *archiveRepository) AddTag(ctx context.Context, articleID, tagID string,
 
   tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return err
   }
 
@@ -1334,7 +1696,7 @@ func (r *archiveRepository) AddTag(ctx context.Context, articleID, tagID string,
     sql.Named("tag_id", tagID))
 
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return err
   }
 
@@ -1357,7 +1719,7 @@ func (r *archiveRepository) AddTag(ctx context.Context, articleID, tagID string,
   }
 
   if err = tx.Commit(); nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return err
   }
 
@@ -1365,6 +1727,18 @@ func (r *archiveRepository) AddTag(ctx context.Context, articleID, tagID string,
 }
 
 func (r *archiveRepository) RemoveTag(ctx context.Context, articleID, tagID string, isDraft ...bool) error {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
+  return r.db.Transact(ctx, func(ctx context.Context) error {
+    return r.removeTag(ctx, articleID, tagID, isDraft...)
+  })
+}
+
+// removeTag checks that both the article and the tag exist before it
+// detaches the tag from the article.
+func (r *archiveRepository) removeTag(ctx context.Context, articleID, tagID string, isDraft ...bool) error {
   var isArticleDraft bool
 
   if 0 < len(isDraft) {
@@ -1375,7 +1749,8 @@ func (r *archiveRepository) RemoveTag(ctx context.Context, articleID, tagID stri
   SELECT count (*)
     FROM "article"
    WHERE "uuid" = @uuid
-     AND "draft" IS @is_draft
+     AND "deleted_at" IS NULL
+     AND "draft" IS NOT DISTINCT FROM @is_draft
      AND CASE WHEN @is_draft
            THEN "published_at" IS NULL
            ELSE "published_at" IS NOT NULL
@@ -1393,7 +1768,7 @@ func (r *archiveRepository) RemoveTag(ctx context.Context, articleID, tagID stri
 
   if nil != err {
     if !errors.Is(err, sql.ErrNoRows) {
-      slog.Error(err.Error())
+      slog.ErrorContext(ctx, err.Error())
       return err
     }
   }
@@ -1409,7 +1784,8 @@ func (r *archiveRepository) RemoveTag(ctx context.Context, articleID, tagID stri
   tagExistsQuery := `
   SELECT count (*)
     FROM "tag"
-   WHERE "id" = $1;`
+   WHERE "id" = $1
+     AND "deleted_at" IS NULL;`
 
   ctx1, cancel = context.WithTimeout(ctx, 3*time.Second)
   defer cancel()
@@ -1419,7 +1795,7 @@ func (r *archiveRepository) RemoveTag(ctx context.Context, articleID, tagID stri
   err = r.db.QueryRowContext(ctx, tagExistsQuery, tagID).Scan(&tagExists)
   if nil != err {
     if !errors.Is(err, sql.ErrNoRows) {
-      slog.Error(err.Error())
+      slog.ErrorContext(ctx, err.Error())
       return err
     }
   }
@@ -1430,7 +1806,7 @@ func (r *archiveRepository) RemoveTag(ctx context.Context, articleID, tagID stri
 
   tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return err
   }
 
@@ -1449,7 +1825,7 @@ func (r *archiveRepository) RemoveTag(ctx context.Context, articleID, tagID stri
     sql.Named("tag_id", tagID))
 
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return err
   }
 
@@ -1465,7 +1841,7 @@ func (r *archiveRepository) RemoveTag(ctx context.Context, articleID, tagID stri
   }
 
   if err = tx.Commit(); nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return err
   }
 
@@ -1473,9 +1849,13 @@ func (r *archiveRepository) RemoveTag(ctx context.Context, articleID, tagID stri
 }
 
 func (r *archiveRepository) SetHidden(ctx context.Context, id string, hidden bool) error {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
   tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return err
   }
 
@@ -1486,14 +1866,15 @@ func (r *archiveRepository) SetHidden(ctx context.Context, id string, hidden boo
      SET "hidden" = @hidden
    WHERE "uuid" = @uuid
      AND "draft" IS FALSE
-     AND "published_at" IS NOT NULL;`
+     AND "published_at" IS NOT NULL
+     AND "deleted_at" IS NULL;`
 
   ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
   defer cancel()
 
   result, err := tx.ExecContext(ctx, setHiddenQuery, sql.Named("uuid", id), sql.Named("hidden", hidden))
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return err
   }
 
@@ -1503,19 +1884,23 @@ func (r *archiveRepository) SetHidden(ctx context.Context, id string, hidden boo
   }
 
   if err = tx.Commit(); nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return err
   }
 
-  r.setPublicationsCache(ctx)
+  r.db.OnCommit(ctx, r.setPublicationsCache)
 
   return nil
 }
 
 func (r *archiveRepository) SetPinned(ctx context.Context, id string, pinned bool) error {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
   tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return err
   }
 
@@ -1526,14 +1911,15 @@ func (r *archiveRepository) SetPinned(ctx context.Context, id string, pinned boo
      SET "pinned" = @pinned
    WHERE "uuid" = @uuid
      AND "draft" IS FALSE
-     AND "published_at" IS NOT NULL;`
+     AND "published_at" IS NOT NULL
+     AND "deleted_at" IS NULL;`
 
   ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
   defer cancel()
 
   result, err := tx.ExecContext(ctx, setPinnedQuery, sql.Named("uuid", id), sql.Named("pinned", pinned))
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return err
   }
 
@@ -1543,7 +1929,7 @@ func (r *archiveRepository) SetPinned(ctx context.Context, id string, pinned boo
   }
 
   if err = tx.Commit(); nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return err
   }
 
@@ -1551,37 +1937,63 @@ func (r *archiveRepository) SetPinned(ctx context.Context, id string, pinned boo
 }
 
 func (r *archiveRepository) Share(ctx context.Context, id string) (link string, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
   defer func() {
     if nil == err {
       r.mu.Lock()
-      r.cleanOnce = sync.Once{}
+      r.cleanOnce = new(sync.Once)
       r.mu.Unlock()
     }
   }()
 
-  assertIsArticlePatchQuery := `
-  SELECT count(*)
+  err = r.db.Transact(ctx, func(ctx context.Context) (err error) {
+    link, err = r.share(ctx, id)
+    return err
+  })
+
+  if nil != err {
+    return "", err
+  }
+
+  return link, nil
+}
+
+// share returns the current link of the draft or patch id, making a
+// new one when there is none or it has expired.
+func (r *archiveRepository) share(ctx context.Context, id string) (link string, err error) {
+  getPatchArticleQuery := `
+  SELECT "article_uuid"
     FROM "article_patch"
-   WHERE "article_uuid" = @article_uuid;`
+   WHERE "uuid" = @uuid
+     AND "deleted_at" IS NULL;`
 
-  var isArticlePatch bool
+  var (
+    articleID = id
+    patchID   sql.NullString
+  )
 
   ctx1, cancel := context.WithTimeout(ctx, 2*time.Second)
   defer cancel()
 
-  err = r.db.QueryRowContext(ctx1, assertIsArticlePatchQuery, sql.Named("article_uuid", id)).Scan(&isArticlePatch)
-  if nil != err {
-    slog.Error(err.Error())
+  err = r.db.QueryRowContext(ctx1, getPatchArticleQuery, sql.Named("uuid", id)).Scan(&articleID)
+  if nil != err && !errors.Is(err, sql.ErrNoRows) {
+    slog.ErrorContext(ctx, err.Error())
     return "", err
   }
 
-  if !isArticlePatch {
+  if nil == err {
+    patchID = sql.NullString{String: id, Valid: true}
+  } else {
     assertIsArticleDraftQuery := `
     SELECT count (*)
       FROM "article"
      WHERE "uuid" = $1
        AND "draft" IS TRUE
-       AND "published_at" IS NULL;`
+       AND "published_at" IS NULL
+       AND "deleted_at" IS NULL;`
 
     ctx1, cancel = context.WithTimeout(ctx, 2*time.Second)
     defer cancel()
@@ -1590,7 +2002,7 @@ func (r *archiveRepository) Share(ctx context.Context, id string) (link string,
 
     err = r.db.QueryRowContext(ctx1, assertIsArticleDraftQuery, id).Scan(&isDraft)
     if nil != err {
-      slog.Error(err.Error())
+      slog.ErrorContext(ctx, err.Error())
       return "", err
     }
 
@@ -1603,17 +2015,22 @@ func (r *archiveRepository) Share(ctx context.Context, id string) (link string,
   SELECT "sharable_link",
          "expires_at"
     FROM "article_link"
-   WHERE "article_uuid" = $1;`
+   WHERE "article_uuid" = @article_uuid
+     AND "patch_uuid" IS NOT DISTINCT FROM @patch_uuid;`
 
   ctx, cancel = context.WithTimeout(ctx, 2*time.Second)
   defer cancel()
 
   var expiresAt time.Time
 
-  err = r.db.QueryRowContext(ctx, tryToGetCurrentLinkWithExpirationTimeQuery, id).Scan(&link, &expiresAt)
+  err = r.db.QueryRowContext(ctx, tryToGetCurrentLinkWithExpirationTimeQuery,
+    sql.Named("article_uuid", articleID),
+    sql.Named("patch_uuid", patchID)).
+    Scan(&link, &expiresAt)
+
   if nil != err {
     if !errors.Is(err, sql.ErrNoRows) {
-      slog.Error(err.Error())
+      slog.ErrorContext(ctx, err.Error())
       return "", err
     }
   }
@@ -1623,20 +2040,25 @@ func (r *archiveRepository) Share(ctx context.Context, id string) (link string,
     if -1 == expiresAt.Compare(now) || 0 == expiresAt.Compare(now) { // link has expired
       removeObsoleteLinkQuery := `
       DELETE FROM "article_link"
-            WHERE "article_uuid" = $1;`
+            WHERE "article_uuid" = @article_uuid
+              AND "patch_uuid" IS NOT DISTINCT FROM @patch_uuid;`
 
       ctx, cancel = context.WithTimeout(ctx, 2*time.Second)
       defer cancel()
 
-      _, err = r.db.ExecContext(ctx, removeObsoleteLinkQuery, id)
+      _, err = r.db.ExecContext(ctx, removeObsoleteLinkQuery,
+        sql.Named("article_uuid", articleID),
+        sql.Named("patch_uuid", patchID))
+
       if nil != err {
-        slog.Error(err.Error())
+        slog.ErrorContext(ctx, err.Error())
         return "", err
       }
 
-      slog.Info("shareable link expired, generating a new one",
+      slog.InfoContext(ctx, "shareable link expired, generating a new one",
         slog.String("elapsed", now.Sub(expiresAt).String()),
-        slog.String("article_uuid", id))
+        slog.String("article_uuid", articleID),
+        slog.String("patch_uuid", patchID.String))
     } else {
       return link, nil
     }
@@ -1644,15 +2066,15 @@ func (r *archiveRepository) Share(ctx context.Context, id string) (link string,
 
   tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return "", err
   }
 
   defer tx.Rollback()
 
   makeShareableLinkQuery := `
-  INSERT INTO "article_link" ("article_uuid", "sharable_link")
-                      VALUES (@article_uuid, @sharable_link)
+  INSERT INTO "article_link" ("article_uuid", "patch_uuid", "sharable_link", "expires_at")
+                      VALUES (@article_uuid, @patch_uuid, @sharable_link, ` + r.db.Dialect().After("@expiry") + `)
     RETURNING "sharable_link";`
 
   data := fmt.Sprintf("%s at %s", id, time.Now().String())
@@ -1662,17 +2084,19 @@ func (r *archiveRepository) Share(ctx context.Context, id string) (link string,
   defer cancel2()
 
   err = tx.QueryRowContext(ctx2, makeShareableLinkQuery,
-    sql.Named("article_uuid", id),
-    sql.Named("sharable_link", fmt.Sprintf("/archive/sharing/%x", hash))).
+    sql.Named("article_uuid", articleID),
+    sql.Named("patch_uuid", patchID),
+    sql.Named("sharable_link", fmt.Sprintf("/archive/sharing/%x", hash)),
+    sql.Named("expiry", int64(r.shareExpiry.Seconds()))).
     Scan(&link)
 
   if nil != err {
-    slog.Error(err.Error())
-    return "", nil
+    slog.ErrorContext(ctx, err.Error())
+    return "", err
   }
 
   if err = tx.Commit(); nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return "", err
   }
 
@@ -1680,50 +2104,59 @@ func (r *archiveRepository) Share(ctx context.Context, id string) (link string,
 }
 
 func (r *archiveRepository) Discard(ctx context.Context, id string) error {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
+  return r.db.Transact(ctx, func(ctx context.Context) error {
+    return r.discard(ctx, id)
+  })
+}
+
+// discard finds whether id is a draft or a patch and drops it.
+func (r *archiveRepository) discard(ctx context.Context, id string) error {
   isArticlePatchQuery := `
   SELECT count(*)
     FROM "article_patch"
-   WHERE "article_uuid" = @article_uuid;`
+   WHERE "uuid" = @uuid
+     AND "deleted_at" IS NULL;`
 
   var isArticlePatch bool
 
   ctx1, cancel := context.WithTimeout(ctx, 2*time.Second)
   defer cancel()
 
-  err := r.db.QueryRowContext(ctx1, isArticlePatchQuery, sql.Named("article_uuid", id)).Scan(&isArticlePatch)
-  if nil != err {
-    slog.Error(err.Error())
-    return err
-  }
-
-  tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+  err := r.db.QueryRowContext(ctx1, isArticlePatchQuery, sql.Named("uuid", id)).Scan(&isArticlePatch)
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return err
   }
 
-  defer tx.Rollback()
-
+  // The shareable links and tags of the draft or the patch are kept
+  // for it to have them back if it is restored, and removed when it
+  // is purged.
   discardPatchOrDraftQuery := `
-  DELETE
-    FROM "article"
+  UPDATE "article"
+     SET "deleted_at" = current_timestamp
    WHERE "uuid" = @uuid
      AND "draft" IS TRUE
-     AND "published_at" IS NULL;`
+     AND "published_at" IS NULL
+     AND "deleted_at" IS NULL;`
 
   if isArticlePatch {
     discardPatchOrDraftQuery = `
-    DELETE
-      FROM "article_patch"
-     WHERE "article_uuid" = @uuid;`
+    UPDATE "article_patch"
+       SET "deleted_at" = current_timestamp
+     WHERE "uuid" = @uuid
+       AND "deleted_at" IS NULL;`
   }
 
   ctx, cancel = context.WithTimeout(ctx, 2*time.Second)
   defer cancel()
 
-  result, err := tx.ExecContext(ctx, discardPatchOrDraftQuery, sql.Named("uuid", id))
+  result, err := r.db.ExecContext(ctx, discardPatchOrDraftQuery, sql.Named("uuid", id))
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return err
   }
 
@@ -1736,37 +2169,90 @@ func (r *archiveRepository) Discard(ctx context.Context, id string) error {
     return problem.NewNotFound(id, "draft")
   }
 
-  if err = tx.Commit(); nil != err {
-    slog.Error(err.Error())
-    return err
+  return nil
+}
+
+func (r *archiveRepository) Restore(ctx context.Context, id string) error {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
+  // Patches and articles have UUIDs of their own, so at most one of
+  // these queries restores a record.
+  restoreQueries := []string{`
+  UPDATE "article_patch"
+     SET "deleted_at" = NULL
+   WHERE "uuid" = @uuid
+     AND "deleted_at" IS NOT NULL;`, `
+  UPDATE "article"
+     SET "deleted_at" = NULL
+   WHERE "uuid" = @uuid
+     AND "deleted_at" IS NOT NULL;`,
   }
 
-  return nil
+  ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+  defer cancel()
+
+  for _, query := range restoreQueries {
+    result, err := r.db.ExecContext(ctx, query, sql.Named("uuid", id))
+    if nil != err {
+      slog.ErrorContext(ctx, err.Error())
+      return err
+    }
+
+    if affected, _ := result.RowsAffected(); 1 == affected {
+      r.db.OnCommit(ctx, r.setPublicationsCache)
+      return nil
+    }
+  }
+
+  return problem.NewNotFound(id, "article")
 }
 
 func (r *archiveRepository) Revise(ctx context.Context, id string, revision *transfer.ArticleRevision) error {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
+  return r.db.Transact(ctx, func(ctx context.Context) error {
+    return r.revise(ctx, id, revision)
+  })
+}
+
+// revise finds whether id is a draft or a patch, checks the topic of
+// revision and applies it.
+func (r *archiveRepository) revise(ctx context.Context, id string, revision *transfer.ArticleRevision) error {
   isArticlePatchQuery := `
   SELECT count(*)
     FROM "article_patch"
-   WHERE "article_uuid" = @article_uuid;`
+   WHERE "uuid" = @uuid
+     AND "deleted_at" IS NULL;`
 
   var isArticlePatch bool
 
   ctx1, cancel := context.WithTimeout(ctx, 2*time.Second)
   defer cancel()
 
-  err := r.db.QueryRowContext(ctx1, isArticlePatchQuery, sql.Named("article_uuid", id)).Scan(&isArticlePatch)
+  err := r.db.QueryRowContext(ctx1, isArticlePatchQuery, sql.Named("uuid", id)).Scan(&isArticlePatch)
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return err
   }
 
+  if 0 == revision.Version {
+    if isArticlePatch {
+      return problem.NewPreconditionRequired("article patch")
+    }
+    return problem.NewPreconditionRequired("draft")
+  }
+
   if "" != revision.Topic {
     exists := false
     topicExistsQuery := `
     SELECT count (1)
       FROM "topic"
-     WHERE "id" = $1;`
+     WHERE "id" = $1
+       AND "deleted_at" IS NULL;`
 
     ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
     defer cancel()
@@ -1774,7 +2260,7 @@ func (r *archiveRepository) Revise(ctx context.Context, id string, revision *tra
     err := r.db.QueryRowContext(ctx, topicExistsQuery, revision.Topic).Scan(&exists)
 
     if nil != err {
-      slog.Error(err.Error())
+      slog.ErrorContext(ctx, err.Error())
       return err
     }
 
@@ -1785,7 +2271,7 @@ func (r *archiveRepository) Revise(ctx context.Context, id string, revision *tra
 
   tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return err
   }
 
@@ -1797,15 +2283,18 @@ func (r *archiveRepository) Revise(ctx context.Context, id string, revision *tra
          "slug" = coalesce (nullif (@slug, ''), "slug"),
          "topic" = coalesce (nullif (@topic, ''), "topic"),
          "read_time" = CASE WHEN @read_time = "read_time"
-                              OR @read_time IS NULL
+                              OR CAST(@read_time AS INTEGER) IS NULL
                               OR @read_time = 0
                             THEN "read_time"
                             ELSE @read_time
                              END,
-         "content" = coalesce (nullif (@content, ''), "content")
+         "content" = coalesce (nullif (@content, ''), "content"),
+         "version" = "version" + 1
    WHERE "uuid" = @uuid
+     AND "version" = @version
      AND "draft" IS TRUE
-     AND "published_at" IS NULL;`
+     AND "published_at" IS NULL
+     AND "deleted_at" IS NULL;`
 
   if isArticlePatch {
     reviseArticleQuery = `
@@ -1814,13 +2303,16 @@ func (r *archiveRepository) Revise(ctx context.Context, id string, revision *tra
            "slug" = coalesce (nullif (@slug, ''), "slug"),
            "topic" = coalesce (nullif (@topic, ''), "topic"),
            "read_time" = CASE WHEN @read_time = "read_time"
-                                OR @read_time IS NULL
+                                OR CAST(@read_time AS INTEGER) IS NULL
                                 OR @read_time = 0
                               THEN "read_time"
                               ELSE @read_time
                                END,
-           "content" = coalesce (nullif (@content, ''), "content")
-     WHERE "article_uuid" = @uuid;`
+           "content" = coalesce (nullif (@content, ''), "content"),
+           "version" = "version" + 1
+     WHERE "uuid" = @uuid
+       AND "version" = @version
+       AND "deleted_at" IS NULL;`
   }
 
   ctx, cancel = context.WithTimeout(ctx, 3*time.Second)
@@ -1833,24 +2325,51 @@ func (r *archiveRepository) Revise(ctx context.Context, id string, revision *tra
     sql.Named("topic", revision.Topic),
     sql.Named("read_time", revision.ReadTime),
     sql.Named("content", revision.Content),
+    sql.Named("version", revision.Version),
   )
 
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return err
   }
 
   affected, _ := result.RowsAffected()
   if 1 != affected {
+    recordType := "draft"
+    currentVersionQuery := `
+    SELECT "version"
+      FROM "article"
+     WHERE "uuid" = @uuid
+       AND "draft" IS TRUE
+       AND "published_at" IS NULL
+       AND "deleted_at" IS NULL;`
+
     if isArticlePatch {
-      return problem.NewNotFound(id, "article patch")
+      recordType = "article patch"
+      currentVersionQuery = `
+      SELECT "version"
+        FROM "article_patch"
+       WHERE "uuid" = @uuid
+         AND "deleted_at" IS NULL;`
     }
 
-    return problem.NewNotFound(id, "draft")
+    var currentVersion int
+
+    err = tx.QueryRowContext(ctx, currentVersionQuery, sql.Named("uuid", id)).Scan(&currentVersion)
+    if nil != err {
+      if errors.Is(err, sql.ErrNoRows) {
+        return problem.NewNotFound(id, recordType)
+      }
+
+      slog.ErrorContext(ctx, err.Error())
+      return err
+    }
+
+    return problem.NewVersionConflict(id, recordType, currentVersion)
   }
 
   if err = tx.Commit(); nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return err
   }
 
@@ -1858,23 +2377,48 @@ func (r *archiveRepository) Revise(ctx context.Context, id string, revision *tra
 }
 
 func (r *archiveRepository) Release(ctx context.Context, id string) error {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
+  tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+  if nil != err {
+    slog.ErrorContext(ctx, err.Error())
+    return err
+  }
+
+  defer tx.Rollback()
+
   getPatchQuery := `
-  SELECT "article_uuid",
+  SELECT "uuid",
+         "article_uuid",
+         "base_revision",
+         "base_title",
+         "base_slug",
+         coalesce ("base_topic", ''),
+         "base_content",
          "title",
          "slug",
          "topic",
          "read_time",
          "content"
     FROM "article_patch"
-   WHERE "article_uuid" = $1;`
+   WHERE "uuid" = $1
+     AND "deleted_at" IS NULL;`
 
   ctx1, cancel := context.WithTimeout(ctx, 3*time.Second)
   defer cancel()
 
-  var patch model.ArticlePatch
+  patch := model.ArticlePatch{Base: new(model.ArticleSnapshot)}
 
-  err := r.db.QueryRowContext(ctx1, getPatchQuery, id).
-    Scan(&patch.ArticleUUID,
+  err = tx.QueryRowContext(ctx1, getPatchQuery, id).
+    Scan(&patch.UUID,
+      &patch.ArticleUUID,
+      &patch.BaseRevision,
+      &patch.Base.Title,
+      &patch.Base.Slug,
+      &patch.Base.TopicID,
+      &patch.Base.Content,
       &patch.Title,
       &patch.Slug,
       &patch.TopicID,
@@ -1887,28 +2431,77 @@ func (r *archiveRepository) Release(ctx context.Context, id string) error {
       return problem.NewNotFound(id, "article patch")
     }
 
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return err
   }
 
-  tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+  getArticleQuery := `
+  SELECT "revision",
+         "title",
+         "slug",
+         coalesce ("topic", ''),
+         "content"
+    FROM "article"
+   WHERE "uuid" = $1
+     AND "draft" IS FALSE
+     AND "published_at" IS NOT NULL
+     AND "deleted_at" IS NULL;`
+
+  var (
+    revision int
+    current  model.ArticleSnapshot
+  )
+
+  ctx1, cancel = context.WithTimeout(ctx, 3*time.Second)
+  defer cancel()
+
+  err = tx.QueryRowContext(ctx1, getArticleQuery, patch.ArticleUUID.String()).
+    Scan(&revision,
+      &current.Title,
+      &current.Slug,
+      &current.TopicID,
+      &current.Content,
+    )
+
   if nil != err {
-    slog.Error(err.Error())
+    if errors.Is(err, sql.ErrNoRows) {
+      return problem.NewNotFound(patch.ArticleUUID.String(), "article")
+    }
+
+    slog.ErrorContext(ctx, err.Error())
     return err
   }
 
+  if patch.BaseRevision != revision {
+    if conflicts := MergePatch(&patch, &current); 0 < len(conflicts) {
+      p := problem.Problem{}
+      p.Status(http.StatusConflict)
+      p.Title("Could not release article patch.")
+      p.Detail("The article was updated after this patch was created and both changed the same parts of it. Revise the patch to agree with the current article and try again.")
+      p.With("patch_uuid", id)
+      p.With("article_uuid", patch.ArticleUUID.String())
+      p.With("base_revision", patch.BaseRevision)
+      p.With("current_revision", revision)
+      p.With("conflicts", conflicts)
+
+      return &p
+    }
+  }
+
   releasePatchQuery := `
   UPDATE "article"
      SET "title" = coalesce(nullif(@title, ''), "title"),
          "slug" = coalesce(nullif(@slug, ''), "slug"),
          "topic" = coalesce(nullif(@topic, ''), "topic"),
          "read_time" = CASE WHEN @read_time = "read_time"
-                              OR @read_time IS NULL
+                              OR CAST(@read_time AS INTEGER) IS NULL
                               OR @read_time = 0
                             THEN "read_time"
                             ELSE @read_time
                              END,
          "content" = coalesce(nullif(@content, ''), "content"),
+         "revision" = "revision" + 1,
+         "version" = "version" + 1,
          "modified_at" = current_timestamp,
          "updated_at" = current_timestamp
    WHERE "uuid" = @uuid
@@ -1919,7 +2512,7 @@ func (r *archiveRepository) Release(ctx context.Context, id string) error {
   defer cancel()
 
   result, err := tx.ExecContext(ctx1, releasePatchQuery,
-    sql.Named("uuid", id),
+    sql.Named("uuid", patch.ArticleUUID.String()),
     sql.Named("title", patch.Title),
     sql.Named("slug", patch.Slug),
     sql.Named("topic", patch.TopicID),
@@ -1927,52 +2520,176 @@ func (r *archiveRepository) Release(ctx context.Context, id string) error {
     sql.Named("content", patch.Content))
 
   if nil != err {
-    slog.Error(err.Error())
-    return nil
+    slog.ErrorContext(ctx, err.Error())
+    return err
   }
 
   if affected, _ := result.RowsAffected(); 1 != affected {
-    return problem.NewNotFound(id, "article")
+    return problem.NewNotFound(patch.ArticleUUID.String(), "article")
   }
 
-  removePatchQuery := `
-  DELETE FROM "article_patch"
-        WHERE "article_uuid" = $1;`
+  removePatchLinksQuery := `
+  DELETE FROM "article_link"
+        WHERE "patch_uuid" = $1;`
 
   ctx1, cancel = context.WithTimeout(ctx, 3*time.Second)
   defer cancel()
 
-  _, err = tx.ExecContext(ctx1, removePatchQuery, id)
+  _, err = tx.ExecContext(ctx1, removePatchLinksQuery, id)
   if nil != err {
-    slog.Error(err.Error())
-    return nil
+    slog.ErrorContext(ctx, err.Error())
+    return err
   }
 
-  defer tx.Rollback()
+  removePatchQuery := `
+  DELETE FROM "article_patch"
+        WHERE "uuid" = $1;`
+
+  _, err = tx.ExecContext(ctx1, removePatchQuery, id)
+  if nil != err {
+    slog.ErrorContext(ctx, err.Error())
+    return err
+  }
 
   if err = tx.Commit(); nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return err
   }
 
   return nil
 }
 
+// MergePatch merges into patch the changes made to the article since
+// the revision it was branched from, so that releasing it does not undo
+// them. It returns the parts of the article that both changed.
+func MergePatch(patch *model.ArticlePatch, current *model.ArticleSnapshot) (conflicts []*transfer.PatchConflict) {
+  conflicts = make([]*transfer.PatchConflict, 0)
+
+  fields := []struct {
+    name    string
+    base    string
+    current string
+    patched **string
+  }{
+    {"title", patch.Base.Title, current.Title, &patch.Title},
+    {"slug", patch.Base.Slug, current.Slug, &patch.Slug},
+    {"topic", patch.Base.TopicID, current.TopicID, &patch.TopicID},
+  }
+
+  for _, field := range fields {
+    patched := *field.patched
+
+    switch {
+    case nil == patched, *patched == field.current, field.base == field.current:
+      continue
+    case *patched == field.base:
+      *field.patched = nil // Unchanged by the patch: keep the current value.
+    default:
+      conflicts = append(conflicts, &transfer.PatchConflict{
+        Field:   field.name,
+        Base:    field.base,
+        Patch:   *patched,
+        Article: field.current,
+      })
+    }
+  }
+
+  if nil == patch.Content || *patch.Content == patch.Base.Content {
+    patch.Content = nil
+    return conflicts
+  }
+
+  merged, hunks := diff.Merge(patch.Base.Content, *patch.Content, current.Content)
+
+  for _, hunk := range hunks {
+    conflicts = append(conflicts, &transfer.PatchConflict{
+      Field:   "content",
+      Line:    hunk.Line,
+      Base:    hunk.Base,
+      Patch:   hunk.Ours,
+      Article: hunk.Theirs,
+    })
+  }
+
+  patch.Content = &merged
+
+  return conflicts
+}
+
 func (r *archiveRepository) GetPatches(ctx context.Context) (patches []*model.ArticlePatch, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
+  return r.getPatches(ctx, "TRUE", "")
+}
+
+func (r *archiveRepository) ListPatches(ctx context.Context, page *transfer.PageRequest) (patches *transfer.Page[*model.ArticlePatch], err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
+  condition, args, err := keyset(page, false, timeKey(`"created_at"`), textKey(`"uuid"`))
+  if nil != err {
+    return nil, err
+  }
+
+  countPatchesQuery := `
+    SELECT count (*)
+      FROM "article_patch"
+     WHERE "deleted_at" IS NULL
+       AND "article_uuid" IN (SELECT "uuid"
+                                FROM "article"
+                               WHERE "deleted_at" IS NULL);`
+
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+
+  var total int
+
+  if err = r.db.QueryRowContext(ctx, countPatchesQuery).Scan(&total); nil != err {
+    slog.ErrorContext(ctx, err.Error())
+    return nil, err
+  }
+
+  items, err := r.getPatches(ctx, condition, "LIMIT @limit", args...)
+  if nil != err {
+    return nil, err
+  }
+
+  return newPage(items, page, total, func(p *model.ArticlePatch) *transfer.Cursor {
+    return &transfer.Cursor{Key: []string{transfer.CursorTime(p.CreatedAt)}, ID: p.UUID.String()}
+  }), nil
+}
+
+// getPatches retrieves the ongoing patches that satisfy condition,
+// limited by limit, oldest first.
+func (r *archiveRepository) getPatches(ctx context.Context, condition, limit string, args ...any) (patches []*model.ArticlePatch, err error) {
   getPatchesQuery := `
-  SELECT "article_uuid",
-         "title",
-         "slug",
-         "topic",
-         "content"
-    FROM "article_patch";`
+    SELECT "uuid",
+           "article_uuid",
+           "base_revision",
+           "version",
+           "title",
+           "slug",
+           "topic",
+           "content",
+           "created_at"
+      FROM "article_patch"
+     WHERE "deleted_at" IS NULL
+       AND "article_uuid" IN (SELECT "uuid"
+                                FROM "article"
+                               WHERE "deleted_at" IS NULL)
+       AND ` + condition + `
+  ORDER BY "created_at", "uuid"
+  ` + limit + `;`
 
   ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
   defer cancel()
 
-  result, err := r.db.QueryContext(ctx, getPatchesQuery)
+  result, err := r.db.QueryContext(ctx, getPatchesQuery, args...)
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return nil, err
   }
 
@@ -1984,14 +2701,18 @@ func (r *archiveRepository) GetPatches(ctx context.Context) (patches []*model.Ar
     var patch model.ArticlePatch
 
     err = result.Scan(
+      &patch.UUID,
       &patch.ArticleUUID,
+      &patch.BaseRevision,
+      &patch.Version,
       &patch.Title,
       &patch.Slug,
       &patch.TopicID,
-      &patch.Content)
+      &patch.Content,
+      &patch.CreatedAt)
 
     if nil != err {
-      slog.Error(err.Error())
+      slog.ErrorContext(ctx, err.Error())
       return nil, err
     }
 
@@ -2000,3 +2721,289 @@ func (r *archiveRepository) GetPatches(ctx context.Context) (patches []*model.Ar
 
   return patches, nil
 }
+
+func (r *archiveRepository) GetPatch(ctx context.Context, id string) (patch *model.ArticlePatch, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
+  getPatchQuery := `
+  SELECT "uuid",
+         "article_uuid",
+         "base_revision",
+         "version",
+         "base_title",
+         "base_slug",
+         coalesce ("base_topic", ''),
+         "base_content",
+         "title",
+         "slug",
+         "topic",
+         "read_time",
+         "content",
+         "created_at"
+    FROM "article_patch"
+   WHERE "uuid" = $1
+     AND "deleted_at" IS NULL
+     AND "article_uuid" IN (SELECT "uuid"
+                              FROM "article"
+                             WHERE "deleted_at" IS NULL);`
+
+  ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+  defer cancel()
+
+  patch = &model.ArticlePatch{Base: new(model.ArticleSnapshot)}
+
+  err = r.db.QueryRowContext(ctx, getPatchQuery, id).
+    Scan(&patch.UUID,
+      &patch.ArticleUUID,
+      &patch.BaseRevision,
+      &patch.Version,
+      &patch.Base.Title,
+      &patch.Base.Slug,
+      &patch.Base.TopicID,
+      &patch.Base.Content,
+      &patch.Title,
+      &patch.Slug,
+      &patch.TopicID,
+      &patch.ReadTime,
+      &patch.Content,
+      &patch.CreatedAt,
+    )
+
+  if nil != err {
+    if errors.Is(err, sql.ErrNoRows) {
+      return nil, problem.NewNotFound(id, "article patch")
+    }
+
+    slog.ErrorContext(ctx, err.Error())
+    return nil, err
+  }
+
+  return patch, nil
+}
+
+func (r *archiveRepository) Import(ctx context.Context, article *transfer.ArticleImport) (id, status string, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
+  slog.InfoContext(ctx, "importing article", slog.String("slug", article.Slug))
+
+  tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+  if nil != err {
+    slog.ErrorContext(ctx, err.Error())
+    return "", "", err
+  }
+
+  defer tx.Rollback()
+
+  ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+  defer cancel()
+
+  var topic string
+
+  if nil != article.Topic {
+    topic = article.Topic.ID
+
+    addTopicQuery := `
+    INSERT INTO "topic" ("id", "name")
+                 VALUES (@id, @name)
+        ON CONFLICT ("id") DO UPDATE
+                      SET "deleted_at" = NULL;`
+
+    _, err = tx.ExecContext(ctx, addTopicQuery,
+      sql.Named("id", article.Topic.ID),
+      sql.Named("name", article.Topic.Name))
+
+    if nil != err {
+      slog.ErrorContext(ctx, err.Error())
+      return "", "", err
+    }
+  }
+
+  addTagQuery := `
+  INSERT INTO "tag" ("id", "name")
+             VALUES (@id, @name)
+      ON CONFLICT ("id") DO UPDATE
+                    SET "deleted_at" = NULL;`
+
+  tags := make([]string, 0, len(article.Tags))
+
+  for _, tag := range article.Tags {
+    _, err = tx.ExecContext(ctx, addTagQuery,
+      sql.Named("id", tag.ID),
+      sql.Named("name", tag.Name))
+
+    if nil != err {
+      slog.ErrorContext(ctx, err.Error())
+      return "", "", err
+    }
+
+    tags = append(tags, tag.ID)
+  }
+
+  var publishedAt sql.NullString
+
+  if nil != article.PublishedAt {
+    publishedAt.String = article.PublishedAt.UTC().Format(time.DateTime)
+    publishedAt.Valid = true
+  }
+
+  args := []any{
+    sql.Named("title", article.Title),
+    sql.Named("slug", article.Slug),
+    sql.Named("read_time", article.ReadTime),
+    sql.Named("content", article.Content),
+    sql.Named("draft", !publishedAt.Valid),
+    sql.Named("hidden", article.IsHidden),
+    sql.Named("pinned", article.IsPinned),
+    sql.Named("topic", topic),
+    sql.Named("published_at", publishedAt),
+  }
+
+  getArticleBySlugQuery := `
+    SELECT "uuid"
+      FROM "article"
+     WHERE "slug" = @slug
+       AND "deleted_at" IS NULL
+  ORDER BY "drafted_at"
+     LIMIT 1;`
+
+  err = tx.QueryRowContext(ctx, getArticleBySlugQuery, sql.Named("slug", article.Slug)).Scan(&id)
+
+  switch {
+  case errors.Is(err, sql.ErrNoRows):
+    insertArticleQuery := `
+    INSERT INTO "article" ("title",
+                           "author",
+                           "slug",
+                           "read_time",
+                           "content",
+                           "draft",
+                           "hidden",
+                           "pinned",
+                           "topic",
+                           "drafted_at",
+                           "published_at")
+                   VALUES (@title,
+                           'fontseca.dev',
+                           @slug,
+                           @read_time,
+                           @content,
+                           @draft,
+                           @hidden,
+                           @pinned,
+                           nullif (@topic, ''),
+                           coalesce (@published_at, current_timestamp),
+                           @published_at)
+                RETURNING "uuid";`
+
+    err = tx.QueryRowContext(ctx, insertArticleQuery, args...).Scan(&id)
+    if nil != err {
+      slog.ErrorContext(ctx, err.Error())
+      return "", "", err
+    }
+
+    status = transfer.ImportCreated
+  case nil != err:
+    slog.ErrorContext(ctx, err.Error())
+    return "", "", err
+  default:
+    updateArticleQuery := `
+    UPDATE "article"
+       SET "title" = @title,
+           "read_time" = @read_time,
+           "content" = @content,
+           "draft" = @draft,
+           "hidden" = @hidden,
+           "pinned" = @pinned,
+           "topic" = nullif (@topic, ''),
+           "published_at" = @published_at,
+           "version" = "version" + 1,
+           "updated_at" = current_timestamp
+     WHERE "uuid" = @uuid
+       AND ("title" IS DISTINCT FROM @title
+        OR "read_time" IS DISTINCT FROM @read_time
+        OR "content" IS DISTINCT FROM @content
+        OR "draft" IS DISTINCT FROM @draft
+        OR "hidden" IS DISTINCT FROM @hidden
+        OR "pinned" IS DISTINCT FROM @pinned
+        OR "topic" IS DISTINCT FROM nullif (@topic, '')
+        OR "published_at" IS DISTINCT FROM @published_at);`
+
+    result, err := tx.ExecContext(ctx, updateArticleQuery, append(args, sql.Named("uuid", id))...)
+    if nil != err {
+      slog.ErrorContext(ctx, err.Error())
+      return "", "", err
+    }
+
+    status = transfer.ImportUnchanged
+
+    if affected, _ := result.RowsAffected(); 1 == affected {
+      status = transfer.ImportUpdated
+    }
+  }
+
+  getTagsQuery := `
+    SELECT "tag_id"
+      FROM "article_tag"
+     WHERE "article_uuid" = $1
+  ORDER BY "tag_id";`
+
+  rows, err := tx.QueryContext(ctx, getTagsQuery, id)
+  if nil != err {
+    slog.ErrorContext(ctx, err.Error())
+    return "", "", err
+  }
+
+  current := make([]string, 0)
+
+  for rows.Next() {
+    var tag string
+
+    if err = rows.Scan(&tag); nil != err {
+      rows.Close()
+      slog.ErrorContext(ctx, err.Error())
+      return "", "", err
+    }
+
+    current = append(current, tag)
+  }
+
+  rows.Close()
+
+  slices.Sort(tags)
+  tags = slices.Compact(tags)
+
+  if !slices.Equal(current, tags) {
+    _, err = tx.ExecContext(ctx, `DELETE FROM "article_tag" WHERE "article_uuid" = $1;`, id)
+    if nil != err {
+      slog.ErrorContext(ctx, err.Error())
+      return "", "", err
+    }
+
+    for _, tag := range tags {
+      _, err = tx.ExecContext(ctx, `INSERT INTO "article_tag" ("article_uuid", "tag_id") VALUES ($1, $2);`, id, tag)
+      if nil != err {
+        slog.ErrorContext(ctx, err.Error())
+        return "", "", err
+      }
+    }
+
+    if transfer.ImportUnchanged == status {
+      status = transfer.ImportUpdated
+    }
+  }
+
+  if err = tx.Commit(); nil != err {
+    slog.ErrorContext(ctx, err.Error())
+    return "", "", err
+  }
+
+  if transfer.ImportUnchanged != status {
+    r.db.OnCommit(ctx, r.setPublicationsCache)
+  }
+
+  return id, status, nil
+}
@@ -0,0 +1,335 @@
+package repository
+
+import (
+  "context"
+  "database/sql"
+  "errors"
+  "fontseca.dev/database"
+  "fontseca.dev/problem"
+  "fontseca.dev/transfer"
+  "github.com/stretchr/testify/assert"
+  "github.com/stretchr/testify/require"
+  "net/http"
+  "testing"
+  "time"
+)
+
+// assertStatus asserts that err is a problem with the HTTP status.
+func assertStatus(t *testing.T, status int, err error) {
+  t.Helper()
+
+  var p *problem.Problem
+  require.True(t, errors.As(err, &p), "expected a problem, got %v", err)
+  got, _, _ := p.Describe()
+  assert.Equal(t, status, got)
+}
+
+// newArchive returns an archive repository on db that is closed, and
+// its cached views written, before db is.
+func newArchive(t *testing.T, db *database.DB) ArchiveRepository {
+  var archive = NewArchiveRepository(db, time.Hour)
+  t.Cleanup(archive.Close)
+  return archive
+}
+
+// publish drafts and publishes an article with title and slug under
+// the topic, which must exist, and returns its UUID.
+func publish(t *testing.T, archive ArchiveRepository, title, slug, topic string) string {
+  t.Helper()
+
+  var ctx = context.Background()
+
+  id, err := archive.Draft(ctx, &transfer.ArticleCreation{Title: title, Slug: slug, Content: "First paragraph.\n\nSecond paragraph."})
+  require.NoError(t, err)
+  require.NoError(t, archive.Revise(ctx, id, &transfer.ArticleRevision{Topic: topic, Version: 1}))
+  require.NoError(t, archive.Publish(ctx, id))
+
+  return id
+}
+
+func TestArchiveRepository_Lifecycle(t *testing.T) {
+  forEachBackend(t, func(t *testing.T, db *database.DB) {
+    var (
+      ctx     = context.Background()
+      archive = newArchive(t, db)
+      now     = time.Now().UTC()
+    )
+
+    require.NoError(t, NewTopicsRepository(db).Add(ctx, &transfer.TopicCreation{ID: "go", Name: "Go"}))
+    require.NoError(t, NewTagsRepository(db).Add(ctx, &transfer.TagCreation{ID: "sql", Name: "SQL"}))
+
+    // Draft.
+    id, err := archive.Draft(ctx, &transfer.ArticleCreation{Title: "Lifecycle", Slug: "lifecycle", Content: "First paragraph.\n\nSecond paragraph."})
+    require.NoError(t, err)
+
+    draft, err := archive.GetByID(ctx, id, true)
+    require.NoError(t, err)
+    assert.True(t, draft.IsDraft)
+    assert.Nil(t, draft.PublishedAt)
+    assert.Equal(t, "fontseca.dev", draft.Author)
+
+    assertStatus(t, http.StatusBadRequest, archive.Publish(ctx, id))
+
+    // Share.
+    link, err := archive.Share(ctx, id)
+    require.NoError(t, err)
+    assert.NotEmpty(t, link)
+
+    again, err := archive.Share(ctx, id)
+    require.NoError(t, err)
+    assert.Equal(t, link, again)
+
+    shared, err := archive.GetByLink(ctx, link)
+    require.NoError(t, err)
+    assert.Equal(t, "Lifecycle", shared.Title)
+
+    // Publish.
+    assertStatus(t, http.StatusPreconditionRequired, archive.Revise(ctx, id, &transfer.ArticleRevision{Topic: "go"}))
+    require.NoError(t, archive.Revise(ctx, id, &transfer.ArticleRevision{Topic: "go", Version: 1}))
+    require.NoError(t, archive.AddTag(ctx, id, "sql", true))
+    require.NoError(t, archive.Publish(ctx, id))
+
+    _, err = archive.GetByID(ctx, id, true)
+    assertStatus(t, http.StatusNotFound, err)
+
+    article, err := archive.GetByID(ctx, id, false)
+    require.NoError(t, err)
+    assert.False(t, article.IsDraft)
+    require.NotNil(t, article.PublishedAt)
+    require.NotNil(t, article.Topic)
+    assert.Equal(t, "go", article.Topic.ID)
+    require.Len(t, article.Tags, 1)
+    assert.Equal(t, "sql", article.Tags[0].ID)
+
+    var request = &transfer.ArticleRequest{
+      Topic:       "go",
+      Publication: &transfer.Publication{Year: now.Year(), Month: now.Month()},
+      Slug:        "lifecycle",
+    }
+
+    article, err = archive.GetOne(ctx, request)
+    require.NoError(t, err)
+    assert.Equal(t, "Lifecycle", article.Title)
+
+    // Amend.
+    patchID, err := archive.Amend(ctx, id)
+    require.NoError(t, err)
+
+    _, err = archive.Amend(ctx, "00000000-0000-0000-0000-000000000000")
+    assertStatus(t, http.StatusNotFound, err)
+
+    patches, err := archive.GetPatches(ctx)
+    require.NoError(t, err)
+    require.Len(t, patches, 1)
+    assert.Equal(t, id, patches[0].ArticleUUID.String())
+
+    // Revise.
+    assertStatus(t, http.StatusPreconditionRequired, archive.Revise(ctx, patchID, &transfer.ArticleRevision{Title: "Unversioned"}))
+    require.NoError(t, archive.Revise(ctx, patchID, &transfer.ArticleRevision{Title: "Lifecycle, revised", Content: "First paragraph.\n\nSecond paragraph, revised.", Version: 1}))
+
+    patch, err := archive.GetPatch(ctx, patchID)
+    require.NoError(t, err)
+    require.NotNil(t, patch.Title)
+    assert.Equal(t, "Lifecycle, revised", *patch.Title)
+    assert.Equal(t, "Lifecycle", patch.Base.Title)
+
+    patchLink, err := archive.Share(ctx, patchID)
+    require.NoError(t, err)
+    assert.NotEqual(t, link, patchLink)
+
+    preview, err := archive.GetByLink(ctx, patchLink)
+    require.NoError(t, err)
+    assert.Equal(t, "Lifecycle, revised", preview.Title)
+
+    // The public copy stays untouched until the patch is released.
+    article, err = archive.GetOne(ctx, request)
+    require.NoError(t, err)
+    assert.Equal(t, "Lifecycle", article.Title)
+
+    // Release.
+    require.NoError(t, archive.Release(ctx, patchID))
+
+    article, err = archive.GetByID(ctx, id, false)
+    require.NoError(t, err)
+    assert.Equal(t, "Lifecycle, revised", article.Title)
+    assert.Equal(t, "First paragraph.\n\nSecond paragraph, revised.", article.Content)
+    assert.Equal(t, 1, article.Revision)
+    assert.NotNil(t, article.ModifiedAt)
+
+    _, err = archive.GetPatch(ctx, patchID)
+    assertStatus(t, http.StatusNotFound, err)
+
+    _, err = archive.GetByLink(ctx, patchLink)
+    assertStatus(t, http.StatusGone, err)
+  })
+}
+
+func TestArchiveRepository_Release(t *testing.T) {
+  forEachBackend(t, func(t *testing.T, db *database.DB) {
+    var (
+      ctx     = context.Background()
+      archive = newArchive(t, db)
+    )
+
+    require.NoError(t, NewTopicsRepository(db).Add(ctx, &transfer.TopicCreation{ID: "go", Name: "Go"}))
+
+    var id = publish(t, archive, "Concurrent", "concurrent", "go")
+
+    first, err := archive.Amend(ctx, id)
+    require.NoError(t, err)
+
+    second, err := archive.Amend(ctx, id)
+    require.NoError(t, err)
+
+    third, err := archive.Amend(ctx, id)
+    require.NoError(t, err)
+
+    require.NoError(t, archive.Revise(ctx, first, &transfer.ArticleRevision{Content: "First paragraph, edited.\n\nSecond paragraph.", Version: 1}))
+    require.NoError(t, archive.Revise(ctx, second, &transfer.ArticleRevision{Content: "First paragraph.\n\nSecond paragraph, edited.", Version: 1}))
+    require.NoError(t, archive.Revise(ctx, third, &transfer.ArticleRevision{Content: "First paragraph, rewritten.\n\nSecond paragraph.", Version: 1}))
+
+    require.NoError(t, archive.Release(ctx, first))
+
+    t.Run("merges patches that change other parts", func(t *testing.T) {
+      require.NoError(t, archive.Release(ctx, second))
+
+      article, err := archive.GetByID(ctx, id, false)
+      require.NoError(t, err)
+      assert.Equal(t, "First paragraph, edited.\n\nSecond paragraph, edited.", article.Content)
+      assert.Equal(t, 2, article.Revision)
+    })
+
+    t.Run("conflicts on patches that change the same parts", func(t *testing.T) {
+      assertStatus(t, http.StatusConflict, archive.Release(ctx, third))
+
+      _, err := archive.GetPatch(ctx, third)
+      require.NoError(t, err)
+    })
+
+    t.Run("discards a patch but keeps the article", func(t *testing.T) {
+      require.NoError(t, archive.Discard(ctx, third))
+
+      _, err := archive.GetPatch(ctx, third)
+      assertStatus(t, http.StatusNotFound, err)
+
+      _, err = archive.GetByID(ctx, id, false)
+      require.NoError(t, err)
+    })
+  })
+}
+
+func TestArchiveRepository_ExpiredLinks(t *testing.T) {
+  forEachBackend(t, func(t *testing.T, db *database.DB) {
+    var (
+      ctx     = context.Background()
+      archive = newArchive(t, db)
+    )
+
+    id, err := archive.Draft(ctx, &transfer.ArticleCreation{Title: "Expiring", Slug: "expiring"})
+    require.NoError(t, err)
+
+    link, err := archive.Share(ctx, id)
+    require.NoError(t, err)
+
+    var expire = func() {
+      _, err := db.ExecContext(ctx, `UPDATE "article_link" SET "expires_at" = '2000-01-01 00:00:00';`)
+      require.NoError(t, err)
+    }
+
+    expire()
+
+    _, err = archive.GetByLink(ctx, link)
+    assertStatus(t, http.StatusGone, err)
+
+    t.Run("sharing again makes a new link", func(t *testing.T) {
+      renewed, err := archive.Share(ctx, id)
+      require.NoError(t, err)
+      assert.NotEqual(t, link, renewed)
+
+      _, err = archive.GetByLink(ctx, renewed)
+      require.NoError(t, err)
+
+      link = renewed
+    })
+
+    t.Run("pruning removes them", func(t *testing.T) {
+      expire()
+
+      // Following a broken link prunes the expired ones in the
+      // background too, so either may have removed it.
+      _, err := archive.PruneLinks(ctx)
+      require.NoError(t, err)
+
+      var n int
+      require.NoError(t, db.QueryRowContext(ctx, `SELECT count (*) FROM "article_link";`).Scan(&n))
+      assert.Zero(t, n)
+    })
+
+    t.Run("discarding the draft breaks them", func(t *testing.T) {
+      link, err := archive.Share(ctx, id)
+      require.NoError(t, err)
+
+      require.NoError(t, archive.Discard(ctx, id))
+
+      _, err = archive.GetByLink(ctx, link)
+      assertStatus(t, http.StatusGone, err)
+
+      _, err = archive.GetByID(ctx, id, true)
+      assertStatus(t, http.StatusNotFound, err)
+    })
+  })
+}
+
+func TestArchiveRepository_Hidden(t *testing.T) {
+  forEachBackend(t, func(t *testing.T, db *database.DB) {
+    var (
+      ctx     = context.Background()
+      archive = newArchive(t, db)
+      now     = time.Now().UTC()
+      page    = &transfer.ArticleFilter{Page: 1, RPP: 10}
+    )
+
+    require.NoError(t, NewTopicsRepository(db).Add(ctx, &transfer.TopicCreation{ID: "go", Name: "Go"}))
+
+    var (
+      visible = publish(t, archive, "Visible", "visible", "go")
+      hidden  = publish(t, archive, "Hidden", "hidden", "go")
+    )
+
+    require.NoError(t, archive.SetHidden(ctx, hidden, true))
+
+    articles, err := archive.Get(ctx, page, false, false)
+    require.NoError(t, err)
+    require.Len(t, articles, 1)
+    assert.Equal(t, visible, articles[0].UUID.String())
+
+    total, err := archive.Count(ctx, page, false, false)
+    require.NoError(t, err)
+    assert.Equal(t, 1, total)
+
+    articles, err = archive.Get(ctx, page, true, false)
+    require.NoError(t, err)
+    require.Len(t, articles, 1)
+    assert.Equal(t, hidden, articles[0].UUID.String())
+
+    _, err = archive.GetOne(ctx, &transfer.ArticleRequest{
+      Topic:       "go",
+      Publication: &transfer.Publication{Year: now.Year(), Month: now.Month()},
+      Slug:        "hidden",
+    })
+    assert.ErrorIs(t, err, sql.ErrNoRows)
+
+    _, err = archive.GetByID(ctx, hidden, false)
+    assertStatus(t, http.StatusNotFound, err)
+
+    require.NoError(t, archive.SetHidden(ctx, hidden, false))
+
+    articles, err = archive.Get(ctx, page, false, false)
+    require.NoError(t, err)
+    assert.Len(t, articles, 2)
+
+    article, err := archive.GetByID(ctx, hidden, false)
+    require.NoError(t, err)
+    assert.Equal(t, "Hidden", article.Title)
+  })
+}
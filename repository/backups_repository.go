@@ -0,0 +1,116 @@
+package repository
+
+import (
+  "context"
+  "database/sql"
+  "errors"
+  "fmt"
+  "fontseca.dev/database"
+  "fontseca.dev/metrics"
+  "fontseca.dev/tracing"
+  _ "github.com/mattn/go-sqlite3"
+  "log/slog"
+  "os"
+  "strings"
+  "time"
+)
+
+// BackupsRepository provides methods for copying the database.
+type BackupsRepository interface {
+  // Backup writes a consistent copy of the SQLite database to file,
+  // which must not exist, and checks its integrity. The copy is made
+  // with VACUUM INTO on the connection of the writer pool, so writers
+  // wait in it until the copy is done; readers are not blocked. A copy
+  // that fails the check is removed. Other databases are backed up with
+  // their own tools.
+  Backup(ctx context.Context, file string) error
+}
+
+type backupsRepository struct {
+  db *database.DB
+}
+
+func NewBackupsRepository(db *database.DB) BackupsRepository {
+  return &backupsRepository{db}
+}
+
+func (r *backupsRepository) Backup(ctx context.Context, file string) error {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
+  if database.SQLite != r.db.Dialect() {
+    return fmt.Errorf("backups of %s databases are made with their own tools", r.db.Dialect().Name())
+  }
+
+  if _, err := os.Stat(file); !errors.Is(err, os.ErrNotExist) {
+    if nil == err {
+      err = fmt.Errorf("%s already exists", file)
+    }
+    return err
+  }
+
+  ctx1, cancel := context.WithTimeout(ctx, 5*time.Minute)
+  defer cancel()
+
+  if _, err := r.db.ExecContext(ctx1, `VACUUM INTO @file;`, sql.Named("file", file)); nil != err {
+    slog.ErrorContext(ctx, err.Error())
+    os.Remove(file)
+    return err
+  }
+
+  if err := CheckIntegrity(ctx, file); nil != err {
+    slog.ErrorContext(ctx, err.Error(), slog.String("file", file))
+    os.Remove(file)
+    return err
+  }
+
+  return nil
+}
+
+// CheckIntegrity runs 'PRAGMA integrity_check' on the SQLite database
+// at file, which is opened read-only, and reports every problem found.
+func CheckIntegrity(ctx context.Context, file string) error {
+  if _, err := os.Stat(file); nil != err {
+    return err
+  }
+
+  db, err := sql.Open("sqlite3", "file:"+file+"?mode=ro")
+  if nil != err {
+    return err
+  }
+
+  defer db.Close()
+
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+  defer cancel()
+
+  rows, err := db.QueryContext(ctx, `PRAGMA integrity_check;`)
+  if nil != err {
+    return fmt.Errorf("checking the integrity of %s: %w", file, err)
+  }
+
+  defer rows.Close()
+
+  var problems []string
+  for rows.Next() {
+    var result string
+    if err = rows.Scan(&result); nil != err {
+      return err
+    }
+
+    if "ok" != result {
+      problems = append(problems, result)
+    }
+  }
+
+  if err = rows.Err(); nil != err {
+    return fmt.Errorf("checking the integrity of %s: %w", file, err)
+  }
+
+  if 0 < len(problems) {
+    return fmt.Errorf("%s is corrupt: %s", file, strings.Join(problems, "; "))
+  }
+
+  return nil
+}
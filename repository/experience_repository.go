@@ -4,10 +4,14 @@ import (
   "context"
   "database/sql"
   "errors"
+  "fontseca.dev/database"
+  "fontseca.dev/metrics"
   "fontseca.dev/model"
   "fontseca.dev/problem"
+  "fontseca.dev/tracing"
   "fontseca.dev/transfer"
   "log/slog"
+  "strconv"
   "time"
 )
 
@@ -17,6 +21,11 @@ type ExperienceRepository interface {
   // the hidden experience records.
   Get(ctx context.Context, hidden bool) (experience []*model.Experience, err error)
 
+  // List retrieves the page of experience records that follows the
+  // cursor of page, the latest first. If hidden is true it only
+  // retrieves the hidden ones.
+  List(ctx context.Context, hidden bool, page *transfer.PageRequest) (experience *transfer.Page[*model.Experience], err error)
+
   // GetByID retrieves a single experience record by its UUID.
   GetByID(ctx context.Context, id string) (experience *model.Experience, err error)
 
@@ -24,40 +33,108 @@ type ExperienceRepository interface {
   Save(ctx context.Context, creation *transfer.ExperienceCreation) (saved bool, err error)
 
   // Update modifies an existing experience record with the provided update data.
+  // update.Version must be the current version of the record; otherwise a
+  // conflict problem with the current version is returned, or a
+  // precondition required problem if it is zero.
   Update(ctx context.Context, id string, update *transfer.ExperienceUpdate) (updated bool, err error)
 
-  // Remove deletes an experience record by its UUID.
+  // Remove moves an experience record to the trash by its UUID.
   Remove(ctx context.Context, id string) error
+
+  // Restore brings back an experience record from the trash.
+  Restore(ctx context.Context, id string) error
 }
 
+// experienceColumns are the columns of an experience record in the
+// order they are scanned.
+const experienceColumns = `"uuid",
+                    "starts",
+                    "ends",
+                    "job_title",
+                    "company",
+                    "country",
+                    "summary",
+                    "active",
+                    "hidden",
+                    "created_at",
+                    "updated_at",
+                    "version"`
+
 type experienceRepository struct {
-  db *sql.DB
+  db *database.DB
 }
 
 // NewExperienceRepository creates a new ExperienceRepository instance associating db as its database.
-func NewExperienceRepository(db *sql.DB) ExperienceRepository {
+func NewExperienceRepository(db *database.DB) ExperienceRepository {
   return &experienceRepository{db}
 }
 
 func (r *experienceRepository) Get(ctx context.Context, hidden bool) (experience []*model.Experience, err error) {
-  var query string
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
+  return r.doGet(ctx, hidden, "TRUE", "")
+}
+
+func (r *experienceRepository) List(ctx context.Context, hidden bool, page *transfer.PageRequest) (experience *transfer.Page[*model.Experience], err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
+  condition, args, err := keyset(page, true, intKey(`"starts"`), textKey(`"uuid"`))
+  if nil != err {
+    return nil, err
+  }
+
+  query := `SELECT count (*)
+              FROM "experience"
+             WHERE ` + experienceFilter(hidden) + `;`
+  var total int
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+  err = r.db.QueryRowContext(ctx, query).Scan(&total)
+  if nil != err {
+    slog.ErrorContext(ctx, err.Error())
+    return nil, err
+  }
+
+  items, err := r.doGet(ctx, hidden, condition, "LIMIT @limit", args...)
+  if nil != err {
+    return nil, err
+  }
+
+  return newPage(items, page, total, func(e *model.Experience) *transfer.Cursor {
+    return &transfer.Cursor{Key: []string{strconv.Itoa(e.Starts)}, ID: e.UUID.String()}
+  }), nil
+}
+
+// experienceFilter returns the condition that selects either the
+// hidden experience records or all of them.
+func experienceFilter(hidden bool) string {
   if hidden {
-    query = `SELECT *
-               FROM "experience"
-              WHERE "hidden" IS TRUE
-           ORDER BY "starts" DESC;`
-  } else {
-    query = `SELECT *
-               FROM "experience"
-           ORDER BY "starts" DESC;`
+    return `"hidden" IS TRUE AND "deleted_at" IS NULL`
   }
+
+  return `"deleted_at" IS NULL`
+}
+
+// doGet retrieves the experience records that satisfy condition, limited by limit.
+func (r *experienceRepository) doGet(ctx context.Context, hidden bool, condition, limit string, args ...any) (experience []*model.Experience, err error) {
+  query := `SELECT ` + experienceColumns + `
+              FROM "experience"
+             WHERE ` + experienceFilter(hidden) + `
+               AND ` + condition + `
+          ORDER BY "starts" DESC, "uuid" DESC
+             ` + limit + `;`
   ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
   defer cancel()
-  rows, err := r.db.QueryContext(ctx, query)
+  rows, err := r.db.QueryContext(ctx, query, args...)
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return nil, err
   }
+  defer rows.Close()
   s := make([]*model.Experience, 0)
   for rows.Next() {
     e := new(model.Experience)
@@ -72,9 +149,10 @@ func (r *experienceRepository) Get(ctx context.Context, hidden bool) (experience
       &e.Active,
       &e.Hidden,
       &e.CreatedAt,
-      &e.UpdatedAt)
+      &e.UpdatedAt,
+      &e.Version)
     if nil != err {
-      slog.Error(err.Error())
+      slog.ErrorContext(ctx, err.Error())
       return nil, err
     }
     s = append(s, e)
@@ -83,9 +161,14 @@ func (r *experienceRepository) Get(ctx context.Context, hidden bool) (experience
 }
 
 func (r *experienceRepository) GetByID(ctx context.Context, id string) (experience *model.Experience, err error) {
-  query := `SELECT *
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
+  query := `SELECT ` + experienceColumns + `
               FROM "experience"
-             WHERE "uuid" = @uuid;`
+             WHERE "uuid" = @uuid
+               AND "deleted_at" IS NULL;`
   ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
   defer cancel()
   row := r.db.QueryRowContext(ctx, query, sql.Named("uuid", id))
@@ -101,12 +184,13 @@ func (r *experienceRepository) GetByID(ctx context.Context, id string) (experien
     &experience.Active,
     &experience.Hidden,
     &experience.CreatedAt,
-    &experience.UpdatedAt)
+    &experience.UpdatedAt,
+    &experience.Version)
   if nil != err {
     if errors.Is(err, sql.ErrNoRows) {
       err = problem.NewNotFound(id, "experience")
     } else {
-      slog.Error(err.Error())
+      slog.ErrorContext(ctx, err.Error())
     }
     return nil, err
   }
@@ -114,9 +198,13 @@ func (r *experienceRepository) GetByID(ctx context.Context, id string) (experien
 }
 
 func (r *experienceRepository) Save(ctx context.Context, creation *transfer.ExperienceCreation) (saved bool, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
   tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return false, err
   }
   defer tx.Rollback()
@@ -145,7 +233,7 @@ func (r *experienceRepository) Save(ctx context.Context, creation *transfer.Expe
     sql.Named("country", creation.Country),
     sql.Named("summary", creation.Summary))
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return false, err
   }
   affected, _ := result.RowsAffected()
@@ -153,7 +241,7 @@ func (r *experienceRepository) Save(ctx context.Context, creation *transfer.Expe
     return false, nil
   }
   if err = tx.Commit(); nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return false, nil
   }
   return true, nil
@@ -174,30 +262,54 @@ func (r *experienceRepository) updatable(current *model.Experience, update *tran
 }
 
 func (r *experienceRepository) Update(ctx context.Context, id string, update *transfer.ExperienceUpdate) (updated bool, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
+  err = r.db.Transact(ctx, func(ctx context.Context) (err error) {
+    updated, err = r.update(ctx, id, update)
+    return err
+  })
+
+  return updated && nil == err, err
+}
+
+// update checks the version of the experience id, which it retrieves in
+// the same transaction, and updates it.
+func (r *experienceRepository) update(ctx context.Context, id string, update *transfer.ExperienceUpdate) (updated bool, err error) {
+  if 0 == update.Version {
+    return false, problem.NewPreconditionRequired("experience")
+  }
   tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return false, err
   }
+  defer tx.Rollback()
   current, err := r.GetByID(ctx, id)
   if nil != err {
     return false, err
   }
+  if update.Version != current.Version {
+    return false, problem.NewVersionConflict(id, "experience", current.Version)
+  }
   if updatable := r.updatable(current, update); !updatable {
     return false, nil
   }
   query := `
   UPDATE "experience"
-     SET "starts" = CASE WHEN @starts = @current_starts OR 0 = @starts THEN @current_starts ELSE @starts END,
-         "ends" = CASE WHEN @ends = @current_ends OR 0 = @ends THEN @current_ends ELSE @ends END,
+     SET "starts" = CASE WHEN @starts = @current_starts OR 0 = @starts THEN CAST(@current_starts AS INTEGER) ELSE CAST(@starts AS INTEGER) END,
+         "ends" = CASE WHEN @ends = @current_ends OR 0 = @ends THEN CAST(@current_ends AS INTEGER) ELSE CAST(@ends AS INTEGER) END,
          "job_title" = coalesce (nullif (@job_title, ''), @current_job_title),
          "company" = coalesce (nullif (@company, ''), @current_company),
          "country" = coalesce (nullif (@country, ''), @current_country),
          "summary" = coalesce (nullif (@summary, ''), @current_summary),
          "active" = @active,
          "hidden" = @hidden,
-         "updated_at" = current_timestamp
-   WHERE "uuid" = @uuid;`
+         "updated_at" = current_timestamp,
+         "version" = "version" + 1
+   WHERE "uuid" = @uuid
+     AND "version" = @current_version;`
   ctx, cancel := context.WithTimeout(ctx, time.Second)
   defer cancel()
   result, err := tx.ExecContext(ctx, query,
@@ -209,37 +321,83 @@ func (r *experienceRepository) Update(ctx context.Context, id string, update *tr
     sql.Named("country", update.Country), sql.Named("current_country", current.Country),
     sql.Named("summary", update.Summary), sql.Named("current_summary", current.Summary),
     sql.Named("active", update.Active),
-    sql.Named("hidden", update.Hidden))
+    sql.Named("hidden", update.Hidden),
+    sql.Named("current_version", current.Version))
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return false, err
   }
   affected, _ := result.RowsAffected()
   if 1 != affected {
-    return false, nil
+    // Another update changed the version since it was retrieved.
+    latest, err := r.GetByID(ctx, id)
+    if nil != err {
+      return false, err
+    }
+    return false, problem.NewVersionConflict(id, "experience", latest.Version)
   }
   if err = tx.Commit(); nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return false, err
   }
   return true, nil
 }
 
 func (r *experienceRepository) Remove(ctx context.Context, id string) error {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
   tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return err
   }
   defer tx.Rollback()
   query := `
-  DELETE FROM "experience"
-        WHERE "uuid" = @uuid;`
+  UPDATE "experience"
+     SET "deleted_at" = current_timestamp
+   WHERE "uuid" = @uuid
+     AND "deleted_at" IS NULL;`
+  ctx, cancel := context.WithTimeout(ctx, time.Second)
+  defer cancel()
+  result, err := tx.ExecContext(ctx, query, sql.Named("uuid", id))
+  if nil != err {
+    slog.ErrorContext(ctx, err.Error())
+    return err
+  }
+  affected, _ := result.RowsAffected()
+  if 1 != affected {
+    return problem.NewNotFound(id, "experience")
+  }
+  if err = tx.Commit(); nil != err {
+    slog.ErrorContext(ctx, err.Error())
+    return err
+  }
+  return nil
+}
+
+func (r *experienceRepository) Restore(ctx context.Context, id string) error {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
+  tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+  if nil != err {
+    slog.ErrorContext(ctx, err.Error())
+    return err
+  }
+  defer tx.Rollback()
+  query := `
+  UPDATE "experience"
+     SET "deleted_at" = NULL
+   WHERE "uuid" = @uuid
+     AND "deleted_at" IS NOT NULL;`
   ctx, cancel := context.WithTimeout(ctx, time.Second)
   defer cancel()
   result, err := tx.ExecContext(ctx, query, sql.Named("uuid", id))
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return err
   }
   affected, _ := result.RowsAffected()
@@ -247,7 +405,7 @@ func (r *experienceRepository) Remove(ctx context.Context, id string) error {
     return problem.NewNotFound(id, "experience")
   }
   if err = tx.Commit(); nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return err
   }
   return nil
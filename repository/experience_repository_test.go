@@ -0,0 +1,83 @@
+package repository
+
+import (
+  "context"
+  "fontseca.dev/database"
+  "fontseca.dev/transfer"
+  "github.com/google/uuid"
+  "github.com/stretchr/testify/assert"
+  "github.com/stretchr/testify/require"
+  "net/http"
+  "testing"
+)
+
+func TestExperienceRepository(t *testing.T) {
+  forEachBackend(t, func(t *testing.T, db *database.DB) {
+    var (
+      ctx        = context.Background()
+      experience = NewExperienceRepository(db)
+    )
+
+    saved, err := experience.Save(ctx, &transfer.ExperienceCreation{
+      Starts:   2020,
+      JobTitle: "Developer",
+      Company:  "Company",
+      Country:  "Nicaragua",
+      Summary:  "A summary.",
+    })
+    require.NoError(t, err)
+    assert.True(t, saved)
+
+    all, err := experience.Get(ctx, false)
+    require.NoError(t, err)
+    require.Len(t, all, 1)
+    var id = all[0].UUID.String()
+    assert.Nil(t, all[0].Ends)
+    assert.True(t, all[0].Active)
+    assert.Equal(t, 1, all[0].Version)
+
+    updated, err := experience.Update(ctx, id, &transfer.ExperienceUpdate{Ends: 2023, JobTitle: "Engineer", Active: true, Version: 1})
+    require.NoError(t, err)
+    assert.True(t, updated)
+
+    got, err := experience.GetByID(ctx, id)
+    require.NoError(t, err)
+    assert.Equal(t, 2020, got.Starts)
+    require.NotNil(t, got.Ends)
+    assert.Equal(t, 2023, *got.Ends)
+    assert.Equal(t, "Engineer", got.JobTitle)
+    assert.Equal(t, "Company", got.Company)
+    assert.Equal(t, 2, got.Version)
+
+    updated, err = experience.Update(ctx, id, &transfer.ExperienceUpdate{JobTitle: "Stale", Active: true, Version: 1})
+    assertStatus(t, http.StatusConflict, err)
+    assert.False(t, updated)
+
+    updated, err = experience.Update(ctx, id, &transfer.ExperienceUpdate{JobTitle: "Unversioned", Active: true})
+    assertStatus(t, http.StatusPreconditionRequired, err)
+    assert.False(t, updated)
+
+    // A failed update leaves the database free for the next one.
+    updated, err = experience.Update(ctx, uuid.NewString(), &transfer.ExperienceUpdate{JobTitle: "Missing", Version: 1})
+    assertStatus(t, http.StatusNotFound, err)
+    assert.False(t, updated)
+
+    updated, err = experience.Update(ctx, id, &transfer.ExperienceUpdate{Hidden: true, Active: true, Version: 2})
+    require.NoError(t, err)
+    assert.True(t, updated)
+
+    hidden, err := experience.Get(ctx, true)
+    require.NoError(t, err)
+    require.Len(t, hidden, 1)
+    assert.True(t, hidden[0].Hidden)
+
+    require.NoError(t, experience.Remove(ctx, id))
+    _, err = experience.GetByID(ctx, id)
+    assertStatus(t, http.StatusNotFound, err)
+    assertStatus(t, http.StatusNotFound, experience.Remove(ctx, id))
+
+    require.NoError(t, experience.Restore(ctx, id))
+    _, err = experience.GetByID(ctx, id)
+    require.NoError(t, err)
+  })
+}
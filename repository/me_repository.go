@@ -3,7 +3,12 @@ package repository
 import (
   "context"
   "database/sql"
+  "errors"
+  "fontseca.dev/database"
+  "fontseca.dev/metrics"
   "fontseca.dev/model"
+  "fontseca.dev/problem"
+  "fontseca.dev/tracing"
   "fontseca.dev/transfer"
   "log/slog"
   "time"
@@ -19,16 +24,27 @@ type MeRepository interface {
   // Get retrieves the information of my profile.
   Get(ctx context.Context) (me *model.Me, err error)
 
-  // Update updates the information of my profile.
+  // Update updates the information of my profile. update.Version must be
+  // the current version of my profile; otherwise a conflict problem with
+  // the current version is returned, or a precondition required problem
+  // if it is zero.
   Update(ctx context.Context, update *transfer.MeUpdate) (ok bool, err error)
+
+  // SetPasswordHash stores hash as the hash of my password, replacing
+  // the one stored before, if any.
+  SetPasswordHash(ctx context.Context, hash string) error
+
+  // PasswordHash retrieves the hash of my password. It is empty if no
+  // password has been set.
+  PasswordHash(ctx context.Context) (hash string, err error)
 }
 
 type meRepositoryImpl struct {
-  db *sql.DB
+  db *database.DB
 }
 
 // NewMeRepository creates a new MeRepository instance associating db as its database.
-func NewMeRepository(db *sql.DB) MeRepository {
+func NewMeRepository(db *database.DB) MeRepository {
   return &meRepositoryImpl{db}
 }
 
@@ -40,13 +56,17 @@ func (r *meRepositoryImpl) registered(ctx context.Context) bool {
     QueryRowContext(ctx, `SELECT count (1) FROM "me";`).
     Scan(&exists)
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return false
   }
   return exists
 }
 
 func (r *meRepositoryImpl) Register(ctx context.Context) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
   if r.registered(ctx) {
     return
   }
@@ -63,11 +83,15 @@ func (r *meRepositoryImpl) Register(ctx context.Context) {
   defer cancel()
   _, err := r.db.ExecContext(ctx, query)
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
   }
 }
 
 func (r *meRepositoryImpl) Get(ctx context.Context) (me *model.Me, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
   ctx, cancel := context.WithTimeout(ctx, time.Second)
   defer cancel()
   var row = r.db.QueryRowContext(ctx, `SELECT * FROM "me";`)
@@ -91,9 +115,10 @@ func (r *meRepositoryImpl) Get(ctx context.Context) (me *model.Me, err error) {
     &me.TwitterURL,
     &me.InstagramURL,
     &me.CreatedAt,
-    &me.UpdatedAt)
+    &me.UpdatedAt,
+    &me.Version)
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return me, err
   }
   return me, nil
@@ -118,9 +143,27 @@ func (r *meRepositoryImpl) updatable(current *model.Me, update *transfer.MeUpdat
 }
 
 func (r *meRepositoryImpl) Update(ctx context.Context, update *transfer.MeUpdate) (ok bool, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
+  err = r.db.Transact(ctx, func(ctx context.Context) (err error) {
+    ok, err = r.update(ctx, update)
+    return err
+  })
+
+  return ok && nil == err, err
+}
+
+// update checks the version of my profile, which it retrieves in the
+// same transaction, and updates it.
+func (r *meRepositoryImpl) update(ctx context.Context, update *transfer.MeUpdate) (ok bool, err error) {
+  if 0 == update.Version {
+    return false, problem.NewPreconditionRequired("me")
+  }
   tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return false, err
   }
   defer tx.Rollback()
@@ -128,6 +171,9 @@ func (r *meRepositoryImpl) Update(ctx context.Context, update *transfer.MeUpdate
   if nil != err {
     return false, err
   }
+  if update.Version != current.Version {
+    return false, problem.NewVersionConflict(current.Username, "me", current.Version)
+  }
   if updatable := r.updatable(current, update); !updatable {
     return false, nil
   }
@@ -146,8 +192,10 @@ func (r *meRepositoryImpl) Update(ctx context.Context, update *transfer.MeUpdate
            "youtube_url" = coalesce (nullif (@new_youtube_url, ''), @current_youtube_url),
            "twitter_url" = coalesce (nullif (@new_twitter_url, ''), @current_twitter_url),
            "instagram_url" = coalesce (nullif (@new_instagram_url, ''), @current_instagram_url),
-           "updated_at" = current_timestamp
-     WHERE "username" = "fontseca.dev";`
+           "updated_at" = current_timestamp,
+           "version" = "version" + 1
+     WHERE "username" = @username
+       AND "version" = @current_version;`
   ctx, cancel := context.WithTimeout(ctx, time.Second)
   defer cancel()
   result, err := tx.ExecContext(ctx, query,
@@ -163,18 +211,73 @@ func (r *meRepositoryImpl) Update(ctx context.Context, update *transfer.MeUpdate
     sql.Named("new_linkedin_url", update.LinkedInURL), sql.Named("current_linkedin_url", current.LinkedInURL),
     sql.Named("new_youtube_url", update.YouTubeURL), sql.Named("current_youtube_url", current.YouTubeURL),
     sql.Named("new_twitter_url", update.TwitterURL), sql.Named("current_twitter_url", current.TwitterURL),
-    sql.Named("new_instagram_url", update.InstagramURL), sql.Named("current_instagram_url", current.InstagramURL))
+    sql.Named("new_instagram_url", update.InstagramURL), sql.Named("current_instagram_url", current.InstagramURL),
+    sql.Named("username", current.Username),
+    sql.Named("current_version", current.Version))
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return false, err
   }
   var affected, _ = result.RowsAffected()
   if 1 != affected {
-    return false, nil
+    // Another update changed the version since it was retrieved.
+    latest, err := r.Get(ctx)
+    if nil != err {
+      return false, err
+    }
+    return false, problem.NewVersionConflict(latest.Username, "me", latest.Version)
   }
   if err = tx.Commit(); nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return false, err
   }
   return true, nil
 }
+
+func (r *meRepositoryImpl) SetPasswordHash(ctx context.Context, hash string) error {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
+  var query = `
+  INSERT INTO "credential" ("username", "password_hash")
+       SELECT "username", @hash
+         FROM "me"
+        WHERE TRUE
+  ON CONFLICT ("username")
+    DO UPDATE SET "password_hash" = excluded."password_hash",
+                  "updated_at" = current_timestamp;`
+  ctx, cancel := context.WithTimeout(ctx, time.Second)
+  defer cancel()
+  result, err := r.db.ExecContext(ctx, query, sql.Named("hash", hash))
+  if nil != err {
+    slog.ErrorContext(ctx, err.Error())
+    return err
+  }
+  if affected, _ := result.RowsAffected(); 0 == affected {
+    return problem.NewNotFound("fontseca.dev", "me")
+  }
+  return nil
+}
+
+func (r *meRepositoryImpl) PasswordHash(ctx context.Context) (hash string, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
+  var query = `
+  SELECT c."password_hash"
+    FROM "credential" c
+    JOIN "me" m ON m."username" = c."username";`
+  ctx, cancel := context.WithTimeout(ctx, time.Second)
+  defer cancel()
+  err = r.db.QueryRowContext(ctx, query).Scan(&hash)
+  if errors.Is(err, sql.ErrNoRows) {
+    return "", nil
+  }
+  if nil != err {
+    slog.ErrorContext(ctx, err.Error())
+    return "", err
+  }
+  return hash, nil
+}
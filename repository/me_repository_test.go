@@ -0,0 +1,70 @@
+package repository
+
+import (
+  "context"
+  "fontseca.dev/database"
+  "fontseca.dev/transfer"
+  "github.com/stretchr/testify/assert"
+  "github.com/stretchr/testify/require"
+  "net/http"
+  "testing"
+)
+
+func TestMeRepository(t *testing.T) {
+  forEachBackend(t, func(t *testing.T, db *database.DB) {
+    var (
+      ctx = context.Background()
+      me  = NewMeRepository(db)
+    )
+
+    // Registering me again keeps the record made by prepare.
+    me.Register(ctx)
+
+    got, err := me.Get(ctx)
+    require.NoError(t, err)
+    assert.Equal(t, "fontseca.dev", got.Username)
+    assert.Equal(t, 1, got.Version)
+
+    updated, err := me.Update(ctx, &transfer.MeUpdate{Summary: "A summary.", Hireable: got.Hireable, Version: 1})
+    require.NoError(t, err)
+    assert.True(t, updated)
+
+    got, err = me.Get(ctx)
+    require.NoError(t, err)
+    assert.Equal(t, "A summary.", got.Summary)
+    assert.Equal(t, "email@example.com", got.Email)
+    assert.Equal(t, 2, got.Version)
+
+    updated, err = me.Update(ctx, &transfer.MeUpdate{Summary: "A summary.", Hireable: got.Hireable, Version: 2})
+    require.NoError(t, err)
+    assert.False(t, updated)
+
+    updated, err = me.Update(ctx, &transfer.MeUpdate{Summary: "Stale.", Hireable: got.Hireable, Version: 1})
+    assertStatus(t, http.StatusConflict, err)
+    assert.False(t, updated)
+
+    updated, err = me.Update(ctx, &transfer.MeUpdate{Summary: "Unversioned.", Hireable: got.Hireable})
+    assertStatus(t, http.StatusPreconditionRequired, err)
+    assert.False(t, updated)
+  })
+}
+
+func TestMeRepository_PasswordHash(t *testing.T) {
+  forEachBackend(t, func(t *testing.T, db *database.DB) {
+    var (
+      ctx = context.Background()
+      me  = NewMeRepository(db)
+    )
+
+    hash, err := me.PasswordHash(ctx)
+    require.NoError(t, err)
+    assert.Empty(t, hash)
+
+    require.NoError(t, me.SetPasswordHash(ctx, "first"))
+    require.NoError(t, me.SetPasswordHash(ctx, "second"))
+
+    hash, err = me.PasswordHash(ctx)
+    require.NoError(t, err)
+    assert.Equal(t, "second", hash)
+  })
+}
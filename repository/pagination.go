@@ -0,0 +1,122 @@
+package repository
+
+import (
+  "database/sql"
+  "fmt"
+  "fontseca.dev/problem"
+  "fontseca.dev/transfer"
+  "strconv"
+  "strings"
+  "time"
+)
+
+// sortKey is one of the values a list is sorted by.
+type sortKey struct {
+  column string                     // the SQL expression sorted by
+  param  string                     // the SQL expression the value of a cursor is compared as, with a %s for the parameter; "%s" if empty
+  value  func(string) (any, error) // converts the value of a cursor into the argument of the parameter
+}
+
+// textKey is a sort key that holds text.
+func textKey(column string) sortKey {
+  return sortKey{column: column, value: func(s string) (any, error) { return s, nil }}
+}
+
+// timeKey is a sort key that holds a timestamp.
+func timeKey(column string) sortKey {
+  return sortKey{column: column, value: func(s string) (any, error) {
+    t, err := time.Parse(transfer.CursorTimeLayout, s)
+    if nil != err {
+      return nil, err
+    }
+    return transfer.CursorTime(t), nil
+  }}
+}
+
+// intKey is a sort key that holds an integer.
+func intKey(column string) sortKey {
+  return sortKey{column: column, value: func(s string) (any, error) { return strconv.Atoi(s) }}
+}
+
+// boolKey is a sort key that holds a boolean.
+func boolKey(column string) sortKey {
+  return sortKey{column: column, value: func(s string) (any, error) { return strconv.ParseBool(s) }}
+}
+
+// keyset writes the condition that selects the rows of a list that
+// come after the cursor of page, as after does, and returns its
+// arguments along with the one of the @limit parameter, which asks for
+// one more row than the page holds to tell whether another page
+// follows.
+func keyset(page *transfer.PageRequest, descending bool, keys ...sortKey) (condition string, args []any, err error) {
+  condition, args, err = after(page.After, descending, keys...)
+  if nil != err {
+    return "", nil, err
+  }
+
+  return condition, append(args, sql.Named("limit", page.Limit+1)), nil
+}
+
+// after writes the condition that selects the rows that come after
+// cursor in a list sorted by keys in descending or ascending order.
+// The last of keys is the ID of the rows, which breaks the ties
+// between the others. Since the cursor holds the values themselves,
+// the condition does not depend on the row it was made from, which may
+// not exist anymore. If there is no cursor, the condition is TRUE.
+func after(cursor *transfer.Cursor, descending bool, keys ...sortKey) (condition string, args []any, err error) {
+  if nil == cursor {
+    return "TRUE", nil, nil
+  }
+
+  var values = append(append([]string{}, cursor.Key...), cursor.ID)
+
+  if len(keys) != len(values) {
+    return "", nil, problem.NewInvalidCursor(cursor.String())
+  }
+
+  var columns, params = make([]string, len(keys)), make([]string, len(keys))
+
+  for i, key := range keys {
+    value, err := key.value(values[i])
+    if nil != err {
+      return "", nil, problem.NewInvalidCursor(cursor.String())
+    }
+
+    var (
+      name  = fmt.Sprintf("after_%d", i)
+      param = key.param
+    )
+
+    if "" == param {
+      param = "%s"
+    }
+
+    columns[i] = key.column
+    params[i] = fmt.Sprintf(param, "@"+name)
+    args = append(args, sql.Named(name, value))
+  }
+
+  var operator = ">"
+
+  if descending {
+    operator = "<"
+  }
+
+  condition = fmt.Sprintf("(%s) %s (%s)", strings.Join(columns, ", "), operator, strings.Join(params, ", "))
+
+  return condition, args, nil
+}
+
+// newPage makes the page of a list out of the rows retrieved with the
+// condition of keyset, which may hold one more item than requested,
+// and the total number of items of the list.
+func newPage[T any](items []T, page *transfer.PageRequest, total int, cursor func(T) *transfer.Cursor) *transfer.Page[T] {
+  var p = &transfer.Page[T]{Items: items, Total: total}
+
+  if page.Limit < len(items) {
+    p.Items = items[:page.Limit]
+    p.NextCursor = cursor(p.Items[page.Limit-1]).String()
+  }
+
+  return p
+}
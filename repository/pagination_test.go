@@ -0,0 +1,121 @@
+package repository
+
+import (
+  "context"
+  "fmt"
+  "fontseca.dev/database"
+  "fontseca.dev/model"
+  "fontseca.dev/transfer"
+  "github.com/stretchr/testify/assert"
+  "github.com/stretchr/testify/require"
+  "net/http"
+  "testing"
+  "time"
+)
+
+// walk retrieves every page of a list of limit items with list and
+// returns the IDs of their items, in order.
+func walk[T any](t *testing.T, limit int, list func(page *transfer.PageRequest) (*transfer.Page[T], error), id func(T) string) []string {
+  var (
+    ids  = make([]string, 0)
+    page = &transfer.PageRequest{Limit: limit}
+  )
+
+  for {
+    got, err := list(page)
+    require.NoError(t, err)
+    require.LessOrEqual(t, len(got.Items), limit)
+
+    for _, item := range got.Items {
+      ids = append(ids, id(item))
+    }
+
+    if "" == got.NextCursor {
+      assert.Len(t, ids, got.Total)
+      return ids
+    }
+
+    page.After, err = transfer.ParseCursor(got.NextCursor)
+    require.NoError(t, err)
+  }
+}
+
+func TestPagination(t *testing.T) {
+  forEachBackend(t, func(t *testing.T, db *database.DB) {
+    var (
+      ctx      = context.Background()
+      topics   = NewTopicsRepository(db)
+      projects = NewProjectsRepository(db)
+      trash    = NewTrashRepository(db)
+    )
+
+    for _, name := range []string{"Rust", "go", "C", "Python", "zig"} {
+      require.NoError(t, topics.Add(ctx, &transfer.TopicCreation{ID: fmt.Sprintf("topic-%s", name), Name: name}))
+    }
+
+    // The projects are created within the same second, so only their
+    // UUIDs tell them apart.
+    var created = make(map[string]bool)
+    for i := 0; i < 5; i++ {
+      id, err := projects.Add(ctx, &transfer.ProjectCreation{Name: fmt.Sprintf("Project %d", i), Slug: fmt.Sprintf("project-%d", i)})
+      require.NoError(t, err)
+      created[id] = true
+    }
+
+    t.Run("walks every list", func(t *testing.T) {
+      ids := walk(t, 2, func(page *transfer.PageRequest) (*transfer.Page[*model.Topic], error) {
+        return topics.List(ctx, page)
+      }, func(topic *model.Topic) string { return topic.Name })
+      assert.Equal(t, []string{"C", "go", "Python", "Rust", "zig"}, ids)
+
+      ids = walk(t, 2, func(page *transfer.PageRequest) (*transfer.Page[*model.Project], error) {
+        return projects.List(ctx, false, page)
+      }, func(project *model.Project) string { return project.UUID.String() })
+      require.Len(t, ids, len(created))
+      for _, id := range ids {
+        assert.True(t, created[id], id)
+      }
+    })
+
+    t.Run("cursor of a removed item", func(t *testing.T) {
+      first, err := topics.List(ctx, &transfer.PageRequest{Limit: 2})
+      require.NoError(t, err)
+      require.Equal(t, "go", first.Items[1].Name)
+
+      require.NoError(t, topics.Remove(ctx, first.Items[1].ID))
+      _, err = trash.Purge(ctx, time.Now().Add(time.Minute))
+      require.NoError(t, err)
+
+      after, err := transfer.ParseCursor(first.NextCursor)
+      require.NoError(t, err)
+
+      second, err := topics.List(ctx, &transfer.PageRequest{After: after, Limit: 2})
+      require.NoError(t, err)
+      require.Len(t, second.Items, 2)
+      assert.Equal(t, "Python", second.Items[0].Name)
+      assert.Equal(t, "Rust", second.Items[1].Name)
+      assert.Equal(t, 4, second.Total)
+    })
+
+    t.Run("trash", func(t *testing.T) {
+      for id := range created {
+        require.NoError(t, projects.Remove(ctx, id))
+      }
+
+      ids := walk(t, 3, func(page *transfer.PageRequest) (*transfer.Page[*transfer.TrashedItem], error) {
+        return trash.Get(ctx, page)
+      }, func(item *transfer.TrashedItem) string { return item.ID })
+      assert.Len(t, ids, len(created))
+    })
+
+    t.Run("invalid cursor", func(t *testing.T) {
+      for _, cursor := range []*transfer.Cursor{
+        {ID: "topic-C"},
+        {Key: []string{"yesterday"}, ID: "00000000-0000-0000-0000-000000000000"},
+      } {
+        _, err := projects.List(ctx, false, &transfer.PageRequest{After: cursor, Limit: 2})
+        assertStatus(t, http.StatusBadRequest, err)
+      }
+    })
+  })
+}
@@ -4,8 +4,11 @@ import (
   "context"
   "database/sql"
   "errors"
+  "fontseca.dev/database"
+  "fontseca.dev/metrics"
   "fontseca.dev/model"
   "fontseca.dev/problem"
+  "fontseca.dev/tracing"
   "fontseca.dev/transfer"
   "github.com/google/uuid"
   "log/slog"
@@ -18,6 +21,10 @@ type ProjectsRepository interface {
   // Get retrieves a slice of project types.
   Get(ctx context.Context, archived bool) (projects []*model.Project, err error)
 
+  // List retrieves the page of archived or not archived projects that
+  // follows the cursor of page, most recent first.
+  List(ctx context.Context, archived bool, page *transfer.PageRequest) (projects *transfer.Page[*model.Project], err error)
+
   // GetByID retrieves a project type by its UUID.
   GetByID(ctx context.Context, id string) (project *model.Project, err error)
 
@@ -32,14 +39,24 @@ type ProjectsRepository interface {
   Exists(ctx context.Context, id string) (err error)
 
   // Update modifies an existing project record with the provided update data.
+  // update.Version must be the current version of the record; otherwise a
+  // conflict problem with the current version is returned, or a
+  // precondition required problem if it is zero.
   Update(ctx context.Context, id string, update *transfer.ProjectUpdate) (updated bool, err error)
 
-  // Unarchive makes a project not archived so that it can be normally listed.
-  Unarchive(ctx context.Context, id string) (unarchived bool, err error)
+  // Unarchive makes a project not archived so that it can be normally
+  // listed. version must be the current version of the project, as
+  // with Update.
+  Unarchive(ctx context.Context, id string, version int) (unarchived bool, err error)
 
-  // Remove deletes an existing project type. If not found, returns a not found error.
+  // Remove moves an existing project to the trash, along with its
+  // technology tags. If not found, returns a not found error.
   Remove(ctx context.Context, id string) (err error)
 
+  // Restore brings back a project from the trash. If there is no such
+  // project in the trash, returns a not found error.
+  Restore(ctx context.Context, id string) (err error)
+
   // ContainsTechnologyTag checks whether technologyTagID belongs to projectID.
   ContainsTechnologyTag(ctx context.Context, projectID, technologyTagID string) (success bool, err error)
 
@@ -51,14 +68,57 @@ type ProjectsRepository interface {
 }
 
 type projectsRepository struct {
-  db *sql.DB
+  db *database.DB
 }
 
-func NewProjectsRepository(db *sql.DB) ProjectsRepository {
+func NewProjectsRepository(db *database.DB) ProjectsRepository {
   return &projectsRepository{db}
 }
 
 func (r *projectsRepository) Get(ctx context.Context, archived bool) (projects []*model.Project, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
+  return r.doGet(ctx, archived, "TRUE", "")
+}
+
+func (r *projectsRepository) List(ctx context.Context, archived bool, page *transfer.PageRequest) (projects *transfer.Page[*model.Project], err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
+  condition, args, err := keyset(page, true, timeKey(`p."created_at"`), textKey(`p."uuid"`))
+  if nil != err {
+    return nil, err
+  }
+
+  var query = `
+  SELECT count (*)
+    FROM "project"
+   WHERE "archived" IS NOT DISTINCT FROM @archived
+     AND "deleted_at" IS NULL;`
+  var total int
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+  err = r.db.QueryRowContext(ctx, query, sql.Named("archived", archived)).Scan(&total)
+  if nil != err {
+    slog.ErrorContext(ctx, err.Error())
+    return nil, err
+  }
+
+  items, err := r.doGet(ctx, archived, condition, "LIMIT @limit", args...)
+  if nil != err {
+    return nil, err
+  }
+
+  return newPage(items, page, total, func(p *model.Project) *transfer.Cursor {
+    return &transfer.Cursor{Key: []string{transfer.CursorTime(p.CreatedAt)}, ID: p.UUID.String()}
+  }), nil
+}
+
+// doGet retrieves the projects that satisfy condition, limited by limit.
+func (r *projectsRepository) doGet(ctx context.Context, archived bool, condition, limit string, args ...any) (projects []*model.Project, err error) {
   var query = `
      SELECT p."uuid",
             p."name",
@@ -77,22 +137,26 @@ func (r *projectsRepository) Get(ctx context.Context, archived bool) (projects [
             p."playable",
             p."archived",
             p."finished",
+            p."version",
             p."created_at",
             p."updated_at",
-            group_concat (tt."name")
+            string_agg (tt."name", ',')
        FROM "project" p
   LEFT JOIN "project_technology_tag" ptt
          ON ptt."project_uuid" = p."uuid"
   LEFT JOIN "technology_tag" tt
          ON tt."uuid" = ptt."technology_tag_uuid"
-      WHERE p."archived" IS @archived
+      WHERE p."archived" IS NOT DISTINCT FROM @archived
+        AND p."deleted_at" IS NULL
+        AND ` + condition + `
    GROUP BY p."uuid"
-   ORDER BY p."created_at" DESC;`
+   ORDER BY p."created_at" DESC, p."uuid" DESC
+   ` + limit + `;`
   ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
   defer cancel()
-  rows, err := r.db.QueryContext(ctx, query, sql.Named("archived", archived))
+  rows, err := r.db.QueryContext(ctx, query, append(args, sql.Named("archived", archived))...)
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return nil, err
   }
   defer rows.Close()
@@ -120,11 +184,12 @@ func (r *projectsRepository) Get(ctx context.Context, archived bool) (projects [
       &project.Playable,
       &project.Archived,
       &project.Finished,
+      &project.Version,
       &project.CreatedAt,
       &project.UpdatedAt,
       &tags)
     if nil != err {
-      slog.Error(err.Error())
+      slog.ErrorContext(ctx, err.Error())
       return nil, err
     }
     if nil != tags && "" != *tags {
@@ -154,22 +219,24 @@ func (r *projectsRepository) doGetByID(ctx context.Context, id string, ignoreArc
             p."playable",
             p."archived",
             p."finished",
+            p."version",
             p."created_at",
             p."updated_at",
-            group_concat (tt."name")
+            string_agg (tt."name", ',')
        FROM "project" p
   LEFT JOIN "project_technology_tag" ptt
          ON ptt."project_uuid" = p."uuid"
   LEFT JOIN "technology_tag" tt
          ON tt."uuid" = ptt."technology_tag_uuid"
-      WHERE p."archived" IS @archived
+      WHERE p."archived" IS NOT DISTINCT FROM @archived
         AND p."uuid" = @project_uuid
+        AND p."deleted_at" IS NULL
    GROUP BY p."uuid";`
   ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
   defer cancel()
   var result = r.db.QueryRowContext(ctx, query, sql.Named("project_uuid", id), sql.Named("archived", ignoreArchived))
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return nil, err
   }
   project = new(model.Project)
@@ -192,6 +259,7 @@ func (r *projectsRepository) doGetByID(ctx context.Context, id string, ignoreArc
     &project.Playable,
     &project.Archived,
     &project.Finished,
+    &project.Version,
     &project.CreatedAt,
     &project.UpdatedAt,
     &tags)
@@ -199,7 +267,7 @@ func (r *projectsRepository) doGetByID(ctx context.Context, id string, ignoreArc
     if errors.Is(err, sql.ErrNoRows) {
       err = problem.NewNotFound(id, "project")
     } else {
-      slog.Error(err.Error())
+      slog.ErrorContext(ctx, err.Error())
     }
     return nil, err
   }
@@ -210,10 +278,18 @@ func (r *projectsRepository) doGetByID(ctx context.Context, id string, ignoreArc
 }
 
 func (r *projectsRepository) GetByID(ctx context.Context, id string) (project *model.Project, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
   return r.doGetByID(ctx, id, false)
 }
 
 func (r *projectsRepository) GetBySlug(ctx context.Context, slug string) (project *model.Project, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
   var query = `
      SELECT p."uuid",
             p."name",
@@ -232,9 +308,10 @@ func (r *projectsRepository) GetBySlug(ctx context.Context, slug string) (projec
             p."playable",
             p."archived",
             p."finished",
+            p."version",
             p."created_at",
             p."updated_at",
-            group_concat (tt."name")
+            string_agg (tt."name", ',')
        FROM "project" p
   LEFT JOIN "project_technology_tag" ptt
          ON ptt."project_uuid" = p."uuid"
@@ -242,13 +319,14 @@ func (r *projectsRepository) GetBySlug(ctx context.Context, slug string) (projec
          ON tt."uuid" = ptt."technology_tag_uuid"
       WHERE p."archived" IS FALSE
         AND p."slug" = @slug
+        AND p."deleted_at" IS NULL
    GROUP BY p."uuid"
       LIMIT 1;`
   ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
   defer cancel()
   var result = r.db.QueryRowContext(ctx, query, sql.Named("slug", slug))
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return nil, err
   }
   project = new(model.Project)
@@ -271,6 +349,7 @@ func (r *projectsRepository) GetBySlug(ctx context.Context, slug string) (projec
     &project.Playable,
     &project.Archived,
     &project.Finished,
+    &project.Version,
     &project.CreatedAt,
     &project.UpdatedAt,
     &tags)
@@ -278,7 +357,7 @@ func (r *projectsRepository) GetBySlug(ctx context.Context, slug string) (projec
     if errors.Is(err, sql.ErrNoRows) {
       err = problem.NewSlugNotFound(slug, "project")
     } else {
-      slog.Error(err.Error())
+      slog.ErrorContext(ctx, err.Error())
     }
     return nil, err
   }
@@ -289,9 +368,13 @@ func (r *projectsRepository) GetBySlug(ctx context.Context, slug string) (projec
 }
 
 func (r *projectsRepository) Add(ctx context.Context, creation *transfer.ProjectCreation) (id string, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
   tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return uuid.Nil.String(), err
   }
   defer tx.Rollback()
@@ -338,28 +421,33 @@ func (r *projectsRepository) Add(ctx context.Context, creation *transfer.Project
     sql.Named("collection_url", creation.CollectionURL))
   err = row.Scan(&id)
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return uuid.Nil.String(), err
   }
   if err = tx.Commit(); nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return uuid.Nil.String(), err
   }
   return id, nil
 }
 
 func (r *projectsRepository) Exists(ctx context.Context, id string) (err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
   var query = `
   SELECT count (1)
     FROM "project"
-   WHERE "uuid" = @uuid;`
+   WHERE "uuid" = @uuid
+     AND "deleted_at" IS NULL;`
   ctx, cancel := context.WithTimeout(ctx, time.Second)
   defer cancel()
   var row = r.db.QueryRowContext(ctx, query, sql.Named("uuid", id))
   var exists bool
   err = row.Scan(&exists)
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return err
   }
   if !exists {
@@ -386,10 +474,15 @@ func (r *projectsRepository) nothingToUpdate(current *model.Project, update *tra
     (update.Finished == current.Finished)
 }
 
+// doUpdate checks the version of the project id, which it retrieves in
+// the same transaction, and updates it.
 func (r *projectsRepository) doUpdate(ctx context.Context, id string, update *transfer.ProjectUpdate, ignoreArchived bool) (updated bool, err error) {
+  if 0 == update.Version {
+    return false, problem.NewPreconditionRequired("project")
+  }
   tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return false, err
   }
   defer tx.Rollback()
@@ -397,6 +490,9 @@ func (r *projectsRepository) doUpdate(ctx context.Context, id string, update *tr
   if nil != err {
     return false, err
   }
+  if update.Version != current.Version {
+    return false, problem.NewVersionConflict(id, "project", current.Version)
+  }
   if r.nothingToUpdate(current, update) {
     return false, nil
   }
@@ -409,14 +505,14 @@ func (r *projectsRepository) doUpdate(ctx context.Context, id string, update *tr
          "summary" = coalesce (nullif (@summary, ''), @current_summary),
          "read_time" = CASE WHEN @read_time = @current_read_time
                               OR 0 = @read_time
-                            THEN @current_read_time
-                            ELSE @read_time
+                            THEN CAST(@current_read_time AS INTEGER)
+                            ELSE CAST(@read_time AS INTEGER)
                             END,
          "content" = coalesce (nullif (@content, ''), @current_content),
          "estimated_time" = CASE WHEN @estimated_time = @current_estimated_time
                                    OR 0 = @estimated_time
-                                 THEN @current_estimated_time
-                                 ELSE @estimated_time
+                                 THEN CAST(@current_estimated_time AS INTEGER)
+                                 ELSE CAST(@estimated_time AS INTEGER)
                                   END,
          "first_image_url" = coalesce (nullif (@first_image_url, ''), @current_first_image_url),
          "second_image_url" = coalesce (nullif (@second_image_url, ''), @current_second_image_url),
@@ -426,8 +522,10 @@ func (r *projectsRepository) doUpdate(ctx context.Context, id string, update *tr
          "playable" = @playable,
          "archived" = @archived,
          "finished" = @finished,
-         "updated_at" = current_timestamp
-   WHERE "uuid" = @uuid;`
+         "updated_at" = current_timestamp,
+         "version" = "version" + 1
+   WHERE "uuid" = @uuid
+     AND "version" = @current_version;`
   var playable = current.Playable
   if "" != update.PlaygroundURL {
     var wantsToDefaultPlaygroundURL = "about:blank" == update.PlaygroundURL
@@ -456,62 +554,100 @@ func (r *projectsRepository) doUpdate(ctx context.Context, id string, update *tr
     sql.Named("playground_url", update.PlaygroundURL), sql.Named("current_playground_url", current.PlaygroundURL),
     sql.Named("playable", playable),
     sql.Named("archived", update.Archived),
-    sql.Named("finished", update.Finished))
+    sql.Named("finished", update.Finished),
+    sql.Named("current_version", current.Version))
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return false, err
   }
   var affected, _ = result.RowsAffected()
   if 1 != affected {
-    return false, nil
+    // Another update changed the version since it was retrieved.
+    latest, err := r.doGetByID(ctx, id, ignoreArchived)
+    if nil != err {
+      return false, err
+    }
+    return false, problem.NewVersionConflict(id, "project", latest.Version)
   }
   if err = tx.Commit(); nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return false, err
   }
   return true, nil
 }
 
 func (r *projectsRepository) Update(ctx context.Context, id string, update *transfer.ProjectUpdate) (updated bool, err error) {
-  return r.doUpdate(ctx, id, update, false)
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
+  err = r.db.Transact(ctx, func(ctx context.Context) (err error) {
+    updated, err = r.doUpdate(ctx, id, update, false)
+    return err
+  })
+
+  return updated && nil == err, err
 }
 
-func (r *projectsRepository) Unarchive(ctx context.Context, id string) (updated bool, err error) {
-  return r.doUpdate(ctx, id, &transfer.ProjectUpdate{Archived: false}, true)
+func (r *projectsRepository) Unarchive(ctx context.Context, id string, version int) (updated bool, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
+  err = r.db.Transact(ctx, func(ctx context.Context) (err error) {
+    updated, err = r.doUpdate(ctx, id, &transfer.ProjectUpdate{Archived: false, Version: version}, true)
+    return err
+  })
+
+  return updated && nil == err, err
 }
 
 func (r *projectsRepository) Remove(ctx context.Context, id string) (err error) {
-  tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
-  if nil != err {
-    slog.Error(err.Error())
-    return err
-  }
-  defer tx.Rollback()
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
 
-  // Remove technology tags associated with the project to remove.
+  // The technology tags of the project are kept for it to have them
+  // back if it is restored, and removed when it is purged.
 
   var query = `
-  DELETE FROM "project_technology_tag"
-        WHERE "project_uuid" = @project_uuid`
+  UPDATE "project"
+     SET "deleted_at" = current_timestamp
+   WHERE "uuid" = @uuid
+     AND "deleted_at" IS NULL;`
   ctx, cancel := context.WithTimeout(ctx, time.Second)
   defer cancel()
-  _, err = tx.ExecContext(ctx, query, sql.Named("project_uuid", id))
+
+  result, err := r.db.ExecContext(ctx, query, sql.Named("uuid", id))
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return err
   }
 
-  // Remove the actual project.
+  affected, _ := result.RowsAffected()
+  if 1 != affected {
+    return problem.NewNotFound(id, "project")
+  }
+
+  return nil
+}
+
+func (r *projectsRepository) Restore(ctx context.Context, id string) (err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
 
-  query = `
-  DELETE FROM "project"
-        WHERE "uuid" = @uuid;`
-  ctx, cancel = context.WithTimeout(ctx, time.Second)
+  var query = `
+  UPDATE "project"
+     SET "deleted_at" = NULL
+   WHERE "uuid" = @uuid
+     AND "deleted_at" IS NOT NULL;`
+  ctx, cancel := context.WithTimeout(ctx, time.Second)
   defer cancel()
 
-  result, err := tx.ExecContext(ctx, query, sql.Named("uuid", id))
+  result, err := r.db.ExecContext(ctx, query, sql.Named("uuid", id))
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return err
   }
 
@@ -520,15 +656,14 @@ func (r *projectsRepository) Remove(ctx context.Context, id string) (err error)
     return problem.NewNotFound(id, "project")
   }
 
-  if err = tx.Commit(); nil != err {
-    slog.Error(err.Error())
-    return err
-  }
-
   return nil
 }
 
 func (r *projectsRepository) ContainsTechnologyTag(ctx context.Context, projectID, technologyTagID string) (success bool, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
   var query = `
   SELECT count (1)
     FROM "project_technology_tag" ptt
@@ -538,16 +673,20 @@ func (r *projectsRepository) ContainsTechnologyTag(ctx context.Context, projectI
     sql.Named("project_uuid", projectID), sql.Named("technology_tag_uuid", technologyTagID))
   err = result.Scan(&success)
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return false, err
   }
   return success, nil
 }
 
 func (r *projectsRepository) AddTechnologyTag(ctx context.Context, projectID, technologyTagID string) (added bool, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
   tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return false, err
   }
   defer tx.Rollback()
@@ -562,7 +701,7 @@ func (r *projectsRepository) AddTechnologyTag(ctx context.Context, projectID, te
     sql.Named("project_uuid", projectID),
     sql.Named("technology_tag_uuid", technologyTagID))
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return false, err
   }
   affected, _ := result.RowsAffected()
@@ -576,9 +715,13 @@ func (r *projectsRepository) AddTechnologyTag(ctx context.Context, projectID, te
 }
 
 func (r *projectsRepository) RemoveTechnologyTag(ctx context.Context, projectID, technologyTagID string) (removed bool, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
   tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return false, err
   }
   defer tx.Rollback()
@@ -591,7 +734,7 @@ func (r *projectsRepository) RemoveTechnologyTag(ctx context.Context, projectID,
   result, err := tx.ExecContext(ctx, query,
     sql.Named("project_uuid", projectID), sql.Named("technology_tag_uuid", technologyTagID))
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return false, err
   }
   affected, _ := result.RowsAffected()
@@ -0,0 +1,102 @@
+package repository
+
+import (
+  "context"
+  "fontseca.dev/database"
+  "fontseca.dev/transfer"
+  "github.com/stretchr/testify/assert"
+  "github.com/stretchr/testify/require"
+  "net/http"
+  "testing"
+)
+
+func TestProjectsRepository(t *testing.T) {
+  forEachBackend(t, func(t *testing.T, db *database.DB) {
+    var (
+      ctx      = context.Background()
+      projects = NewProjectsRepository(db)
+    )
+
+    id, err := projects.Add(ctx, &transfer.ProjectCreation{
+      Name:          "Site",
+      Slug:          "site",
+      Summary:       "A summary.",
+      Content:       "Content.",
+      ReadTime:      1,
+      EstimatedTime: 4,
+    })
+    require.NoError(t, err)
+    require.NoError(t, projects.Exists(ctx, id))
+
+    technology, err := NewTechnologyTagRepository(db).Add(ctx, &transfer.TechnologyTagCreation{Name: "Go"})
+    require.NoError(t, err)
+    added, err := projects.AddTechnologyTag(ctx, id, technology)
+    require.NoError(t, err)
+    assert.True(t, added)
+    contains, err := projects.ContainsTechnologyTag(ctx, id, technology)
+    require.NoError(t, err)
+    assert.True(t, contains)
+
+    project, err := projects.GetBySlug(ctx, "site")
+    require.NoError(t, err)
+    assert.Equal(t, id, project.UUID.String())
+    assert.Equal(t, []string{"Go"}, project.TechnologyTags)
+    require.NotNil(t, project.EstimatedTime)
+    assert.Equal(t, 4, *project.EstimatedTime)
+    assert.Equal(t, 1, project.Version)
+
+    updated, err := projects.Update(ctx, id, &transfer.ProjectUpdate{Name: "Website", EstimatedTime: 6, Version: 1})
+    require.NoError(t, err)
+    assert.True(t, updated)
+
+    project, err = projects.GetByID(ctx, id)
+    require.NoError(t, err)
+    assert.Equal(t, "Website", project.Name)
+    assert.Equal(t, "site", project.Slug)
+    require.NotNil(t, project.EstimatedTime)
+    assert.Equal(t, 6, *project.EstimatedTime)
+    assert.Equal(t, 2, project.Version)
+
+    updated, err = projects.Update(ctx, id, &transfer.ProjectUpdate{Name: "Stale", Version: 1})
+    assertStatus(t, http.StatusConflict, err)
+    assert.False(t, updated)
+
+    updated, err = projects.Update(ctx, id, &transfer.ProjectUpdate{Name: "Unversioned"})
+    assertStatus(t, http.StatusPreconditionRequired, err)
+    assert.False(t, updated)
+
+    updated, err = projects.Update(ctx, id, &transfer.ProjectUpdate{Archived: true, Version: 2})
+    require.NoError(t, err)
+    assert.True(t, updated)
+
+    current, err := projects.Get(ctx, false)
+    require.NoError(t, err)
+    assert.Empty(t, current)
+    archived, err := projects.Get(ctx, true)
+    require.NoError(t, err)
+    require.Len(t, archived, 1)
+    assert.Equal(t, id, archived[0].UUID.String())
+
+    updated, err = projects.Unarchive(ctx, id, 0)
+    assertStatus(t, http.StatusPreconditionRequired, err)
+    assert.False(t, updated)
+
+    updated, err = projects.Unarchive(ctx, id, 3)
+    require.NoError(t, err)
+    assert.True(t, updated)
+
+    removed, err := projects.RemoveTechnologyTag(ctx, id, technology)
+    require.NoError(t, err)
+    assert.True(t, removed)
+
+    require.NoError(t, projects.Remove(ctx, id))
+    assertStatus(t, http.StatusNotFound, projects.Exists(ctx, id))
+    _, err = projects.GetByID(ctx, id)
+    assertStatus(t, http.StatusNotFound, err)
+
+    require.NoError(t, projects.Restore(ctx, id))
+    project, err = projects.GetByID(ctx, id)
+    require.NoError(t, err)
+    assert.Empty(t, project.TechnologyTags)
+  })
+}
@@ -0,0 +1,292 @@
+package repository
+
+import (
+  "context"
+  "fmt"
+  "fontseca.dev/database"
+  "fontseca.dev/transfer"
+  "github.com/google/uuid"
+  "github.com/stretchr/testify/assert"
+  "github.com/stretchr/testify/require"
+  "net/http"
+  "os"
+  "strings"
+  "testing"
+  "time"
+)
+
+// forEachBackend runs test against every database the repositories
+// can store their data in, each time with a new database that has the
+// whole schema and a registered owner: an in-memory SQLite database,
+// and a PostgreSQL one when POSTGRES_TEST_URL names a server to create
+// it in, as a schema of its own that is dropped once test ends.
+func forEachBackend(t *testing.T, test func(t *testing.T, db *database.DB)) {
+  t.Run("sqlite", func(t *testing.T) {
+    db, err := database.Open(database.SQLiteDriver, ":memory:", 1, 0)
+    require.NoError(t, err)
+    t.Cleanup(func() { db.Close() })
+
+    test(t, prepare(t, db))
+  })
+
+  t.Run("postgres", func(t *testing.T) {
+    var url = os.Getenv("POSTGRES_TEST_URL")
+    if "" == url {
+      t.Skip("POSTGRES_TEST_URL is not set")
+    }
+
+    admin, err := database.OpenPostgres(url, 1)
+    require.NoError(t, err)
+    t.Cleanup(func() { admin.Close() })
+
+    var schema = "test_" + strings.ReplaceAll(uuid.NewString(), "-", "")
+
+    _, err = admin.ExecContext(context.Background(), fmt.Sprintf(`CREATE SCHEMA %q;`, schema))
+    require.NoError(t, err)
+    t.Cleanup(func() {
+      admin.ExecContext(context.Background(), fmt.Sprintf(`DROP SCHEMA %q CASCADE;`, schema))
+    })
+
+    db, err := database.OpenPostgres(withOption(url, "search_path", schema), 4)
+    require.NoError(t, err)
+    t.Cleanup(func() { db.Close() })
+
+    test(t, prepare(t, db))
+  })
+}
+
+// prepare creates the schema of db and registers its owner.
+func prepare(t *testing.T, db *database.DB) *database.DB {
+  require.NoError(t, database.Migrate(context.Background(), db))
+  NewMeRepository(db).Register(context.Background())
+  return db
+}
+
+// withOption adds the option key with value to the PostgreSQL
+// connection string url, in either of its forms.
+func withOption(url, key, value string) string {
+  switch {
+  case !strings.Contains(url, "://"):
+    return url + " " + key + "=" + value
+  case strings.Contains(url, "?"):
+    return url + "&" + key + "=" + value
+  default:
+    return url + "?" + key + "=" + value
+  }
+}
+
+func TestTagsRepository(t *testing.T) {
+  forEachBackend(t, func(t *testing.T, db *database.DB) {
+    var (
+      ctx     = context.Background()
+      tags    = NewTagsRepository(db)
+      archive = newArchive(t, db)
+    )
+
+    require.NoError(t, tags.Add(ctx, &transfer.TagCreation{ID: "go", Name: "Go"}))
+    require.NoError(t, tags.Update(ctx, "go", &transfer.TagUpdate{ID: "golang", Name: "Golang"}))
+
+    got, err := tags.Get(ctx)
+    require.NoError(t, err)
+    require.Len(t, got, 1)
+    assert.Equal(t, "golang", got[0].ID)
+    assert.Equal(t, "Golang", got[0].Name)
+
+    id, err := archive.Draft(ctx, &transfer.ArticleCreation{Title: "Tagged", Slug: "tagged", Content: "Content."})
+    require.NoError(t, err)
+    require.NoError(t, archive.AddTag(ctx, id, "golang", true))
+
+    require.NoError(t, tags.Remove(ctx, "golang"))
+
+    draft, err := archive.GetByID(ctx, id, true)
+    require.NoError(t, err)
+    assert.Empty(t, draft.Tags)
+
+    got, err = tags.Get(ctx)
+    require.NoError(t, err)
+    assert.Empty(t, got)
+
+    require.NoError(t, tags.Restore(ctx, "golang"))
+    assertStatus(t, http.StatusNotFound, tags.Restore(ctx, "golang"))
+
+    draft, err = archive.GetByID(ctx, id, true)
+    require.NoError(t, err)
+    require.Len(t, draft.Tags, 1)
+    assert.Equal(t, "golang", draft.Tags[0].ID)
+  })
+}
+
+func TestTopicsRepository(t *testing.T) {
+  forEachBackend(t, func(t *testing.T, db *database.DB) {
+    var (
+      ctx     = context.Background()
+      topics  = NewTopicsRepository(db)
+      archive = newArchive(t, db)
+    )
+
+    require.NoError(t, topics.Add(ctx, &transfer.TopicCreation{ID: "databases", Name: "Databases"}))
+
+    id, err := archive.Draft(ctx, &transfer.ArticleCreation{Title: "On topic", Slug: "on-topic", Content: "Content."})
+    require.NoError(t, err)
+    require.NoError(t, archive.Revise(ctx, id, &transfer.ArticleRevision{Topic: "databases", Version: 1}))
+
+    draft, err := archive.GetByID(ctx, id, true)
+    require.NoError(t, err)
+    require.NotNil(t, draft.Topic)
+    assert.Equal(t, "databases", draft.Topic.ID)
+
+    published, err := archive.Draft(ctx, &transfer.ArticleCreation{Title: "Published", Slug: "published", Content: "Content."})
+    require.NoError(t, err)
+    require.NoError(t, archive.Revise(ctx, published, &transfer.ArticleRevision{Topic: "databases", Version: 1}))
+    require.NoError(t, archive.Publish(ctx, published))
+
+    require.NoError(t, topics.Remove(ctx, "databases"))
+    assertStatus(t, http.StatusNotFound, topics.Remove(ctx, "databases"))
+
+    got, err := topics.Get(ctx)
+    require.NoError(t, err)
+    assert.Empty(t, got)
+
+    // The draft is detached from the topic, so that it cannot be
+    // published under it.
+    draft, err = archive.GetByID(ctx, id, true)
+    require.NoError(t, err)
+    assert.Nil(t, draft.Topic)
+    assertStatus(t, http.StatusBadRequest, archive.Publish(ctx, id))
+
+    // The published article stays filed under it until it is purged.
+    article, err := archive.GetByID(ctx, published, false)
+    require.NoError(t, err)
+    require.NotNil(t, article.Topic)
+    assert.Equal(t, "databases", article.Topic.ID)
+
+    err = archive.Revise(ctx, id, &transfer.ArticleRevision{Topic: "databases", Version: 2})
+    assertStatus(t, http.StatusNotFound, err)
+
+    require.NoError(t, topics.Restore(ctx, "databases"))
+    require.NoError(t, archive.Revise(ctx, id, &transfer.ArticleRevision{Topic: "databases", Version: 2}))
+  })
+}
+
+func TestTagsAndTopicsRepository_RenameInUse(t *testing.T) {
+  forEachBackend(t, func(t *testing.T, db *database.DB) {
+    var (
+      ctx     = context.Background()
+      tags    = NewTagsRepository(db)
+      topics  = NewTopicsRepository(db)
+      archive = newArchive(t, db)
+    )
+
+    require.NoError(t, topics.Add(ctx, &transfer.TopicCreation{ID: "go", Name: "Go"}))
+    require.NoError(t, tags.Add(ctx, &transfer.TagCreation{ID: "sql", Name: "SQL"}))
+
+    var id = publish(t, archive, "In use", "in-use", "go")
+    require.NoError(t, archive.AddTag(ctx, id, "sql"))
+    patch, err := archive.Amend(ctx, id)
+    require.NoError(t, err)
+    require.NoError(t, archive.Revise(ctx, patch, &transfer.ArticleRevision{Topic: "go", Version: 1}))
+
+    require.NoError(t, topics.Update(ctx, "go", &transfer.TopicUpdate{ID: "golang", Name: "Golang"}))
+    require.NoError(t, tags.Update(ctx, "sql", &transfer.TagUpdate{ID: "databases", Name: "Databases"}))
+
+    article, err := archive.GetByID(ctx, id, false)
+    require.NoError(t, err)
+    require.NotNil(t, article.Topic)
+    assert.Equal(t, "golang", article.Topic.ID)
+    assert.Equal(t, "Golang", article.Topic.Name)
+    require.Len(t, article.Tags, 1)
+    assert.Equal(t, "databases", article.Tags[0].ID)
+
+    got, err := archive.GetPatch(ctx, patch)
+    require.NoError(t, err)
+    require.NotNil(t, got.TopicID)
+    assert.Equal(t, "golang", *got.TopicID)
+
+    // The old IDs are gone and can be taken again.
+    assertStatus(t, http.StatusNotFound, topics.Update(ctx, "go", &transfer.TopicUpdate{ID: "go", Name: "Go"}))
+    require.NoError(t, topics.Add(ctx, &transfer.TopicCreation{ID: "go", Name: "Go"}))
+
+    // Renaming without changing the ID keeps the references as they are.
+    require.NoError(t, tags.Update(ctx, "databases", &transfer.TagUpdate{ID: "databases", Name: "DATABASES"}))
+    article, err = archive.GetByID(ctx, id, false)
+    require.NoError(t, err)
+    require.Len(t, article.Tags, 1)
+    assert.Equal(t, "DATABASES", article.Tags[0].Name)
+  })
+}
+
+func TestArchiveRepository_Get(t *testing.T) {
+  forEachBackend(t, func(t *testing.T, db *database.DB) {
+    var (
+      ctx     = context.Background()
+      archive = newArchive(t, db)
+      now     = time.Now().UTC()
+    )
+
+    require.NoError(t, NewTopicsRepository(db).Add(ctx, &transfer.TopicCreation{ID: "go", Name: "Go"}))
+
+    for _, title := range []string{"Hello World", "Goodbye World"} {
+      id, err := archive.Draft(ctx, &transfer.ArticleCreation{Title: title, Slug: strings.ToLower(title), Content: "Content."})
+      require.NoError(t, err)
+      require.NoError(t, archive.Revise(ctx, id, &transfer.ArticleRevision{Topic: "go", Version: 1}))
+      require.NoError(t, archive.Publish(ctx, id))
+    }
+
+    publications, err := archive.Publications(ctx)
+    require.NoError(t, err)
+    require.Len(t, publications, 1)
+    assert.Equal(t, now.Year(), publications[0].Year)
+    assert.Equal(t, now.Month(), publications[0].Month)
+
+    var filter = &transfer.ArticleFilter{
+      Publication: &transfer.Publication{Year: now.Year(), Month: now.Month()},
+      Page:        1,
+      RPP:         10,
+    }
+
+    articles, err := archive.Get(ctx, filter, false, false)
+    require.NoError(t, err)
+    assert.Len(t, articles, 2)
+
+    filter.Publication.Year--
+    articles, err = archive.Get(ctx, filter, false, false)
+    require.NoError(t, err)
+    assert.Empty(t, articles)
+
+    filter.Publication = nil
+    filter.Search = "hello"
+    articles, err = archive.Get(ctx, filter, false, false)
+    require.NoError(t, err)
+    require.Len(t, articles, 1)
+    assert.Equal(t, "Hello World", articles[0].Title)
+
+    filter.Search = ""
+    filter.RPP = 1
+    first, err := archive.Get(ctx, filter, false, false)
+    require.NoError(t, err)
+    require.Len(t, first, 1)
+
+    filter.After = first[0].Cursor()
+    second, err := archive.Get(ctx, filter, false, false)
+    require.NoError(t, err)
+    require.Len(t, second, 1)
+    assert.NotEqual(t, first[0].UUID, second[0].UUID)
+
+    filter.After, filter.Before = nil, second[0].Cursor()
+    previous, err := archive.Get(ctx, filter, false, false)
+    require.NoError(t, err)
+    require.Len(t, previous, 1)
+    assert.Equal(t, first[0].UUID, previous[0].UUID)
+    filter.After, filter.Before = first[0].Cursor(), nil
+
+    // The cursor keeps working once its article is gone for good.
+    require.NoError(t, archive.Remove(ctx, first[0].UUID.String()))
+    _, err = NewTrashRepository(db).Purge(ctx, time.Now().Add(time.Minute))
+    require.NoError(t, err)
+
+    again, err := archive.Get(ctx, filter, false, false)
+    require.NoError(t, err)
+    require.Len(t, again, 1)
+    assert.Equal(t, second[0].UUID, again[0].UUID)
+  })
+}
@@ -0,0 +1,351 @@
+package repository
+
+import (
+  "context"
+  "database/sql"
+  "fmt"
+  "fontseca.dev/database"
+  "fontseca.dev/metrics"
+  "fontseca.dev/problem"
+  "fontseca.dev/tracing"
+  "fontseca.dev/transfer"
+  "log/slog"
+  "net/http"
+  "slices"
+  "time"
+)
+
+// SiteRepository is a low level API that reads and writes the whole
+// content of the site at once, for backups and migrations between
+// installations.
+type SiteRepository interface {
+  // Dump retrieves every record of every table that holds content of
+  // the site. Shared links are not included since they are temporary.
+  Dump(ctx context.Context) (dump *transfer.SiteDump, err error)
+
+  // Restore replaces the content of the site with the content of dump.
+  Restore(ctx context.Context, dump *transfer.SiteDump) error
+}
+
+type siteRepository struct {
+  db *database.DB
+}
+
+func NewSiteRepository(db *database.DB) SiteRepository {
+  return &siteRepository{db}
+}
+
+// dumpTable retrieves every row of table as a record, ordered by the
+// given columns. Times are written in UTC using the same layout SQLite
+// uses for CURRENT_TIMESTAMP.
+func dumpTable(ctx context.Context, tx *database.Tx, table, orderBy string) (records []transfer.Record, err error) {
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+
+  rows, err := tx.QueryContext(ctx, fmt.Sprintf(`SELECT * FROM %q ORDER BY %s;`, table, orderBy))
+  if nil != err {
+    slog.ErrorContext(ctx, err.Error())
+    return nil, err
+  }
+
+  defer rows.Close()
+
+  columns, err := rows.Columns()
+  if nil != err {
+    slog.ErrorContext(ctx, err.Error())
+    return nil, err
+  }
+
+  records = make([]transfer.Record, 0)
+
+  for rows.Next() {
+    var values = make([]any, len(columns))
+    var pointers = make([]any, len(columns))
+
+    for i := range values {
+      pointers[i] = &values[i]
+    }
+
+    if err = rows.Scan(pointers...); nil != err {
+      slog.ErrorContext(ctx, err.Error())
+      return nil, err
+    }
+
+    var record = make(transfer.Record, len(columns))
+
+    for i, column := range columns {
+      switch value := values[i].(type) {
+      case time.Time:
+        record[column] = value.UTC().Format(time.DateTime)
+      case []byte:
+        record[column] = string(value)
+      default:
+        record[column] = value
+      }
+    }
+
+    records = append(records, record)
+  }
+
+  if err = rows.Err(); nil != err {
+    slog.ErrorContext(ctx, err.Error())
+    return nil, err
+  }
+
+  return records, nil
+}
+
+// dumpRelation retrieves the pairs of a many-to-many table as a map
+// from every key in column to the values of other related to it.
+func dumpRelation(ctx context.Context, tx *database.Tx, table, column, other string) (relation map[any][]any, err error) {
+  records, err := dumpTable(ctx, tx, table, fmt.Sprintf("%q, %q", column, other))
+  if nil != err {
+    return nil, err
+  }
+
+  relation = make(map[any][]any)
+
+  for _, record := range records {
+    relation[record[column]] = append(relation[record[column]], record[other])
+  }
+
+  return relation, nil
+}
+
+func (r *siteRepository) Dump(ctx context.Context) (dump *transfer.SiteDump, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
+  tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable, ReadOnly: true})
+  if nil != err {
+    slog.ErrorContext(ctx, err.Error())
+    return nil, err
+  }
+
+  defer tx.Rollback()
+
+  dump = &transfer.SiteDump{}
+
+  me, err := dumpTable(ctx, tx, "me", `"username"`)
+  if nil != err {
+    return nil, err
+  }
+
+  if 0 < len(me) {
+    dump.Me = me[0]
+  }
+
+  if dump.Experience, err = dumpTable(ctx, tx, "experience", `"starts", "uuid"`); nil != err {
+    return nil, err
+  }
+
+  if dump.Technologies, err = dumpTable(ctx, tx, "technology_tag", `"name", "uuid"`); nil != err {
+    return nil, err
+  }
+
+  if dump.Projects, err = dumpTable(ctx, tx, "project", `"created_at", "uuid"`); nil != err {
+    return nil, err
+  }
+
+  technologies, err := dumpRelation(ctx, tx, "project_technology_tag", "project_uuid", "technology_tag_uuid")
+  if nil != err {
+    return nil, err
+  }
+
+  for _, project := range dump.Projects {
+    project["technology_tags"] = append([]any{}, technologies[project["uuid"]]...)
+  }
+
+  if dump.Topics, err = dumpTable(ctx, tx, "topic", `"id"`); nil != err {
+    return nil, err
+  }
+
+  if dump.Tags, err = dumpTable(ctx, tx, "tag", `"id"`); nil != err {
+    return nil, err
+  }
+
+  if dump.Articles, err = dumpTable(ctx, tx, "article", `"drafted_at", "uuid"`); nil != err {
+    return nil, err
+  }
+
+  tags, err := dumpRelation(ctx, tx, "article_tag", "article_uuid", "tag_id")
+  if nil != err {
+    return nil, err
+  }
+
+  for _, article := range dump.Articles {
+    article["tags"] = append([]any{}, tags[article["uuid"]]...)
+  }
+
+  if dump.Patches, err = dumpTable(ctx, tx, "article_patch", `"created_at", "uuid"`); nil != err {
+    return nil, err
+  }
+
+  return dump, nil
+}
+
+// restoreTable inserts records into table. Only the keys of a record
+// that are columns of table are inserted; any other key is ignored.
+func restoreTable(ctx context.Context, tx *database.Tx, table string, records ...transfer.Record) error {
+  if 0 == len(records) {
+    return nil
+  }
+
+  ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+  defer cancel()
+
+  rows, err := tx.QueryContext(ctx, fmt.Sprintf(`SELECT * FROM %q LIMIT 0;`, table))
+  if nil != err {
+    slog.ErrorContext(ctx, err.Error())
+    return err
+  }
+
+  existing, err := rows.Columns()
+  rows.Close()
+  if nil != err {
+    slog.ErrorContext(ctx, err.Error())
+    return err
+  }
+
+  var known = make(map[string]bool)
+
+  for _, column := range existing {
+    known[column] = true
+  }
+
+  for _, record := range records {
+    var columns = make([]string, 0, len(record))
+
+    for column := range record {
+      if known[column] {
+        columns = append(columns, column)
+      }
+    }
+
+    if 0 == len(columns) {
+      continue
+    }
+
+    slices.Sort(columns)
+
+    var names, placeholders string
+    var values = make([]any, 0, len(columns))
+
+    for i, column := range columns {
+      if 0 < i {
+        names += ", "
+        placeholders += ", "
+      }
+
+      names += fmt.Sprintf("%q", column)
+      placeholders += fmt.Sprintf("@p%d", i)
+      values = append(values, sql.Named(fmt.Sprintf("p%d", i), record[column]))
+    }
+
+    query := fmt.Sprintf(`INSERT INTO %q (%s) VALUES (%s);`, table, names, placeholders)
+
+    if _, err = tx.ExecContext(ctx, query, values...); nil != err {
+      slog.ErrorContext(ctx, err.Error())
+      p := problem.Problem{}
+      p.Status(http.StatusUnprocessableEntity)
+      p.Title("Could not restore record.")
+      p.Detail(fmt.Sprintf("A record of table '%s' could not be restored: %v.", table, err))
+      p.With("table", table)
+      return &p
+    }
+  }
+
+  return nil
+}
+
+// restoreRelation inserts the pairs of a many-to-many table from the
+// list of related values that every record keeps under key.
+func restoreRelation(ctx context.Context, tx *database.Tx, table, column, other, key, id string, records []transfer.Record) error {
+  var pairs = make([]transfer.Record, 0)
+
+  for _, record := range records {
+    related, _ := record[key].([]any)
+
+    for _, value := range related {
+      pairs = append(pairs, transfer.Record{column: record[id], other: value})
+    }
+  }
+
+  return restoreTable(ctx, tx, table, pairs...)
+}
+
+func (r *siteRepository) Restore(ctx context.Context, dump *transfer.SiteDump) error {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
+  tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+  if nil != err {
+    slog.ErrorContext(ctx, err.Error())
+    return err
+  }
+
+  defer tx.Rollback()
+
+  var tables = []string{
+    "article_link",
+    "article_tag",
+    "article_patch",
+    "article",
+    "tag",
+    "topic",
+    "project_technology_tag",
+    "project",
+    "technology_tag",
+    "experience",
+  }
+
+  if nil != dump.Me {
+    tables = append(tables, "me")
+  }
+
+  for _, table := range tables {
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    _, err = tx.ExecContext(ctx, fmt.Sprintf(`DELETE FROM %q;`, table))
+    cancel()
+
+    if nil != err {
+      slog.ErrorContext(ctx, err.Error())
+      return err
+    }
+  }
+
+  if nil != dump.Me {
+    if err = restoreTable(ctx, tx, "me", dump.Me); nil != err {
+      return err
+    }
+  }
+
+  var steps = []func() error{
+    func() error { return restoreTable(ctx, tx, "experience", dump.Experience...) },
+    func() error { return restoreTable(ctx, tx, "technology_tag", dump.Technologies...) },
+    func() error { return restoreTable(ctx, tx, "project", dump.Projects...) },
+    func() error {
+      return restoreRelation(ctx, tx, "project_technology_tag", "project_uuid", "technology_tag_uuid", "technology_tags", "uuid", dump.Projects)
+    },
+    func() error { return restoreTable(ctx, tx, "topic", dump.Topics...) },
+    func() error { return restoreTable(ctx, tx, "tag", dump.Tags...) },
+    func() error { return restoreTable(ctx, tx, "article", dump.Articles...) },
+    func() error { return restoreRelation(ctx, tx, "article_tag", "article_uuid", "tag_id", "tags", "uuid", dump.Articles) },
+    func() error { return restoreTable(ctx, tx, "article_patch", dump.Patches...) },
+  }
+
+  for _, step := range steps {
+    if err = step(); nil != err {
+      return err
+    }
+  }
+
+  if err = tx.Commit(); nil != err {
+    slog.ErrorContext(ctx, err.Error())
+    return err
+  }
+
+  return nil
+}
@@ -3,8 +3,12 @@ package repository
 import (
   "context"
   "database/sql"
+  "errors"
+  "fontseca.dev/database"
+  "fontseca.dev/metrics"
   "fontseca.dev/model"
   "fontseca.dev/problem"
+  "fontseca.dev/tracing"
   "fontseca.dev/transfer"
   "log/slog"
   "net/http"
@@ -14,28 +18,42 @@ import (
 // TagsRepository is a low level API that provides methods for interacting
 // with tags in the database.
 type TagsRepository interface {
-  // Add adds a new tag.
+  // Add adds a new tag. It fails with a conflict if the ID is taken,
+  // even by a tag in the trash.
   Add(ctx context.Context, creation *transfer.TagCreation) error
 
   // Get retrieves all the tags.
   Get(ctx context.Context) (tags []*model.Tag, err error)
 
+  // List retrieves the page of tags that follows the cursor of page,
+  // sorted by name.
+  List(ctx context.Context, page *transfer.PageRequest) (tags *transfer.Page[*model.Tag], err error)
+
   // Update updates an existing tag.
   Update(ctx context.Context, id string, update *transfer.TagUpdate) error
 
-  // Remove removes a tag and detaches it from any article that uses it.
+  // Remove moves a tag to the trash. The articles that use it stop
+  // showing it, and have it back if it is restored; it is detached
+  // from them once it is purged.
   Remove(ctx context.Context, id string) error
+
+  // Restore brings back a tag from the trash.
+  Restore(ctx context.Context, id string) error
 }
 
 type tagsRepository struct {
-  db *sql.DB
+  db *database.DB
 }
 
-func NewTagsRepository(db *sql.DB) TagsRepository {
+func NewTagsRepository(db *database.DB) TagsRepository {
   return &tagsRepository{db}
 }
 
 func (r *tagsRepository) Add(ctx context.Context, creation *transfer.TagCreation) error {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
   tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
   if nil != err {
     return err
@@ -43,20 +61,50 @@ func (r *tagsRepository) Add(ctx context.Context, creation *transfer.TagCreation
 
   defer tx.Rollback()
 
+  // A trashed tag keeps its ID until it is purged, so adding it again
+  // would collide with it.
+  getExistingQuery := `
+  SELECT "deleted_at" IS NOT NULL
+    FROM "tag"
+   WHERE "id" = @id;`
+
+  ctx1, cancel := context.WithTimeout(ctx, 3*time.Second)
+  defer cancel()
+
+  var trashed bool
+
+  err = tx.QueryRowContext(ctx1, getExistingQuery, sql.Named("id", creation.ID)).Scan(&trashed)
+
+  switch {
+  case nil == err && trashed:
+    return problem.NewInTrash(creation.ID, "tag")
+  case nil == err:
+    p := &problem.Problem{}
+    p.Status(http.StatusConflict)
+    p.Title("Could not create tag.")
+    p.Detail("This tag is already registered.")
+    p.With("tag_id", creation.ID)
+
+    return p
+  case !errors.Is(err, sql.ErrNoRows):
+    slog.ErrorContext(ctx, err.Error())
+    return err
+  }
+
   addTagQuery := `
   INSERT INTO "tag" ("id", "name")
                VALUES (@id, @name);`
 
-  ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+  ctx1, cancel = context.WithTimeout(ctx, 3*time.Second)
   defer cancel()
 
-  result, err := tx.ExecContext(ctx, addTagQuery,
+  result, err := tx.ExecContext(ctx1, addTagQuery,
     sql.Named("id", creation.ID),
     sql.Named("name", creation.Name),
   )
 
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return err
   }
 
@@ -70,7 +118,7 @@ func (r *tagsRepository) Add(ctx context.Context, creation *transfer.TagCreation
   }
 
   if err = tx.Commit(); nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return err
   }
 
@@ -78,20 +126,70 @@ func (r *tagsRepository) Add(ctx context.Context, creation *transfer.TagCreation
 }
 
 func (r *tagsRepository) Get(ctx context.Context) (tags []*model.Tag, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
+  return r.doGet(ctx, "TRUE", "")
+}
+
+func (r *tagsRepository) List(ctx context.Context, page *transfer.PageRequest) (tags *transfer.Page[*model.Tag], err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
+  name := textKey(`lower ("name")`)
+  name.param = "lower (%s)"
+
+  condition, args, err := keyset(page, false, name, textKey(`"id"`))
+  if nil != err {
+    return nil, err
+  }
+
+  countTagsQuery := `
+  SELECT count (*)
+    FROM "tag"
+   WHERE "deleted_at" IS NULL;`
+
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+
+  var total int
+
+  if err = r.db.QueryRowContext(ctx, countTagsQuery).Scan(&total); nil != err {
+    slog.ErrorContext(ctx, err.Error())
+    return nil, err
+  }
+
+  items, err := r.doGet(ctx, condition, "LIMIT @limit", args...)
+  if nil != err {
+    return nil, err
+  }
+
+  return newPage(items, page, total, func(tag *model.Tag) *transfer.Cursor {
+    return &transfer.Cursor{Key: []string{tag.Name}, ID: tag.ID}
+  }), nil
+}
+
+// doGet retrieves the tags that satisfy condition, limited by limit.
+func (r *tagsRepository) doGet(ctx context.Context, condition, limit string, args ...any) (tags []*model.Tag, err error) {
   getTagsQuery := `
   SELECT "id",
          "name",
          "created_at",
          "updated_at"
     FROM "tag"
-ORDER BY lower("name");`
+   WHERE "deleted_at" IS NULL
+     AND ` + condition + `
+ORDER BY lower("name"), "id"
+   ` + limit + `;`
 
   ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
   defer cancel()
 
-  result, err := r.db.QueryContext(ctx, getTagsQuery)
+  result, err := r.db.QueryContext(ctx, getTagsQuery, args...)
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return nil, err
   }
 
@@ -110,7 +208,7 @@ ORDER BY lower("name");`
     )
 
     if nil != err {
-      slog.Error(err.Error())
+      slog.ErrorContext(ctx, err.Error())
       return nil, err
     }
 
@@ -121,50 +219,49 @@ ORDER BY lower("name");`
 }
 
 func (r *tagsRepository) Update(ctx context.Context, id string, update *transfer.TagUpdate) error {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
   tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return err
   }
 
   defer tx.Rollback()
 
-  updateArticleTagQuery := `
-  UPDATE "article_tag"
-     SET "tag_id" = @new_tag_id
-   WHERE "tag_id" = @tag_id;`
-
-  ctx1, cancel := context.WithTimeout(ctx, 5*time.Second)
-  defer cancel()
-
-  result, err := tx.ExecContext(ctx1, updateArticleTagQuery,
-    sql.Named("tag_id", id),
-    sql.Named("new_tag_id", update.ID),
-  )
-
-  if nil != err {
-    slog.Error(err.Error())
-    return err
-  }
-
-  updateTagQuery := `
+  // A tag that keeps its ID is only renamed. Otherwise, the tag is
+  // copied under its new ID before the articles that use it are
+  // pointed at it, so that no reference is ever left dangling, and
+  // only then is the old one deleted.
+  renameTagQuery := `
   UPDATE "tag"
-     SET "id" = @new_tag_id,
-         "name" = @name,
+     SET "name" = @name,
          "updated_at" = current_timestamp
-   WHERE "id" = @id;`
+   WHERE "id" = @id
+     AND "deleted_at" IS NULL;`
+
+  if id != update.ID {
+    renameTagQuery = `
+  INSERT INTO "tag" ("id", "name", "created_at")
+       SELECT @new_tag_id, @name, "created_at"
+         FROM "tag"
+        WHERE "id" = @id
+          AND "deleted_at" IS NULL;`
+  }
 
-  ctx, cancel = context.WithTimeout(ctx, 3*time.Second)
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
   defer cancel()
 
-  result, err = tx.ExecContext(ctx, updateTagQuery,
+  result, err := tx.ExecContext(ctx, renameTagQuery,
     sql.Named("id", id),
     sql.Named("new_tag_id", update.ID),
     sql.Named("name", update.Name),
   )
 
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return err
   }
 
@@ -172,8 +269,30 @@ func (r *tagsRepository) Update(ctx context.Context, id string, update *transfer
     return problem.NewNotFound(id, "tag")
   }
 
+  if id != update.ID {
+    moveTagQueries := []string{`
+  UPDATE "article_tag"
+     SET "tag_id" = @new_tag_id
+   WHERE "tag_id" = @id;`, `
+  DELETE FROM "tag"
+        WHERE "id" = @id;`,
+    }
+
+    for _, query := range moveTagQueries {
+      _, err = tx.ExecContext(ctx, query,
+        sql.Named("id", id),
+        sql.Named("new_tag_id", update.ID),
+      )
+
+      if nil != err {
+        slog.ErrorContext(ctx, err.Error())
+        return err
+      }
+    }
+  }
+
   if err = tx.Commit(); nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return err
   }
 
@@ -181,25 +300,23 @@ func (r *tagsRepository) Update(ctx context.Context, id string, update *transfer
 }
 
 func (r *tagsRepository) Remove(ctx context.Context, id string) error {
-  tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
-  if nil != err {
-    slog.Error(err.Error())
-    return err
-  }
-
-  defer tx.Rollback()
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
 
   removeTagQuery := `
-  DELETE FROM "tag"
-        WHERE "id" = $1;`
+  UPDATE "tag"
+     SET "deleted_at" = current_timestamp
+   WHERE "id" = @id
+     AND "deleted_at" IS NULL;`
 
-  ctx1, cancel := context.WithTimeout(ctx, 3*time.Second)
+  ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
   defer cancel()
 
-  result, err := tx.ExecContext(ctx1, removeTagQuery, id)
+  result, err := r.db.ExecContext(ctx, removeTagQuery, sql.Named("id", id))
 
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return err
   }
 
@@ -207,27 +324,32 @@ func (r *tagsRepository) Remove(ctx context.Context, id string) error {
     return problem.NewNotFound(id, "tag")
   }
 
-  removeFromAttachedArticlesQuery := `
-  DELETE FROM "article_tag"
-        WHERE "tag_id" = $1;`
+  return nil
+}
 
-  ctx1, cancel = context.WithTimeout(ctx, 5*time.Second)
+func (r *tagsRepository) Restore(ctx context.Context, id string) error {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
+  restoreTagQuery := `
+  UPDATE "tag"
+     SET "deleted_at" = NULL
+   WHERE "id" = @id
+     AND "deleted_at" IS NOT NULL;`
+
+  ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
   defer cancel()
 
-  result, err = tx.ExecContext(ctx1, removeFromAttachedArticlesQuery, id)
+  result, err := r.db.ExecContext(ctx, restoreTagQuery, sql.Named("id", id))
 
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return err
   }
 
-  if _, err = result.RowsAffected(); nil != err {
-    slog.Error(err.Error())
-  }
-
-  if err = tx.Commit(); nil != err {
-    slog.Error(err.Error())
-    return err
+  if affected, _ := result.RowsAffected(); 1 != affected {
+    return problem.NewNotFound(id, "tag")
   }
 
   return nil
@@ -3,8 +3,11 @@ package repository
 import (
   "context"
   "database/sql"
+  "fontseca.dev/database"
+  "fontseca.dev/metrics"
   "fontseca.dev/model"
   "fontseca.dev/problem"
+  "fontseca.dev/tracing"
   "fontseca.dev/transfer"
   "github.com/google/uuid"
   "log/slog"
@@ -16,6 +19,10 @@ type TechnologyTagRepository interface {
   // Get retrieves a slice of technology tags.
   Get(ctx context.Context) (technologies []*model.TechnologyTag, err error)
 
+  // List retrieves the page of technology tags that follows the cursor
+  // of page, most recent first.
+  List(ctx context.Context, page *transfer.PageRequest) (technologies *transfer.Page[*model.TechnologyTag], err error)
+
   // Add creates a new technology tag record with the provided creation data.
   Add(ctx context.Context, creation *transfer.TechnologyTagCreation) (id string, err error)
 
@@ -31,31 +38,72 @@ type TechnologyTagRepository interface {
 }
 
 type technologyTagRepository struct {
-  db *sql.DB
+  db *database.DB
 }
 
-func NewTechnologyTagRepository(db *sql.DB) TechnologyTagRepository {
+func NewTechnologyTagRepository(db *database.DB) TechnologyTagRepository {
   return &technologyTagRepository{db}
 }
 
 func (r *technologyTagRepository) Get(ctx context.Context) (technologies []*model.TechnologyTag, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
+  return r.doGet(ctx, "TRUE", "")
+}
+
+func (r *technologyTagRepository) List(ctx context.Context, page *transfer.PageRequest) (technologies *transfer.Page[*model.TechnologyTag], err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
+  condition, args, err := keyset(page, true, timeKey(`"created_at"`), textKey(`"uuid"`))
+  if nil != err {
+    return nil, err
+  }
+
+  var total int
+  ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+  defer cancel()
+  err = r.db.QueryRowContext(ctx, `SELECT count (*) FROM "technology_tag";`).Scan(&total)
+  if nil != err {
+    slog.ErrorContext(ctx, err.Error())
+    return nil, err
+  }
+
+  items, err := r.doGet(ctx, condition, "LIMIT @limit", args...)
+  if nil != err {
+    return nil, err
+  }
+
+  return newPage(items, page, total, func(t *model.TechnologyTag) *transfer.Cursor {
+    return &transfer.Cursor{Key: []string{transfer.CursorTime(t.CreatedAt)}, ID: t.UUID.String()}
+  }), nil
+}
+
+// doGet retrieves the technology tags that satisfy condition, limited by limit.
+func (r *technologyTagRepository) doGet(ctx context.Context, condition, limit string, args ...any) (technologies []*model.TechnologyTag, err error) {
   var query = `
   SELECT *
     FROM "technology_tag"
-ORDER BY "technology_tag"."created_at" DESC;`
+   WHERE ` + condition + `
+ORDER BY "technology_tag"."created_at" DESC, "technology_tag"."uuid" DESC
+   ` + limit + `;`
   ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
   defer cancel()
-  rows, err := r.db.QueryContext(ctx, query)
+  rows, err := r.db.QueryContext(ctx, query, args...)
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return nil, err
   }
+  defer rows.Close()
   technologies = make([]*model.TechnologyTag, 0)
   for rows.Next() {
     var tech = new(model.TechnologyTag)
     err = rows.Scan(&tech.UUID, &tech.Name, &tech.CreatedAt, &tech.UpdatedAt)
     if nil != err {
-      slog.Error(err.Error())
+      slog.ErrorContext(ctx, err.Error())
       return nil, err
     }
     technologies = append(technologies, tech)
@@ -64,9 +112,13 @@ ORDER BY "technology_tag"."created_at" DESC;`
 }
 
 func (r *technologyTagRepository) Add(ctx context.Context, creation *transfer.TechnologyTagCreation) (id string, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
   tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return uuid.Nil.String(), err
   }
   defer tx.Rollback()
@@ -79,17 +131,21 @@ func (r *technologyTagRepository) Add(ctx context.Context, creation *transfer.Te
   var result = tx.QueryRowContext(ctx, query, sql.Named("name", creation.Name))
   err = result.Scan(&id)
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return uuid.Nil.String(), err
   }
   if err = tx.Commit(); nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return uuid.Nil.String(), err
   }
   return id, nil
 }
 
 func (r *technologyTagRepository) Exists(ctx context.Context, id string) (err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
   var query = `
   SELECT count (1)
     FROM "technology_tag"
@@ -100,7 +156,7 @@ func (r *technologyTagRepository) Exists(ctx context.Context, id string) (err er
   var count int
   err = row.Scan(&count)
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return err
   }
   if count != 1 {
@@ -110,9 +166,13 @@ func (r *technologyTagRepository) Exists(ctx context.Context, id string) (err er
 }
 
 func (r *technologyTagRepository) Update(ctx context.Context, id string, update *transfer.TechnologyTagUpdate) (updated bool, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
   tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return false, err
   }
   defer tx.Rollback()
@@ -126,7 +186,7 @@ func (r *technologyTagRepository) Update(ctx context.Context, id string, update
   var currentName string
   err = row.Scan(&currentName)
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return false, err
   }
   if update.Name == currentName {
@@ -143,7 +203,7 @@ func (r *technologyTagRepository) Update(ctx context.Context, id string, update
     sql.Named("uuid", id),
     sql.Named("name", update.Name))
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return false, err
   }
   affected, _ := result.RowsAffected()
@@ -151,27 +211,38 @@ func (r *technologyTagRepository) Update(ctx context.Context, id string, update
     return false, nil
   }
   if err = tx.Commit(); nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return false, err
   }
   return true, nil
 }
 
 func (r *technologyTagRepository) Remove(ctx context.Context, id string) (err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
   tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return err
   }
   defer tx.Rollback()
+  var detach = `
+  DELETE FROM "project_technology_tag"
+        WHERE "technology_tag_uuid" = @uuid;`
+  ctx, cancel := context.WithTimeout(ctx, time.Second)
+  defer cancel()
+  if _, err = tx.ExecContext(ctx, detach, sql.Named("uuid", id)); nil != err {
+    slog.ErrorContext(ctx, err.Error())
+    return err
+  }
   var query = `
   DELETE FROM "technology_tag"
         WHERE "uuid" = @uuid;`
-  ctx, cancel := context.WithTimeout(ctx, time.Second)
-  defer cancel()
   result, err := tx.ExecContext(ctx, query, sql.Named("uuid", id))
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return err
   }
   affected, _ := result.RowsAffected()
@@ -179,7 +250,7 @@ func (r *technologyTagRepository) Remove(ctx context.Context, id string) (err er
     return problem.NewNotFound(id, "technology_tag")
   }
   if err = tx.Commit(); nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return err
   }
   return nil
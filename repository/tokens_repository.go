@@ -0,0 +1,170 @@
+package repository
+
+import (
+  "context"
+  "database/sql"
+  "errors"
+  "fontseca.dev/database"
+  "fontseca.dev/metrics"
+  "fontseca.dev/model"
+  "fontseca.dev/problem"
+  "fontseca.dev/tracing"
+  "log/slog"
+  "net/http"
+  "time"
+)
+
+// TokensRepository provides methods for storing the hashes of the API
+// tokens in the database.
+type TokensRepository interface {
+  // Add stores the hash of a new token called name. If a token is
+  // already called name, returns a conflict error.
+  Add(ctx context.Context, name, hash string) error
+
+  // Exists reports whether a token with hash is stored.
+  Exists(ctx context.Context, hash string) (exists bool, err error)
+
+  // Get retrieves every stored token, the most recent first.
+  Get(ctx context.Context) (tokens []*model.Token, err error)
+
+  // Remove deletes the token called name. If not found, returns a not
+  // found error.
+  Remove(ctx context.Context, name string) error
+}
+
+type tokensRepository struct {
+  db *database.DB
+}
+
+func NewTokensRepository(db *database.DB) TokensRepository {
+  return &tokensRepository{db}
+}
+
+func (r *tokensRepository) Add(ctx context.Context, name, hash string) error {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
+  tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+  if nil != err {
+    slog.ErrorContext(ctx, err.Error())
+    return err
+  }
+  defer tx.Rollback()
+
+  var existsQuery = `
+  SELECT count (*)
+    FROM "api_token"
+   WHERE "name" = @name;`
+  ctx1, cancel := context.WithTimeout(ctx, time.Second)
+  defer cancel()
+  var n int
+  if err = tx.QueryRowContext(ctx1, existsQuery, sql.Named("name", name)).Scan(&n); nil != err {
+    slog.ErrorContext(ctx, err.Error())
+    return err
+  }
+
+  if 0 != n {
+    p := &problem.Problem{}
+    p.Status(http.StatusConflict)
+    p.Title("Could not create token.")
+    p.Detail("A token with this name is already registered.")
+    p.With("token_name", name)
+
+    return p
+  }
+
+  var addQuery = `
+  INSERT INTO "api_token" ("name", "hash")
+                   VALUES (@name, @hash);`
+  ctx1, cancel = context.WithTimeout(ctx, time.Second)
+  defer cancel()
+  if _, err = tx.ExecContext(ctx1, addQuery, sql.Named("name", name), sql.Named("hash", hash)); nil != err {
+    slog.ErrorContext(ctx, err.Error())
+    return err
+  }
+
+  if err = tx.Commit(); nil != err {
+    slog.ErrorContext(ctx, err.Error())
+    return err
+  }
+
+  return nil
+}
+
+func (r *tokensRepository) Exists(ctx context.Context, hash string) (exists bool, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
+  var query = `
+  SELECT TRUE
+    FROM "api_token"
+   WHERE "hash" = @hash;`
+  ctx, cancel := context.WithTimeout(ctx, time.Second)
+  defer cancel()
+  err = r.db.QueryRowContext(ctx, query, sql.Named("hash", hash)).Scan(&exists)
+  if errors.Is(err, sql.ErrNoRows) {
+    return false, nil
+  }
+  if nil != err {
+    slog.ErrorContext(ctx, err.Error())
+    return false, err
+  }
+  return exists, nil
+}
+
+func (r *tokensRepository) Get(ctx context.Context) (tokens []*model.Token, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
+  var query = `
+    SELECT "name",
+           "created_at"
+      FROM "api_token"
+  ORDER BY "created_at" DESC, "name";`
+  ctx, cancel := context.WithTimeout(ctx, time.Second)
+  defer cancel()
+  rows, err := r.db.QueryContext(ctx, query)
+  if nil != err {
+    slog.ErrorContext(ctx, err.Error())
+    return nil, err
+  }
+  defer rows.Close()
+  tokens = make([]*model.Token, 0)
+  for rows.Next() {
+    var token model.Token
+    if err = rows.Scan(&token.Name, &token.CreatedAt); nil != err {
+      slog.ErrorContext(ctx, err.Error())
+      return nil, err
+    }
+    tokens = append(tokens, &token)
+  }
+  if err = rows.Err(); nil != err {
+    slog.ErrorContext(ctx, err.Error())
+    return nil, err
+  }
+  return tokens, nil
+}
+
+func (r *tokensRepository) Remove(ctx context.Context, name string) error {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
+  var query = `
+  DELETE FROM "api_token"
+        WHERE "name" = @name;`
+  ctx, cancel := context.WithTimeout(ctx, time.Second)
+  defer cancel()
+  result, err := r.db.ExecContext(ctx, query, sql.Named("name", name))
+  if nil != err {
+    slog.ErrorContext(ctx, err.Error())
+    return err
+  }
+  if affected, _ := result.RowsAffected(); 1 != affected {
+    return problem.NewNotFound(name, "token")
+  }
+  return nil
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+  "context"
+  "fontseca.dev/database"
+  "github.com/stretchr/testify/assert"
+  "github.com/stretchr/testify/require"
+  "net/http"
+  "testing"
+)
+
+func TestTokensRepository(t *testing.T) {
+  forEachBackend(t, func(t *testing.T, db *database.DB) {
+    var (
+      ctx    = context.Background()
+      tokens = NewTokensRepository(db)
+    )
+
+    require.NoError(t, tokens.Add(ctx, "deploy", "hash"))
+    assertStatus(t, http.StatusConflict, tokens.Add(ctx, "deploy", "other"))
+
+    exists, err := tokens.Exists(ctx, "hash")
+    require.NoError(t, err)
+    assert.True(t, exists)
+
+    exists, err = tokens.Exists(ctx, "other")
+    require.NoError(t, err)
+    assert.False(t, exists)
+
+    got, err := tokens.Get(ctx)
+    require.NoError(t, err)
+    require.Len(t, got, 1)
+    assert.Equal(t, "deploy", got[0].Name)
+    assert.False(t, got[0].CreatedAt.IsZero())
+
+    require.NoError(t, tokens.Remove(ctx, "deploy"))
+    assertStatus(t, http.StatusNotFound, tokens.Remove(ctx, "deploy"))
+
+    exists, err = tokens.Exists(ctx, "hash")
+    require.NoError(t, err)
+    assert.False(t, exists, "a removed token must not authenticate")
+  })
+}
@@ -3,8 +3,12 @@ package repository
 import (
   "context"
   "database/sql"
+  "errors"
+  "fontseca.dev/database"
+  "fontseca.dev/metrics"
   "fontseca.dev/model"
   "fontseca.dev/problem"
+  "fontseca.dev/tracing"
   "fontseca.dev/transfer"
   "log/slog"
   "net/http"
@@ -14,36 +18,73 @@ import (
 // TopicsRepository is a low level API that provides methods for interacting
 // with topics in the database.
 type TopicsRepository interface {
-  // Add adds a new topic.
+  // Add adds a new topic. It fails with a conflict if the ID is taken,
+  // even by a topic in the trash.
   Add(ctx context.Context, creation *transfer.TopicCreation) error
 
   // Get retrieves all the topics.
   Get(ctx context.Context) (topics []*model.Topic, err error)
 
+  // List retrieves the page of topics that follows the cursor of page,
+  // sorted by name.
+  List(ctx context.Context, page *transfer.PageRequest) (topics *transfer.Page[*model.Topic], err error)
+
   // Update updates an existing topic.
   Update(ctx context.Context, id string, update *transfer.TopicUpdate) error
 
-  // Remove removes a topic and detaches it from any article that uses it.
+  // Remove moves a topic to the trash, so that no more articles can
+  // be filed under it: the drafts and patches that would file one are
+  // detached from it in the same transaction. The published articles
+  // stay filed under it until it is purged, when it is detached from
+  // them as well.
   Remove(ctx context.Context, id string) error
+
+  // Restore brings back a topic from the trash.
+  Restore(ctx context.Context, id string) error
 }
 
 type topicsRepository struct {
-  db *sql.DB
+  db *database.DB
 }
 
-func NewTopicsRepository(db *sql.DB) TopicsRepository {
+func NewTopicsRepository(db *database.DB) TopicsRepository {
   return &topicsRepository{db}
 }
 
 func (r *topicsRepository) Add(ctx context.Context, creation *transfer.TopicCreation) error {
-  slog.Info("adding new article topic",
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
+  slog.InfoContext(ctx, "adding new article topic",
     slog.String("id", creation.ID),
     slog.String("name", creation.Name))
 
-  exists := false
-  r.db.QueryRowContext(ctx, `SELECT count (1) FROM "topic" WHERE "id" = $1;`, creation.ID).Scan(&exists)
+  tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+  if nil != err {
+    return err
+  }
+
+  defer tx.Rollback()
+
+  // A trashed topic keeps its ID until it is purged, so adding it again
+  // would collide with it.
+  getExistingQuery := `
+  SELECT "deleted_at" IS NOT NULL
+    FROM "topic"
+   WHERE "id" = @id;`
+
+  ctx1, cancel := context.WithTimeout(ctx, 3*time.Second)
+  defer cancel()
+
+  var trashed bool
 
-  if exists {
+  err = tx.QueryRowContext(ctx1, getExistingQuery, sql.Named("id", creation.ID)).Scan(&trashed)
+
+  switch {
+  case nil == err && trashed:
+    return problem.NewInTrash(creation.ID, "topic")
+  case nil == err:
     p := &problem.Problem{}
     p.Status(http.StatusConflict)
     p.Title("Could not create topic.")
@@ -51,29 +92,25 @@ func (r *topicsRepository) Add(ctx context.Context, creation *transfer.TopicCrea
     p.With("topic_id", creation.ID)
 
     return p
-  }
-
-  tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
-  if nil != err {
+  case !errors.Is(err, sql.ErrNoRows):
+    slog.ErrorContext(ctx, err.Error())
     return err
   }
 
-  defer tx.Rollback()
-
   addTopicQuery := `
   INSERT INTO "topic" ("id", "name")
                VALUES (@id, @name);`
 
-  ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+  ctx1, cancel = context.WithTimeout(ctx, 3*time.Second)
   defer cancel()
 
-  result, err := tx.ExecContext(ctx, addTopicQuery,
+  result, err := tx.ExecContext(ctx1, addTopicQuery,
     sql.Named("id", creation.ID),
     sql.Named("name", creation.Name),
   )
 
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return err
   }
 
@@ -87,7 +124,7 @@ func (r *topicsRepository) Add(ctx context.Context, creation *transfer.TopicCrea
   }
 
   if err = tx.Commit(); nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return err
   }
 
@@ -95,20 +132,70 @@ func (r *topicsRepository) Add(ctx context.Context, creation *transfer.TopicCrea
 }
 
 func (r *topicsRepository) Get(ctx context.Context) (topics []*model.Topic, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
+  return r.doGet(ctx, "TRUE", "")
+}
+
+func (r *topicsRepository) List(ctx context.Context, page *transfer.PageRequest) (topics *transfer.Page[*model.Topic], err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
+  name := textKey(`lower ("name")`)
+  name.param = "lower (%s)"
+
+  condition, args, err := keyset(page, false, name, textKey(`"id"`))
+  if nil != err {
+    return nil, err
+  }
+
+  countTopicsQuery := `
+  SELECT count (*)
+    FROM "topic"
+   WHERE "deleted_at" IS NULL;`
+
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+
+  var total int
+
+  if err = r.db.QueryRowContext(ctx, countTopicsQuery).Scan(&total); nil != err {
+    slog.ErrorContext(ctx, err.Error())
+    return nil, err
+  }
+
+  items, err := r.doGet(ctx, condition, "LIMIT @limit", args...)
+  if nil != err {
+    return nil, err
+  }
+
+  return newPage(items, page, total, func(topic *model.Topic) *transfer.Cursor {
+    return &transfer.Cursor{Key: []string{topic.Name}, ID: topic.ID}
+  }), nil
+}
+
+// doGet retrieves the topics that satisfy condition, limited by limit.
+func (r *topicsRepository) doGet(ctx context.Context, condition, limit string, args ...any) (topics []*model.Topic, err error) {
   getTopicsQuery := `
   SELECT "id",
          "name",
          "created_at",
          "updated_at"
     FROM "topic"
-ORDER BY lower("name");`
+   WHERE "deleted_at" IS NULL
+     AND ` + condition + `
+ORDER BY lower("name"), "id"
+   ` + limit + `;`
 
   ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
   defer cancel()
 
-  result, err := r.db.QueryContext(ctx, getTopicsQuery)
+  result, err := r.db.QueryContext(ctx, getTopicsQuery, args...)
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return nil, err
   }
 
@@ -127,7 +214,7 @@ ORDER BY lower("name");`
     )
 
     if nil != err {
-      slog.Error(err.Error())
+      slog.ErrorContext(ctx, err.Error())
       return nil, err
     }
 
@@ -138,50 +225,49 @@ ORDER BY lower("name");`
 }
 
 func (r *topicsRepository) Update(ctx context.Context, id string, update *transfer.TopicUpdate) error {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
   tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return err
   }
 
   defer tx.Rollback()
 
-  updateArticleTopicQuery := `
-  UPDATE "article"
-     SET "topic" = @new_topic_id
-   WHERE "topic" = @topic_id;`
-
-  ctx1, cancel := context.WithTimeout(ctx, 7*time.Second)
-  defer cancel()
-
-  result, err := tx.ExecContext(ctx1, updateArticleTopicQuery,
-    sql.Named("topic_id", id),
-    sql.Named("new_topic_id", update.ID),
-  )
-
-  if nil != err {
-    slog.Error(err.Error())
-    return err
-  }
-
-  updateTopicQuery := `
+  // A topic that keeps its ID is only renamed. Otherwise, the topic is
+  // copied under its new ID before the articles and patches filed
+  // under it are pointed at it, so that no reference is ever left
+  // dangling, and only then is the old one deleted.
+  renameTopicQuery := `
   UPDATE "topic"
-     SET "id" = @new_topic_id,
-         "name" = @name,
+     SET "name" = @name,
          "updated_at" = current_timestamp
-   WHERE "id" = @id;`
+   WHERE "id" = @id
+     AND "deleted_at" IS NULL;`
+
+  if id != update.ID {
+    renameTopicQuery = `
+  INSERT INTO "topic" ("id", "name", "created_at")
+       SELECT @new_topic_id, @name, "created_at"
+         FROM "topic"
+        WHERE "id" = @id
+          AND "deleted_at" IS NULL;`
+  }
 
-  ctx, cancel = context.WithTimeout(ctx, 3*time.Second)
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
   defer cancel()
 
-  result, err = tx.ExecContext(ctx, updateTopicQuery,
+  result, err := tx.ExecContext(ctx, renameTopicQuery,
     sql.Named("id", id),
     sql.Named("new_topic_id", update.ID),
     sql.Named("name", update.Name),
   )
 
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return err
   }
 
@@ -189,8 +275,33 @@ func (r *topicsRepository) Update(ctx context.Context, id string, update *transf
     return problem.NewNotFound(id, "topic")
   }
 
+  if id != update.ID {
+    moveTopicQueries := []string{`
+  UPDATE "article"
+     SET "topic" = @new_topic_id
+   WHERE "topic" = @id;`, `
+  UPDATE "article_patch"
+     SET "topic" = @new_topic_id
+   WHERE "topic" = @id;`, `
+  DELETE FROM "topic"
+        WHERE "id" = @id;`,
+    }
+
+    for _, query := range moveTopicQueries {
+      _, err = tx.ExecContext(ctx, query,
+        sql.Named("id", id),
+        sql.Named("new_topic_id", update.ID),
+      )
+
+      if nil != err {
+        slog.ErrorContext(ctx, err.Error())
+        return err
+      }
+    }
+  }
+
   if err = tx.Commit(); nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return err
   }
 
@@ -198,56 +309,87 @@ func (r *topicsRepository) Update(ctx context.Context, id string, update *transf
 }
 
 func (r *topicsRepository) Remove(ctx context.Context, id string) error {
-  slog.Info("removing article topic", slog.String("id", id))
-
-  tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
-  if nil != err {
-    slog.Error(err.Error())
-    return err
-  }
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
 
-  defer tx.Rollback()
+  slog.InfoContext(ctx, "removing article topic", slog.String("id", id))
 
-  removeTopicQuery := `
-  DELETE FROM "topic"
-        WHERE "id" = $1;`
+  return r.db.Transact(ctx, func(ctx context.Context) error {
+    removeTopicQuery := `
+  UPDATE "topic"
+     SET "deleted_at" = current_timestamp
+   WHERE "id" = @id
+     AND "deleted_at" IS NULL;`
 
-  ctx1, cancel := context.WithTimeout(ctx, 3*time.Second)
-  defer cancel()
+    ctx1, cancel := context.WithTimeout(ctx, 3*time.Second)
+    defer cancel()
 
-  result, err := tx.ExecContext(ctx1, removeTopicQuery, id)
+    result, err := r.db.ExecContext(ctx1, removeTopicQuery, sql.Named("id", id))
 
-  if nil != err {
-    slog.Error(err.Error())
-    return err
-  }
+    if nil != err {
+      slog.ErrorContext(ctx, err.Error())
+      return err
+    }
 
-  if affected, _ := result.RowsAffected(); 1 != affected {
-    return problem.NewNotFound(id, "topic")
-  }
+    if affected, _ := result.RowsAffected(); 1 != affected {
+      return problem.NewNotFound(id, "topic")
+    }
 
-  removeFromAttachedArticlesQuery := `
+    // The drafts and patches are not filed under the topic until they
+    // are published or released, so they are detached from it right
+    // away: none of them can file an article under a removed topic.
+    detachTopicQueries := []string{`
   UPDATE "article"
+     SET "topic" = NULL,
+         "updated_at" = current_timestamp
+   WHERE "topic" = @id
+     AND "draft" IS TRUE
+     AND "published_at" IS NULL;`, `
+  UPDATE "article_patch"
      SET "topic" = NULL
-   WHERE "topic" = $1;`
+   WHERE "topic" = @id;`,
+    }
+
+    ctx1, cancel = context.WithTimeout(ctx, 3*time.Second)
+    defer cancel()
+
+    for _, query := range detachTopicQueries {
+      if _, err = r.db.ExecContext(ctx1, query, sql.Named("id", id)); nil != err {
+        slog.ErrorContext(ctx, err.Error())
+        return err
+      }
+    }
 
-  ctx1, cancel = context.WithTimeout(ctx, 7*time.Second)
+    return nil
+  })
+}
+
+func (r *topicsRepository) Restore(ctx context.Context, id string) error {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
+  slog.InfoContext(ctx, "restoring article topic", slog.String("id", id))
+
+  restoreTopicQuery := `
+  UPDATE "topic"
+     SET "deleted_at" = NULL
+   WHERE "id" = @id
+     AND "deleted_at" IS NOT NULL;`
+
+  ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
   defer cancel()
 
-  result, err = tx.ExecContext(ctx1, removeFromAttachedArticlesQuery, id)
+  result, err := r.db.ExecContext(ctx, restoreTopicQuery, sql.Named("id", id))
 
   if nil != err {
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return err
   }
 
-  if _, err = result.RowsAffected(); nil != err {
-    slog.Error(err.Error())
-  }
-
-  if err = tx.Commit(); nil != err {
-    slog.Error(err.Error())
-    return err
+  if affected, _ := result.RowsAffected(); 1 != affected {
+    return problem.NewNotFound(id, "topic")
   }
 
   return nil
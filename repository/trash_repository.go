@@ -0,0 +1,257 @@
+package repository
+
+import (
+  "context"
+  "database/sql"
+  "fontseca.dev/database"
+  "fontseca.dev/metrics"
+  "fontseca.dev/tracing"
+  "fontseca.dev/transfer"
+  "log/slog"
+  "time"
+)
+
+// TrashRepository provides methods for the content that was removed
+// but not yet purged: articles, drafts, patches, projects, experience,
+// tags and topics. Each of them is restored by its own repository.
+type TrashRepository interface {
+  // Get retrieves the page of removed content that follows the cursor
+  // of page, the most recently removed first.
+  Get(ctx context.Context, page *transfer.PageRequest) (items *transfer.Page[*transfer.TrashedItem], err error)
+
+  // Purge permanently deletes the content removed before the provided
+  // time, along with whatever depends on it: the patches, tags and
+  // shareable links of an article, the technologies of a project, and
+  // the articles a tag or a topic was attached to lose it.
+  Purge(ctx context.Context, before time.Time) (purged int64, err error)
+}
+
+type trashRepository struct {
+  db *database.DB
+}
+
+func NewTrashRepository(db *database.DB) TrashRepository {
+  return &trashRepository{db}
+}
+
+// trashQuery is the query of every removed record. The columns are
+// named after the first SELECT, for SQLite takes no column list.
+const trashQuery = `
+  SELECT "deleted_at" AS "deleted_at",
+         CASE WHEN "published_at" IS NULL
+           THEN 'draft'
+           ELSE 'article'
+            END AS "type",
+         "uuid" AS "id",
+         "title" AS "title"
+    FROM "article"
+   WHERE "deleted_at" IS NOT NULL
+   UNION ALL
+  SELECT p."deleted_at",
+         'patch',
+         p."uuid",
+         coalesce(p."title", a."title")
+    FROM "article_patch" p
+    JOIN "article" a
+      ON a."uuid" = p."article_uuid"
+   WHERE p."deleted_at" IS NOT NULL
+   UNION ALL
+  SELECT "deleted_at",
+         'project',
+         "uuid",
+         "name"
+    FROM "project"
+   WHERE "deleted_at" IS NOT NULL
+   UNION ALL
+  SELECT "deleted_at",
+         'experience',
+         "uuid",
+         "job_title" || ' at ' || "company"
+    FROM "experience"
+   WHERE "deleted_at" IS NOT NULL
+   UNION ALL
+  SELECT "deleted_at",
+         'tag',
+         "id",
+         "name"
+    FROM "tag"
+   WHERE "deleted_at" IS NOT NULL
+   UNION ALL
+  SELECT "deleted_at",
+         'topic',
+         "id",
+         "name"
+    FROM "topic"
+   WHERE "deleted_at" IS NOT NULL`
+
+func (r *trashRepository) Get(ctx context.Context, page *transfer.PageRequest) (items *transfer.Page[*transfer.TrashedItem], err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
+  condition, args, err := keyset(page, true, timeKey(`"deleted_at"`), textKey(`"type"`), textKey(`"id"`))
+  if nil != err {
+    return nil, err
+  }
+
+  getTrashQuery := `
+  SELECT "deleted_at",
+         "type",
+         "id",
+         "title"
+    FROM (` + trashQuery + `) AS "trash"
+   WHERE ` + condition + `
+ORDER BY "deleted_at" DESC, "type" DESC, "id" DESC
+   LIMIT @limit;`
+
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+
+  var total int
+
+  err = r.db.QueryRowContext(ctx, `SELECT count (*) FROM (`+trashQuery+`) AS "trash";`).Scan(&total)
+  if nil != err {
+    slog.ErrorContext(ctx, err.Error())
+    return nil, err
+  }
+
+  rows, err := r.db.QueryContext(ctx, getTrashQuery, args...)
+  if nil != err {
+    slog.ErrorContext(ctx, err.Error())
+    return nil, err
+  }
+
+  defer rows.Close()
+
+  var trashed = make([]*transfer.TrashedItem, 0)
+
+  for rows.Next() {
+    var item = new(transfer.TrashedItem)
+
+    err = rows.Scan(
+      &item.DeletedAt,
+      &item.Type,
+      &item.ID,
+      &item.Title,
+    )
+
+    if nil != err {
+      slog.ErrorContext(ctx, err.Error())
+      return nil, err
+    }
+
+    trashed = append(trashed, item)
+  }
+
+  if err = rows.Err(); nil != err {
+    slog.ErrorContext(ctx, err.Error())
+    return nil, err
+  }
+
+  return newPage(trashed, page, total, func(item *transfer.TrashedItem) *transfer.Cursor {
+    return &transfer.Cursor{Key: []string{transfer.CursorTime(item.DeletedAt), item.Type}, ID: item.ID}
+  }), nil
+}
+
+func (r *trashRepository) Purge(ctx context.Context, before time.Time) (purged int64, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
+  // Every purged record is deleted after whatever references it; only
+  // the deletions of the trashed records themselves are counted.
+  purgeQueries := []struct {
+    query   string
+    counted bool
+  }{
+    {query: `
+  DELETE FROM "article_link"
+        WHERE "patch_uuid" IN (SELECT "uuid"
+                                 FROM "article_patch"
+                                WHERE "deleted_at" < @before);`},
+    {query: `
+  DELETE FROM "article_patch"
+        WHERE "deleted_at" < @before;`, counted: true},
+    {query: `
+  DELETE FROM "article_link"
+        WHERE "article_uuid" IN (SELECT "uuid"
+                                   FROM "article"
+                                  WHERE "deleted_at" < @before);`},
+    {query: `
+  DELETE FROM "article_tag"
+        WHERE "article_uuid" IN (SELECT "uuid"
+                                   FROM "article"
+                                  WHERE "deleted_at" < @before);`},
+    {query: `
+  DELETE FROM "article_patch"
+        WHERE "article_uuid" IN (SELECT "uuid"
+                                   FROM "article"
+                                  WHERE "deleted_at" < @before);`},
+    {query: `
+  DELETE FROM "article"
+        WHERE "deleted_at" < @before;`, counted: true},
+    {query: `
+  DELETE FROM "project_technology_tag"
+        WHERE "project_uuid" IN (SELECT "uuid"
+                                   FROM "project"
+                                  WHERE "deleted_at" < @before);`},
+    {query: `
+  DELETE FROM "project"
+        WHERE "deleted_at" < @before;`, counted: true},
+    {query: `
+  DELETE FROM "experience"
+        WHERE "deleted_at" < @before;`, counted: true},
+    {query: `
+  DELETE FROM "article_tag"
+        WHERE "tag_id" IN (SELECT "id"
+                             FROM "tag"
+                            WHERE "deleted_at" < @before);`},
+    {query: `
+  DELETE FROM "tag"
+        WHERE "deleted_at" < @before;`, counted: true},
+    {query: `
+  UPDATE "article"
+     SET "topic" = NULL
+   WHERE "topic" IN (SELECT "id"
+                       FROM "topic"
+                      WHERE "deleted_at" < @before);`},
+    {query: `
+  UPDATE "article_patch"
+     SET "topic" = NULL
+   WHERE "topic" IN (SELECT "id"
+                       FROM "topic"
+                      WHERE "deleted_at" < @before);`},
+    {query: `
+  DELETE FROM "topic"
+        WHERE "deleted_at" < @before;`, counted: true},
+  }
+
+  // Timestamps are compared in the format the database writes them.
+  var cutoff = sql.Named("before", before.UTC().Format(time.DateTime))
+
+  err = r.db.Transact(ctx, func(ctx context.Context) error {
+    ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+    defer cancel()
+
+    for _, purge := range purgeQueries {
+      result, err := r.db.ExecContext(ctx, purge.query, cutoff)
+      if nil != err {
+        slog.ErrorContext(ctx, err.Error())
+        return err
+      }
+
+      if purge.counted {
+        affected, _ := result.RowsAffected()
+        purged += affected
+      }
+    }
+
+    return nil
+  })
+
+  if nil != err {
+    return 0, err
+  }
+
+  return purged, nil
+}
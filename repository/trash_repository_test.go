@@ -0,0 +1,129 @@
+package repository
+
+import (
+  "context"
+  "fontseca.dev/database"
+  "fontseca.dev/transfer"
+  "github.com/stretchr/testify/assert"
+  "github.com/stretchr/testify/require"
+  "net/http"
+  "testing"
+  "time"
+)
+
+func TestTrashRepository(t *testing.T) {
+  forEachBackend(t, func(t *testing.T, db *database.DB) {
+    var (
+      ctx        = context.Background()
+      trash      = NewTrashRepository(db)
+      archive    = newArchive(t, db)
+      projects   = NewProjectsRepository(db)
+      experience = NewExperienceRepository(db)
+      tags       = NewTagsRepository(db)
+      topics     = NewTopicsRepository(db)
+    )
+
+    // types returns the types of the items in the trash.
+    types := func() []string {
+      items, err := trash.Get(ctx, &transfer.PageRequest{Limit: 100})
+      require.NoError(t, err)
+
+      var types = make([]string, 0, len(items.Items))
+      for _, item := range items.Items {
+        assert.False(t, item.DeletedAt.IsZero())
+        types = append(types, item.Type)
+      }
+
+      return types
+    }
+
+    require.NoError(t, topics.Add(ctx, &transfer.TopicCreation{ID: "go", Name: "Go"}))
+    require.NoError(t, tags.Add(ctx, &transfer.TagCreation{ID: "sql", Name: "SQL"}))
+
+    var article = publish(t, archive, "Trashed", "trashed", "go")
+    require.NoError(t, archive.AddTag(ctx, article, "sql"))
+    patch, err := archive.Amend(ctx, article)
+    require.NoError(t, err)
+    _, err = archive.Share(ctx, patch)
+    require.NoError(t, err)
+
+    draft, err := archive.Draft(ctx, &transfer.ArticleCreation{Title: "Kept", Slug: "kept", Content: "Content."})
+    require.NoError(t, err)
+    require.NoError(t, archive.Revise(ctx, draft, &transfer.ArticleRevision{Topic: "go", Version: 1}))
+    require.NoError(t, archive.AddTag(ctx, draft, "sql", true))
+
+    technology, err := NewTechnologyTagRepository(db).Add(ctx, &transfer.TechnologyTagCreation{Name: "Go"})
+    require.NoError(t, err)
+    project, err := projects.Add(ctx, &transfer.ProjectCreation{Name: "Trashed", Slug: "trashed"})
+    require.NoError(t, err)
+    _, err = projects.AddTechnologyTag(ctx, project, technology)
+    require.NoError(t, err)
+
+    _, err = experience.Save(ctx, &transfer.ExperienceCreation{Starts: 2020, JobTitle: "Developer", Company: "Company", Country: "Country", Summary: "Summary."})
+    require.NoError(t, err)
+    records, err := experience.Get(ctx, false)
+    require.NoError(t, err)
+    require.Len(t, records, 1)
+    var record = records[0].UUID.String()
+
+    assert.Empty(t, types())
+
+    require.NoError(t, archive.Remove(ctx, article))
+    require.NoError(t, archive.Discard(ctx, draft))
+    require.NoError(t, projects.Remove(ctx, project))
+    require.NoError(t, experience.Remove(ctx, record))
+
+    assert.ElementsMatch(t, []string{"article", "draft", "project", "experience"}, types())
+
+    t.Run("restored content leaves the trash", func(t *testing.T) {
+      require.NoError(t, archive.Restore(ctx, draft))
+      assert.ElementsMatch(t, []string{"article", "project", "experience"}, types())
+
+      _, err := archive.GetByID(ctx, draft, true)
+      assert.NoError(t, err)
+    })
+
+    t.Run("nothing is purged before its time", func(t *testing.T) {
+      purged, err := trash.Purge(ctx, time.Now().Add(-time.Hour))
+      require.NoError(t, err)
+      assert.Zero(t, purged)
+      assert.Len(t, types(), 3)
+    })
+
+    t.Run("purged content cannot be restored", func(t *testing.T) {
+      purged, err := trash.Purge(ctx, time.Now().Add(time.Minute))
+      require.NoError(t, err)
+      assert.Equal(t, int64(3), purged)
+      assert.Empty(t, types())
+
+      assertStatus(t, http.StatusNotFound, archive.Restore(ctx, article))
+      assertStatus(t, http.StatusNotFound, archive.Restore(ctx, patch))
+      assertStatus(t, http.StatusNotFound, projects.Restore(ctx, project))
+      assertStatus(t, http.StatusNotFound, experience.Restore(ctx, record))
+    })
+
+    t.Run("purged tags and topics are detached", func(t *testing.T) {
+      require.NoError(t, tags.Remove(ctx, "sql"))
+      require.NoError(t, topics.Remove(ctx, "go"))
+      assert.ElementsMatch(t, []string{"tag", "topic"}, types())
+
+      // Their IDs are taken until they are purged.
+      assertStatus(t, http.StatusConflict, tags.Add(ctx, &transfer.TagCreation{ID: "sql", Name: "SQL"}))
+      assertStatus(t, http.StatusConflict, topics.Add(ctx, &transfer.TopicCreation{ID: "go", Name: "Go"}))
+      assert.ElementsMatch(t, []string{"tag", "topic"}, types())
+
+      purged, err := trash.Purge(ctx, time.Now().Add(time.Minute))
+      require.NoError(t, err)
+      assert.Equal(t, int64(2), purged)
+
+      got, err := archive.GetByID(ctx, draft, true)
+      require.NoError(t, err)
+      assert.Nil(t, got.Topic)
+      assert.Empty(t, got.Tags)
+
+      // Both can be added again under the same IDs.
+      require.NoError(t, topics.Add(ctx, &transfer.TopicCreation{ID: "go", Name: "Go"}))
+      require.NoError(t, tags.Add(ctx, &transfer.TagCreation{ID: "sql", Name: "SQL"}))
+    })
+  })
+}
@@ -0,0 +1,44 @@
+package repository
+
+import (
+  "context"
+  "fontseca.dev/database"
+  "fontseca.dev/tracing"
+)
+
+// UnitOfWork runs several repository calls as a whole.
+type UnitOfWork interface {
+  // Do runs fn in a single transaction. Every repository call made
+  // with the context fn receives takes part in it: if fn returns nil
+  // their changes are committed together, otherwise none of them is.
+  // Calling Do within fn joins the outer unit of work.
+  Do(ctx context.Context, fn func(ctx context.Context) error) error
+
+  // Read runs fn in a single read-only transaction, so that the
+  // records read with the context fn receives agree with each other,
+  // without holding up the writers. Calling Read within a unit of work
+  // joins it.
+  Read(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type unitOfWork struct {
+  db *database.DB
+}
+
+func NewUnitOfWork(db *database.DB) UnitOfWork {
+  return &unitOfWork{db}
+}
+
+func (u *unitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
+  return u.db.Transact(ctx, fn)
+}
+
+func (u *unitOfWork) Read(ctx context.Context, fn func(ctx context.Context) error) error {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
+  return u.db.Read(ctx, fn)
+}
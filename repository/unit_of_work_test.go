@@ -0,0 +1,93 @@
+package repository
+
+import (
+  "context"
+  "errors"
+  "fontseca.dev/database"
+  "fontseca.dev/transfer"
+  "github.com/stretchr/testify/assert"
+  "github.com/stretchr/testify/require"
+  "testing"
+)
+
+func TestUnitOfWork(t *testing.T) {
+  forEachBackend(t, func(t *testing.T, db *database.DB) {
+    var (
+      ctx        = context.Background()
+      unit       = NewUnitOfWork(db)
+      topics     = NewTopicsRepository(db)
+      archive    = newArchive(t, db)
+      unexpected = errors.New("unexpected error")
+    )
+
+    // write adds a topic and publishes an article under it.
+    write := func(ctx context.Context, topic string) error {
+      if err := topics.Add(ctx, &transfer.TopicCreation{ID: topic, Name: topic}); nil != err {
+        return err
+      }
+
+      id, err := archive.Draft(ctx, &transfer.ArticleCreation{Title: "In " + topic, Slug: "in-" + topic, Content: "Content."})
+      if nil != err {
+        return err
+      }
+
+      if err = archive.Revise(ctx, id, &transfer.ArticleRevision{Topic: topic, Version: 1}); nil != err {
+        return err
+      }
+
+      return archive.Publish(ctx, id)
+    }
+
+    t.Run("rolls back every repository", func(t *testing.T) {
+      err := unit.Do(ctx, func(ctx context.Context) error {
+        require.NoError(t, write(ctx, "go"))
+        return unexpected
+      })
+      assert.ErrorIs(t, err, unexpected)
+
+      got, err := topics.Get(ctx)
+      require.NoError(t, err)
+      assert.Empty(t, got)
+
+      publications, err := archive.Publications(ctx)
+      require.NoError(t, err)
+      assert.Empty(t, publications)
+    })
+
+    t.Run("commits every repository", func(t *testing.T) {
+      assert.NoError(t, unit.Do(ctx, func(ctx context.Context) error {
+        return write(ctx, "go")
+      }))
+
+      got, err := topics.Get(ctx)
+      require.NoError(t, err)
+      assert.Len(t, got, 1)
+
+      publications, err := archive.Publications(ctx)
+      require.NoError(t, err)
+      assert.Len(t, publications, 1)
+    })
+
+    t.Run("tags added to a draft that fails to publish are not kept", func(t *testing.T) {
+      tags := NewTagsRepository(db)
+      require.NoError(t, tags.Add(ctx, &transfer.TagCreation{ID: "sql", Name: "SQL"}))
+
+      id, err := archive.Draft(ctx, &transfer.ArticleCreation{Title: "Untopical", Slug: "untopical", Content: "Content."})
+      require.NoError(t, err)
+
+      err = unit.Do(ctx, func(ctx context.Context) error {
+        if err := archive.AddTag(ctx, id, "sql", true); nil != err {
+          return err
+        }
+
+        // The draft has no topic, so it cannot be published.
+        return archive.Publish(ctx, id)
+      })
+      assert.Error(t, err)
+
+      draft, err := archive.GetByID(ctx, id, true)
+      require.NoError(t, err)
+      assert.Empty(t, draft.Tags)
+    })
+  })
+}
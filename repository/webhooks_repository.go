@@ -0,0 +1,387 @@
+package repository
+
+import (
+  "context"
+  "database/sql"
+  "fontseca.dev/database"
+  "fontseca.dev/metrics"
+  "fontseca.dev/model"
+  "fontseca.dev/problem"
+  "fontseca.dev/tracing"
+  "fontseca.dev/transfer"
+  "github.com/google/uuid"
+  "log/slog"
+  "strings"
+  "time"
+)
+
+// WebhooksRepository provides methods for interacting with webhooks
+// and their queue of deliveries in the database.
+type WebhooksRepository interface {
+  // Add registers a webhook with the provided creation data. Its
+  // events must be already normalized.
+  Add(ctx context.Context, creation *transfer.WebhookCreation) (id string, err error)
+
+  // Get retrieves the page of registered webhooks that follows the
+  // cursor of page, the most recent first.
+  Get(ctx context.Context, page *transfer.PageRequest) (webhooks *transfer.Page[*model.Webhook], err error)
+
+  // Remove deletes a webhook and its deliveries. If not found, returns
+  // a not found error.
+  Remove(ctx context.Context, id string) error
+
+  // Enqueue queues a delivery of payload to every webhook subscribed
+  // to event.
+  Enqueue(ctx context.Context, event string, payload []byte) error
+
+  // Due retrieves up to limit pending deliveries whose next attempt is
+  // due, along with the URL and secret of their webhooks.
+  Due(ctx context.Context, limit int) (deliveries []*model.WebhookDelivery, err error)
+
+  // Record saves the outcome of an attempt to deliver an event.
+  Record(ctx context.Context, attempt *transfer.WebhookAttempt) error
+
+  // Deliveries retrieves the page of the log of deliveries of a webhook
+  // that follows the cursor of page, the most recent first.
+  Deliveries(ctx context.Context, webhookID string, page *transfer.PageRequest) (deliveries *transfer.Page[*model.WebhookDelivery], err error)
+}
+
+type webhooksRepository struct {
+  db *database.DB
+}
+
+func NewWebhooksRepository(db *database.DB) WebhooksRepository {
+  return &webhooksRepository{db}
+}
+
+func (r *webhooksRepository) Add(ctx context.Context, creation *transfer.WebhookCreation) (id string, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
+  tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+  if nil != err {
+    slog.ErrorContext(ctx, err.Error())
+    return uuid.Nil.String(), err
+  }
+  defer tx.Rollback()
+  var query = `
+  INSERT INTO "webhook" ("url", "secret", "events")
+                 VALUES (@url, @secret, @events)
+              RETURNING "uuid";`
+  ctx, cancel := context.WithTimeout(ctx, time.Second)
+  defer cancel()
+  var row = tx.QueryRowContext(ctx, query,
+    sql.Named("url", creation.URL),
+    sql.Named("secret", creation.Secret),
+    sql.Named("events", creation.Events))
+  err = row.Scan(&id)
+  if nil != err {
+    slog.ErrorContext(ctx, err.Error())
+    return uuid.Nil.String(), err
+  }
+  if err = tx.Commit(); nil != err {
+    slog.ErrorContext(ctx, err.Error())
+    return uuid.Nil.String(), err
+  }
+  return id, nil
+}
+
+func (r *webhooksRepository) Get(ctx context.Context, page *transfer.PageRequest) (webhooks *transfer.Page[*model.Webhook], err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
+  condition, args, err := keyset(page, true, timeKey(`"created_at"`), textKey(`"uuid"`))
+  if nil != err {
+    return nil, err
+  }
+  var total int
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+  err = r.db.QueryRowContext(ctx, `SELECT count (*) FROM "webhook";`).Scan(&total)
+  if nil != err {
+    slog.ErrorContext(ctx, err.Error())
+    return nil, err
+  }
+  var query = `
+    SELECT "uuid",
+           "url",
+           "events",
+           "created_at",
+           "updated_at"
+      FROM "webhook"
+     WHERE ` + condition + `
+  ORDER BY "created_at" DESC, "uuid" DESC
+     LIMIT @limit;`
+  rows, err := r.db.QueryContext(ctx, query, args...)
+  if nil != err {
+    slog.ErrorContext(ctx, err.Error())
+    return nil, err
+  }
+  defer rows.Close()
+  var items = make([]*model.Webhook, 0)
+  for rows.Next() {
+    var (
+      webhook = new(model.Webhook)
+      events  string
+    )
+    err = rows.Scan(
+      &webhook.UUID,
+      &webhook.URL,
+      &events,
+      &webhook.CreatedAt,
+      &webhook.UpdatedAt)
+    if nil != err {
+      slog.ErrorContext(ctx, err.Error())
+      return nil, err
+    }
+    webhook.Events = strings.Split(events, ",")
+    items = append(items, webhook)
+  }
+  return newPage(items, page, total, func(w *model.Webhook) *transfer.Cursor {
+    return &transfer.Cursor{Key: []string{transfer.CursorTime(w.CreatedAt)}, ID: w.UUID.String()}
+  }), nil
+}
+
+func (r *webhooksRepository) Remove(ctx context.Context, id string) error {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
+  tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+  if nil != err {
+    slog.ErrorContext(ctx, err.Error())
+    return err
+  }
+  defer tx.Rollback()
+
+  // Remove the deliveries of the webhook to remove.
+
+  var query = `
+  DELETE FROM "webhook_delivery"
+        WHERE "webhook_uuid" = @uuid;`
+  ctx, cancel := context.WithTimeout(ctx, time.Second)
+  defer cancel()
+  _, err = tx.ExecContext(ctx, query, sql.Named("uuid", id))
+  if nil != err {
+    slog.ErrorContext(ctx, err.Error())
+    return err
+  }
+
+  // Remove the actual webhook.
+
+  query = `
+  DELETE FROM "webhook"
+        WHERE "uuid" = @uuid;`
+  result, err := tx.ExecContext(ctx, query, sql.Named("uuid", id))
+  if nil != err {
+    slog.ErrorContext(ctx, err.Error())
+    return err
+  }
+
+  affected, _ := result.RowsAffected()
+  if 1 != affected {
+    return problem.NewNotFound(id, "webhook")
+  }
+
+  if err = tx.Commit(); nil != err {
+    slog.ErrorContext(ctx, err.Error())
+    return err
+  }
+
+  return nil
+}
+
+func (r *webhooksRepository) Enqueue(ctx context.Context, event string, payload []byte) error {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
+  var query = `
+  INSERT INTO "webhook_delivery" ("webhook_uuid", "event", "payload")
+       SELECT "uuid", @event, @payload
+         FROM "webhook"
+        WHERE "events" = '*'
+           OR ',' || "events" || ',' LIKE '%,' || @event || ',%';`
+  ctx, cancel := context.WithTimeout(ctx, time.Second)
+  defer cancel()
+  _, err := r.db.ExecContext(ctx, query,
+    sql.Named("event", event),
+    sql.Named("payload", string(payload)))
+  if nil != err {
+    slog.ErrorContext(ctx, err.Error())
+    return err
+  }
+  return nil
+}
+
+func (r *webhooksRepository) Due(ctx context.Context, limit int) (deliveries []*model.WebhookDelivery, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
+  var query = `
+    SELECT d."uuid",
+           d."webhook_uuid",
+           d."event",
+           d."payload",
+           d."attempts",
+           d."created_at",
+           w."url",
+           w."secret"
+      FROM "webhook_delivery" d
+      JOIN "webhook" w
+        ON w."uuid" = d."webhook_uuid"
+     WHERE d."status" = 'pending'
+       AND d."next_attempt_at" <= current_timestamp
+  ORDER BY d."next_attempt_at"
+     LIMIT @limit;`
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+  rows, err := r.db.QueryContext(ctx, query, sql.Named("limit", limit))
+  if nil != err {
+    slog.ErrorContext(ctx, err.Error())
+    return nil, err
+  }
+  defer rows.Close()
+  deliveries = make([]*model.WebhookDelivery, 0)
+  for rows.Next() {
+    var (
+      delivery = &model.WebhookDelivery{Status: model.DeliveryPending}
+      payload  string
+    )
+    err = rows.Scan(
+      &delivery.UUID,
+      &delivery.WebhookUUID,
+      &delivery.Event,
+      &payload,
+      &delivery.Attempts,
+      &delivery.CreatedAt,
+      &delivery.URL,
+      &delivery.Secret)
+    if nil != err {
+      slog.ErrorContext(ctx, err.Error())
+      return nil, err
+    }
+    delivery.Payload = []byte(payload)
+    deliveries = append(deliveries, delivery)
+  }
+  return deliveries, nil
+}
+
+func (r *webhooksRepository) Record(ctx context.Context, attempt *transfer.WebhookAttempt) error {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
+  var status = model.DeliveryFailed
+  var retryIn *int64
+
+  switch {
+  case attempt.Delivered:
+    status = model.DeliveryDelivered
+  case 0 < attempt.RetryIn:
+    status = model.DeliveryPending
+    seconds := int64(attempt.RetryIn.Seconds())
+    retryIn = &seconds
+  }
+
+  var query = `
+  UPDATE "webhook_delivery"
+     SET "status" = @status,
+         "attempts" = "attempts" + 1,
+         "last_status_code" = nullif (@status_code, 0),
+         "last_error" = nullif (@error, ''),
+         "next_attempt_at" = ` + r.db.Dialect().After("@retry_in") + `,
+         "delivered_at" = CASE WHEN @status = 'delivered' THEN current_timestamp END
+   WHERE "uuid" = @uuid;`
+  ctx, cancel := context.WithTimeout(ctx, time.Second)
+  defer cancel()
+  result, err := r.db.ExecContext(ctx, query,
+    sql.Named("uuid", attempt.DeliveryUUID),
+    sql.Named("status_code", attempt.StatusCode),
+    sql.Named("error", attempt.Error),
+    sql.Named("status", status),
+    sql.Named("retry_in", retryIn))
+  if nil != err {
+    slog.ErrorContext(ctx, err.Error())
+    return err
+  }
+  affected, _ := result.RowsAffected()
+  if 1 != affected {
+    return problem.NewNotFound(attempt.DeliveryUUID, "webhook delivery")
+  }
+  return nil
+}
+
+func (r *webhooksRepository) Deliveries(ctx context.Context, webhookID string, page *transfer.PageRequest) (deliveries *transfer.Page[*model.WebhookDelivery], err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+  defer metrics.ObserveQuery(time.Now())
+
+  condition, args, err := keyset(page, true, timeKey(`"created_at"`), textKey(`"uuid"`))
+  if nil != err {
+    return nil, err
+  }
+  var total int
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+  var webhook = sql.Named("webhook_uuid", webhookID)
+  err = r.db.QueryRowContext(ctx, `SELECT count (*) FROM "webhook_delivery" WHERE "webhook_uuid" = @webhook_uuid;`, webhook).Scan(&total)
+  if nil != err {
+    slog.ErrorContext(ctx, err.Error())
+    return nil, err
+  }
+  var query = `
+    SELECT "uuid",
+           "webhook_uuid",
+           "event",
+           "payload",
+           "status",
+           "attempts",
+           "last_status_code",
+           "last_error",
+           "next_attempt_at",
+           "created_at",
+           "delivered_at"
+      FROM "webhook_delivery"
+     WHERE "webhook_uuid" = @webhook_uuid
+       AND ` + condition + `
+  ORDER BY "created_at" DESC, "uuid" DESC
+     LIMIT @limit;`
+  rows, err := r.db.QueryContext(ctx, query, append(args, webhook)...)
+  if nil != err {
+    slog.ErrorContext(ctx, err.Error())
+    return nil, err
+  }
+  defer rows.Close()
+  var items = make([]*model.WebhookDelivery, 0)
+  for rows.Next() {
+    var (
+      delivery = new(model.WebhookDelivery)
+      payload  string
+    )
+    err = rows.Scan(
+      &delivery.UUID,
+      &delivery.WebhookUUID,
+      &delivery.Event,
+      &payload,
+      &delivery.Status,
+      &delivery.Attempts,
+      &delivery.LastStatusCode,
+      &delivery.LastError,
+      &delivery.NextAttemptAt,
+      &delivery.CreatedAt,
+      &delivery.DeliveredAt)
+    if nil != err {
+      slog.ErrorContext(ctx, err.Error())
+      return nil, err
+    }
+    delivery.Payload = []byte(payload)
+    items = append(items, delivery)
+  }
+  return newPage(items, page, total, func(d *model.WebhookDelivery) *transfer.Cursor {
+    return &transfer.Cursor{Key: []string{transfer.CursorTime(d.CreatedAt)}, ID: d.UUID.String()}
+  }), nil
+}
@@ -0,0 +1,502 @@
+package main
+
+import (
+  "context"
+  "errors"
+  "fmt"
+  "fontseca.dev/config"
+  "fontseca.dev/database"
+  "fontseca.dev/handler"
+  "fontseca.dev/metrics"
+  "fontseca.dev/problem"
+  "fontseca.dev/repository"
+  "fontseca.dev/service"
+  "fontseca.dev/tracing"
+  "github.com/gin-gonic/gin"
+  "github.com/gin-gonic/gin/binding"
+  "github.com/go-playground/validator/v10"
+  "log/slog"
+  "net"
+  "net/http"
+  "os"
+  "os/signal"
+  "reflect"
+  "strconv"
+  "strings"
+  "syscall"
+  "time"
+)
+
+// staticFiles maps the routes of the files served from the root of
+// the site to their location in the public directory.
+var staticFiles = map[string]string{
+  "/favicon.ico": "public/icons/favicon.ico",
+  "/photo.webp":  "public/images/photo.webp",
+}
+
+// routeWeb registers the pages of the site in engine. The static
+// build renders the same routes.
+func routeWeb(engine *gin.Engine, web *handler.WebHandler) {
+  engine.GET("/", web.RenderMe)
+  engine.GET("/experience", web.RenderExperience)
+  engine.GET("/work", web.RenderProjects)
+  engine.GET("/work/:project_slug", web.RenderProjectDetails)
+  engine.GET("/archive", web.RenderArchive)
+  engine.GET("/archive/:topic", web.RenderArchive)
+  engine.GET("/archive/:topic/:year/:month", web.RenderArchive)
+  engine.GET("/archive/tag/:tag", web.RenderArchive)
+  engine.GET("/archive/feed.xml", web.RenderFeed)
+  engine.GET("/archive/:topic/feed.xml", web.RenderFeed)
+  engine.GET("/archive/:topic/:year/:month/:slug", web.RenderArticle)
+  engine.GET("/archive/sharing/:hash", web.RenderArticle)
+
+  engine.NoRoute(web.NotFound)
+}
+
+// routeAdmin registers the pages of the administration site in engine.
+// Every page but the login one requires a session started with the
+// administration token adminToken, or one of the API tokens of tokens.
+func routeAdmin(engine *gin.Engine, panel *handler.AdminHandler, adminToken string, tokens service.TokensService) {
+  engine.GET("/admin/login", panel.Login)
+  engine.POST("/admin/login", panel.Authenticate)
+
+  var area = engine.Group("/admin", handler.RequireAdminSession(adminToken, tokens))
+
+  area.POST("/logout", panel.Logout)
+  area.GET("", panel.Dashboard)
+
+  area.POST("/preview", panel.Preview)
+
+  area.GET("/drafts", panel.Drafts)
+  area.POST("/drafts", panel.StartDraft)
+  area.GET("/drafts/:id", panel.Draft)
+  area.POST("/drafts/:id", panel.ReviseDraft)
+  area.POST("/drafts/:id/publish", panel.PublishDraft)
+  area.POST("/drafts/:id/discard", panel.DiscardDraft)
+
+  area.GET("/patches", panel.Patches)
+  area.GET("/patches/:id", panel.Patch)
+  area.POST("/patches/:id", panel.RevisePatch)
+  area.POST("/patches/:id/release", panel.ReleasePatch)
+  area.POST("/patches/:id/discard", panel.DiscardPatch)
+
+  area.GET("/articles", panel.Articles)
+  area.POST("/articles/:id/amend", panel.AmendArticle)
+  area.POST("/articles/:id/hide", panel.HideArticle)
+  area.POST("/articles/:id/show", panel.ShowArticle)
+  area.POST("/articles/:id/pin", panel.PinArticle)
+  area.POST("/articles/:id/unpin", panel.UnpinArticle)
+  area.POST("/articles/:id/remove", panel.RemoveArticle)
+
+  area.GET("/projects", panel.Projects)
+  area.POST("/projects", panel.AddProject)
+  area.GET("/projects/:id", panel.Project)
+  area.POST("/projects/:id", panel.UpdateProject)
+  area.POST("/projects/:id/remove", panel.RemoveProject)
+
+  area.GET("/experience", panel.Experience)
+  area.POST("/experience", panel.AddExperience)
+  area.GET("/experience/:id", panel.ExperienceEntry)
+  area.POST("/experience/:id", panel.UpdateExperience)
+  area.POST("/experience/:id/remove", panel.RemoveExperience)
+
+  area.GET("/topics", panel.Topics)
+  area.POST("/topics", panel.AddTopic)
+  area.POST("/topics/:id", panel.UpdateTopic)
+  area.POST("/topics/:id/remove", panel.RemoveTopic)
+
+  area.GET("/tags", panel.Tags)
+  area.POST("/tags", panel.AddTag)
+  area.POST("/tags/:id", panel.UpdateTag)
+  area.POST("/tags/:id/remove", panel.RemoveTag)
+
+  area.GET("/technologies", panel.Technologies)
+  area.POST("/technologies", panel.AddTechnology)
+  area.POST("/technologies/:id", panel.UpdateTechnology)
+  area.POST("/technologies/:id/remove", panel.RemoveTechnology)
+}
+
+// drainDelay is how long the server keeps serving requests after it
+// reports that it is not ready, so that the orchestrator stops sending
+// requests to it before it shuts down.
+const drainDelay = 5 * time.Second
+
+// serve runs the web server until it receives an interrupt signal.
+func serve(cfg *config.Config, db *database.DB, _ []string) error {
+  var mode = cfg.Server.Mode
+  gin.SetMode(mode)
+  var engine = gin.New()
+
+  // Let the services and repositories, which are given the gin.Context
+  // of a request, see the span that handler.Trace puts in its context.
+  engine.ContextWithFallback = true
+
+  shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing.Exporter)
+  if nil != err {
+    return err
+  }
+
+  defer func() {
+    if err := shutdownTracing(context.Background()); nil != err {
+      slog.Error(err.Error())
+    }
+  }()
+
+  engine.Use(handler.RequestID(), handler.Trace(), handler.Measure(), handler.Log(), gin.Recovery())
+  engine.Use(handler.Conditional())
+
+  engine.Static("/public", "public")
+  for route, file := range staticFiles {
+    engine.StaticFile(route, file)
+  }
+
+  binding.EnableDecoderDisallowUnknownFields = true
+  if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+    v.RegisterTagNameFunc(func(fld reflect.StructField) string {
+      var name = strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
+      if 0 == strings.Compare(name, "-") {
+        return ""
+      }
+      return name
+    })
+  }
+
+  var adminToken = cfg.Admin.Token
+  if "" == adminToken {
+    slog.Warn("administration token not set; only the API tokens made with 'tokens create' authenticate the administrative endpoints",
+      slog.String("setting", "admin.token"))
+  }
+
+  var metricsToken = cfg.Admin.MetricsToken
+  if "" == metricsToken {
+    metricsToken = adminToken
+  }
+
+  var tokens = service.NewTokensService(repository.NewTokensRepository(db))
+
+  engine.GET("/metrics", handler.RequireToken(metricsToken, tokens), gin.WrapH(metrics.Handler()))
+
+  repository.NewMeRepository(db).Register(context.Background())
+
+  var archive, webhooks, backups, trash = route(engine, db, cfg)
+
+  var health = handler.NewHealthHandler(map[string]handler.Probe{
+    "database": db.PingContext,
+    "schema": func(ctx context.Context) error {
+      return database.CheckSchema(ctx, db)
+    },
+    "cache_writer": func(context.Context) error {
+      if !archive.Running() {
+        return errors.New("the article views cache writer is not running")
+      }
+      return nil
+    },
+  }, buildTime)
+
+  engine.GET("/healthz", health.Live)
+  engine.GET("/readyz", health.Ready)
+  engine.GET("/version", health.Version)
+
+  engine.HandleMethodNotAllowed = true
+  var routes = engine.Routes()
+  engine.NoMethod(func(c *gin.Context) {
+    var allowedMethods = make([]string, 0, 1)
+    for _, route := range routes {
+      if route.Path == c.Request.URL.Path {
+        allowedMethods = append(allowedMethods, route.Method)
+      }
+    }
+    c.Header("Allow", strings.Join(allowedMethods, ","))
+    var p problem.Problem
+    p.Status(http.StatusMethodNotAllowed)
+    p.Title("Unsupported HTTP method.")
+    p.Detail(fmt.Sprintf("The target resource doesn't support this method (%s). Check the 'Allow' header in the response for a list of supported methods.", c.Request.Method))
+    p.Instance(c.Request.URL.String())
+    p.Emit(c.Writer)
+  })
+
+  var server = http.Server{
+    Addr:           net.JoinHostPort(cfg.Server.Host, strconv.Itoa(cfg.Server.Port)),
+    IdleTimeout:    cfg.Server.IdleTimeout,
+    ReadTimeout:    cfg.Server.ReadTimeout,
+    WriteTimeout:   cfg.Server.WriteTimeout,
+    MaxHeaderBytes: cfg.Server.MaxHeaderBytes,
+    Handler:        engine,
+  }
+
+  slog.Info("running server",
+    slog.String("address", server.Addr),
+    slog.String("mode", mode))
+
+  var (
+    didNotServe = make(chan error, 1)
+    shutdown    = make(chan os.Signal, 1)
+  )
+
+  jobs, stopJobs := context.WithCancel(context.Background())
+  defer stopJobs()
+
+  go webhooks.Run(jobs, cfg.Webhooks.Interval)
+
+  if 0 < cfg.Backup.Interval && "sqlite" == cfg.Database.Driver {
+    go backups.Run(jobs, cfg.Backup.Interval)
+  }
+
+  if 0 < cfg.Trash.Interval {
+    go trash.Run(jobs, cfg.Trash.Interval)
+  }
+
+  signal.Notify(shutdown, syscall.SIGINT, syscall.SIGTERM, syscall.SIGKILL)
+
+  go func() {
+    didNotServe <- server.ListenAndServe()
+  }()
+
+  select {
+  case err = <-didNotServe:
+    return err
+  case sig := <-shutdown:
+    fmt.Fprintf(os.Stdout, "received %s signal, gracefully shutting down...\n", sig.String())
+
+    health.Drain()
+    if gin.ReleaseMode == mode {
+      time.Sleep(drainDelay)
+    }
+
+    stopJobs()
+    archive.Close()
+
+    if err := server.Shutdown(context.TODO()); nil != err {
+      fmt.Fprintf(os.Stderr, "could not shutdown server: %v", err)
+    }
+  }
+
+  return nil
+}
+
+// route registers every route of the site in engine and returns the
+// archive repository, which must be closed when the server shuts down,
+// the webhooks service, which must be run to deliver the events, the
+// backups service, which may be run to back up the database, and the
+// trash service, which may be run to purge the removed content.
+// The administrative endpoints require the bearer token of cfg or an
+// API token made with 'tokens create'.
+func route(engine *gin.Engine, db *database.DB, cfg *config.Config) (repository.ArchiveRepository, service.WebhooksService, service.BackupsService, service.TrashService) {
+  var adminToken = cfg.Admin.Token
+  var tokens = service.NewTokensService(repository.NewTokensRepository(db))
+
+  // The changes to the content are committed along with the events
+  // they cause.
+  var unit = repository.NewUnitOfWork(db)
+
+  var (
+    webhooksService = service.NewWebhooksService(repository.NewWebhooksRepository(db))
+    webhooks        = handler.NewWebhooksHandler(webhooksService)
+  )
+
+  engine.POST("/webhooks.add", handler.RequireToken(adminToken, tokens), webhooks.Add)
+  engine.GET("/webhooks.list", handler.RequireToken(adminToken, tokens), webhooks.Get)
+  engine.POST("/webhooks.remove", handler.RequireToken(adminToken, tokens), webhooks.Remove)
+  engine.GET("/webhooks.deliveries.list", handler.RequireToken(adminToken, tokens), webhooks.Deliveries)
+  engine.GET("/webhooks.events.list", handler.RequireToken(adminToken, tokens), webhooks.Events)
+
+  var (
+    meService = service.NewMeService(repository.NewMeRepository(db), unit, webhooksService)
+    me        = handler.NewMeHandler(meService)
+  )
+
+  engine.GET("/me.info", me.Get)
+  engine.POST("/me.setPhoto", me.SetPhoto)
+  engine.POST("/me.setResume", me.SetResume)
+  engine.POST("/me.setHireable", me.SetHireable)
+  engine.POST("/me.set", me.Update)
+  engine.POST("/me.authenticate", me.Authenticate)
+  engine.POST("/me.deauthenticate", me.Deauthenticate)
+
+  var (
+    experienceRepository = repository.NewExperienceRepository(db)
+    experienceService    = service.NewExperienceService(experienceRepository, unit, webhooksService)
+    experience           = handler.NewExperienceHandler(experienceService)
+  )
+
+  engine.GET("/me.experience.list", experience.Get)
+  engine.GET("/me.experience.hidden.list", experience.GetHidden)
+  engine.GET("/me.experience.info", experience.GetByID)
+  engine.POST("/me.experience.add", experience.Add)
+  engine.POST("/me.experience.set", experience.Set)
+  engine.POST("/me.experience.hide", experience.Hide)
+  engine.POST("/me.experience.show", experience.Show)
+  engine.POST("/me.experience.quit", experience.Quit)
+  engine.POST("/me.experience.remove", experience.Remove)
+
+  var (
+    technologyTagRepository = repository.NewTechnologyTagRepository(db)
+    technologyTagService    = service.NewTechnologyTagService(technologyTagRepository)
+    technologies            = handler.NewTechnologyTagHandler(technologyTagService)
+  )
+
+  engine.GET("/technologies.list", technologies.Get)
+  engine.GET("/technologies.info", technologies.GetByID)
+  engine.POST("/technologies.add", technologies.Add)
+  engine.POST("/technologies.set", technologies.Set)
+  engine.POST("/technologies.remove", technologies.Remove)
+
+  var (
+    projectsRepository = repository.NewProjectsRepository(db)
+    projectsService    = service.NewProjectsService(projectsRepository, unit, webhooksService, cfg.Archive.ReadingSpeed)
+    projects           = handler.NewProjectsHandler(projectsService)
+  )
+
+  engine.GET("/me.projects.list", projects.Get)
+  engine.GET("/me.projects.info", projects.GetByID)
+  engine.GET("/me.projects.archived.list", projects.GetArchived)
+  engine.POST("/me.projects.add", projects.Add)
+  engine.POST("/me.projects.set", projects.Set)
+  engine.POST("/me.projects.archive", projects.Archive)
+  engine.POST("/me.projects.unarchive", projects.Unarchive)
+  engine.POST("/me.projects.finish", projects.Finish)
+  engine.POST("/me.projects.unfinish", projects.Unfinish)
+  engine.POST("/me.projects.remove", projects.Remove)
+  engine.POST("/me.projects.setPlaygroundURL", projects.SetPlaygroundURL)
+  engine.POST("/me.projects.setFirstImageURL", projects.SetFirstImageURL)
+  engine.POST("/me.projects.setSecondImageURL", projects.SetSecondImageURL)
+  engine.POST("/me.projects.setGitHubURL", projects.SetGitHubURL)
+  engine.POST("/me.projects.setCollectionURL", projects.SetCollectionURL)
+  engine.POST("/me.projects.technologies.add", projects.AddTechnologyTag)
+  engine.POST("/me.projects.technologies.remove", projects.RemoveTechnologyTag)
+
+  var archive = repository.NewArchiveRepository(db, cfg.Archive.ShareExpiry)
+
+  var (
+    tagsRepository = repository.NewTagsRepository(db)
+    tagsService    = service.NewTagsService(tagsRepository, unit)
+    tags           = handler.NewTagsHandler(tagsService)
+  )
+
+  engine.POST("/archive.tags.add", tags.Add)
+  engine.GET("/archive.tags.list", tags.Get)
+  engine.GET("/archive.tags.info", tags.GetByID)
+  engine.POST("/archive.tags.set", tags.Update)
+  engine.POST("/archive.tags.remove", tags.Remove)
+
+  var (
+    topicsRepository = repository.NewTopicsRepository(db)
+    topicsService    = service.NewTopicsService(topicsRepository, unit)
+    topics           = handler.NewTopicsHandler(topicsService)
+  )
+
+  engine.POST("/archive.topics.add", topics.Add)
+  engine.GET("/archive.topics.list", topics.Get)
+  engine.GET("/archive.topics.info", topics.GetByID)
+  engine.POST("/archive.topics.set", topics.Update)
+  engine.POST("/archive.topics.remove", topics.Remove)
+
+  var (
+    draftsService = service.NewDraftsService(archive, unit, webhooksService, cfg.Archive.ReadingSpeed)
+    drafts        = handler.NewDraftsHandler(draftsService, cfg.Archive.PageSize)
+  )
+
+  engine.POST("/archive.drafts.start", drafts.Start)
+  engine.POST("/archive.drafts.publish", drafts.Publish)
+  engine.GET("/archive.drafts.list", drafts.Get)
+  engine.GET("/archive.drafts.info", drafts.GetByID)
+  engine.POST("/archive.drafts.share", drafts.Share)
+  engine.POST("/archive.drafts.revise", drafts.Revise)
+  engine.POST("/archive.drafts.discard", drafts.Discard)
+  engine.POST("/archive.drafts.tags.add", drafts.AddTag)
+  engine.POST("/archive.drafts.tags.remove", drafts.RemoveTag)
+
+  var (
+    articlesService = service.NewArticlesService(archive, unit, webhooksService)
+    articles        = handler.NewArticlesHandler(articlesService, cfg.Archive.PageSize)
+  )
+
+  engine.GET("/archive.articles.list", articles.Get)
+  engine.GET("/archive.articles.hidden.list", articles.GetHidden)
+  engine.GET("/archive.articles.info", articles.GetByID)
+  engine.POST("/archive.articles.amend", articles.Amend)
+  engine.POST("/archive.articles.setSlug", articles.SetSlug)
+  engine.POST("/archive.articles.hide", articles.Hide)
+  engine.POST("/archive.articles.show", articles.Show)
+  engine.POST("/archive.articles.remove", articles.Remove)
+  engine.POST("/archive.articles.pin", articles.Pin)
+  engine.POST("/archive.articles.unpin", articles.Unpin)
+  engine.POST("/archive.articles.tags.add", articles.AddTag)
+  engine.POST("/archive.articles.tags.remove", articles.RemoveTag)
+  engine.POST("/archive.articles.views.flush", handler.RequireToken(adminToken, tokens), articles.FlushViews)
+
+  var (
+    patchesServices = service.NewPatchesService(archive, unit, webhooksService, cfg.Archive.ReadingSpeed)
+    patches         = handler.NewPatchesHandler(patchesServices)
+  )
+
+  engine.GET("/archive.articles.patches.list", patches.Get)
+  engine.GET("/archive.articles.patches.diff", patches.Diff)
+  engine.POST("/archive.articles.patches.revise", patches.Revise)
+  engine.POST("/archive.articles.patches.share", patches.Share)
+  engine.POST("/archive.articles.patches.discard", patches.Discard)
+  engine.POST("/archive.articles.patches.release", patches.Release)
+
+  var imports = handler.NewImportHandler(service.NewImportService(archive, cfg.Archive.ReadingSpeed))
+
+  engine.POST("/archive.import", handler.RequireToken(adminToken, tokens), imports.Import)
+
+  var site = handler.NewSiteHandler(service.NewSiteService(repository.NewSiteRepository(db)))
+
+  engine.GET("/site.export", handler.RequireToken(adminToken, tokens), site.Export)
+
+  var (
+    backupsService = service.NewBackupsService(repository.NewBackupsRepository(db), cfg.Backup.Dir, cfg.Backup.Retention)
+    backups        = handler.NewBackupsHandler(backupsService)
+  )
+
+  engine.POST("/site.backup", handler.RequireToken(adminToken, tokens), backups.Create)
+
+  var (
+    trashService = service.NewTrashService(
+      repository.NewTrashRepository(db),
+      archive,
+      projectsRepository,
+      experienceRepository,
+      tagsService,
+      topicsService,
+      cfg.Trash.Retention,
+    )
+    trash = handler.NewTrashHandler(trashService)
+  )
+
+  engine.GET("/trash.list", handler.RequireToken(adminToken, tokens), trash.Get)
+  engine.POST("/trash.restore", handler.RequireToken(adminToken, tokens), trash.Restore)
+
+  var web = handler.NewWebHandler(
+    meService,
+    experienceService,
+    projectsService,
+    draftsService,
+    articlesService,
+    patchesServices,
+    topicsService,
+    tagsService,
+  )
+
+  routeWeb(engine, web)
+
+  var panel = handler.NewAdminHandler(
+    adminToken,
+    meService,
+    draftsService,
+    articlesService,
+    patchesServices,
+    projectsService,
+    experienceService,
+    topicsService,
+    tagsService,
+    technologyTagService,
+  )
+
+  routeAdmin(engine, panel, adminToken, tokens)
+
+  routeAPIDocument(engine)
+
+  return archive, webhooksService, backupsService, trashService
+}
\ No newline at end of file
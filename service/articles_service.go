@@ -5,7 +5,9 @@ import (
   "errors"
   "fontseca.dev/model"
   "fontseca.dev/repository"
+  "fontseca.dev/tracing"
   "fontseca.dev/transfer"
+  "github.com/google/uuid"
   "log/slog"
   "strings"
 )
@@ -20,6 +22,10 @@ type ArticlesService interface {
   // in filter.Search.
   Get(ctx context.Context, filter *transfer.ArticleFilter) (articles []*transfer.Article, err error)
 
+  // Count counts the published articles, either hidden or not, that
+  // match filter, regardless of its pagination.
+  Count(ctx context.Context, filter *transfer.ArticleFilter, hidden bool) (total int, err error)
+
   // Publications retrieves a list of distinct months during which articles have been published.
   Publications(ctx context.Context) (publications []*transfer.Publication, err error)
 
@@ -47,14 +53,14 @@ type ArticlesService interface {
   // a public copy of it is kept available to everyone while a patch
   // is created to store any revision made to the article.
   //
-  // If the article is already being amended, any call to this method has
-  // no effect.
-  Amend(ctx context.Context, id string) error
+  // An article can be amended several times at once; each call creates
+  // a new patch and returns its UUID.
+  Amend(ctx context.Context, id string) (patchUUID uuid.UUID, err error)
 
   // SetSlug changes the slug of an article.
   SetSlug(ctx context.Context, id, slug string) error
 
-  // Remove completely removes an article and any patch it currently has.
+  // Remove moves an article to the trash, along with its patches.
   Remove(ctx context.Context, id string) error
 
   // Pin pins an article.
@@ -71,14 +77,22 @@ type ArticlesService interface {
   // has no tag identified by its UUID, it returns an error indication
   // a not found state.
   RemoveTag(ctx context.Context, articleUUID, tagID string) error
+
+  // FlushViews writes the views counted since the last midnight to the
+  // articles right away.
+  FlushViews(ctx context.Context) error
 }
 
 type articlesService struct {
-  r repository.ArchiveRepository
+  r      repository.ArchiveRepository
+  unit   repository.UnitOfWork
+  events Publisher
 }
 
-func NewArticlesService(r repository.ArchiveRepository) ArticlesService {
-  return &articlesService{r}
+// NewArticlesService returns an ArticlesService that publishes the
+// events of the changes to the articles within unit, along with them.
+func NewArticlesService(r repository.ArchiveRepository, unit repository.UnitOfWork, events Publisher) ArticlesService {
+  return &articlesService{r, unit, events}
 }
 
 func (s *articlesService) doGet(ctx context.Context, filter *transfer.ArticleFilter, hidden ...bool) (articles []*transfer.Article, err error) {
@@ -90,21 +104,40 @@ func (s *articlesService) doGet(ctx context.Context, filter *transfer.ArticleFil
 }
 
 func (s *articlesService) Get(ctx context.Context, filter *transfer.ArticleFilter) (articles []*transfer.Article, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
   return s.doGet(ctx, filter)
 }
 
+func (s *articlesService) Count(ctx context.Context, filter *transfer.ArticleFilter, hidden bool) (total int, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
+  return s.r.Count(ctx, filter, hidden, false)
+}
+
 func (s *articlesService) Publications(ctx context.Context) (publications []*transfer.Publication, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
   return s.r.Publications(ctx)
 }
 
 func (s *articlesService) GetHidden(ctx context.Context, filter *transfer.ArticleFilter) (articles []*transfer.Article, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
   return s.doGet(ctx, filter, true)
 }
 
 func (s *articlesService) GetOne(ctx context.Context, request *transfer.ArticleRequest) (article *model.Article, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
   if nil == request {
     err = errors.New("nil value for parameter: request")
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return nil, err
   }
 
@@ -112,6 +145,9 @@ func (s *articlesService) GetOne(ctx context.Context, request *transfer.ArticleR
 }
 
 func (s *articlesService) GetByID(ctx context.Context, articleUUID string) (article *model.Article, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
   if err = validateUUID(&articleUUID); nil != err {
     return nil, err
   }
@@ -120,22 +156,43 @@ func (s *articlesService) GetByID(ctx context.Context, articleUUID string) (arti
 }
 
 func (s *articlesService) Hide(ctx context.Context, id string) error {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
   if err := validateUUID(&id); nil != err {
     return err
   }
 
-  return s.r.SetHidden(ctx, id, true)
+  return within(ctx, s.unit, func(ctx context.Context) error {
+    if err := s.r.SetHidden(ctx, id, true); nil != err {
+      return err
+    }
+
+    return publish(ctx, s.events, ArticleHidden, "article_uuid", id)
+  })
 }
 
 func (s *articlesService) Show(ctx context.Context, id string) error {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
   if err := validateUUID(&id); nil != err {
     return err
   }
 
-  return s.r.SetHidden(ctx, id, false)
+  return within(ctx, s.unit, func(ctx context.Context) error {
+    if err := s.r.SetHidden(ctx, id, false); nil != err {
+      return err
+    }
+
+    return publish(ctx, s.events, ArticleShown, "article_uuid", id)
+  })
 }
 
 func (s *articlesService) SetSlug(ctx context.Context, id, slug string) error {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
   if err := validateUUID(&id); nil != err {
     return err
   }
@@ -146,53 +203,119 @@ func (s *articlesService) SetSlug(ctx context.Context, id, slug string) error {
     return nil
   }
 
-  return s.r.SetSlug(ctx, id, generateSlug(slug))
+  return within(ctx, s.unit, func(ctx context.Context) error {
+    if err := s.r.SetSlug(ctx, id, generateSlug(slug)); nil != err {
+      return err
+    }
+
+    return publish(ctx, s.events, ArticleUpdated, "article_uuid", id)
+  })
 }
 
-func (s *articlesService) Amend(ctx context.Context, id string) error {
-  if err := validateUUID(&id); nil != err {
-    return err
+func (s *articlesService) Amend(ctx context.Context, id string) (patchUUID uuid.UUID, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
+  if err = validateUUID(&id); nil != err {
+    return uuid.Nil, err
   }
 
-  return s.r.Amend(ctx, id)
+  patchID, err := s.r.Amend(ctx, id)
+  if nil != err {
+    return uuid.Nil, err
+  }
+
+  return uuid.Parse(patchID)
 }
 
 func (s *articlesService) Remove(ctx context.Context, id string) error {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
   if err := validateUUID(&id); nil != err {
     return err
   }
 
-  return s.r.Remove(ctx, id)
+  return within(ctx, s.unit, func(ctx context.Context) error {
+    if err := s.r.Remove(ctx, id); nil != err {
+      return err
+    }
+
+    return publish(ctx, s.events, ArticleRemoved, "article_uuid", id)
+  })
 }
 
 func (s *articlesService) Pin(ctx context.Context, id string) error {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
   if err := validateUUID(&id); nil != err {
     return err
   }
 
-  return s.r.SetPinned(ctx, id, true)
+  return within(ctx, s.unit, func(ctx context.Context) error {
+    if err := s.r.SetPinned(ctx, id, true); nil != err {
+      return err
+    }
+
+    return publish(ctx, s.events, ArticleUpdated, "article_uuid", id)
+  })
 }
 
 func (s *articlesService) Unpin(ctx context.Context, id string) error {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
   if err := validateUUID(&id); nil != err {
     return err
   }
 
-  return s.r.SetPinned(ctx, id, false)
+  return within(ctx, s.unit, func(ctx context.Context) error {
+    if err := s.r.SetPinned(ctx, id, false); nil != err {
+      return err
+    }
+
+    return publish(ctx, s.events, ArticleUpdated, "article_uuid", id)
+  })
 }
 
 func (s *articlesService) AddTag(ctx context.Context, articleUUID, tagID string) error {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
   if err := validateUUID(&articleUUID); nil != err {
     return err
   }
 
-  return s.r.AddTag(ctx, articleUUID, tagID)
+  return within(ctx, s.unit, func(ctx context.Context) error {
+    if err := s.r.AddTag(ctx, articleUUID, tagID); nil != err {
+      return err
+    }
+
+    return publish(ctx, s.events, ArticleUpdated, "article_uuid", articleUUID)
+  })
 }
 
 func (s *articlesService) RemoveTag(ctx context.Context, articleUUID, tagID string) error {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
   if err := validateUUID(&articleUUID); nil != err {
     return err
   }
 
-  return s.r.RemoveTag(ctx, articleUUID, tagID)
+  return within(ctx, s.unit, func(ctx context.Context) error {
+    if err := s.r.RemoveTag(ctx, articleUUID, tagID); nil != err {
+      return err
+    }
+
+    return publish(ctx, s.events, ArticleUpdated, "article_uuid", articleUUID)
+  })
+}
+
+func (s *articlesService) FlushViews(ctx context.Context) error {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
+  return s.r.FlushViews(ctx)
 }
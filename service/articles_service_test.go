@@ -24,7 +24,7 @@ func TestArticlesService_Get(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.On(routine, ctx, filter, false, false).Return(expectedArticles, nil)
 
-    articles, err := NewArticlesService(r).Get(ctx, filter)
+    articles, err := NewArticlesService(r, nil, nil).Get(ctx, filter)
 
     assert.Equal(t, expectedArticles, articles)
     assert.NoError(t, err)
@@ -36,7 +36,7 @@ func TestArticlesService_Get(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.On(routine, ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil, unexpected)
 
-    _, err := NewArticlesService(r).Get(ctx, filter)
+    _, err := NewArticlesService(r, nil, nil).Get(ctx, filter)
     assert.ErrorIs(t, err, unexpected)
   })
 }
@@ -53,7 +53,7 @@ func TestArticlesService_GetHidden(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.On(routine, ctx, filter, true, false).Return(expectedArticles, nil)
 
-    articles, err := NewArticlesService(r).GetHidden(ctx, filter)
+    articles, err := NewArticlesService(r, nil, nil).GetHidden(ctx, filter)
 
     assert.Equal(t, expectedArticles, articles)
     assert.NoError(t, err)
@@ -65,7 +65,36 @@ func TestArticlesService_GetHidden(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.On(routine, ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil, unexpected)
 
-    _, err := NewArticlesService(r).GetHidden(ctx, filter)
+    _, err := NewArticlesService(r, nil, nil).GetHidden(ctx, filter)
+    assert.ErrorIs(t, err, unexpected)
+  })
+}
+
+func TestArticlesService_Count(t *testing.T) {
+  const routine = "Count"
+
+  ctx := context.TODO()
+  filter := &transfer.ArticleFilter{}
+
+  t.Run("success", func(t *testing.T) {
+    for _, hidden := range []bool{false, true} {
+      r := mocks.NewArchiveRepository()
+      r.On(routine, ctx, filter, hidden, false).Return(3, nil)
+
+      total, err := NewArticlesService(r, nil, nil).Count(ctx, filter, hidden)
+
+      assert.Equal(t, 3, total)
+      assert.NoError(t, err)
+    }
+  })
+
+  t.Run("gets a repository failure", func(t *testing.T) {
+    unexpected := errors.New("unexpected error")
+
+    r := mocks.NewArchiveRepository()
+    r.On(routine, ctx, mock.Anything, mock.Anything, mock.Anything).Return(0, unexpected)
+
+    _, err := NewArticlesService(r, nil, nil).Count(ctx, filter, false)
     assert.ErrorIs(t, err, unexpected)
   })
 }
@@ -82,7 +111,7 @@ func TestArticlesService_GetByID(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.On(routine, ctx, id, false).Return(expectedArticle, nil)
 
-    article, err := NewArticlesService(r).GetByID(ctx, id)
+    article, err := NewArticlesService(r, nil, nil).GetByID(ctx, id)
 
     assert.Equal(t, expectedArticle, article)
     assert.NoError(t, err)
@@ -94,7 +123,7 @@ func TestArticlesService_GetByID(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.On(routine, mock.Anything, mock.Anything, mock.Anything).Return(nil, unexpected)
 
-    article, err := NewArticlesService(r).GetByID(ctx, id)
+    article, err := NewArticlesService(r, nil, nil).GetByID(ctx, id)
 
     assert.Nil(t, article)
     assert.ErrorIs(t, err, unexpected)
@@ -106,7 +135,7 @@ func TestArticlesService_GetByID(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.AssertNotCalled(t, routine)
 
-    _, err := NewArticlesService(r).GetByID(ctx, id)
+    _, err := NewArticlesService(r, nil, nil).GetByID(ctx, id)
 
     assert.Error(t, err)
   })
@@ -122,7 +151,18 @@ func TestArticlesService_Hide(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.On(routine, ctx, id, true).Return(nil)
 
-    assert.NoError(t, NewArticlesService(r).Hide(ctx, id))
+    assert.NoError(t, NewArticlesService(r, nil, nil).Hide(ctx, id))
+  })
+
+  t.Run("publishes the event", func(t *testing.T) {
+    r := mocks.NewArchiveRepository()
+    r.On(routine, ctx, id, true).Return(nil)
+
+    p := mocks.NewPublisher()
+    p.On("Publish", ctx, ArticleHidden, map[string]string{"article_uuid": id}).Return(nil)
+
+    assert.NoError(t, NewArticlesService(r, nil, p).Hide(ctx, id))
+    p.AssertExpectations(t)
   })
 
   t.Run("gets a repository failure", func(t *testing.T) {
@@ -131,7 +171,7 @@ func TestArticlesService_Hide(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.On(routine, mock.Anything, mock.Anything, mock.Anything).Return(unexpected)
 
-    assert.ErrorIs(t, NewArticlesService(r).Hide(ctx, id), unexpected)
+    assert.ErrorIs(t, NewArticlesService(r, nil, nil).Hide(ctx, id), unexpected)
   })
 
   t.Run("wrong uuid", func(t *testing.T) {
@@ -140,7 +180,7 @@ func TestArticlesService_Hide(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.AssertNotCalled(t, routine)
 
-    assert.Error(t, NewArticlesService(r).Hide(ctx, id))
+    assert.Error(t, NewArticlesService(r, nil, nil).Hide(ctx, id))
   })
 }
 
@@ -154,7 +194,7 @@ func TestArticlesService_Show(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.On(routine, ctx, id, false).Return(nil)
 
-    assert.NoError(t, NewArticlesService(r).Show(ctx, id))
+    assert.NoError(t, NewArticlesService(r, nil, nil).Show(ctx, id))
   })
 
   t.Run("gets a repository failure", func(t *testing.T) {
@@ -163,7 +203,7 @@ func TestArticlesService_Show(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.On(routine, mock.Anything, mock.Anything, mock.Anything).Return(unexpected)
 
-    assert.ErrorIs(t, NewArticlesService(r).Show(ctx, id), unexpected)
+    assert.ErrorIs(t, NewArticlesService(r, nil, nil).Show(ctx, id), unexpected)
   })
 
   t.Run("wrong uuid", func(t *testing.T) {
@@ -172,7 +212,7 @@ func TestArticlesService_Show(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.AssertNotCalled(t, routine)
 
-    assert.Error(t, NewArticlesService(r).Show(ctx, id))
+    assert.Error(t, NewArticlesService(r, nil, nil).Show(ctx, id))
   })
 }
 
@@ -183,19 +223,25 @@ func TestArticlesService_Amend(t *testing.T) {
   id := uuid.NewString()
 
   t.Run("success", func(t *testing.T) {
+    patch := uuid.New()
+
     r := mocks.NewArchiveRepository()
-    r.On(routine, ctx, id).Return(nil)
+    r.On(routine, ctx, id).Return(patch.String(), nil)
 
-    assert.NoError(t, NewArticlesService(r).Amend(ctx, id))
+    patchUUID, err := NewArticlesService(r, nil, nil).Amend(ctx, id)
+    assert.NoError(t, err)
+    assert.Equal(t, patch, patchUUID)
   })
 
   t.Run("gets a repository failure", func(t *testing.T) {
     unexpected := errors.New("unexpected error")
 
     r := mocks.NewArchiveRepository()
-    r.On(routine, mock.Anything, mock.Anything).Return(unexpected)
+    r.On(routine, mock.Anything, mock.Anything).Return("", unexpected)
 
-    assert.ErrorIs(t, NewArticlesService(r).Amend(ctx, id), unexpected)
+    patchUUID, err := NewArticlesService(r, nil, nil).Amend(ctx, id)
+    assert.ErrorIs(t, err, unexpected)
+    assert.Equal(t, uuid.Nil, patchUUID)
   })
 
   t.Run("wrong uuid", func(t *testing.T) {
@@ -204,7 +250,8 @@ func TestArticlesService_Amend(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.AssertNotCalled(t, routine)
 
-    assert.Error(t, NewArticlesService(r).Amend(ctx, id))
+    _, err := NewArticlesService(r, nil, nil).Amend(ctx, id)
+    assert.Error(t, err)
   })
 }
 
@@ -218,7 +265,7 @@ func TestArticlesService_Remove(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.On(routine, ctx, id).Return(nil)
 
-    assert.NoError(t, NewArticlesService(r).Remove(ctx, id))
+    assert.NoError(t, NewArticlesService(r, nil, nil).Remove(ctx, id))
   })
 
   t.Run("gets a repository failure", func(t *testing.T) {
@@ -227,7 +274,7 @@ func TestArticlesService_Remove(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.On(routine, mock.Anything, mock.Anything).Return(unexpected)
 
-    assert.ErrorIs(t, NewArticlesService(r).Remove(ctx, id), unexpected)
+    assert.ErrorIs(t, NewArticlesService(r, nil, nil).Remove(ctx, id), unexpected)
   })
 
   t.Run("wrong uuid", func(t *testing.T) {
@@ -236,7 +283,7 @@ func TestArticlesService_Remove(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.AssertNotCalled(t, routine)
 
-    assert.Error(t, NewArticlesService(r).Remove(ctx, id))
+    assert.Error(t, NewArticlesService(r, nil, nil).Remove(ctx, id))
   })
 }
 
@@ -250,7 +297,7 @@ func TestArticlesService_Pin(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.On(routine, ctx, id, true).Return(nil)
 
-    assert.NoError(t, NewArticlesService(r).Pin(ctx, id))
+    assert.NoError(t, NewArticlesService(r, nil, nil).Pin(ctx, id))
   })
 
   t.Run("gets a repository failure", func(t *testing.T) {
@@ -259,7 +306,7 @@ func TestArticlesService_Pin(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.On(routine, mock.Anything, mock.Anything, mock.Anything).Return(unexpected)
 
-    assert.ErrorIs(t, NewArticlesService(r).Pin(ctx, id), unexpected)
+    assert.ErrorIs(t, NewArticlesService(r, nil, nil).Pin(ctx, id), unexpected)
   })
 
   t.Run("wrong uuid", func(t *testing.T) {
@@ -268,7 +315,7 @@ func TestArticlesService_Pin(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.AssertNotCalled(t, routine)
 
-    assert.Error(t, NewArticlesService(r).Pin(ctx, id))
+    assert.Error(t, NewArticlesService(r, nil, nil).Pin(ctx, id))
   })
 }
 
@@ -282,7 +329,7 @@ func TestArticlesService_Unpin(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.On(routine, ctx, id, false).Return(nil)
 
-    assert.NoError(t, NewArticlesService(r).Unpin(ctx, id))
+    assert.NoError(t, NewArticlesService(r, nil, nil).Unpin(ctx, id))
   })
 
   t.Run("gets a repository failure", func(t *testing.T) {
@@ -291,7 +338,7 @@ func TestArticlesService_Unpin(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.On(routine, mock.Anything, mock.Anything, mock.Anything).Return(unexpected)
 
-    assert.ErrorIs(t, NewArticlesService(r).Unpin(ctx, id), unexpected)
+    assert.ErrorIs(t, NewArticlesService(r, nil, nil).Unpin(ctx, id), unexpected)
   })
 
   t.Run("wrong uuid", func(t *testing.T) {
@@ -300,7 +347,7 @@ func TestArticlesService_Unpin(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.AssertNotCalled(t, routine)
 
-    assert.Error(t, NewArticlesService(r).Unpin(ctx, id))
+    assert.Error(t, NewArticlesService(r, nil, nil).Unpin(ctx, id))
   })
 }
 
@@ -315,7 +362,7 @@ func TestArticlesService_AddTag(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.On(routine, ctx, articleUUID, tagID, mock.Anything).Return(nil)
 
-    assert.NoError(t, NewArticlesService(r).AddTag(ctx, articleUUID, tagID))
+    assert.NoError(t, NewArticlesService(r, nil, nil).AddTag(ctx, articleUUID, tagID))
   })
 
   t.Run("wrong draft uuid", func(t *testing.T) {
@@ -324,7 +371,7 @@ func TestArticlesService_AddTag(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.AssertNotCalled(t, routine)
 
-    assert.Error(t, NewArticlesService(r).AddTag(ctx, articleUUID, tagID))
+    assert.Error(t, NewArticlesService(r, nil, nil).AddTag(ctx, articleUUID, tagID))
   })
 
   articleUUID = uuid.NewString()
@@ -335,7 +382,7 @@ func TestArticlesService_AddTag(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.On(routine, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(unexpected)
 
-    err := NewArticlesService(r).AddTag(ctx, articleUUID, uuid.NewString())
+    err := NewArticlesService(r, nil, nil).AddTag(ctx, articleUUID, uuid.NewString())
 
     assert.ErrorIs(t, err, unexpected)
   })
@@ -352,14 +399,14 @@ func TestArticlesService_RemoveTag(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.On(routine, ctx, articleUUID, tagID, mock.Anything).Return(nil)
 
-    assert.NoError(t, NewArticlesService(r).RemoveTag(ctx, articleUUID, tagID))
+    assert.NoError(t, NewArticlesService(r, nil, nil).RemoveTag(ctx, articleUUID, tagID))
   })
 
   t.Run("wrong draft uuid", func(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.AssertNotCalled(t, routine)
 
-    assert.Error(t, NewArticlesService(r).RemoveTag(ctx, "e4d06ba7-f086-47dc-9f5e", tagID))
+    assert.Error(t, NewArticlesService(r, nil, nil).RemoveTag(ctx, "e4d06ba7-f086-47dc-9f5e", tagID))
   })
 
   t.Run("gets a repository failure", func(t *testing.T) {
@@ -368,8 +415,30 @@ func TestArticlesService_RemoveTag(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.On(routine, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(unexpected)
 
-    err := NewArticlesService(r).RemoveTag(ctx, articleUUID, uuid.NewString())
+    err := NewArticlesService(r, nil, nil).RemoveTag(ctx, articleUUID, uuid.NewString())
 
     assert.ErrorIs(t, err, unexpected)
   })
 }
+
+func TestArticlesService_FlushViews(t *testing.T) {
+  const routine = "FlushViews"
+
+  ctx := context.TODO()
+
+  t.Run("success", func(t *testing.T) {
+    r := mocks.NewArchiveRepository()
+    r.On(routine, ctx).Return(nil)
+
+    assert.NoError(t, NewArticlesService(r, nil, nil).FlushViews(ctx))
+  })
+
+  t.Run("gets a repository failure", func(t *testing.T) {
+    unexpected := errors.New("unexpected error")
+
+    r := mocks.NewArchiveRepository()
+    r.On(routine, mock.Anything).Return(unexpected)
+
+    assert.ErrorIs(t, NewArticlesService(r, nil, nil).FlushViews(ctx), unexpected)
+  })
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+  "context"
+  "fontseca.dev/repository"
+  "fontseca.dev/tracing"
+  "fontseca.dev/transfer"
+  "log/slog"
+  "os"
+  "path/filepath"
+  "sort"
+  "strings"
+  "sync"
+  "time"
+)
+
+const (
+  // backupPrefix and backupSuffix enclose the time at which a backup
+  // in the directory of the backups was made, formatted as
+  // backupLayout, so that their names sort chronologically.
+  backupPrefix = "db-"
+  backupSuffix = ".sqlite"
+  backupLayout = "20060102T150405.000"
+)
+
+// BackupsService is a high level provider for backups of the database.
+// Backups are kept in a directory of their own, and only the most
+// recent ones are retained.
+type BackupsService interface {
+  // Create backs up the database into the directory of the backups
+  // and removes the oldest backups past the retention.
+  Create(ctx context.Context) (backup *transfer.Backup, err error)
+
+  // Get retrieves the backups in the directory of the backups, the
+  // most recent first.
+  Get(ctx context.Context) (backups []*transfer.Backup, err error)
+
+  // Run creates a backup every interval until ctx is done.
+  Run(ctx context.Context, interval time.Duration)
+}
+
+type backupsService struct {
+  r         repository.BackupsRepository
+  dir       string
+  retention int
+  mu        sync.Mutex // serializes the backups so their retention holds
+}
+
+// NewBackupsService returns a BackupsService that keeps the retention
+// most recent backups in dir.
+func NewBackupsService(r repository.BackupsRepository, dir string, retention int) BackupsService {
+  return &backupsService{r: r, dir: dir, retention: retention}
+}
+
+func (s *backupsService) Create(ctx context.Context) (backup *transfer.Backup, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
+  s.mu.Lock()
+  defer s.mu.Unlock()
+
+  if err = os.MkdirAll(s.dir, 0755); nil != err {
+    slog.ErrorContext(ctx, err.Error())
+    return nil, err
+  }
+
+  var name = backupPrefix + time.Now().UTC().Format(backupLayout) + backupSuffix
+  var file = filepath.Join(s.dir, name)
+
+  if err = s.r.Backup(ctx, file); nil != err {
+    return nil, err
+  }
+
+  info, err := os.Stat(file)
+  if nil != err {
+    slog.ErrorContext(ctx, err.Error())
+    return nil, err
+  }
+
+  slog.InfoContext(ctx, "backed up database", slog.String("file", file), slog.Int64("size", info.Size()))
+
+  s.prune(ctx)
+
+  return &transfer.Backup{Name: name, Size: info.Size(), CreatedAt: info.ModTime().UTC()}, nil
+}
+
+// prune removes the oldest backups past the retention.
+func (s *backupsService) prune(ctx context.Context) {
+  backups, err := s.Get(ctx)
+  if nil != err {
+    return
+  }
+
+  for _, backup := range backups[min(s.retention, len(backups)):] {
+    if err = os.Remove(filepath.Join(s.dir, backup.Name)); nil != err {
+      slog.ErrorContext(ctx, err.Error())
+      continue
+    }
+
+    slog.InfoContext(ctx, "removed old backup", slog.String("name", backup.Name))
+  }
+}
+
+func (s *backupsService) Get(ctx context.Context) (backups []*transfer.Backup, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
+  entries, err := os.ReadDir(s.dir)
+  if nil != err {
+    if os.IsNotExist(err) {
+      return []*transfer.Backup{}, nil
+    }
+
+    slog.ErrorContext(ctx, err.Error())
+    return nil, err
+  }
+
+  backups = make([]*transfer.Backup, 0, len(entries))
+  for _, entry := range entries {
+    var name = entry.Name()
+    if entry.IsDir() || !strings.HasPrefix(name, backupPrefix) || !strings.HasSuffix(name, backupSuffix) {
+      continue
+    }
+
+    info, err := entry.Info()
+    if nil != err {
+      continue
+    }
+
+    backups = append(backups, &transfer.Backup{Name: name, Size: info.Size(), CreatedAt: info.ModTime().UTC()})
+  }
+
+  sort.Slice(backups, func(i, j int) bool { return backups[i].Name > backups[j].Name })
+
+  return backups, nil
+}
+
+func (s *backupsService) Run(ctx context.Context, interval time.Duration) {
+  var ticker = time.NewTicker(interval)
+  defer ticker.Stop()
+
+  for {
+    select {
+    case <-ctx.Done():
+      return
+    case <-ticker.C:
+      if _, err := s.Create(ctx); nil != err && nil == ctx.Err() {
+        slog.Error("could not back up database: " + err.Error())
+      }
+    }
+  }
+}
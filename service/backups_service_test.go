@@ -0,0 +1,94 @@
+package service
+
+import (
+  "context"
+  "errors"
+  "fontseca.dev/mocks"
+  "github.com/stretchr/testify/assert"
+  "github.com/stretchr/testify/mock"
+  "os"
+  "path/filepath"
+  "testing"
+)
+
+func TestBackupsService_Create(t *testing.T) {
+  const routine = "Backup"
+
+  ctx := context.TODO()
+
+  t.Run("success", func(t *testing.T) {
+    var dir = filepath.Join(t.TempDir(), "backups")
+    r := mocks.NewBackupsRepository()
+    r.On(routine, mock.Anything, mock.AnythingOfType("string")).
+      Run(func(args mock.Arguments) { _ = os.WriteFile(args.String(1), []byte("backup"), 0644) }).
+      Return(nil)
+
+    backup, err := NewBackupsService(r, dir, 3).Create(ctx)
+    assert.NoError(t, err)
+    if assert.NotNil(t, backup) {
+      assert.Regexp(t, `^db-\d{8}T\d{6}\.\d{3}\.sqlite$`, backup.Name)
+      assert.Equal(t, int64(len("backup")), backup.Size)
+      assert.FileExists(t, filepath.Join(dir, backup.Name))
+    }
+    r.AssertExpectations(t)
+  })
+
+  t.Run("removes the backups past the retention", func(t *testing.T) {
+    var dir = t.TempDir()
+    for _, name := range []string{"db-20240101T000000.000.sqlite", "db-20240102T000000.000.sqlite", "db-20240103T000000.000.sqlite", "notes.txt"} {
+      assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("old"), 0644))
+    }
+
+    r := mocks.NewBackupsRepository()
+    r.On(routine, mock.Anything, mock.AnythingOfType("string")).
+      Run(func(args mock.Arguments) { _ = os.WriteFile(args.String(1), []byte("backup"), 0644) }).
+      Return(nil)
+
+    backup, err := NewBackupsService(r, dir, 2).Create(ctx)
+    assert.NoError(t, err)
+
+    entries, err := os.ReadDir(dir)
+    assert.NoError(t, err)
+    var names []string
+    for _, entry := range entries {
+      names = append(names, entry.Name())
+    }
+    assert.ElementsMatch(t, []string{"db-20240103T000000.000.sqlite", backup.Name, "notes.txt"}, names)
+  })
+
+  t.Run("error", func(t *testing.T) {
+    var dir = t.TempDir()
+    var unexpected = errors.New("unexpected error")
+    r := mocks.NewBackupsRepository()
+    r.On(routine, mock.Anything, mock.AnythingOfType("string")).Return(unexpected)
+
+    backup, err := NewBackupsService(r, dir, 2).Create(ctx)
+    assert.ErrorIs(t, err, unexpected)
+    assert.Nil(t, backup)
+  })
+}
+
+func TestBackupsService_Get(t *testing.T) {
+  ctx := context.TODO()
+
+  t.Run("success", func(t *testing.T) {
+    var dir = t.TempDir()
+    for _, name := range []string{"db-20240102T000000.000.sqlite", "db-20240101T000000.000.sqlite", "db-20240103T000000.000.sqlite", "db.sqlite", "notes.txt"} {
+      assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("backup"), 0644))
+    }
+
+    backups, err := NewBackupsService(mocks.NewBackupsRepository(), dir, 2).Get(ctx)
+    assert.NoError(t, err)
+    if assert.Len(t, backups, 3) {
+      assert.Equal(t, "db-20240103T000000.000.sqlite", backups[0].Name)
+      assert.Equal(t, "db-20240102T000000.000.sqlite", backups[1].Name)
+      assert.Equal(t, "db-20240101T000000.000.sqlite", backups[2].Name)
+    }
+  })
+
+  t.Run("missing directory", func(t *testing.T) {
+    backups, err := NewBackupsService(mocks.NewBackupsRepository(), filepath.Join(t.TempDir(), "missing"), 2).Get(ctx)
+    assert.NoError(t, err)
+    assert.Empty(t, backups)
+  })
+}
@@ -6,6 +6,7 @@ import (
   "fontseca.dev/model"
   "fontseca.dev/problem"
   "fontseca.dev/repository"
+  "fontseca.dev/tracing"
   "fontseca.dev/transfer"
   "github.com/google/uuid"
   "log/slog"
@@ -21,10 +22,12 @@ type DraftsService interface {
   // are completely optional and can be added in an eventual revision.
   Draft(ctx context.Context, creation *transfer.ArticleCreation) (insertedUUID uuid.UUID, err error)
 
-  // Publish makes a draft publicly available.
+  // Publish makes a draft publicly available, after it adds the tags
+  // with the IDs in tags to it. Either the tags are added and the draft
+  // is published, or neither happens.
   //
   // Invoking Publish on an already published article has no effect.
-  Publish(ctx context.Context, draftUUID string) error
+  Publish(ctx context.Context, draftUUID string, tags ...string) error
 
   // Get retrieves all the ongoing articles drafts.
   //
@@ -34,6 +37,9 @@ type DraftsService interface {
   // (if more than one) in filter.Search.
   Get(ctx context.Context, filter *transfer.ArticleFilter) (drafts []*transfer.Article, err error)
 
+  // Count counts the drafts that match filter, regardless of its pagination.
+  Count(ctx context.Context, filter *transfer.ArticleFilter) (total int, err error)
+
   // GetByLink retrieves a draft by its shareable link.
   GetByLink(ctx context.Context, link string) (article *model.Article, err error)
 
@@ -56,26 +62,39 @@ type DraftsService interface {
   // eventually expire after a certain amount of time.
   Share(ctx context.Context, draftUUID string) (link string, err error)
 
-  // Discard completely drops an article draft.
+  // Discard moves an article draft to the trash.
   Discard(ctx context.Context, draftUUID string) error
 
   // Revise adds a correction or inclusion to an article draft in order
   // to correct or improve it.
   Revise(ctx context.Context, draftUUID string, revision *transfer.ArticleRevision) error
+
+  // Measure approximates the words of a title and a content as Revise
+  // counts them, and the minutes it would take to read them.
+  Measure(title, content string) (words, readTime int)
 }
 
 type draftsService struct {
-  r repository.ArchiveRepository
+  r            repository.ArchiveRepository
+  unit         repository.UnitOfWork
+  events       Publisher
+  readingSpeed int // words read per minute
 }
 
-func NewDraftsService(r repository.ArchiveRepository) DraftsService {
-  return &draftsService{r}
+// NewDraftsService returns a DraftsService that estimates reading times
+// at readingSpeed words per minute and publishes a draft within unit,
+// along with the event of its publication.
+func NewDraftsService(r repository.ArchiveRepository, unit repository.UnitOfWork, events Publisher, readingSpeed int) DraftsService {
+  return &draftsService{r, unit, events, readingSpeed}
 }
 
 func (s *draftsService) Draft(ctx context.Context, creation *transfer.ArticleCreation) (insertedUUID uuid.UUID, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
   if nil == creation {
     err = errors.New("nil value for parameter: creation")
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return uuid.Nil, err
   }
 
@@ -102,7 +121,7 @@ func (s *draftsService) Draft(ctx context.Context, creation *transfer.ArticleCre
   }
 
   reader := strings.NewReader(builder.String())
-  creation.ReadTime = computePostReadingTimeInMinutes(reader)
+  creation.ReadTime = computePostReadingTimeInMinutes(reader, s.readingSpeed)
 
   id, err := s.r.Draft(ctx, creation)
   if err != nil {
@@ -112,23 +131,54 @@ func (s *draftsService) Draft(ctx context.Context, creation *transfer.ArticleCre
   return uuid.Parse(id)
 }
 
-func (s *draftsService) Publish(ctx context.Context, draftUUID string) error {
+func (s *draftsService) Publish(ctx context.Context, draftUUID string, tags ...string) error {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
   if err := validateUUID(&draftUUID); nil != err {
     return err
   }
 
-  return s.r.Publish(ctx, draftUUID)
+  return within(ctx, s.unit, func(ctx context.Context) error {
+    for _, tag := range tags {
+      if err := s.r.AddTag(ctx, draftUUID, tag, true); nil != err {
+        return err
+      }
+    }
+
+    if err := s.r.Publish(ctx, draftUUID); nil != err {
+      return err
+    }
+
+    return publish(ctx, s.events, ArticlePublished, "article_uuid", draftUUID)
+  })
 }
 
 func (s *draftsService) Get(ctx context.Context, filter *transfer.ArticleFilter) (drafts []*transfer.Article, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
   return s.r.Get(ctx, filter, false, true)
 }
 
+func (s *draftsService) Count(ctx context.Context, filter *transfer.ArticleFilter) (total int, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
+  return s.r.Count(ctx, filter, false, true)
+}
+
 func (s *draftsService) GetByLink(ctx context.Context, link string) (article *model.Article, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
   return s.r.GetByLink(ctx, link)
 }
 
 func (s *draftsService) GetByID(ctx context.Context, draftUUID string) (draft *model.Article, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
   if err = validateUUID(&draftUUID); nil != err {
     return nil, err
   }
@@ -137,22 +187,35 @@ func (s *draftsService) GetByID(ctx context.Context, draftUUID string) (draft *m
 }
 
 func (s *draftsService) AddTag(ctx context.Context, draftUUID, tagID string) error {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
   if err := validateUUID(&draftUUID); nil != err {
     return err
   }
 
-  return s.r.AddTag(ctx, draftUUID, tagID, true)
+  return within(ctx, s.unit, func(ctx context.Context) error {
+    return s.r.AddTag(ctx, draftUUID, tagID, true)
+  })
 }
 
 func (s *draftsService) RemoveTag(ctx context.Context, draftUUID, tagID string) error {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
   if err := validateUUID(&draftUUID); nil != err {
     return err
   }
 
-  return s.r.RemoveTag(ctx, draftUUID, tagID, true)
+  return within(ctx, s.unit, func(ctx context.Context) error {
+    return s.r.RemoveTag(ctx, draftUUID, tagID, true)
+  })
 }
 
 func (s *draftsService) Share(ctx context.Context, draftUUID string) (link string, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
   if err = validateUUID(&draftUUID); nil != err {
     return "about:blank", err
   }
@@ -167,6 +230,9 @@ func (s *draftsService) Share(ctx context.Context, draftUUID string) (link strin
 }
 
 func (s *draftsService) Discard(ctx context.Context, draftUUID string) error {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
   if err := validateUUID(&draftUUID); nil != err {
     return err
   }
@@ -175,9 +241,12 @@ func (s *draftsService) Discard(ctx context.Context, draftUUID string) error {
 }
 
 func (s *draftsService) Revise(ctx context.Context, draftUUID string, revision *transfer.ArticleRevision) error {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
   if nil == revision {
     err := errors.New("nil value for parameter: revision")
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return err
   }
 
@@ -199,25 +268,25 @@ func (s *draftsService) Revise(ctx context.Context, draftUUID string, revision *
     return problem.NewValidation([3]string{"content", "max", "3145728"})
   }
 
-  if "" != revision.Title || "" != revision.Content {
-    builder := strings.Builder{}
+  if "" != revision.Title {
+    revision.Slug = generateSlug(revision.Title)
+  }
 
-    if "" != revision.Title {
-      builder.WriteString(revision.Title)
-      revision.Slug = generateSlug(revision.Title)
-    }
+  if "" != revision.Title || "" != revision.Content {
+    r := strings.NewReader(readableText(revision.Title, revision.Content))
+    revision.ReadTime = computePostReadingTimeInMinutes(r, s.readingSpeed)
+  }
 
-    if "" != revision.Content {
-      if 0 < builder.Len() {
-        builder.WriteRune('\n')
-      }
+  return s.r.Revise(ctx, draftUUID, revision)
+}
 
-      builder.WriteString(revision.Content)
-    }
+func (s *draftsService) Measure(title, content string) (words, readTime int) {
+  var text = readableText(strings.TrimSpace(title), strings.TrimSpace(content))
 
-    r := strings.NewReader(builder.String())
-    revision.ReadTime = computePostReadingTimeInMinutes(r)
+  words, err := approximatePostWordsCount(strings.NewReader(text))
+  if nil != err {
+    return 0, 0
   }
 
-  return s.r.Revise(ctx, draftUUID, revision)
+  return words, computePostReadingTimeInMinutes(strings.NewReader(text), s.readingSpeed)
 }
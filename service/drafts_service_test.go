@@ -5,6 +5,7 @@ import (
   "errors"
   "fontseca.dev/mocks"
   "fontseca.dev/model"
+  "fontseca.dev/problem"
   "fontseca.dev/transfer"
   "github.com/google/uuid"
   "github.com/stretchr/testify/assert"
@@ -33,7 +34,7 @@ func TestDraftsService_Draft(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.On(routine, ctx, creation).Return(id.String(), nil)
 
-    insertedID, err := NewDraftsService(r).Draft(ctx, dirty)
+    insertedID, err := NewDraftsService(r, nil, nil, readingSpeed).Draft(ctx, dirty)
 
     assert.NoError(t, err)
     assert.Equal(t, id, insertedID)
@@ -43,7 +44,7 @@ func TestDraftsService_Draft(t *testing.T) {
     unexpected := errors.New("unexpected error")
     r := mocks.NewArchiveRepository()
     r.On(routine, ctx, mock.Anything).Return("", unexpected)
-    s := NewDraftsService(r)
+    s := NewDraftsService(r, nil, nil, readingSpeed)
 
     insertedID, err := s.Draft(ctx, creation)
 
@@ -62,7 +63,18 @@ func TestDraftsService_Publish(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.On(routine, ctx, id).Return(nil)
 
-    assert.NoError(t, NewDraftsService(r).Publish(ctx, id))
+    assert.NoError(t, NewDraftsService(r, nil, nil, readingSpeed).Publish(ctx, id))
+  })
+
+  t.Run("publishes the event", func(t *testing.T) {
+    r := mocks.NewArchiveRepository()
+    r.On(routine, ctx, id).Return(nil)
+
+    p := mocks.NewPublisher()
+    p.On("Publish", ctx, ArticlePublished, map[string]string{"article_uuid": id}).Return(nil)
+
+    assert.NoError(t, NewDraftsService(r, nil, p, readingSpeed).Publish(ctx, id))
+    p.AssertExpectations(t)
   })
 
   t.Run("gets a repository failure", func(t *testing.T) {
@@ -71,7 +83,51 @@ func TestDraftsService_Publish(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.On(routine, mock.Anything, mock.Anything).Return(unexpected)
 
-    assert.ErrorIs(t, NewDraftsService(r).Publish(ctx, id), unexpected)
+    p := mocks.NewPublisher()
+
+    assert.ErrorIs(t, NewDraftsService(r, nil, p, readingSpeed).Publish(ctx, id), unexpected)
+    p.AssertNotCalled(t, "Publish")
+  })
+
+  t.Run("gets a publishing failure within the unit", func(t *testing.T) {
+    unexpected := errors.New("unexpected error")
+
+    r := mocks.NewArchiveRepository()
+    r.On(routine, ctx, id).Return(nil)
+
+    p := mocks.NewPublisher()
+    p.On("Publish", ctx, ArticlePublished, mock.Anything).Return(unexpected)
+
+    u := mocks.NewUnitOfWork()
+    u.On("Do", ctx).Return(nil)
+
+    assert.ErrorIs(t, NewDraftsService(r, u, p, readingSpeed).Publish(ctx, id), unexpected)
+    u.AssertExpectations(t)
+  })
+
+  t.Run("adds the tags within the unit", func(t *testing.T) {
+    r := mocks.NewArchiveRepository()
+    r.On("AddTag", ctx, id, "go", []bool{true}).Return(nil)
+    r.On("AddTag", ctx, id, "sql", []bool{true}).Return(nil)
+    r.On(routine, ctx, id).Return(nil)
+
+    u := mocks.NewUnitOfWork()
+    u.On("Do", ctx).Return(nil).Once()
+
+    assert.NoError(t, NewDraftsService(r, u, nil, readingSpeed).Publish(ctx, id, "go", "sql"))
+    r.AssertExpectations(t)
+    u.AssertExpectations(t)
+  })
+
+  t.Run("does not publish when a tag cannot be added", func(t *testing.T) {
+    r := mocks.NewArchiveRepository()
+    r.On("AddTag", ctx, id, "missing", []bool{true}).Return(problem.NewNotFound("missing", "tag"))
+
+    u := mocks.NewUnitOfWork()
+    u.On("Do", ctx).Return(nil)
+
+    assert.Error(t, NewDraftsService(r, u, nil, readingSpeed).Publish(ctx, id, "missing"))
+    r.AssertNotCalled(t, routine, mock.Anything, mock.Anything)
   })
 
   t.Run("wrong uuid", func(t *testing.T) {
@@ -80,7 +136,7 @@ func TestDraftsService_Publish(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.AssertNotCalled(t, routine)
 
-    assert.Error(t, NewDraftsService(r).Publish(ctx, id))
+    assert.Error(t, NewDraftsService(r, nil, nil, readingSpeed).Publish(ctx, id))
   })
 }
 
@@ -96,7 +152,7 @@ func TestDraftsService_Get(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.On(routine, ctx, filter, false, true).Return(expectedDrafts, nil)
 
-    drafts, err := NewDraftsService(r).Get(ctx, filter)
+    drafts, err := NewDraftsService(r, nil, nil, readingSpeed).Get(ctx, filter)
 
     assert.Equal(t, expectedDrafts, drafts)
     assert.NoError(t, err)
@@ -108,7 +164,34 @@ func TestDraftsService_Get(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.On(routine, ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil, unexpected)
 
-    _, err := NewDraftsService(r).Get(ctx, filter)
+    _, err := NewDraftsService(r, nil, nil, readingSpeed).Get(ctx, filter)
+    assert.ErrorIs(t, err, unexpected)
+  })
+}
+
+func TestDraftsService_Count(t *testing.T) {
+  const routine = "Count"
+
+  ctx := context.TODO()
+  filter := &transfer.ArticleFilter{}
+
+  t.Run("success", func(t *testing.T) {
+    r := mocks.NewArchiveRepository()
+    r.On(routine, ctx, filter, false, true).Return(3, nil)
+
+    total, err := NewDraftsService(r, nil, nil, readingSpeed).Count(ctx, filter)
+
+    assert.Equal(t, 3, total)
+    assert.NoError(t, err)
+  })
+
+  t.Run("gets a repository failure", func(t *testing.T) {
+    unexpected := errors.New("unexpected error")
+
+    r := mocks.NewArchiveRepository()
+    r.On(routine, ctx, mock.Anything, mock.Anything, mock.Anything).Return(0, unexpected)
+
+    _, err := NewDraftsService(r, nil, nil, readingSpeed).Count(ctx, filter)
     assert.ErrorIs(t, err, unexpected)
   })
 }
@@ -125,7 +208,7 @@ func TestDraftsService_GetByID(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.On(routine, ctx, id, true).Return(expectedDraft, nil)
 
-    draft, err := NewDraftsService(r).GetByID(ctx, id)
+    draft, err := NewDraftsService(r, nil, nil, readingSpeed).GetByID(ctx, id)
 
     assert.Equal(t, expectedDraft, draft)
     assert.NoError(t, err)
@@ -137,7 +220,7 @@ func TestDraftsService_GetByID(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.On(routine, mock.Anything, mock.Anything, mock.Anything).Return(nil, unexpected)
 
-    draft, err := NewDraftsService(r).GetByID(ctx, id)
+    draft, err := NewDraftsService(r, nil, nil, readingSpeed).GetByID(ctx, id)
 
     assert.Nil(t, draft)
     assert.ErrorIs(t, err, unexpected)
@@ -149,7 +232,7 @@ func TestDraftsService_GetByID(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.AssertNotCalled(t, routine)
 
-    _, err := NewDraftsService(r).GetByID(ctx, id)
+    _, err := NewDraftsService(r, nil, nil, readingSpeed).GetByID(ctx, id)
 
     assert.Error(t, err)
   })
@@ -166,7 +249,7 @@ func TestDraftsService_AddTag(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.On(routine, ctx, draftUUID, tagID, []bool{true}).Return(nil)
 
-    assert.NoError(t, NewDraftsService(r).AddTag(ctx, draftUUID, tagID))
+    assert.NoError(t, NewDraftsService(r, nil, nil, readingSpeed).AddTag(ctx, draftUUID, tagID))
   })
 
   t.Run("wrong draft uuid", func(t *testing.T) {
@@ -175,7 +258,7 @@ func TestDraftsService_AddTag(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.AssertNotCalled(t, routine)
 
-    assert.Error(t, NewDraftsService(r).AddTag(ctx, draftUUID, tagID))
+    assert.Error(t, NewDraftsService(r, nil, nil, readingSpeed).AddTag(ctx, draftUUID, tagID))
   })
 
   draftUUID = uuid.NewString()
@@ -186,7 +269,7 @@ func TestDraftsService_AddTag(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.On(routine, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(unexpected)
 
-    err := NewDraftsService(r).AddTag(ctx, draftUUID, uuid.NewString())
+    err := NewDraftsService(r, nil, nil, readingSpeed).AddTag(ctx, draftUUID, uuid.NewString())
 
     assert.ErrorIs(t, err, unexpected)
   })
@@ -203,7 +286,7 @@ func TestDraftsService_RemoveTag(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.On(routine, ctx, draftUUID, tagID, []bool{true}).Return(nil)
 
-    assert.NoError(t, NewDraftsService(r).RemoveTag(ctx, draftUUID, tagID))
+    assert.NoError(t, NewDraftsService(r, nil, nil, readingSpeed).RemoveTag(ctx, draftUUID, tagID))
   })
 
   t.Run("wrong draft uuid", func(t *testing.T) {
@@ -212,7 +295,7 @@ func TestDraftsService_RemoveTag(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.AssertNotCalled(t, routine)
 
-    assert.Error(t, NewDraftsService(r).RemoveTag(ctx, draftUUID, tagID))
+    assert.Error(t, NewDraftsService(r, nil, nil, readingSpeed).RemoveTag(ctx, draftUUID, tagID))
   })
 
   draftUUID = uuid.NewString()
@@ -223,7 +306,7 @@ func TestDraftsService_RemoveTag(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.On(routine, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(unexpected)
 
-    err := NewDraftsService(r).RemoveTag(ctx, draftUUID, uuid.NewString())
+    err := NewDraftsService(r, nil, nil, readingSpeed).RemoveTag(ctx, draftUUID, uuid.NewString())
 
     assert.ErrorIs(t, err, unexpected)
   })
@@ -241,7 +324,7 @@ func TestDraftsService_Share(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.On(routine, ctx, draftUUID).Return(expectedLink, nil)
 
-    link, err := NewDraftsService(r).Share(ctx, draftUUID)
+    link, err := NewDraftsService(r, nil, nil, readingSpeed).Share(ctx, draftUUID)
 
     assert.Equal(t, expectedLink, link)
     assert.NoError(t, err)
@@ -253,7 +336,7 @@ func TestDraftsService_Share(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.AssertNotCalled(t, routine)
 
-    link, err := NewDraftsService(r).Share(ctx, draftUUID)
+    link, err := NewDraftsService(r, nil, nil, readingSpeed).Share(ctx, draftUUID)
 
     assert.Error(t, err)
     assert.Equal(t, "about:blank", link)
@@ -265,7 +348,7 @@ func TestDraftsService_Share(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.On(routine, mock.Anything, mock.Anything, mock.Anything).Return("", unexpected)
 
-    link, err := NewDraftsService(r).Share(ctx, uuid.NewString())
+    link, err := NewDraftsService(r, nil, nil, readingSpeed).Share(ctx, uuid.NewString())
 
     assert.Equal(t, "about:blank", link)
     assert.ErrorIs(t, err, unexpected)
@@ -282,7 +365,7 @@ func TestDraftsService_Discard(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.On(routine, ctx, id).Return(nil)
 
-    assert.NoError(t, NewDraftsService(r).Discard(ctx, id))
+    assert.NoError(t, NewDraftsService(r, nil, nil, readingSpeed).Discard(ctx, id))
   })
 
   t.Run("gets a repository failure", func(t *testing.T) {
@@ -291,7 +374,7 @@ func TestDraftsService_Discard(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.On(routine, mock.Anything, mock.Anything).Return(unexpected)
 
-    assert.ErrorIs(t, NewDraftsService(r).Discard(ctx, id), unexpected)
+    assert.ErrorIs(t, NewDraftsService(r, nil, nil, readingSpeed).Discard(ctx, id), unexpected)
   })
 
   t.Run("wrong uuid", func(t *testing.T) {
@@ -300,7 +383,7 @@ func TestDraftsService_Discard(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.AssertNotCalled(t, routine)
 
-    assert.Error(t, NewDraftsService(r).Discard(ctx, id))
+    assert.Error(t, NewDraftsService(r, nil, nil, readingSpeed).Discard(ctx, id))
   })
 }
 
@@ -325,7 +408,7 @@ func TestDraftsService_Revise(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.On(routine, ctx, draftUUID, revision).Return(nil)
 
-    assert.NoError(t, NewDraftsService(r).Revise(ctx, draftUUID, dirty))
+    assert.NoError(t, NewDraftsService(r, nil, nil, readingSpeed).Revise(ctx, draftUUID, dirty))
   })
 
   t.Run("success: changing title", func(t *testing.T) {
@@ -342,7 +425,7 @@ func TestDraftsService_Revise(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.On(routine, mock.Anything, mock.Anything, revision).Return(nil)
 
-    assert.NoError(t, NewDraftsService(r).Revise(ctx, draftUUID, dirty))
+    assert.NoError(t, NewDraftsService(r, nil, nil, readingSpeed).Revise(ctx, draftUUID, dirty))
   })
 
   t.Run("success: changing content", func(t *testing.T) {
@@ -358,19 +441,19 @@ func TestDraftsService_Revise(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.On(routine, mock.Anything, mock.Anything, revision).Return(nil)
 
-    assert.NoError(t, NewDraftsService(r).Revise(ctx, draftUUID, dirty))
+    assert.NoError(t, NewDraftsService(r, nil, nil, readingSpeed).Revise(ctx, draftUUID, dirty))
   })
 
   t.Run("nil parameter: revision", func(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.AssertNotCalled(t, routine)
-    assert.ErrorContains(t, NewDraftsService(r).Revise(ctx, draftUUID, nil), "nil value")
+    assert.ErrorContains(t, NewDraftsService(r, nil, nil, readingSpeed).Revise(ctx, draftUUID, nil), "nil value")
   })
 
   t.Run("wrong uuid: draftUUID", func(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.AssertNotCalled(t, routine)
-    assert.Error(t, NewDraftsService(r).Revise(ctx, "x", &transfer.ArticleRevision{}))
+    assert.Error(t, NewDraftsService(r, nil, nil, readingSpeed).Revise(ctx, "x", &transfer.ArticleRevision{}))
   })
 
   t.Run("gets a repository failure", func(t *testing.T) {
@@ -379,6 +462,20 @@ func TestDraftsService_Revise(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.On(routine, mock.Anything, mock.Anything, mock.Anything).Return(unexpected)
 
-    assert.ErrorIs(t, NewDraftsService(r).Revise(ctx, draftUUID, &transfer.ArticleRevision{}), unexpected)
+    assert.ErrorIs(t, NewDraftsService(r, nil, nil, readingSpeed).Revise(ctx, draftUUID, &transfer.ArticleRevision{}), unexpected)
+  })
+}
+
+func TestDraftsService_Measure(t *testing.T) {
+  t.Run("success", func(t *testing.T) {
+    words, readTime := NewDraftsService(nil, nil, nil, readingSpeed).Measure(" Title ", strings.Repeat("word ", 298)+"word")
+    assert.Equal(t, 300, words)
+    assert.Equal(t, 2, readTime)
+  })
+
+  t.Run("empty", func(t *testing.T) {
+    words, readTime := NewDraftsService(nil, nil, nil, readingSpeed).Measure("", " \t\n ")
+    assert.Zero(t, words)
+    assert.Zero(t, readTime)
   })
 }
@@ -6,6 +6,7 @@ import (
   "fontseca.dev/model"
   "fontseca.dev/problem"
   "fontseca.dev/repository"
+  "fontseca.dev/tracing"
   "fontseca.dev/transfer"
   "log/slog"
   "strconv"
@@ -21,6 +22,10 @@ type ExperienceService interface {
   // hidden experience records.
   Get(ctx context.Context, hidden ...bool) (experience []*model.Experience, err error)
 
+  // List retrieves the page of experience records that follows the
+  // cursor of page. If hidden is true it only retrieves the hidden ones.
+  List(ctx context.Context, hidden bool, page *transfer.PageRequest) (experience *transfer.Page[*model.Experience], err error)
+
   // GetByID retrieves a single experience record by its UUID.
   GetByID(ctx context.Context, id string) (experience *model.Experience, err error)
 
@@ -34,28 +39,45 @@ type ExperienceService interface {
   // and an error if something went wrong.
   Update(ctx context.Context, id string, update *transfer.ExperienceUpdate) (updated bool, err error)
 
-  // Remove deletes an experience record by its UUID.
+  // Remove moves an experience record to the trash by its UUID.
   // It returns an error if the operation fails; for example,
   // if the record does not exist.
   Remove(ctx context.Context, id string) error
 }
 
 type experienceService struct {
-  r repository.ExperienceRepository
+  r      repository.ExperienceRepository
+  unit   repository.UnitOfWork
+  events Publisher
 }
 
-func NewExperienceService(r repository.ExperienceRepository) ExperienceService {
-  return &experienceService{r}
+// NewExperienceService returns an ExperienceService that publishes the
+// events of the changes to the experience within unit, along with them.
+func NewExperienceService(r repository.ExperienceRepository, unit repository.UnitOfWork, events Publisher) ExperienceService {
+  return &experienceService{r, unit, events}
 }
 
 func (s *experienceService) Get(ctx context.Context, hidden ...bool) (experience []*model.Experience, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
   if 0 != len(hidden) && hidden[0] {
     return s.r.Get(ctx, true)
   }
   return s.r.Get(ctx, false)
 }
 
+func (s *experienceService) List(ctx context.Context, hidden bool, page *transfer.PageRequest) (experience *transfer.Page[*model.Experience], err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
+  return s.r.List(ctx, hidden, page)
+}
+
 func (s *experienceService) GetByID(ctx context.Context, id string) (experience *model.Experience, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
   if err = validateUUID(&id); nil != err {
     return nil, err
   }
@@ -63,9 +85,12 @@ func (s *experienceService) GetByID(ctx context.Context, id string) (experience
 }
 
 func (s *experienceService) Save(ctx context.Context, creation *transfer.ExperienceCreation) (saved bool, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
   if nil == creation {
     err = errors.New("nil value for parameter: creation")
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return false, err
   }
   creation.JobTitle = strings.TrimSpace(creation.JobTitle)
@@ -85,13 +110,24 @@ func (s *experienceService) Save(ctx context.Context, creation *transfer.Experie
     return false, problem.NewValidation([3]string{"ends", "lte", strconv.Itoa(year)})
   }
 
-  return s.r.Save(ctx, creation)
+  err = within(ctx, s.unit, func(ctx context.Context) (err error) {
+    if saved, err = s.r.Save(ctx, creation); !saved {
+      return err
+    }
+
+    return publish(ctx, s.events, ExperienceAdded, "job_title", creation.JobTitle)
+  })
+
+  return saved && nil == err, err
 }
 
 func (s *experienceService) Update(ctx context.Context, id string, update *transfer.ExperienceUpdate) (updated bool, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
   if nil == update {
     err = errors.New("nil value for parameter: update")
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return false, err
   }
   if err = validateUUID(&id); nil != err {
@@ -120,12 +156,29 @@ func (s *experienceService) Update(ctx context.Context, id string, update *trans
     }
   }
 
-  return s.r.Update(ctx, id, update)
+  err = within(ctx, s.unit, func(ctx context.Context) (err error) {
+    if updated, err = s.r.Update(ctx, id, update); !updated {
+      return err
+    }
+
+    return publish(ctx, s.events, ExperienceUpdated, "experience_uuid", id)
+  })
+
+  return updated && nil == err, err
 }
 
 func (s *experienceService) Remove(ctx context.Context, id string) error {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
   if err := validateUUID(&id); err != nil {
     return err
   }
-  return s.r.Remove(ctx, id)
+  return within(ctx, s.unit, func(ctx context.Context) error {
+    if err := s.r.Remove(ctx, id); nil != err {
+      return err
+    }
+
+    return publish(ctx, s.events, ExperienceRemoved, "experience_uuid", id)
+  })
 }
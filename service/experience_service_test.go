@@ -22,19 +22,19 @@ func TestExperienceService_Get(t *testing.T) {
 
     var r = mocks.NewExperienceRepository()
     r.On(routine, ctx, true).Return(exp, nil)
-    res, err := NewExperienceService(r).Get(ctx, true)
+    res, err := NewExperienceService(r, nil, nil).Get(ctx, true)
     assert.NotNil(t, res)
     assert.NoError(t, err)
 
     r = mocks.NewExperienceRepository()
     r.On(routine, ctx, false).Return(exp, nil)
-    res, err = NewExperienceService(r).Get(ctx, false)
+    res, err = NewExperienceService(r, nil, nil).Get(ctx, false)
     assert.NotNil(t, res)
     assert.NoError(t, err)
 
     r = mocks.NewExperienceRepository()
     r.On(routine, ctx, false).Return(exp, nil)
-    res, err = NewExperienceService(r).Get(ctx)
+    res, err = NewExperienceService(r, nil, nil).Get(ctx)
     assert.NotNil(t, res)
     assert.NoError(t, err)
   })
@@ -45,7 +45,7 @@ func TestExperienceService_Get(t *testing.T) {
 
     var r = mocks.NewExperienceRepository()
     r.On(routine, ctx, false).Return(nil, unexpected)
-    res, err := NewExperienceService(r).Get(ctx)
+    res, err := NewExperienceService(r, nil, nil).Get(ctx)
     assert.Nil(t, res)
     assert.ErrorIs(t, err, unexpected)
   })
@@ -59,7 +59,7 @@ func TestExperienceService_GetByID(t *testing.T) {
     var r = mocks.NewExperienceRepository()
     var ctx = context.Background()
     r.On(routine, ctx, id).Return(new(model.Experience), nil)
-    res, err := NewExperienceService(r).GetByID(ctx, id)
+    res, err := NewExperienceService(r, nil, nil).GetByID(ctx, id)
     assert.NotNil(t, res)
     assert.NoError(t, err)
   })
@@ -69,7 +69,7 @@ func TestExperienceService_GetByID(t *testing.T) {
     var r = mocks.NewExperienceRepository()
     var ctx = context.Background()
     r.On(routine, ctx, id).Return(nil, unexpected)
-    res, err := NewExperienceService(r).GetByID(ctx, id)
+    res, err := NewExperienceService(r, nil, nil).GetByID(ctx, id)
     assert.Nil(t, res)
     assert.ErrorIs(t, err, unexpected)
   })
@@ -98,7 +98,7 @@ func TestExperienceService_Save(t *testing.T) {
     var ctx = context.Background()
     var r = mocks.NewExperienceRepository()
     r.On(routine, ctx, &expected).Return(true, nil)
-    res, err := NewExperienceService(r).Save(ctx, &dirty)
+    res, err := NewExperienceService(r, nil, nil).Save(ctx, &dirty)
     assert.NoError(t, err)
     assert.True(t, res)
   })
@@ -107,7 +107,7 @@ func TestExperienceService_Save(t *testing.T) {
     var r = mocks.NewExperienceRepository()
     var ctx = context.Background()
     r.AssertNotCalled(t, routine)
-    res, err := NewExperienceService(r).Save(ctx, nil)
+    res, err := NewExperienceService(r, nil, nil).Save(ctx, nil)
     assert.ErrorContains(t, err, "nil value for parameter: creation")
     assert.False(t, res)
   })
@@ -121,7 +121,7 @@ func TestExperienceService_Save(t *testing.T) {
         var ctx = context.Background()
         var r = mocks.NewExperienceRepository()
         r.On(routine, ctx, mock.AnythingOfType("*transfer.ExperienceCreation")).Return(true, nil)
-        res, err := NewExperienceService(r).Save(ctx, &creation)
+        res, err := NewExperienceService(r, nil, nil).Save(ctx, &creation)
         assert.ErrorContains(t, err, "The provided data does not meet the required validation criteria")
         assert.False(t, res)
       })
@@ -131,7 +131,7 @@ func TestExperienceService_Save(t *testing.T) {
         var ctx = context.Background()
         var r = mocks.NewExperienceRepository()
         r.On(routine, ctx, mock.AnythingOfType("*transfer.ExperienceCreation")).Return(true, nil)
-        res, err := NewExperienceService(r).Save(ctx, &creation)
+        res, err := NewExperienceService(r, nil, nil).Save(ctx, &creation)
         assert.NoError(t, err)
         assert.True(t, res)
       })
@@ -142,7 +142,7 @@ func TestExperienceService_Save(t *testing.T) {
         var ctx = context.Background()
         var r = mocks.NewExperienceRepository()
         r.On(routine, ctx, mock.AnythingOfType("*transfer.ExperienceCreation")).Return(true, nil)
-        res, err := NewExperienceService(r).Save(ctx, &creation)
+        res, err := NewExperienceService(r, nil, nil).Save(ctx, &creation)
         assert.NoError(t, err)
         assert.True(t, res)
       })
@@ -152,7 +152,7 @@ func TestExperienceService_Save(t *testing.T) {
         var ctx = context.Background()
         var r = mocks.NewExperienceRepository()
         r.On(routine, ctx, mock.AnythingOfType("*transfer.ExperienceCreation")).Return(true, nil)
-        res, err := NewExperienceService(r).Save(ctx, &creation)
+        res, err := NewExperienceService(r, nil, nil).Save(ctx, &creation)
         assert.ErrorContains(t, err, "The provided data does not meet the required validation criteria")
         assert.False(t, res)
       })
@@ -168,7 +168,7 @@ func TestExperienceService_Save(t *testing.T) {
         var ctx = context.Background()
         var r = mocks.NewExperienceRepository()
         r.On(routine, ctx, mock.AnythingOfType("*transfer.ExperienceCreation")).Return(true, nil)
-        res, err := NewExperienceService(r).Save(ctx, &creation)
+        res, err := NewExperienceService(r, nil, nil).Save(ctx, &creation)
         assert.ErrorContains(t, err, "The provided data does not meet the required validation criteria")
         assert.False(t, res)
       })
@@ -178,7 +178,7 @@ func TestExperienceService_Save(t *testing.T) {
         var ctx = context.Background()
         var r = mocks.NewExperienceRepository()
         r.On(routine, ctx, mock.AnythingOfType("*transfer.ExperienceCreation")).Return(true, nil)
-        res, err := NewExperienceService(r).Save(ctx, &creation)
+        res, err := NewExperienceService(r, nil, nil).Save(ctx, &creation)
         assert.NoError(t, err)
         assert.True(t, res)
       })
@@ -188,7 +188,7 @@ func TestExperienceService_Save(t *testing.T) {
         var ctx = context.Background()
         var r = mocks.NewExperienceRepository()
         r.On(routine, ctx, mock.AnythingOfType("*transfer.ExperienceCreation")).Return(true, nil)
-        res, err := NewExperienceService(r).Save(ctx, &creation)
+        res, err := NewExperienceService(r, nil, nil).Save(ctx, &creation)
         assert.NoError(t, err)
         assert.True(t, res)
       })
@@ -198,7 +198,7 @@ func TestExperienceService_Save(t *testing.T) {
         var ctx = context.Background()
         var r = mocks.NewExperienceRepository()
         r.On(routine, ctx, mock.AnythingOfType("*transfer.ExperienceCreation")).Return(true, nil)
-        res, err := NewExperienceService(r).Save(ctx, &creation)
+        res, err := NewExperienceService(r, nil, nil).Save(ctx, &creation)
         assert.NoError(t, err)
         assert.True(t, res)
       })
@@ -208,7 +208,7 @@ func TestExperienceService_Save(t *testing.T) {
         var ctx = context.Background()
         var r = mocks.NewExperienceRepository()
         r.On(routine, ctx, mock.AnythingOfType("*transfer.ExperienceCreation")).Return(true, nil)
-        res, err := NewExperienceService(r).Save(ctx, &creation)
+        res, err := NewExperienceService(r, nil, nil).Save(ctx, &creation)
         assert.ErrorContains(t, err, "The provided data does not meet the required validation criteria")
         assert.False(t, res)
       })
@@ -220,7 +220,7 @@ func TestExperienceService_Save(t *testing.T) {
     var r = mocks.NewExperienceRepository()
     var ctx = context.Background()
     r.On(routine, ctx, mock.AnythingOfType("*transfer.ExperienceCreation")).Return(false, unexpected)
-    res, err := NewExperienceService(r).Save(ctx, new(transfer.ExperienceCreation))
+    res, err := NewExperienceService(r, nil, nil).Save(ctx, new(transfer.ExperienceCreation))
     assert.False(t, res)
     assert.ErrorIs(t, err, unexpected)
   })
@@ -251,7 +251,7 @@ func TestExperienceService_Update(t *testing.T) {
     }
     var r = mocks.NewExperienceRepository()
     r.On(routine, ctx, expectedID, &expected).Return(true, nil)
-    res, err := NewExperienceService(r).Update(ctx, id, &dirty)
+    res, err := NewExperienceService(r, nil, nil).Update(ctx, id, &dirty)
     assert.True(t, res)
     assert.NoError(t, err)
   })
@@ -264,7 +264,7 @@ func TestExperienceService_Update(t *testing.T) {
         update.Starts = 2016
         var r = mocks.NewExperienceRepository()
         r.On(routine, ctx, mock.Anything, mock.AnythingOfType("*transfer.ExperienceUpdate")).Return(true, nil)
-        res, err := NewExperienceService(r).Update(ctx, id, &update)
+        res, err := NewExperienceService(r, nil, nil).Update(ctx, id, &update)
         assert.ErrorContains(t, err, "The provided data does not meet the required validation criteria")
         assert.False(t, res)
       })
@@ -273,7 +273,7 @@ func TestExperienceService_Update(t *testing.T) {
         update.Starts = 2020
         var r = mocks.NewExperienceRepository()
         r.On(routine, ctx, mock.Anything, mock.AnythingOfType("*transfer.ExperienceUpdate")).Return(true, nil)
-        res, err := NewExperienceService(r).Update(ctx, id, &update)
+        res, err := NewExperienceService(r, nil, nil).Update(ctx, id, &update)
         assert.NoError(t, err)
         assert.True(t, res)
       })
@@ -283,7 +283,7 @@ func TestExperienceService_Update(t *testing.T) {
         update.Ends = update.Starts
         var r = mocks.NewExperienceRepository()
         r.On(routine, ctx, mock.Anything, mock.AnythingOfType("*transfer.ExperienceUpdate")).Return(true, nil)
-        res, err := NewExperienceService(r).Update(ctx, id, &update)
+        res, err := NewExperienceService(r, nil, nil).Update(ctx, id, &update)
         assert.NoError(t, err)
         assert.True(t, res)
       })
@@ -292,7 +292,7 @@ func TestExperienceService_Update(t *testing.T) {
         update.Starts = 1 + time.Now().Year()
         var r = mocks.NewExperienceRepository()
         r.On(routine, ctx, mock.Anything, mock.AnythingOfType("*transfer.ExperienceUpdate")).Return(true, nil)
-        res, err := NewExperienceService(r).Update(ctx, id, &update)
+        res, err := NewExperienceService(r, nil, nil).Update(ctx, id, &update)
         assert.ErrorContains(t, err, "The provided data does not meet the required validation criteria")
         assert.False(t, res)
       })
@@ -307,7 +307,7 @@ func TestExperienceService_Update(t *testing.T) {
         update.Ends = 2016
         var r = mocks.NewExperienceRepository()
         r.On(routine, ctx, mock.Anything, mock.AnythingOfType("*transfer.ExperienceUpdate")).Return(true, nil)
-        res, err := NewExperienceService(r).Update(ctx, id, &update)
+        res, err := NewExperienceService(r, nil, nil).Update(ctx, id, &update)
         assert.ErrorContains(t, err, "The provided data does not meet the required validation criteria")
         assert.False(t, res)
       })
@@ -317,7 +317,7 @@ func TestExperienceService_Update(t *testing.T) {
         update.Ends = update.Starts
         var r = mocks.NewExperienceRepository()
         r.On(routine, ctx, mock.Anything, mock.AnythingOfType("*transfer.ExperienceUpdate")).Return(true, nil)
-        res, err := NewExperienceService(r).Update(ctx, id, &update)
+        res, err := NewExperienceService(r, nil, nil).Update(ctx, id, &update)
         assert.NoError(t, err)
         assert.True(t, res)
       })
@@ -326,7 +326,7 @@ func TestExperienceService_Update(t *testing.T) {
         update.Ends = 1 + update.Starts
         var r = mocks.NewExperienceRepository()
         r.On(routine, ctx, mock.Anything, mock.AnythingOfType("*transfer.ExperienceUpdate")).Return(true, nil)
-        res, err := NewExperienceService(r).Update(ctx, id, &update)
+        res, err := NewExperienceService(r, nil, nil).Update(ctx, id, &update)
         assert.NoError(t, err)
         assert.True(t, res)
       })
@@ -335,7 +335,7 @@ func TestExperienceService_Update(t *testing.T) {
         update.Ends = time.Now().Year()
         var r = mocks.NewExperienceRepository()
         r.On(routine, ctx, mock.Anything, mock.AnythingOfType("*transfer.ExperienceUpdate")).Return(true, nil)
-        res, err := NewExperienceService(r).Update(ctx, id, &update)
+        res, err := NewExperienceService(r, nil, nil).Update(ctx, id, &update)
         assert.NoError(t, err)
         assert.True(t, res)
       })
@@ -345,7 +345,7 @@ func TestExperienceService_Update(t *testing.T) {
         update.Ends = 2019
         var r = mocks.NewExperienceRepository()
         r.On(routine, ctx, mock.Anything, mock.AnythingOfType("*transfer.ExperienceUpdate")).Return(true, nil)
-        res, err := NewExperienceService(r).Update(ctx, id, &update)
+        res, err := NewExperienceService(r, nil, nil).Update(ctx, id, &update)
         assert.ErrorContains(t, err, "The provided data does not meet the required validation criteria")
         assert.False(t, res)
       })
@@ -354,7 +354,7 @@ func TestExperienceService_Update(t *testing.T) {
         update.Ends = 1 + time.Now().Year()
         var r = mocks.NewExperienceRepository()
         r.On(routine, ctx, mock.Anything, mock.AnythingOfType("*transfer.ExperienceUpdate")).Return(true, nil)
-        res, err := NewExperienceService(r).Update(ctx, id, &update)
+        res, err := NewExperienceService(r, nil, nil).Update(ctx, id, &update)
         assert.ErrorContains(t, err, "The provided data does not meet the required validation criteria")
         assert.False(t, res)
       })
@@ -365,7 +365,7 @@ func TestExperienceService_Update(t *testing.T) {
     var unexpected = errors.New("unexpected error")
     var r = mocks.NewExperienceRepository()
     r.On(routine, ctx, expectedID, mock.AnythingOfType("*transfer.ExperienceUpdate")).Return(false, unexpected)
-    res, err := NewExperienceService(r).Update(ctx, id, new(transfer.ExperienceUpdate))
+    res, err := NewExperienceService(r, nil, nil).Update(ctx, id, new(transfer.ExperienceUpdate))
     assert.False(t, res)
     assert.ErrorIs(t, err, unexpected)
   })
@@ -380,7 +380,7 @@ func TestExperienceService_Remove(t *testing.T) {
   t.Run("success", func(t *testing.T) {
     var r = mocks.NewExperienceRepository()
     r.On(routine, ctx, expectedID).Return(nil)
-    err := NewExperienceService(r).Remove(ctx, id)
+    err := NewExperienceService(r, nil, nil).Remove(ctx, id)
     assert.NoError(t, err)
   })
 
@@ -388,7 +388,7 @@ func TestExperienceService_Remove(t *testing.T) {
     var unexpected = errors.New("unexpected error")
     var r = mocks.NewExperienceRepository()
     r.On(routine, ctx, expectedID).Return(unexpected)
-    err := NewExperienceService(r).Remove(ctx, id)
+    err := NewExperienceService(r, nil, nil).Remove(ctx, id)
     assert.ErrorIs(t, err, unexpected)
   })
 }
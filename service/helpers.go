@@ -210,8 +210,27 @@ func computePostReadingTime(r io.Reader, wordsPerMinute float64) (readingTime ti
   return readingTime, nil
 }
 
-func computePostReadingTimeInMinutes(r io.Reader) int {
-  duration, err := computePostReadingTime(r, 183.0)
+// readableText joins the title and the content of an article the way
+// its reading time is computed.
+func readableText(title, content string) string {
+  builder := strings.Builder{}
+  builder.WriteString(title)
+
+  if "" != content {
+    if 0 < builder.Len() {
+      builder.WriteRune('\n')
+    }
+
+    builder.WriteString(content)
+  }
+
+  return builder.String()
+}
+
+// computePostReadingTimeInMinutes rounds up the minutes it takes to
+// read the text of r at wordsPerMinute words per minute.
+func computePostReadingTimeInMinutes(r io.Reader, wordsPerMinute int) int {
+  duration, err := computePostReadingTime(r, float64(wordsPerMinute))
   if err != nil {
     return 0
   }
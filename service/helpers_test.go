@@ -10,6 +10,10 @@ import (
   "time"
 )
 
+// readingSpeed is the reading speed, in words per minute, given to the
+// services under test.
+const readingSpeed = 183
+
 func Test_generateSlug(t *testing.T) {
   sources := [][2]string{
     {"Quisque egestas cursus.", "quisque-egestas-cursus"},
@@ -0,0 +1,257 @@
+package service
+
+import (
+  "bytes"
+  "context"
+  "fmt"
+  "fontseca.dev/problem"
+  "fontseca.dev/repository"
+  "fontseca.dev/tracing"
+  "fontseca.dev/transfer"
+  "github.com/google/uuid"
+  "gopkg.in/yaml.v3"
+  "io"
+  "io/fs"
+  "net/http"
+  "path"
+  "strings"
+  "time"
+)
+
+// ImportService imports articles written as Markdown files with a
+// YAML front matter, such as:
+//
+//  ---
+//  title: On Writing Well
+//  slug: on-writing-well
+//  topic: Writing
+//  tags: [essays, craft]
+//  published_at: 2019-03-21
+//  hidden: false
+//  pinned: false
+//  ---
+//  The content of the article.
+//
+// Only the title is required. Articles without a publication date are
+// imported as drafts. Importing the same file twice is idempotent:
+// articles are matched by their slug.
+type ImportService interface {
+  // Import imports the article in the Markdown file read from r.
+  Import(ctx context.Context, name string, r io.Reader) (result *transfer.ArticleImportResult, err error)
+
+  // ImportAll imports every Markdown file in fsys, in lexical order.
+  // It stops at the first file that could not be imported and returns
+  // the results of the files imported until then.
+  ImportAll(ctx context.Context, fsys fs.FS) (results []*transfer.ArticleImportResult, err error)
+}
+
+type importService struct {
+  r            repository.ArchiveRepository
+  readingSpeed int // words read per minute
+}
+
+// NewImportService returns an ImportService that estimates reading
+// times at readingSpeed words per minute.
+func NewImportService(r repository.ArchiveRepository, readingSpeed int) ImportService {
+  return &importService{r, readingSpeed}
+}
+
+// frontMatter is the metadata at the beginning of an imported file.
+type frontMatter struct {
+  Title       string     `yaml:"title"`
+  Slug        string     `yaml:"slug"`
+  Topic       string     `yaml:"topic"`
+  Tags        []string   `yaml:"tags"`
+  PublishedAt *time.Time `yaml:"published_at"`
+  Date        *time.Time `yaml:"date"` // alias of published_at used by most static site generators
+  Hidden      bool       `yaml:"hidden"`
+  Pinned      bool       `yaml:"pinned"`
+}
+
+// newUnprocessableFile creates a problem for a file that could not be imported.
+func newUnprocessableFile(name, detail string) *problem.Problem {
+  p := problem.Problem{}
+  p.Status(http.StatusUnprocessableEntity)
+  p.Title("Unprocessable Markdown file.")
+  p.Detail(detail)
+  p.With("file", name)
+  return &p
+}
+
+// splitFrontMatter separates the YAML front matter of a Markdown file
+// from its content.
+func splitFrontMatter(data []byte) (matter, content []byte, ok bool) {
+  data = bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+
+  if !bytes.HasPrefix(data, []byte("---\n")) {
+    return nil, nil, false
+  }
+
+  data = data[len("---\n"):]
+
+  if bytes.HasPrefix(data, []byte("---\n")) {
+    return []byte{}, data[len("---\n"):], true
+  }
+
+  end := bytes.Index(data, []byte("\n---\n"))
+
+  switch {
+  case -1 != end:
+    return data[:end], data[end+len("\n---\n"):], true
+  case bytes.HasSuffix(data, []byte("\n---")):
+    return data[:len(data)-len("\n---")], []byte{}, true
+  default:
+    return nil, nil, false
+  }
+}
+
+func (s *importService) Import(ctx context.Context, name string, r io.Reader) (result *transfer.ArticleImportResult, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
+  data, err := io.ReadAll(r)
+  if nil != err {
+    return nil, err
+  }
+
+  rawMatter, content, ok := splitFrontMatter(data)
+  if !ok {
+    return nil, newUnprocessableFile(name, "The file does not start with a YAML front matter delimited by '---' lines.")
+  }
+
+  var matter frontMatter
+
+  if err = yaml.Unmarshal(rawMatter, &matter); nil != err {
+    return nil, newUnprocessableFile(name, fmt.Sprintf("The front matter of the file is not valid YAML: %v.", err))
+  }
+
+  article := &transfer.ArticleImport{
+    Title:       strings.TrimSpace(matter.Title),
+    Slug:        strings.TrimSpace(matter.Slug),
+    Content:     strings.TrimSpace(string(content)),
+    PublishedAt: matter.PublishedAt,
+    IsHidden:    matter.Hidden,
+    IsPinned:    matter.Pinned,
+    Tags:        make([]*transfer.TagCreation, 0, len(matter.Tags)),
+  }
+
+  if nil == article.PublishedAt {
+    article.PublishedAt = matter.Date
+  }
+
+  sanitizeTextWordIntersections(&article.Title)
+
+  if "" == article.Slug {
+    article.Slug = generateSlug(article.Title)
+  } else {
+    article.Slug = generateSlug(article.Slug)
+  }
+
+  if topic := strings.TrimSpace(matter.Topic); "" != topic {
+    sanitizeTextWordIntersections(&topic)
+    article.Topic = &transfer.TopicCreation{ID: toKebabCase(topic), Name: topic}
+  }
+
+  for _, tag := range matter.Tags {
+    tag = strings.TrimSpace(tag)
+
+    if "" == tag {
+      continue
+    }
+
+    sanitizeTextWordIntersections(&tag)
+    article.Tags = append(article.Tags, &transfer.TagCreation{ID: toKebabCase(tag), Name: tag})
+  }
+
+  failures := make([][3]string, 0)
+
+  switch {
+  case "" == article.Title:
+    failures = append(failures, [3]string{"title", "required", ""})
+  case 256 < len(article.Title):
+    failures = append(failures, [3]string{"title", "max", "256"})
+  }
+
+  if 3145728 < len(article.Content) {
+    failures = append(failures, [3]string{"content", "max", "3145728"})
+  }
+
+  if nil != article.Topic && 32 < len(article.Topic.ID) {
+    failures = append(failures, [3]string{"topic", "max", "32"})
+  }
+
+  if nil != article.PublishedAt && nil == article.Topic {
+    failures = append(failures, [3]string{"topic", "required_with", "published_at"})
+  }
+
+  for _, tag := range article.Tags {
+    if 32 < len(tag.ID) {
+      failures = append(failures, [3]string{"tags", "max", "32"})
+      break
+    }
+  }
+
+  if 0 < len(failures) {
+    p := problem.NewValidation(failures...)
+    p.With("file", name)
+    return nil, p
+  }
+
+  article.ReadTime = computePostReadingTimeInMinutes(strings.NewReader(article.Title + "\n" + article.Content), s.readingSpeed)
+
+  id, status, err := s.r.Import(ctx, article)
+  if nil != err {
+    return nil, err
+  }
+
+  result = &transfer.ArticleImportResult{
+    File:   name,
+    Slug:   article.Slug,
+    Status: status,
+  }
+
+  if result.UUID, err = uuid.Parse(id); nil != err {
+    return nil, err
+  }
+
+  return result, nil
+}
+
+func (s *importService) ImportAll(ctx context.Context, fsys fs.FS) (results []*transfer.ArticleImportResult, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
+  results = make([]*transfer.ArticleImportResult, 0)
+
+  err = fs.WalkDir(fsys, ".", func(name string, entry fs.DirEntry, err error) error {
+    if nil != err {
+      return err
+    }
+
+    if entry.IsDir() {
+      return nil
+    }
+
+    if ext := strings.ToLower(path.Ext(name)); ".md" != ext && ".markdown" != ext {
+      return nil
+    }
+
+    file, err := fsys.Open(name)
+    if nil != err {
+      return err
+    }
+
+    defer file.Close()
+
+    result, err := s.Import(ctx, name, file)
+    if nil != err {
+      return err
+    }
+
+    results = append(results, result)
+
+    return nil
+  })
+
+  return results, err
+}
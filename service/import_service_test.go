@@ -0,0 +1,161 @@
+package service
+
+import (
+  "context"
+  "errors"
+  "fontseca.dev/mocks"
+  "fontseca.dev/problem"
+  "fontseca.dev/transfer"
+  "github.com/google/uuid"
+  "github.com/stretchr/testify/assert"
+  "github.com/stretchr/testify/mock"
+  "github.com/stretchr/testify/require"
+  "strings"
+  "testing"
+  "testing/fstest"
+  "time"
+)
+
+func TestImportService_Import(t *testing.T) {
+  const routine = "Import"
+
+  ctx := context.TODO()
+  id := uuid.New()
+
+  t.Run("success", func(t *testing.T) {
+    file := "---\r\n" +
+      "title: \" On   Writing Well \"\r\n" +
+      "topic: Writing Craft\r\n" +
+      "tags: [Essays, \" Style Guides \"]\r\n" +
+      "published_at: 2019-03-21\r\n" +
+      "pinned: true\r\n" +
+      "---\r\n" +
+      "\r\nThe content.\r\n"
+
+    expected := &transfer.ArticleImport{
+      Title:       "On Writing Well",
+      Slug:        "on-writing-well",
+      Topic:       &transfer.TopicCreation{ID: "writing-craft", Name: "Writing Craft"},
+      Tags:        []*transfer.TagCreation{{ID: "essays", Name: "Essays"}, {ID: "style-guides", Name: "Style Guides"}},
+      ReadTime:    1,
+      Content:     "The content.",
+      PublishedAt: new(time.Time),
+      IsPinned:    true,
+    }
+
+    *expected.PublishedAt = time.Date(2019, time.March, 21, 0, 0, 0, 0, time.UTC)
+
+    r := mocks.NewArchiveRepository()
+    r.On(routine, ctx, expected).Return(id.String(), transfer.ImportCreated, nil)
+
+    result, err := NewImportService(r, readingSpeed).Import(ctx, "post.md", strings.NewReader(file))
+    require.NoError(t, err)
+    assert.Equal(t, &transfer.ArticleImportResult{
+      File:   "post.md",
+      UUID:   id,
+      Slug:   "on-writing-well",
+      Status: transfer.ImportCreated,
+    }, result)
+  })
+
+  t.Run("imports a draft without a topic", func(t *testing.T) {
+    file := "---\ntitle: Untitled thoughts\nslug: Some Slug\n---\nContent."
+
+    r := mocks.NewArchiveRepository()
+    r.On(routine, ctx, mock.MatchedBy(func(a *transfer.ArticleImport) bool {
+      return "some-slug" == a.Slug && nil == a.Topic && nil == a.PublishedAt
+    })).Return(id.String(), transfer.ImportUnchanged, nil)
+
+    result, err := NewImportService(r, readingSpeed).Import(ctx, "draft.md", strings.NewReader(file))
+    require.NoError(t, err)
+    assert.Equal(t, transfer.ImportUnchanged, result.Status)
+  })
+
+  t.Run("missing front matter", func(t *testing.T) {
+    r := mocks.NewArchiveRepository()
+    r.AssertNotCalled(t, routine)
+
+    result, err := NewImportService(r, readingSpeed).Import(ctx, "post.md", strings.NewReader("# Title\nContent."))
+    assert.Nil(t, result)
+
+    var p *problem.Problem
+    assert.ErrorAs(t, err, &p)
+  })
+
+  t.Run("invalid front matter", func(t *testing.T) {
+    r := mocks.NewArchiveRepository()
+    r.AssertNotCalled(t, routine)
+
+    _, err := NewImportService(r, readingSpeed).Import(ctx, "post.md", strings.NewReader("---\ntitle: [\n---\n"))
+
+    var p *problem.Problem
+    assert.ErrorAs(t, err, &p)
+  })
+
+  t.Run("validation failures", func(t *testing.T) {
+    r := mocks.NewArchiveRepository()
+    r.AssertNotCalled(t, routine)
+
+    files := []string{
+      "---\ntopic: Writing\n---\nNo title.",
+      "---\ntitle: " + strings.Repeat("x", 257) + "\n---\n",
+      "---\ntitle: Published\npublished_at: 2019-03-21\n---\nNo topic.",
+    }
+
+    for _, file := range files {
+      _, err := NewImportService(r, readingSpeed).Import(ctx, "post.md", strings.NewReader(file))
+
+      var p *problem.Problem
+      assert.ErrorAs(t, err, &p)
+    }
+  })
+
+  t.Run("gets a repository failure", func(t *testing.T) {
+    unexpected := errors.New("unexpected error")
+
+    r := mocks.NewArchiveRepository()
+    r.On(routine, ctx, mock.Anything).Return("", "", unexpected)
+
+    result, err := NewImportService(r, readingSpeed).Import(ctx, "post.md", strings.NewReader("---\ntitle: Title\n---\n"))
+    assert.Nil(t, result)
+    assert.ErrorIs(t, err, unexpected)
+  })
+}
+
+func TestImportService_ImportAll(t *testing.T) {
+  const routine = "Import"
+
+  ctx := context.TODO()
+
+  fsys := fstest.MapFS{
+    "b.md":           {Data: []byte("---\ntitle: B\n---\n")},
+    "a.markdown":     {Data: []byte("---\ntitle: A\n---\n")},
+    "notes/c.md":     {Data: []byte("---\ntitle: C\n---\n")},
+    "images/img.png": {Data: []byte{0x89}},
+  }
+
+  t.Run("success", func(t *testing.T) {
+    r := mocks.NewArchiveRepository()
+    r.On(routine, ctx, mock.Anything).Return(uuid.NewString(), transfer.ImportCreated, nil)
+
+    results, err := NewImportService(r, readingSpeed).ImportAll(ctx, fsys)
+    require.NoError(t, err)
+    require.Len(t, results, 3)
+    assert.Equal(t, "a.markdown", results[0].File)
+    assert.Equal(t, "b.md", results[1].File)
+    assert.Equal(t, "notes/c.md", results[2].File)
+  })
+
+  t.Run("stops at the first failure", func(t *testing.T) {
+    unexpected := errors.New("unexpected error")
+
+    r := mocks.NewArchiveRepository()
+    r.On(routine, ctx, mock.MatchedBy(func(a *transfer.ArticleImport) bool { return "A" == a.Title })).
+      Return(uuid.NewString(), transfer.ImportCreated, nil)
+    r.On(routine, ctx, mock.Anything).Return("", "", unexpected)
+
+    results, err := NewImportService(r, readingSpeed).ImportAll(ctx, fsys)
+    assert.ErrorIs(t, err, unexpected)
+    assert.Len(t, results, 1)
+  })
+}
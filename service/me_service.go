@@ -4,9 +4,13 @@ import (
   "context"
   "errors"
   "fontseca.dev/model"
+  "fontseca.dev/problem"
   "fontseca.dev/repository"
+  "fontseca.dev/tracing"
   "fontseca.dev/transfer"
+  "golang.org/x/crypto/bcrypt"
   "log/slog"
+  "strconv"
   "strings"
 )
 
@@ -21,24 +25,46 @@ type MeService interface {
   // errors when they occur. Returns true if the profile was successfully
   // updated, otherwise false.
   Update(ctx context.Context, update *transfer.MeUpdate) (updated bool, err error)
+
+  // SetPassword sets the password with which I log in to the
+  // administration panel, which must be at least minPasswordLength
+  // characters long. Only its hash is stored.
+  SetPassword(ctx context.Context, password string) error
+
+  // CheckPassword reports whether password is my password. It is false
+  // for any password until one is set.
+  CheckPassword(ctx context.Context, password string) (ok bool, err error)
 }
 
+// minPasswordLength is the minimum length of my password.
+const minPasswordLength = 12
+
 type meService struct {
-  r repository.MeRepository
+  r      repository.MeRepository
+  unit   repository.UnitOfWork
+  events Publisher
 }
 
-func NewMeService(r repository.MeRepository) MeService {
-  return &meService{r}
+// NewMeService returns a MeService that publishes the event of an
+// update of my information within unit, along with the update.
+func NewMeService(r repository.MeRepository, unit repository.UnitOfWork, events Publisher) MeService {
+  return &meService{r, unit, events}
 }
 
 func (m *meService) Get(ctx context.Context) (me *model.Me, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
   return m.r.Get(ctx)
 }
 
 func (m *meService) Update(ctx context.Context, update *transfer.MeUpdate) (updated bool, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
   if nil == update {
     err = errors.New("nil value for parameter: update")
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return false, err
   }
 
@@ -66,5 +92,48 @@ func (m *meService) Update(ctx context.Context, update *transfer.MeUpdate) (upda
   update.YouTubeURL = strings.TrimSpace(update.YouTubeURL)
   update.TwitterURL = strings.TrimSpace(update.TwitterURL)
   update.InstagramURL = strings.TrimSpace(update.InstagramURL)
-  return m.r.Update(ctx, update)
+  err = within(ctx, m.unit, func(ctx context.Context) (err error) {
+    if updated, err = m.r.Update(ctx, update); !updated {
+      return err
+    }
+
+    return publish(ctx, m.events, MeUpdated)
+  })
+
+  return updated && nil == err, err
+}
+
+func (m *meService) SetPassword(ctx context.Context, password string) error {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
+  if len([]rune(password)) < minPasswordLength {
+    return problem.NewValidation([3]string{"password", "min", strconv.Itoa(minPasswordLength)})
+  }
+
+  // bcrypt ignores whatever follows the first 72 bytes of a password,
+  // so a longer one would not be checked in full.
+  if 72 < len(password) {
+    return problem.NewValidation([3]string{"password", "max", "72"})
+  }
+
+  hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+  if nil != err {
+    slog.ErrorContext(ctx, err.Error())
+    return err
+  }
+
+  return m.r.SetPasswordHash(ctx, string(hash))
+}
+
+func (m *meService) CheckPassword(ctx context.Context, password string) (ok bool, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
+  hash, err := m.r.PasswordHash(ctx)
+  if nil != err || "" == hash || "" == password {
+    return false, err
+  }
+
+  return nil == bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)), nil
 }
@@ -5,10 +5,12 @@ import (
   "errors"
   "fontseca.dev/mocks"
   "fontseca.dev/model"
+  "fontseca.dev/problem"
   "fontseca.dev/transfer"
   "github.com/stretchr/testify/assert"
   "github.com/stretchr/testify/mock"
   "github.com/stretchr/testify/require"
+  "strings"
   "testing"
   "time"
 )
@@ -42,7 +44,7 @@ func TestMeService_Get(t *testing.T) {
     var r = mocks.NewMeRepository()
     var ctx = context.Background()
     r.On(routine, ctx).Return(&me, nil)
-    res, err := NewMeService(r).Get(ctx)
+    res, err := NewMeService(r, nil, nil).Get(ctx)
     require.NotNil(t, res)
     assert.NoError(t, err)
     assert.Equal(t, expected, *res)
@@ -53,7 +55,7 @@ func TestMeService_Get(t *testing.T) {
     var r = mocks.NewMeRepository()
     var ctx = context.Background()
     r.On(routine, mock.Anything).Return(nil, unexpected)
-    res, err := NewMeService(r).Get(ctx)
+    res, err := NewMeService(r, nil, nil).Get(ctx)
     assert.ErrorIs(t, err, unexpected)
     assert.Nil(t, res)
   })
@@ -96,7 +98,7 @@ func TestMeService_Update(t *testing.T) {
     var r = mocks.NewMeRepository()
     var ctx = context.Background()
     r.On(routine, ctx, &expected).Return(true, nil)
-    res, err := NewMeService(r).Update(ctx, &dirty)
+    res, err := NewMeService(r, nil, nil).Update(ctx, &dirty)
     assert.NoError(t, err)
     assert.True(t, res)
   })
@@ -105,7 +107,7 @@ func TestMeService_Update(t *testing.T) {
     var r = mocks.NewMeRepository()
     var ctx = context.Background()
     r.AssertNotCalled(t, routine)
-    res, err := NewMeService(r).Update(ctx, nil)
+    res, err := NewMeService(r, nil, nil).Update(ctx, nil)
     assert.ErrorContains(t, err, "nil value for parameter: update")
     assert.False(t, res)
   })
@@ -115,8 +117,67 @@ func TestMeService_Update(t *testing.T) {
     var r = mocks.NewMeRepository()
     var ctx = context.Background()
     r.On(routine, mock.Anything, mock.Anything).Return(false, unexpected)
-    res, err := NewMeService(r).Update(ctx, new(transfer.MeUpdate))
+    res, err := NewMeService(r, nil, nil).Update(ctx, new(transfer.MeUpdate))
     assert.ErrorIs(t, err, unexpected)
     assert.False(t, res)
   })
 }
+
+func TestMeService_SetPassword(t *testing.T) {
+  const routine = "SetPasswordHash"
+
+  t.Run("success", func(t *testing.T) {
+    const password = "correct horse battery"
+    var r = mocks.NewMeRepository()
+    var hash string
+    r.On(routine, mock.Anything, mock.AnythingOfType("string")).
+      Run(func(args mock.Arguments) { hash = args.String(1) }).
+      Return(nil)
+    assert.NoError(t, NewMeService(r, nil, nil).SetPassword(context.Background(), password))
+    require.NotEmpty(t, hash)
+    assert.NotContains(t, hash, password)
+
+    r.On("PasswordHash", mock.Anything).Return(hash, nil)
+    ok, err := NewMeService(r, nil, nil).CheckPassword(context.Background(), password)
+    assert.NoError(t, err)
+    assert.True(t, ok)
+    ok, err = NewMeService(r, nil, nil).CheckPassword(context.Background(), "wrong horse battery")
+    assert.NoError(t, err)
+    assert.False(t, ok)
+  })
+
+  t.Run("too short", func(t *testing.T) {
+    var r = mocks.NewMeRepository()
+    var err = NewMeService(r, nil, nil).SetPassword(context.Background(), "short")
+    assert.Equal(t, problem.NewValidation([3]string{"password", "min", "12"}), err)
+    r.AssertNotCalled(t, routine)
+  })
+
+  t.Run("too long", func(t *testing.T) {
+    var r = mocks.NewMeRepository()
+    var err = NewMeService(r, nil, nil).SetPassword(context.Background(), strings.Repeat("x", 73))
+    assert.Equal(t, problem.NewValidation([3]string{"password", "max", "72"}), err)
+    r.AssertNotCalled(t, routine)
+  })
+}
+
+func TestMeService_CheckPassword(t *testing.T) {
+  const routine = "PasswordHash"
+
+  t.Run("no password set", func(t *testing.T) {
+    var r = mocks.NewMeRepository()
+    r.On(routine, mock.Anything).Return("", nil)
+    ok, err := NewMeService(r, nil, nil).CheckPassword(context.Background(), "")
+    assert.NoError(t, err)
+    assert.False(t, ok)
+  })
+
+  t.Run("got an error", func(t *testing.T) {
+    var unexpected = errors.New("unexpected error")
+    var r = mocks.NewMeRepository()
+    r.On(routine, mock.Anything).Return("", unexpected)
+    ok, err := NewMeService(r, nil, nil).CheckPassword(context.Background(), "correct horse battery")
+    assert.ErrorIs(t, err, unexpected)
+    assert.False(t, ok)
+  })
+}
@@ -3,9 +3,11 @@ package service
 import (
   "context"
   "errors"
+  "fontseca.dev/diff"
   "fontseca.dev/model"
   "fontseca.dev/problem"
   "fontseca.dev/repository"
+  "fontseca.dev/tracing"
   "fontseca.dev/transfer"
   "log/slog"
   "strings"
@@ -16,6 +18,20 @@ type PatchesService interface {
   // Get retrieves all the ongoing article patches.
   Get(ctx context.Context) (patches []*model.ArticlePatch, err error)
 
+  // List retrieves the page of ongoing article patches that follows
+  // the cursor of page.
+  List(ctx context.Context, page *transfer.PageRequest) (patches *transfer.Page[*model.ArticlePatch], err error)
+
+  // Diff compares an article patch against the current version of the
+  // article it points to and reports what releasing it would change:
+  // a line-level diff of the content and the changes to its title,
+  // slug and topic.
+  //
+  // If other patches were released after the patch was created, the
+  // content is first merged with them as Release would do, and any
+  // overlapping change is reported as a conflict.
+  Diff(ctx context.Context, id string) (diff *transfer.ArticlePatchDiff, err error)
+
   // Revise adds a correction or inclusion to an article patch in order
   // to correct or improve it.
   Revise(ctx context.Context, id string, revision *transfer.ArticleRevision) error
@@ -27,7 +43,7 @@ type PatchesService interface {
   // eventually expire after a certain amount of time.
   Share(ctx context.Context, id string) (link string, err error)
 
-  // Discard completely drops an article patch but keeps the original
+  // Discard moves an article patch to the trash but keeps the original
   // article.
   Discard(ctx context.Context, id string) error
 
@@ -37,21 +53,125 @@ type PatchesService interface {
 }
 
 type patchesService struct {
-  r repository.ArchiveRepository
+  r            repository.ArchiveRepository
+  unit         repository.UnitOfWork
+  events       Publisher
+  readingSpeed int // words read per minute
 }
 
-func NewPatchesService(r repository.ArchiveRepository) PatchesService {
-  return &patchesService{r}
+// NewPatchesService returns a PatchesService that estimates reading times
+// at readingSpeed words per minute and reads a patch together with its
+// article within unit.
+func NewPatchesService(r repository.ArchiveRepository, unit repository.UnitOfWork, events Publisher, readingSpeed int) PatchesService {
+  return &patchesService{r, unit, events, readingSpeed}
 }
 
 func (s *patchesService) Get(ctx context.Context) (patches []*model.ArticlePatch, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
   return s.r.GetPatches(ctx)
 }
 
+func (s *patchesService) List(ctx context.Context, page *transfer.PageRequest) (patches *transfer.Page[*model.ArticlePatch], err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
+  return s.r.ListPatches(ctx, page)
+}
+
+func (s *patchesService) Diff(ctx context.Context, id string) (d *transfer.ArticlePatchDiff, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
+  if err = validateUUID(&id); nil != err {
+    return nil, err
+  }
+
+  var (
+    patch   *model.ArticlePatch
+    article *model.Article
+  )
+
+  // The patch and its article are read in the same transaction so that
+  // a release in between cannot make them disagree.
+  err = reading(ctx, s.unit, func(ctx context.Context) (err error) {
+    if patch, err = s.r.GetPatch(ctx, id); nil != err {
+      return err
+    }
+
+    article, err = s.r.GetByID(ctx, patch.ArticleUUID.String(), false)
+    return err
+  })
+
+  if nil != err {
+    return nil, err
+  }
+
+  var topic string
+
+  if nil != article.Topic {
+    topic = article.Topic.ID
+  }
+
+  var (
+    current = model.ArticleSnapshot{Title: article.Title, Slug: article.Slug, TopicID: topic, Content: article.Content}
+    merged  = *patch
+  )
+
+  d = &transfer.ArticlePatchDiff{
+    PatchUUID:    patch.UUID,
+    ArticleUUID:  patch.ArticleUUID,
+    BaseRevision: patch.BaseRevision,
+    Revision:     article.Revision,
+    Fields:       make([]*transfer.FieldChange, 0),
+    Conflicts:    make([]*transfer.PatchConflict, 0),
+  }
+
+  // The patch is merged with the newer revisions as Release merges it,
+  // so that the diff shows what releasing it would actually write.
+  if patch.BaseRevision != article.Revision && nil != patch.Base {
+    d.Conflicts = repository.MergePatch(&merged, &current)
+  }
+
+  fields := []struct {
+    name    string
+    current string
+    patched *string
+  }{
+    {"title", current.Title, merged.Title},
+    {"slug", current.Slug, merged.Slug},
+    {"topic", current.TopicID, merged.TopicID},
+  }
+
+  for _, field := range fields {
+    if nil != field.patched && *field.patched != field.current {
+      d.Fields = append(d.Fields, &transfer.FieldChange{
+        Field: field.name,
+        Old:   field.current,
+        New:   *field.patched,
+      })
+    }
+  }
+
+  content := current.Content
+
+  if nil != merged.Content {
+    content = *merged.Content
+  }
+
+  d.Content = diff.Lines(article.Content, content)
+
+  return d, nil
+}
+
 func (s *patchesService) Revise(ctx context.Context, id string, revision *transfer.ArticleRevision) error {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
   if nil == revision {
     err := errors.New("nil value for parameter: revision")
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return err
   }
 
@@ -73,30 +193,22 @@ func (s *patchesService) Revise(ctx context.Context, id string, revision *transf
     return problem.NewValidation([3]string{"content", "max", "3145728"})
   }
 
-  if "" != revision.Title || "" != revision.Content {
-    builder := strings.Builder{}
-
-    if "" != revision.Title {
-      builder.WriteString(revision.Title)
-      revision.Slug = generateSlug(revision.Title)
-    }
-
-    if "" != revision.Content {
-      if 0 < builder.Len() {
-        builder.WriteRune('\n')
-      }
-
-      builder.WriteString(revision.Content)
-    }
+  if "" != revision.Title {
+    revision.Slug = generateSlug(revision.Title)
+  }
 
-    r := strings.NewReader(builder.String())
-    revision.ReadTime = computePostReadingTimeInMinutes(r)
+  if "" != revision.Title || "" != revision.Content {
+    r := strings.NewReader(readableText(revision.Title, revision.Content))
+    revision.ReadTime = computePostReadingTimeInMinutes(r, s.readingSpeed)
   }
 
   return s.r.Revise(ctx, id, revision)
 }
 
 func (s *patchesService) Share(ctx context.Context, id string) (link string, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
   if err = validateUUID(&id); nil != err {
     return "about:blank", err
   }
@@ -111,6 +223,9 @@ func (s *patchesService) Share(ctx context.Context, id string) (link string, err
 }
 
 func (s *patchesService) Discard(ctx context.Context, id string) error {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
   if err := validateUUID(&id); nil != err {
     return err
   }
@@ -119,9 +234,18 @@ func (s *patchesService) Discard(ctx context.Context, id string) error {
 }
 
 func (s *patchesService) Release(ctx context.Context, id string) error {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
   if err := validateUUID(&id); nil != err {
     return err
   }
 
-  return s.r.Release(ctx, id)
+  return within(ctx, s.unit, func(ctx context.Context) error {
+    if err := s.r.Release(ctx, id); nil != err {
+      return err
+    }
+
+    return publish(ctx, s.events, PatchReleased, "patch_uuid", id)
+  })
 }
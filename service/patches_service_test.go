@@ -3,6 +3,7 @@ package service
 import (
   "context"
   "errors"
+  "fontseca.dev/diff"
   "fontseca.dev/mocks"
   "fontseca.dev/model"
   "fontseca.dev/transfer"
@@ -24,7 +25,7 @@ func TestPatchesService_Get(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.On(routine, ctx).Return(expectedPatches, nil)
 
-    articles, err := NewPatchesService(r).Get(ctx)
+    articles, err := NewPatchesService(r, nil, nil, readingSpeed).Get(ctx)
 
     assert.Equal(t, expectedPatches, articles)
     assert.NoError(t, err)
@@ -36,13 +37,181 @@ func TestPatchesService_Get(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.On(routine, ctx).Return(nil, unexpected)
 
-    articles, err := NewPatchesService(r).Get(ctx)
+    articles, err := NewPatchesService(r, nil, nil, readingSpeed).Get(ctx)
 
     assert.Nil(t, articles)
     assert.ErrorIs(t, err, unexpected)
   })
 }
 
+func TestPatchesService_Diff(t *testing.T) {
+  const (
+    getPatch = "GetPatch"
+    getByID  = "GetByID"
+    read     = "Read"
+  )
+
+  ctx := context.TODO()
+  id := uuid.NewString()
+  title, content := "New title", "one\n2\nthree"
+
+  patch := &model.ArticlePatch{
+    UUID:         uuid.MustParse(id),
+    ArticleUUID:  uuid.New(),
+    BaseRevision: 1,
+    Base:         &model.ArticleSnapshot{Title: "Title", Slug: "title", Content: "one\ntwo\nthree"},
+    Title:        &title,
+    Content:      &content,
+  }
+
+  t.Run("success", func(t *testing.T) {
+    article := &model.Article{Title: "Title", Slug: "title", Revision: 1, Content: "one\ntwo\nthree"}
+
+    u := mocks.NewUnitOfWork()
+    u.On(read, ctx).Return(nil)
+
+    r := mocks.NewArchiveRepository()
+    r.On(getPatch, ctx, id).Return(patch, nil)
+    r.On(getByID, ctx, patch.ArticleUUID.String(), false).Return(article, nil)
+
+    d, err := NewPatchesService(r, u, nil, readingSpeed).Diff(ctx, id)
+    assert.NoError(t, err)
+    assert.Equal(t, []*transfer.FieldChange{{Field: "title", Old: "Title", New: title}}, d.Fields)
+    assert.Equal(t, []diff.Line{
+      {Operation: diff.Equal, Text: "one", Old: 1, New: 1},
+      {Operation: diff.Delete, Text: "two", Old: 2},
+      {Operation: diff.Insert, Text: "2", New: 2},
+      {Operation: diff.Equal, Text: "three", Old: 3, New: 3},
+    }, d.Content)
+    assert.Empty(t, d.Conflicts)
+  })
+
+  t.Run("merges with newer revisions", func(t *testing.T) {
+    article := &model.Article{Title: "Title", Slug: "title", Revision: 2, Content: "one\ntwo\nthree\nfour"}
+
+    u := mocks.NewUnitOfWork()
+    u.On(read, ctx).Return(nil)
+
+    r := mocks.NewArchiveRepository()
+    r.On(getPatch, ctx, id).Return(patch, nil)
+    r.On(getByID, ctx, patch.ArticleUUID.String(), false).Return(article, nil)
+
+    d, err := NewPatchesService(r, u, nil, readingSpeed).Diff(ctx, id)
+    assert.NoError(t, err)
+    assert.Len(t, d.Content, 5)
+    assert.Equal(t, "four", d.Content[4].Text)
+    assert.Equal(t, diff.Equal, d.Content[4].Operation)
+    assert.Empty(t, d.Conflicts)
+  })
+
+  t.Run("reports conflicts", func(t *testing.T) {
+    article := &model.Article{Title: "Title", Slug: "title", Revision: 2, Content: "one\nTWO\nthree"}
+
+    u := mocks.NewUnitOfWork()
+    u.On(read, ctx).Return(nil)
+
+    r := mocks.NewArchiveRepository()
+    r.On(getPatch, ctx, id).Return(patch, nil)
+    r.On(getByID, ctx, patch.ArticleUUID.String(), false).Return(article, nil)
+
+    d, err := NewPatchesService(r, u, nil, readingSpeed).Diff(ctx, id)
+    assert.NoError(t, err)
+    assert.Len(t, d.Conflicts, 1)
+  })
+
+  t.Run("keeps the fields changed by newer revisions", func(t *testing.T) {
+    unchanged := "Title"
+    patch := &model.ArticlePatch{
+      UUID:         uuid.MustParse(id),
+      ArticleUUID:  patch.ArticleUUID,
+      BaseRevision: 1,
+      Base:         patch.Base,
+      Title:        &unchanged,
+      Content:      &content,
+    }
+
+    article := &model.Article{Title: "Newer title", Slug: "newer-title", Revision: 2, Content: "one\ntwo\nthree"}
+
+    u := mocks.NewUnitOfWork()
+    u.On(read, ctx).Return(nil)
+
+    r := mocks.NewArchiveRepository()
+    r.On(getPatch, ctx, id).Return(patch, nil)
+    r.On(getByID, ctx, patch.ArticleUUID.String(), false).Return(article, nil)
+
+    d, err := NewPatchesService(r, u, nil, readingSpeed).Diff(ctx, id)
+    assert.NoError(t, err)
+    assert.Empty(t, d.Fields)
+    assert.Empty(t, d.Conflicts)
+    assert.Equal(t, "Title", *patch.Title)
+  })
+
+  t.Run("reports conflicting fields", func(t *testing.T) {
+    article := &model.Article{Title: "Another title", Slug: "title", Revision: 2, Content: "one\ntwo\nthree"}
+
+    u := mocks.NewUnitOfWork()
+    u.On(read, ctx).Return(nil)
+
+    r := mocks.NewArchiveRepository()
+    r.On(getPatch, ctx, id).Return(patch, nil)
+    r.On(getByID, ctx, patch.ArticleUUID.String(), false).Return(article, nil)
+
+    d, err := NewPatchesService(r, u, nil, readingSpeed).Diff(ctx, id)
+    assert.NoError(t, err)
+    assert.Equal(t, []*transfer.PatchConflict{{Field: "title", Base: "Title", Patch: title, Article: "Another title"}}, d.Conflicts)
+  })
+
+  t.Run("without a unit of work", func(t *testing.T) {
+    article := &model.Article{Title: "Title", Slug: "title", Revision: 1, Content: "one\ntwo\nthree"}
+
+    r := mocks.NewArchiveRepository()
+    r.On(getPatch, ctx, id).Return(patch, nil)
+    r.On(getByID, ctx, patch.ArticleUUID.String(), false).Return(article, nil)
+
+    d, err := NewPatchesService(r, nil, nil, readingSpeed).Diff(ctx, id)
+    assert.NoError(t, err)
+    assert.Len(t, d.Fields, 1)
+  })
+
+  t.Run("gets a repository failure", func(t *testing.T) {
+    unexpected := errors.New("unexpected error")
+
+    u := mocks.NewUnitOfWork()
+    u.On(read, ctx).Return(nil)
+
+    r := mocks.NewArchiveRepository()
+    r.On(getPatch, ctx, id).Return(nil, unexpected)
+
+    d, err := NewPatchesService(r, u, nil, readingSpeed).Diff(ctx, id)
+    assert.Nil(t, d)
+    assert.ErrorIs(t, err, unexpected)
+  })
+
+  t.Run("gets a transaction failure", func(t *testing.T) {
+    unexpected := errors.New("unexpected error")
+
+    u := mocks.NewUnitOfWork()
+    u.On(read, ctx).Return(unexpected)
+
+    r := mocks.NewArchiveRepository()
+
+    d, err := NewPatchesService(r, u, nil, readingSpeed).Diff(ctx, id)
+    assert.Nil(t, d)
+    assert.ErrorIs(t, err, unexpected)
+    r.AssertNotCalled(t, getPatch)
+  })
+
+  t.Run("wrong uuid", func(t *testing.T) {
+    u := mocks.NewUnitOfWork()
+
+    r := mocks.NewArchiveRepository()
+    r.AssertNotCalled(t, getPatch)
+
+    _, err := NewPatchesService(r, u, nil, readingSpeed).Diff(ctx, "e4d06ba7-f086-47dc-9f5e")
+    assert.Error(t, err)
+  })
+}
+
 func TestPatchesService_Revise(t *testing.T) {
   const routine = "Revise"
   ctx := context.TODO()
@@ -64,7 +233,7 @@ func TestPatchesService_Revise(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.On(routine, ctx, id, revision).Return(nil)
 
-    assert.NoError(t, NewPatchesService(r).Revise(ctx, id, dirty))
+    assert.NoError(t, NewPatchesService(r, nil, nil, readingSpeed).Revise(ctx, id, dirty))
   })
 
   t.Run("success: changing title", func(t *testing.T) {
@@ -81,7 +250,7 @@ func TestPatchesService_Revise(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.On(routine, mock.Anything, mock.Anything, revision).Return(nil)
 
-    assert.NoError(t, NewPatchesService(r).Revise(ctx, id, dirty))
+    assert.NoError(t, NewPatchesService(r, nil, nil, readingSpeed).Revise(ctx, id, dirty))
   })
 
   t.Run("success: changing content", func(t *testing.T) {
@@ -97,19 +266,19 @@ func TestPatchesService_Revise(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.On(routine, mock.Anything, mock.Anything, revision).Return(nil)
 
-    assert.NoError(t, NewPatchesService(r).Revise(ctx, id, dirty))
+    assert.NoError(t, NewPatchesService(r, nil, nil, readingSpeed).Revise(ctx, id, dirty))
   })
 
   t.Run("nil parameter: revision", func(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.AssertNotCalled(t, routine)
-    assert.ErrorContains(t, NewPatchesService(r).Revise(ctx, id, nil), "nil value")
+    assert.ErrorContains(t, NewPatchesService(r, nil, nil, readingSpeed).Revise(ctx, id, nil), "nil value")
   })
 
   t.Run("wrong uuid: id", func(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.AssertNotCalled(t, routine)
-    assert.Error(t, NewPatchesService(r).Revise(ctx, "x", &transfer.ArticleRevision{}))
+    assert.Error(t, NewPatchesService(r, nil, nil, readingSpeed).Revise(ctx, "x", &transfer.ArticleRevision{}))
   })
 
   t.Run("gets a repository failure", func(t *testing.T) {
@@ -118,7 +287,7 @@ func TestPatchesService_Revise(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.On(routine, mock.Anything, mock.Anything, mock.Anything).Return(unexpected)
 
-    assert.ErrorIs(t, NewPatchesService(r).Revise(ctx, id, &transfer.ArticleRevision{}), unexpected)
+    assert.ErrorIs(t, NewPatchesService(r, nil, nil, readingSpeed).Revise(ctx, id, &transfer.ArticleRevision{}), unexpected)
   })
 }
 
@@ -134,7 +303,7 @@ func TestPatchesService_Share(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.On(routine, ctx, id).Return(expectedLink, nil)
 
-    link, err := NewPatchesService(r).Share(ctx, id)
+    link, err := NewPatchesService(r, nil, nil, readingSpeed).Share(ctx, id)
 
     assert.Equal(t, expectedLink, link)
     assert.NoError(t, err)
@@ -146,7 +315,7 @@ func TestPatchesService_Share(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.AssertNotCalled(t, routine)
 
-    link, err := NewPatchesService(r).Share(ctx, id)
+    link, err := NewPatchesService(r, nil, nil, readingSpeed).Share(ctx, id)
 
     assert.Error(t, err)
     assert.Equal(t, "about:blank", link)
@@ -158,7 +327,7 @@ func TestPatchesService_Share(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.On(routine, mock.Anything, mock.Anything, mock.Anything).Return("", unexpected)
 
-    link, err := NewPatchesService(r).Share(ctx, uuid.NewString())
+    link, err := NewPatchesService(r, nil, nil, readingSpeed).Share(ctx, uuid.NewString())
 
     assert.Equal(t, "about:blank", link)
     assert.ErrorIs(t, err, unexpected)
@@ -175,7 +344,7 @@ func TestPatchesService_Discard(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.On(routine, ctx, id).Return(nil)
 
-    assert.NoError(t, NewPatchesService(r).Discard(ctx, id))
+    assert.NoError(t, NewPatchesService(r, nil, nil, readingSpeed).Discard(ctx, id))
   })
 
   t.Run("gets a repository failure", func(t *testing.T) {
@@ -184,7 +353,7 @@ func TestPatchesService_Discard(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.On(routine, mock.Anything, mock.Anything).Return(unexpected)
 
-    assert.ErrorIs(t, NewPatchesService(r).Discard(ctx, id), unexpected)
+    assert.ErrorIs(t, NewPatchesService(r, nil, nil, readingSpeed).Discard(ctx, id), unexpected)
   })
 
   t.Run("wrong uuid", func(t *testing.T) {
@@ -193,7 +362,7 @@ func TestPatchesService_Discard(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.AssertNotCalled(t, routine)
 
-    assert.Error(t, NewPatchesService(r).Discard(ctx, id))
+    assert.Error(t, NewPatchesService(r, nil, nil, readingSpeed).Discard(ctx, id))
   })
 }
 
@@ -207,7 +376,18 @@ func TestPatchesService_Release(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.On(routine, ctx, id).Return(nil)
 
-    assert.NoError(t, NewPatchesService(r).Release(ctx, id))
+    assert.NoError(t, NewPatchesService(r, nil, nil, readingSpeed).Release(ctx, id))
+  })
+
+  t.Run("publishes the event", func(t *testing.T) {
+    r := mocks.NewArchiveRepository()
+    r.On(routine, ctx, id).Return(nil)
+
+    p := mocks.NewPublisher()
+    p.On("Publish", ctx, PatchReleased, map[string]string{"patch_uuid": id}).Return(nil)
+
+    assert.NoError(t, NewPatchesService(r, nil, p, readingSpeed).Release(ctx, id))
+    p.AssertExpectations(t)
   })
 
   t.Run("gets a repository failure", func(t *testing.T) {
@@ -216,7 +396,10 @@ func TestPatchesService_Release(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.On(routine, mock.Anything, mock.Anything).Return(unexpected)
 
-    assert.ErrorIs(t, NewPatchesService(r).Release(ctx, id), unexpected)
+    p := mocks.NewPublisher()
+
+    assert.ErrorIs(t, NewPatchesService(r, nil, p, readingSpeed).Release(ctx, id), unexpected)
+    p.AssertNotCalled(t, "Publish")
   })
 
   t.Run("wrong uuid", func(t *testing.T) {
@@ -225,6 +408,6 @@ func TestPatchesService_Release(t *testing.T) {
     r := mocks.NewArchiveRepository()
     r.AssertNotCalled(t, routine)
 
-    assert.Error(t, NewPatchesService(r).Release(ctx, id))
+    assert.Error(t, NewPatchesService(r, nil, nil, readingSpeed).Release(ctx, id))
   })
 }
@@ -6,6 +6,7 @@ import (
   "fontseca.dev/model"
   "fontseca.dev/problem"
   "fontseca.dev/repository"
+  "fontseca.dev/tracing"
   "fontseca.dev/transfer"
   "log/slog"
   "net/http"
@@ -18,6 +19,10 @@ type ProjectsService interface {
   // Get retrieves a slice of projects.
   Get(ctx context.Context, archived ...bool) (projects []*model.Project, err error)
 
+  // List retrieves the page of archived or not archived projects that
+  // follows the cursor of page.
+  List(ctx context.Context, archived bool, page *transfer.PageRequest) (projects *transfer.Page[*model.Project], err error)
+
   // GetByID retrieves a single project by its UUID.
   GetByID(ctx context.Context, id string) (project *model.Project, err error)
 
@@ -34,10 +39,11 @@ type ProjectsService interface {
   // Update modifies an existing project record with the provided update data.
   Update(ctx context.Context, id string, update *transfer.ProjectUpdate) (updated bool, err error)
 
-  // Unarchive makes a project not archived so that it can be normally listed.
-  Unarchive(ctx context.Context, id string) (unarchived bool, err error)
+  // Unarchive makes a project not archived so that it can be normally
+  // listed. version must be the current version of the project.
+  Unarchive(ctx context.Context, id string, version int) (unarchived bool, err error)
 
-  // Remove deletes an existing project. If not found, returns a not found error.
+  // Remove moves an existing project to the trash. If not found, returns a not found error.
   Remove(ctx context.Context, id string) (err error)
 
   // ContainsTechnologyTag checks whether technologyTagID belongs to projectID.
@@ -51,14 +57,23 @@ type ProjectsService interface {
 }
 
 type projectsService struct {
-  r repository.ProjectsRepository
+  r            repository.ProjectsRepository
+  unit         repository.UnitOfWork
+  events       Publisher
+  readingSpeed int // words read per minute
 }
 
-func NewProjectsService(repository repository.ProjectsRepository) ProjectsService {
-  return &projectsService{repository}
+// NewProjectsService returns a ProjectsService that estimates reading
+// times at readingSpeed words per minute and publishes the events of
+// the changes to the projects within unit, along with them.
+func NewProjectsService(r repository.ProjectsRepository, unit repository.UnitOfWork, events Publisher, readingSpeed int) ProjectsService {
+  return &projectsService{r, unit, events, readingSpeed}
 }
 
 func (s *projectsService) Get(ctx context.Context, archived ...bool) (projects []*model.Project, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
   var a = false
   if 0 != len(archived) && archived[0] {
     a = true
@@ -66,7 +81,17 @@ func (s *projectsService) Get(ctx context.Context, archived ...bool) (projects [
   return s.r.Get(ctx, a)
 }
 
+func (s *projectsService) List(ctx context.Context, archived bool, page *transfer.PageRequest) (projects *transfer.Page[*model.Project], err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
+  return s.r.List(ctx, archived, page)
+}
+
 func (s *projectsService) GetByID(ctx context.Context, id string) (project *model.Project, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
   if err = validateUUID(&id); err != nil {
     return nil, err
   }
@@ -74,13 +99,19 @@ func (s *projectsService) GetByID(ctx context.Context, id string) (project *mode
 }
 
 func (s *projectsService) GetBySlug(ctx context.Context, slug string) (project *model.Project, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
   return s.r.GetBySlug(ctx, slug)
 }
 
 func (s *projectsService) Add(ctx context.Context, creation *transfer.ProjectCreation) (id string, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
   if nil == creation {
     err = errors.New("nil value for parameter: creation")
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return "", err
   }
 
@@ -143,7 +174,7 @@ func (s *projectsService) Add(ctx context.Context, creation *transfer.ProjectCre
 
   if 0 < projectText.Len() {
     var r = strings.NewReader(projectText.String())
-    creation.ReadTime = computePostReadingTimeInMinutes(r)
+    creation.ReadTime = computePostReadingTimeInMinutes(r, s.readingSpeed)
   }
 
   creation.Slug = generateSlug(creation.Name)
@@ -157,10 +188,25 @@ func (s *projectsService) Add(ctx context.Context, creation *transfer.ProjectCre
     return "", err
   }
 
-  return s.r.Add(ctx, creation)
+  err = within(ctx, s.unit, func(ctx context.Context) (err error) {
+    if id, err = s.r.Add(ctx, creation); nil != err {
+      return err
+    }
+
+    return publish(ctx, s.events, ProjectAdded, "project_uuid", id)
+  })
+
+  if nil != err {
+    return "", err
+  }
+
+  return id, nil
 }
 
 func (s *projectsService) Exists(ctx context.Context, id string) (err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
   if err = validateUUID(&id); err != nil {
     return err
   }
@@ -168,9 +214,12 @@ func (s *projectsService) Exists(ctx context.Context, id string) (err error) {
 }
 
 func (s *projectsService) Update(ctx context.Context, id string, update *transfer.ProjectUpdate) (updated bool, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
   if nil == update {
     err = errors.New("nil value for parameter: update")
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return false, err
   }
 
@@ -244,7 +293,7 @@ func (s *projectsService) Update(ctx context.Context, id string, update *transfe
 
   if 0 < projectText.Len() {
     var r = strings.NewReader(projectText.String())
-    update.ReadTime = computePostReadingTimeInMinutes(r)
+    update.ReadTime = computePostReadingTimeInMinutes(r, s.readingSpeed)
   }
 
   if "" != update.Name {
@@ -262,24 +311,55 @@ func (s *projectsService) Update(ctx context.Context, id string, update *transfe
     return false, err
   }
 
-  return s.r.Update(ctx, id, update)
+  err = within(ctx, s.unit, func(ctx context.Context) (err error) {
+    if updated, err = s.r.Update(ctx, id, update); !updated {
+      return err
+    }
+
+    return publish(ctx, s.events, ProjectUpdated, "project_uuid", id)
+  })
+
+  return updated && nil == err, err
 }
 
-func (s *projectsService) Unarchive(ctx context.Context, id string) (unarchived bool, err error) {
+func (s *projectsService) Unarchive(ctx context.Context, id string, version int) (unarchived bool, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
   if err = validateUUID(&id); err != nil {
     return false, err
   }
-  return s.r.Unarchive(ctx, id)
+  err = within(ctx, s.unit, func(ctx context.Context) (err error) {
+    if unarchived, err = s.r.Unarchive(ctx, id, version); !unarchived {
+      return err
+    }
+
+    return publish(ctx, s.events, ProjectUpdated, "project_uuid", id)
+  })
+
+  return unarchived && nil == err, err
 }
 
 func (s *projectsService) Remove(ctx context.Context, id string) (err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
   if err = validateUUID(&id); err != nil {
     return err
   }
-  return s.r.Remove(ctx, id)
+  return within(ctx, s.unit, func(ctx context.Context) error {
+    if err := s.r.Remove(ctx, id); nil != err {
+      return err
+    }
+
+    return publish(ctx, s.events, ProjectRemoved, "project_uuid", id)
+  })
 }
 
 func (s *projectsService) ContainsTechnologyTag(ctx context.Context, projectID, technologyTagID string) (success bool, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
   if err = validateUUID(&projectID); err != nil {
     return false, err
   }
@@ -290,6 +370,9 @@ func (s *projectsService) ContainsTechnologyTag(ctx context.Context, projectID,
 }
 
 func (s *projectsService) AddTechnologyTag(ctx context.Context, projectID, technologyTagID string) (added bool, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
   if err = validateUUID(&projectID); err != nil {
     return false, err
   }
@@ -309,15 +392,34 @@ func (s *projectsService) AddTechnologyTag(ctx context.Context, projectID, techn
     p.With("technology_tag_id", projectID)
     return false, &p
   }
-  return s.r.AddTechnologyTag(ctx, projectID, technologyTagID)
+  err = within(ctx, s.unit, func(ctx context.Context) (err error) {
+    if added, err = s.r.AddTechnologyTag(ctx, projectID, technologyTagID); !added {
+      return err
+    }
+
+    return publish(ctx, s.events, ProjectUpdated, "project_uuid", projectID)
+  })
+
+  return added && nil == err, err
 }
 
 func (s *projectsService) RemoveTechnologyTag(ctx context.Context, projectID, technologyTagID string) (removed bool, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
   if err = validateUUID(&projectID); err != nil {
     return false, err
   }
   if err = validateUUID(&technologyTagID); err != nil {
     return false, err
   }
-  return s.r.RemoveTechnologyTag(ctx, projectID, technologyTagID)
+  err = within(ctx, s.unit, func(ctx context.Context) (err error) {
+    if removed, err = s.r.RemoveTechnologyTag(ctx, projectID, technologyTagID); !removed {
+      return err
+    }
+
+    return publish(ctx, s.events, ProjectUpdated, "project_uuid", projectID)
+  })
+
+  return removed && nil == err, err
 }
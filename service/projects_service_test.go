@@ -23,19 +23,19 @@ func TestProjectService_Get(t *testing.T) {
 
     var r = mocks.NewProjectsRepository()
     r.On(routine, ctx, true).Return(projects, nil)
-    res, err := NewProjectsService(r).Get(ctx, true)
+    res, err := NewProjectsService(r, nil, nil, readingSpeed).Get(ctx, true)
     assert.NotNil(t, res)
     assert.NoError(t, err)
 
     r = mocks.NewProjectsRepository()
     r.On(routine, ctx, false).Return(projects, nil)
-    res, err = NewProjectsService(r).Get(ctx, false)
+    res, err = NewProjectsService(r, nil, nil, readingSpeed).Get(ctx, false)
     assert.NotNil(t, res)
     assert.NoError(t, err)
 
     r = mocks.NewProjectsRepository()
     r.On(routine, ctx, false).Return(projects, nil)
-    res, err = NewProjectsService(r).Get(ctx)
+    res, err = NewProjectsService(r, nil, nil, readingSpeed).Get(ctx)
     assert.NotNil(t, res)
     assert.NoError(t, err)
   })
@@ -44,7 +44,7 @@ func TestProjectService_Get(t *testing.T) {
     var unexpected = errors.New("unexpected error")
     var r = mocks.NewProjectsRepository()
     r.On(routine, ctx, false).Return(nil, unexpected)
-    res, err := NewProjectsService(r).Get(ctx)
+    res, err := NewProjectsService(r, nil, nil, readingSpeed).Get(ctx)
     assert.Nil(t, res)
     assert.ErrorIs(t, err, unexpected)
   })
@@ -59,7 +59,7 @@ func TestProjectService_GetByID(t *testing.T) {
     var project = new(model.Project)
     var r = mocks.NewProjectsRepository()
     r.On(routine, ctx, id).Return(project, nil)
-    res, err := NewProjectsService(r).GetByID(ctx, id)
+    res, err := NewProjectsService(r, nil, nil, readingSpeed).GetByID(ctx, id)
     assert.Equal(t, project, res)
     assert.NoError(t, err)
   })
@@ -68,7 +68,7 @@ func TestProjectService_GetByID(t *testing.T) {
     var unexpected = errors.New("unexpected error")
     var r = mocks.NewProjectsRepository()
     r.On(routine, ctx, id).Return(nil, unexpected)
-    res, err := NewProjectsService(r).GetByID(ctx, id)
+    res, err := NewProjectsService(r, nil, nil, readingSpeed).GetByID(ctx, id)
     assert.Nil(t, res)
     assert.ErrorIs(t, err, unexpected)
   })
@@ -83,7 +83,7 @@ func TestProjectService_GetBySlug(t *testing.T) {
     var project = new(model.Project)
     var r = mocks.NewProjectsRepository()
     r.On(routine, ctx, slug).Return(project, nil)
-    res, err := NewProjectsService(r).GetBySlug(ctx, slug)
+    res, err := NewProjectsService(r, nil, nil, readingSpeed).GetBySlug(ctx, slug)
     assert.Equal(t, project, res)
     assert.NoError(t, err)
   })
@@ -92,7 +92,7 @@ func TestProjectService_GetBySlug(t *testing.T) {
     var unexpected = errors.New("unexpected error")
     var r = mocks.NewProjectsRepository()
     r.On(routine, ctx, slug).Return(nil, unexpected)
-    res, err := NewProjectsService(r).GetBySlug(ctx, slug)
+    res, err := NewProjectsService(r, nil, nil, readingSpeed).GetBySlug(ctx, slug)
     assert.Nil(t, res)
     assert.ErrorIs(t, err, unexpected)
   })
@@ -132,7 +132,7 @@ func TestProjectsService_Add(t *testing.T) {
     }
     var r = mocks.NewProjectsRepository()
     r.On(routine, ctx, &creation).Return(id, nil)
-    res, err := NewProjectsService(r).Add(ctx, &dirty)
+    res, err := NewProjectsService(r, nil, nil, readingSpeed).Add(ctx, &dirty)
     assert.NoError(t, err)
     assert.Equal(t, id, res)
   })
@@ -147,7 +147,7 @@ func TestProjectsService_Add(t *testing.T) {
     }
     var r = mocks.NewProjectsRepository()
     r.On(routine, ctx, &creation).Return(id, nil)
-    res, err := NewProjectsService(r).Add(ctx, &dirty)
+    res, err := NewProjectsService(r, nil, nil, readingSpeed).Add(ctx, &dirty)
     assert.NoError(t, err)
     assert.Equal(t, id, res)
   })
@@ -155,7 +155,7 @@ func TestProjectsService_Add(t *testing.T) {
   t.Run("no nil parameter", func(t *testing.T) {
     var r = mocks.NewProjectsRepository()
     r.AssertNotCalled(t, routine)
-    res, err := NewProjectsService(r).Add(ctx, nil)
+    res, err := NewProjectsService(r, nil, nil, readingSpeed).Add(ctx, nil)
     assert.ErrorContains(t, err, "nil value for parameter: creation")
     assert.Empty(t, res)
   })
@@ -167,14 +167,14 @@ func TestProjectsService_Add(t *testing.T) {
       creation.Name = strings.Repeat("x", 36)
       var r = mocks.NewProjectsRepository()
       r.On(routine, ctx, &creation).Return(id, nil)
-      res, err := NewProjectsService(r).Add(ctx, &creation)
+      res, err := NewProjectsService(r, nil, nil, readingSpeed).Add(ctx, &creation)
       assert.NoError(t, err)
       assert.Equal(t, id, res)
 
       creation.Name = "https://" + strings.Repeat("x", 1+2036) + ".com"
       r = mocks.NewProjectsRepository()
       r.On(routine, ctx, &creation).Return(id, nil)
-      res, err = NewProjectsService(r).Add(ctx, &creation)
+      res, err = NewProjectsService(r, nil, nil, readingSpeed).Add(ctx, &creation)
       assert.Error(t, err)
       assert.Empty(t, res)
     })
@@ -185,14 +185,14 @@ func TestProjectsService_Add(t *testing.T) {
       creation.Homepage = "https://" + strings.Repeat("x", 2036) + ".com"
       var r = mocks.NewProjectsRepository()
       r.On(routine, ctx, &creation).Return(id, nil)
-      res, err := NewProjectsService(r).Add(ctx, &creation)
+      res, err := NewProjectsService(r, nil, nil, readingSpeed).Add(ctx, &creation)
       assert.NoError(t, err)
       assert.Equal(t, id, res)
 
       creation.Homepage = "https://" + strings.Repeat("x", 1+2036) + ".com"
       r = mocks.NewProjectsRepository()
       r.On(routine, ctx, &creation).Return(id, nil)
-      res, err = NewProjectsService(r).Add(ctx, &creation)
+      res, err = NewProjectsService(r, nil, nil, readingSpeed).Add(ctx, &creation)
       assert.Error(t, err)
       assert.Empty(t, res)
     })
@@ -203,14 +203,14 @@ func TestProjectsService_Add(t *testing.T) {
       creation.Language = strings.Repeat("x", 64)
       var r = mocks.NewProjectsRepository()
       r.On(routine, ctx, &creation).Return(id, nil)
-      res, err := NewProjectsService(r).Add(ctx, &creation)
+      res, err := NewProjectsService(r, nil, nil, readingSpeed).Add(ctx, &creation)
       assert.NoError(t, err)
       assert.Equal(t, id, res)
 
       creation.Language = strings.Repeat("x", 1+64)
       r = mocks.NewProjectsRepository()
       r.On(routine, ctx, &creation).Return(id, nil)
-      res, err = NewProjectsService(r).Add(ctx, &creation)
+      res, err = NewProjectsService(r, nil, nil, readingSpeed).Add(ctx, &creation)
       assert.Error(t, err)
       assert.Empty(t, res)
     })
@@ -221,14 +221,14 @@ func TestProjectsService_Add(t *testing.T) {
       creation.Summary = strings.Repeat("x", 1024)
       var r = mocks.NewProjectsRepository()
       r.On(routine, ctx, &creation).Return(id, nil)
-      res, err := NewProjectsService(r).Add(ctx, &creation)
+      res, err := NewProjectsService(r, nil, nil, readingSpeed).Add(ctx, &creation)
       assert.NoError(t, err)
       assert.Equal(t, id, res)
 
       creation.Summary = strings.Repeat("x", 1+1024)
       r = mocks.NewProjectsRepository()
       r.On(routine, ctx, &creation).Return(id, nil)
-      res, err = NewProjectsService(r).Add(ctx, &creation)
+      res, err = NewProjectsService(r, nil, nil, readingSpeed).Add(ctx, &creation)
       assert.Error(t, err)
       assert.Empty(t, res)
     })
@@ -239,14 +239,14 @@ func TestProjectsService_Add(t *testing.T) {
       creation.Summary = strings.Repeat("word ", 60)
       var r = mocks.NewProjectsRepository()
       r.On(routine, ctx, &creation).Return(id, nil)
-      res, err := NewProjectsService(r).Add(ctx, &creation)
+      res, err := NewProjectsService(r, nil, nil, readingSpeed).Add(ctx, &creation)
       assert.NoError(t, err)
       assert.Equal(t, id, res)
 
       creation.Summary = strings.Repeat("word ", 1+60)
       r = mocks.NewProjectsRepository()
       r.On(routine, ctx, &creation).Return(id, nil)
-      res, err = NewProjectsService(r).Add(ctx, &creation)
+      res, err = NewProjectsService(r, nil, nil, readingSpeed).Add(ctx, &creation)
       assert.Error(t, err)
       assert.Empty(t, res)
     })
@@ -257,14 +257,14 @@ func TestProjectsService_Add(t *testing.T) {
       creation.Content = strings.Repeat("x", 3145728)
       var r = mocks.NewProjectsRepository()
       r.On(routine, ctx, &creation).Return(id, nil)
-      res, err := NewProjectsService(r).Add(ctx, &creation)
+      res, err := NewProjectsService(r, nil, nil, readingSpeed).Add(ctx, &creation)
       assert.NoError(t, err)
       assert.Equal(t, id, res)
 
       creation.Content = strings.Repeat("x", 1+3145728)
       r = mocks.NewProjectsRepository()
       r.On(routine, ctx, &creation).Return(id, nil)
-      res, err = NewProjectsService(r).Add(ctx, &creation)
+      res, err = NewProjectsService(r, nil, nil, readingSpeed).Add(ctx, &creation)
       assert.Error(t, err)
       assert.Empty(t, res)
     })
@@ -275,14 +275,14 @@ func TestProjectsService_Add(t *testing.T) {
       creation.FirstImageURL = "https://" + strings.Repeat("x", 2036) + ".com"
       var r = mocks.NewProjectsRepository()
       r.On(routine, ctx, &creation).Return(id, nil)
-      res, err := NewProjectsService(r).Add(ctx, &creation)
+      res, err := NewProjectsService(r, nil, nil, readingSpeed).Add(ctx, &creation)
       assert.NoError(t, err)
       assert.Equal(t, id, res)
 
       creation.FirstImageURL = "https://" + strings.Repeat("x", 1+2036) + ".com"
       r = mocks.NewProjectsRepository()
       r.On(routine, ctx, &creation).Return(id, nil)
-      res, err = NewProjectsService(r).Add(ctx, &creation)
+      res, err = NewProjectsService(r, nil, nil, readingSpeed).Add(ctx, &creation)
       assert.Error(t, err)
       assert.Empty(t, res)
     })
@@ -293,14 +293,14 @@ func TestProjectsService_Add(t *testing.T) {
       creation.SecondImageURL = "https://" + strings.Repeat("x", 2036) + ".com"
       var r = mocks.NewProjectsRepository()
       r.On(routine, ctx, &creation).Return(id, nil)
-      res, err := NewProjectsService(r).Add(ctx, &creation)
+      res, err := NewProjectsService(r, nil, nil, readingSpeed).Add(ctx, &creation)
       assert.NoError(t, err)
       assert.Equal(t, id, res)
 
       creation.SecondImageURL = "https://" + strings.Repeat("x", 1+2036) + ".com"
       r = mocks.NewProjectsRepository()
       r.On(routine, ctx, &creation).Return(id, nil)
-      res, err = NewProjectsService(r).Add(ctx, &creation)
+      res, err = NewProjectsService(r, nil, nil, readingSpeed).Add(ctx, &creation)
       assert.Error(t, err)
       assert.Empty(t, res)
     })
@@ -311,14 +311,14 @@ func TestProjectsService_Add(t *testing.T) {
       creation.GitHubURL = "https://" + strings.Repeat("x", 2036) + ".com"
       var r = mocks.NewProjectsRepository()
       r.On(routine, ctx, &creation).Return(id, nil)
-      res, err := NewProjectsService(r).Add(ctx, &creation)
+      res, err := NewProjectsService(r, nil, nil, readingSpeed).Add(ctx, &creation)
       assert.NoError(t, err)
       assert.Equal(t, id, res)
 
       creation.GitHubURL = "https://" + strings.Repeat("x", 1+2036) + ".com"
       r = mocks.NewProjectsRepository()
       r.On(routine, ctx, &creation).Return(id, nil)
-      res, err = NewProjectsService(r).Add(ctx, &creation)
+      res, err = NewProjectsService(r, nil, nil, readingSpeed).Add(ctx, &creation)
       assert.Error(t, err)
       assert.Empty(t, res)
     })
@@ -329,14 +329,14 @@ func TestProjectsService_Add(t *testing.T) {
       creation.CollectionURL = "https://" + strings.Repeat("x", 2036) + ".com"
       var r = mocks.NewProjectsRepository()
       r.On(routine, ctx, &creation).Return(id, nil)
-      res, err := NewProjectsService(r).Add(ctx, &creation)
+      res, err := NewProjectsService(r, nil, nil, readingSpeed).Add(ctx, &creation)
       assert.NoError(t, err)
       assert.Equal(t, id, res)
 
       creation.CollectionURL = "https://" + strings.Repeat("x", 1+2036) + ".com"
       r = mocks.NewProjectsRepository()
       r.On(routine, ctx, &creation).Return(id, nil)
-      res, err = NewProjectsService(r).Add(ctx, &creation)
+      res, err = NewProjectsService(r, nil, nil, readingSpeed).Add(ctx, &creation)
       assert.Error(t, err)
       assert.Empty(t, res)
     })
@@ -346,7 +346,7 @@ func TestProjectsService_Add(t *testing.T) {
     var expected = problem.NewInternal()
     var r = mocks.NewProjectsRepository()
     r.On(routine, ctx, mock.Anything).Return("", expected)
-    res, err := NewProjectsService(r).Add(ctx, new(transfer.ProjectCreation))
+    res, err := NewProjectsService(r, nil, nil, readingSpeed).Add(ctx, new(transfer.ProjectCreation))
     assert.ErrorAs(t, err, &expected)
     assert.Empty(t, res)
   })
@@ -355,7 +355,7 @@ func TestProjectsService_Add(t *testing.T) {
     var unexpected = errors.New("unexpected error")
     var r = mocks.NewProjectsRepository()
     r.On(routine, ctx, mock.Anything).Return("", unexpected)
-    res, err := NewProjectsService(r).Add(ctx, new(transfer.ProjectCreation))
+    res, err := NewProjectsService(r, nil, nil, readingSpeed).Add(ctx, new(transfer.ProjectCreation))
     assert.ErrorIs(t, err, unexpected)
     assert.Empty(t, res)
   })
@@ -369,7 +369,7 @@ func TestProjectService_Exists(t *testing.T) {
   t.Run("success", func(t *testing.T) {
     var r = mocks.NewProjectsRepository()
     r.On(routine, ctx, id).Return(nil)
-    err := NewProjectsService(r).Exists(ctx, id)
+    err := NewProjectsService(r, nil, nil, readingSpeed).Exists(ctx, id)
     assert.NoError(t, err)
   })
 
@@ -377,7 +377,7 @@ func TestProjectService_Exists(t *testing.T) {
     var unexpected = errors.New("unexpected error")
     var r = mocks.NewProjectsRepository()
     r.On(routine, ctx, id).Return(unexpected)
-    err := NewProjectsService(r).Exists(ctx, id)
+    err := NewProjectsService(r, nil, nil, readingSpeed).Exists(ctx, id)
     assert.ErrorIs(t, err, unexpected)
   })
 }
@@ -422,7 +422,7 @@ func TestProjectsService_Update(t *testing.T) {
     }
     var r = mocks.NewProjectsRepository()
     r.On(routine, ctx, id, &update).Return(true, nil)
-    res, err := NewProjectsService(r).Update(ctx, id, &dirty)
+    res, err := NewProjectsService(r, nil, nil, readingSpeed).Update(ctx, id, &dirty)
     assert.NoError(t, err)
     assert.True(t, res)
   })
@@ -437,7 +437,7 @@ func TestProjectsService_Update(t *testing.T) {
     }
     var r = mocks.NewProjectsRepository()
     r.On(routine, ctx, id, &update).Return(true, nil)
-    res, err := NewProjectsService(r).Update(ctx, id, &dirty)
+    res, err := NewProjectsService(r, nil, nil, readingSpeed).Update(ctx, id, &dirty)
     assert.NoError(t, err)
     assert.True(t, res)
   })
@@ -445,7 +445,7 @@ func TestProjectsService_Update(t *testing.T) {
   t.Run("no nil parameter", func(t *testing.T) {
     var r = mocks.NewProjectsRepository()
     r.AssertNotCalled(t, routine)
-    res, err := NewProjectsService(r).Update(ctx, id, nil)
+    res, err := NewProjectsService(r, nil, nil, readingSpeed).Update(ctx, id, nil)
     assert.ErrorContains(t, err, "nil value for parameter: update")
     assert.Empty(t, res)
   })
@@ -457,14 +457,14 @@ func TestProjectsService_Update(t *testing.T) {
       update.Name = strings.Repeat("x", 36)
       var r = mocks.NewProjectsRepository()
       r.On(routine, ctx, id, &update).Return(true, nil)
-      res, err := NewProjectsService(r).Update(ctx, id, &update)
+      res, err := NewProjectsService(r, nil, nil, readingSpeed).Update(ctx, id, &update)
       assert.NoError(t, err)
       assert.True(t, res)
 
       update.Name = strings.Repeat("x", 1+36)
       r = mocks.NewProjectsRepository()
       r.On(routine, ctx, id, &update).Return(false, nil)
-      res, err = NewProjectsService(r).Update(ctx, id, &update)
+      res, err = NewProjectsService(r, nil, nil, readingSpeed).Update(ctx, id, &update)
       assert.Error(t, err)
       assert.False(t, res)
     })
@@ -475,14 +475,14 @@ func TestProjectsService_Update(t *testing.T) {
       update.Homepage = "https://" + strings.Repeat("x", 2036) + ".com"
       var r = mocks.NewProjectsRepository()
       r.On(routine, ctx, id, &update).Return(true, nil)
-      res, err := NewProjectsService(r).Update(ctx, id, &update)
+      res, err := NewProjectsService(r, nil, nil, readingSpeed).Update(ctx, id, &update)
       assert.NoError(t, err)
       assert.True(t, res)
 
       update.Homepage = "https://" + strings.Repeat("x", 1+2036) + ".com"
       r = mocks.NewProjectsRepository()
       r.On(routine, ctx, id, &update).Return(false, nil)
-      res, err = NewProjectsService(r).Update(ctx, id, &update)
+      res, err = NewProjectsService(r, nil, nil, readingSpeed).Update(ctx, id, &update)
       assert.Error(t, err)
       assert.False(t, res)
     })
@@ -493,14 +493,14 @@ func TestProjectsService_Update(t *testing.T) {
       update.Language = strings.Repeat("x", 64)
       var r = mocks.NewProjectsRepository()
       r.On(routine, ctx, id, &update).Return(true, nil)
-      res, err := NewProjectsService(r).Update(ctx, id, &update)
+      res, err := NewProjectsService(r, nil, nil, readingSpeed).Update(ctx, id, &update)
       assert.NoError(t, err)
       assert.True(t, res)
 
       update.Language = strings.Repeat("x", 1+64)
       r = mocks.NewProjectsRepository()
       r.On(routine, ctx, id, &update).Return(false, nil)
-      res, err = NewProjectsService(r).Update(ctx, id, &update)
+      res, err = NewProjectsService(r, nil, nil, readingSpeed).Update(ctx, id, &update)
       assert.Error(t, err)
       assert.False(t, res)
     })
@@ -511,14 +511,14 @@ func TestProjectsService_Update(t *testing.T) {
       update.Summary = strings.Repeat("x", 1024)
       var r = mocks.NewProjectsRepository()
       r.On(routine, ctx, id, &update).Return(true, nil)
-      res, err := NewProjectsService(r).Update(ctx, id, &update)
+      res, err := NewProjectsService(r, nil, nil, readingSpeed).Update(ctx, id, &update)
       assert.NoError(t, err)
       assert.True(t, res)
 
       update.Summary = strings.Repeat("x", 1+1024)
       r = mocks.NewProjectsRepository()
       r.On(routine, ctx, id, &update).Return(false, nil)
-      res, err = NewProjectsService(r).Update(ctx, id, &update)
+      res, err = NewProjectsService(r, nil, nil, readingSpeed).Update(ctx, id, &update)
       assert.Error(t, err)
       assert.False(t, res)
     })
@@ -529,14 +529,14 @@ func TestProjectsService_Update(t *testing.T) {
       update.Summary = strings.Repeat("word ", 60)
       var r = mocks.NewProjectsRepository()
       r.On(routine, ctx, id, &update).Return(true, nil)
-      res, err := NewProjectsService(r).Update(ctx, id, &update)
+      res, err := NewProjectsService(r, nil, nil, readingSpeed).Update(ctx, id, &update)
       assert.NoError(t, err)
       assert.True(t, res)
 
       update.Summary = strings.Repeat("word ", 1+60)
       r = mocks.NewProjectsRepository()
       r.On(routine, ctx, id, &update).Return(false, nil)
-      res, err = NewProjectsService(r).Update(ctx, id, &update)
+      res, err = NewProjectsService(r, nil, nil, readingSpeed).Update(ctx, id, &update)
       assert.Error(t, err)
       assert.False(t, res)
     })
@@ -547,14 +547,14 @@ func TestProjectsService_Update(t *testing.T) {
       update.Content = strings.Repeat("x", 3145728)
       var r = mocks.NewProjectsRepository()
       r.On(routine, ctx, id, &update).Return(true, nil)
-      res, err := NewProjectsService(r).Update(ctx, id, &update)
+      res, err := NewProjectsService(r, nil, nil, readingSpeed).Update(ctx, id, &update)
       assert.NoError(t, err)
       assert.True(t, res)
 
       update.Content = strings.Repeat("x", 1+3145728)
       r = mocks.NewProjectsRepository()
       r.On(routine, ctx, id, &update).Return(false, nil)
-      res, err = NewProjectsService(r).Update(ctx, id, &update)
+      res, err = NewProjectsService(r, nil, nil, readingSpeed).Update(ctx, id, &update)
       assert.Error(t, err)
       assert.False(t, res)
     })
@@ -565,14 +565,14 @@ func TestProjectsService_Update(t *testing.T) {
       update.FirstImageURL = "https://" + strings.Repeat("x", 2036) + ".com"
       var r = mocks.NewProjectsRepository()
       r.On(routine, ctx, id, &update).Return(true, nil)
-      res, err := NewProjectsService(r).Update(ctx, id, &update)
+      res, err := NewProjectsService(r, nil, nil, readingSpeed).Update(ctx, id, &update)
       assert.NoError(t, err)
       assert.True(t, res)
 
       update.FirstImageURL = "https://" + strings.Repeat("x", 1+2036) + ".com"
       r = mocks.NewProjectsRepository()
       r.On(routine, ctx, id, &update).Return(false, nil)
-      res, err = NewProjectsService(r).Update(ctx, id, &update)
+      res, err = NewProjectsService(r, nil, nil, readingSpeed).Update(ctx, id, &update)
       assert.Error(t, err)
       assert.False(t, res)
     })
@@ -583,14 +583,14 @@ func TestProjectsService_Update(t *testing.T) {
       update.SecondImageURL = "https://" + strings.Repeat("x", 2036) + ".com"
       var r = mocks.NewProjectsRepository()
       r.On(routine, ctx, id, &update).Return(true, nil)
-      res, err := NewProjectsService(r).Update(ctx, id, &update)
+      res, err := NewProjectsService(r, nil, nil, readingSpeed).Update(ctx, id, &update)
       assert.NoError(t, err)
       assert.True(t, res)
 
       update.SecondImageURL = "https://" + strings.Repeat("x", 1+2036) + ".com"
       r = mocks.NewProjectsRepository()
       r.On(routine, ctx, id, &update).Return(false, nil)
-      res, err = NewProjectsService(r).Update(ctx, id, &update)
+      res, err = NewProjectsService(r, nil, nil, readingSpeed).Update(ctx, id, &update)
       assert.Error(t, err)
       assert.False(t, res)
     })
@@ -601,14 +601,14 @@ func TestProjectsService_Update(t *testing.T) {
       update.GitHubURL = "https://" + strings.Repeat("x", 2036) + ".com"
       var r = mocks.NewProjectsRepository()
       r.On(routine, ctx, id, &update).Return(true, nil)
-      res, err := NewProjectsService(r).Update(ctx, id, &update)
+      res, err := NewProjectsService(r, nil, nil, readingSpeed).Update(ctx, id, &update)
       assert.NoError(t, err)
       assert.True(t, res)
 
       update.GitHubURL = "https://" + strings.Repeat("x", 1+2036) + ".com"
       r = mocks.NewProjectsRepository()
       r.On(routine, ctx, id, &update).Return(false, nil)
-      res, err = NewProjectsService(r).Update(ctx, id, &update)
+      res, err = NewProjectsService(r, nil, nil, readingSpeed).Update(ctx, id, &update)
       assert.Error(t, err)
       assert.False(t, res)
     })
@@ -619,14 +619,14 @@ func TestProjectsService_Update(t *testing.T) {
       update.CollectionURL = "https://" + strings.Repeat("x", 2036) + ".com"
       var r = mocks.NewProjectsRepository()
       r.On(routine, ctx, id, &update).Return(true, nil)
-      res, err := NewProjectsService(r).Update(ctx, id, &update)
+      res, err := NewProjectsService(r, nil, nil, readingSpeed).Update(ctx, id, &update)
       assert.NoError(t, err)
       assert.True(t, res)
 
       update.CollectionURL = "https://" + strings.Repeat("x", 1+2036) + ".com"
       r = mocks.NewProjectsRepository()
       r.On(routine, ctx, id, &update).Return(false, nil)
-      res, err = NewProjectsService(r).Update(ctx, id, &update)
+      res, err = NewProjectsService(r, nil, nil, readingSpeed).Update(ctx, id, &update)
       assert.Error(t, err)
       assert.False(t, res)
     })
@@ -637,14 +637,14 @@ func TestProjectsService_Update(t *testing.T) {
       update.PlaygroundURL = "https://" + strings.Repeat("x", 2036) + ".com"
       var r = mocks.NewProjectsRepository()
       r.On(routine, ctx, id, &update).Return(true, nil)
-      res, err := NewProjectsService(r).Update(ctx, id, &update)
+      res, err := NewProjectsService(r, nil, nil, readingSpeed).Update(ctx, id, &update)
       assert.NoError(t, err)
       assert.True(t, res)
 
       update.PlaygroundURL = "https://" + strings.Repeat("x", 1+2036) + ".com"
       r = mocks.NewProjectsRepository()
       r.On(routine, ctx, id, &update).Return(false, nil)
-      res, err = NewProjectsService(r).Update(ctx, id, &update)
+      res, err = NewProjectsService(r, nil, nil, readingSpeed).Update(ctx, id, &update)
       assert.Error(t, err)
       assert.False(t, res)
     })
@@ -654,7 +654,7 @@ func TestProjectsService_Update(t *testing.T) {
     var expected = problem.NewInternal()
     var r = mocks.NewProjectsRepository()
     r.On(routine, ctx, mock.Anything, mock.Anything).Return(false, expected)
-    res, err := NewProjectsService(r).Update(ctx, id, new(transfer.ProjectUpdate))
+    res, err := NewProjectsService(r, nil, nil, readingSpeed).Update(ctx, id, new(transfer.ProjectUpdate))
     assert.ErrorAs(t, err, &expected)
     assert.Empty(t, res)
   })
@@ -663,7 +663,7 @@ func TestProjectsService_Update(t *testing.T) {
     var unexpected = errors.New("unexpected error")
     var r = mocks.NewProjectsRepository()
     r.On(routine, ctx, mock.Anything, mock.Anything).Return(false, unexpected)
-    res, err := NewProjectsService(r).Update(ctx, id, new(transfer.ProjectUpdate))
+    res, err := NewProjectsService(r, nil, nil, readingSpeed).Update(ctx, id, new(transfer.ProjectUpdate))
     assert.ErrorIs(t, err, unexpected)
     assert.Empty(t, res)
   })
@@ -677,7 +677,7 @@ func TestProjectService_Remove(t *testing.T) {
   t.Run("success", func(t *testing.T) {
     var r = mocks.NewProjectsRepository()
     r.On(routine, ctx, id).Return(nil)
-    err := NewProjectsService(r).Remove(ctx, id)
+    err := NewProjectsService(r, nil, nil, readingSpeed).Remove(ctx, id)
     assert.NoError(t, err)
   })
 
@@ -685,7 +685,7 @@ func TestProjectService_Remove(t *testing.T) {
     var unexpected = errors.New("unexpected error")
     var r = mocks.NewProjectsRepository()
     r.On(routine, ctx, id).Return(unexpected)
-    err := NewProjectsService(r).Remove(ctx, id)
+    err := NewProjectsService(r, nil, nil, readingSpeed).Remove(ctx, id)
     assert.ErrorIs(t, err, unexpected)
   })
 }
@@ -0,0 +1,330 @@
+package service
+
+import (
+  "archive/tar"
+  "bytes"
+  "compress/gzip"
+  "context"
+  "encoding/json"
+  "errors"
+  "fmt"
+  "fontseca.dev/problem"
+  "fontseca.dev/repository"
+  "fontseca.dev/tracing"
+  "fontseca.dev/transfer"
+  "gopkg.in/yaml.v3"
+  "io"
+  "net/http"
+  "path"
+  "strings"
+  "time"
+)
+
+// SiteService exports the whole content of the site as a gzipped tar
+// archive and restores it from one. The archive is laid out as:
+//
+//  me.json
+//  experience.json
+//  projects.json
+//  technologies.json
+//  topics.json
+//  tags.json
+//  articles/<slug>.md
+//  drafts/<slug>.md
+//  patches/<uuid>.md
+//
+// Articles, drafts and patches are Markdown files whose YAML front
+// matter holds every field but the content, so published articles can
+// also be imported with ImportService.
+type SiteService interface {
+  // Export writes the archive of the site to w.
+  Export(ctx context.Context, w io.Writer) error
+
+  // Restore replaces the content of the site with the content of the
+  // archive read from r.
+  Restore(ctx context.Context, r io.Reader) error
+}
+
+type siteService struct {
+  r repository.SiteRepository
+}
+
+func NewSiteService(r repository.SiteRepository) SiteService {
+  return &siteService{r}
+}
+
+// maxSiteArchiveEntrySize is the maximum size of a file in an archive.
+const maxSiteArchiveEntrySize = 1 << 26
+
+// newInvalidSiteArchive creates a problem for an archive that could not be restored.
+func newInvalidSiteArchive(detail string) *problem.Problem {
+  p := problem.Problem{}
+  p.Status(http.StatusUnprocessableEntity)
+  p.Title("Invalid site archive.")
+  p.Detail(detail)
+  return &p
+}
+
+// toFrontMatter encodes record as a Markdown file with a YAML front
+// matter. Timestamps are written as YAML timestamps.
+func toFrontMatter(record transfer.Record) ([]byte, error) {
+  var matter = make(map[string]any, len(record))
+  var content string
+
+  for key, value := range record {
+    s, isString := value.(string)
+
+    switch {
+    case "content" == key && isString:
+      content = s
+      continue
+    case strings.HasSuffix(key, "_at") && isString:
+      if t, err := time.Parse(time.DateTime, s); nil == err {
+        value = t
+      }
+    }
+
+    matter[key] = value
+  }
+
+  data, err := yaml.Marshal(matter)
+  if nil != err {
+    return nil, err
+  }
+
+  var buf bytes.Buffer
+  buf.WriteString("---\n")
+  buf.Write(data)
+  buf.WriteString("---\n")
+  buf.WriteString(content)
+
+  return buf.Bytes(), nil
+}
+
+// fromFrontMatter decodes a Markdown file written by toFrontMatter.
+// Unlike splitFrontMatter, it keeps the content byte by byte.
+func fromFrontMatter(name string, data []byte) (record transfer.Record, err error) {
+  rest, ok := bytes.CutPrefix(data, []byte("---\n"))
+  if !ok {
+    return nil, newInvalidSiteArchive(fmt.Sprintf("The file '%s' does not start with a YAML front matter.", name))
+  }
+
+  matter, content, ok := bytes.Cut(rest, []byte("\n---\n"))
+  if !ok {
+    return nil, newInvalidSiteArchive(fmt.Sprintf("The front matter of the file '%s' is not closed.", name))
+  }
+
+  if err = yaml.Unmarshal(matter, &record); nil != err {
+    return nil, newInvalidSiteArchive(fmt.Sprintf("The front matter of the file '%s' is not valid YAML: %v.", name, err))
+  }
+
+  if nil == record {
+    record = make(transfer.Record)
+  }
+
+  for key, value := range record {
+    if t, isTime := value.(time.Time); isTime {
+      record[key] = t.UTC().Format(time.DateTime)
+    }
+  }
+
+  if _, ok = record["content"]; !ok {
+    record["content"] = string(content)
+  }
+
+  return record, nil
+}
+
+func (s *siteService) Export(ctx context.Context, w io.Writer) error {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
+  dump, err := s.r.Dump(ctx)
+  if nil != err {
+    return err
+  }
+
+  var compressed = gzip.NewWriter(w)
+  var archive = tar.NewWriter(compressed)
+  var now = time.Now()
+
+  write := func(name string, data []byte) error {
+    header := &tar.Header{
+      Typeflag: tar.TypeReg,
+      Name:     name,
+      Mode:     0o644,
+      Size:     int64(len(data)),
+      ModTime:  now,
+    }
+
+    if err := archive.WriteHeader(header); nil != err {
+      return err
+    }
+
+    _, err := archive.Write(data)
+    return err
+  }
+
+  var tables = []struct {
+    name    string
+    content any
+  }{
+    {"me.json", dump.Me},
+    {"experience.json", dump.Experience},
+    {"projects.json", dump.Projects},
+    {"technologies.json", dump.Technologies},
+    {"topics.json", dump.Topics},
+    {"tags.json", dump.Tags},
+  }
+
+  for _, table := range tables {
+    data, err := json.MarshalIndent(table.content, "", "  ")
+    if nil != err {
+      return err
+    }
+
+    if err = write(table.name, append(data, '\n')); nil != err {
+      return err
+    }
+  }
+
+  var taken = make(map[string]bool)
+
+  for _, article := range dump.Articles {
+    var dir = "articles"
+
+    if draft, _ := article["draft"].(bool); draft {
+      dir = "drafts"
+    }
+
+    var name = path.Join(dir, fmt.Sprintf("%v.md", article["slug"]))
+
+    if taken[name] {
+      name = path.Join(dir, fmt.Sprintf("%v-%v.md", article["slug"], article["uuid"]))
+    }
+
+    taken[name] = true
+
+    data, err := toFrontMatter(article)
+    if nil != err {
+      return err
+    }
+
+    if err = write(name, data); nil != err {
+      return err
+    }
+  }
+
+  for _, patch := range dump.Patches {
+    data, err := toFrontMatter(patch)
+    if nil != err {
+      return err
+    }
+
+    if err = write(path.Join("patches", fmt.Sprintf("%v.md", patch["uuid"])), data); nil != err {
+      return err
+    }
+  }
+
+  if err = archive.Close(); nil != err {
+    return err
+  }
+
+  return compressed.Close()
+}
+
+func (s *siteService) Restore(ctx context.Context, r io.Reader) error {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
+  compressed, err := gzip.NewReader(r)
+  if nil != err {
+    return newInvalidSiteArchive("The archive is not compressed with gzip.")
+  }
+
+  defer compressed.Close()
+
+  var archive = tar.NewReader(compressed)
+  var dump = &transfer.SiteDump{}
+  var found bool
+
+  for {
+    header, err := archive.Next()
+    if errors.Is(err, io.EOF) {
+      break
+    }
+
+    if nil != err {
+      return newInvalidSiteArchive(fmt.Sprintf("The archive could not be read: %v.", err))
+    }
+
+    if tar.TypeReg != header.Typeflag {
+      continue
+    }
+
+    if maxSiteArchiveEntrySize < header.Size {
+      return newInvalidSiteArchive(fmt.Sprintf("The file '%s' is too large.", header.Name))
+    }
+
+    data, err := io.ReadAll(archive)
+    if nil != err {
+      return newInvalidSiteArchive(fmt.Sprintf("The file '%s' could not be read: %v.", header.Name, err))
+    }
+
+    var name = path.Clean(header.Name)
+    var table any
+
+    switch name {
+    case "me.json":
+      table, found = &dump.Me, true
+    case "experience.json":
+      table = &dump.Experience
+    case "projects.json":
+      table = &dump.Projects
+    case "technologies.json":
+      table = &dump.Technologies
+    case "topics.json":
+      table = &dump.Topics
+    case "tags.json":
+      table = &dump.Tags
+    }
+
+    if nil != table {
+      decoder := json.NewDecoder(bytes.NewReader(data))
+      decoder.UseNumber()
+
+      if err = decoder.Decode(table); nil != err {
+        return newInvalidSiteArchive(fmt.Sprintf("The file '%s' is not valid JSON: %v.", name, err))
+      }
+
+      continue
+    }
+
+    if ".md" != path.Ext(name) {
+      continue
+    }
+
+    switch path.Dir(name) {
+    case "articles", "drafts":
+      record, err := fromFrontMatter(name, data)
+      if nil != err {
+        return err
+      }
+
+      dump.Articles = append(dump.Articles, record)
+    case "patches":
+      record, err := fromFrontMatter(name, data)
+      if nil != err {
+        return err
+      }
+
+      dump.Patches = append(dump.Patches, record)
+    }
+  }
+
+  if !found {
+    return newInvalidSiteArchive("The archive does not contain a 'me.json' file; it is not a site archive.")
+  }
+
+  return s.r.Restore(ctx, dump)
+}
@@ -0,0 +1,197 @@
+package service
+
+import (
+  "archive/tar"
+  "bytes"
+  "compress/gzip"
+  "context"
+  "errors"
+  "fontseca.dev/mocks"
+  "fontseca.dev/problem"
+  "fontseca.dev/transfer"
+  "github.com/stretchr/testify/assert"
+  "github.com/stretchr/testify/mock"
+  "github.com/stretchr/testify/require"
+  "io"
+  "testing"
+)
+
+func TestSiteService_Export(t *testing.T) {
+  const routine = "Dump"
+
+  ctx := context.TODO()
+
+  dump := &transfer.SiteDump{
+    Me:           transfer.Record{"username": "fontseca.dev", "name": "Fontseca"},
+    Experience:   []transfer.Record{{"uuid": "e1", "company": "ACME"}},
+    Projects:     []transfer.Record{{"uuid": "p1", "name": "Site", "technology_tags": []any{"t1"}}},
+    Technologies: []transfer.Record{{"uuid": "t1", "name": "Go"}},
+    Topics:       []transfer.Record{{"id": "go", "name": "Go"}},
+    Tags:         []transfer.Record{{"id": "intro", "name": "Intro"}},
+    Articles: []transfer.Record{
+      {"uuid": "a1", "slug": "hello", "title": "Hello", "draft": false, "views": 7, "published_at": "2020-05-01 10:00:00", "tags": []any{"intro"}, "content": "Hello,\r\nworld.\n"},
+      {"uuid": "a2", "slug": "hello", "title": "Hello again", "draft": true, "published_at": nil, "tags": []any{}, "content": ""},
+    },
+    Patches: []transfer.Record{{"uuid": "x1", "article_uuid": "a1", "title": "Hi", "content": nil}},
+  }
+
+  t.Run("success", func(t *testing.T) {
+    r := mocks.NewSiteRepository()
+    r.On(routine, ctx).Return(dump, nil)
+
+    var buf bytes.Buffer
+    require.NoError(t, NewSiteService(r).Export(ctx, &buf))
+
+    compressed, err := gzip.NewReader(&buf)
+    require.NoError(t, err)
+
+    names := make([]string, 0)
+    archive := tar.NewReader(compressed)
+
+    for {
+      header, err := archive.Next()
+      if errors.Is(err, io.EOF) {
+        break
+      }
+      require.NoError(t, err)
+      names = append(names, header.Name)
+    }
+
+    assert.Equal(t, []string{
+      "me.json",
+      "experience.json",
+      "projects.json",
+      "technologies.json",
+      "topics.json",
+      "tags.json",
+      "articles/hello.md",
+      "drafts/hello.md",
+      "patches/x1.md",
+    }, names)
+  })
+
+  t.Run("restores what it exports", func(t *testing.T) {
+    r := mocks.NewSiteRepository()
+    r.On(routine, ctx).Return(dump, nil)
+
+    var buf bytes.Buffer
+    require.NoError(t, NewSiteService(r).Export(ctx, &buf))
+
+    var restored *transfer.SiteDump
+
+    r.On("Restore", ctx, mock.MatchedBy(func(d *transfer.SiteDump) bool {
+      restored = d
+      return true
+    })).Return(nil)
+
+    require.NoError(t, NewSiteService(r).Restore(ctx, &buf))
+    require.NotNil(t, restored)
+
+    assert.Equal(t, dump.Me, restored.Me)
+    assert.Equal(t, dump.Topics, restored.Topics)
+    assert.Equal(t, []any{"t1"}, restored.Projects[0]["technology_tags"])
+    assert.Equal(t, dump.Articles, restored.Articles)
+    assert.Equal(t, dump.Patches, restored.Patches)
+  })
+
+  t.Run("error", func(t *testing.T) {
+    unexpected := errors.New("unexpected error")
+
+    r := mocks.NewSiteRepository()
+    r.On(routine, ctx).Return(nil, unexpected)
+
+    var buf bytes.Buffer
+    assert.ErrorIs(t, NewSiteService(r).Export(ctx, &buf), unexpected)
+    assert.Zero(t, buf.Len())
+  })
+}
+
+func TestSiteService_Restore(t *testing.T) {
+  const routine = "Restore"
+
+  ctx := context.TODO()
+
+  newArchive := func(t *testing.T, files map[string]string) io.Reader {
+    var buf bytes.Buffer
+
+    compressed := gzip.NewWriter(&buf)
+    archive := tar.NewWriter(compressed)
+
+    for name, content := range files {
+      require.NoError(t, archive.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: name, Mode: 0o644, Size: int64(len(content))}))
+      _, err := archive.Write([]byte(content))
+      require.NoError(t, err)
+    }
+
+    require.NoError(t, archive.Close())
+    require.NoError(t, compressed.Close())
+
+    return &buf
+  }
+
+  t.Run("success", func(t *testing.T) {
+    file := newArchive(t, map[string]string{
+      "me.json":           `{"username": "fontseca.dev", "coding_since": 2017}`,
+      "articles/hello.md": "---\ntitle: Hello\npublished_at: 2020-05-01T10:00:00Z\n---\nContent.",
+      "patches/x1.md":     "---\nuuid: x1\ncontent: null\n---\n",
+      "notes/ignored.md":  "Not part of the site.",
+    })
+
+    r := mocks.NewSiteRepository()
+    r.On(routine, ctx, mock.MatchedBy(func(d *transfer.SiteDump) bool {
+      return "fontseca.dev" == d.Me["username"] &&
+        1 == len(d.Articles) &&
+        "2020-05-01 10:00:00" == d.Articles[0]["published_at"] &&
+        "Content." == d.Articles[0]["content"] &&
+        1 == len(d.Patches) &&
+        nil == d.Patches[0]["content"]
+    })).Return(nil)
+
+    assert.NoError(t, NewSiteService(r).Restore(ctx, file))
+    r.AssertNumberOfCalls(t, routine, 1)
+  })
+
+  t.Run("invalid front matter", func(t *testing.T) {
+    r := mocks.NewSiteRepository()
+    r.AssertNotCalled(t, routine)
+
+    file := newArchive(t, map[string]string{
+      "me.json":           "{}",
+      "articles/hello.md": "# Hello\nNo front matter.",
+    })
+
+    var p *problem.Problem
+    assert.ErrorAs(t, NewSiteService(r).Restore(ctx, file), &p)
+  })
+
+  t.Run("not an archive", func(t *testing.T) {
+    r := mocks.NewSiteRepository()
+    r.AssertNotCalled(t, routine)
+
+    err := NewSiteService(r).Restore(ctx, bytes.NewReader([]byte("plain text")))
+
+    var p *problem.Problem
+    assert.ErrorAs(t, err, &p)
+  })
+
+  t.Run("missing me.json", func(t *testing.T) {
+    r := mocks.NewSiteRepository()
+    r.AssertNotCalled(t, routine)
+
+    err := NewSiteService(r).Restore(ctx, newArchive(t, map[string]string{"topics.json": "[]"}))
+
+    var p *problem.Problem
+    require.ErrorAs(t, err, &p)
+    assert.Contains(t, p.Error(), "me.json")
+  })
+
+  t.Run("error", func(t *testing.T) {
+    unexpected := errors.New("unexpected error")
+
+    r := mocks.NewSiteRepository()
+    r.On(routine, ctx, mock.Anything).Return(unexpected)
+
+    err := NewSiteService(r).Restore(ctx, newArchive(t, map[string]string{"me.json": "{}"}))
+    assert.ErrorIs(t, err, unexpected)
+  })
+}
@@ -4,7 +4,9 @@ import (
   "context"
   "errors"
   "fontseca.dev/model"
+  "fontseca.dev/problem"
   "fontseca.dev/repository"
+  "fontseca.dev/tracing"
   "fontseca.dev/transfer"
   "log/slog"
   "strings"
@@ -18,30 +20,46 @@ type TagsService interface {
   // Get retrieves all the tags.
   Get(ctx context.Context) (tags []*model.Tag, err error)
 
+  // List retrieves the page of tags that follows the cursor of page.
+  List(ctx context.Context, page *transfer.PageRequest) (tags *transfer.Page[*model.Tag], err error)
+
+  // GetByID retrieves the tag with the given ID.
+  GetByID(ctx context.Context, id string) (tag *model.Tag, err error)
+
   // Update updates an existing tag.
   Update(ctx context.Context, id string, update *transfer.TagUpdate) error
 
-  // Remove removes a tag and detaches it from any
-  // article that currently uses it.
+  // Remove moves a tag to the trash.
   Remove(ctx context.Context, id string) error
+
+  // Restore brings back a tag from the trash.
+  Restore(ctx context.Context, id string) error
 }
 
 type tagsService struct {
   cache []*model.Tag
   r     repository.TagsRepository
+  unit  repository.UnitOfWork
 }
 
-func NewTagsService(r repository.TagsRepository) TagsService {
+// NewTagsService returns a TagsService whose changes run in unit, so
+// that they take part in the unit of work of the context they are
+// given, if any.
+func NewTagsService(r repository.TagsRepository, unit repository.UnitOfWork) TagsService {
   return &tagsService{
     cache: nil,
     r:     r,
+    unit:  unit,
   }
 }
 
 func (s *tagsService) Add(ctx context.Context, creation *transfer.TagCreation) error {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
   if nil == creation {
     err := errors.New("nil value for parameter: creation")
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return err
   }
 
@@ -49,7 +67,9 @@ func (s *tagsService) Add(ctx context.Context, creation *transfer.TagCreation) e
   sanitizeTextWordIntersections(&creation.Name)
   creation.ID = toKebabCase(creation.Name)
 
-  err := s.r.Add(ctx, creation)
+  err := within(ctx, s.unit, func(ctx context.Context) error {
+    return s.r.Add(ctx, creation)
+  })
 
   if nil != err {
     return err
@@ -61,6 +81,9 @@ func (s *tagsService) Add(ctx context.Context, creation *transfer.TagCreation) e
 }
 
 func (s *tagsService) Get(ctx context.Context) (tags []*model.Tag, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
   if s.hasCache() {
     return s.cache, nil
   }
@@ -76,10 +99,39 @@ func (s *tagsService) Get(ctx context.Context) (tags []*model.Tag, err error) {
   return tags, err
 }
 
+func (s *tagsService) List(ctx context.Context, page *transfer.PageRequest) (tags *transfer.Page[*model.Tag], err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
+  return s.r.List(ctx, page)
+}
+
+func (s *tagsService) GetByID(ctx context.Context, id string) (tag *model.Tag, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
+  tags, err := s.Get(ctx)
+
+  if nil != err {
+    return nil, err
+  }
+
+  for _, tag := range tags {
+    if id == tag.ID {
+      return tag, nil
+    }
+  }
+
+  return nil, problem.NewNotFound(id, "tag")
+}
+
 func (s *tagsService) Update(ctx context.Context, id string, update *transfer.TagUpdate) error {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
   if nil == update {
     err := errors.New("nil value for parameter: creation")
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return err
   }
 
@@ -87,7 +139,9 @@ func (s *tagsService) Update(ctx context.Context, id string, update *transfer.Ta
   sanitizeTextWordIntersections(&update.Name)
   update.ID = toKebabCase(update.Name)
 
-  err := s.r.Update(ctx, id, update)
+  err := within(ctx, s.unit, func(ctx context.Context) error {
+    return s.r.Update(ctx, id, update)
+  })
 
   if nil != err {
     return err
@@ -99,7 +153,29 @@ func (s *tagsService) Update(ctx context.Context, id string, update *transfer.Ta
 }
 
 func (s *tagsService) Remove(ctx context.Context, id string) error {
-  err := s.r.Remove(ctx, id)
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
+  err := within(ctx, s.unit, func(ctx context.Context) error {
+    return s.r.Remove(ctx, id)
+  })
+
+  if nil != err {
+    return err
+  }
+
+  s.setCache(ctx)
+
+  return nil
+}
+
+func (s *tagsService) Restore(ctx context.Context, id string) error {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
+  err := within(ctx, s.unit, func(ctx context.Context) error {
+    return s.r.Restore(ctx, id)
+  })
 
   if nil != err {
     return err
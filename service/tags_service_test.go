@@ -30,7 +30,7 @@ func TestTagsService_Add(t *testing.T) {
     r.On(routine, ctx, creation).Return(nil)
     r.On("Get", ctx).Return([]*model.Tag{{}, {}}, nil)
 
-    err := NewTagsService(r).Add(ctx, dirty)
+    err := NewTagsService(r, nil).Add(ctx, dirty)
 
     assert.NoError(t, err)
   })
@@ -40,7 +40,7 @@ func TestTagsService_Add(t *testing.T) {
     r := mocks.NewTagsRepository()
     r.On(routine, ctx, mock.Anything).Return(unexpected)
 
-    err := NewTagsService(r).Add(ctx, creation)
+    err := NewTagsService(r, nil).Add(ctx, creation)
     assert.ErrorIs(t, err, unexpected)
   })
 }
@@ -56,7 +56,7 @@ func TestTagsService_Get(t *testing.T) {
     r := mocks.NewTagsRepository()
     r.On(routine, ctx).Return(expectedTags, nil)
 
-    s := NewTagsService(r).(*tagsService)
+    s := NewTagsService(r, nil).(*tagsService)
 
     s.cache = nil
 
@@ -72,7 +72,7 @@ func TestTagsService_Get(t *testing.T) {
     r := mocks.NewTagsRepository()
     r.AssertNotCalled(t, routine)
 
-    s := NewTagsService(r).(*tagsService)
+    s := NewTagsService(r, nil).(*tagsService)
 
     s.cache = expectedTags
 
@@ -88,13 +88,39 @@ func TestTagsService_Get(t *testing.T) {
     r := mocks.NewTagsRepository()
     r.On(routine, ctx).Return(nil, unexpected)
 
-    tags, err := NewTagsService(r).Get(ctx)
+    tags, err := NewTagsService(r, nil).Get(ctx)
 
     assert.Nil(t, tags)
     assert.ErrorIs(t, err, unexpected)
   })
 }
 
+func TestTagsService_GetByID(t *testing.T) {
+  ctx := context.TODO()
+
+  t.Run("success", func(t *testing.T) {
+    expected := &model.Tag{ID: "go", Name: "Go"}
+
+    r := mocks.NewTagsRepository()
+    r.On("Get", ctx).Return([]*model.Tag{{ID: "sql"}, expected}, nil)
+
+    tag, err := NewTagsService(r, nil).GetByID(ctx, "go")
+
+    assert.Equal(t, expected, tag)
+    assert.NoError(t, err)
+  })
+
+  t.Run("not found", func(t *testing.T) {
+    r := mocks.NewTagsRepository()
+    r.On("Get", ctx).Return([]*model.Tag{{ID: "sql"}}, nil)
+
+    tag, err := NewTagsService(r, nil).GetByID(ctx, "go")
+
+    assert.Nil(t, tag)
+    assert.ErrorContains(t, err, "could not be found")
+  })
+}
+
 func TestTagsService_Update(t *testing.T) {
   const routine = "Update"
 
@@ -116,7 +142,7 @@ func TestTagsService_Update(t *testing.T) {
     r.On(routine, ctx, id, update).Return(nil)
     r.On("Get", ctx).Return([]*model.Tag{{}, {}}, nil)
 
-    err := NewTagsService(r).Update(ctx, id, dirty)
+    err := NewTagsService(r, nil).Update(ctx, id, dirty)
 
     assert.NoError(t, err)
   })
@@ -126,7 +152,7 @@ func TestTagsService_Update(t *testing.T) {
     r := mocks.NewTagsRepository()
     r.On(routine, ctx, mock.Anything, mock.Anything).Return(unexpected)
 
-    err := NewTagsService(r).Update(ctx, id, update)
+    err := NewTagsService(r, nil).Update(ctx, id, update)
     assert.ErrorIs(t, err, unexpected)
   })
 }
@@ -142,7 +168,45 @@ func TestTagsService_Remove(t *testing.T) {
     r.On(routine, ctx, id).Return(nil)
     r.On("Get", ctx).Return([]*model.Tag{{}, {}}, nil)
 
-    assert.NoError(t, NewTagsService(r).Remove(ctx, id))
+    assert.NoError(t, NewTagsService(r, nil).Remove(ctx, id))
+  })
+
+  t.Run("gets a repository failure", func(t *testing.T) {
+    unexpected := errors.New("unexpected error")
+
+    r := mocks.NewTagsRepository()
+    r.On(routine, ctx, id).Return(unexpected)
+
+    assert.ErrorIs(t, NewTagsService(r, nil).Remove(ctx, id), unexpected)
+  })
+
+  t.Run("runs in the unit of work", func(t *testing.T) {
+    unexpected := errors.New("unexpected error")
+
+    r := mocks.NewTagsRepository()
+
+    u := mocks.NewUnitOfWork()
+    u.On("Do", ctx).Return(unexpected)
+
+    assert.ErrorIs(t, NewTagsService(r, u).Remove(ctx, id), unexpected)
+    r.AssertNotCalled(t, routine, mock.Anything, mock.Anything)
+    u.AssertExpectations(t)
+  })
+}
+
+func TestTagsService_Restore(t *testing.T) {
+  const routine = "Restore"
+
+  ctx := context.TODO()
+  id := "id"
+
+  t.Run("success", func(t *testing.T) {
+    r := mocks.NewTagsRepository()
+    r.On(routine, ctx, id).Return(nil)
+    r.On("Get", ctx).Return([]*model.Tag{{}, {}}, nil)
+
+    assert.NoError(t, NewTagsService(r, nil).Restore(ctx, id))
+    r.AssertExpectations(t)
   })
 
   t.Run("gets a repository failure", func(t *testing.T) {
@@ -151,6 +215,6 @@ func TestTagsService_Remove(t *testing.T) {
     r := mocks.NewTagsRepository()
     r.On(routine, ctx, id).Return(unexpected)
 
-    assert.ErrorIs(t, NewTagsService(r).Remove(ctx, id), unexpected)
+    assert.ErrorIs(t, NewTagsService(r, nil).Restore(ctx, id), unexpected)
   })
 }
@@ -6,6 +6,7 @@ import (
   "fontseca.dev/model"
   "fontseca.dev/problem"
   "fontseca.dev/repository"
+  "fontseca.dev/tracing"
   "fontseca.dev/transfer"
   "log/slog"
   "strings"
@@ -17,6 +18,12 @@ type TechnologyTagService interface {
   // Get retrieves a slice of technology tags.
   Get(ctx context.Context) (technologies []*model.TechnologyTag, err error)
 
+  // List retrieves the page of technology tags that follows the cursor of page.
+  List(ctx context.Context, page *transfer.PageRequest) (technologies *transfer.Page[*model.TechnologyTag], err error)
+
+  // GetByID retrieves the technology tag with the given ID.
+  GetByID(ctx context.Context, id string) (technology *model.TechnologyTag, err error)
+
   // Add creates a new technology tag record with the provided creation data.
   Add(ctx context.Context, creation *transfer.TechnologyTagCreation) (id string, err error)
 
@@ -40,13 +47,46 @@ func NewTechnologyTagService(repository repository.TechnologyTagRepository) Tech
 }
 
 func (s *technologyTagService) Get(ctx context.Context) (technologies []*model.TechnologyTag, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
   return s.r.Get(ctx)
 }
 
+func (s *technologyTagService) List(ctx context.Context, page *transfer.PageRequest) (technologies *transfer.Page[*model.TechnologyTag], err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
+  return s.r.List(ctx, page)
+}
+
+func (s *technologyTagService) GetByID(ctx context.Context, id string) (technology *model.TechnologyTag, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
+  err = validateUUID(&id)
+  if nil != err {
+    return nil, err
+  }
+  technologies, err := s.r.Get(ctx)
+  if nil != err {
+    return nil, err
+  }
+  for _, technology := range technologies {
+    if id == technology.UUID.String() {
+      return technology, nil
+    }
+  }
+  return nil, problem.NewNotFound(id, "technology_tag")
+}
+
 func (s *technologyTagService) Add(ctx context.Context, creation *transfer.TechnologyTagCreation) (id string, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
   if nil == creation {
     err = errors.New("nil value for parameter: creation")
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return "", err
   }
   creation.Name = strings.TrimSpace(creation.Name)
@@ -57,6 +97,9 @@ func (s *technologyTagService) Add(ctx context.Context, creation *transfer.Techn
 }
 
 func (s *technologyTagService) Exists(ctx context.Context, id string) (err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
   err = validateUUID(&id)
   if nil != err {
     return err
@@ -65,9 +108,12 @@ func (s *technologyTagService) Exists(ctx context.Context, id string) (err error
 }
 
 func (s *technologyTagService) Update(ctx context.Context, id string, update *transfer.TechnologyTagUpdate) (updated bool, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
   if nil == update {
     err = errors.New("nil value for parameter: update")
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return false, err
   }
   err = validateUUID(&id)
@@ -82,6 +128,9 @@ func (s *technologyTagService) Update(ctx context.Context, id string, update *tr
 }
 
 func (s *technologyTagService) Remove(ctx context.Context, id string) (err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
   err = validateUUID(&id)
   if nil != err {
     return err
@@ -0,0 +1,106 @@
+package service
+
+import (
+  "context"
+  "crypto/rand"
+  "crypto/sha256"
+  "encoding/base64"
+  "encoding/hex"
+  "fontseca.dev/model"
+  "fontseca.dev/problem"
+  "fontseca.dev/repository"
+  "fontseca.dev/tracing"
+  "log/slog"
+  "strings"
+)
+
+// maxTokenNameLength is the length of the longest name of a token.
+const maxTokenNameLength = 64
+
+// TokensService is a high level provider for the API tokens that
+// authenticate the administrative endpoints besides the configured
+// administration token.
+type TokensService interface {
+  // Create makes a random token called name and stores its hash. The
+  // token is returned only here, so it must be kept by the caller.
+  Create(ctx context.Context, name string) (token string, err error)
+
+  // Check reports whether token was made by Create and not revoked.
+  Check(ctx context.Context, token string) (ok bool, err error)
+
+  // Get retrieves every token, the most recent first.
+  Get(ctx context.Context) (tokens []*model.Token, err error)
+
+  // Revoke removes the token called name, which stops authenticating
+  // right away.
+  Revoke(ctx context.Context, name string) error
+}
+
+type tokensService struct {
+  r repository.TokensRepository
+}
+
+func NewTokensService(r repository.TokensRepository) TokensService {
+  return &tokensService{r}
+}
+
+// hashToken returns the hash under which token is stored. The tokens
+// are 256 random bits, so a fast hash is as good as a slow one here,
+// and it lets every request be checked with a single lookup.
+func hashToken(token string) string {
+  var sum = sha256.Sum256([]byte(token))
+  return hex.EncodeToString(sum[:])
+}
+
+func (s *tokensService) Create(ctx context.Context, name string) (token string, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
+  name = strings.TrimSpace(name)
+
+  switch {
+  case "" == name:
+    return "", problem.NewValidation([3]string{"name", "required", ""})
+  case maxTokenNameLength < len(name):
+    return "", problem.NewValidation([3]string{"name", "max", "64"})
+  }
+
+  var random = make([]byte, 32)
+  if _, err = rand.Read(random); nil != err {
+    slog.ErrorContext(ctx, err.Error())
+    return "", err
+  }
+
+  token = base64.RawURLEncoding.EncodeToString(random)
+
+  if err = s.r.Add(ctx, name, hashToken(token)); nil != err {
+    return "", err
+  }
+
+  return token, nil
+}
+
+func (s *tokensService) Check(ctx context.Context, token string) (ok bool, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
+  if "" == token {
+    return false, nil
+  }
+
+  return s.r.Exists(ctx, hashToken(token))
+}
+
+func (s *tokensService) Get(ctx context.Context) (tokens []*model.Token, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
+  return s.r.Get(ctx)
+}
+
+func (s *tokensService) Revoke(ctx context.Context, name string) error {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
+  return s.r.Remove(ctx, strings.TrimSpace(name))
+}
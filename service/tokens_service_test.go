@@ -0,0 +1,77 @@
+package service
+
+import (
+  "context"
+  "errors"
+  "fontseca.dev/mocks"
+  "github.com/stretchr/testify/assert"
+  "github.com/stretchr/testify/mock"
+  "testing"
+)
+
+func TestTokensService_Create(t *testing.T) {
+  const routine = "Add"
+
+  ctx := context.TODO()
+
+  t.Run("stores the hash of the token", func(t *testing.T) {
+    var stored string
+    r := mocks.NewTokensRepository()
+    r.On(routine, mock.Anything, "deploy", mock.AnythingOfType("string")).
+      Run(func(args mock.Arguments) { stored = args.String(2) }).
+      Return(nil)
+
+    token, err := NewTokensService(r).Create(ctx, " deploy ")
+    assert.NoError(t, err)
+    assert.Len(t, token, 43)
+    assert.Equal(t, hashToken(token), stored)
+    assert.NotContains(t, stored, token)
+    r.AssertExpectations(t)
+  })
+
+  t.Run("invalid name", func(t *testing.T) {
+    r := mocks.NewTokensRepository()
+    r.AssertNotCalled(t, routine)
+
+    for _, name := range []string{"", "  ", string(make([]byte, maxTokenNameLength+1))} {
+      token, err := NewTokensService(r).Create(ctx, name)
+      assert.Error(t, err)
+      assert.Empty(t, token)
+    }
+  })
+
+  t.Run("unexpected error", func(t *testing.T) {
+    unexpected := errors.New("unexpected error")
+    r := mocks.NewTokensRepository()
+    r.On(routine, mock.Anything, mock.Anything, mock.Anything).Return(unexpected)
+
+    token, err := NewTokensService(r).Create(ctx, "deploy")
+    assert.ErrorIs(t, err, unexpected)
+    assert.Empty(t, token)
+  })
+}
+
+func TestTokensService_Check(t *testing.T) {
+  const routine = "Exists"
+
+  ctx := context.TODO()
+
+  t.Run("looks the token up by its hash", func(t *testing.T) {
+    r := mocks.NewTokensRepository()
+    r.On(routine, mock.Anything, hashToken("token")).Return(true, nil)
+
+    ok, err := NewTokensService(r).Check(ctx, "token")
+    assert.NoError(t, err)
+    assert.True(t, ok)
+    r.AssertExpectations(t)
+  })
+
+  t.Run("empty token", func(t *testing.T) {
+    r := mocks.NewTokensRepository()
+    r.AssertNotCalled(t, routine)
+
+    ok, err := NewTokensService(r).Check(ctx, "")
+    assert.NoError(t, err)
+    assert.False(t, ok)
+  })
+}
@@ -4,7 +4,9 @@ import (
   "context"
   "errors"
   "fontseca.dev/model"
+  "fontseca.dev/problem"
   "fontseca.dev/repository"
+  "fontseca.dev/tracing"
   "fontseca.dev/transfer"
   "log/slog"
   "strings"
@@ -18,30 +20,46 @@ type TopicsService interface {
   // Get retrieves all the topics.
   Get(ctx context.Context) (topics []*model.Topic, err error)
 
+  // List retrieves the page of topics that follows the cursor of page.
+  List(ctx context.Context, page *transfer.PageRequest) (topics *transfer.Page[*model.Topic], err error)
+
+  // GetByID retrieves the topic with the given ID.
+  GetByID(ctx context.Context, id string) (topic *model.Topic, err error)
+
   // Update updates an existing topic.
   Update(ctx context.Context, id string, update *transfer.TopicUpdate) error
 
-  // Remove removes a topic and detaches it from any
-  // article that currently uses it.
+  // Remove moves a topic to the trash.
   Remove(ctx context.Context, id string) error
+
+  // Restore brings back a topic from the trash.
+  Restore(ctx context.Context, id string) error
 }
 
 type topicsService struct {
   cache []*model.Topic
   r     repository.TopicsRepository
+  unit  repository.UnitOfWork
 }
 
-func NewTopicsService(r repository.TopicsRepository) TopicsService {
+// NewTopicsService returns a TopicsService whose changes run in unit, so
+// that they take part in the unit of work of the context they are
+// given, if any.
+func NewTopicsService(r repository.TopicsRepository, unit repository.UnitOfWork) TopicsService {
   return &topicsService{
     cache: nil,
     r:     r,
+    unit:  unit,
   }
 }
 
 func (s *topicsService) Add(ctx context.Context, creation *transfer.TopicCreation) error {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
   if nil == creation {
     err := errors.New("nil value for parameter: creation")
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return err
   }
 
@@ -49,7 +67,9 @@ func (s *topicsService) Add(ctx context.Context, creation *transfer.TopicCreatio
   sanitizeTextWordIntersections(&creation.Name)
   creation.ID = toKebabCase(creation.Name)
 
-  err := s.r.Add(ctx, creation)
+  err := within(ctx, s.unit, func(ctx context.Context) error {
+    return s.r.Add(ctx, creation)
+  })
 
   if nil != err {
     return err
@@ -61,6 +81,9 @@ func (s *topicsService) Add(ctx context.Context, creation *transfer.TopicCreatio
 }
 
 func (s *topicsService) Get(ctx context.Context) (topics []*model.Topic, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
   if s.hasCache() {
     return s.cache, nil
   }
@@ -76,10 +99,39 @@ func (s *topicsService) Get(ctx context.Context) (topics []*model.Topic, err err
   return topics, err
 }
 
+func (s *topicsService) List(ctx context.Context, page *transfer.PageRequest) (topics *transfer.Page[*model.Topic], err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
+  return s.r.List(ctx, page)
+}
+
+func (s *topicsService) GetByID(ctx context.Context, id string) (topic *model.Topic, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
+  topics, err := s.Get(ctx)
+
+  if nil != err {
+    return nil, err
+  }
+
+  for _, topic := range topics {
+    if id == topic.ID {
+      return topic, nil
+    }
+  }
+
+  return nil, problem.NewNotFound(id, "topic")
+}
+
 func (s *topicsService) Update(ctx context.Context, id string, update *transfer.TopicUpdate) error {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
   if nil == update {
     err := errors.New("nil value for parameter: creation")
-    slog.Error(err.Error())
+    slog.ErrorContext(ctx, err.Error())
     return err
   }
 
@@ -87,7 +139,9 @@ func (s *topicsService) Update(ctx context.Context, id string, update *transfer.
   sanitizeTextWordIntersections(&update.Name)
   update.ID = toKebabCase(update.Name)
 
-  err := s.r.Update(ctx, id, update)
+  err := within(ctx, s.unit, func(ctx context.Context) error {
+    return s.r.Update(ctx, id, update)
+  })
 
   if nil != err {
     return err
@@ -99,7 +153,29 @@ func (s *topicsService) Update(ctx context.Context, id string, update *transfer.
 }
 
 func (s *topicsService) Remove(ctx context.Context, id string) error {
-  err := s.r.Remove(ctx, id)
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
+  err := within(ctx, s.unit, func(ctx context.Context) error {
+    return s.r.Remove(ctx, id)
+  })
+
+  if nil != err {
+    return err
+  }
+
+  s.setCache(ctx)
+
+  return nil
+}
+
+func (s *topicsService) Restore(ctx context.Context, id string) error {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
+  err := within(ctx, s.unit, func(ctx context.Context) error {
+    return s.r.Restore(ctx, id)
+  })
 
   if nil != err {
     return err
@@ -30,7 +30,7 @@ func TestTopicsService_Add(t *testing.T) {
     r.On(routine, ctx, creation).Return(nil)
     r.On("Get", ctx).Return([]*model.Topic{{}, {}}, nil)
 
-    err := NewTopicsService(r).Add(ctx, dirty)
+    err := NewTopicsService(r, nil).Add(ctx, dirty)
 
     assert.NoError(t, err)
   })
@@ -40,7 +40,7 @@ func TestTopicsService_Add(t *testing.T) {
     r := mocks.NewTopicsRepository()
     r.On(routine, ctx, mock.Anything).Return(unexpected)
 
-    err := NewTopicsService(r).Add(ctx, creation)
+    err := NewTopicsService(r, nil).Add(ctx, creation)
     assert.ErrorIs(t, err, unexpected)
   })
 }
@@ -56,7 +56,7 @@ func TestTopicsService_Get(t *testing.T) {
     r := mocks.NewTopicsRepository()
     r.On(routine, ctx).Return(expectedTopics, nil)
 
-    s := NewTopicsService(r).(*topicsService)
+    s := NewTopicsService(r, nil).(*topicsService)
 
     s.cache = nil
 
@@ -72,7 +72,7 @@ func TestTopicsService_Get(t *testing.T) {
     r := mocks.NewTopicsRepository()
     r.AssertNotCalled(t, routine)
 
-    s := NewTopicsService(r).(*topicsService)
+    s := NewTopicsService(r, nil).(*topicsService)
 
     s.cache = expectedTopics
 
@@ -88,13 +88,39 @@ func TestTopicsService_Get(t *testing.T) {
     r := mocks.NewTopicsRepository()
     r.On(routine, ctx).Return(nil, unexpected)
 
-    topics, err := NewTopicsService(r).Get(ctx)
+    topics, err := NewTopicsService(r, nil).Get(ctx)
 
     assert.Nil(t, topics)
     assert.ErrorIs(t, err, unexpected)
   })
 }
 
+func TestTopicsService_GetByID(t *testing.T) {
+  ctx := context.TODO()
+
+  t.Run("success", func(t *testing.T) {
+    expected := &model.Topic{ID: "go", Name: "Go"}
+
+    r := mocks.NewTopicsRepository()
+    r.On("Get", ctx).Return([]*model.Topic{{ID: "sql"}, expected}, nil)
+
+    topic, err := NewTopicsService(r, nil).GetByID(ctx, "go")
+
+    assert.Equal(t, expected, topic)
+    assert.NoError(t, err)
+  })
+
+  t.Run("not found", func(t *testing.T) {
+    r := mocks.NewTopicsRepository()
+    r.On("Get", ctx).Return([]*model.Topic{{ID: "sql"}}, nil)
+
+    topic, err := NewTopicsService(r, nil).GetByID(ctx, "go")
+
+    assert.Nil(t, topic)
+    assert.ErrorContains(t, err, "could not be found")
+  })
+}
+
 func TestTopicsService_Update(t *testing.T) {
   const routine = "Update"
 
@@ -116,7 +142,7 @@ func TestTopicsService_Update(t *testing.T) {
     r.On(routine, ctx, id, update).Return(nil)
     r.On("Get", ctx).Return([]*model.Topic{{}, {}}, nil)
 
-    err := NewTopicsService(r).Update(ctx, id, dirty)
+    err := NewTopicsService(r, nil).Update(ctx, id, dirty)
 
     assert.NoError(t, err)
   })
@@ -126,7 +152,7 @@ func TestTopicsService_Update(t *testing.T) {
     r := mocks.NewTopicsRepository()
     r.On(routine, ctx, mock.Anything, mock.Anything).Return(unexpected)
 
-    err := NewTopicsService(r).Update(ctx, id, update)
+    err := NewTopicsService(r, nil).Update(ctx, id, update)
     assert.ErrorIs(t, err, unexpected)
   })
 }
@@ -142,7 +168,45 @@ func TestTopicsService_Remove(t *testing.T) {
     r.On(routine, ctx, id).Return(nil)
     r.On("Get", ctx).Return([]*model.Topic{{}, {}}, nil)
 
-    assert.NoError(t, NewTopicsService(r).Remove(ctx, id))
+    assert.NoError(t, NewTopicsService(r, nil).Remove(ctx, id))
+  })
+
+  t.Run("gets a repository failure", func(t *testing.T) {
+    unexpected := errors.New("unexpected error")
+
+    r := mocks.NewTopicsRepository()
+    r.On(routine, ctx, id).Return(unexpected)
+
+    assert.ErrorIs(t, NewTopicsService(r, nil).Remove(ctx, id), unexpected)
+  })
+
+  t.Run("runs in the unit of work", func(t *testing.T) {
+    unexpected := errors.New("unexpected error")
+
+    r := mocks.NewTopicsRepository()
+
+    u := mocks.NewUnitOfWork()
+    u.On("Do", ctx).Return(unexpected)
+
+    assert.ErrorIs(t, NewTopicsService(r, u).Remove(ctx, id), unexpected)
+    r.AssertNotCalled(t, routine, mock.Anything, mock.Anything)
+    u.AssertExpectations(t)
+  })
+}
+
+func TestTopicsService_Restore(t *testing.T) {
+  const routine = "Restore"
+
+  ctx := context.TODO()
+  id := "id"
+
+  t.Run("success", func(t *testing.T) {
+    r := mocks.NewTopicsRepository()
+    r.On(routine, ctx, id).Return(nil)
+    r.On("Get", ctx).Return([]*model.Topic{{}, {}}, nil)
+
+    assert.NoError(t, NewTopicsService(r, nil).Restore(ctx, id))
+    r.AssertExpectations(t)
   })
 
   t.Run("gets a repository failure", func(t *testing.T) {
@@ -151,6 +215,6 @@ func TestTopicsService_Remove(t *testing.T) {
     r := mocks.NewTopicsRepository()
     r.On(routine, ctx, id).Return(unexpected)
 
-    assert.ErrorIs(t, NewTopicsService(r).Remove(ctx, id), unexpected)
+    assert.ErrorIs(t, NewTopicsService(r, nil).Restore(ctx, id), unexpected)
   })
 }
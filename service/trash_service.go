@@ -0,0 +1,134 @@
+package service
+
+import (
+  "context"
+  "fontseca.dev/problem"
+  "fontseca.dev/repository"
+  "fontseca.dev/tracing"
+  "fontseca.dev/transfer"
+  "log/slog"
+  "time"
+)
+
+// TrashService is a high level provider for the removed content, which
+// can be restored until it has been in the trash for longer than the
+// retention, when it is purged.
+type TrashService interface {
+  // Get retrieves the page of removed content that follows the cursor
+  // of page, the most recently removed first.
+  Get(ctx context.Context, page *transfer.PageRequest) (items *transfer.Page[*transfer.TrashedItem], err error)
+
+  // Restore brings back the item of the provided type from the trash.
+  // The type is one of the types of transfer.TrashedItem.
+  Restore(ctx context.Context, kind, id string) error
+
+  // Purge permanently deletes the content that has been in the trash
+  // for longer than the retention.
+  Purge(ctx context.Context) (purged int64, err error)
+
+  // Run purges the trash every interval until ctx is done.
+  Run(ctx context.Context, interval time.Duration)
+}
+
+type trashService struct {
+  r          repository.TrashRepository
+  archive    repository.ArchiveRepository
+  projects   repository.ProjectsRepository
+  experience repository.ExperienceRepository
+  tags       TagsService   // keeps the cache of the tags in sync
+  topics     TopicsService // keeps the cache of the topics in sync
+  retention  time.Duration
+}
+
+// NewTrashService returns a TrashService that purges the content that
+// has been in the trash for longer than retention.
+func NewTrashService(
+  r repository.TrashRepository,
+  archive repository.ArchiveRepository,
+  projects repository.ProjectsRepository,
+  experience repository.ExperienceRepository,
+  tags TagsService,
+  topics TopicsService,
+  retention time.Duration,
+) TrashService {
+  return &trashService{
+    r:          r,
+    archive:    archive,
+    projects:   projects,
+    experience: experience,
+    tags:       tags,
+    topics:     topics,
+    retention:  retention,
+  }
+}
+
+func (s *trashService) Get(ctx context.Context, page *transfer.PageRequest) (items *transfer.Page[*transfer.TrashedItem], err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
+  return s.r.Get(ctx, page)
+}
+
+func (s *trashService) Restore(ctx context.Context, kind, id string) error {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
+  switch kind {
+  case "article", "draft", "patch":
+    if err := validateUUID(&id); nil != err {
+      return err
+    }
+
+    return s.archive.Restore(ctx, id)
+  case "project":
+    if err := validateUUID(&id); nil != err {
+      return err
+    }
+
+    return s.projects.Restore(ctx, id)
+  case "experience":
+    if err := validateUUID(&id); nil != err {
+      return err
+    }
+
+    return s.experience.Restore(ctx, id)
+  case "tag":
+    return s.tags.Restore(ctx, id)
+  case "topic":
+    return s.topics.Restore(ctx, id)
+  }
+
+  return problem.NewValidation([3]string{"type", "oneof", "article draft patch project experience tag topic"})
+}
+
+func (s *trashService) Purge(ctx context.Context) (purged int64, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
+  purged, err = s.r.Purge(ctx, time.Now().Add(-s.retention))
+  if nil != err {
+    return 0, err
+  }
+
+  if 0 < purged {
+    slog.InfoContext(ctx, "purged trash", slog.Int64("purged", purged))
+  }
+
+  return purged, nil
+}
+
+func (s *trashService) Run(ctx context.Context, interval time.Duration) {
+  var ticker = time.NewTicker(interval)
+  defer ticker.Stop()
+
+  for {
+    select {
+    case <-ctx.Done():
+      return
+    case <-ticker.C:
+      if _, err := s.Purge(ctx); nil != err && nil == ctx.Err() {
+        slog.Error("could not purge trash: " + err.Error())
+      }
+    }
+  }
+}
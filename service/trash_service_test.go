@@ -0,0 +1,122 @@
+package service
+
+import (
+  "context"
+  "errors"
+  "fontseca.dev/mocks"
+  "fontseca.dev/problem"
+  "github.com/google/uuid"
+  "github.com/stretchr/testify/assert"
+  "github.com/stretchr/testify/mock"
+  "net/http"
+  "testing"
+  "time"
+)
+
+func TestTrashService_Restore(t *testing.T) {
+  const routine = "Restore"
+
+  ctx := context.TODO()
+  id := uuid.NewString()
+
+  var newTrashService = func() (TrashService, *mocks.ArchiveRepository, *mocks.ProjectsRepository, *mocks.ExperienceRepository, *mocks.TagsService, *mocks.TopicsService) {
+    var (
+      archive    = mocks.NewArchiveRepository()
+      projects   = mocks.NewProjectsRepository()
+      experience = mocks.NewExperienceRepository()
+      tags       = mocks.NewTagsService()
+      topics     = mocks.NewTopicsService()
+    )
+
+    return NewTrashService(mocks.NewTrashRepository(), archive, projects, experience, tags, topics, time.Hour), archive, projects, experience, tags, topics
+  }
+
+  for _, kind := range []string{"article", "draft", "patch"} {
+    t.Run("restores a "+kind+" from the archive", func(t *testing.T) {
+      s, archive, _, _, _, _ := newTrashService()
+      archive.On(routine, ctx, id).Return(nil)
+      assert.NoError(t, s.Restore(ctx, kind, id))
+      archive.AssertExpectations(t)
+    })
+  }
+
+  t.Run("restores a project", func(t *testing.T) {
+    s, _, projects, _, _, _ := newTrashService()
+    projects.On(routine, ctx, id).Return(nil)
+    assert.NoError(t, s.Restore(ctx, "project", id))
+    projects.AssertExpectations(t)
+  })
+
+  t.Run("restores an experience record", func(t *testing.T) {
+    s, _, _, experience, _, _ := newTrashService()
+    experience.On(routine, ctx, id).Return(nil)
+    assert.NoError(t, s.Restore(ctx, "experience", id))
+    experience.AssertExpectations(t)
+  })
+
+  t.Run("restores a tag", func(t *testing.T) {
+    s, _, _, _, tags, _ := newTrashService()
+    tags.On(routine, ctx, "go").Return(nil)
+    assert.NoError(t, s.Restore(ctx, "tag", "go"))
+    tags.AssertExpectations(t)
+  })
+
+  t.Run("restores a topic", func(t *testing.T) {
+    s, _, _, _, _, topics := newTrashService()
+    topics.On(routine, ctx, "go").Return(nil)
+    assert.NoError(t, s.Restore(ctx, "topic", "go"))
+    topics.AssertExpectations(t)
+  })
+
+  t.Run("wrong uuid", func(t *testing.T) {
+    s, archive, _, _, _, _ := newTrashService()
+    archive.AssertNotCalled(t, routine)
+    var p *problem.Problem
+    assert.ErrorAs(t, s.Restore(ctx, "article", "x"), &p)
+  })
+
+  t.Run("unknown type", func(t *testing.T) {
+    s, _, _, _, _, _ := newTrashService()
+    var p *problem.Problem
+    if assert.ErrorAs(t, s.Restore(ctx, "webhook", id), &p) {
+      status, _, _ := p.Describe()
+      assert.Equal(t, http.StatusBadRequest, status)
+    }
+  })
+
+  t.Run("error", func(t *testing.T) {
+    var unexpected = errors.New("unexpected error")
+    s, _, projects, _, _, _ := newTrashService()
+    projects.On(routine, ctx, id).Return(unexpected)
+    assert.ErrorIs(t, s.Restore(ctx, "project", id), unexpected)
+  })
+}
+
+func TestTrashService_Purge(t *testing.T) {
+  const routine = "Purge"
+
+  ctx := context.TODO()
+
+  t.Run("purges what outlived the retention", func(t *testing.T) {
+    var now = time.Now()
+    r := mocks.NewTrashRepository()
+    r.On(routine, ctx, mock.MatchedBy(func(before time.Time) bool {
+      return before.Before(now.Add(-time.Hour+time.Second)) && before.After(now.Add(-time.Hour-time.Minute))
+    })).Return(int64(3), nil)
+
+    purged, err := NewTrashService(r, nil, nil, nil, nil, nil, time.Hour).Purge(ctx)
+    assert.NoError(t, err)
+    assert.Equal(t, int64(3), purged)
+    r.AssertExpectations(t)
+  })
+
+  t.Run("error", func(t *testing.T) {
+    var unexpected = errors.New("unexpected error")
+    r := mocks.NewTrashRepository()
+    r.On(routine, ctx, mock.AnythingOfType("time.Time")).Return(int64(0), unexpected)
+
+    purged, err := NewTrashService(r, nil, nil, nil, nil, nil, time.Hour).Purge(ctx)
+    assert.ErrorIs(t, err, unexpected)
+    assert.Zero(t, purged)
+  })
+}
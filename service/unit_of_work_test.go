@@ -0,0 +1,70 @@
+package service
+
+import (
+  "context"
+  "fontseca.dev/database"
+  "fontseca.dev/repository"
+  "fontseca.dev/transfer"
+  "github.com/stretchr/testify/assert"
+  "github.com/stretchr/testify/require"
+  "testing"
+)
+
+// TestUnitOfWork checks on a real database that a change to the content
+// and the event it causes, which are written by different repositories,
+// are committed together or not at all.
+func TestUnitOfWork(t *testing.T) {
+  var ctx = context.Background()
+
+  db, err := database.Open(database.SQLiteDriver, ":memory:", 1, 0)
+  require.NoError(t, err)
+  defer db.Close()
+
+  require.NoError(t, database.Migrate(ctx, db))
+  repository.NewMeRepository(db).Register(ctx)
+
+  var (
+    archive  = repository.NewArchiveRepository(db, 0)
+    topics   = repository.NewTopicsRepository(db)
+    webhooks = NewWebhooksService(repository.NewWebhooksRepository(db))
+    drafts   = NewDraftsService(archive, repository.NewUnitOfWork(db), webhooks, readingSpeed)
+  )
+
+  defer archive.Close()
+
+  require.NoError(t, topics.Add(ctx, &transfer.TopicCreation{ID: "go", Name: "Go"}))
+  _, _, err = webhooks.Add(ctx, &transfer.WebhookCreation{URL: "https://example.com/hooks", Events: ArticlePublished})
+  require.NoError(t, err)
+
+  // draft starts a draft that is ready to be published.
+  draft := func(slug string) string {
+    id, err := drafts.Draft(ctx, &transfer.ArticleCreation{Title: slug, Content: "Content."})
+    require.NoError(t, err)
+    require.NoError(t, archive.Revise(ctx, id.String(), &transfer.ArticleRevision{Topic: "go", Version: 1}))
+    return id.String()
+  }
+
+  t.Run("commits the change with its event", func(t *testing.T) {
+    var id = draft("committed")
+    require.NoError(t, drafts.Publish(ctx, id))
+
+    _, err := archive.GetByID(ctx, id, false)
+    assert.NoError(t, err)
+
+    deliveries, err := repository.NewWebhooksRepository(db).Due(ctx, 10)
+    require.NoError(t, err)
+    assert.Len(t, deliveries, 1)
+  })
+
+  t.Run("rolls back the change when its event cannot be queued", func(t *testing.T) {
+    var id = draft("rolled-back")
+
+    _, err := db.ExecContext(ctx, `ALTER TABLE "webhook_delivery" RENAME TO "webhook_delivery_gone";`)
+    require.NoError(t, err)
+
+    assert.Error(t, drafts.Publish(ctx, id))
+
+    _, err = archive.GetByID(ctx, id, true)
+    assert.NoError(t, err, "the draft must not have been published")
+  })
+}
@@ -0,0 +1,352 @@
+package service
+
+import (
+  "bytes"
+  "context"
+  "crypto/hmac"
+  "crypto/rand"
+  "crypto/sha256"
+  "encoding/hex"
+  "encoding/json"
+  "errors"
+  "fontseca.dev/model"
+  "fontseca.dev/problem"
+  "fontseca.dev/repository"
+  "fontseca.dev/tracing"
+  "fontseca.dev/transfer"
+  "github.com/google/uuid"
+  "io"
+  "log/slog"
+  "net/http"
+  "slices"
+  "strconv"
+  "strings"
+  "time"
+)
+
+// The events of the lifecycle of the content of the site that webhooks
+// can subscribe to.
+const (
+  ArticlePublished  = "article.published"
+  ArticleUpdated    = "article.updated"
+  ArticleHidden     = "article.hidden"
+  ArticleShown      = "article.shown"
+  ArticleRemoved    = "article.removed"
+  PatchReleased     = "patch.released"
+  ProjectAdded      = "project.added"
+  ProjectUpdated    = "project.updated"
+  ProjectRemoved    = "project.removed"
+  ExperienceAdded   = "experience.added"
+  ExperienceUpdated = "experience.updated"
+  ExperienceRemoved = "experience.removed"
+  MeUpdated         = "me.updated"
+)
+
+// Events is the catalogue of events webhooks can subscribe to.
+var Events = []string{
+  ArticlePublished,
+  ArticleUpdated,
+  ArticleHidden,
+  ArticleShown,
+  ArticleRemoved,
+  PatchReleased,
+  ProjectAdded,
+  ProjectUpdated,
+  ProjectRemoved,
+  ExperienceAdded,
+  ExperienceUpdated,
+  ExperienceRemoved,
+  MeUpdated,
+}
+
+// Publisher publishes the events of the lifecycle of the content.
+type Publisher interface {
+  // Publish notifies that event occurred. Within a unit of work, the
+  // event is published only if the unit commits, so an error fails the
+  // operation that caused it and rolls it back.
+  Publish(ctx context.Context, event string, data any) error
+}
+
+// publish publishes event through p, if any, with the data formed by
+// pairs of keys and values.
+func publish(ctx context.Context, p Publisher, event string, pairs ...string) error {
+  if nil == p {
+    return nil
+  }
+
+  var data = make(map[string]string, len(pairs)/2)
+  for i := 0; i+1 < len(pairs); i += 2 {
+    data[pairs[i]] = pairs[i+1]
+  }
+
+  return p.Publish(ctx, event, data)
+}
+
+// within runs fn in unit, if any, so that the changes fn makes and the
+// events it publishes are committed together or not at all.
+func within(ctx context.Context, unit repository.UnitOfWork, fn func(ctx context.Context) error) error {
+  if nil == unit {
+    return fn(ctx)
+  }
+
+  return unit.Do(ctx, fn)
+}
+
+// reading runs fn in a read-only transaction of unit, if any, so that
+// the records fn reads agree with each other.
+func reading(ctx context.Context, unit repository.UnitOfWork, fn func(ctx context.Context) error) error {
+  if nil == unit {
+    return fn(ctx)
+  }
+
+  return unit.Read(ctx, fn)
+}
+
+const (
+  // maxDeliveryAttempts is the number of attempts after which a
+  // delivery is given up on.
+  maxDeliveryAttempts = 10
+
+  // firstRetryIn is the time before the first retry of a delivery,
+  // which is doubled after every other failed attempt.
+  firstRetryIn = 30 * time.Second
+
+  // maxRetryIn is the longest time between two attempts of a delivery.
+  maxRetryIn = 6 * time.Hour
+
+  // dueDeliveries is the number of deliveries attempted at once.
+  dueDeliveries = 50
+)
+
+// WebhooksService is a high level provider for webhooks. Published
+// events are queued for every subscribed webhook and delivered later
+// on, so that a slow or unavailable endpoint never delays the site.
+//
+// Every delivery is a POST request with a JSON body signed with the
+// secret of the webhook. The X-Webhook-Signature header holds the
+// HMAC-SHA256 of the X-Webhook-Timestamp header, a dot and the body,
+// in the form: 'sha256=<hex>'.
+type WebhooksService interface {
+  Publisher
+
+  // Add registers a webhook subscribed to the comma-separated events
+  // of creation, or to all of them if it is '*'. If no secret is
+  // provided, a random one is generated. It returns the UUID of the
+  // webhook and its secret.
+  Add(ctx context.Context, creation *transfer.WebhookCreation) (id, secret string, err error)
+
+  // Get retrieves the page of registered webhooks that follows the
+  // cursor of page.
+  Get(ctx context.Context, page *transfer.PageRequest) (webhooks *transfer.Page[*model.Webhook], err error)
+
+  // Remove deletes a webhook along with its deliveries.
+  Remove(ctx context.Context, id string) error
+
+  // Deliveries retrieves the page of the log of deliveries of a
+  // webhook that follows the cursor of page.
+  Deliveries(ctx context.Context, webhookID string, page *transfer.PageRequest) (deliveries *transfer.Page[*model.WebhookDelivery], err error)
+
+  // Deliver attempts the deliveries that are due. A failed delivery is
+  // retried with exponential backoff until it is given up on. It
+  // returns the number of events delivered.
+  Deliver(ctx context.Context) (delivered int, err error)
+
+  // Run attempts the deliveries that are due every interval until ctx
+  // is done.
+  Run(ctx context.Context, interval time.Duration)
+}
+
+type webhooksService struct {
+  r      repository.WebhooksRepository
+  client *http.Client
+}
+
+func NewWebhooksService(r repository.WebhooksRepository) WebhooksService {
+  return &webhooksService{r, &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (s *webhooksService) Publish(ctx context.Context, event string, data any) error {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
+  var payload, err = json.Marshal(&transfer.WebhookEvent{
+    ID:         uuid.NewString(),
+    Event:      event,
+    OccurredAt: time.Now().UTC(),
+    Data:       data,
+  })
+
+  if nil != err {
+    slog.ErrorContext(ctx, err.Error())
+    return err
+  }
+
+  return s.r.Enqueue(ctx, event, payload)
+}
+
+func (s *webhooksService) Add(ctx context.Context, creation *transfer.WebhookCreation) (id, secret string, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
+  if nil == creation {
+    err = errors.New("nil value for parameter: creation")
+    slog.ErrorContext(ctx, err.Error())
+    return "", "", err
+  }
+
+  if err = sanitizeURL(&creation.URL); nil != err {
+    return "", "", err
+  }
+
+  var events []string
+  for _, event := range strings.Split(creation.Events, ",") {
+    event = strings.ToLower(strings.TrimSpace(event))
+
+    switch {
+    case "" == event || slices.Contains(events, event):
+      continue
+    case "*" != event && !slices.Contains(Events, event):
+      return "", "", problem.NewValidation([3]string{"events", "oneof", "* " + strings.Join(Events, " ")})
+    }
+
+    events = append(events, event)
+  }
+
+  switch {
+  case 0 == len(events):
+    return "", "", problem.NewValidation([3]string{"events", "required", ""})
+  case slices.Contains(events, "*"):
+    events = []string{"*"}
+  }
+
+  creation.Events = strings.Join(events, ",")
+  creation.Secret = strings.TrimSpace(creation.Secret)
+
+  if "" == creation.Secret {
+    var key = make([]byte, 32)
+    if _, err = rand.Read(key); nil != err {
+      slog.ErrorContext(ctx, err.Error())
+      return "", "", err
+    }
+    creation.Secret = hex.EncodeToString(key)
+  }
+
+  id, err = s.r.Add(ctx, creation)
+  if nil != err {
+    return "", "", err
+  }
+
+  return id, creation.Secret, nil
+}
+
+func (s *webhooksService) Get(ctx context.Context, page *transfer.PageRequest) (webhooks *transfer.Page[*model.Webhook], err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
+  return s.r.Get(ctx, page)
+}
+
+func (s *webhooksService) Remove(ctx context.Context, id string) error {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
+  if err := validateUUID(&id); nil != err {
+    return err
+  }
+
+  return s.r.Remove(ctx, id)
+}
+
+func (s *webhooksService) Deliveries(ctx context.Context, webhookID string, page *transfer.PageRequest) (deliveries *transfer.Page[*model.WebhookDelivery], err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
+  if err = validateUUID(&webhookID); nil != err {
+    return nil, err
+  }
+
+  return s.r.Deliveries(ctx, webhookID, page)
+}
+
+func (s *webhooksService) Deliver(ctx context.Context) (delivered int, err error) {
+  ctx, span := tracing.Start(ctx)
+  defer span.End()
+
+  deliveries, err := s.r.Due(ctx, dueDeliveries)
+  if nil != err {
+    return 0, err
+  }
+
+  for _, delivery := range deliveries {
+    var attempt = s.attempt(ctx, delivery)
+
+    if err = s.r.Record(ctx, attempt); nil != err {
+      return delivered, err
+    }
+
+    if attempt.Delivered {
+      delivered++
+    }
+  }
+
+  return delivered, nil
+}
+
+// attempt sends delivery to its webhook and returns the outcome.
+func (s *webhooksService) attempt(ctx context.Context, delivery *model.WebhookDelivery) *transfer.WebhookAttempt {
+  var attempt = &transfer.WebhookAttempt{DeliveryUUID: delivery.UUID.String()}
+
+  var timestamp = strconv.FormatInt(time.Now().Unix(), 10)
+  var mac = hmac.New(sha256.New, []byte(delivery.Secret))
+  mac.Write([]byte(timestamp + "."))
+  mac.Write(delivery.Payload)
+
+  request, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+  if nil == err {
+    request.Header.Set("Content-Type", "application/json")
+    request.Header.Set("User-Agent", "fontseca.dev-webhooks")
+    request.Header.Set("X-Webhook-Event", delivery.Event)
+    request.Header.Set("X-Webhook-Delivery", delivery.UUID.String())
+    request.Header.Set("X-Webhook-Timestamp", timestamp)
+    request.Header.Set("X-Webhook-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+
+    var response *http.Response
+    response, err = s.client.Do(request)
+    if nil == err {
+      _, _ = io.Copy(io.Discard, io.LimitReader(response.Body, 1<<16))
+      _ = response.Body.Close()
+
+      attempt.StatusCode = response.StatusCode
+      if 200 <= response.StatusCode && 300 > response.StatusCode {
+        attempt.Delivered = true
+        return attempt
+      }
+
+      err = errors.New("unexpected response status: " + response.Status)
+    }
+  }
+
+  attempt.Error = err.Error()
+
+  if attempts := delivery.Attempts + 1; maxDeliveryAttempts > attempts {
+    attempt.RetryIn = min(firstRetryIn<<(attempts-1), maxRetryIn)
+  }
+
+  return attempt
+}
+
+func (s *webhooksService) Run(ctx context.Context, interval time.Duration) {
+  var ticker = time.NewTicker(interval)
+  defer ticker.Stop()
+
+  for {
+    select {
+    case <-ctx.Done():
+      return
+    case <-ticker.C:
+      if _, err := s.Deliver(ctx); nil != err && nil == ctx.Err() {
+        slog.Error("could not deliver webhooks: " + err.Error())
+      }
+    }
+  }
+}
@@ -0,0 +1,216 @@
+package service
+
+import (
+  "context"
+  "crypto/hmac"
+  "crypto/sha256"
+  "encoding/hex"
+  "encoding/json"
+  "errors"
+  "fontseca.dev/mocks"
+  "fontseca.dev/model"
+  "fontseca.dev/transfer"
+  "github.com/google/uuid"
+  "github.com/stretchr/testify/assert"
+  "github.com/stretchr/testify/mock"
+  "io"
+  "net/http"
+  "net/http/httptest"
+  "testing"
+  "time"
+)
+
+func TestWebhooksService_Add(t *testing.T) {
+  const routine = "Add"
+
+  ctx := context.TODO()
+  id := uuid.NewString()
+
+  t.Run("success", func(t *testing.T) {
+    creation := &transfer.WebhookCreation{
+      URL:    " https://example.com/hooks ",
+      Events: " Article.Published , patch.released,, article.published ",
+      Secret: "0123456789abcdef",
+    }
+
+    r := mocks.NewWebhooksRepository()
+    r.On(routine, ctx, &transfer.WebhookCreation{
+      URL:    "https://example.com/hooks",
+      Events: "article.published,patch.released",
+      Secret: "0123456789abcdef",
+    }).Return(id, nil)
+
+    insertedID, secret, err := NewWebhooksService(r).Add(ctx, creation)
+    assert.NoError(t, err)
+    assert.Equal(t, id, insertedID)
+    assert.Equal(t, "0123456789abcdef", secret)
+  })
+
+  t.Run("subscribes to every event", func(t *testing.T) {
+    creation := &transfer.WebhookCreation{URL: "https://example.com/hooks", Events: "article.hidden,*"}
+
+    r := mocks.NewWebhooksRepository()
+    r.On(routine, ctx, mock.Anything).Return(id, nil)
+
+    _, secret, err := NewWebhooksService(r).Add(ctx, creation)
+    assert.NoError(t, err)
+    assert.Equal(t, "*", creation.Events)
+    assert.Len(t, secret, 64)
+    assert.Equal(t, creation.Secret, secret)
+  })
+
+  t.Run("unknown event", func(t *testing.T) {
+    creation := &transfer.WebhookCreation{URL: "https://example.com/hooks", Events: "article.published,article.liked"}
+
+    r := mocks.NewWebhooksRepository()
+    r.AssertNotCalled(t, routine)
+
+    _, _, err := NewWebhooksService(r).Add(ctx, creation)
+    assert.ErrorContains(t, err, "validation criteria")
+  })
+
+  t.Run("no events", func(t *testing.T) {
+    creation := &transfer.WebhookCreation{URL: "https://example.com/hooks", Events: " , "}
+
+    r := mocks.NewWebhooksRepository()
+    r.AssertNotCalled(t, routine)
+
+    _, _, err := NewWebhooksService(r).Add(ctx, creation)
+    assert.Error(t, err)
+  })
+
+  t.Run("gets a repository failure", func(t *testing.T) {
+    unexpected := errors.New("unexpected error")
+    creation := &transfer.WebhookCreation{URL: "https://example.com/hooks", Events: "*"}
+
+    r := mocks.NewWebhooksRepository()
+    r.On(routine, ctx, mock.Anything).Return("", unexpected)
+
+    _, _, err := NewWebhooksService(r).Add(ctx, creation)
+    assert.ErrorIs(t, err, unexpected)
+  })
+}
+
+func TestWebhooksService_Publish(t *testing.T) {
+  const routine = "Enqueue"
+
+  ctx := context.TODO()
+  data := map[string]string{"article_uuid": uuid.NewString()}
+
+  r := mocks.NewWebhooksRepository()
+  r.On(routine, mock.Anything, ArticlePublished, mock.MatchedBy(func(payload []byte) bool {
+    var event struct {
+      ID    string            `json:"id"`
+      Event string            `json:"event"`
+      Data  map[string]string `json:"data"`
+    }
+    return nil == json.Unmarshal(payload, &event) &&
+      nil == uuid.Validate(event.ID) &&
+      ArticlePublished == event.Event &&
+      data["article_uuid"] == event.Data["article_uuid"]
+  })).Return(nil)
+
+  assert.NoError(t, NewWebhooksService(r).Publish(ctx, ArticlePublished, data))
+
+  r.AssertExpectations(t)
+}
+
+func TestWebhooksService_Deliver(t *testing.T) {
+  const routine = "Record"
+
+  ctx := context.TODO()
+  payload := []byte(`{"event":"patch.released"}`)
+
+  newDelivery := func(url string, attempts int) *model.WebhookDelivery {
+    return &model.WebhookDelivery{
+      UUID:     uuid.New(),
+      Event:    PatchReleased,
+      Payload:  payload,
+      Attempts: attempts,
+      URL:      url,
+      Secret:   "0123456789abcdef",
+    }
+  }
+
+  t.Run("success", func(t *testing.T) {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+      body, _ := io.ReadAll(r.Body)
+
+      mac := hmac.New(sha256.New, []byte("0123456789abcdef"))
+      mac.Write([]byte(r.Header.Get("X-Webhook-Timestamp") + "."))
+      mac.Write(body)
+
+      assert.Equal(t, "sha256="+hex.EncodeToString(mac.Sum(nil)), r.Header.Get("X-Webhook-Signature"))
+      assert.Equal(t, PatchReleased, r.Header.Get("X-Webhook-Event"))
+      assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+      assert.Equal(t, payload, body)
+
+      w.WriteHeader(http.StatusNoContent)
+    }))
+    defer server.Close()
+
+    delivery := newDelivery(server.URL, 0)
+
+    r := mocks.NewWebhooksRepository()
+    r.On("Due", ctx, dueDeliveries).Return([]*model.WebhookDelivery{delivery}, nil)
+    r.On(routine, ctx, &transfer.WebhookAttempt{
+      DeliveryUUID: delivery.UUID.String(),
+      StatusCode:   http.StatusNoContent,
+      Delivered:    true,
+    }).Return(nil)
+
+    delivered, err := NewWebhooksService(r).Deliver(ctx)
+    assert.NoError(t, err)
+    assert.Equal(t, 1, delivered)
+    r.AssertExpectations(t)
+  })
+
+  t.Run("retries with backoff", func(t *testing.T) {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+      w.WriteHeader(http.StatusInternalServerError)
+    }))
+    defer server.Close()
+
+    delivery := newDelivery(server.URL, 2)
+
+    r := mocks.NewWebhooksRepository()
+    r.On("Due", ctx, dueDeliveries).Return([]*model.WebhookDelivery{delivery}, nil)
+    r.On(routine, ctx, &transfer.WebhookAttempt{
+      DeliveryUUID: delivery.UUID.String(),
+      StatusCode:   http.StatusInternalServerError,
+      Error:        "unexpected response status: 500 Internal Server Error",
+      RetryIn:      2 * time.Minute,
+    }).Return(nil)
+
+    delivered, err := NewWebhooksService(r).Deliver(ctx)
+    assert.NoError(t, err)
+    assert.Zero(t, delivered)
+    r.AssertExpectations(t)
+  })
+
+  t.Run("gives up after the last attempt", func(t *testing.T) {
+    delivery := newDelivery("http://127.0.0.1:0", maxDeliveryAttempts-1)
+
+    r := mocks.NewWebhooksRepository()
+    r.On("Due", ctx, dueDeliveries).Return([]*model.WebhookDelivery{delivery}, nil)
+    r.On(routine, ctx, mock.MatchedBy(func(attempt *transfer.WebhookAttempt) bool {
+      return !attempt.Delivered && "" != attempt.Error && 0 == attempt.RetryIn
+    })).Return(nil)
+
+    delivered, err := NewWebhooksService(r).Deliver(ctx)
+    assert.NoError(t, err)
+    assert.Zero(t, delivered)
+    r.AssertExpectations(t)
+  })
+
+  t.Run("gets a repository failure", func(t *testing.T) {
+    unexpected := errors.New("unexpected error")
+
+    r := mocks.NewWebhooksRepository()
+    r.On("Due", ctx, dueDeliveries).Return(nil, unexpected)
+    r.AssertNotCalled(t, routine)
+
+    _, err := NewWebhooksService(r).Deliver(ctx)
+    assert.ErrorIs(t, err, unexpected)
+  })
+}
@@ -0,0 +1,37 @@
+package tracing
+
+import (
+  "context"
+  "go.opentelemetry.io/otel/trace"
+  "log/slog"
+)
+
+// logHandler adds the IDs of the trace and the span in the context of
+// a record to its attributes.
+type logHandler struct {
+  slog.Handler
+}
+
+// NewLogHandler wraps h so that the records logged with a context that
+// carries a span get its 'trace_id' and 'span_id'.
+func NewLogHandler(h slog.Handler) slog.Handler {
+  return &logHandler{Handler: h}
+}
+
+func (h *logHandler) Handle(ctx context.Context, record slog.Record) error {
+  if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+    record.AddAttrs(
+      slog.String("trace_id", span.TraceID().String()),
+      slog.String("span_id", span.SpanID().String()))
+  }
+
+  return h.Handler.Handle(ctx, record)
+}
+
+func (h *logHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+  return &logHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *logHandler) WithGroup(name string) slog.Handler {
+  return &logHandler{Handler: h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,185 @@
+package tracing
+
+import (
+  "context"
+  "database/sql/driver"
+  "errors"
+  "go.opentelemetry.io/otel/attribute"
+  semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+  "go.opentelemetry.io/otel/trace"
+  "strings"
+)
+
+// WrapDriver wraps the SQL driver d so that every query and every
+// statement executed through its connections gets a span of its own,
+// named after the statement, like 'SELECT article'. system names the
+// database management system, as in the 'db.system' attribute.
+//
+// Only queries and statements run directly on a connection or on a
+// transaction are traced; prepared statements are not.
+func WrapDriver(d driver.Driver, system string) driver.Driver {
+  return &tracedDriver{driver: d, system: system}
+}
+
+type tracedDriver struct {
+  driver driver.Driver
+  system string
+}
+
+func (d *tracedDriver) Open(name string) (driver.Conn, error) {
+  conn, err := d.driver.Open(name)
+  if nil != err {
+    return nil, err
+  }
+
+  return &tracedConn{Conn: conn, system: d.system}, nil
+}
+
+// tracedConn traces the queries and statements run on a connection.
+type tracedConn struct {
+  driver.Conn
+  system string
+}
+
+// Unwrap returns the connection of the wrapped driver, for the uses of
+// the connection that are specific to it.
+func (c *tracedConn) Unwrap() driver.Conn {
+  return c.Conn
+}
+
+func (c *tracedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+  if b, ok := c.Conn.(driver.ConnBeginTx); ok {
+    return b.BeginTx(ctx, opts)
+  }
+
+  return c.Conn.Begin()
+}
+
+func (c *tracedConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+  if p, ok := c.Conn.(driver.ConnPrepareContext); ok {
+    return p.PrepareContext(ctx, query)
+  }
+
+  return c.Conn.Prepare(query)
+}
+
+func (c *tracedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+  e, ok := c.Conn.(driver.ExecerContext)
+  if !ok {
+    return nil, driver.ErrSkip
+  }
+
+  ctx, span := c.start(ctx, query)
+  defer span.End()
+
+  result, err := e.ExecContext(ctx, query, args)
+  c.end(span, err)
+
+  return result, err
+}
+
+func (c *tracedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+  q, ok := c.Conn.(driver.QueryerContext)
+  if !ok {
+    return nil, driver.ErrSkip
+  }
+
+  ctx, span := c.start(ctx, query)
+  defer span.End()
+
+  rows, err := q.QueryContext(ctx, query, args)
+  c.end(span, err)
+
+  return rows, err
+}
+
+func (c *tracedConn) Ping(ctx context.Context) error {
+  if p, ok := c.Conn.(driver.Pinger); ok {
+    return p.Ping(ctx)
+  }
+
+  return nil
+}
+
+func (c *tracedConn) ResetSession(ctx context.Context) error {
+  if r, ok := c.Conn.(driver.SessionResetter); ok {
+    return r.ResetSession(ctx)
+  }
+
+  return nil
+}
+
+func (c *tracedConn) IsValid() bool {
+  if v, ok := c.Conn.(driver.Validator); ok {
+    return v.IsValid()
+  }
+
+  return true
+}
+
+func (c *tracedConn) CheckNamedValue(value *driver.NamedValue) error {
+  if n, ok := c.Conn.(driver.NamedValueChecker); ok {
+    return n.CheckNamedValue(value)
+  }
+
+  return driver.ErrSkip
+}
+
+func (c *tracedConn) start(ctx context.Context, query string) (context.Context, trace.Span) {
+  var operation, table = statementName(query)
+
+  var spanName = operation
+  if "" != table {
+    spanName += " " + table
+  }
+
+  return Tracer().Start(ctx, spanName,
+    trace.WithSpanKind(trace.SpanKindClient),
+    trace.WithAttributes(
+      attribute.String(string(semconv.DBSystemKey), c.system),
+      semconv.DBOperationName(operation),
+      semconv.DBCollectionName(table),
+      semconv.DBQueryText(strings.TrimSpace(query))))
+}
+
+func (c *tracedConn) end(span trace.Span, err error) {
+  if !errors.Is(err, driver.ErrSkip) {
+    Fail(span, err)
+  }
+}
+
+// statementName finds the operation of the SQL statement query and the
+// table it operates on, if it is a SELECT, INSERT, UPDATE or DELETE one.
+func statementName(query string) (operation, table string) {
+  var words = strings.Fields(query)
+  if 0 == len(words) {
+    return "", ""
+  }
+
+  operation = strings.ToUpper(words[0])
+
+  var after string
+  switch operation {
+  case "SELECT", "DELETE":
+    after = "FROM"
+  case "INSERT", "REPLACE":
+    after = "INTO"
+  case "UPDATE":
+    if 1 < len(words) {
+      table = words[1]
+    }
+  }
+
+  if "" != after {
+    for i, word := range words[:len(words)-1] {
+      if strings.EqualFold(after, word) {
+        table = words[i+1]
+        break
+      }
+    }
+  }
+
+  table = strings.Trim(table, "\"`[];(),")
+
+  return operation, table
+}
@@ -0,0 +1,149 @@
+// Package tracing traces the requests served by the site, through its
+// handlers, services and repositories down to the database, with
+// OpenTelemetry.
+package tracing
+
+import (
+  "context"
+  "fmt"
+  "go.opentelemetry.io/otel"
+  "go.opentelemetry.io/otel/codes"
+  "go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+  "go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+  "go.opentelemetry.io/otel/propagation"
+  "go.opentelemetry.io/otel/sdk/resource"
+  sdktrace "go.opentelemetry.io/otel/sdk/trace"
+  semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+  "go.opentelemetry.io/otel/trace"
+  "os"
+  "runtime"
+  "runtime/debug"
+  "strings"
+  "sync"
+)
+
+// name identifies the site as the instrumentation scope of its spans
+// and as the service that emits them.
+const name = "fontseca.dev"
+
+// Exporters that Setup understands.
+const (
+  ExporterNone   = "none"   // spans are not recorded at all
+  ExporterStdout = "stdout" // spans are written to the standard output
+  ExporterOTLP   = "otlp"   // spans are sent to an OTLP collector over HTTP
+)
+
+// Setup installs the propagator of W3C trace contexts and the tracer
+// provider of the site, which sends the spans to exporter; with
+// ExporterNone, spans are not recorded. The OTLP exporter is set up
+// with the standard OTEL_EXPORTER_OTLP_* environment variables and
+// sends to http://localhost:4318 by default.
+//
+// The returned function flushes the pending spans and must be called
+// before the program exits.
+func Setup(ctx context.Context, exporter string) (shutdown func(context.Context) error, err error) {
+  otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+  var spans sdktrace.SpanExporter
+
+  switch strings.ToLower(strings.TrimSpace(exporter)) {
+  case "", ExporterNone:
+    return func(context.Context) error { return nil }, nil
+  case ExporterStdout, "console":
+    spans, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+  case ExporterOTLP:
+    spans, err = otlptracehttp.New(ctx)
+  default:
+    return nil, fmt.Errorf("unknown traces exporter %q; use %q, %q or %q", exporter, ExporterNone, ExporterStdout, ExporterOTLP)
+  }
+
+  if nil != err {
+    return nil, err
+  }
+
+  var version = "(devel)"
+  if info, ok := debug.ReadBuildInfo(); ok && "" != info.Main.Version {
+    version = info.Main.Version
+  }
+
+  res, err := resource.New(ctx,
+    resource.WithFromEnv(),
+    resource.WithTelemetrySDK(),
+    resource.WithAttributes(semconv.ServiceName(name), semconv.ServiceVersion(version)))
+
+  if nil != err {
+    return nil, err
+  }
+
+  var provider = sdktrace.NewTracerProvider(
+    sdktrace.WithBatcher(spans),
+    sdktrace.WithResource(res))
+
+  otel.SetTracerProvider(provider)
+
+  return provider.Shutdown, nil
+}
+
+// Tracer returns the tracer of the site.
+func Tracer() trace.Tracer {
+  return otel.Tracer(name)
+}
+
+// names caches the span names of the callers of Start, by program counter.
+var names sync.Map
+
+// Start starts a span as a child of the one in ctx, named after the
+// method that calls it, so (*draftsService).Revise starts the span
+// 'draftsService.Revise'. It is meant to be called at the beginning of
+// the method, replacing its context:
+//
+//	ctx, span := tracing.Start(ctx)
+//	defer span.End()
+func Start(ctx context.Context, options ...trace.SpanStartOption) (context.Context, trace.Span) {
+  var pc, _, _, _ = runtime.Caller(1)
+
+  var spanName, cached = names.Load(pc)
+  if !cached {
+    spanName, _ = names.LoadOrStore(pc, callerName(pc))
+  }
+
+  child, span := Tracer().Start(ctx, spanName.(string), options...)
+
+  // With tracing disabled, the span is the one already in ctx, so
+  // ctx is kept for callees to get the very context they were given.
+  if !span.IsRecording() && span.SpanContext().Equal(trace.SpanContextFromContext(ctx)) {
+    return ctx, span
+  }
+
+  return child, span
+}
+
+// callerName names the function at pc after its fully qualified name,
+// such as 'fontseca.dev/service.(*draftsService).Revise', without the
+// package path nor the pointer notation of its receiver.
+func callerName(pc uintptr) string {
+  var fn = runtime.FuncForPC(pc)
+  if nil == fn {
+    return "unknown"
+  }
+
+  var qualified = fn.Name()
+  qualified = qualified[1+strings.LastIndex(qualified, "/"):]
+
+  var parts = strings.Split(qualified, ".")
+  if 3 > len(parts) {
+    return parts[len(parts)-1]
+  }
+
+  return strings.Trim(parts[1], "(*)") + "." + parts[2]
+}
+
+// Fail marks span as failed because of err, if any.
+func Fail(span trace.Span, err error) {
+  if nil == err {
+    return
+  }
+
+  span.RecordError(err)
+  span.SetStatus(codes.Error, err.Error())
+}
@@ -0,0 +1,111 @@
+package tracing
+
+import (
+  "bytes"
+  "context"
+  "database/sql"
+  "github.com/mattn/go-sqlite3"
+  "github.com/stretchr/testify/assert"
+  "github.com/stretchr/testify/require"
+  "go.opentelemetry.io/otel"
+  sdktrace "go.opentelemetry.io/otel/sdk/trace"
+  "go.opentelemetry.io/otel/sdk/trace/tracetest"
+  "log/slog"
+  "testing"
+)
+
+// record makes the spans of the test be recorded by the returned recorder.
+func record(t *testing.T) *tracetest.SpanRecorder {
+  var recorder = tracetest.NewSpanRecorder()
+  var previous = otel.GetTracerProvider()
+  otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+  t.Cleanup(func() { otel.SetTracerProvider(previous) })
+  return recorder
+}
+
+type draftsService struct{}
+
+func (s *draftsService) Revise(ctx context.Context) context.Context {
+  ctx, span := Start(ctx)
+  defer span.End()
+  return ctx
+}
+
+func TestStart(t *testing.T) {
+  t.Run("disabled", func(t *testing.T) {
+    var ctx = context.Background()
+    assert.Equal(t, ctx, new(draftsService).Revise(ctx))
+  })
+
+  t.Run("enabled", func(t *testing.T) {
+    var recorder = record(t)
+    new(draftsService).Revise(context.Background())
+    require.Len(t, recorder.Ended(), 1)
+    assert.Equal(t, "draftsService.Revise", recorder.Ended()[0].Name())
+  })
+}
+
+func TestStatementName(t *testing.T) {
+  var tests = []struct {
+    query, operation, table string
+  }{
+    {`SELECT count (*) FROM "article_link" WHERE "expires_at" <= current_timestamp;`, "SELECT", "article_link"},
+    {"\n  insert into \"tag\" (\"id\", \"name\") values ($1, $2);", "INSERT", "tag"},
+    {`UPDATE "article" SET "views" = "views" + @views;`, "UPDATE", "article"},
+    {`DELETE FROM "webhook" WHERE "uuid" = @uuid;`, "DELETE", "webhook"},
+    {`PRAGMA optimize;`, "PRAGMA", ""},
+    {"", "", ""},
+  }
+
+  for _, test := range tests {
+    var operation, table = statementName(test.query)
+    assert.Equal(t, test.operation, operation, test.query)
+    assert.Equal(t, test.table, table, test.query)
+  }
+}
+
+func TestWrapDriver(t *testing.T) {
+  sql.Register("sqlite3_traced_test", WrapDriver(&sqlite3.SQLiteDriver{}, "sqlite"))
+  var db, err = sql.Open("sqlite3_traced_test", ":memory:")
+  require.NoError(t, err)
+  defer db.Close()
+
+  var recorder = record(t)
+
+  _, err = db.Exec(`CREATE TABLE "tag" ("id" TEXT NOT NULL PRIMARY KEY);`)
+  require.NoError(t, err)
+  _, err = db.Exec(`INSERT INTO "tag" ("id") VALUES (@id);`, sql.Named("id", "go"))
+  require.NoError(t, err)
+
+  var id string
+  require.NoError(t, db.QueryRow(`SELECT "id" FROM "tag";`).Scan(&id))
+  assert.Equal(t, "go", id)
+
+  _, err = db.Exec(`SELECT * FROM "missing";`)
+  require.Error(t, err)
+
+  var spans = recorder.Ended()
+  require.Len(t, spans, 4)
+  assert.Equal(t, "CREATE", spans[0].Name())
+  assert.Equal(t, "INSERT tag", spans[1].Name())
+  assert.Equal(t, "SELECT tag", spans[2].Name())
+  assert.Equal(t, "SELECT missing", spans[3].Name())
+  assert.NotEmpty(t, spans[3].Events(), "the error must be recorded")
+}
+
+func TestNewLogHandler(t *testing.T) {
+  var recorder = record(t)
+  var output bytes.Buffer
+  var logger = slog.New(NewLogHandler(slog.NewTextHandler(&output, nil)))
+
+  logger.Info("untraced")
+  assert.NotContains(t, output.String(), "trace_id")
+
+  ctx, span := Tracer().Start(context.Background(), "traced")
+  logger.InfoContext(ctx, "traced")
+  span.End()
+
+  require.Len(t, recorder.Ended(), 1)
+  assert.Contains(t, output.String(), "trace_id="+span.SpanContext().TraceID().String())
+  assert.Contains(t, output.String(), "span_id="+span.SpanContext().SpanID().String())
+}
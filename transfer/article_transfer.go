@@ -1,7 +1,9 @@
 package transfer
 
 import (
+  "fontseca.dev/diff"
   "github.com/google/uuid"
+  "strconv"
   "time"
 )
 
@@ -20,12 +22,14 @@ type ArticleRevision struct {
   Slug     string
   ReadTime int
   Content  string `json:"content"`
+  Version  int    `json:"version" binding:"required,min=1"` // the version being revised
 }
 
 // Article is a shallow article entry for transferring metadata.
 type Article struct {
   UUID  uuid.UUID `json:"uuid"`
   Title string    `json:"title"`
+  Slug  string    `json:"slug"`
   Topic *struct {
     ID  string `json:"id"`
     URL string `json:"url"` // in the form: 'https://fontseca.dev/archive/:topic'
@@ -33,6 +37,20 @@ type Article struct {
   URL         string     `json:"url"` // in the form: 'https://fontseca.dev/archive/:topic/:year/:month/:slug'
   IsPinned    bool       `json:"is_pinned"`
   PublishedAt *time.Time `json:"published_at"`
+  UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// Cursor returns the cursor that points at the article in a list of
+// articles, which are sorted by whether they are pinned and by their
+// publication date, drafts last.
+func (a *Article) Cursor() *Cursor {
+  var published = "-infinity"
+
+  if nil != a.PublishedAt {
+    published = CursorTime(*a.PublishedAt)
+  }
+
+  return &Cursor{Key: []string{strconv.FormatBool(a.IsPinned), published}, ID: a.UUID.String()}
 }
 
 // Publication represents the publication date of an article,
@@ -48,7 +66,16 @@ type ArticleFilter struct {
   Topic       string
   Publication *Publication
   Page        int
-  RPP         int // records per page
+  RPP         int     // records per page
+  After       *Cursor // cursor of the last article of the previous page; when set, Page is ignored
+  Before      *Cursor // cursor of the first article of the next page; when set, Page is ignored
+}
+
+// ArchivePage represents a page of the web archive and the URLs of
+// its neighbouring pages, if any.
+type ArchivePage struct {
+  Previous string
+  Next     string
 }
 
 // ArticleRequest represents the parameters used to query one
@@ -58,3 +85,63 @@ type ArticleRequest struct {
   Publication *Publication
   Slug        string
 }
+
+// FieldChange represents a change made to a single field of an article.
+type FieldChange struct {
+  Field string `json:"field"`
+  Old   string `json:"old"`
+  New   string `json:"new"`
+}
+
+// ArticlePatchDiff represents the changes that releasing an article
+// patch would make to the article it points to.
+type ArticlePatchDiff struct {
+  PatchUUID    uuid.UUID        `json:"patch_uuid"`
+  ArticleUUID  uuid.UUID        `json:"article_uuid"`
+  BaseRevision int              `json:"base_revision"` // revision the patch was branched from
+  Revision     int              `json:"revision"`      // current revision of the article
+  Fields       []*FieldChange   `json:"fields"`
+  Content      []diff.Line      `json:"content"`
+  Conflicts    []*PatchConflict `json:"conflicts"`
+}
+
+// PatchConflict represents a part of an article that both a patch and
+// the revisions released after the patch was branched changed. Base,
+// Patch and Article hold the value of a field or, for the content, the
+// lines of the region that starts at Line.
+type PatchConflict struct {
+  Field   string `json:"field"`
+  Line    int    `json:"line,omitempty"`
+  Base    any    `json:"base"`
+  Patch   any    `json:"patch"`
+  Article any    `json:"article"`
+}
+
+// ArticleImport represents the data required to import an article
+// written outside the archive, keeping its original publication date.
+type ArticleImport struct {
+  Title       string
+  Slug        string
+  Topic       *TopicCreation // nil when the article does not belong to a topic
+  Tags        []*TagCreation
+  ReadTime    int
+  Content     string
+  PublishedAt *time.Time // nil when the article is imported as a draft
+  IsHidden    bool
+  IsPinned    bool
+}
+
+// Possible outcomes of importing an article.
+const (
+  ImportCreated   = "created"
+  ImportUpdated   = "updated"
+  ImportUnchanged = "unchanged"
+)
+
+// ArticleImportResult represents the outcome of importing one article.
+type ArticleImportResult struct {
+  File   string    `json:"file"`
+  UUID   uuid.UUID `json:"uuid"`
+  Slug   string    `json:"slug"`
+  Status string    `json:"status"` // one of ImportCreated, ImportUpdated or ImportUnchanged
+}
@@ -0,0 +1,11 @@
+package transfer
+
+import "time"
+
+// Backup describes a copy of the database kept in the directory of the
+// backups.
+type Backup struct {
+  Name      string    `json:"name"`
+  Size      int64     `json:"size"`
+  CreatedAt time.Time `json:"created_at"`
+}
@@ -20,4 +20,5 @@ type ExperienceUpdate struct {
   Summary  string `json:"summary"`
   Active   bool   `json:"-"`
   Hidden   bool   `json:"-"`
+  Version  int    `json:"version" binding:"required,min=1"` // the version being updated
 }
@@ -0,0 +1,17 @@
+package transfer
+
+// Build describes the binary that serves the site.
+type Build struct {
+  Module    string `json:"module"`
+  Version   string `json:"version"`
+  Revision  string `json:"revision,omitempty"`
+  Modified  bool   `json:"modified"`
+  BuildTime string `json:"build_time,omitempty"`
+  GoVersion string `json:"go_version"`
+}
+
+// Health reports the checks that tell whether the server is ready.
+type Health struct {
+  Status string            `json:"status"`
+  Checks map[string]string `json:"checks,omitempty"`
+}
@@ -15,4 +15,5 @@ type MeUpdate struct {
   YouTubeURL   string `json:"youtube_url" binding:"max=2048"`
   TwitterURL   string `json:"twitter_url" binding:"max=2048"`
   InstagramURL string `json:"instagram_url" binding:"max=2048"`
+  Version      int    `json:"version" binding:"required,min=1"` // the version being updated
 }
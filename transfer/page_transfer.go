@@ -0,0 +1,69 @@
+package transfer
+
+import (
+  "encoding/base64"
+  "encoding/json"
+  "errors"
+  "time"
+)
+
+// Page is the envelope of every list: a page of items, the total number
+// of items in the list and the opaque cursor that retrieves the page
+// that follows, if any.
+type Page[T any] struct {
+  Items      []T    `json:"items"`
+  NextCursor string `json:"next_cursor,omitempty"`
+  Total      int    `json:"total"`
+}
+
+// PageRequest asks for the page of Limit items of a list that follows
+// the item After points at, or for the first page if After is nil.
+type PageRequest struct {
+  After *Cursor
+  Limit int
+}
+
+// Cursor points at an item of a list. It holds the values the list is
+// sorted by and the ID of the item, which breaks the ties between them,
+// rather than the position of the item, so it keeps pointing at the
+// same place of the list after the item is removed.
+type Cursor struct {
+  Key []string `json:"k"`
+  ID  string   `json:"i"`
+}
+
+// CursorTimeLayout is the layout of the timestamps of cursors. It is
+// the one in which the database writes them: SQLite writes no fraction
+// of a second, so they are compared as the text it stores, and
+// PostgreSQL writes microseconds.
+const CursorTimeLayout = "2006-01-02 15:04:05.999999"
+
+// CursorTime formats t as a value of a cursor.
+func CursorTime(t time.Time) string {
+  return t.UTC().Format(CursorTimeLayout)
+}
+
+// String encodes the cursor as the opaque string clients send back.
+func (c *Cursor) String() string {
+  data, _ := json.Marshal(c)
+  return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// ParseCursor decodes a cursor encoded by Cursor.String.
+func ParseCursor(s string) (*Cursor, error) {
+  data, err := base64.RawURLEncoding.DecodeString(s)
+  if nil != err {
+    return nil, err
+  }
+
+  var cursor Cursor
+  if err = json.Unmarshal(data, &cursor); nil != err {
+    return nil, err
+  }
+
+  if "" == cursor.ID {
+    return nil, errors.New("cursor without an ID")
+  }
+
+  return &cursor, nil
+}
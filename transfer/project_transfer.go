@@ -33,4 +33,5 @@ type ProjectUpdate struct {
   PlaygroundURL  string
   Archived       bool
   Finished       bool
+  Version        int `json:"version" binding:"required,min=1"` // the version being updated
 }
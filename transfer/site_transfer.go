@@ -0,0 +1,17 @@
+package transfer
+
+// Record is a row of a table, keyed by column name.
+type Record map[string]any
+
+// SiteDump represents the whole content of the site, as stored in the
+// database.
+type SiteDump struct {
+  Me           Record   `json:"me"`
+  Experience   []Record `json:"experience"`
+  Projects     []Record `json:"projects"` // each one with the UUIDs of its "technology_tags"
+  Technologies []Record `json:"technologies"`
+  Topics       []Record `json:"topics"`
+  Tags         []Record `json:"tags"`
+  Articles     []Record `json:"articles"` // drafts included, each one with the IDs of its "tags"
+  Patches      []Record `json:"patches"`
+}
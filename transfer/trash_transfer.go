@@ -0,0 +1,12 @@
+package transfer
+
+import "time"
+
+// TrashedItem describes a piece of content that was removed and can be
+// restored until it is purged.
+type TrashedItem struct {
+  Type      string    `json:"type"` // one of: article, draft, patch, project, experience, tag, topic
+  ID        string    `json:"id"`
+  Title     string    `json:"title"`
+  DeletedAt time.Time `json:"deleted_at"`
+}
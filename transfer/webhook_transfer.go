@@ -0,0 +1,30 @@
+package transfer
+
+import (
+  "time"
+)
+
+// WebhookCreation represents the data required to register a webhook.
+type WebhookCreation struct {
+  URL    string `json:"url" binding:"required,http_url,max=2048"`
+  Events string `json:"events" binding:"required"`              // comma-separated names of events, or '*' for all of them
+  Secret string `json:"secret" binding:"omitempty,min=16,max=256"` // generated when empty
+}
+
+// WebhookEvent is the body of every delivery of a webhook.
+type WebhookEvent struct {
+  ID         string    `json:"id"` // the same for every delivery of the event
+  Event      string    `json:"event"`
+  OccurredAt time.Time `json:"occurred_at"`
+  Data       any       `json:"data"`
+}
+
+// WebhookAttempt represents the outcome of an attempt to deliver an
+// event to a webhook.
+type WebhookAttempt struct {
+  DeliveryUUID string
+  StatusCode   int           // zero when no response was received
+  Error        string        // empty when the event was delivered
+  Delivered    bool
+  RetryIn      time.Duration // zero when the delivery must not be retried
+}